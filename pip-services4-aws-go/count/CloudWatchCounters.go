@@ -2,6 +2,7 @@ package count
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -33,6 +34,8 @@ import (
 // - options:
 //   - interval:              interval in milliseconds to save current counters measurements (default: 5 mins)
 //   - reset_timeout:         timeout in milliseconds to reset the counters. 0 disables the reset (default: 0)
+//   - max_labels:            maximum number of labels per measurement (default: 10)
+//   - max_label_sets:        maximum number of distinct label sets per counter (default: 100)
 //
 // References ###
 //
@@ -188,12 +191,50 @@ func (c *CloudWatchCounters) Close(ctx context.Context) error {
 	return nil
 }
 
+// CloudWatch accepts up to 30 dimensions per metric
+const maxCounterDimensions = 30
+
+// GetCounterDimensions adds counter labels to the given dimensions sorted by their names.
+// Labels with empty names or values are skipped and the result is limited to 30 dimensions.
+//
+//	Parameters:
+//		- counter ccount.Counter a counter with labels.
+//		- dimensions []*cloudwatch.Dimension common dimensions of all counters.
+//	Returns: []*cloudwatch.Dimension dimensions of the counter metric.
+func (c *CloudWatchCounters) GetCounterDimensions(counter ccount.Counter, dimensions []*cloudwatch.Dimension) []*cloudwatch.Dimension {
+	if len(counter.Labels) == 0 {
+		return dimensions
+	}
+
+	keys := make([]string, 0, len(counter.Labels))
+	for key, value := range counter.Labels {
+		// CloudWatch rejects dimensions with empty names or values
+		if key != "" && value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	result := make([]*cloudwatch.Dimension, 0, len(dimensions)+len(keys))
+	result = append(result, dimensions...)
+	for _, key := range keys {
+		if len(result) >= maxCounterDimensions {
+			break
+		}
+		result = append(result, &cloudwatch.Dimension{
+			Name:  aws.String(key),
+			Value: aws.String(counter.Labels[key]),
+		})
+	}
+	return result
+}
+
 func (c *CloudWatchCounters) getCounterData(counter ccount.Counter, now time.Time, dimensions []*cloudwatch.Dimension) *cloudwatch.MetricDatum {
 
 	value := &cloudwatch.MetricDatum{
 		MetricName: aws.String(counter.Name),
 		Unit:       aws.String(None),
-		Dimensions: dimensions,
+		Dimensions: c.GetCounterDimensions(counter, dimensions),
 	}
 	tm := counter.Time
 	if tm.IsZero() {
@@ -5,12 +5,12 @@ go 1.20
 require (
 	github.com/aws/aws-lambda-go v1.22.0
 	github.com/aws/aws-sdk-go v1.37.16
-	github.com/pip-services4/pip-services4-go/pip-services4-commons-go v0.0.0-20231024100230-d6ca9798682c
-	github.com/pip-services4/pip-services4-go/pip-services4-components-go v0.0.0-20231024100230-d6ca9798682c
-	github.com/pip-services4/pip-services4-go/pip-services4-config-go v0.0.0-20231024100230-d6ca9798682c
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go v0.0.1-2
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go v0.0.1-2
+	github.com/pip-services4/pip-services4-go/pip-services4-config-go v0.0.0-20240325121312-3b0195749a25
 	github.com/pip-services4/pip-services4-go/pip-services4-container-go v0.0.0-20231024100230-d6ca9798682c
-	github.com/pip-services4/pip-services4-go/pip-services4-data-go v0.0.0-20231024100230-d6ca9798682c
-	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.0-20231024100230-d6ca9798682c
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go v0.0.1-2
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.1-3
 	github.com/pip-services4/pip-services4-go/pip-services4-rpc-go v0.0.0-20231024100230-d6ca9798682c
 	github.com/stretchr/testify v1.8.4
 )
@@ -19,9 +19,21 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/google/uuid v1.3.0 // indirect
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
-	github.com/pip-services4/pip-services4-go/pip-services4-expressions-go v0.0.0-20231024100230-d6ca9798682c // indirect
-	github.com/pip-services4/pip-services4-go/pip-services4-logic-go v0.0.0-20231024100230-d6ca9798682c // indirect
+	github.com/pip-services4/pip-services4-go/pip-services4-expressions-go v0.0.1-2 // indirect
+	github.com/pip-services4/pip-services4-go/pip-services4-logic-go v0.0.1-3 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go => ../pip-services4-commons-go
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go => ../pip-services4-components-go
+	github.com/pip-services4/pip-services4-go/pip-services4-config-go => ../pip-services4-config-go
+	github.com/pip-services4/pip-services4-go/pip-services4-container-go => ../pip-services4-container-go
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go => ../pip-services4-data-go
+	github.com/pip-services4/pip-services4-go/pip-services4-expressions-go => ../pip-services4-expressions-go
+	github.com/pip-services4/pip-services4-go/pip-services4-logic-go => ../pip-services4-logic-go
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go => ../pip-services4-observability-go
+	github.com/pip-services4/pip-services4-go/pip-services4-rpc-go => ../pip-services4-rpc-go
+)
@@ -0,0 +1,52 @@
+package test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	awscount "github.com/pip-services4/pip-services4-go/pip-services4-aws-go/count"
+	ccount "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/count"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudWatchCountersGetCounterDimensions(t *testing.T) {
+	counters := awscount.NewCloudWatchCounters()
+	common := []*cloudwatch.Dimension{
+		{Name: aws.String("InstanceID"), Value: aws.String("instance1")},
+	}
+
+	// Counters without labels keep the common dimensions
+	counter := ccount.Counter{Name: "test.counter", Type: ccount.Increment}
+	assert.Equal(t, common, counters.GetCounterDimensions(counter, common))
+
+	// Labels are added after the common dimensions sorted by their names
+	counter.Labels = map[string]string{"status": "200", "method": "GET"}
+	dimensions := counters.GetCounterDimensions(counter, common)
+	assert.Equal(t, []*cloudwatch.Dimension{
+		{Name: aws.String("InstanceID"), Value: aws.String("instance1")},
+		{Name: aws.String("method"), Value: aws.String("GET")},
+		{Name: aws.String("status"), Value: aws.String("200")},
+	}, dimensions)
+	assert.Len(t, common, 1)
+
+	// Labels with empty names or values are skipped
+	counter.Labels = map[string]string{"method": "", "": "value", "status": "200"}
+	dimensions = counters.GetCounterDimensions(counter, common)
+	assert.Equal(t, []*cloudwatch.Dimension{
+		{Name: aws.String("InstanceID"), Value: aws.String("instance1")},
+		{Name: aws.String("status"), Value: aws.String("200")},
+	}, dimensions)
+
+	// Dimensions are limited to 30 per metric
+	counter.Labels = make(map[string]string)
+	for i := 0; i < 40; i++ {
+		counter.Labels["label"+strconv.Itoa(i+10)] = "value"
+	}
+	dimensions = counters.GetCounterDimensions(counter, common)
+	assert.Len(t, dimensions, 30)
+	assert.Equal(t, "InstanceID", *dimensions[0].Name)
+	assert.Equal(t, "label10", *dimensions[1].Name)
+	assert.Equal(t, "label38", *dimensions[29].Name)
+}
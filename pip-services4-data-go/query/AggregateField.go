@@ -0,0 +1,57 @@
+package query
+
+// Aggregate functions supported by AggregateField
+const (
+	// AggregateCount counts items, or items with non-null values when a field is set
+	AggregateCount = "count"
+	// AggregateSum sums up numeric values of the field
+	AggregateSum = "sum"
+	// AggregateAvg calculates an average of numeric values of the field
+	AggregateAvg = "avg"
+	// AggregateMin finds the minimum value of the field
+	AggregateMin = "min"
+	// AggregateMax finds the maximum value of the field
+	AggregateMax = "max"
+)
+
+// AggregateField defines an aggregate function calculated over a group of data items.
+//
+//	see AggregateParams
+//
+//	Example:
+//		aggregate := NewAggregateParams([]string{"type"},
+//			NewCountAggregateField("count"),
+//			NewAggregateField("total", AggregateSum, "amount"),
+//		)
+type AggregateField struct {
+	// Name of the calculated value in result rows
+	Name string `json:"name"`
+	// Function is one of the aggregate functions, e.g. AggregateSum
+	Function string `json:"function"`
+	// Field is a name of the aggregated field, it is optional for AggregateCount
+	Field string `json:"field"`
+}
+
+// NewAggregateField creates a new instance and assigns its values.
+//
+//	Parameters:
+//		- name string the name of the calculated value.
+//		- function string the aggregate function.
+//		- field string the name of the aggregated field.
+//	Returns: AggregateField
+func NewAggregateField(name string, function string, field string) AggregateField {
+	return AggregateField{
+		Name:     name,
+		Function: function,
+		Field:    field,
+	}
+}
+
+// NewCountAggregateField creates a new instance that counts items in a group.
+//
+//	Parameters:
+//		- name string the name of the calculated value.
+//	Returns: AggregateField
+func NewCountAggregateField(name string) AggregateField {
+	return NewAggregateField(name, AggregateCount, "")
+}
@@ -0,0 +1,48 @@
+package query
+
+// AggregateParams defines aggregate functions and a list of fields to group data items by.
+// Without group fields the aggregates are calculated over all items that match the filter.
+//
+//	see AggregateField
+//
+//	Example:
+//		filter := NewFilterParamsFromTuples("status", "active")
+//		aggregate := NewAggregateParams([]string{"type"},
+//			NewCountAggregateField("count"),
+//			NewAggregateField("total", AggregateSum, "amount"),
+//		)
+//
+//		rows, err := myDataClient.GetAggregateByFilter(context.Background(), filter, *aggregate)
+//		for _, row := range rows {
+//			fmt.Println(row.GetGroupValue("type"), row.GetValueAsLong("count"), row.GetValueAsDouble("total"))
+//		}
+type AggregateParams struct {
+	GroupBy []string         `json:"group_by"`
+	Fields  []AggregateField `json:"fields"`
+}
+
+// NewEmptyAggregateParams creates a new empty instance.
+//
+//	Returns: *AggregateParams
+func NewEmptyAggregateParams() *AggregateParams {
+	return &AggregateParams{
+		GroupBy: make([]string, 0),
+		Fields:  make([]AggregateField, 0),
+	}
+}
+
+// NewAggregateParams creates a new instance and assigns its values.
+//
+//	Parameters:
+//		- groupBy []string names of the fields to group items by.
+//		- fields ...AggregateField aggregate functions to calculate.
+//	Returns: *AggregateParams
+func NewAggregateParams(groupBy []string, fields ...AggregateField) *AggregateParams {
+	c := &AggregateParams{
+		GroupBy: make([]string, len(groupBy)),
+		Fields:  make([]AggregateField, len(fields)),
+	}
+	copy(c.GroupBy, groupBy)
+	copy(c.Fields, fields)
+	return c
+}
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+)
+
+// AggregateRow is a result row of an aggregation query. It contains values of the
+// group fields and aggregate values calculated for the group.
+//
+//	see AggregateParams
+type AggregateRow struct {
+	// Group contains values of the group fields by field names
+	Group map[string]any `json:"group"`
+	// Values contains calculated aggregates by their names
+	Values map[string]any `json:"values"`
+}
+
+// NewAggregateRow creates a new instance and assigns its values.
+//
+//	Parameters:
+//		- group map[string]any values of the group fields.
+//		- values map[string]any calculated aggregates.
+//	Returns: AggregateRow
+func NewAggregateRow(group map[string]any, values map[string]any) AggregateRow {
+	if group == nil {
+		group = map[string]any{}
+	}
+	if values == nil {
+		values = map[string]any{}
+	}
+	return AggregateRow{
+		Group:  group,
+		Values: values,
+	}
+}
+
+// GetGroupValue gets a value of the group field.
+//
+//	Parameters:
+//		- field string the name of the group field.
+//	Returns: any value of the field or nil.
+func (c *AggregateRow) GetGroupValue(field string) any {
+	return c.Group[field]
+}
+
+// GetValue gets a calculated aggregate value.
+//
+//	Parameters:
+//		- name string the name of the aggregate.
+//	Returns: any aggregate value or nil.
+func (c *AggregateRow) GetValue(name string) any {
+	return c.Values[name]
+}
+
+// GetValueAsLong converts a calculated aggregate value into int64 or returns 0 when it is missing.
+//
+//	Parameters:
+//		- name string the name of the aggregate.
+//	Returns: int64
+func (c *AggregateRow) GetValueAsLong(name string) int64 {
+	return convert.LongConverter.ToLong(c.Values[name])
+}
+
+// GetValueAsDouble converts a calculated aggregate value into float64 or returns 0 when it is missing.
+//
+//	Parameters:
+//		- name string the name of the aggregate.
+//	Returns: float64
+func (c *AggregateRow) GetValueAsDouble(name string) float64 {
+	return convert.DoubleConverter.ToDouble(c.Values[name])
+}
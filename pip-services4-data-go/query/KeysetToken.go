@@ -0,0 +1,76 @@
+package query
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+)
+
+// EncodeKeysetToken encodes sort key values of the last item in a page into an opaque token
+// that can be passed in TokenizedPagingParams to resume the search after that item.
+//
+//	Parameters:
+//		- values []any values of the sort fields in the same order as the fields in SortParams.
+//	Returns: string an opaque token or EmptyTokenValue when there are no values.
+func EncodeKeysetToken(values []any) (string, error) {
+	if len(values) == 0 {
+		return EmptyTokenValue, nil
+	}
+
+	buf, err := json.Marshal(values)
+	if err != nil {
+		return EmptyTokenValue, err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// DecodeKeysetToken decodes sort key values from a token created by EncodeKeysetToken.
+// Integer numbers are restored as int64 (or uint64 when they exceed int64) without loss of precision,
+// other numbers as float64 and date-times as strings in RFC3339 format.
+//
+//	Parameters:
+//		- token string a token received from a previous page.
+//	Returns: []any decoded values, nil for empty token, or BadRequestError if token is invalid.
+func DecodeKeysetToken(token string) ([]any, error) {
+	if token == EmptyTokenValue {
+		return nil, nil
+	}
+
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.NewBadRequestError("", "INVALID_TOKEN", "Paging token is invalid").WithCause(err)
+	}
+
+	var values []any
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	if err = decoder.Decode(&values); err != nil {
+		return nil, errors.NewBadRequestError("", "INVALID_TOKEN", "Paging token is invalid").WithCause(err)
+	}
+	for i, value := range values {
+		if number, ok := value.(json.Number); ok {
+			values[i] = DecodeKeysetNumber(number)
+		}
+	}
+	return values, nil
+}
+
+// DecodeKeysetNumber converts a number decoded from JSON into int64, uint64 or float64
+// so integers larger than 2^53 keep their exact values.
+//
+//	Parameters:
+//		- number json.Number a number decoded with json.Decoder.UseNumber.
+//	Returns: any int64 or uint64 for integers and float64 for other numbers.
+func DecodeKeysetNumber(number json.Number) any {
+	if value, err := number.Int64(); err == nil {
+		return value
+	}
+	if value, err := strconv.ParseUint(number.String(), 10, 64); err == nil {
+		return value
+	}
+	value, _ := number.Float64()
+	return value
+}
@@ -0,0 +1,36 @@
+package test_query
+
+import (
+	"testing"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeysetToken(t *testing.T) {
+	token, err := query.EncodeKeysetToken([]any{"Key 1", 123, nil})
+	assert.Nil(t, err)
+	assert.NotEqual(t, query.EmptyTokenValue, token)
+
+	values, err := query.DecodeKeysetToken(token)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{"Key 1", int64(123), nil}, values)
+
+	// Large integers and fractions keep their values
+	token, err = query.EncodeKeysetToken([]any{int64(9007199254740993), uint64(18446744073709551615), 1.5})
+	assert.Nil(t, err)
+	values, err = query.DecodeKeysetToken(token)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{int64(9007199254740993), uint64(18446744073709551615), 1.5}, values)
+
+	token, err = query.EncodeKeysetToken(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, query.EmptyTokenValue, token)
+
+	values, err = query.DecodeKeysetToken(query.EmptyTokenValue)
+	assert.Nil(t, err)
+	assert.Nil(t, values)
+
+	_, err = query.DecodeKeysetToken("not a token")
+	assert.NotNil(t, err)
+}
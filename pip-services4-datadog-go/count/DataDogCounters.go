@@ -33,6 +33,10 @@ import (
 //   - retries:               number of retries (default: 3)
 //   - connect_timeout:       connection timeout in milliseconds (default: 10 sec)
 //   - timeout:               invocation timeout in milliseconds (default: 10 sec)
+//   - max_labels:            maximum number of labels per measurement (default: 10)
+//   - max_label_sets:        maximum number of distinct label sets per counter (default: 100)
+//
+// Counter labels are sent as metric tags.
 //
 // ### References ###
 //
@@ -145,6 +149,7 @@ func (c *DataDogCounters) convertCounter(counter ccount.Counter) []clients1.Data
 			Type:    clients1.Gauge,
 			Host:    c.instance,
 			Service: c.source,
+			Tags:    c.ConvertLabels(counter.Labels),
 			Points:  []clients1.DataDogMetricPoint{{Time: counter.Time, Value: (float64)(counter.Count)}},
 		}}
 
@@ -154,17 +159,18 @@ func (c *DataDogCounters) convertCounter(counter ccount.Counter) []clients1.Data
 			Type:    clients1.Gauge,
 			Host:    c.instance,
 			Service: c.source,
+			Tags:    c.ConvertLabels(counter.Labels),
 			Points:  []clients1.DataDogMetricPoint{{Time: counter.Time, Value: (float64)(counter.Last)}},
 		}}
 
-	case ccount.Interval:
-	case ccount.Statistics:
+	case ccount.Interval, ccount.Statistics:
 		return []clients1.DataDogMetric{
 			{
 				Metric:  counter.Name + ".min",
 				Type:    clients1.Gauge,
 				Host:    c.instance,
 				Service: c.source,
+				Tags:    c.ConvertLabels(counter.Labels),
 				Points:  []clients1.DataDogMetricPoint{{Time: counter.Time, Value: (float64)(counter.Min)}},
 			},
 			{
@@ -172,6 +178,7 @@ func (c *DataDogCounters) convertCounter(counter ccount.Counter) []clients1.Data
 				Type:    clients1.Gauge,
 				Host:    c.instance,
 				Service: c.source,
+				Tags:    c.ConvertLabels(counter.Labels),
 				Points:  []clients1.DataDogMetricPoint{{Time: counter.Time, Value: (float64)(counter.Average)}},
 			},
 			{
@@ -179,6 +186,7 @@ func (c *DataDogCounters) convertCounter(counter ccount.Counter) []clients1.Data
 				Type:    clients1.Gauge,
 				Host:    c.instance,
 				Service: c.source,
+				Tags:    c.ConvertLabels(counter.Labels),
 				Points:  []clients1.DataDogMetricPoint{{Time: counter.Time, Value: (float64)(counter.Max)}},
 			},
 		}
@@ -187,6 +195,24 @@ func (c *DataDogCounters) convertCounter(counter ccount.Counter) []clients1.Data
 	return nil
 }
 
+// ConvertLabels converts counter labels into DataDog metric tags.
+// Each metric gets its own copy of tags as the client adds service tag into them.
+//
+//	Parameters:
+//		- labels map[string]string counter labels.
+//	Returns: map[string]string metric tags or nil when there are no labels.
+func (c *DataDogCounters) ConvertLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	tags := make(map[string]string, len(labels))
+	for key, value := range labels {
+		tags[key] = value
+	}
+	return tags
+}
+
 func (c *DataDogCounters) convertCounters(counters []ccount.Counter) []clients1.DataDogMetric {
 	metrics := make([]clients1.DataDogMetric, 0)
 
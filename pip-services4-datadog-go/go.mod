@@ -3,19 +3,30 @@ module github.com/pip-services4/pip-services4-go/pip-services4-datadog-go
 go 1.20
 
 require (
-	github.com/pip-services4/pip-services4-go/pip-services4-commons-go v0.0.0-20230628201024-77520f2586d7
-	github.com/pip-services4/pip-services4-go/pip-services4-components-go v0.0.0-20230628201024-77520f2586d7
-	github.com/pip-services4/pip-services4-go/pip-services4-config-go v0.0.0-20230628201024-77520f2586d7
-	github.com/pip-services4/pip-services4-go/pip-services4-data-go v0.0.0-20230628201024-77520f2586d7
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go v0.0.1-2
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go v0.0.1-2
+	github.com/pip-services4/pip-services4-go/pip-services4-config-go v0.0.0-20240304141352-928143cb0946
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go v0.0.1-2
 	github.com/pip-services4/pip-services4-go/pip-services4-http-go v0.0.0-20230628201024-77520f2586d7
-	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.0-20230628201024-77520f2586d7
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.1-3
 	github.com/stretchr/testify v1.8.4
 	golang.org/x/net v0.11.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/pip-services4/pip-services4-go/pip-services4-rpc-go v0.0.0-20230621170919-605130ef8de8 // indirect
+	github.com/pip-services4/pip-services4-go/pip-services4-rpc-go v0.0.0-20240304141352-928143cb0946 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go => ../pip-services4-commons-go
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go => ../pip-services4-components-go
+	github.com/pip-services4/pip-services4-go/pip-services4-config-go => ../pip-services4-config-go
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go => ../pip-services4-data-go
+	github.com/pip-services4/pip-services4-go/pip-services4-expressions-go => ../pip-services4-expressions-go
+	github.com/pip-services4/pip-services4-go/pip-services4-http-go => ../pip-services4-http-go
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go => ../pip-services4-observability-go
+	github.com/pip-services4/pip-services4-go/pip-services4-rpc-go => ../pip-services4-rpc-go
+)
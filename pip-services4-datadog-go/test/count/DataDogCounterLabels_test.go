@@ -0,0 +1,26 @@
+package count_test
+
+import (
+	"testing"
+
+	ddcount "github.com/pip-services4/pip-services4-go/pip-services4-datadog-go/count"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataDogCountersConvertLabels(t *testing.T) {
+	counters := ddcount.NewDataDogCounters()
+
+	// Counters without labels have no tags
+	assert.Nil(t, counters.ConvertLabels(nil))
+	assert.Nil(t, counters.ConvertLabels(map[string]string{}))
+
+	// Labels are mapped into tags with the same names
+	labels := map[string]string{"method": "GET", "status": "200"}
+	tags := counters.ConvertLabels(labels)
+	assert.Equal(t, map[string]string{"method": "GET", "status": "200"}, tags)
+
+	// Tags are copied, so the client can add service tag without changing the labels
+	tags["service"] = "test"
+	assert.Len(t, labels, 2)
+	assert.Len(t, counters.ConvertLabels(labels), 2)
+}
@@ -17,6 +17,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 )
 
 // GrpcClient abstract client that calls commandable HTTP service.
@@ -242,6 +243,9 @@ func (c *GrpcClient) Open(ctx context.Context) error {
 
 	opts := []grpc.DialOption{
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{Timeout: c.Timeout}),
+		// Propagate W3C trace context and baggage
+		grpc.WithChainUnaryInterceptor(injectTraceContextUnaryInterceptor),
+		grpc.WithChainStreamInterceptor(injectTraceContextStreamInterceptor),
 	}
 
 	if len(c.interceptors) > 0 {
@@ -269,6 +273,28 @@ func (c *GrpcClient) Open(ctx context.Context) error {
 	return nil
 }
 
+// injectTraceContext puts W3C trace context and baggage from the context into outgoing metadata
+func injectTraceContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	ctrace.InjectTraceContext(ctx, ctrace.MultiValueTraceContextCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func injectTraceContextUnaryInterceptor(ctx context.Context, method string, req, reply any,
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(injectTraceContext(ctx), method, req, reply, cc, opts...)
+}
+
+func injectTraceContextStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc,
+	cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(injectTraceContext(ctx), desc, cc, method, opts...)
+}
+
 // Close method are closes component and frees used resources.
 //
 //		Parameters:
@@ -15,11 +15,13 @@ import (
 	cvalid "github.com/pip-services4/pip-services4-go/pip-services4-data-go/validate"
 	ccount "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/count"
 	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
 
 	grpcproto "github.com/pip-services4/pip-services4-go/pip-services4-grpc-go/protos"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 )
 
 // GrpcEndpoint used for creating GRPC endpoints. An endpoint is a URL, at which a given controller can be accessed by a client.
@@ -177,7 +179,11 @@ func (c *GrpcEndpoint) Open(ctx context.Context) (err error) {
 		return err
 	}
 	c.uri = connection.Host() + ":" + strconv.FormatInt(int64(connection.Port()), 10)
-	opts := []grpc.ServerOption{}
+	opts := []grpc.ServerOption{
+		// Extract W3C trace context and baggage
+		grpc.ChainUnaryInterceptor(extractTraceContextUnaryInterceptor),
+		grpc.ChainStreamInterceptor(extractTraceContextStreamInterceptor),
+	}
 	if len(c.interceptors) > 0 {
 		// Add interceptors
 		opts = append(opts, c.interceptors...)
@@ -221,6 +227,36 @@ func (c *GrpcEndpoint) Open(ctx context.Context) (err error) {
 	return nil
 }
 
+// extractTraceContext puts W3C trace context and baggage from incoming metadata into the context
+func extractTraceContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return ctrace.ExtractTraceContext(ctx, ctrace.MultiValueTraceContextCarrier(md))
+}
+
+func extractTraceContextUnaryInterceptor(ctx context.Context, req any,
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(extractTraceContext(ctx), req)
+}
+
+// traceContextServerStream overrides context of the server stream
+type traceContextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (c *traceContextServerStream) Context() context.Context {
+	return c.ctx
+}
+
+func extractTraceContextStreamInterceptor(srv any, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := extractTraceContext(stream.Context())
+	return handler(srv, &traceContextServerStream{ServerStream: stream, ctx: ctx})
+}
+
 // Close methods are closes c endpoint and the GRPC server (controller) that was opened earlier.
 //
 //	Parameters:
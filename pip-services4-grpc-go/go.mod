@@ -25,3 +25,13 @@ require (
 	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go => ../pip-services4-commons-go
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go => ../pip-services4-components-go
+	github.com/pip-services4/pip-services4-go/pip-services4-config-go => ../pip-services4-config-go
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go => ../pip-services4-data-go
+	github.com/pip-services4/pip-services4-go/pip-services4-expressions-go => ../pip-services4-expressions-go
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go => ../pip-services4-observability-go
+	github.com/pip-services4/pip-services4-go/pip-services4-rpc-go => ../pip-services4-rpc-go
+)
@@ -0,0 +1,70 @@
+package test_services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cexec "github.com/pip-services4/pip-services4-go/pip-services4-components-go/exec"
+	grpcservices "github.com/pip-services4/pip-services4-go/pip-services4-grpc-go/controllers"
+	"github.com/pip-services4/pip-services4-go/pip-services4-grpc-go/test"
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceContextPropagation(t *testing.T) {
+	ctx := context.Background()
+
+	grpcConfig := cconf.NewConfigParamsFromTuples(
+		"connection.protocol", "http",
+		"connection.host", "localhost",
+		"connection.port", 3006,
+	)
+
+	endpoint := grpcservices.NewGrpcEndpoint()
+	endpoint.Configure(ctx, grpcConfig)
+	endpoint.RegisterCommandableMethod("trace.check", nil,
+		func(ctx context.Context, args *cexec.Parameters) (result any, err error) {
+			spanContext, _ := ctrace.GetSpanContext(ctx)
+			return map[string]any{
+				"trace_id": spanContext.TraceId,
+				"span_id":  spanContext.SpanId,
+				"remote":   spanContext.Remote,
+				"user_id":  ctrace.GetBaggageValue(ctx, "userId"),
+			}, nil
+		})
+
+	err := endpoint.Open(ctx)
+	assert.Nil(t, err)
+	defer endpoint.Close(ctx)
+
+	// wait server start
+	<-time.After(100 * time.Millisecond)
+
+	client := test.NewTestCommandableGrpcClient("trace")
+	client.Configure(ctx, grpcConfig)
+	err = client.Open(ctx)
+	assert.Nil(t, err)
+	defer client.Close(ctx)
+
+	callCtx := ctrace.NewContextWithSpanContext(ctx, ctrace.SpanContext{
+		TraceId:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanId:     "00f067aa0ba902b7",
+		TraceFlags: ctrace.TraceFlagsSampled,
+	})
+	callCtx = ctrace.NewContextWithBaggageValue(callCtx, "userId", "alice")
+
+	response, err := client.CallCommand(callCtx, "check", nil)
+	assert.Nil(t, err)
+
+	var result map[string]any
+	err = json.Unmarshal([]byte(response.ResultJson), &result)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", result["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", result["span_id"])
+	assert.Equal(t, true, result["remote"])
+	assert.Equal(t, "alice", result["user_id"])
+}
@@ -440,6 +440,8 @@ func (c *RestClient) prepareRequest(ctx context.Context,
 	if c.contextLocation == "headers" || c.contextLocation == "both" {
 		req.Header.Set("trace_id", cctx.GetTraceId(ctx))
 	}
+	// Propagate W3C trace context and baggage
+	ctrace.InjectTraceContext(ctx, ctrace.HeaderTraceContextCarrier(req.Header))
 	for k, v := range c.Headers.Value() {
 		req.Header.Set(k, v)
 	}
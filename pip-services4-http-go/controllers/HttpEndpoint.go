@@ -27,6 +27,7 @@ import (
 	cvalid "github.com/pip-services4/pip-services4-go/pip-services4-data-go/validate"
 	ccount "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/count"
 	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
 )
 
 // HttpEndpoint used for creating HTTP endpoints. An endpoint is a URL,
@@ -116,6 +117,9 @@ func NewHttpEndpoint() *HttpEndpoint {
 		//"X-CSRF-Token",
 		//"Authorization",
 		"trace_id",
+		ctrace.TraceParentHeader,
+		ctrace.TraceStateHeader,
+		ctrace.BaggageHeader,
 		//"access_token",
 	}
 	c.allowedOrigins = make([]string, 0)
@@ -221,6 +225,7 @@ func (c *HttpEndpoint) Open(ctx context.Context) error {
 	}).Handler)
 
 	c.mux.Use(c.noCache)
+	c.mux.Use(c.extractTraceContext)
 	c.mux.Use(c.doMaintenance)
 
 	c.performRegistrations()
@@ -268,6 +273,14 @@ func (c *HttpEndpoint) noCache(next http.Handler) http.Handler {
 	})
 }
 
+// extractTraceContext puts W3C trace context and baggage from request headers into request context
+func (c *HttpEndpoint) extractTraceContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := ctrace.ExtractTraceContext(r.Context(), ctrace.HeaderTraceContextCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // doMaintenance returns maintenance error code
 func (c *HttpEndpoint) doMaintenance(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -7,7 +7,7 @@ require (
 	github.com/pip-services4/pip-services4-go/pip-services4-components-go v0.0.1-2
 	github.com/pip-services4/pip-services4-go/pip-services4-config-go v0.0.0-20240304141352-928143cb0946
 	github.com/pip-services4/pip-services4-go/pip-services4-data-go v0.0.1-2
-	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.0-20240304141352-928143cb0946
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.1-3
 	github.com/pip-services4/pip-services4-go/pip-services4-rpc-go v0.0.0-20240304141352-928143cb0946
 	github.com/rs/cors v1.9.0
 	github.com/stretchr/testify v1.8.4
@@ -17,8 +17,16 @@ require (
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/google/uuid v1.6.0 // indirect
-	github.com/pip-services4/pip-services4-go/pip-services4-expressions-go v0.0.1-2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go => ../pip-services4-commons-go
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go => ../pip-services4-components-go
+	github.com/pip-services4/pip-services4-go/pip-services4-config-go => ../pip-services4-config-go
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go => ../pip-services4-data-go
+	github.com/pip-services4/pip-services4-go/pip-services4-expressions-go => ../pip-services4-expressions-go
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go => ../pip-services4-observability-go
+	github.com/pip-services4/pip-services4-go/pip-services4-rpc-go => ../pip-services4-rpc-go
+)
@@ -0,0 +1,71 @@
+package test_clients
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	"github.com/pip-services4/pip-services4-go/pip-services4-http-go/controllers"
+	"github.com/pip-services4/pip-services4-go/pip-services4-http-go/test"
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceContextPropagation(t *testing.T) {
+	ctx := context.Background()
+	config := cconf.NewConfigParamsFromTuples(
+		"connection.protocol", "http",
+		"connection.host", "localhost",
+		"connection.port", DummyCommandableHttpControllerPort+1,
+	)
+
+	endpoint := controllers.NewHttpEndpoint()
+	endpoint.Configure(ctx, config)
+	err := endpoint.Open(ctx)
+	assert.Nil(t, err)
+	defer endpoint.Close(ctx)
+
+	endpoint.RegisterRoute(http.MethodGet, "/trace", nil, func(res http.ResponseWriter, req *http.Request) {
+		spanContext, _ := ctrace.GetSpanContext(req.Context())
+		result := map[string]any{
+			"trace_id": spanContext.TraceId,
+			"span_id":  spanContext.SpanId,
+			"remote":   spanContext.Remote,
+			"user_id":  ctrace.GetBaggageValue(req.Context(), "userId"),
+		}
+		controllers.HttpResponseSender.SendResult(res, req, result, nil)
+	})
+	time.Sleep(time.Second)
+
+	client := test.NewTestRestClient("")
+	client.Configure(ctx, config)
+	err = client.Open(ctx)
+	assert.Nil(t, err)
+	defer client.Close(ctx)
+
+	callCtx := ctrace.NewContextWithSpanContext(ctx, ctrace.SpanContext{
+		TraceId:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanId:     "00f067aa0ba902b7",
+		TraceFlags: ctrace.TraceFlagsSampled,
+	})
+	callCtx = ctrace.NewContextWithBaggageValue(callCtx, "userId", "alice")
+
+	response, err := client.Call(callCtx, http.MethodGet, "/trace", nil, nil)
+	assert.Nil(t, err)
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+	var result map[string]any
+	err = json.Unmarshal(body, &result)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", result["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", result["span_id"])
+	assert.Equal(t, true, result["remote"])
+	assert.Equal(t, "alice", result["user_id"])
+}
@@ -9,6 +9,7 @@ import (
 
 // Creates KafkaMessageQueue components by their descriptors.
 // See KafkaMessageQueue
+// See KafkaTopic
 type DefaultKafkaFactory struct {
 	*cbuild.Factory
 }
@@ -21,6 +22,7 @@ func NewDefaultKafkaFactory() *DefaultKafkaFactory {
 	kafkaQueueFactoryDescriptor := cref.NewDescriptor("pip-services", "queue-factory", "kafka", "*", "1.0")
 	kafkaConnectionDescriptor := cref.NewDescriptor("pip-services", "connection", "kafka", "*", "1.0")
 	kafkaQueueDescriptor := cref.NewDescriptor("pip-services", "message-queue", "kafka", "*", "1.0")
+	kafkaTopicDescriptor := cref.NewDescriptor("pip-services", "topic", "kafka", "*", "1.0")
 
 	c.RegisterType(kafkaQueueFactoryDescriptor, NewKafkaMessageQueueFactory)
 
@@ -36,5 +38,15 @@ func NewDefaultKafkaFactory() *DefaultKafkaFactory {
 		return queues.NewKafkaMessageQueue(name)
 	})
 
+	c.Register(kafkaTopicDescriptor, func(locator interface{}) interface{} {
+		name := ""
+		descriptor, ok := locator.(*cref.Descriptor)
+		if ok {
+			name = descriptor.Name()
+		}
+
+		return queues.NewKafkaTopic(name)
+	})
+
 	return &c
 }
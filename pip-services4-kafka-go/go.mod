@@ -4,11 +4,11 @@ go 1.20
 
 require (
 	github.com/Shopify/sarama v1.38.1
-	github.com/pip-services4/pip-services4-go/pip-services4-commons-go v0.0.0-20230707031404-19c86e470df6
-	github.com/pip-services4/pip-services4-go/pip-services4-components-go v0.0.0-20230707031404-19c86e470df6
-	github.com/pip-services4/pip-services4-go/pip-services4-config-go v0.0.0-20230707031404-19c86e470df6
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go v0.0.1-2
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go v0.0.1-2
+	github.com/pip-services4/pip-services4-go/pip-services4-config-go v0.0.1-3
 	github.com/pip-services4/pip-services4-go/pip-services4-messaging-go v0.0.0-20230707031404-19c86e470df6
-	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.0-20230707031404-19c86e470df6
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.1-3
 	github.com/stretchr/testify v1.8.4
 )
 
@@ -19,6 +19,7 @@ require (
 	github.com/eapache/queue v1.1.0 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
 	github.com/google/uuid v1.3.0 // indirect
+	github.com/hamba/avro/v2 v2.20.0 // indirect
 	github.com/hashicorp/errwrap v1.0.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/hashicorp/go-uuid v1.0.3 // indirect
@@ -27,14 +28,35 @@ require (
 	github.com/jcmturner/gofork v1.7.6 // indirect
 	github.com/jcmturner/gokrb5/v8 v8.4.3 // indirect
 	github.com/jcmturner/rpc/v2 v2.0.3 // indirect
-	github.com/klauspost/compress v1.15.14 // indirect
+	github.com/jinzhu/copier v0.3.5 // indirect
+	github.com/json-iterator/go v1.1.12 // indirect
+	github.com/klauspost/compress v1.17.5 // indirect
 	github.com/kr/text v0.2.0 // indirect
+	github.com/mitchellh/mapstructure v1.5.0 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/pierrec/lz4/v4 v4.1.17 // indirect
-	github.com/pip-services4/pip-services4-go/pip-services4-data-go v0.0.0-20230707031404-19c86e470df6 // indirect
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go v0.0.1-2 // indirect
+	github.com/pip-services4/pip-services4-go/pip-services4-persistence-go v0.0.0-20230719170734-6e7b58414323 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rcrowley/go-metrics v0.0.0-20201227073835-cf1acfcdf475 // indirect
 	github.com/rogpeppe/go-internal v1.11.0 // indirect
+	github.com/vmihailenco/msgpack/v5 v5.4.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
 	golang.org/x/crypto v0.0.0-20220722155217-630584e8d5aa // indirect
 	golang.org/x/net v0.5.0 // indirect
+	google.golang.org/protobuf v1.31.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go => ../pip-services4-commons-go
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go => ../pip-services4-components-go
+	github.com/pip-services4/pip-services4-go/pip-services4-config-go => ../pip-services4-config-go
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go => ../pip-services4-data-go
+	github.com/pip-services4/pip-services4-go/pip-services4-expressions-go => ../pip-services4-expressions-go
+	github.com/pip-services4/pip-services4-go/pip-services4-messaging-go => ../pip-services4-messaging-go
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go => ../pip-services4-observability-go
+	github.com/pip-services4/pip-services4-go/pip-services4-persistence-go => ../pip-services4-persistence-go
+	github.com/pip-services4/pip-services4-go/pip-services4-rpc-go => ../pip-services4-rpc-go
+)
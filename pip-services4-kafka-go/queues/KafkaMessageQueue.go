@@ -44,6 +44,8 @@ import (
 //   - max_retries:          	(optional) maximum retry attempts (default: 5)
 //   - retry_timeout:        	(optional) number of milliseconds to wait on each reconnection attempt (default: 30000)
 //   - request_timeout:      	(optional) number of milliseconds to wait on flushing messages (default: 30000)
+//   - dead_letter_queue:    	(optional) name of Kafka topic to move poison messages to (default: none, messages are dropped)
+//   - max_delivery_count:   	(optional) maximum number of deliveries before a message is moved to dead letter queue (default: 0 unlimited)
 //
 // References:
 //
@@ -91,6 +93,8 @@ type KafkaMessageQueue struct {
 	Logger *clog.CompositeLogger
 	// The Kafka connection component.
 	Connection *connect.KafkaConnection
+	// The scheduler that holds delayed messages since Kafka does not support them.
+	Scheduler *cqueues.MessageDelayScheduler
 
 	topic         string
 	groupId       string
@@ -103,6 +107,7 @@ type KafkaMessageQueue struct {
 
 	writePartition     int
 	readablePartitions []int32
+	deadLetterTopic    string
 
 	ready chan bool
 }
@@ -133,7 +138,9 @@ func NewKafkaMessageQueue(name string) *KafkaMessageQueue {
 		ready: make(chan bool),
 	}
 	c.MessageQueue = cqueues.InheritMessageQueue(&c, name,
-		cqueues.NewMessagingCapabilities(false, true, true, true, true, false, false, false, true))
+		cqueues.NewMessagingCapabilities(false, true, true, true, true, false, false, true, true).WithDelay(true))
+	c.Scheduler = cqueues.NewMessageDelayScheduler(&c)
+	c.Scheduler.Logger = c.Logger
 	c.DependencyResolver = cref.NewDependencyResolver()
 	c.DependencyResolver.Configure(context.Background(), c.defaultConfig)
 
@@ -159,6 +166,8 @@ func (c *KafkaMessageQueue) Configure(ctx context.Context, config *cconf.ConfigP
 	c.autoSubscribe = config.GetAsBooleanWithDefault("options.autosubscribe", c.autoSubscribe)
 
 	c.writePartition = config.GetAsIntegerWithDefault("options.write_partition", c.writePartition)
+	c.deadLetterTopic = config.GetAsStringWithDefault("options.dead_letter_queue", c.deadLetterTopic)
+	c.MaxDeliveryCount = config.GetAsIntegerWithDefault("options.max_delivery_count", c.MaxDeliveryCount)
 
 	if partitions, ok := config.GetAsNullableString("options.read_partitions"); ok {
 		for _, strVal := range strings.Split(partitions, ";") {
@@ -250,24 +259,31 @@ func (c *KafkaMessageQueue) Open(ctx context.Context) (err error) {
 		return err
 	}
 
-	// Create topic if it does not exist
+	// Create topics if they do not exist
 	topics, err := c.Connection.ReadQueueNames()
 	if err != nil {
 		return err
 	}
 
-	found := false
-	for _, v := range topics {
-		if v == c.getTopic() {
-			found = true
-			break
-		}
+	required := []string{c.getTopic()}
+	if c.deadLetterTopic != "" {
+		required = append(required, c.deadLetterTopic)
 	}
 
-	if !found {
-		err := c.Connection.CreateQueue(c.getTopic())
-		if err != nil {
-			return err
+	for _, topic := range required {
+		found := false
+		for _, v := range topics {
+			if v == topic {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			err := c.Connection.CreateQueue(topic)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -280,6 +296,7 @@ func (c *KafkaMessageQueue) Open(ctx context.Context) (err error) {
 	}
 
 	c.opened = true
+	c.Scheduler.Start(ctx)
 
 	return err
 }
@@ -310,6 +327,8 @@ func (c *KafkaMessageQueue) Close(ctx context.Context) (err error) {
 		c.subscribed = false
 	}
 
+	c.Scheduler.Stop(ctx)
+
 	c.Lock.Lock()
 	defer c.Lock.Unlock()
 	c.opened = false
@@ -380,6 +399,17 @@ func (c *KafkaMessageQueue) fromMessage(message *cqueues.MessageEnvelope) (*kafk
 			Key:   []byte("message_type"),
 			Value: []byte(message.MessageType),
 		},
+		{
+			Key:   []byte("delivery_count"),
+			Value: []byte(strconv.Itoa(message.DeliveryCount)),
+		},
+	}
+
+	for key, value := range message.Headers {
+		headers = append(headers, kafka.RecordHeader{
+			Key:   []byte(key),
+			Value: []byte(value),
+		})
 	}
 
 	msg := &kafka.ProducerMessage{}
@@ -401,6 +431,18 @@ func (c *KafkaMessageQueue) toMessage(msg *connect.KafkaMessage) (*cqueues.Messa
 	message.MessageId = string(msg.Message.Key)
 	message.SentTime = msg.Message.Timestamp
 	message.Message = msg.Message.Value
+	// Count this delivery on top of the previous ones
+	deliveryCount, _ := strconv.Atoi(c.getHeaderByKey(msg.Message.Headers, "delivery_count"))
+	message.DeliveryCount = deliveryCount + 1
+
+	// Collect custom headers
+	for _, header := range msg.Message.Headers {
+		key := string(header.Key)
+		if key != "trace_id" && key != "message_type" && key != "delivery_count" {
+			message.SetHeader(key, string(header.Value))
+		}
+	}
+
 	message.SetReference(msg)
 
 	return message, nil
@@ -486,6 +528,11 @@ func (c *KafkaMessageQueue) OnMessage(ctx context.Context, msg *connect.KafkaMes
 	c.Counters.IncrementOne(ctx, "queue."+c.Name()+".received_messages")
 	c.Logger.Debug(cctx.NewContextWithTraceId(ctx, message.TraceId), "Received message %s via %s", message, c.Name())
 
+	// Skip poison messages
+	if c.MoveToDeadLetterIfExceeded(ctx, message) {
+		return
+	}
+
 	// Send message to receiver if its set or put it into the queue
 	c.Lock.Lock()
 	if c.receiver != nil {
@@ -509,7 +556,7 @@ func (c *KafkaMessageQueue) Clear(ctx context.Context) error {
 
 	c.messages = make([]*cqueues.MessageEnvelope, 0)
 
-	return nil
+	return c.Scheduler.Clear(ctx)
 }
 
 // ReadMessageCount method are reads the current number of messages in the queue to be delivered.
@@ -636,6 +683,51 @@ func (c *KafkaMessageQueue) Receive(ctx context.Context, waitTimeout time.Durati
 	return message, nil
 }
 
+// ReceiveBatch method are receives multiple incoming messages and removes them from the queue.
+// The method waits for the first message to come and then takes all collected messages up to the maximum count.
+// Parameters:
+//   - ctx context.Context	transaction id to trace execution through call chain.
+//   - maxCount  int64     a maximum number of messages to receive.
+//   - waitTimeout  time.Duration     a timeout in milliseconds to wait for messages to come.
+//
+// Returns: a list with messages or error.
+func (c *KafkaMessageQueue) ReceiveBatch(ctx context.Context, maxCount int64, waitTimeout time.Duration) ([]*cqueues.MessageEnvelope, error) {
+	err := c.CheckOpen(cctx.GetTraceId(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// Subscribe if needed
+	err = c.subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []*cqueues.MessageEnvelope{}
+	elapsedTime := time.Duration(0)
+
+	for elapsedTime < waitTimeout && len(messages) == 0 {
+		c.Lock.Lock()
+		count := int64(len(c.messages))
+		if count == 0 {
+			c.Lock.Unlock()
+			time.Sleep(time.Duration(100) * time.Millisecond)
+			elapsedTime += time.Duration(100) * time.Millisecond
+			continue
+		}
+
+		// Get messages from the queue
+		if count > maxCount {
+			count = maxCount
+		}
+		messages = append(messages, c.messages[:count]...)
+		c.messages = c.messages[count:]
+		c.Lock.Unlock()
+	}
+
+	return messages, nil
+}
+
 // Send method are sends a message into the queue.
 // Parameters:
 //   - ctx context.Context	transaction id to trace execution through call chain.
@@ -648,6 +740,14 @@ func (c *KafkaMessageQueue) Send(ctx context.Context, envelop *cqueues.MessageEn
 		return err
 	}
 
+	// Propagate W3C trace context and baggage to receivers
+	cqueues.InjectTraceContext(ctx, envelop)
+
+	// Kafka does not support delayed delivery, so the message is held until its visible time
+	if !envelop.IsVisible(time.Now()) {
+		return c.Scheduler.Schedule(ctx, envelop)
+	}
+
 	c.Counters.IncrementOne(ctx, "queue."+c.Name()+".sent_messages")
 	c.Logger.Debug(cctx.NewContextWithTraceId(ctx, envelop.TraceId), "Sent message %s via %s", envelop.String(), c.Name())
 
@@ -674,6 +774,66 @@ func (c *KafkaMessageQueue) Send(ctx context.Context, envelop *cqueues.MessageEn
 	return nil
 }
 
+// SendBatch method are sends multiple messages into the queue in a single publish request.
+// Delayed messages are held by the Scheduler and sent when their visible time comes.
+// Parameters:
+//   - ctx context.Context	transaction id to trace execution through call chain.
+//   - envelopes []*cqueues.MessageEnvelope  a list of message envelops to be sent.
+//
+// Returns: error or nil for success.
+func (c *KafkaMessageQueue) SendBatch(ctx context.Context, envelopes []*cqueues.MessageEnvelope) error {
+	err := c.CheckOpen(cctx.GetTraceId(ctx))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	msgs := make([]*kafka.ProducerMessage, 0, len(envelopes))
+
+	for _, envelop := range envelopes {
+		cqueues.InjectTraceContext(ctx, envelop)
+
+		// Kafka does not support delayed delivery, so the message is held until its visible time
+		if !envelop.IsVisible(now) {
+			err = c.Scheduler.Schedule(ctx, envelop)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		msg, err := c.fromMessage(envelop)
+		if err != nil {
+			return err
+		}
+
+		if c.writePartition != -1 {
+			msg.Partition = int32(c.writePartition)
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	topic := c.Name()
+	if topic == "" {
+		topic = c.topic
+	}
+
+	err = c.Connection.Publish(ctx, topic, msgs)
+	if err != nil {
+		c.Logger.Error(ctx, err, "Failed to send %d messages via %s", len(msgs), c.Name())
+		return err
+	}
+
+	c.Counters.Increment(ctx, "queue."+c.Name()+".sent_messages", int64(len(msgs)))
+	c.Logger.Debug(ctx, "Sent %d messages via %s", len(msgs), c.Name())
+
+	return nil
+}
+
 // RenewLock method are renews a lock on a message that makes it invisible from other receivers in the queue.
 // This method is usually used to extend the message processing time.
 // Important: This method is not supported by Kafka.
@@ -703,10 +863,10 @@ func (c *KafkaMessageQueue) Complete(ctx context.Context, message *cqueues.Messa
 		return err
 	}
 
-	msg := message.GetReference().(*connect.KafkaMessage)
+	msg, ok := message.GetReference().(*connect.KafkaMessage)
 
 	// Skip on autocommit
-	if c.autoCommit || msg == nil {
+	if c.autoCommit || !ok || msg == nil {
 		return nil
 	}
 
@@ -718,10 +878,61 @@ func (c *KafkaMessageQueue) Complete(ctx context.Context, message *cqueues.Messa
 	return nil
 }
 
+// CompleteBatch method are permanently removes multiple messages from the queue.
+// Offsets of all messages are marked and committed at once.
+// Parameters:
+//   - ctx context.Context	operation context
+//   - messages  []*cqueues.MessageEnvelope a list of messages to remove.
+//
+// Returns: error
+// error or nil for success.
+func (c *KafkaMessageQueue) CompleteBatch(ctx context.Context, messages []*cqueues.MessageEnvelope) error {
+	err := c.CheckOpen(cctx.GetTraceId(ctx))
+	if err != nil {
+		return err
+	}
+
+	// Skip on autocommit
+	if c.autoCommit {
+		return nil
+	}
+
+	// Mark offsets of all messages and commit every session once
+	sessions := make([]kafka.ConsumerGroupSession, 0)
+	for _, message := range messages {
+		msg, ok := message.GetReference().(*connect.KafkaMessage)
+		if !ok || msg == nil {
+			continue
+		}
+
+		msg.Session.MarkOffset(msg.Message.Topic, msg.Message.Partition, msg.Message.Offset, "")
+		message.SetReference(nil)
+
+		found := false
+		for _, session := range sessions {
+			if session == msg.Session {
+				found = true
+				break
+			}
+		}
+		if !found {
+			sessions = append(sessions, msg.Session)
+		}
+	}
+
+	for _, session := range sessions {
+		session.Commit()
+	}
+
+	return nil
+}
+
 //		Abandon method are returnes message into the queue and makes it available for all subscribers to receive it again.
 //		This method is usually used to return a message which could not be processed at the moment
 //		to repeat the attempt. Messages that cause unrecoverable errors shall be removed permanently
 //		or/and send to dead letter queue.
+//		When max_delivery_count is set the message is sent again with incremented delivery count
+//		and the original message is completed.
 //		Parameters:
 //			- ctx context.Context	operation context
 //			- message *cqueues.MessageEnvelope  a message to return.
@@ -733,10 +944,19 @@ func (c *KafkaMessageQueue) Abandon(ctx context.Context, message *cqueues.Messag
 		return err
 	}
 
-	msg := message.GetReference().(*connect.KafkaMessage)
+	// Resend the message to keep track of deliveries
+	if c.MaxDeliveryCount > 0 {
+		err = c.Send(ctx, message.Clone())
+		if err != nil {
+			return err
+		}
+		return c.Complete(ctx, message)
+	}
+
+	msg, ok := message.GetReference().(*connect.KafkaMessage)
 
 	// Skip on autocommit
-	if c.autoCommit || msg == nil {
+	if c.autoCommit || !ok || msg == nil {
 		return nil
 	}
 
@@ -749,7 +969,8 @@ func (c *KafkaMessageQueue) Abandon(ctx context.Context, message *cqueues.Messag
 }
 
 // Permanently removes a message from the queue and sends it to dead letter queue.
-// Important: This method is not supported by Kafka.
+// The message is sent to DeadLetterQueue when it is set or to the topic
+// configured by options.dead_letter_queue. Otherwise it is dropped.
 // Parameters:
 //   - ctx context.Context	operation context
 //   - message  *cqueues.MessageEnvelope a message to be removed.
@@ -757,8 +978,34 @@ func (c *KafkaMessageQueue) Abandon(ctx context.Context, message *cqueues.Messag
 // Returns: error
 // error or nil for success.
 func (c *KafkaMessageQueue) MoveToDeadLetter(ctx context.Context, message *cqueues.MessageEnvelope) error {
-	// Not supported
-	return nil
+	err := c.CheckOpen(cctx.GetTraceId(ctx))
+	if err != nil {
+		return err
+	}
+
+	sent, err := c.SendToDeadLetterQueue(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	if !sent && c.deadLetterTopic != "" {
+		msg, err := c.fromMessage(message)
+		if err != nil {
+			return err
+		}
+		msg.Topic = c.deadLetterTopic
+
+		err = c.Connection.Publish(ctx, c.deadLetterTopic, []*kafka.ProducerMessage{msg})
+		if err != nil {
+			c.Logger.Error(cctx.NewContextWithTraceId(ctx, message.TraceId), err, "Failed to move message to %s", c.deadLetterTopic)
+			return err
+		}
+	}
+
+	c.Counters.IncrementOne(ctx, "queue."+c.Name()+".dead_messages")
+	c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Moved to dead message %s at %s", message, c.Name())
+
+	return c.Complete(ctx, message)
 }
 
 func (c *KafkaMessageQueue) sendMessageToReceiver(ctx context.Context, receiver cqueues.IMessageReceiver, message *cqueues.MessageEnvelope) {
@@ -771,7 +1018,7 @@ func (c *KafkaMessageQueue) sendMessageToReceiver(ctx context.Context, receiver
 		}
 	}()
 
-	err := receiver.ReceiveMessage(ctx, message, c)
+	err := receiver.ReceiveMessage(cqueues.ExtractTraceContext(ctx, message), message, c)
 	if err != nil {
 		c.Logger.Error(cctx.NewContextWithTraceId(ctx, traceId), err, "Failed to process the message")
 	}
@@ -806,7 +1053,7 @@ func (c *KafkaMessageQueue) Listen(ctx context.Context, receiver cqueues.IMessag
 
 	// Resend collected messages to receiver
 	for _, message := range batchMessages {
-		receiver.ReceiveMessage(ctx, message, c)
+		receiver.ReceiveMessage(cqueues.ExtractTraceContext(ctx, message), message, c)
 	}
 
 	// Set the receiver
@@ -0,0 +1,213 @@
+package queues
+
+import (
+	"context"
+	"sync"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+)
+
+// KafkaTopic are publish/subscribe topic that delivers messages via Kafka topics.
+// Every subscription is a Kafka consumer group, so receivers that subscribe with the same name
+// share messages while different subscriptions receive every message.
+//
+// Topic name is a Kafka topic. Kafka consumer groups do not support wildcards,
+// so subscriptions to topic names with wildcards are rejected.
+// Subscriptions share the connection of the topic.
+//
+// Configuration parameters:
+//
+//   - from_beginning:                (optional) restarts receiving messages from the beginning (default: false)
+//   - autocommit:                    (optional) turns on/off autocommit (default: true)
+//   - connection(s):
+//   - discovery_key:               (optional) a key to retrieve the connection from  IDiscovery
+//   - host:                        host name or IP address
+//   - port:                        port number
+//   - uri:                         resource URI or connection string with all parameters in it
+//   - credential(s):
+//   - store_key:                   (optional) a key to retrieve the credentials from  ICredentialStore
+//   - username:                    user name
+//   - password:                    user password
+//   - options:
+//   - log_level:            	(optional) log level 0 - None, 1 - Error, 2 - Warn, 3 - Info, 4 - Debug (default: 1)
+//   - connect_timeout:      	(optional) number of milliseconds to connect to broker (default: 1000)
+//   - max_retries:          	(optional) maximum retry attempts (default: 5)
+//   - retry_timeout:        	(optional) number of milliseconds to wait on each reconnection attempt (default: 30000)
+//   - request_timeout:      	(optional) number of milliseconds to wait on flushing messages (default: 30000)
+//   - max_delivery_count:   	(optional) maximum number of deliveries before a message is moved to dead letter queue (default: 0 unlimited)
+//
+// References:
+//
+//   - *:logger:*:*:1.0             (optional)  ILogger components to pass log messages
+//   - *:counters:*:*:1.0           (optional)  ICounters components to pass collected measurements
+//   - *:discovery:*:*:1.0          (optional)  IDiscovery services to resolve connections
+//   - *:credential-store:*:*:1.0   (optional) Credential stores to resolve credentials
+//   - *:connection:kafka:*:1.0      (optional) Shared connection to Kafka service
+//
+// See ITopic
+// See KafkaMessageQueue
+//
+// Example:
+//
+//	ctx := context.Background()
+//	topic := NewKafkaTopic("orders")
+//	topic.Configure(ctx, cconf.NewConfigParamsFromTuples(
+//		"connection.host", "localhost",
+//		"connection.port", 9092,
+//	))
+//	_ = topic.Open(ctx)
+//
+//	subscription, err := topic.Subscribe(ctx, "billing")
+//	message, err := subscription.Receive(ctx, 10000*time.Millisecond)
+type KafkaTopic struct {
+	publisher     *KafkaMessageQueue
+	config        *cconf.ConfigParams
+	references    cref.IReferences
+	lock          sync.Mutex
+	subscriptions []*KafkaMessageQueue
+}
+
+// NewKafkaTopic are creates a new instance of the topic.
+//
+//	Parameters:
+//		- name  string a Kafka topic name.
+func NewKafkaTopic(name string) *KafkaTopic {
+	c := &KafkaTopic{
+		publisher:     NewKafkaMessageQueue(name),
+		config:        cconf.NewEmptyConfigParams(),
+		subscriptions: make([]*KafkaMessageQueue, 0),
+	}
+	return c
+}
+
+// Configure are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context	operation context
+//		- config    configuration parameters to be set.
+func (c *KafkaTopic) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.config = config.Override(cconf.NewConfigParamsFromTuples(
+		"topic", c.Name(),
+	))
+	c.publisher.Configure(ctx, c.config)
+}
+
+// SetReferences are sets references to dependent components.
+//
+//	Parameters:
+//		- ctx context.Context	operation context
+//		- references 	references to locate the component dependencies.
+func (c *KafkaTopic) SetReferences(ctx context.Context, references cref.IReferences) {
+	c.references = references
+	c.publisher.SetReferences(ctx, references)
+}
+
+// Name are gets the topic name.
+func (c *KafkaTopic) Name() string {
+	return c.publisher.Name()
+}
+
+// IsOpen are checks if the component is opened.
+func (c *KafkaTopic) IsOpen() bool {
+	return c.publisher.IsOpen()
+}
+
+// Open are opens the component.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//
+// Returns error or nil no errors occured.
+func (c *KafkaTopic) Open(ctx context.Context) error {
+	return c.publisher.Open(ctx)
+}
+
+// Close are closes all subscriptions and frees used resources.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//
+// Returns error or nil no errors occured.
+func (c *KafkaTopic) Close(ctx context.Context) error {
+	c.lock.Lock()
+	subscriptions := c.subscriptions
+	c.subscriptions = make([]*KafkaMessageQueue, 0)
+	c.lock.Unlock()
+
+	for _, subscription := range subscriptions {
+		if err := subscription.Close(ctx); err != nil {
+			return err
+		}
+	}
+	return c.publisher.Close(ctx)
+}
+
+// Publish are sends a message to all subscriptions of the topic.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- envelope  a message envelop to be published.
+//
+// Returns error or nil for success.
+func (c *KafkaTopic) Publish(ctx context.Context, envelope *cqueues.MessageEnvelope) error {
+	return c.publisher.Send(ctx, envelope)
+}
+
+// Subscribe are joins a Kafka consumer group with the subscription name.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- subscription  a name of the subscription.
+//
+// Returns the subscription or error.
+func (c *KafkaTopic) Subscribe(ctx context.Context, subscription string) (cqueues.ISubscription, error) {
+	if !c.IsOpen() {
+		return nil, cerr.NewInvalidStateError(cctx.GetTraceId(ctx), "NOT_OPENED", "Topic "+c.Name()+" is not opened")
+	}
+	if cqueues.IsTopicPattern(c.Name()) {
+		return nil, cerr.NewUnsupportedError(cctx.GetTraceId(ctx), "WILDCARD_NOT_SUPPORTED",
+			"Kafka does not support wildcard subscriptions to "+c.Name())
+	}
+
+	queue := NewKafkaMessageQueue(subscription)
+	queue.Configure(ctx, c.config.Override(cconf.NewConfigParamsFromTuples(
+		"group_id", subscription,
+		"options.autosubscribe", true,
+	)))
+	if c.references != nil {
+		queue.SetReferences(ctx, c.references)
+	}
+
+	// Share the connection of the topic
+	queue.Connection = c.publisher.Connection
+	queue.localConnection = false
+
+	if err := queue.Open(ctx); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.subscriptions = append(c.subscriptions, queue)
+	c.lock.Unlock()
+
+	return cqueues.NewTopicSubscription(queue, c.Name(), func(ctx context.Context) error {
+		return c.unsubscribe(ctx, queue)
+	}), nil
+}
+
+func (c *KafkaTopic) unsubscribe(ctx context.Context, queue *KafkaMessageQueue) error {
+	c.lock.Lock()
+	for index, subscription := range c.subscriptions {
+		if subscription == queue {
+			c.subscriptions = append(c.subscriptions[:index:index], c.subscriptions[index+1:]...)
+			break
+		}
+	}
+	c.lock.Unlock()
+
+	return queue.Close(ctx)
+}
@@ -115,4 +115,12 @@ func TestKafkaMessageQueue(t *testing.T) {
 	c.setup(t)
 	t.Run("On Message", c.fixture.TestOnMessage)
 	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Delayed Message", c.fixture.TestSendDelayedMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Receive Batch", c.fixture.TestSendReceiveBatch)
+	c.teardown(t)
 }
@@ -0,0 +1,48 @@
+package test_queues
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	queues "github.com/pip-services4/pip-services4-go/pip-services4-kafka-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaTopic(t *testing.T) {
+	kafkaUri := os.Getenv("KAFKA_SERVICE_URI")
+	kafkaHost := os.Getenv("KAFKA_SERVICE_HOST")
+	if kafkaHost == "" {
+		kafkaHost = "localhost"
+	}
+
+	kafkaPort := os.Getenv("KAFKA_SERVICE_PORT")
+	if kafkaPort == "" {
+		kafkaPort = "9092"
+	}
+
+	kafkaUser := os.Getenv("KAFKA_USER")
+	kafkaPassword := os.Getenv("KAFKA_PASS")
+
+	if kafkaUri == "" && kafkaHost == "" {
+		return
+	}
+
+	topic := queues.NewKafkaTopic("test_topic")
+	topic.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"connection.uri", kafkaUri,
+		"connection.host", kafkaHost,
+		"connection.port", kafkaPort,
+		"credential.mechanism", "plain",
+		"credential.username", kafkaUser,
+		"credential.password", kafkaPassword,
+	))
+
+	err := topic.Open(context.Background())
+	assert.Nil(t, err)
+	defer topic.Close(context.Background())
+
+	fixture := NewTopicFixture(topic)
+	t.Run("Publish Subscribe", fixture.TestPublishSubscribe)
+}
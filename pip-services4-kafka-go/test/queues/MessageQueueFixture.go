@@ -183,3 +183,47 @@ func (c *TestMsgReceiver) ReceiveMessage(ctx context.Context, envelope *queues.M
 	c._envelope = envelope
 	return nil
 }
+
+func (c *MessageQueueFixture) TestSendDelayedMessage(t *testing.T) {
+	envelope1 := queues.NewMessageEnvelope("123", "Test", []byte("Test message"))
+	start := time.Now()
+	sndErr := c.queue.SendWithOptions(context.Background(), envelope1, queues.NewDelaySendOptions(500*time.Millisecond))
+	assert.Nil(t, sndErr)
+
+	envelope2, rcvErr := c.queue.Receive(context.Background(), 10000*time.Millisecond)
+	assert.Nil(t, rcvErr)
+	assert.NotNil(t, envelope2)
+	assert.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, envelope1.MessageType, envelope2.MessageType)
+	assert.Equal(t, envelope1.Message, envelope2.Message)
+	assert.Equal(t, envelope1.TraceId, envelope2.TraceId)
+}
+
+func (c *MessageQueueFixture) TestSendReceiveBatch(t *testing.T) {
+	envelopes := []*queues.MessageEnvelope{
+		queues.NewMessageEnvelope("123", "Test", []byte("Test message 1")),
+		queues.NewMessageEnvelope("123", "Test", []byte("Test message 2")),
+		queues.NewMessageEnvelope("123", "Test", []byte("Test message 3")),
+	}
+	sndErr := c.queue.SendBatch(context.Background(), envelopes)
+	assert.Nil(t, sndErr)
+
+	received := make([]*queues.MessageEnvelope, 0)
+	for len(received) < len(envelopes) {
+		batch, rcvErr := c.queue.ReceiveBatch(context.Background(), int64(len(envelopes)-len(received)), 10000*time.Millisecond)
+		assert.Nil(t, rcvErr)
+		if len(batch) == 0 {
+			break
+		}
+		received = append(received, batch...)
+	}
+
+	assert.Len(t, received, len(envelopes))
+	for index, envelope := range received {
+		assert.Equal(t, envelopes[index].MessageType, envelope.MessageType)
+		assert.Equal(t, envelopes[index].Message, envelope.Message)
+	}
+
+	cmplErr := c.queue.CompleteBatch(context.Background(), received)
+	assert.Nil(t, cmplErr)
+}
@@ -11,6 +11,7 @@ import (
 //
 // See Factory
 // See MemoryMessageQueue
+// See MemoryTopic
 type DefaultMessagingFactory struct {
 	*cbuild.Factory
 }
@@ -34,5 +35,16 @@ func NewDefaultMessagingFactory() *DefaultMessagingFactory {
 	})
 	c.RegisterType(memoryQueueFactoryDescriptor, NewMemoryMessageQueueFactory)
 
+	memoryTopicDescriptor := cref.NewDescriptor("pip-services", "topic", "memory", "*", "1.0")
+	c.Register(memoryTopicDescriptor, func(locator any) any {
+		name := ""
+		descriptor, ok := locator.(*cref.Descriptor)
+		if ok {
+			name = descriptor.Name()
+		}
+
+		return queues.NewMemoryTopic(name)
+	})
+
 	return &c
 }
@@ -0,0 +1,341 @@
+package clients
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	ccount "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/count"
+	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
+	"github.com/pip-services4/pip-services4-go/pip-services4-rpc-go/trace"
+)
+
+// CommandableMessageQueueClient abstract client that calls commandable service
+// via message queues.
+//
+// Commands are sent to the request queue as messages of type serviceName + "." + commandName
+// with all parameters in a JSON object. The client waits for the reply in the reply queue
+// and matches it with the request by "correlation_id" header.
+// Every client shall have its own reply queue, because replies to unknown requests are dropped.
+//
+//	Configuration parameters:
+//
+//		- dependencies:
+//			- request_queue:       override for request queue dependency
+//			- reply_queue:         override for reply queue dependency
+//		- options:
+//			- timeout:             invocation timeout in milliseconds (default: 10 sec)
+//
+//	References:
+//
+//		- *:logger:*:*:1.0         (optional) ILogger components to pass log messages
+//		- *:counters:*:*:1.0       (optional) ICounters components to pass collected measurements
+//		- *:tracer:*:*:1.0         (optional) ITracer components to record traces
+//
+// See CommandableMessageQueueController
+//
+//	Example:
+//		type MyCommandableMessageQueueClient struct {
+//			*CommandableMessageQueueClient
+//		}
+//
+//		func (c *MyCommandableMessageQueueClient) GetData(ctx context.Context, id string) (*MyData, error) {
+//			params := cdata.NewEmptyAnyValueMap()
+//			params.Put("id", id)
+//			reply, err := c.CallCommand(ctx, "get_mydata_by_id", params)
+//			if err != nil {
+//				return nil, err
+//			}
+//			return clients.HandleMessageReply[*MyData](reply)
+//		}
+//		...
+//
+//		client := NewMyCommandableMessageQueueClient()
+//		client.RequestQueue = requestQueue
+//		client.ReplyQueue = replyQueue
+//		err := client.Open(ctx)
+//
+//		result, err := client.GetData(ctx, "123")
+//
+//	Implements: IMessageReceiver
+type CommandableMessageQueueClient struct {
+	// The service name
+	Name string
+	// The invocation timeout
+	Timeout time.Duration
+	// The dependency resolver.
+	DependencyResolver *cref.DependencyResolver
+	// The logger.
+	Logger *clog.CompositeLogger
+	// The performance counters.
+	Counters *ccount.CompositeCounters
+	// The tracer.
+	Tracer *ctrace.CompositeTracer
+	// The queue to send requests to.
+	RequestQueue cqueues.IMessageQueue
+	// The queue to receive replies from.
+	ReplyQueue cqueues.IMessageQueue
+
+	lock    sync.Mutex
+	pending map[string]chan *cqueues.MessageEnvelope
+	opened  bool
+}
+
+// NewCommandableMessageQueueClient method are creates a new instance of the client.
+//
+//	Parameters:
+//		- name  a service name.
+//	Returns: *CommandableMessageQueueClient
+func NewCommandableMessageQueueClient(name string) *CommandableMessageQueueClient {
+	c := &CommandableMessageQueueClient{
+		Name:               name,
+		Timeout:            10000 * time.Millisecond,
+		DependencyResolver: cref.NewDependencyResolver(),
+		Logger:             clog.NewCompositeLogger(),
+		Counters:           ccount.NewCompositeCounters(),
+		Tracer:             ctrace.NewCompositeTracer(),
+		pending:            make(map[string]chan *cqueues.MessageEnvelope),
+	}
+	c.DependencyResolver.Put(context.Background(), "request_queue", "none")
+	c.DependencyResolver.Put(context.Background(), "reply_queue", "none")
+	return c
+}
+
+// Configure method are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config    configuration parameters to be set.
+func (c *CommandableMessageQueueClient) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.DependencyResolver.Configure(ctx, config)
+
+	timeout := config.GetAsLongWithDefault("options.timeout", c.Timeout.Milliseconds())
+	c.Timeout = time.Duration(timeout) * time.Millisecond
+}
+
+// SetReferences method are sets references to dependent components.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- references 	references to locate the component dependencies.
+func (c *CommandableMessageQueueClient) SetReferences(ctx context.Context, references cref.IReferences) {
+	c.Logger.SetReferences(ctx, references)
+	c.Counters.SetReferences(ctx, references)
+	c.Tracer.SetReferences(ctx, references)
+	c.DependencyResolver.SetReferences(ctx, references)
+
+	if queue, ok := c.DependencyResolver.GetOneOptional("request_queue").(cqueues.IMessageQueue); ok {
+		c.RequestQueue = queue
+	}
+	if queue, ok := c.DependencyResolver.GetOneOptional("reply_queue").(cqueues.IMessageQueue); ok {
+		c.ReplyQueue = queue
+	}
+}
+
+// IsOpen method are checks if the component is opened.
+//
+//	Returns: true if the component has been opened and false otherwise.
+func (c *CommandableMessageQueueClient) IsOpen() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.opened
+}
+
+// Open method are starts listening replies in the reply queue.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *CommandableMessageQueueClient) Open(ctx context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.opened {
+		return nil
+	}
+
+	traceId := cctx.GetTraceId(ctx)
+	if c.RequestQueue == nil {
+		return cerr.NewConfigError(traceId, "NO_REQUEST_QUEUE", "Request queue is not set")
+	}
+	if c.ReplyQueue == nil {
+		return cerr.NewConfigError(traceId, "NO_REPLY_QUEUE", "Reply queue is not set")
+	}
+
+	c.opened = true
+	c.ReplyQueue.BeginListen(cctx.NewContextWithTraceId(context.Background(), traceId), c)
+
+	c.Logger.Debug(ctx, "Started listening replies at %s", c.ReplyQueue.Name())
+	return nil
+}
+
+// Close method are stops listening replies and cancels pending calls.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *CommandableMessageQueueClient) Close(ctx context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.opened {
+		return nil
+	}
+
+	c.opened = false
+	c.ReplyQueue.EndListen(ctx)
+
+	for messageId, replies := range c.pending {
+		close(replies)
+		delete(c.pending, messageId)
+	}
+
+	c.Logger.Debug(ctx, "Stopped listening replies at %s", c.ReplyQueue.Name())
+	return nil
+}
+
+// Instrument method are adds instrumentation to log calls and measure call time.
+// It returns a InstrumentTiming object that is used to end the time measurement.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name              a method name.
+//	Returns: InstrumentTiming object to end the time measurement.
+func (c *CommandableMessageQueueClient) Instrument(ctx context.Context, name string) *trace.InstrumentTiming {
+	c.Logger.Trace(ctx, "Calling %s method", name)
+	c.Counters.IncrementOne(ctx, name+".call_count")
+
+	counterTiming := c.Counters.BeginTiming(ctx, name+".call_time")
+	traceTiming := c.Tracer.BeginTrace(ctx, name, "")
+	return trace.NewInstrumentTiming(ctx, name, "call",
+		c.Logger, c.Counters, counterTiming, traceTiming)
+}
+
+// CallCommand method are calls a remote command via message queues.
+// The call waits for the reply until the invocation timeout or the context is done.
+// Errors returned by the remote command are restored from ErrorDescription.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name              a name of the command to call.
+//		- params            (optional) command parameters.
+//	Returns: the reply message or error.
+func (c *CommandableMessageQueueClient) CallCommand(ctx context.Context, name string,
+	params *cdata.AnyValueMap) (*cqueues.MessageEnvelope, error) {
+
+	method := name
+	if c.Name != "" {
+		method = c.Name + "." + name
+	}
+	traceId := cctx.GetTraceId(ctx)
+
+	var value any
+	if params != nil {
+		value = params.Value()
+	}
+	request := cqueues.NewMessageEnvelopeFromObject(traceId, method, value)
+
+	replies, err := c.beginCall(traceId, request.MessageId)
+	if err != nil {
+		return nil, err
+	}
+	defer c.endCall(request.MessageId)
+
+	request.SetHeader(cqueues.ReplyToHeader, c.ReplyQueue.Name())
+
+	timing := c.Instrument(ctx, method)
+	reply, err := c.sendAndWait(ctx, request, replies)
+	timing.EndTiming(ctx, err)
+
+	return reply, err
+}
+
+func (c *CommandableMessageQueueClient) beginCall(traceId string, messageId string) (chan *cqueues.MessageEnvelope, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.opened {
+		return nil, cerr.NewInvalidStateError(traceId, "NOT_OPENED", "Client is not opened")
+	}
+
+	replies := make(chan *cqueues.MessageEnvelope, 1)
+	c.pending[messageId] = replies
+	return replies, nil
+}
+
+func (c *CommandableMessageQueueClient) endCall(messageId string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.pending, messageId)
+}
+
+func (c *CommandableMessageQueueClient) sendAndWait(ctx context.Context, request *cqueues.MessageEnvelope,
+	replies chan *cqueues.MessageEnvelope) (*cqueues.MessageEnvelope, error) {
+
+	if err := c.RequestQueue.Send(ctx, request); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(c.Timeout)
+	defer timer.Stop()
+
+	select {
+	case reply, ok := <-replies:
+		if !ok {
+			return nil, cerr.NewInvalidStateError(request.TraceId, "CLIENT_CLOSED",
+				"Client was closed while waiting for reply to "+request.MessageType)
+		}
+		if reply.MessageType == cqueues.CommandErrorMessageType {
+			description, err := cqueues.GetMessageAs[*cerr.ErrorDescription](reply)
+			if err != nil || description == nil {
+				return reply, cerr.NewUnknownError(request.TraceId, "INVALID_REPLY",
+					"Failed to read error reply to "+request.MessageType)
+			}
+			return reply, cerr.ApplicationErrorFactory.Create(description)
+		}
+		return reply, nil
+	case <-timer.C:
+		return nil, cerr.NewInvocationError(request.TraceId, "CALL_TIMEOUT",
+			"Call to "+request.MessageType+" timed out").
+			WithDetails("timeout", c.Timeout.Milliseconds())
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ReceiveMessage method are passes incoming reply to the pending call.
+// Replies to unknown or timed out calls are dropped.
+//
+//	Parameters:
+//		- ctx context.Context   operation context
+//		- envelope  an incoming reply message
+//		- queue     a queue where the message comes from
+//	Returns: error or nil for success.
+func (c *CommandableMessageQueueClient) ReceiveMessage(ctx context.Context,
+	envelope *cqueues.MessageEnvelope, queue cqueues.IMessageQueue) error {
+
+	correlationId := envelope.GetHeader(cqueues.CorrelationIdHeader)
+
+	c.lock.Lock()
+	replies, ok := c.pending[correlationId]
+	if ok {
+		delete(c.pending, correlationId)
+		replies <- envelope
+	}
+	c.lock.Unlock()
+
+	if !ok {
+		c.Logger.Debug(ctx, "Dropped reply %s to unknown request %s", envelope.MessageId, correlationId)
+	}
+
+	return queue.Complete(ctx, envelope)
+}
@@ -0,0 +1,21 @@
+package clients
+
+import (
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+)
+
+// HandleMessageReply method helps convert the result of a command from the reply message.
+// Empty replies are returned as default value of the result type.
+//
+//	Parameters:
+//		- reply *cqueues.MessageEnvelope a reply returned by CallCommand.
+//	Returns: T any result, err error
+func HandleMessageReply[T any](reply *cqueues.MessageEnvelope) (T, error) {
+	var defaultValue T
+
+	if reply == nil || len(reply.Message) == 0 {
+		return defaultValue, nil
+	}
+
+	return cqueues.GetMessageAs[T](reply)
+}
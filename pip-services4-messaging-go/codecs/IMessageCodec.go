@@ -0,0 +1,25 @@
+package codecs
+
+// IMessageCodec interface for codecs that serialize message payloads.
+// Codecs are identified by content types that are recorded in message envelopes,
+// so receivers are able to choose the right codec to read messages.
+//
+//	see MessageCodecRegistry
+type IMessageCodec interface {
+	// ContentType method are gets the content type of messages serialized by the codec.
+	//	Returns: the content type.
+	ContentType() string
+
+	// Encode method are serializes the value into the message payload.
+	//	Parameters:
+	//		- value     a value to serialize.
+	//	Returns: the serialized payload or error.
+	Encode(value any) ([]byte, error)
+
+	// Decode method are deserializes the message payload into the value.
+	//	Parameters:
+	//		- data      a serialized payload.
+	//		- value     a pointer to the value to deserialize into.
+	//	Returns: error or nil for success.
+	Decode(data []byte, value any) error
+}
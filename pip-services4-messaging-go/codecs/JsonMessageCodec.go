@@ -0,0 +1,57 @@
+package codecs
+
+import (
+	"encoding/json"
+
+	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+)
+
+// JsonMessageCodec codec that serializes message payloads as JSON.
+// It is the default codec used when messages have no content type.
+//
+//	Implements: IMessageCodec
+type JsonMessageCodec struct {
+}
+
+// NewJsonMessageCodec method are creates a new instance of the codec.
+//
+//	Returns: *JsonMessageCodec
+func NewJsonMessageCodec() *JsonMessageCodec {
+	return &JsonMessageCodec{}
+}
+
+// ContentType method are gets the content type of messages serialized by the codec.
+//
+//	Returns: "application/json"
+func (c *JsonMessageCodec) ContentType() string {
+	return JsonContentType
+}
+
+// Encode method are serializes the value into JSON.
+//
+//	Parameters:
+//		- value     a value to serialize.
+//	Returns: the serialized payload or error.
+func (c *JsonMessageCodec) Encode(value any) ([]byte, error) {
+	if value == nil {
+		return []byte{}, nil
+	}
+	result, err := cconv.JsonConverter.ToJson(value)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(result), nil
+}
+
+// Decode method are deserializes JSON into the value.
+//
+//	Parameters:
+//		- data      a serialized payload.
+//		- value     a pointer to the value to deserialize into.
+//	Returns: error or nil for success.
+func (c *JsonMessageCodec) Decode(data []byte, value any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, value)
+}
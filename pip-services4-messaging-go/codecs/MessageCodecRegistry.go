@@ -0,0 +1,107 @@
+package codecs
+
+import (
+	"strings"
+	"sync"
+)
+
+const (
+	// JsonContentType is a content type of messages serialized as JSON
+	JsonContentType = "application/json"
+	// ProtobufContentType is a content type of messages serialized as Protocol Buffers
+	ProtobufContentType = "application/x-protobuf"
+	// MessagePackContentType is a content type of messages serialized as MessagePack
+	MessagePackContentType = "application/msgpack"
+	// AvroContentType is a content type of messages serialized as Avro binary
+	AvroContentType = "application/avro"
+)
+
+// MessageCodecs is the default codec registry used by message envelopes.
+// It contains JSON, Protobuf and MessagePack codecs.
+// Avro codecs require schemas and shall be registered explicitly.
+var MessageCodecs = NewDefaultMessageCodecRegistry()
+
+// MessageCodecRegistry keeps message codecs by their content types.
+// Content types are compared case insensitive.
+//
+//	Example:
+//		codec, err := NewAvroMessageCodec(orderSchema)
+//		if err == nil {
+//			MessageCodecs.Register(codec)
+//		}
+//		...
+//		codec, ok := MessageCodecs.Get(envelope.GetHeader(queues.ContentTypeHeader))
+type MessageCodecRegistry struct {
+	lock   sync.RWMutex
+	codecs map[string]IMessageCodec
+}
+
+// NewMessageCodecRegistry method are creates a new empty registry.
+//
+//	Returns: *MessageCodecRegistry
+func NewMessageCodecRegistry() *MessageCodecRegistry {
+	return &MessageCodecRegistry{
+		codecs: make(map[string]IMessageCodec),
+	}
+}
+
+// NewDefaultMessageCodecRegistry method are creates a new registry with JSON, Protobuf and MessagePack codecs.
+//
+//	Returns: *MessageCodecRegistry
+func NewDefaultMessageCodecRegistry() *MessageCodecRegistry {
+	c := NewMessageCodecRegistry()
+	c.Register(NewJsonMessageCodec())
+	c.Register(NewProtobufMessageCodec())
+	c.Register(NewMessagePackMessageCodec())
+	return c
+}
+
+// Register method are registers the codec under its content type.
+// A codec registered before for the same content type is replaced.
+//
+//	Parameters:
+//		- codec     a codec to register.
+func (c *MessageCodecRegistry) Register(codec IMessageCodec) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.codecs[strings.ToLower(codec.ContentType())] = codec
+}
+
+// Unregister method are removes the codec for the content type.
+//
+//	Parameters:
+//		- contentType   a content type of the codec.
+func (c *MessageCodecRegistry) Unregister(contentType string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.codecs, strings.ToLower(contentType))
+}
+
+// Get method are finds the codec for the content type.
+//
+//	Parameters:
+//		- contentType   a content type of the codec.
+//	Returns: the codec and true or nil and false when the codec is not registered.
+func (c *MessageCodecRegistry) Get(contentType string) (IMessageCodec, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	codec, ok := c.codecs[strings.ToLower(contentType)]
+	return codec, ok
+}
+
+// ContentTypes method are gets content types of all registered codecs.
+//
+//	Returns: a list of content types.
+func (c *MessageCodecRegistry) ContentTypes() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	result := make([]string, 0, len(c.codecs))
+	for _, codec := range c.codecs {
+		result = append(result, codec.ContentType())
+	}
+	return result
+}
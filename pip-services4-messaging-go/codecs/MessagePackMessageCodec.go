@@ -0,0 +1,67 @@
+package codecs
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MessagePackMessageCodec codec that serializes message payloads as MessagePack.
+// Fields of structs are named by json tags, map keys are sorted and integers
+// are written in the most compact form, so payloads can be read by any MessagePack implementation.
+//
+//	Implements: IMessageCodec
+type MessagePackMessageCodec struct {
+}
+
+// NewMessagePackMessageCodec method are creates a new instance of the codec.
+//
+//	Returns: *MessagePackMessageCodec
+func NewMessagePackMessageCodec() *MessagePackMessageCodec {
+	return &MessagePackMessageCodec{}
+}
+
+// ContentType method are gets the content type of messages serialized by the codec.
+//
+//	Returns: "application/msgpack"
+func (c *MessagePackMessageCodec) ContentType() string {
+	return MessagePackContentType
+}
+
+// Encode method are serializes the value into MessagePack.
+//
+//	Parameters:
+//		- value     a value to serialize.
+//	Returns: the serialized payload or error.
+func (c *MessagePackMessageCodec) Encode(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := msgpack.NewEncoder(&buffer)
+	encoder.SetCustomStructTag("json")
+	encoder.SetSortMapKeys(true)
+	encoder.UseCompactInts(true)
+
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Decode method are deserializes MessagePack into the value.
+// Maps are read into map[string]any when the value is generic.
+//
+//	Parameters:
+//		- data      a serialized payload.
+//		- value     a pointer to the value to deserialize into.
+//	Returns: error or nil for success.
+func (c *MessagePackMessageCodec) Decode(data []byte, value any) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	decoder.SetMapDecoder(func(d *msgpack.Decoder) (any, error) {
+		return d.DecodeUntypedMap()
+	})
+	return decoder.Decode(value)
+}
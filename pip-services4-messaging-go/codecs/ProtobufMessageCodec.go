@@ -0,0 +1,80 @@
+package codecs
+
+import (
+	"fmt"
+	"reflect"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtobufMessageCodec codec that serializes message payloads as Protocol Buffers.
+// Values shall be generated protobuf messages, like the ones in pip-services4-grpc-go protos.
+//
+//	Example:
+//		envelope, err := queues.NewMessageEnvelopeFromEncodedObject("123", "invoke",
+//			&grpcproto.InvokeRequest{Method: "get_dummies"}, codecs.ProtobufContentType)
+//		...
+//		request, err := queues.GetMessageAs[*grpcproto.InvokeRequest](envelope)
+//
+//	Implements: IMessageCodec
+type ProtobufMessageCodec struct {
+}
+
+// NewProtobufMessageCodec method are creates a new instance of the codec.
+//
+//	Returns: *ProtobufMessageCodec
+func NewProtobufMessageCodec() *ProtobufMessageCodec {
+	return &ProtobufMessageCodec{}
+}
+
+// ContentType method are gets the content type of messages serialized by the codec.
+//
+//	Returns: "application/x-protobuf"
+func (c *ProtobufMessageCodec) ContentType() string {
+	return ProtobufContentType
+}
+
+// Encode method are serializes the protobuf message.
+//
+//	Parameters:
+//		- value     a protobuf message to serialize.
+//	Returns: the serialized payload or error.
+func (c *ProtobufMessageCodec) Encode(value any) ([]byte, error) {
+	message, ok := value.(proto.Message)
+	if !ok {
+		return nil, cerr.NewBadRequestError("", "NOT_PROTOBUF_MESSAGE",
+			fmt.Sprintf("Value of type %T is not a protobuf message", value))
+	}
+	return proto.Marshal(message)
+}
+
+// Decode method are deserializes the protobuf message.
+// The value can be a pointer to a protobuf message or a pointer to a nil pointer
+// to a protobuf message that is allocated by the codec.
+//
+//	Parameters:
+//		- data      a serialized payload.
+//		- value     a pointer to the protobuf message to deserialize into.
+//	Returns: error or nil for success.
+func (c *ProtobufMessageCodec) Decode(data []byte, value any) error {
+	if message, ok := value.(proto.Message); ok {
+		return proto.Unmarshal(data, message)
+	}
+
+	// Allocate the message when the value is a pointer to a message pointer
+	ref := reflect.ValueOf(value)
+	if ref.Kind() == reflect.Pointer && !ref.IsNil() && ref.Elem().Kind() == reflect.Pointer {
+		target := reflect.New(ref.Elem().Type().Elem())
+		if message, ok := target.Interface().(proto.Message); ok {
+			if err := proto.Unmarshal(data, message); err != nil {
+				return err
+			}
+			ref.Elem().Set(target)
+			return nil
+		}
+	}
+
+	return cerr.NewBadRequestError("", "NOT_PROTOBUF_MESSAGE",
+		fmt.Sprintf("Value of type %T is not a protobuf message", value))
+}
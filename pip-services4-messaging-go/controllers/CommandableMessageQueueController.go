@@ -0,0 +1,294 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cexec "github.com/pip-services4/pip-services4-go/pip-services4-components-go/exec"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	ccount "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/count"
+	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
+	ccomands "github.com/pip-services4/pip-services4-go/pip-services4-rpc-go/commands"
+	"github.com/pip-services4/pip-services4-go/pip-services4-rpc-go/trace"
+)
+
+// CommandableMessageQueueController service that receives commands via message queues
+// and executes them against commands defined in ICommandable components.
+//
+// Every request message has type serviceName + "." + commandName and carries command
+// arguments as JSON object. After the command is executed the controller sends a reply
+// with the result or with the error serialized as ErrorDescription. The reply carries
+// the id of the request message in "correlation_id" header.
+//
+// The reply is sent to the queue named in "reply_to" header of the request.
+// The queue is looked up in references by its name. When the request has no "reply_to" header
+// or the queue is not found, the reply is sent to the default reply queue.
+//
+//	Configuration parameters:
+//
+//		- name:                    (optional) a service name used as a prefix for message types
+//		- dependencies:
+//			- service:             override for service dependency
+//			- request_queue:       override for request queue dependency
+//			- reply_queue:         (optional) override for default reply queue dependency
+//
+//	References:
+//
+//		- *:logger:*:*:1.0               (optional) ILogger components to pass log messages
+//		- *:counters:*:*:1.0             (optional) ICounters components to pass collected measurements
+//		- *:tracer:*:*:1.0               (optional) ITracer components to record traces
+//		- *:message-queue:*:*:1.0        (optional) message queues to send replies to
+//
+// See CommandableMessageQueueClient
+//
+//	Example:
+//		controller := NewCommandableMessageQueueController("mydata")
+//		controller.DependencyResolver.Put(ctx, "service", cref.NewDescriptor("mygroup", "service", "default", "*", "1.0"))
+//		controller.DependencyResolver.Put(ctx, "request_queue", cref.NewDescriptor("pip-services", "message-queue", "memory", "requests", "1.0"))
+//		controller.SetReferences(ctx, cref.NewReferencesFromTuples(ctx,
+//			cref.NewDescriptor("mygroup", "service", "default", "default", "1.0"), service,
+//			cref.NewDescriptor("pip-services", "message-queue", "memory", "requests", "1.0"), requestQueue,
+//			cref.NewDescriptor("pip-services", "message-queue", "memory", "replies", "1.0"), replyQueue,
+//		))
+//
+//		err := controller.Open(ctx)
+//
+//	Implements: IMessageReceiver
+type CommandableMessageQueueController struct {
+	// The service name
+	Name string
+	// The dependency resolver.
+	DependencyResolver *cref.DependencyResolver
+	// The logger.
+	Logger *clog.CompositeLogger
+	// The performance counters.
+	Counters *ccount.CompositeCounters
+	// The tracer.
+	Tracer *ctrace.CompositeTracer
+	// The queue to receive requests from.
+	RequestQueue cqueues.IMessageQueue
+	// The default queue to send replies to.
+	ReplyQueue cqueues.IMessageQueue
+
+	references cref.IReferences
+	commandSet *ccomands.CommandSet
+	opened     bool
+}
+
+// NewCommandableMessageQueueController method are creates a new instance of the controller.
+//
+//	Parameters:
+//		- name  a service name.
+//	Returns: *CommandableMessageQueueController
+func NewCommandableMessageQueueController(name string) *CommandableMessageQueueController {
+	c := &CommandableMessageQueueController{
+		Name:               name,
+		DependencyResolver: cref.NewDependencyResolver(),
+		Logger:             clog.NewCompositeLogger(),
+		Counters:           ccount.NewCompositeCounters(),
+		Tracer:             ctrace.NewCompositeTracer(),
+	}
+	c.DependencyResolver.Put(context.Background(), "service", "none")
+	c.DependencyResolver.Put(context.Background(), "request_queue", "none")
+	c.DependencyResolver.Put(context.Background(), "reply_queue", "none")
+	return c
+}
+
+// Configure method are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config    configuration parameters to be set.
+func (c *CommandableMessageQueueController) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.Name = config.GetAsStringWithDefault("name", c.Name)
+	c.DependencyResolver.Configure(ctx, config)
+}
+
+// SetReferences method are sets references to dependent components.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- references 	references to locate the component dependencies.
+func (c *CommandableMessageQueueController) SetReferences(ctx context.Context, references cref.IReferences) {
+	c.references = references
+	c.Logger.SetReferences(ctx, references)
+	c.Counters.SetReferences(ctx, references)
+	c.Tracer.SetReferences(ctx, references)
+	c.DependencyResolver.SetReferences(ctx, references)
+
+	if queue, ok := c.DependencyResolver.GetOneOptional("request_queue").(cqueues.IMessageQueue); ok {
+		c.RequestQueue = queue
+	}
+	if queue, ok := c.DependencyResolver.GetOneOptional("reply_queue").(cqueues.IMessageQueue); ok {
+		c.ReplyQueue = queue
+	}
+}
+
+// IsOpen method are checks if the component is opened.
+//
+//	Returns: true if the component has been opened and false otherwise.
+func (c *CommandableMessageQueueController) IsOpen() bool {
+	return c.opened
+}
+
+// Open method are resolves the service commands and starts listening the request queue.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *CommandableMessageQueueController) Open(ctx context.Context) error {
+	if c.opened {
+		return nil
+	}
+
+	traceId := cctx.GetTraceId(ctx)
+
+	res, err := c.DependencyResolver.GetOneRequired("service")
+	if err != nil {
+		return err
+	}
+	service, ok := res.(ccomands.ICommandable)
+	if !ok {
+		return cerr.NewConfigError(traceId, "NOT_COMMANDABLE", "Service does not implement ICommandable")
+	}
+	c.commandSet = service.GetCommandSet()
+
+	if c.RequestQueue == nil {
+		return cerr.NewConfigError(traceId, "NO_REQUEST_QUEUE", "Request queue is not set")
+	}
+
+	c.opened = true
+	c.RequestQueue.BeginListen(cctx.NewContextWithTraceId(context.Background(), traceId), c)
+
+	c.Logger.Info(ctx, "Started listening commands at %s", c.RequestQueue.Name())
+	return nil
+}
+
+// Close method are stops listening the request queue.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *CommandableMessageQueueController) Close(ctx context.Context) error {
+	if !c.opened {
+		return nil
+	}
+
+	c.opened = false
+	c.RequestQueue.EndListen(ctx)
+
+	c.Logger.Info(ctx, "Stopped listening commands at %s", c.RequestQueue.Name())
+	return nil
+}
+
+// Instrument method are adds instrumentation to log calls and measure call time.
+// It returns a InstrumentTiming object that is used to end the time measurement.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name              a method name.
+//	Returns: InstrumentTiming object to end the time measurement.
+func (c *CommandableMessageQueueController) Instrument(ctx context.Context, name string) *trace.InstrumentTiming {
+	c.Logger.Trace(ctx, "Executing %s method", name)
+	c.Counters.IncrementOne(ctx, name+".exec_count")
+
+	counterTiming := c.Counters.BeginTiming(ctx, name+".exec_time")
+	traceTiming := c.Tracer.BeginTrace(ctx, name, "")
+	return trace.NewInstrumentTiming(ctx, name, "exec",
+		c.Logger, c.Counters, counterTiming, traceTiming)
+}
+
+// ReceiveMessage method are executes the command requested by the message
+// and sends the reply. The request message is completed after the reply is sent.
+//
+//	Parameters:
+//		- ctx context.Context   operation context
+//		- envelope  an incoming request message
+//		- queue     a queue where the message comes from
+//	Returns: error or nil for success.
+func (c *CommandableMessageQueueController) ReceiveMessage(ctx context.Context,
+	envelope *cqueues.MessageEnvelope, queue cqueues.IMessageQueue) error {
+
+	ctx = cctx.NewContextWithTraceId(ctx, envelope.TraceId)
+
+	result, err := c.executeCommand(ctx, envelope)
+
+	var reply *cqueues.MessageEnvelope
+	if err != nil {
+		reply = cqueues.NewMessageEnvelopeFromObject(envelope.TraceId, cqueues.CommandErrorMessageType,
+			cerr.ErrorDescriptionFactory.Create(err))
+	} else {
+		reply = cqueues.NewMessageEnvelopeFromObject(envelope.TraceId, cqueues.CommandReplyMessageType, result)
+	}
+	reply.SetHeader(cqueues.CorrelationIdHeader, envelope.MessageId)
+
+	replyQueue := c.findReplyQueue(envelope.GetHeader(cqueues.ReplyToHeader))
+	if replyQueue == nil {
+		c.Logger.Error(ctx, nil, "Reply queue for message %s is not found", envelope.MessageId)
+	} else if sendErr := replyQueue.Send(ctx, reply); sendErr != nil {
+		// Return the request into the queue to retry it later
+		_ = queue.Abandon(ctx, envelope)
+		return sendErr
+	}
+
+	return queue.Complete(ctx, envelope)
+}
+
+func (c *CommandableMessageQueueController) executeCommand(ctx context.Context,
+	envelope *cqueues.MessageEnvelope) (any, error) {
+
+	commandName := envelope.MessageType
+	if c.Name != "" {
+		if !strings.HasPrefix(commandName, c.Name+".") {
+			return nil, cerr.NewBadRequestError(envelope.TraceId, "CMD_NOT_FOUND",
+				"Request "+envelope.MessageType+" is not supported by "+c.Name).
+				WithDetails("message_type", envelope.MessageType)
+		}
+		commandName = commandName[len(c.Name)+1:]
+	}
+
+	command := c.commandSet.FindCommand(commandName)
+	if command == nil {
+		return nil, cerr.NewBadRequestError(envelope.TraceId, "CMD_NOT_FOUND",
+			"Request "+envelope.MessageType+" is not supported by "+c.Name).
+			WithDetails("message_type", envelope.MessageType)
+	}
+
+	params := make(map[string]any)
+	if len(envelope.Message) > 0 {
+		value, ok := cconv.JsonConverter.ToNullableMap(string(envelope.Message))
+		if !ok {
+			return nil, cerr.NewBadRequestError(envelope.TraceId, "INVALID_MESSAGE",
+				"Failed to read request "+envelope.MessageId)
+		}
+		params = value
+	}
+
+	timing := c.Instrument(ctx, envelope.MessageType)
+	result, err := command.Execute(ctx, cexec.NewParametersFromValue(params))
+	timing.EndTiming(ctx, err)
+	return result, err
+}
+
+func (c *CommandableMessageQueueController) findReplyQueue(name string) cqueues.IMessageQueue {
+	if name == "" || (c.ReplyQueue != nil && c.ReplyQueue.Name() == name) {
+		return c.ReplyQueue
+	}
+
+	if c.references != nil {
+		queues := c.references.GetOptional(cref.NewDescriptor("*", "message-queue", "*", "*", "*"))
+		for _, queue := range queues {
+			if queue, ok := queue.(cqueues.IMessageQueue); ok && queue.Name() == name {
+				return queue
+			}
+		}
+	}
+
+	return c.ReplyQueue
+}
@@ -0,0 +1,135 @@
+package queues
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+)
+
+// BatchMessageListener listens for incoming messages in batches and passes them to a batch receiver.
+// When the receiver succeeds the whole batch is completed at once.
+// When it fails all messages of the batch are abandoned to be received again.
+//
+//	see IMessageBatchReceiver
+//	see IMessageQueue.ReceiveBatch
+//	see IMessageQueue.CompleteBatch
+//
+//	Example:
+//		listener := NewBatchMessageListener(queue, NewMyBatchReceiver())
+//		listener.MaxCount = 500
+//
+//		listener.BeginListen(ctx)
+//		...
+//		listener.EndListen(ctx)
+type BatchMessageListener struct {
+	// The logger
+	Logger *clog.CompositeLogger
+	// The maximum number of messages in a batch
+	MaxCount int64
+	// The timeout to wait for messages to come
+	WaitTimeout time.Duration
+
+	queue    IMessageQueue
+	receiver IMessageBatchReceiver
+	cancel   int32
+}
+
+// NewBatchMessageListener method are creates a new instance of the listener.
+//
+//	Parameters:
+//		- queue     a queue to receive messages from.
+//		- receiver  a receiver to process batches of messages.
+//	Returns: *BatchMessageListener
+func NewBatchMessageListener(queue IMessageQueue, receiver IMessageBatchReceiver) *BatchMessageListener {
+	return &BatchMessageListener{
+		Logger:      clog.NewCompositeLogger(),
+		MaxCount:    100,
+		WaitTimeout: time.Duration(1000) * time.Millisecond,
+		queue:       queue,
+		receiver:    receiver,
+	}
+}
+
+// Listen method are listens for incoming messages and blocks the current thread until listening is ended.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//	Returns: error of the context or nil when listening was ended.
+func (c *BatchMessageListener) Listen(ctx context.Context) error {
+	c.Logger.Trace(ctx, "Started listening message batches at %s", c.queue.Name())
+
+	atomic.StoreInt32(&c.cancel, 0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if atomic.LoadInt32(&c.cancel) == 1 {
+			return nil
+		}
+
+		messages, err := c.queue.ReceiveBatch(ctx, c.MaxCount, c.WaitTimeout)
+		if err != nil {
+			c.Logger.Error(ctx, err, "Failed to receive messages from %s", c.queue.Name())
+			time.Sleep(c.WaitTimeout)
+			continue
+		}
+
+		if len(messages) > 0 {
+			c.processBatch(ctx, messages)
+		}
+	}
+}
+
+func (c *BatchMessageListener) processBatch(ctx context.Context, messages []*MessageEnvelope) {
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+			}
+		}()
+		return c.receiver.ReceiveMessages(ctx, messages, c.queue)
+	}()
+
+	if err != nil {
+		c.Logger.Error(ctx, err, "Failed to process %d messages from %s", len(messages), c.queue.Name())
+		for _, message := range messages {
+			if abandonErr := c.queue.Abandon(ctx, message); abandonErr != nil {
+				c.Logger.Error(ctx, abandonErr, "Failed to abandon message %s", message)
+			}
+		}
+		return
+	}
+
+	if err = c.queue.CompleteBatch(ctx, messages); err != nil {
+		c.Logger.Error(ctx, err, "Failed to complete %d messages at %s", len(messages), c.queue.Name())
+	}
+}
+
+// BeginListen method are listens for incoming messages without blocking the current thread.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+func (c *BatchMessageListener) BeginListen(ctx context.Context) {
+	go func() {
+		err := c.Listen(ctx)
+		if err != nil {
+			c.Logger.Error(ctx, err, "Failed to listen the message queue %s", c.queue.Name())
+		}
+	}()
+}
+
+// EndListen method are ends listening for incoming messages.
+// The messages of the batch that is being processed are still completed or abandoned.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+func (c *BatchMessageListener) EndListen(ctx context.Context) {
+	atomic.StoreInt32(&c.cancel, 1)
+}
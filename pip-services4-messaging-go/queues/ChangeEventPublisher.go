@@ -0,0 +1,48 @@
+package queues
+
+import (
+	"context"
+
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// ChangeEventPublisher is a change listener that sends change events of persistence components
+// to a message queue. Every event is sent as a JSON message with the change type
+// ("created", "updated" or "deleted") as the message type.
+//
+//	Example:
+//		queue := NewMemoryMessageQueue("changes")
+//		persistence.AddChangeListener(NewChangeEventPublisher(queue))
+//
+//		item, err := persistence.Create(ctx, item)
+//		...
+//		envelope, err := queue.Receive(ctx, 10000)
+//		event, err := GetMessageAs[cpersist.ChangeEvent](envelope)
+//
+//	Implements: cpersist.IChangeListener
+type ChangeEventPublisher struct {
+	queue IMessageQueue
+}
+
+// NewChangeEventPublisher creates a new publisher that sends change events to the queue.
+//
+//	Parameters:
+//		- queue IMessageQueue a queue to send the events to.
+//	Returns: *ChangeEventPublisher
+func NewChangeEventPublisher(queue IMessageQueue) *ChangeEventPublisher {
+	return &ChangeEventPublisher{
+		queue: queue,
+	}
+}
+
+// OnChange sends the change event to the queue.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- event *cpersist.ChangeEvent the change event.
+//	Returns: error or nil if the event was sent.
+func (c *ChangeEventPublisher) OnChange(ctx context.Context, event *cpersist.ChangeEvent) error {
+	envelope := NewMessageEnvelopeFromObject(cctx.GetTraceId(ctx), event.Type, event)
+	return c.queue.Send(ctx, envelope)
+}
@@ -0,0 +1,300 @@
+package queues
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+)
+
+// ConcurrentMessageListener listens for incoming messages and processes them in parallel by a pool of workers.
+// The listener receives messages only while the number of messages in flight is below the prefetch limit,
+// so slow receivers hold messages in the queue instead of piling them up in memory.
+//
+// When OrderKey is set, messages with the same key are processed one after another
+// in the order they were received, while messages with different keys are processed in parallel.
+// For Kafka the message id carries the message key, so ordering by the message id preserves
+// the order of messages within a partition.
+//
+// Receivers complete messages themselves, the same way as with IMessageQueue.Listen.
+// The listener only abandons messages when the receiver fails or times out
+// and the message was not completed, abandoned or moved to dead letter queue by the receiver.
+// On timeout the context of the receiver is cancelled and the message is abandoned after the receiver returns,
+// so the message is never redelivered while it is still processed. Receivers shall stop when the context is done.
+// EndListen stops receiving new messages and waits until messages in flight are processed.
+//
+//	Configuration parameters:
+//		- options:
+//			- workers:              (optional) number of workers to process messages (default: 4)
+//			- prefetch:             (optional) maximum number of received messages in flight (default: 2 x workers)
+//			- message_timeout:      (optional) number of milliseconds to process a message (default: 0 unlimited)
+//			- wait_timeout:         (optional) number of milliseconds to wait for a message to come (default: 1000)
+//
+//	see IMessageReceiver
+//
+//	Example:
+//		listener := NewConcurrentMessageListener(queue, NewMyMessageReceiver())
+//		listener.Configure(ctx, cconf.NewConfigParamsFromTuples(
+//			"options.workers", 8,
+//			"options.message_timeout", 30000,
+//		))
+//		listener.OrderKey = func(envelope *MessageEnvelope) string {
+//			return envelope.MessageId
+//		}
+//
+//		listener.BeginListen(ctx)
+//		...
+//		listener.EndListen(ctx)
+//		queue.Close(ctx)
+type ConcurrentMessageListener struct {
+	// The logger
+	Logger *clog.CompositeLogger
+	// The number of workers to process messages
+	Workers int
+	// The maximum number of received messages in flight. 0 to use twice the number of workers
+	Prefetch int
+	// The timeout to process a single message. 0 for unlimited
+	MessageTimeout time.Duration
+	// The timeout to wait for a message to come
+	WaitTimeout time.Duration
+	// The function to get a key to preserve order of messages. nil to process messages in any order
+	OrderKey func(envelope *MessageEnvelope) string
+
+	queue    IMessageQueue
+	receiver IMessageReceiver
+	lock     sync.Mutex
+	cancel   chan bool
+	done     chan bool
+}
+
+// NewConcurrentMessageListener method are creates a new instance of the listener.
+//
+//	Parameters:
+//		- queue     a queue to receive messages from.
+//		- receiver  a receiver to process messages.
+//	Returns: *ConcurrentMessageListener
+func NewConcurrentMessageListener(queue IMessageQueue, receiver IMessageReceiver) *ConcurrentMessageListener {
+	return &ConcurrentMessageListener{
+		Logger:      clog.NewCompositeLogger(),
+		Workers:     4,
+		WaitTimeout: time.Duration(1000) * time.Millisecond,
+		queue:       queue,
+		receiver:    receiver,
+	}
+}
+
+// Configure method are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config    configuration parameters to be set.
+func (c *ConcurrentMessageListener) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.Workers = config.GetAsIntegerWithDefault("options.workers", c.Workers)
+	c.Prefetch = config.GetAsIntegerWithDefault("options.prefetch", c.Prefetch)
+	messageTimeout := config.GetAsLongWithDefault("options.message_timeout", c.MessageTimeout.Milliseconds())
+	c.MessageTimeout = time.Duration(messageTimeout) * time.Millisecond
+	waitTimeout := config.GetAsLongWithDefault("options.wait_timeout", c.WaitTimeout.Milliseconds())
+	c.WaitTimeout = time.Duration(waitTimeout) * time.Millisecond
+}
+
+func (c *ConcurrentMessageListener) workerCount() int {
+	if c.Workers < 1 {
+		return 1
+	}
+	return c.Workers
+}
+
+func (c *ConcurrentMessageListener) prefetchLimit() int {
+	workers := c.workerCount()
+	if c.Prefetch <= 0 {
+		return 2 * workers
+	}
+	return c.Prefetch
+}
+
+// Listen method are listens for incoming messages and blocks the current thread until listening is ended.
+// Before it returns all received messages are processed.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//	Returns: error of the context or nil when listening was ended.
+func (c *ConcurrentMessageListener) Listen(ctx context.Context) error {
+	cancel, done := c.start()
+	if cancel == nil {
+		return nil
+	}
+	return c.listen(ctx, cancel, done)
+}
+
+func (c *ConcurrentMessageListener) start() (chan bool, chan bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Skip if the listener is already running
+	if c.done != nil {
+		return nil, nil
+	}
+	c.cancel = make(chan bool)
+	c.done = make(chan bool)
+	return c.cancel, c.done
+}
+
+func (c *ConcurrentMessageListener) listen(ctx context.Context, cancel chan bool, done chan bool) error {
+	defer func() {
+		c.lock.Lock()
+		c.cancel = nil
+		c.done = nil
+		c.lock.Unlock()
+		close(done)
+	}()
+
+	workers := c.workerCount()
+	prefetch := c.prefetchLimit()
+	c.Logger.Trace(ctx, "Started listening messages at %s with %d workers", c.queue.Name(), workers)
+
+	// Slots limit the number of messages in flight
+	slots := make(chan bool, prefetch)
+	channels := make([]chan *MessageEnvelope, workers)
+	var wg sync.WaitGroup
+
+	for index := range channels {
+		channels[index] = make(chan *MessageEnvelope, prefetch)
+		wg.Add(1)
+		go func(messages chan *MessageEnvelope) {
+			defer wg.Done()
+			for message := range messages {
+				c.processMessage(ctx, message)
+				<-slots
+			}
+		}(channels[index])
+	}
+
+	// Drain messages in flight before return
+	defer func() {
+		for _, messages := range channels {
+			close(messages)
+		}
+		wg.Wait()
+		c.Logger.Trace(ctx, "Stopped listening messages at %s", c.queue.Name())
+	}()
+
+	next := 0
+	for {
+		// Stop before receiving more messages
+		select {
+		case <-cancel:
+			return nil
+		default:
+		}
+
+		// Wait for a free slot
+		select {
+		case <-cancel:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case slots <- true:
+		}
+
+		message, err := c.queue.Receive(ctx, c.WaitTimeout)
+		if err != nil {
+			c.Logger.Error(ctx, err, "Failed to receive the message from %s", c.queue.Name())
+		}
+		if message == nil {
+			<-slots
+			continue
+		}
+
+		// Messages with the same key always go to the same worker
+		index := next
+		if c.OrderKey != nil {
+			hash := fnv.New32a()
+			hash.Write([]byte(c.OrderKey(message)))
+			index = int(hash.Sum32() % uint32(workers))
+		} else {
+			next = (next + 1) % workers
+		}
+		channels[index] <- message
+	}
+}
+
+func (c *ConcurrentMessageListener) processMessage(ctx context.Context, message *MessageEnvelope) {
+	traceCtx := cctx.NewContextWithTraceId(ctx, message.TraceId)
+
+	processCtx := ctx
+	if c.MessageTimeout > 0 {
+		var cancel context.CancelFunc
+		processCtx, cancel = context.WithTimeout(ctx, c.MessageTimeout)
+		defer cancel()
+	}
+
+	// Wait for the receiver to return even when it timed out
+	err := c.receiveMessage(processCtx, message)
+	if err != nil {
+		c.Logger.Error(traceCtx, err, "Failed to process the message %s", message)
+		// Skip messages already released by the receiver
+		if message.GetReference() == nil {
+			return
+		}
+		if abandonErr := c.queue.Abandon(ctx, message); abandonErr != nil {
+			c.Logger.Error(traceCtx, abandonErr, "Failed to abandon the message %s", message)
+		}
+	}
+}
+
+func (c *ConcurrentMessageListener) receiveMessage(ctx context.Context, message *MessageEnvelope) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return c.receiver.ReceiveMessage(ExtractTraceContext(ctx, message), message, c.queue)
+}
+
+// BeginListen method are listens for incoming messages without blocking the current thread.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+func (c *ConcurrentMessageListener) BeginListen(ctx context.Context) {
+	cancel, done := c.start()
+	if cancel == nil {
+		return
+	}
+
+	go func() {
+		err := c.listen(ctx, cancel, done)
+		if err != nil {
+			c.Logger.Error(ctx, err, "Failed to listen the message queue %s", c.queue.Name())
+		}
+	}()
+}
+
+// EndListen method are ends listening for incoming messages and waits until messages in flight are processed.
+// The method returns earlier when the context is done.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+func (c *ConcurrentMessageListener) EndListen(ctx context.Context) {
+	c.lock.Lock()
+	cancel := c.cancel
+	done := c.done
+	if cancel != nil {
+		close(cancel)
+		c.cancel = nil
+	}
+	c.lock.Unlock()
+
+	if done == nil {
+		return
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
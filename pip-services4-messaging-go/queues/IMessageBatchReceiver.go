@@ -0,0 +1,29 @@
+package queues
+
+import "context"
+
+// IMessageBatchReceiver callback interface to receive batches of incoming messages.
+//
+//	see BatchMessageListener
+//
+//	Example:
+//		type MyBatchReceiver struct {}
+//
+//		func (c *MyBatchReceiver) ReceiveMessages(ctx context.Context, envelopes []*MessageEnvelope, queue IMessageQueue) error {
+//			fmt.Printf("Received %d messages\n", len(envelopes))
+//			return nil
+//		}
+//
+//		listener := NewBatchMessageListener(queue, &MyBatchReceiver{})
+//		listener.BeginListen(ctx)
+type IMessageBatchReceiver interface {
+	// ReceiveMessages method are receives a batch of incoming messages from the queue.
+	//	Parameters:
+	//		- ctx context.Context   operation context
+	//		- envelopes  a list of incoming messages
+	//		- queue     a queue where the messages come from
+	//	Returns: error to abandon the batch or nil to complete it.
+	//	see: MessageEnvelope
+	//	see: IMessageQueue
+	ReceiveMessages(ctx context.Context, envelopes []*MessageEnvelope, queue IMessageQueue) (err error)
+}
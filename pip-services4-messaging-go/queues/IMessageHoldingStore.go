@@ -0,0 +1,37 @@
+package queues
+
+import (
+	"context"
+	"time"
+)
+
+// IMessageHoldingStore interface for stores that hold delayed messages until they become visible.
+// It is used by MessageDelayScheduler to emulate delayed delivery in queues
+// where message brokers do not support it natively.
+//
+//	see MemoryMessageHoldingStore
+//	see MessageDelayScheduler
+type IMessageHoldingStore interface {
+	// Hold method are saves a delayed message until its visible time.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- queue     a name of the queue where the message shall be delivered.
+	//		- envelope  a message envelope with set visible time.
+	//	Returns: error or nil for success.
+	Hold(ctx context.Context, queue string, envelope *MessageEnvelope) error
+
+	// TakeDue method are removes and returns messages which visible time has come.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- queue     a name of the queue.
+	//		- now       the current time.
+	//	Returns: messages ordered by their visible time or error.
+	TakeDue(ctx context.Context, queue string, now time.Time) ([]*MessageEnvelope, error)
+
+	// Clear method are removes all messages held for the queue.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- queue     a name of the queue.
+	//	Returns: error or nil for success.
+	Clear(ctx context.Context, queue string) error
+}
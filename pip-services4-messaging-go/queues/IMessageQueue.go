@@ -37,6 +37,17 @@ type IMessageQueue interface {
 	// Returns: error or nil for success.
 	Send(ctx context.Context, envelope *MessageEnvelope) error
 
+	// SendWithOptions method are sends a message into the queue with delivery options.
+	// Delayed messages stay invisible to receivers until the delay expires or the scheduled time comes.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- envelope				a message envelop to be sent.
+	//		- options				delivery options like delay or scheduled time.
+	//	Returns: error or nil for success.
+	//	see SendOptions
+	//	see MessagingCapabilities.CanDelay
+	SendWithOptions(ctx context.Context, envelope *MessageEnvelope, options *SendOptions) error
+
 	// SendAsObject method are sends an object into the queue.
 	// Before sending the object is converted into JSON string and wrapped in a MessageEnvelop.
 	//	Parameters:
@@ -47,6 +58,14 @@ type IMessageQueue interface {
 	//	see Send
 	SendAsObject(ctx context.Context, messageType string, value any) error
 
+	// SendBatch method are sends multiple messages into the queue at once.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- envelopes				a list of message envelops to be sent.
+	//	Returns: error or nil for success.
+	//	see Send
+	SendBatch(ctx context.Context, envelopes []*MessageEnvelope) error
+
 	// Peek method are peeks a single incoming message from the queue without removing it.
 	// If there are no messages available in the queue it returns nil.
 	//	Parameters:
@@ -69,6 +88,16 @@ type IMessageQueue interface {
 	//	Returns: a message or error.
 	Receive(ctx context.Context, waitTimeout time.Duration) (result *MessageEnvelope, err error)
 
+	// ReceiveBatch method are receives multiple incoming messages and removes them from the queue.
+	// The method waits for the first message to come and then returns all available messages up to the maximum count.
+	// If no messages come within the timeout it returns an empty list.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- maxCount          	a maximum number of messages to receive.
+	//		- waitTimeout       	a timeout in milliseconds to wait for messages to come.
+	//	Returns: list with messages or error.
+	ReceiveBatch(ctx context.Context, maxCount int64, waitTimeout time.Duration) (result []*MessageEnvelope, err error)
+
 	// RenewLock method are renews a lock on a message that makes it invisible from other receivers in the queue.
 	// This method is usually used to extend the message processing time.
 	//	Parameters:
@@ -86,6 +115,14 @@ type IMessageQueue interface {
 	//	Returns: error or nil for success.
 	Complete(ctx context.Context, message *MessageEnvelope) error
 
+	// CompleteBatch method are permanently removes multiple messages from the queue at once.
+	// This method is usually used to remove messages received by ReceiveBatch after successful processing.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- messages   			a list of messages to remove.
+	//	Returns: error or nil for success.
+	CompleteBatch(ctx context.Context, messages []*MessageEnvelope) error
+
 	// Abandon method are returns message into the queue and makes it available for all subscribers to receive it again.
 	// This method is usually used to return a message which could not be processed at the moment
 	// to repeat the attempt. Messages that cause unrecoverable errors shall be removed permanently
@@ -0,0 +1,53 @@
+package queues
+
+import (
+	"context"
+)
+
+// IProcessedMessageStore interface for stores that record ids of processed messages.
+// It is used by IdempotentMessageReceiver to skip redelivered messages.
+// Stores keep the ids for a retention period which shall be longer than
+// the time brokers may redeliver messages.
+//
+// Messages are claimed before they are processed, so only one receiver processes a message at a time.
+// Claims expire after a claim timeout to let other receivers process messages of crashed receivers.
+//
+//	see MemoryProcessedMessageStore
+//	see IdempotentMessageReceiver
+type IProcessedMessageStore interface {
+	// IsProcessed method are checks if the message was already processed.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- messageId     a unique id of the message.
+	//	Returns: true if the message was processed within the retention period or error.
+	IsProcessed(ctx context.Context, messageId string) (bool, error)
+
+	// TryClaim method are atomically claims the message for processing
+	// unless it was already processed or claimed by another receiver.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- messageId     a unique id of the message.
+	//	Returns: true if the message was claimed or false if it was processed or claimed before, or error.
+	TryClaim(ctx context.Context, messageId string) (bool, error)
+
+	// ReleaseClaim method are removes the claim of the message that failed to be processed,
+	// so the message can be processed again when it is redelivered.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- messageId     a unique id of the message.
+	//	Returns: error or nil for success.
+	ReleaseClaim(ctx context.Context, messageId string) error
+
+	// MarkProcessed method are records the message as processed.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- messageId     a unique id of the message.
+	//	Returns: error or nil for success.
+	MarkProcessed(ctx context.Context, messageId string) error
+
+	// Cleanup method are removes ids of messages processed before the retention period.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//	Returns: error or nil for success.
+	Cleanup(ctx context.Context) error
+}
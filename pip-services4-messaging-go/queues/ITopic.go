@@ -0,0 +1,75 @@
+package queues
+
+import (
+	"context"
+
+	crun "github.com/pip-services4/pip-services4-go/pip-services4-components-go/run"
+)
+
+// ITopic interface for publish/subscribe topics.
+//
+// Unlike message queues where every message is delivered to a single receiver,
+// messages published to a topic are delivered to every subscription.
+// Subscriptions are named. Receivers that subscribe with the same name share the subscription
+// and compete for its messages, so every message is processed once per subscription.
+//
+// Topic names may contain wildcards to subscribe to multiple topics at once when the broker
+// supports them. Names and wildcards follow the syntax of the broker. Messages can not be published
+// to topics with wildcards.
+//
+//	see ISubscription
+//	see MemoryTopic
+//
+//	Example:
+//		topic := NewMemoryTopic("orders")
+//		topic.Open(ctx)
+//
+//		billing, err := topic.Subscribe(ctx, "billing")
+//		shipping, err := topic.Subscribe(ctx, "shipping")
+//
+//		err = topic.Publish(ctx, NewMessageEnvelope("", "created", []byte("ABC")))
+//
+//		message1, err := billing.Receive(ctx, 10000*time.Millisecond)	// Receives the message
+//		message2, err := shipping.Receive(ctx, 10000*time.Millisecond)	// Receives the same message
+type ITopic interface {
+	crun.IOpenable
+
+	// Name method are gets the topic name.
+	//	Returns: the topic name.
+	Name() string
+
+	// Publish method are sends a message to all subscriptions of the topic.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- envelope  a message envelop to be published.
+	//	Returns: error or nil for success.
+	Publish(ctx context.Context, envelope *MessageEnvelope) error
+
+	// Subscribe method are creates or joins a named subscription to the topic.
+	// The subscription is opened and starts receiving messages right away.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- subscription  a name of the subscription.
+	//	Returns: the subscription or error.
+	Subscribe(ctx context.Context, subscription string) (ISubscription, error)
+}
+
+// ISubscription interface for subscriptions to publish/subscribe topics.
+// Subscriptions are message queues that receive messages published to the topic.
+// The queue name is the subscription name.
+//
+//	see ITopic
+//	see IMessageQueue
+type ISubscription interface {
+	IMessageQueue
+
+	// Topic method are gets the name of the subscribed topic.
+	//	Returns: the topic name that may contain wildcards.
+	Topic() string
+
+	// Unsubscribe method are stops receiving messages and closes the subscription.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//	Returns: error or nil for success.
+	Unsubscribe(ctx context.Context) error
+}
@@ -0,0 +1,95 @@
+package queues
+
+import (
+	"context"
+
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+)
+
+// IdempotentMessageReceiver wraps a message receiver to process every message only once.
+// Message queues deliver messages at least once, so the same message can be received again
+// after failures or redeliveries. The receiver records ids of processed messages in a store
+// and completes redelivered messages without passing them to the wrapped receiver.
+//
+// A message is claimed in the store before it is passed to the wrapped receiver,
+// so concurrent receivers never process the same message at the same time.
+// The message is recorded as processed after the wrapped receiver succeeds,
+// and the claim is released when it fails so the message can be processed again.
+// Duplicates that arrive while the message is still processed are abandoned to be redelivered later.
+// Messages without ids are always passed to the wrapped receiver.
+//
+//	see IProcessedMessageStore
+//	see IMessageReceiver
+//
+//	Example:
+//		store := NewMemoryProcessedMessageStore()
+//		receiver := NewIdempotentMessageReceiver(NewMyMessageReceiver(), store)
+//
+//		queue.BeginListen(ctx, receiver)
+//
+//	Implements: IMessageReceiver
+type IdempotentMessageReceiver struct {
+	// The logger
+	Logger *clog.CompositeLogger
+
+	receiver IMessageReceiver
+	store    IProcessedMessageStore
+}
+
+// NewIdempotentMessageReceiver method are creates a new instance of the receiver.
+//
+//	Parameters:
+//		- receiver  a receiver to process messages.
+//		- store     a store to record ids of processed messages.
+//	Returns: *IdempotentMessageReceiver
+func NewIdempotentMessageReceiver(receiver IMessageReceiver, store IProcessedMessageStore) *IdempotentMessageReceiver {
+	return &IdempotentMessageReceiver{
+		Logger:   clog.NewCompositeLogger(),
+		receiver: receiver,
+		store:    store,
+	}
+}
+
+// ReceiveMessage method are passes the message to the wrapped receiver unless it was already processed.
+//
+//	Parameters:
+//		- ctx context.Context   operation context
+//		- envelope  an incoming message
+//		- queue     a queue where the message comes from
+//	Returns: error of the wrapped receiver or the store.
+func (c *IdempotentMessageReceiver) ReceiveMessage(ctx context.Context, envelope *MessageEnvelope, queue IMessageQueue) error {
+	messageId := envelope.MessageId
+	if messageId == "" {
+		return c.receiver.ReceiveMessage(ctx, envelope, queue)
+	}
+
+	traceCtx := cctx.NewContextWithTraceId(ctx, envelope.TraceId)
+
+	claimed, err := c.store.TryClaim(ctx, messageId)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		processed, err := c.store.IsProcessed(ctx, messageId)
+		if err != nil {
+			return err
+		}
+		if processed {
+			c.Logger.Debug(traceCtx, "Skipped duplicate message %s at %s", envelope, queue.Name())
+			return queue.Complete(ctx, envelope)
+		}
+		c.Logger.Debug(traceCtx, "Message %s is being processed, abandoned duplicate at %s", envelope, queue.Name())
+		return queue.Abandon(ctx, envelope)
+	}
+
+	err = c.receiver.ReceiveMessage(ctx, envelope, queue)
+	if err != nil {
+		if releaseErr := c.store.ReleaseClaim(ctx, messageId); releaseErr != nil {
+			c.Logger.Error(traceCtx, releaseErr, "Failed to release claim of message %s at %s", envelope, queue.Name())
+		}
+		return err
+	}
+
+	return c.store.MarkProcessed(ctx, messageId)
+}
@@ -0,0 +1,86 @@
+package queues
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryMessageHoldingStore holds delayed messages in memory.
+// Held messages are lost when the process is restarted.
+//
+//	see IMessageHoldingStore
+type MemoryMessageHoldingStore struct {
+	lock     sync.Mutex
+	messages map[string][]*MessageEnvelope
+}
+
+// NewMemoryMessageHoldingStore method are creates a new instance of the store.
+//
+//	Returns: *MemoryMessageHoldingStore
+func NewMemoryMessageHoldingStore() *MemoryMessageHoldingStore {
+	return &MemoryMessageHoldingStore{
+		messages: make(map[string][]*MessageEnvelope),
+	}
+}
+
+// Hold method are saves a delayed message until its visible time.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- queue     a name of the queue where the message shall be delivered.
+//		- envelope  a message envelope with set visible time.
+//	Returns: error or nil for success.
+func (c *MemoryMessageHoldingStore) Hold(ctx context.Context, queue string, envelope *MessageEnvelope) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	messages := append(c.messages[queue], envelope)
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].VisibleTime.Before(messages[j].VisibleTime)
+	})
+	c.messages[queue] = messages
+	return nil
+}
+
+// TakeDue method are removes and returns messages which visible time has come.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- queue     a name of the queue.
+//		- now       the current time.
+//	Returns: messages ordered by their visible time or error.
+func (c *MemoryMessageHoldingStore) TakeDue(ctx context.Context, queue string, now time.Time) ([]*MessageEnvelope, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	messages := c.messages[queue]
+	count := 0
+	for count < len(messages) && messages[count].IsVisible(now) {
+		count++
+	}
+
+	result := make([]*MessageEnvelope, count)
+	copy(result, messages[:count])
+	if count == len(messages) {
+		delete(c.messages, queue)
+	} else {
+		c.messages[queue] = messages[count:]
+	}
+	return result, nil
+}
+
+// Clear method are removes all messages held for the queue.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- queue     a name of the queue.
+//	Returns: error or nil for success.
+func (c *MemoryMessageHoldingStore) Clear(ctx context.Context, queue string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.messages, queue)
+	return nil
+}
@@ -14,6 +14,8 @@ import (
 //
 //	Configuration parameters:
 //		- name: name of the message queue
+//		- options:
+//			- max_delivery_count: (optional) maximum number of deliveries before a message is moved to DeadLetterQueue (default: 0 unlimited)
 //	References:
 //		- *:logger:*:*:1.0           (optional)  ILogger components to pass log messages
 //		- *:counters:*:*:1.0         (optional)  ICounters components to pass collected measurements
@@ -48,7 +50,7 @@ func NewMemoryMessageQueue(name string) *MemoryMessageQueue {
 	c := MemoryMessageQueue{}
 
 	c.MessageQueue = *InheritMessageQueue(
-		&c, name, NewMessagingCapabilities(true, true, true, true, true, true, true, false, true),
+		&c, name, NewMessagingCapabilities(true, true, true, true, true, true, true, true, true).WithDelay(true),
 	)
 
 	c.messages = make([]*MessageEnvelope, 0)
@@ -113,17 +115,35 @@ func (c *MemoryMessageQueue) Clear(ctx context.Context) (err error) {
 }
 
 // ReadMessageCount method are reads the current number of messages in the queue to be delivered.
+// Delayed messages are not counted until they become visible.
 //
 //	Returns: number of messages or error.
 func (c *MemoryMessageQueue) ReadMessageCount() (count int64, err error) {
 	c.Lock.Lock()
 	defer c.Lock.Unlock()
 
-	count = (int64)(len(c.messages))
+	now := time.Now()
+	for _, message := range c.messages {
+		if message.IsVisible(now) {
+			count++
+		}
+	}
 	return count, nil
 }
 
+// nextVisible returns an index of the first message that can be delivered or -1 if there is none.
+// The caller shall hold the lock.
+func (c *MemoryMessageQueue) nextVisible(now time.Time) int {
+	for index, message := range c.messages {
+		if message.IsVisible(now) {
+			return index
+		}
+	}
+	return -1
+}
+
 // Send method are sends a message into the queue.
+// If the envelope has a visible time the message is held in the queue until that time.
 //
 //	Parameters:
 //		- ctx context.Context execution context to trace execution through call chain.
@@ -131,6 +151,8 @@ func (c *MemoryMessageQueue) ReadMessageCount() (count int64, err error) {
 //	Returns: error or nil for success.
 func (c *MemoryMessageQueue) Send(ctx context.Context, envelope *MessageEnvelope) (err error) {
 	envelope.SentTime = time.Now()
+	// Propagate W3C trace context and baggage to receivers
+	InjectTraceContext(ctx, envelope)
 
 	// Add message to the queue
 	c.Lock.Lock()
@@ -144,6 +166,30 @@ func (c *MemoryMessageQueue) Send(ctx context.Context, envelope *MessageEnvelope
 	return nil
 }
 
+// SendBatch method are sends multiple messages into the queue at once.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- envelopes          	a list of message envelops to be sent.
+//	Returns: error or nil for success.
+func (c *MemoryMessageQueue) SendBatch(ctx context.Context, envelopes []*MessageEnvelope) (err error) {
+	now := time.Now()
+
+	// Add messages to the queue
+	c.Lock.Lock()
+	for _, envelope := range envelopes {
+		envelope.SentTime = now
+		InjectTraceContext(ctx, envelope)
+		c.messages = append(c.messages, envelope)
+	}
+	c.Lock.Unlock()
+
+	c.Counters.Increment(ctx, "queue."+c.Name()+".sent_messages", int64(len(envelopes)))
+	c.Logger.Debug(ctx, "Sent %d messages via %s", len(envelopes), c.Name())
+
+	return nil
+}
+
 // Peek meethod are peeks a single incoming message from the queue without removing it.
 // If there are no messages available in the queue it returns nil.
 //
@@ -155,8 +201,8 @@ func (c *MemoryMessageQueue) Peek(ctx context.Context) (result *MessageEnvelope,
 
 	// Pick a message
 	c.Lock.Lock()
-	if len(c.messages) > 0 {
-		message = c.messages[0]
+	if index := c.nextVisible(time.Now()); index >= 0 {
+		message = c.messages[index]
 	}
 	c.Lock.Unlock()
 
@@ -176,16 +222,20 @@ func (c *MemoryMessageQueue) Peek(ctx context.Context) (result *MessageEnvelope,
 //		- messageCount      	a maximum number of messages to peek.
 //	Returns: a list with messages or error.
 func (c *MemoryMessageQueue) PeekBatch(ctx context.Context, messageCount int64) (result []*MessageEnvelope, err error) {
+	messages := []*MessageEnvelope{}
+
 	c.Lock.Lock()
-	batchMessages := c.messages
-	if messageCount <= (int64)(len(batchMessages)) {
-		batchMessages = batchMessages[0:messageCount]
+	now := time.Now()
+	for _, message := range c.messages {
+		if (int64)(len(messages)) >= messageCount {
+			break
+		}
+		if message.IsVisible(now) {
+			messages = append(messages, message)
+		}
 	}
 	c.Lock.Unlock()
 
-	messages := []*MessageEnvelope{}
-	messages = append(messages, batchMessages...)
-
 	c.Logger.Trace(ctx, "Peeked %d messages on %s", len(messages), c.Name())
 
 	return messages, nil
@@ -198,22 +248,72 @@ func (c *MemoryMessageQueue) PeekBatch(ctx context.Context, messageCount int64)
 //		- waitTimeout       	a timeout in milliseconds to wait for a message to come.
 //	Returns: a message or error.
 func (c *MemoryMessageQueue) Receive(ctx context.Context, waitTimeout time.Duration) (*MessageEnvelope, error) {
-	messageReceived := false
-	var message *MessageEnvelope
+	messages := c.receiveMessages(ctx, 1, waitTimeout)
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	return messages[0], nil
+}
+
+// ReceiveBatch method are receives multiple incoming messages and removes them from the queue.
+// The method waits for the first message to come and then takes all visible messages up to the maximum count.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- maxCount          	a maximum number of messages to receive.
+//		- waitTimeout       	a timeout in milliseconds to wait for messages to come.
+//	Returns: a list with messages or error.
+func (c *MemoryMessageQueue) ReceiveBatch(ctx context.Context, maxCount int64, waitTimeout time.Duration) ([]*MessageEnvelope, error) {
+	return c.receiveMessages(ctx, maxCount, waitTimeout), nil
+}
+
+func (c *MemoryMessageQueue) receiveMessages(ctx context.Context, maxCount int64, waitTimeout time.Duration) []*MessageEnvelope {
+	messages := make([]*MessageEnvelope, 0)
 	elapsedTime := time.Duration(0)
 
-	for elapsedTime < waitTimeout && !messageReceived {
+	for elapsedTime < waitTimeout && len(messages) == 0 {
 		c.Lock.Lock()
-		if len(c.messages) == 0 {
-			c.Lock.Unlock()
+		locked := c.lockMessages(maxCount, waitTimeout)
+		c.Lock.Unlock()
+
+		if len(locked) == 0 {
 			time.Sleep(time.Duration(100) * time.Millisecond)
-			elapsedTime += time.Duration(100)
+			elapsedTime += time.Duration(100) * time.Millisecond
 			continue
 		}
 
+		// Move poison messages to dead letter queue and wait for the next ones
+		for _, message := range locked {
+			if !c.MoveToDeadLetterIfExceeded(ctx, message) {
+				messages = append(messages, message)
+			}
+		}
+	}
+
+	for _, message := range messages {
+		c.Counters.IncrementOne(ctx, "queue."+c.Name()+".received_messages")
+		c.Logger.Debug(cctx.NewContextWithTraceId(ctx, message.TraceId), "Received message %s via %s", message, c.Name())
+	}
+
+	return messages
+}
+
+// lockMessages takes visible messages from the queue and adds them to locked messages.
+// The caller must hold the queue lock.
+func (c *MemoryMessageQueue) lockMessages(maxCount int64, lockTimeout time.Duration) []*MessageEnvelope {
+	messages := make([]*MessageEnvelope, 0)
+	now := time.Now()
+
+	for int64(len(messages)) < maxCount {
+		index := c.nextVisible(now)
+		if index < 0 {
+			break
+		}
+
 		// Get message from the queue
-		message = c.messages[0]
-		c.messages = c.messages[1:]
+		message := c.messages[index]
+		c.messages = append(c.messages[:index], c.messages[index+1:]...)
+		message.DeliveryCount++
 
 		// Generate and set locked token
 		lockedToken := c.lockTokenSequence
@@ -221,25 +321,16 @@ func (c *MemoryMessageQueue) Receive(ctx context.Context, waitTimeout time.Durat
 		message.SetReference(lockedToken)
 
 		// Add messages to locked messages list
-		now := time.Now().Add(waitTimeout)
-		lockedMessage := &LockedMessage{
-			ExpirationTime: now,
+		c.lockedMessages[lockedToken] = &LockedMessage{
+			ExpirationTime: now.Add(lockTimeout),
 			Message:        message,
-			Timeout:        waitTimeout,
+			Timeout:        lockTimeout,
 		}
-		c.lockedMessages[lockedToken] = lockedMessage
 
-		messageReceived = true
-		c.Lock.Unlock()
-	}
-
-	if message != nil {
-		c.Counters.IncrementOne(ctx, "queue."+c.Name()+".received_messages")
-		ctx = cctx.NewContextWithTraceId(ctx, message.TraceId)
-		c.Logger.Debug(ctx, "Received message %s via %s", message, c.Name())
+		messages = append(messages, message)
 	}
 
-	return message, nil
+	return messages
 }
 
 // RenewLock method are renews a lock on a message that makes it invisible from other receivers in the queue.
@@ -300,6 +391,30 @@ func (c *MemoryMessageQueue) Complete(ctx context.Context, message *MessageEnvel
 	return nil
 }
 
+// CompleteBatch method are permanently removes multiple messages from the queue at once.
+//
+//	Parameters:
+//		- ctx context.Context	operation context
+//		- messages  			a list of messages to remove.
+//	Returns: error or nil for success.
+func (c *MemoryMessageQueue) CompleteBatch(ctx context.Context, messages []*MessageEnvelope) (err error) {
+	count := 0
+
+	c.Lock.Lock()
+	for _, message := range messages {
+		if lockedToken, ok := message.GetReference().(int); ok {
+			delete(c.lockedMessages, lockedToken)
+			message.SetReference(nil)
+			count++
+		}
+	}
+	c.Lock.Unlock()
+
+	c.Logger.Trace(ctx, "Completed %d messages at %s", count, c.Name())
+
+	return nil
+}
+
 // Abandon method are returns message into the queue and makes it available for all subscribers to receive it again.
 // This method is usually used to return a message which could not be processed at the moment
 // to repeat the attempt. Messages that cause unrecoverable errors shall be removed permanently
@@ -342,6 +457,7 @@ func (c *MemoryMessageQueue) Abandon(ctx context.Context, message *MessageEnvelo
 }
 
 // MoveToDeadLetter method are permanently removes a message from the queue and sends it to dead letter queue.
+// The message is sent to DeadLetterQueue when it is set, otherwise it is dropped.
 //
 //	Parameters:
 //		- ctx context.Context	operation context
@@ -360,6 +476,11 @@ func (c *MemoryMessageQueue) MoveToDeadLetter(ctx context.Context, message *Mess
 	}
 	c.Lock.Unlock()
 
+	_, err = c.SendToDeadLetterQueue(ctx, message)
+	if err != nil {
+		return err
+	}
+
 	c.Counters.IncrementOne(ctx, "queue."+c.Name()+".dead_messages")
 	ctx = cctx.NewContextWithTraceId(ctx, message.TraceId)
 	c.Logger.Trace(ctx, "Moved to dead message %s at %s", message, c.Name())
@@ -411,7 +532,7 @@ func (c *MemoryMessageQueue) Listen(ctx context.Context, receiver IMessageReceiv
 							}
 						}()
 
-						err = receiver.ReceiveMessage(ctx, message, c)
+						err = receiver.ReceiveMessage(ExtractTraceContext(ctx, message), message, c)
 						if err != nil {
 							c.Logger.Error(ctx, err, "Failed to process the message")
 						}
@@ -0,0 +1,159 @@
+package queues
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+)
+
+// MemoryProcessedMessageStore records ids of processed messages in memory.
+// Expired ids are removed at most once a minute when new messages are recorded.
+// The ids are lost when the process is restarted.
+//
+//	Configuration parameters:
+//		- options:
+//			- retention:     (optional) number of milliseconds to keep processed message ids (default: 86400000)
+//			- claim_timeout: (optional) number of milliseconds to keep claims of messages in processing (default: 300000)
+//
+//	see IProcessedMessageStore
+type MemoryProcessedMessageStore struct {
+	// The period of time to keep processed message ids
+	Retention time.Duration
+	// The period of time to keep claims of messages in processing
+	ClaimTimeout time.Duration
+
+	lock        sync.Mutex
+	processed   map[string]processedMessage
+	lastCleanup time.Time
+}
+
+// processedMessage is a record of processed or claimed message.
+type processedMessage struct {
+	time    time.Time
+	claimed bool
+}
+
+// NewMemoryProcessedMessageStore method are creates a new instance of the store.
+//
+//	Returns: *MemoryProcessedMessageStore
+func NewMemoryProcessedMessageStore() *MemoryProcessedMessageStore {
+	return &MemoryProcessedMessageStore{
+		Retention:    time.Duration(24) * time.Hour,
+		ClaimTimeout: time.Duration(5) * time.Minute,
+		processed:    make(map[string]processedMessage),
+	}
+}
+
+// Configure method are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- config    configuration parameters to be set.
+func (c *MemoryProcessedMessageStore) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	retention := config.GetAsLongWithDefault("options.retention", c.Retention.Milliseconds())
+	c.Retention = time.Duration(retention) * time.Millisecond
+	claimTimeout := config.GetAsLongWithDefault("options.claim_timeout", c.ClaimTimeout.Milliseconds())
+	c.ClaimTimeout = time.Duration(claimTimeout) * time.Millisecond
+}
+
+// IsProcessed method are checks if the message was already processed.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- messageId     a unique id of the message.
+//	Returns: true if the message was processed within the retention period or error.
+func (c *MemoryProcessedMessageStore) IsProcessed(ctx context.Context, messageId string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	record, ok := c.processed[messageId]
+	return ok && !record.claimed && time.Since(record.time) < c.Retention, nil
+}
+
+// TryClaim method are atomically claims the message for processing
+// unless it was already processed or claimed by another receiver.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- messageId     a unique id of the message.
+//	Returns: true if the message was claimed or false if it was processed or claimed before, or error.
+func (c *MemoryProcessedMessageStore) TryClaim(ctx context.Context, messageId string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if record, ok := c.processed[messageId]; ok && !c.isExpired(record) {
+		return false, nil
+	}
+
+	if time.Since(c.lastCleanup) > time.Minute {
+		c.cleanup()
+	}
+	c.processed[messageId] = processedMessage{time: time.Now(), claimed: true}
+	return true, nil
+}
+
+// ReleaseClaim method are removes the claim of the message that failed to be processed,
+// so the message can be processed again when it is redelivered.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- messageId     a unique id of the message.
+//	Returns: error or nil for success.
+func (c *MemoryProcessedMessageStore) ReleaseClaim(ctx context.Context, messageId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if record, ok := c.processed[messageId]; ok && record.claimed {
+		delete(c.processed, messageId)
+	}
+	return nil
+}
+
+// MarkProcessed method are records the message as processed.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- messageId     a unique id of the message.
+//	Returns: error or nil for success.
+func (c *MemoryProcessedMessageStore) MarkProcessed(ctx context.Context, messageId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if time.Since(c.lastCleanup) > time.Minute {
+		c.cleanup()
+	}
+	c.processed[messageId] = processedMessage{time: time.Now()}
+	return nil
+}
+
+// Cleanup method are removes ids of messages processed before the retention period
+// and expired claims.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//	Returns: error or nil for success.
+func (c *MemoryProcessedMessageStore) Cleanup(ctx context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.cleanup()
+	return nil
+}
+
+func (c *MemoryProcessedMessageStore) isExpired(record processedMessage) bool {
+	if record.claimed {
+		return time.Since(record.time) >= c.ClaimTimeout
+	}
+	return time.Since(record.time) >= c.Retention
+}
+
+func (c *MemoryProcessedMessageStore) cleanup() {
+	c.lastCleanup = time.Now()
+	for messageId, record := range c.processed {
+		if c.isExpired(record) {
+			delete(c.processed, messageId)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package queues
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+)
+
+// MemoryTopicBroker keeps in-memory subscriptions of topics and delivers published messages to them.
+// Topics created by the same broker can subscribe to each other using wildcards.
+//
+//	see MemoryTopic
+type MemoryTopicBroker struct {
+	lock          sync.Mutex
+	subscriptions []*memorySubscription
+}
+
+type memorySubscription struct {
+	pattern string
+	queue   *MemoryMessageQueue
+}
+
+// NewMemoryTopicBroker method are creates a new instance of the broker.
+//
+//	Returns: *MemoryTopicBroker
+func NewMemoryTopicBroker() *MemoryTopicBroker {
+	return &MemoryTopicBroker{
+		subscriptions: make([]*memorySubscription, 0),
+	}
+}
+
+// Topic method are creates a topic that publishes messages via this broker.
+//
+//	Parameters:
+//		- name      a name of the topic.
+//	Returns: *MemoryTopic
+func (c *MemoryTopicBroker) Topic(name string) *MemoryTopic {
+	return &MemoryTopic{
+		name:   name,
+		broker: c,
+	}
+}
+
+func (c *MemoryTopicBroker) publish(ctx context.Context, topic string, envelope *MessageEnvelope) error {
+	c.lock.Lock()
+	queues := make([]*MemoryMessageQueue, 0)
+	for _, subscription := range c.subscriptions {
+		if MatchTopic(subscription.pattern, topic) {
+			queues = append(queues, subscription.queue)
+		}
+	}
+	c.lock.Unlock()
+
+	for _, queue := range queues {
+		if err := queue.Send(ctx, envelope.Clone()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *MemoryTopicBroker) subscribe(ctx context.Context, pattern string, name string) (*MemoryMessageQueue, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, subscription := range c.subscriptions {
+		if subscription.pattern == pattern && subscription.queue.Name() == name {
+			return subscription.queue, nil
+		}
+	}
+
+	queue := NewMemoryMessageQueue(name)
+	if err := queue.Open(ctx); err != nil {
+		return nil, err
+	}
+	c.subscriptions = append(c.subscriptions, &memorySubscription{
+		pattern: pattern,
+		queue:   queue,
+	})
+	return queue, nil
+}
+
+func (c *MemoryTopicBroker) unsubscribe(ctx context.Context, queue *MemoryMessageQueue) error {
+	c.lock.Lock()
+	for index, subscription := range c.subscriptions {
+		if subscription.queue == queue {
+			c.subscriptions = append(c.subscriptions[:index:index], c.subscriptions[index+1:]...)
+			break
+		}
+	}
+	c.lock.Unlock()
+
+	return queue.Close(ctx)
+}
+
+// MemoryTopic is a publish/subscribe topic that delivers messages in memory.
+// Every subscription keeps its messages in a MemoryMessageQueue.
+//
+// Topic names consist of segments separated by dots. Subscriptions support wildcards:
+// "*" matches exactly one segment, "#" matches zero or more segments
+// and ">" matches one or more segments at the end of the name.
+// Wildcards work between topics created by the same MemoryTopicBroker.
+//
+//	see ITopic
+//	see MemoryTopicBroker
+//	see MatchTopic
+//
+//	Example:
+//		broker := NewMemoryTopicBroker()
+//		created := broker.Topic("orders.created")
+//		all := broker.Topic("orders.*")
+//
+//		audit, err := all.Subscribe(ctx, "audit")
+//		err = created.Publish(ctx, NewMessageEnvelope("", "created", []byte("ABC")))
+//
+//		message, err := audit.Receive(ctx, 10000*time.Millisecond)	// Receives the message
+type MemoryTopic struct {
+	name   string
+	broker *MemoryTopicBroker
+	opened bool
+}
+
+// NewMemoryTopic method are creates a new instance of the topic with its own broker.
+//
+//	Parameters:
+//		- name      a name of the topic.
+//	Returns: *MemoryTopic
+func NewMemoryTopic(name string) *MemoryTopic {
+	return NewMemoryTopicBroker().Topic(name)
+}
+
+// Name method are gets the topic name.
+//
+//	Returns: the topic name.
+func (c *MemoryTopic) Name() string {
+	return c.name
+}
+
+// IsOpen method are checks if the component is opened.
+//
+//	Returns: true if the component has been opened and false otherwise.
+func (c *MemoryTopic) IsOpen() bool {
+	return c.opened
+}
+
+// Open method are opens the component.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *MemoryTopic) Open(ctx context.Context) error {
+	c.opened = true
+	return nil
+}
+
+// Close method are closes component and frees used resources.
+// Subscriptions stay in the broker until they are unsubscribed.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *MemoryTopic) Close(ctx context.Context) error {
+	c.opened = false
+	return nil
+}
+
+// Publish method are sends a message to all subscriptions which topics match the topic name.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- envelope  a message envelop to be published.
+//	Returns: error or nil for success.
+func (c *MemoryTopic) Publish(ctx context.Context, envelope *MessageEnvelope) error {
+	if IsTopicPattern(c.name) {
+		return cerr.NewBadRequestError(cctx.GetTraceId(ctx), "WILDCARD_TOPIC",
+			"Messages can not be published to topic "+c.name+" with wildcards")
+	}
+	return c.broker.publish(ctx, c.name, envelope)
+}
+
+// Subscribe method are creates or joins a named subscription to the topic.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- subscription  a name of the subscription.
+//	Returns: the subscription or error.
+func (c *MemoryTopic) Subscribe(ctx context.Context, subscription string) (ISubscription, error) {
+	queue, err := c.broker.subscribe(ctx, c.name, subscription)
+	if err != nil {
+		return nil, err
+	}
+	return NewTopicSubscription(queue, c.name, func(ctx context.Context) error {
+		return c.broker.unsubscribe(ctx, queue)
+	}), nil
+}
+
+// IsTopicPattern checks if the topic name contains "*", "#" or ">" wildcards.
+// The wildcards cover topic patterns of AMQP, NATS and in-memory topics.
+//
+//	Parameters:
+//		- topic     a topic name.
+//	Returns: true if the name is a pattern.
+func IsTopicPattern(topic string) bool {
+	return strings.ContainsAny(topic, "*#>")
+}
+
+// MatchTopic checks if the topic name matches the pattern. Names consist of segments separated by dots.
+// In patterns "*" matches exactly one segment, "#" matches zero or more segments
+// and ">" matches one or more segments at the end of the name.
+//
+//	Parameters:
+//		- pattern   a topic pattern.
+//		- topic     a topic name.
+//	Returns: true if the name matches the pattern.
+func MatchTopic(pattern string, topic string) bool {
+	return matchTopicSegments(strings.Split(pattern, "."), strings.Split(topic, "."))
+}
+
+func matchTopicSegments(pattern []string, topic []string) bool {
+	if len(pattern) == 0 {
+		return len(topic) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		for index := 0; index <= len(topic); index++ {
+			if matchTopicSegments(pattern[1:], topic[index:]) {
+				return true
+			}
+		}
+		return false
+	case ">":
+		return len(pattern) == 1 && len(topic) > 0
+	case "*":
+		return len(topic) > 0 && matchTopicSegments(pattern[1:], topic[1:])
+	default:
+		return len(topic) > 0 && pattern[0] == topic[0] && matchTopicSegments(pattern[1:], topic[1:])
+	}
+}
@@ -0,0 +1,155 @@
+package queues
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+)
+
+// MessageDelayScheduler emulates delayed delivery for message queues which brokers do not support it.
+// Delayed messages are kept in a holding store and sent to the queue when their visible time comes.
+// By default the messages are held in memory. Set Store to keep them in a persistent storage.
+//
+//	see IMessageHoldingStore
+//	see SendOptions
+//
+//	Example:
+//		func (c *MyMessageQueue) Open(ctx context.Context) error {
+//			...
+//			c.scheduler.Start(ctx)
+//		}
+//
+//		func (c *MyMessageQueue) Send(ctx context.Context, envelope *MessageEnvelope) error {
+//			if !envelope.IsVisible(time.Now()) {
+//				return c.scheduler.Schedule(ctx, envelope)
+//			}
+//			...
+//		}
+type MessageDelayScheduler struct {
+	// The store to hold delayed messages
+	Store IMessageHoldingStore
+	// The logger
+	Logger *clog.CompositeLogger
+	// The interval to check for due messages
+	Interval time.Duration
+
+	queue  IMessageQueue
+	lock   sync.Mutex
+	cancel chan bool
+}
+
+// NewMessageDelayScheduler method are creates a new instance of the scheduler.
+//
+//	Parameters:
+//		- queue     a queue to send due messages to.
+//	Returns: *MessageDelayScheduler
+func NewMessageDelayScheduler(queue IMessageQueue) *MessageDelayScheduler {
+	return &MessageDelayScheduler{
+		Store:    NewMemoryMessageHoldingStore(),
+		Logger:   clog.NewCompositeLogger(),
+		Interval: time.Duration(100) * time.Millisecond,
+		queue:    queue,
+	}
+}
+
+// Schedule method are holds a message until its visible time.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- envelope  a message envelope with set visible time.
+//	Returns: error or nil for success.
+func (c *MessageDelayScheduler) Schedule(ctx context.Context, envelope *MessageEnvelope) error {
+	err := c.Store.Hold(ctx, c.queue.Name(), envelope)
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Trace(cctx.NewContextWithTraceId(ctx, envelope.TraceId),
+		"Held message %s at %s until %s", envelope, c.queue.Name(), envelope.VisibleTime)
+	return nil
+}
+
+// ReleaseDue method are sends to the queue all held messages which visible time has come.
+// If sending fails the remaining messages are held again to be retried later.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//	Returns: error or nil for success.
+func (c *MessageDelayScheduler) ReleaseDue(ctx context.Context) error {
+	messages, err := c.Store.TakeDue(ctx, c.queue.Name(), time.Now())
+	if err != nil {
+		return err
+	}
+
+	for index, message := range messages {
+		err = c.queue.Send(ctx, message)
+		if err != nil {
+			for _, remaining := range messages[index:] {
+				_ = c.Store.Hold(ctx, c.queue.Name(), remaining)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear method are removes all messages held for the queue.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//	Returns: error or nil for success.
+func (c *MessageDelayScheduler) Clear(ctx context.Context) error {
+	return c.Store.Clear(ctx, c.queue.Name())
+}
+
+// Start method are starts releasing due messages in background.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+func (c *MessageDelayScheduler) Start(ctx context.Context) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.cancel != nil {
+		return
+	}
+	cancel := make(chan bool)
+	c.cancel = cancel
+
+	// The scheduler outlives the context of the open call
+	ctx = cctx.NewContextWithTraceId(context.Background(), cctx.GetTraceId(ctx))
+
+	go func() {
+		ticker := time.NewTicker(c.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-cancel:
+				return
+			case <-ticker.C:
+				err := c.ReleaseDue(ctx)
+				if err != nil {
+					c.Logger.Error(ctx, err, "Failed to release delayed messages at %s", c.queue.Name())
+				}
+			}
+		}
+	}()
+}
+
+// Stop method are stops releasing due messages. Held messages stay in the store.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+func (c *MessageDelayScheduler) Stop(ctx context.Context) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.cancel != nil {
+		close(c.cancel)
+		c.cancel = nil
+	}
+}
@@ -6,20 +6,40 @@ import (
 	"time"
 
 	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
 	"github.com/pip-services4/pip-services4-go/pip-services4-data-go/keys"
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/codecs"
+)
+
+const (
+	// ReplyToHeader is a header of request messages with the name of the queue to send replies to
+	ReplyToHeader = "reply_to"
+	// CorrelationIdHeader is a header of reply messages with the id of the request message
+	CorrelationIdHeader = "correlation_id"
+	// ContentTypeHeader is a header with the content type of the message payload
+	ContentTypeHeader = "content_type"
+	// CommandReplyMessageType is a type of reply messages with command results
+	CommandReplyMessageType = "command_reply"
+	// CommandErrorMessageType is a type of reply messages with errors serialized as ErrorDescription
+	CommandErrorMessageType = "command_error"
 )
 
 // MessageEnvelope allows adding additional information to messages. A trace id, message id, and a message type
 // are added to the data being sent/received. Additionally, a MessageEnvelope can reference a lock token.
+// Custom headers like tenant id, content type or schema version are passed along with the message
+// and mapped to native headers of message brokers.
 // Side note: a MessageEnvelope"s message is stored as a buffer, so strings are converted
 // using utf8 conversions.
 type MessageEnvelope struct {
 	reference        any
-	TraceId          string    `json:"trace_id" bson:"trace_id"`         // The unique business transaction id that is used to trace calls across components.
-	MessageId        string    `json:"message_id" bson:"message_id"`     // The message"s auto-generated ID.
-	MessageType      string    `json:"message_type" bson:"message_type"` // String value that defines the stored message"s type.
-	SentTime         time.Time `json:"sent_time" bson:"sent_time"`       // The time at which the message was sent.
-	Message          []byte    `json:"message" bson:"message"`           // The stored message.
+	TraceId          string            `json:"trace_id" bson:"trace_id"`             // The unique business transaction id that is used to trace calls across components.
+	MessageId        string            `json:"message_id" bson:"message_id"`         // The message"s auto-generated ID.
+	MessageType      string            `json:"message_type" bson:"message_type"`     // String value that defines the stored message"s type.
+	SentTime         time.Time         `json:"sent_time" bson:"sent_time"`           // The time at which the message was sent.
+	Message          []byte            `json:"message" bson:"message"`               // The stored message.
+	VisibleTime      time.Time         `json:"visible_time" bson:"visible_time"`     // The time after which the message becomes visible to receivers. Zero for immediate delivery.
+	DeliveryCount    int               `json:"delivery_count" bson:"delivery_count"` // The number of times the message was delivered to receivers.
+	Headers          map[string]string `json:"headers" bson:"headers"`               // The custom message headers.
 	JsonMapConvertor cconv.IJSONEngine[map[string]any]
 }
 
@@ -71,6 +91,65 @@ func NewMessageEnvelopeFromObject(traceId string, messageType string, message an
 	return &c
 }
 
+// NewMessageEnvelopeFromEncodedObject method are creates a new MessageEnvelope with the data object
+// encoded by the codec registered for the content type.
+//
+//		Parameters:
+//	  - traceId     (optional) transaction id to trace execution through call chain.
+//	  - messageType       a string value that defines the message"s type.
+//	  - message           the data object being sent/received.
+//	  - contentType       a content type of the codec, for instance "application/msgpack".
+//		Returns: *MessageEnvelope new instance or error when the object cannot be encoded.
+func NewMessageEnvelopeFromEncodedObject(traceId string, messageType string, message any,
+	contentType string) (*MessageEnvelope, error) {
+
+	c := NewMessageEnvelope(traceId, messageType, nil)
+	if err := c.SetMessageAsEncoded(message, contentType); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Clone method are creates a copy of this MessageEnvelope without the lock token reference.
+//
+//	Returns: *MessageEnvelope a new instance
+func (c *MessageEnvelope) Clone() *MessageEnvelope {
+	clone := *c
+	clone.reference = nil
+	if c.Headers != nil {
+		clone.Headers = make(map[string]string, len(c.Headers))
+		for key, value := range c.Headers {
+			clone.Headers[key] = value
+		}
+	}
+	if c.Message != nil {
+		clone.Message = make([]byte, len(c.Message))
+		copy(clone.Message, c.Message)
+	}
+	return &clone
+}
+
+// GetHeader method are returns a value of the custom header.
+//
+//	Parameters:
+//		- key     the header name.
+//	Returns: the header value or empty string if it is not set.
+func (c *MessageEnvelope) GetHeader(key string) string {
+	return c.Headers[key]
+}
+
+// SetHeader method are sets a value of the custom header.
+//
+//	Parameters:
+//		- key     the header name.
+//		- value   the header value.
+func (c *MessageEnvelope) SetHeader(key string, value string) {
+	if c.Headers == nil {
+		c.Headers = make(map[string]string)
+	}
+	c.Headers[key] = value
+}
+
 // GetReference method are returns the lock token that this MessageEnvelope references.
 func (c *MessageEnvelope) GetReference() any {
 	return c.reference
@@ -84,6 +163,15 @@ func (c *MessageEnvelope) SetReference(value any) {
 	c.reference = value
 }
 
+// IsVisible method are checks if the message can be delivered to receivers at the given time.
+//
+//	Parameters:
+//		- now     the current time.
+//	Returns: true if the message is not delayed or its visible time has come.
+func (c *MessageEnvelope) IsVisible(now time.Time) bool {
+	return c.VisibleTime.IsZero() || !c.VisibleTime.After(now)
+}
+
 // GetMessageAsString method are returns the information stored in this message as a string.
 func (c *MessageEnvelope) GetMessageAsString() string {
 	return string(c.Message)
@@ -98,14 +186,34 @@ func (c *MessageEnvelope) SetMessageAsString(value string) {
 }
 
 // GetMessageAs method are returns the value that was stored in this message as object.
+// When the message has a content type, the payload is decoded by the codec
+// registered for that content type. Otherwise the payload is read as JSON.
 //
 //	see  SetMessageAsObject
+//	see  SetMessageAsEncoded
 func GetMessageAs[T any](envelope *MessageEnvelope) (T, error) {
 	var defaultValue T
 	if envelope.Message == nil {
 		return defaultValue, nil
 	}
-	return cconv.NewDefaultCustomTypeJsonConvertor[T]().FromJson(string(envelope.Message))
+
+	contentType := envelope.GetContentType()
+	if contentType == "" || strings.EqualFold(contentType, codecs.JsonContentType) {
+		return cconv.NewDefaultCustomTypeJsonConvertor[T]().FromJson(string(envelope.Message))
+	}
+
+	codec, ok := codecs.MessageCodecs.Get(contentType)
+	if !ok {
+		return defaultValue, cerr.NewUnsupportedError(envelope.TraceId, "UNSUPPORTED_CONTENT_TYPE",
+			"Content type "+contentType+" is not supported").
+			WithDetails("content_type", contentType)
+	}
+
+	var result T
+	if err := codec.Decode(envelope.Message, &result); err != nil {
+		return defaultValue, err
+	}
+	return result, nil
 }
 
 // SetMessageAsObject method are stores the given value as a JSON string.
@@ -123,6 +231,40 @@ func (c *MessageEnvelope) SetMessageAsObject(value any) {
 	}
 }
 
+// SetMessageAsEncoded method are stores the given value encoded by the codec
+// registered for the content type. The content type is recorded in the message headers.
+//
+//	Parameters:
+//		- value         the value to encode and store in this message.
+//		- contentType   a content type of the codec, for instance "application/x-protobuf".
+//	Returns: error or nil for success.
+//	see  GetMessageAs
+//	see  codecs.MessageCodecs
+func (c *MessageEnvelope) SetMessageAsEncoded(value any, contentType string) error {
+	codec, ok := codecs.MessageCodecs.Get(contentType)
+	if !ok {
+		return cerr.NewUnsupportedError(c.TraceId, "UNSUPPORTED_CONTENT_TYPE",
+			"Content type "+contentType+" is not supported").
+			WithDetails("content_type", contentType)
+	}
+
+	message, err := codec.Encode(value)
+	if err != nil {
+		return err
+	}
+
+	c.Message = message
+	c.SetHeader(ContentTypeHeader, codec.ContentType())
+	return nil
+}
+
+// GetContentType method are returns the content type of the message payload.
+//
+//	Returns: the content type or empty string for JSON payloads without content type.
+func (c *MessageEnvelope) GetContentType() string {
+	return c.GetHeader(ContentTypeHeader)
+}
+
 // String method are convert"s this MessageEnvelope to a string, using the following format:
 // <trace_id>,<MessageType>,<message.toString>
 // If any of the values are nil, they will be replaced with ---.
@@ -165,6 +307,18 @@ func (c MessageEnvelope) MarshalJSON() ([]byte, error) {
 		jsonData["sent_time"] = time.Now()
 	}
 
+	if !c.VisibleTime.IsZero() {
+		jsonData["visible_time"] = c.VisibleTime
+	}
+
+	if c.DeliveryCount > 0 {
+		jsonData["delivery_count"] = c.DeliveryCount
+	}
+
+	if len(c.Headers) > 0 {
+		jsonData["headers"] = c.Headers
+	}
+
 	if c.Message != nil {
 		base64Text := make([]byte, base64.StdEncoding.EncodedLen(len(c.Message)))
 		base64.StdEncoding.Encode(base64Text, c.Message)
@@ -195,6 +349,16 @@ func (c *MessageEnvelope) UnmarshalJSON(data []byte) error {
 		c.MessageType = _val
 	}
 	c.SentTime = cconv.DateTimeConverter.ToDateTime(jsonData["sent_time"])
+	if _val, ok := jsonData["visible_time"]; ok && _val != nil {
+		c.VisibleTime = cconv.DateTimeConverter.ToDateTime(_val)
+	}
+	c.DeliveryCount = cconv.IntegerConverter.ToInteger(jsonData["delivery_count"])
+	if headers, ok := jsonData["headers"].(map[string]any); ok {
+		c.Headers = make(map[string]string, len(headers))
+		for key, value := range headers {
+			c.Headers[key] = cconv.StringConverter.ToString(value)
+		}
+	}
 
 	if base64Text, ok := jsonData["message"].(string); ok && base64Text != "" {
 		data := make([]byte, base64.StdEncoding.DecodedLen(len(base64Text)))
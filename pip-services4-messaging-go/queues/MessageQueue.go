@@ -3,6 +3,7 @@ package queues
 import (
 	"context"
 	"sync"
+	"time"
 
 	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
 	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
@@ -29,6 +30,9 @@ type IMessageQueueOverrides interface {
 //
 //	Configuration parameters:
 //		- name:                        	name of the message queue
+//		- options:
+//			- max_delivery_count:       (optional) maximum number of deliveries before a message is moved to dead letter queue (default: 0 unlimited)
+//			- content_type:             (optional) content type of objects sent by SendAsObject, for instance application/x-protobuf (default: JSON)
 //		- connection(s):
 //			- discovery_key:            key to retrieve parameters from discovery service
 //			- protocol:                 connection protocol like http, https, tcp, udp
@@ -56,6 +60,13 @@ type MessageQueue struct {
 	Lock               sync.Mutex
 	name               string
 	capabilities       *MessagingCapabilities
+
+	// The queue to move poison messages to. When it is not set a queue specific dead letter destination is used.
+	DeadLetterQueue IMessageQueue
+	// The maximum number of deliveries before a message is moved to dead letter queue. 0 for unlimited.
+	MaxDeliveryCount int
+	// The content type of objects sent by SendAsObject. Empty for JSON.
+	ContentType string
 }
 
 // InheritMessageQueue method are creates a new instance of the message queue.
@@ -108,6 +119,8 @@ func (c *MessageQueue) Configure(ctx context.Context, config *cconf.ConfigParams
 
 	c.name = cconf.NameResolver.ResolveWithDefault(config, c.name)
 	c.name = config.GetAsStringWithDefault("queue", c.name)
+	c.MaxDeliveryCount = config.GetAsIntegerWithDefault("options.max_delivery_count", c.MaxDeliveryCount)
+	c.ContentType = config.GetAsStringWithDefault("options.content_type", c.ContentType)
 }
 
 // SetReferences method are sets references to dependent components.
@@ -174,8 +187,33 @@ func (c *MessageQueue) CheckOpen(traceId string) error {
 	return nil
 }
 
+// SendWithOptions method are sends a message into the queue with delivery options.
+// The delay is recorded in the envelope visible time and Send of the specific queue
+// is responsible to hold the message until it becomes visible.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- envelope          a message envelop to be sent.
+//		- options           delivery options like delay or scheduled time.
+//	Returns: error or nil for success.
+//	see SendOptions
+func (c *MessageQueue) SendWithOptions(ctx context.Context, envelope *MessageEnvelope, options *SendOptions) error {
+	if options.IsDelayed() {
+		if c.capabilities == nil || !c.capabilities.CanDelay() {
+			return cerr.NewUnsupportedError(
+				cctx.GetTraceId(ctx),
+				"DELAY_NOT_SUPPORTED",
+				"Delayed delivery is not supported by queue "+c.Name(),
+			)
+		}
+		envelope.VisibleTime = options.VisibleTime(time.Now())
+	}
+	return c.Overrides.Send(ctx, envelope)
+}
+
 // SendAsObject method are sends an object into the queue.
 // Before sending the object is converted into JSON string and wrapped in a MessageEnvelop.
+// When the queue has a content type, the object is encoded by the codec registered for it.
 //
 //	Parameters:
 //		- ctx context.Context execution context to trace execution through call chain.
@@ -184,10 +222,131 @@ func (c *MessageQueue) CheckOpen(traceId string) error {
 //	Returns: error or nil for success.
 //	see Send
 func (c *MessageQueue) SendAsObject(ctx context.Context, messageType string, message any) (err error) {
-	envelope := NewMessageEnvelopeFromObject(cctx.GetTraceId(ctx), messageType, message)
+	if c.ContentType == "" {
+		envelope := NewMessageEnvelopeFromObject(cctx.GetTraceId(ctx), messageType, message)
+		return c.Overrides.Send(ctx, envelope)
+	}
+
+	envelope, err := NewMessageEnvelopeFromEncodedObject(cctx.GetTraceId(ctx), messageType, message, c.ContentType)
+	if err != nil {
+		return err
+	}
 	return c.Overrides.Send(ctx, envelope)
 }
 
+// SendBatch method are sends multiple messages into the queue.
+// The default implementation sends messages one by one.
+// Queues that are able to send batches natively override this method.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- envelopes         a list of message envelops to be sent.
+//	Returns: error or nil for success.
+//	see Send
+func (c *MessageQueue) SendBatch(ctx context.Context, envelopes []*MessageEnvelope) error {
+	for _, envelope := range envelopes {
+		if err := c.Overrides.Send(ctx, envelope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReceiveBatch method are receives multiple incoming messages and removes them from the queue.
+// The default implementation waits for the first message and then receives
+// messages one by one while they are available without waiting.
+// Queues that are able to receive batches natively override this method.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- maxCount          a maximum number of messages to receive.
+//		- waitTimeout       a timeout in milliseconds to wait for messages to come.
+//	Returns: a list with messages or error.
+//	see Receive
+func (c *MessageQueue) ReceiveBatch(ctx context.Context, maxCount int64, waitTimeout time.Duration) ([]*MessageEnvelope, error) {
+	messages := make([]*MessageEnvelope, 0)
+	timeout := waitTimeout
+
+	for int64(len(messages)) < maxCount {
+		message, err := c.Overrides.Receive(ctx, timeout)
+		if err != nil {
+			return messages, err
+		}
+		if message == nil {
+			break
+		}
+		messages = append(messages, message)
+		timeout = 0
+	}
+
+	return messages, nil
+}
+
+// CompleteBatch method are permanently removes multiple messages from the queue.
+// The default implementation completes messages one by one.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- messages          a list of messages to remove.
+//	Returns: error or nil for success.
+//	see Complete
+func (c *MessageQueue) CompleteBatch(ctx context.Context, messages []*MessageEnvelope) error {
+	for _, message := range messages {
+		if err := c.Overrides.Complete(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsDeliveryExceeded method are checks if the message was delivered more times than allowed.
+//
+//	Parameters:
+//		- envelope          a received message.
+//	Returns: true if the message shall be moved to dead letter queue.
+func (c *MessageQueue) IsDeliveryExceeded(envelope *MessageEnvelope) bool {
+	return c.MaxDeliveryCount > 0 && envelope != nil && envelope.DeliveryCount > c.MaxDeliveryCount
+}
+
+// MoveToDeadLetterIfExceeded method are moves the received message to dead letter queue
+// when it was delivered more times than allowed. Receivers shall skip such messages.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- envelope          a received message.
+//	Returns: true if the message was moved to dead letter queue.
+func (c *MessageQueue) MoveToDeadLetterIfExceeded(ctx context.Context, envelope *MessageEnvelope) bool {
+	if !c.IsDeliveryExceeded(envelope) {
+		return false
+	}
+
+	ctx = cctx.NewContextWithTraceId(ctx, envelope.TraceId)
+	c.Logger.Warn(ctx, "Message %s exceeded %d deliveries at %s", envelope, c.MaxDeliveryCount, c.Name())
+
+	err := c.Overrides.MoveToDeadLetter(ctx, envelope)
+	if err != nil {
+		c.Logger.Error(ctx, err, "Failed to move message %s to dead letter queue", envelope)
+	}
+	return true
+}
+
+// SendToDeadLetterQueue method are sends a copy of the message to DeadLetterQueue when it is set.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- envelope          a message to be sent.
+//	Returns: true if the message was sent or error.
+func (c *MessageQueue) SendToDeadLetterQueue(ctx context.Context, envelope *MessageEnvelope) (bool, error) {
+	if c.DeadLetterQueue == nil {
+		return false, nil
+	}
+
+	message := envelope.Clone()
+	message.VisibleTime = time.Time{}
+	err := c.DeadLetterQueue.Send(ctx, message)
+	return err == nil, err
+}
+
 // BeginListen method are listens for incoming messages without blocking the current thread.
 //
 //	Parameters:
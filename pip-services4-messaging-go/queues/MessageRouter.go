@@ -0,0 +1,292 @@
+package queues
+
+import (
+	"context"
+	"sync"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cvalid "github.com/pip-services4/pip-services4-go/pip-services4-data-go/validate"
+	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// UnknownMessageIgnore completes messages of unknown types without processing
+	UnknownMessageIgnore = "ignore"
+	// UnknownMessageAbandon returns messages of unknown types into the queue
+	UnknownMessageAbandon = "abandon"
+	// UnknownMessageDeadLetter moves messages of unknown types to dead letter queue
+	UnknownMessageDeadLetter = "dead_letter"
+)
+
+type messageRoute struct {
+	schema  cvalid.ISchema
+	read    func(envelope *MessageEnvelope) (any, error)
+	handler func(ctx context.Context, message any, envelope *MessageEnvelope, queue IMessageQueue) error
+}
+
+// MessageRouter message receiver that passes incoming messages to handlers registered for their message types.
+// Before a message is passed to the handler its content can be validated by a schema.
+// The content is decoded for validation by the codec registered for the message content type.
+// Protobuf messages are decoded into the handler type, so they shall be routed to typed handlers
+// registered by RegisterMessageHandler. Their fields are validated by the names in .proto files.
+// Messages that cannot be read or fail validation are moved to dead letter queue.
+//
+// Handlers are responsible to complete or abandon messages the same way as IMessageReceiver.
+//
+//	Configuration parameters:
+//		- options:
+//			- unknown_type:    (optional) action for messages of unknown types: ignore, abandon or dead_letter (default: ignore)
+//
+//	see IMessageReceiver
+//	see RegisterMessageHandler
+//
+//	Example:
+//		type OrderCreatedV1 struct {
+//			Id     string `json:"id"`
+//			Amount float64 `json:"amount"`
+//		}
+//
+//		router := NewMessageRouter()
+//		RegisterMessageHandler(router, "order.created", nil,
+//			func(ctx context.Context, order OrderCreatedV1, envelope *MessageEnvelope, queue IMessageQueue) error {
+//				...
+//				return queue.Complete(ctx, envelope)
+//			})
+//
+//		queue.BeginListen(ctx, router)
+//
+//	Implements: IMessageReceiver
+type MessageRouter struct {
+	// The logger
+	Logger *clog.CompositeLogger
+	// The action for messages of unknown types
+	UnknownType string
+
+	lock   sync.RWMutex
+	routes map[string]*messageRoute
+}
+
+// NewMessageRouter method are creates a new instance of the router.
+//
+//	Returns: *MessageRouter
+func NewMessageRouter() *MessageRouter {
+	return &MessageRouter{
+		Logger:      clog.NewCompositeLogger(),
+		UnknownType: UnknownMessageIgnore,
+		routes:      make(map[string]*messageRoute),
+	}
+}
+
+// Configure method are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config    configuration parameters to be set.
+func (c *MessageRouter) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.UnknownType = config.GetAsStringWithDefault("options.unknown_type", c.UnknownType)
+}
+
+// Register method are registers a handler for messages of the given type.
+// A handler registered before for the same type is replaced.
+//
+//	Parameters:
+//		- messageType   a type of messages to handle.
+//		- schema        (optional) a validation schema for the message content.
+//		- handler       a function to process messages.
+func (c *MessageRouter) Register(messageType string, schema cvalid.ISchema,
+	handler func(ctx context.Context, envelope *MessageEnvelope, queue IMessageQueue) error) {
+
+	c.register(messageType, &messageRoute{
+		schema: schema,
+		handler: func(ctx context.Context, message any, envelope *MessageEnvelope, queue IMessageQueue) error {
+			return handler(ctx, envelope, queue)
+		},
+	})
+}
+
+func (c *MessageRouter) register(messageType string, route *messageRoute) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.routes[messageType] = route
+}
+
+// RegisterMessageHandler registers a typed handler for messages of the given type.
+// The message content is decoded to the handler type before the handler is called.
+//
+//	Parameters:
+//		- router        a router to register the handler in.
+//		- messageType   a type of messages to handle.
+//		- schema        (optional) a validation schema for the message content.
+//		- handler       a function to process messages.
+func RegisterMessageHandler[T any](router *MessageRouter, messageType string, schema cvalid.ISchema,
+	handler func(ctx context.Context, message T, envelope *MessageEnvelope, queue IMessageQueue) error) {
+
+	router.register(messageType, &messageRoute{
+		schema: schema,
+		read: func(envelope *MessageEnvelope) (any, error) {
+			return GetMessageAs[T](envelope)
+		},
+		handler: func(ctx context.Context, message any, envelope *MessageEnvelope, queue IMessageQueue) error {
+			typedMessage, _ := message.(T)
+			return handler(ctx, typedMessage, envelope, queue)
+		},
+	})
+}
+
+// IsRegistered method are checks if a handler is registered for the given message type.
+//
+//	Parameters:
+//		- messageType   a type of messages.
+//	Returns: true if the handler is registered.
+func (c *MessageRouter) IsRegistered(messageType string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	_, ok := c.routes[messageType]
+	return ok
+}
+
+// ReceiveMessage method are passes the message to the handler registered for its type.
+//
+//	Parameters:
+//		- ctx context.Context   operation context
+//		- envelope  an incoming message
+//		- queue     a queue where the message comes from
+//	Returns: error of the handler or the queue.
+func (c *MessageRouter) ReceiveMessage(ctx context.Context, envelope *MessageEnvelope, queue IMessageQueue) error {
+	c.lock.RLock()
+	route, ok := c.routes[envelope.MessageType]
+	c.lock.RUnlock()
+
+	if !ok {
+		return c.handleUnknownMessage(ctx, envelope, queue)
+	}
+
+	var message any
+	if route.read != nil {
+		var err error
+		message, err = route.read(envelope)
+		if err != nil {
+			return c.rejectInvalidMessage(ctx, envelope, queue, err)
+		}
+	}
+
+	if route.schema != nil {
+		value, err := c.readValidatedValue(envelope, message)
+		if err != nil {
+			return c.rejectInvalidMessage(ctx, envelope, queue, err)
+		}
+
+		if err := route.schema.ValidateAndReturnError(envelope.TraceId, value, false); err != nil {
+			return c.rejectMessage(ctx, envelope, queue, err)
+		}
+	}
+
+	return route.handler(ctx, message, envelope, queue)
+}
+
+// readValidatedValue gets the message content in a generic form to validate it by a schema.
+// Protobuf messages have no generic form in their codec, so the decoded message is converted into a map.
+func (c *MessageRouter) readValidatedValue(envelope *MessageEnvelope, message any) (any, error) {
+	if protoMessage, ok := message.(proto.Message); ok && protoMessage.ProtoReflect().IsValid() {
+		return protoMessageToValue(protoMessage.ProtoReflect()), nil
+	}
+
+	if len(envelope.Message) == 0 {
+		return nil, nil
+	}
+	return GetMessageAs[any](envelope)
+}
+
+func (c *MessageRouter) handleUnknownMessage(ctx context.Context, envelope *MessageEnvelope, queue IMessageQueue) error {
+	traceCtx := cctx.NewContextWithTraceId(ctx, envelope.TraceId)
+
+	switch c.UnknownType {
+	case UnknownMessageAbandon:
+		c.Logger.Debug(traceCtx, "Abandoned message %s of unknown type at %s", envelope, queue.Name())
+		return queue.Abandon(ctx, envelope)
+	case UnknownMessageDeadLetter:
+		c.Logger.Warn(traceCtx, "Moved message %s of unknown type to dead letter queue at %s", envelope, queue.Name())
+		return queue.MoveToDeadLetter(ctx, envelope)
+	default:
+		c.Logger.Debug(traceCtx, "Ignored message %s of unknown type at %s", envelope, queue.Name())
+		return queue.Complete(ctx, envelope)
+	}
+}
+
+func (c *MessageRouter) rejectInvalidMessage(ctx context.Context, envelope *MessageEnvelope, queue IMessageQueue, err error) error {
+	return c.rejectMessage(ctx, envelope, queue,
+		cerr.NewBadRequestError(envelope.TraceId, "INVALID_MESSAGE", "Failed to read message "+envelope.MessageId).
+			WithCause(err))
+}
+
+// rejectMessage moves the message that cannot be processed to dead letter queue.
+// Such messages fail on every delivery, so they are not returned to the queue.
+func (c *MessageRouter) rejectMessage(ctx context.Context, envelope *MessageEnvelope, queue IMessageQueue, err error) error {
+	traceCtx := cctx.NewContextWithTraceId(ctx, envelope.TraceId)
+	c.Logger.Error(traceCtx, err, "Rejected invalid message %s at %s", envelope, queue.Name())
+	return queue.MoveToDeadLetter(ctx, envelope)
+}
+
+// protoMessageToValue converts the protobuf message into a map with field names from .proto files.
+// Fields without presence are always set, like in messages encoded to JSON with unpopulated fields.
+func protoMessageToValue(message protoreflect.Message) any {
+	switch value := message.Interface().(type) {
+	case *structpb.Struct:
+		return value.AsMap()
+	case *structpb.ListValue:
+		return value.AsSlice()
+	case *structpb.Value:
+		return value.AsInterface()
+	}
+
+	result := make(map[string]any)
+	fields := message.Descriptor().Fields()
+	for index := 0; index < fields.Len(); index++ {
+		field := fields.Get(index)
+		if field.HasPresence() && !message.Has(field) {
+			continue
+		}
+
+		value := message.Get(field)
+		switch {
+		case field.IsList():
+			list := value.List()
+			items := make([]any, list.Len())
+			for itemIndex := range items {
+				items[itemIndex] = protoFieldValueToValue(field, list.Get(itemIndex))
+			}
+			result[string(field.Name())] = items
+		case field.IsMap():
+			items := make(map[string]any)
+			value.Map().Range(func(key protoreflect.MapKey, item protoreflect.Value) bool {
+				items[key.String()] = protoFieldValueToValue(field.MapValue(), item)
+				return true
+			})
+			result[string(field.Name())] = items
+		default:
+			result[string(field.Name())] = protoFieldValueToValue(field, value)
+		}
+	}
+	return result
+}
+
+func protoFieldValueToValue(field protoreflect.FieldDescriptor, value protoreflect.Value) any {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoMessageToValue(value.Message())
+	case protoreflect.EnumKind:
+		if enumValue := field.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return int32(value.Enum())
+	default:
+		return value.Interface()
+	}
+}
@@ -12,6 +12,7 @@ type MessagingCapabilities struct {
 	canAbandon      bool
 	canDeadLetter   bool
 	canClear        bool
+	canDelay        bool
 }
 
 // NewMessagingCapabilities method are creates a new instance of the capabilities object.
@@ -96,3 +97,19 @@ func (c *MessagingCapabilities) CanDeadLetter() bool {
 func (c *MessagingCapabilities) CanClear() bool {
 	return c.canClear
 }
+
+// CanDelay method are informs if the queue is able to deliver messages with a delay or at a scheduled time.
+//	Returns: true if queue is able to deliver delayed messages.
+func (c *MessagingCapabilities) CanDelay() bool {
+	return c.canDelay
+}
+
+// WithDelay method are sets the delayed delivery capability and returns the same object
+// to chain it with the constructor.
+//	Parameters:
+//		- canDelay          true if queue is able to deliver delayed messages.
+//	Returns: *MessagingCapabilities
+func (c *MessagingCapabilities) WithDelay(canDelay bool) *MessagingCapabilities {
+	c.canDelay = canDelay
+	return c
+}
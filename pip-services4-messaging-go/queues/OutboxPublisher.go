@@ -0,0 +1,146 @@
+package queues
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// OutboxPublisher drains change events from a transactional outbox of a persistence component
+// and sends them to a message queue. Events are removed from the outbox only after they were sent,
+// so they are published at least once even if the process fails.
+//
+// Every event is sent as a JSON message with the change type as the message type
+// and a message id derived from the outbox entry. Events sent again after failures keep
+// the same message id, so consumers wrapped into IdempotentMessageReceiver process them only once.
+//
+//	see cpersist.IChangeOutbox
+//	see IdempotentMessageReceiver
+//
+//	Example:
+//		persistence.Configure(ctx, cconf.NewConfigParamsFromTuples(
+//			"options.outbox", true,
+//		))
+//		...
+//		publisher := NewOutboxPublisher(persistence, queue)
+//		publisher.Start(ctx)
+//		...
+//		publisher.Stop(ctx)
+type OutboxPublisher struct {
+	// The logger
+	Logger *clog.CompositeLogger
+	// The interval to check for new events
+	Interval time.Duration
+	// The maximum number of events drained at once
+	BatchSize int
+
+	outbox cpersist.IChangeOutbox
+	queue  IMessageQueue
+	lock   sync.Mutex
+	cancel chan bool
+}
+
+// NewOutboxPublisher method are creates a new instance of the publisher.
+//
+//	Parameters:
+//		- outbox    an outbox to drain change events from.
+//		- queue     a queue to send change events to.
+//	Returns: *OutboxPublisher
+func NewOutboxPublisher(outbox cpersist.IChangeOutbox, queue IMessageQueue) *OutboxPublisher {
+	return &OutboxPublisher{
+		Logger:    clog.NewCompositeLogger(),
+		Interval:  time.Duration(1000) * time.Millisecond,
+		BatchSize: 100,
+		outbox:    outbox,
+		queue:     queue,
+	}
+}
+
+// Publish method are sends all change events kept in the outbox to the queue.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//	Returns: a number of sent events or error.
+func (c *OutboxPublisher) Publish(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		count, err := c.outbox.DrainOutbox(ctx, c.BatchSize, c.sendEntries)
+		total += count
+		if err != nil {
+			return total, err
+		}
+		if count == 0 || count < c.BatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		c.Logger.Trace(ctx, "Published %d change events to %s", total, c.queue.Name())
+	}
+	return total, nil
+}
+
+func (c *OutboxPublisher) sendEntries(ctx context.Context, entries []*cpersist.OutboxEntry) error {
+	for _, entry := range entries {
+		event := entry.Event
+		envelope := NewMessageEnvelopeFromObject(cctx.GetTraceId(ctx), event.Type, event)
+		envelope.MessageId = "outbox-" + event.Source + "-" + entry.Id
+		if err := c.queue.Send(ctx, envelope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start method are starts publishing change events in background.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+func (c *OutboxPublisher) Start(ctx context.Context) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.cancel != nil {
+		return
+	}
+	cancel := make(chan bool)
+	c.cancel = cancel
+
+	// The publisher outlives the context of the start call
+	ctx = cctx.NewContextWithTraceId(context.Background(), cctx.GetTraceId(ctx))
+
+	go func() {
+		ticker := time.NewTicker(c.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-cancel:
+				return
+			case <-ticker.C:
+				_, err := c.Publish(ctx)
+				if err != nil {
+					c.Logger.Error(ctx, err, "Failed to publish change events to %s", c.queue.Name())
+				}
+			}
+		}
+	}()
+}
+
+// Stop method are stops publishing change events. Unpublished events stay in the outbox.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+func (c *OutboxPublisher) Stop(ctx context.Context) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.cancel != nil {
+		close(c.cancel)
+		c.cancel = nil
+	}
+}
@@ -0,0 +1,69 @@
+package queues
+
+import "time"
+
+// SendOptions defines delivery options for messages sent via IMessageQueue.SendWithOptions.
+// A message can be delayed for a period of time or scheduled to be delivered at a specific time.
+// When both are set the scheduled time takes precedence.
+//
+//	see IMessageQueue
+//	see MessagingCapabilities
+//
+//	Example:
+//		// Retry in 30 seconds
+//		err := queue.SendWithOptions(ctx, envelope, NewDelaySendOptions(30*time.Second))
+//		// Remind tomorrow
+//		err = queue.SendWithOptions(ctx, envelope, NewScheduledSendOptions(time.Now().Add(24*time.Hour)))
+type SendOptions struct {
+	// Delay is a period of time after which the message becomes visible to receivers
+	Delay time.Duration
+	// ScheduledTime is a time when the message becomes visible to receivers
+	ScheduledTime time.Time
+}
+
+// NewDelaySendOptions method are creates options to deliver a message after a delay.
+//
+//	Parameters:
+//		- delay     a period of time to hold the message.
+//	Returns: *SendOptions
+func NewDelaySendOptions(delay time.Duration) *SendOptions {
+	return &SendOptions{
+		Delay: delay,
+	}
+}
+
+// NewScheduledSendOptions method are creates options to deliver a message at a scheduled time.
+//
+//	Parameters:
+//		- scheduledTime     a time when the message shall be delivered.
+//	Returns: *SendOptions
+func NewScheduledSendOptions(scheduledTime time.Time) *SendOptions {
+	return &SendOptions{
+		ScheduledTime: scheduledTime,
+	}
+}
+
+// IsDelayed method are checks if the options hold the message from immediate delivery.
+//
+//	Returns: true if delay or scheduled time are set.
+func (c *SendOptions) IsDelayed() bool {
+	return c != nil && (c.Delay > 0 || !c.ScheduledTime.IsZero())
+}
+
+// VisibleTime method are calculates a time when the message becomes visible to receivers.
+//
+//	Parameters:
+//		- now   the current time.
+//	Returns: the visible time or zero time when the message is not delayed.
+func (c *SendOptions) VisibleTime(now time.Time) time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+	if !c.ScheduledTime.IsZero() {
+		return c.ScheduledTime
+	}
+	if c.Delay > 0 {
+		return now.Add(c.Delay)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,52 @@
+package queues
+
+import (
+	"context"
+)
+
+// TopicSubscription is a subscription to a publish/subscribe topic that receives messages
+// via a message queue. It is used by topic implementations to expose their subscriptions.
+//
+//	see ISubscription
+//	see ITopic
+type TopicSubscription struct {
+	IMessageQueue
+
+	topic       string
+	unsubscribe func(ctx context.Context) error
+}
+
+// NewTopicSubscription method are creates a new instance of the subscription.
+//
+//	Parameters:
+//		- queue         a message queue that receives messages of the subscription.
+//		- topic         a name of the subscribed topic.
+//		- unsubscribe   (optional) a function to remove the subscription. When it is not set the queue is closed.
+//	Returns: *TopicSubscription
+func NewTopicSubscription(queue IMessageQueue, topic string, unsubscribe func(ctx context.Context) error) *TopicSubscription {
+	c := &TopicSubscription{
+		IMessageQueue: queue,
+		topic:         topic,
+		unsubscribe:   unsubscribe,
+	}
+	if c.unsubscribe == nil {
+		c.unsubscribe = queue.Close
+	}
+	return c
+}
+
+// Topic method are gets the name of the subscribed topic.
+//
+//	Returns: the topic name that may contain wildcards.
+func (c *TopicSubscription) Topic() string {
+	return c.topic
+}
+
+// Unsubscribe method are stops receiving messages and closes the subscription.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//	Returns: error or nil for success.
+func (c *TopicSubscription) Unsubscribe(ctx context.Context) error {
+	return c.unsubscribe(ctx)
+}
@@ -0,0 +1,40 @@
+package queues
+
+import (
+	"context"
+
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
+)
+
+// InjectTraceContext method are puts W3C trace context and baggage from the context
+// into the message headers, so receivers can continue the trace.
+// Trace context that is already set in the message, e.g. when the message is
+// redelivered or forwarded, is kept unchanged.
+//
+//	Parameters:
+//		- ctx context.Context execution context with the current span context.
+//		- envelope          	a message envelop to be sent.
+func InjectTraceContext(ctx context.Context, envelope *MessageEnvelope) {
+	if envelope == nil || envelope.GetHeader(ctrace.TraceParentHeader) != "" {
+		return
+	}
+	headers := make(ctrace.MapTraceContextCarrier)
+	ctrace.InjectTraceContext(ctx, headers)
+	for key, value := range headers {
+		envelope.SetHeader(key, value)
+	}
+}
+
+// ExtractTraceContext method are reads W3C trace context and baggage from the message headers
+// into the context passed to message receivers.
+//
+//	Parameters:
+//		- ctx context.Context execution context.
+//		- envelope          	a received message envelop.
+//	Returns: context with the span context of the sender.
+func ExtractTraceContext(ctx context.Context, envelope *MessageEnvelope) context.Context {
+	if envelope == nil || len(envelope.Headers) == 0 {
+		return ctx
+	}
+	return ctrace.ExtractTraceContext(ctx, ctrace.MapTraceContextCarrier(envelope.Headers))
+}
@@ -9,12 +9,14 @@ import (
 
 type TestMessageReceiver struct {
 	messages []queues.MessageEnvelope
+	contexts []context.Context
 	lock     sync.Mutex
 }
 
 func NewTestMessageReceiver() *TestMessageReceiver {
 	return &TestMessageReceiver{
 		messages: make([]queues.MessageEnvelope, 0),
+		contexts: make([]context.Context, 0),
 	}
 }
 
@@ -27,6 +29,15 @@ func (c *TestMessageReceiver) GetMessages() []queues.MessageEnvelope {
 	return result
 }
 
+func (c *TestMessageReceiver) GetContexts() []context.Context {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	result := make([]context.Context, len(c.contexts))
+	copy(result, c.contexts)
+
+	return result
+}
+
 func (c *TestMessageReceiver) GetMessageCount() int {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -37,6 +48,7 @@ func (c *TestMessageReceiver) ReceiveMessage(ctx context.Context, envelope *queu
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	c.messages = append(c.messages, *envelope)
+	c.contexts = append(c.contexts, ctx)
 	return nil
 }
 
@@ -44,5 +56,6 @@ func (c *TestMessageReceiver) Clear(ctx context.Context) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	c.messages = make([]queues.MessageEnvelope, 0)
+	c.contexts = make([]context.Context, 0)
 	return nil
 }
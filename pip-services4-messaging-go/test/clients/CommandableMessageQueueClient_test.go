@@ -0,0 +1,179 @@
+package test_clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	"github.com/pip-services4/pip-services4-go/pip-services4-components-go/exec"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/clients"
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/controllers"
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	ccomands "github.com/pip-services4/pip-services4-go/pip-services4-rpc-go/commands"
+	"github.com/stretchr/testify/assert"
+)
+
+type echoService struct {
+	commandSet *ccomands.CommandSet
+}
+
+func newEchoService() *echoService {
+	c := &echoService{
+		commandSet: ccomands.NewCommandSet(),
+	}
+	c.commandSet.AddCommand(ccomands.NewCommand("echo", nil,
+		func(ctx context.Context, args *exec.Parameters) (any, error) {
+			return map[string]any{
+				"value":    args.GetAsString("value"),
+				"trace_id": cctx.GetTraceId(ctx),
+			}, nil
+		}))
+	c.commandSet.AddCommand(ccomands.NewCommand("nothing", nil,
+		func(ctx context.Context, args *exec.Parameters) (any, error) {
+			return nil, nil
+		}))
+	c.commandSet.AddCommand(ccomands.NewCommand("fail", nil,
+		func(ctx context.Context, args *exec.Parameters) (any, error) {
+			return nil, cerr.NewNotFoundError(cctx.GetTraceId(ctx), "DUMMY_NOT_FOUND", "Dummy is not found")
+		}))
+	return c
+}
+
+func (c *echoService) GetCommandSet() *ccomands.CommandSet {
+	return c.commandSet
+}
+
+type commandableQueueTest struct {
+	requestQueue *queues.MemoryMessageQueue
+	replyQueue   *queues.MemoryMessageQueue
+	controller   *controllers.CommandableMessageQueueController
+	client       *clients.CommandableMessageQueueClient
+}
+
+func newCommandableQueueTest(t *testing.T) *commandableQueueTest {
+	ctx := context.Background()
+
+	c := &commandableQueueTest{
+		requestQueue: queues.NewMemoryMessageQueue("requests"),
+		replyQueue:   queues.NewMemoryMessageQueue("replies"),
+		controller:   controllers.NewCommandableMessageQueueController("echo"),
+		client:       clients.NewCommandableMessageQueueClient("echo"),
+	}
+
+	references := cref.NewReferencesFromTuples(ctx,
+		cref.NewDescriptor("test", "service", "default", "default", "1.0"), newEchoService(),
+		cref.NewDescriptor("pip-services", "message-queue", "memory", "requests", "1.0"), c.requestQueue,
+		cref.NewDescriptor("pip-services", "message-queue", "memory", "replies", "1.0"), c.replyQueue,
+	)
+
+	c.controller.DependencyResolver.Put(ctx, "service", cref.NewDescriptor("test", "service", "*", "*", "1.0"))
+	c.controller.DependencyResolver.Put(ctx, "request_queue", cref.NewDescriptor("*", "message-queue", "*", "requests", "1.0"))
+	c.controller.SetReferences(ctx, references)
+
+	c.client.DependencyResolver.Put(ctx, "request_queue", cref.NewDescriptor("*", "message-queue", "*", "requests", "1.0"))
+	c.client.DependencyResolver.Put(ctx, "reply_queue", cref.NewDescriptor("*", "message-queue", "*", "replies", "1.0"))
+	c.client.SetReferences(ctx, references)
+
+	assert.Nil(t, c.requestQueue.Open(ctx))
+	assert.Nil(t, c.replyQueue.Open(ctx))
+	assert.Nil(t, c.controller.Open(ctx))
+	assert.Nil(t, c.client.Open(ctx))
+	return c
+}
+
+func (c *commandableQueueTest) close(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, c.client.Close(ctx))
+	assert.Nil(t, c.controller.Close(ctx))
+	assert.Nil(t, c.requestQueue.Close(ctx))
+	assert.Nil(t, c.replyQueue.Close(ctx))
+}
+
+func TestCommandableMessageQueueClientCallCommand(t *testing.T) {
+	c := newCommandableQueueTest(t)
+	defer c.close(t)
+
+	ctx := cctx.NewContextWithTraceId(context.Background(), "123")
+
+	reply, err := c.client.CallCommand(ctx, "echo", cdata.NewAnyValueMapFromTuples("value", "ABC"))
+	assert.Nil(t, err)
+	assert.NotNil(t, reply)
+
+	result, err := clients.HandleMessageReply[map[string]any](reply)
+	assert.Nil(t, err)
+	assert.Equal(t, "ABC", result["value"])
+	assert.Equal(t, "123", result["trace_id"])
+
+	reply, err = c.client.CallCommand(ctx, "nothing", nil)
+	assert.Nil(t, err)
+
+	empty, err := clients.HandleMessageReply[map[string]any](reply)
+	assert.Nil(t, err)
+	assert.Nil(t, empty)
+
+	// Requests are completed by the controller and replies by the client
+	count, _ := c.requestQueue.ReadMessageCount()
+	assert.Equal(t, int64(0), count)
+	count, _ = c.replyQueue.ReadMessageCount()
+	assert.Equal(t, int64(0), count)
+}
+
+func TestCommandableMessageQueueClientErrors(t *testing.T) {
+	c := newCommandableQueueTest(t)
+	defer c.close(t)
+
+	ctx := cctx.NewContextWithTraceId(context.Background(), "123")
+
+	_, err := c.client.CallCommand(ctx, "fail", nil)
+	assert.NotNil(t, err)
+	appErr, ok := err.(*cerr.ApplicationError)
+	assert.True(t, ok)
+	assert.Equal(t, "DUMMY_NOT_FOUND", appErr.Code)
+	assert.Equal(t, cerr.NotFound, appErr.Category)
+	assert.Equal(t, "123", appErr.TraceId)
+
+	_, err = c.client.CallCommand(ctx, "unknown", nil)
+	assert.NotNil(t, err)
+	appErr, ok = err.(*cerr.ApplicationError)
+	assert.True(t, ok)
+	assert.Equal(t, "CMD_NOT_FOUND", appErr.Code)
+}
+
+func TestCommandableMessageQueueClientTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	requestQueue := queues.NewMemoryMessageQueue("requests")
+	replyQueue := queues.NewMemoryMessageQueue("replies")
+	assert.Nil(t, requestQueue.Open(ctx))
+	assert.Nil(t, replyQueue.Open(ctx))
+
+	client := clients.NewCommandableMessageQueueClient("echo")
+	client.RequestQueue = requestQueue
+	client.ReplyQueue = replyQueue
+	client.Timeout = 200 * time.Millisecond
+
+	_, err := client.CallCommand(ctx, "echo", nil)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, client.Open(ctx))
+	defer client.Close(ctx)
+
+	start := time.Now()
+	_, err = client.CallCommand(ctx, "echo", nil)
+	assert.NotNil(t, err)
+	appErr, ok := err.(*cerr.ApplicationError)
+	assert.True(t, ok)
+	assert.Equal(t, "CALL_TIMEOUT", appErr.Code)
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+
+	// The request stays in the queue for the service
+	envelope, err := requestQueue.Peek(ctx)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope)
+	assert.Equal(t, "echo.echo", envelope.MessageType)
+	assert.Equal(t, "replies", envelope.GetHeader(queues.ReplyToHeader))
+}
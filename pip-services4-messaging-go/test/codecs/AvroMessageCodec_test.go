@@ -0,0 +1,88 @@
+package test_codecs
+
+import (
+	"testing"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/codecs"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPayloadSchema = `{
+	"type": "record",
+	"name": "TestPayload",
+	"namespace": "test",
+	"fields": [
+		{"name": "id", "type": "string"},
+		{"name": "count", "type": "long"},
+		{"name": "amount", "type": "double"},
+		{"name": "active", "type": "boolean"},
+		{"name": "tags", "type": {"type": "array", "items": "string"}},
+		{"name": "labels", "type": {"type": "map", "values": "string"}},
+		{"name": "data", "type": "bytes"},
+		{"name": "comment", "type": ["null", "string"], "default": null},
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["NEW", "DONE"]}, "default": "NEW"}
+	]
+}`
+
+func TestAvroMessageCodecEncode(t *testing.T) {
+	codec, err := codecs.NewAvroMessageCodec(`{
+		"type": "record", "name": "Order",
+		"fields": [
+			{"name": "id", "type": "string"},
+			{"name": "amount", "type": "long"},
+			{"name": "note", "type": ["null", "string"]}
+		]
+	}`)
+	assert.Nil(t, err)
+	assert.Equal(t, codecs.AvroContentType+"; schema=Order", codec.ContentType())
+
+	data, err := codec.Encode(map[string]any{"id": "abc", "amount": int64(-3), "note": "x"})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x06, 'a', 'b', 'c', 0x05, 0x02, 0x02, 'x'}, data)
+
+	data, err = codec.Encode(map[string]any{"id": "abc", "amount": int64(5), "note": nil})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x06, 'a', 'b', 'c', 0x0a, 0x00}, data)
+
+	_, err = codec.Encode(map[string]any{"id": 1, "amount": int64(5), "note": nil})
+	assert.NotNil(t, err)
+}
+
+func TestAvroMessageCodecRoundTrip(t *testing.T) {
+	codec, err := codecs.NewAvroMessageCodec(testPayloadSchema)
+	assert.Nil(t, err)
+	assert.Equal(t, codecs.AvroContentType+"; schema=test.TestPayload", codec.ContentType())
+
+	payload := newTestPayload()
+	comment := "Test comment"
+	payload.Comment = &comment
+
+	data, err := codec.Encode(payload)
+	assert.Nil(t, err)
+
+	var result testPayload
+	err = codec.Decode(data, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, payload, result)
+
+	var generic map[string]any
+	err = codec.Decode(data, &generic)
+	assert.Nil(t, err)
+	assert.Equal(t, "NEW", generic["status"])
+
+	err = codec.Decode(data[:len(data)-1], &result)
+	assert.NotNil(t, err)
+}
+
+func TestAvroMessageCodecSchemaErrors(t *testing.T) {
+	_, err := codecs.NewAvroMessageCodec(`{"type": "record", "fields": []}`)
+	assert.NotNil(t, err)
+
+	_, err = codecs.NewAvroMessageCodec(`{"type": "unknown_type"}`)
+	assert.NotNil(t, err)
+
+	_, err = codecs.NewAvroMessageCodec(`{"type": "record", "name": "Node", "fields": [
+		{"name": "children", "type": {"type": "array", "items": "Node"}}
+	]}`)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,65 @@
+package test_codecs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/codecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPayload struct {
+	Id      string            `json:"id"`
+	Count   int64             `json:"count"`
+	Amount  float64           `json:"amount"`
+	Active  bool              `json:"active"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+	Data    []byte            `json:"data"`
+	Comment *string           `json:"comment"`
+}
+
+func newTestPayload() testPayload {
+	return testPayload{
+		Id:     strings.Repeat("x", 40),
+		Count:  -1234567890123,
+		Amount: 12.5,
+		Active: true,
+		Tags:   []string{"a", "b"},
+		Labels: map[string]string{"key": "value"},
+		Data:   []byte{0, 1, 2, 255},
+	}
+}
+
+func TestMessagePackMessageCodecEncode(t *testing.T) {
+	codec := codecs.NewMessagePackMessageCodec()
+	assert.Equal(t, codecs.MessagePackContentType, codec.ContentType())
+
+	data, err := codec.Encode(map[string]any{
+		"b": []any{true, nil, "x"},
+		"a": 1,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x93, 0xc3, 0xc0, 0xa1, 'x'}, data)
+
+	data, err = codec.Encode([]any{-1, 200, -200, 70000, 1.5})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x95, 0xff, 0xcc, 0xc8, 0xd1, 0xff, 0x38, 0xce, 0x00, 0x01, 0x11, 0x70,
+		0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}, data)
+}
+
+func TestMessagePackMessageCodecRoundTrip(t *testing.T) {
+	codec := codecs.NewMessagePackMessageCodec()
+	payload := newTestPayload()
+
+	data, err := codec.Encode(payload)
+	assert.Nil(t, err)
+
+	var result testPayload
+	err = codec.Decode(data, &result)
+	assert.Nil(t, err)
+	assert.Equal(t, payload, result)
+
+	err = codec.Decode(data[:len(data)-1], &result)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,63 @@
+package test_codecs
+
+import (
+	"testing"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/codecs"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestProtobufMessageCodec(t *testing.T) {
+	codec := codecs.NewProtobufMessageCodec()
+	assert.Equal(t, codecs.ProtobufContentType, codec.ContentType())
+
+	message, err := structpb.NewStruct(map[string]any{"id": "1", "count": 2})
+	assert.Nil(t, err)
+
+	data, err := codec.Encode(message)
+	assert.Nil(t, err)
+
+	var result structpb.Struct
+	err = codec.Decode(data, &result)
+	assert.Nil(t, err)
+	assert.True(t, proto.Equal(message, &result))
+
+	// Message is allocated for pointer to nil pointer
+	var pointer *structpb.Struct
+	err = codec.Decode(data, &pointer)
+	assert.Nil(t, err)
+	assert.True(t, proto.Equal(message, pointer))
+
+	_, err = codec.Encode(map[string]any{"id": "1"})
+	assert.NotNil(t, err)
+
+	var value map[string]any
+	err = codec.Decode(data, &value)
+	assert.NotNil(t, err)
+}
+
+func TestMessageCodecRegistry(t *testing.T) {
+	registry := codecs.NewDefaultMessageCodecRegistry()
+
+	codec, ok := registry.Get("APPLICATION/JSON")
+	assert.True(t, ok)
+	assert.Equal(t, codecs.JsonContentType, codec.ContentType())
+
+	_, ok = registry.Get(codecs.AvroContentType)
+	assert.False(t, ok)
+
+	avroCodec, err := codecs.NewAvroMessageCodec(`"string"`)
+	assert.Nil(t, err)
+	registry.Register(avroCodec)
+
+	codec, ok = registry.Get(codecs.AvroContentType)
+	assert.True(t, ok)
+	assert.Equal(t, avroCodec, codec)
+	assert.Len(t, registry.ContentTypes(), 4)
+
+	registry.Unregister(codecs.AvroContentType)
+	_, ok = registry.Get(codecs.AvroContentType)
+	assert.False(t, ok)
+}
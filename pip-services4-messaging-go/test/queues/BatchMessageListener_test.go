@@ -0,0 +1,104 @@
+package test_queues
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+type testBatchReceiver struct {
+	lock     sync.Mutex
+	batches  [][]*queues.MessageEnvelope
+	failures int
+}
+
+func (c *testBatchReceiver) ReceiveMessages(ctx context.Context, envelopes []*queues.MessageEnvelope, queue queues.IMessageQueue) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.failures > 0 {
+		c.failures--
+		return errors.New("batch failed")
+	}
+	c.batches = append(c.batches, envelopes)
+	return nil
+}
+
+func (c *testBatchReceiver) messageCount() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	count := 0
+	for _, batch := range c.batches {
+		count += len(batch)
+	}
+	return count
+}
+
+func TestBatchMessageListener(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	envelopes := make([]*queues.MessageEnvelope, 0)
+	for i := 0; i < 5; i++ {
+		envelopes = append(envelopes, queues.NewMessageEnvelope("123", "Test", []byte("Test message")))
+	}
+	err := queue.SendBatch(context.TODO(), envelopes)
+	assert.Nil(t, err)
+
+	receiver := &testBatchReceiver{}
+	listener := queues.NewBatchMessageListener(queue, receiver)
+	listener.MaxCount = 3
+
+	listener.BeginListen(context.TODO())
+	defer listener.EndListen(context.TODO())
+
+	assert.Eventually(t, func() bool {
+		return receiver.messageCount() == 5
+	}, 5*time.Second, 100*time.Millisecond)
+
+	receiver.lock.Lock()
+	assert.Len(t, receiver.batches, 2)
+	assert.Len(t, receiver.batches[0], 3)
+	assert.Len(t, receiver.batches[1], 2)
+	receiver.lock.Unlock()
+
+	count, err := queue.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestBatchMessageListenerAbandonsFailedBatch(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	receiver := &testBatchReceiver{failures: 1}
+	listener := queues.NewBatchMessageListener(queue, receiver)
+
+	err := queue.SendBatch(context.TODO(), []*queues.MessageEnvelope{
+		queues.NewMessageEnvelope("123", "Test", []byte("Message 1")),
+		queues.NewMessageEnvelope("123", "Test", []byte("Message 2")),
+	})
+	assert.Nil(t, err)
+
+	listener.BeginListen(context.TODO())
+	defer listener.EndListen(context.TODO())
+
+	// The failed batch is received again
+	assert.Eventually(t, func() bool {
+		return receiver.messageCount() == 2
+	}, 5*time.Second, 100*time.Millisecond)
+
+	receiver.lock.Lock()
+	for _, message := range receiver.batches[0] {
+		assert.Equal(t, 2, message.DeliveryCount)
+	}
+	receiver.lock.Unlock()
+}
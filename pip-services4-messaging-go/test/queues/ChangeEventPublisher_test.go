@@ -0,0 +1,39 @@
+package test_queues
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+type changeDummy struct {
+	Id  string `json:"id"`
+	Key string `json:"key"`
+}
+
+func TestChangeEventPublisher(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.Background())
+	defer queue.Close(context.Background())
+
+	persistence := cpersist.NewIdentifiableMemoryPersistence[changeDummy, string]()
+	persistence.AddChangeListener(queues.NewChangeEventPublisher(queue))
+
+	_, err := persistence.Create(context.Background(), changeDummy{Id: "1", Key: "Key 1"})
+	assert.Nil(t, err)
+
+	envelope, err := queue.Receive(context.Background(), 1000)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope)
+	assert.Equal(t, cpersist.ChangeTypeCreated, envelope.MessageType)
+
+	event, err := queues.GetMessageAs[cpersist.ChangeEvent](envelope)
+	assert.Nil(t, err)
+	assert.Equal(t, cpersist.ChangeTypeCreated, event.Type)
+	assert.Equal(t, "1", event.Id)
+	assert.Nil(t, event.Before)
+	assert.Equal(t, "Key 1", event.After.(map[string]any)["key"])
+}
@@ -0,0 +1,325 @@
+package test_queues
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+type slowMessageReceiver struct {
+	lock      sync.Mutex
+	delay     time.Duration
+	inFlight  int
+	maxFlight int
+	processed []string
+	fail      map[string]bool
+}
+
+func (c *slowMessageReceiver) ReceiveMessage(ctx context.Context, envelope *queues.MessageEnvelope, queue queues.IMessageQueue) error {
+	c.lock.Lock()
+	c.inFlight++
+	if c.inFlight > c.maxFlight {
+		c.maxFlight = c.inFlight
+	}
+	c.lock.Unlock()
+
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.inFlight--
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if c.fail[envelope.GetMessageAsString()] {
+		delete(c.fail, envelope.GetMessageAsString())
+		return errors.New("processing failed")
+	}
+	c.processed = append(c.processed, envelope.GetMessageAsString())
+	return queue.Complete(ctx, envelope)
+}
+
+func (c *slowMessageReceiver) processedCount() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.processed)
+}
+
+type hangingMessageReceiver func(envelope *queues.MessageEnvelope)
+
+func (c hangingMessageReceiver) ReceiveMessage(ctx context.Context, envelope *queues.MessageEnvelope, queue queues.IMessageQueue) error {
+	c(envelope)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type stubbornMessageReceiver struct {
+	lock       sync.Mutex
+	delay      time.Duration
+	inFlight   int
+	maxFlight  int
+	deliveries int
+}
+
+func (c *stubbornMessageReceiver) ReceiveMessage(ctx context.Context, envelope *queues.MessageEnvelope, queue queues.IMessageQueue) error {
+	c.lock.Lock()
+	c.deliveries++
+	c.inFlight++
+	if c.inFlight > c.maxFlight {
+		c.maxFlight = c.inFlight
+	}
+	c.lock.Unlock()
+
+	// Ignore cancellation of the context
+	time.Sleep(c.delay)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.inFlight--
+	return ctx.Err()
+}
+
+type countingMessageQueue struct {
+	*queues.MemoryMessageQueue
+	lock      sync.Mutex
+	completed int
+	abandoned int
+}
+
+func (c *countingMessageQueue) Complete(ctx context.Context, message *queues.MessageEnvelope) error {
+	c.lock.Lock()
+	c.completed++
+	c.lock.Unlock()
+	return c.MemoryMessageQueue.Complete(ctx, message)
+}
+
+func (c *countingMessageQueue) Abandon(ctx context.Context, message *queues.MessageEnvelope) error {
+	c.lock.Lock()
+	c.abandoned++
+	c.lock.Unlock()
+	return c.MemoryMessageQueue.Abandon(ctx, message)
+}
+
+func sendTestMessages(t *testing.T, queue queues.IMessageQueue, count int, key func(index int) string) {
+	for i := 0; i < count; i++ {
+		envelope := queues.NewMessageEnvelope("123", "Test", []byte(strconv.Itoa(i)))
+		if key != nil {
+			envelope.MessageId = key(i)
+		}
+		err := queue.Send(context.TODO(), envelope)
+		assert.Nil(t, err)
+	}
+}
+
+func TestConcurrentMessageListenerProcessesInParallel(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	sendTestMessages(t, queue, 8, nil)
+
+	receiver := &slowMessageReceiver{delay: 300 * time.Millisecond}
+	listener := queues.NewConcurrentMessageListener(queue, receiver)
+	listener.Workers = 4
+	listener.Prefetch = 4
+
+	start := time.Now()
+	listener.BeginListen(context.TODO())
+
+	assert.Eventually(t, func() bool {
+		return receiver.processedCount() == 8
+	}, 5*time.Second, 50*time.Millisecond)
+	listener.EndListen(context.TODO())
+
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.LessOrEqual(t, receiver.maxFlight, 4)
+	assert.Greater(t, receiver.maxFlight, 1)
+}
+
+func TestConcurrentMessageListenerLimitsPrefetch(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	sendTestMessages(t, queue, 4, nil)
+
+	receiver := &slowMessageReceiver{delay: 100 * time.Millisecond}
+	listener := queues.NewConcurrentMessageListener(queue, receiver)
+	listener.Workers = 4
+	listener.Prefetch = 1
+
+	listener.BeginListen(context.TODO())
+
+	assert.Eventually(t, func() bool {
+		return receiver.processedCount() == 4
+	}, 5*time.Second, 50*time.Millisecond)
+	listener.EndListen(context.TODO())
+
+	assert.Equal(t, 1, receiver.maxFlight)
+}
+
+func TestConcurrentMessageListenerPreservesOrder(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	sendTestMessages(t, queue, 12, func(index int) string {
+		return "key" + strconv.Itoa(index%3)
+	})
+
+	receiver := &slowMessageReceiver{delay: 20 * time.Millisecond}
+	listener := queues.NewConcurrentMessageListener(queue, receiver)
+	listener.OrderKey = func(envelope *queues.MessageEnvelope) string {
+		return envelope.MessageId
+	}
+
+	listener.BeginListen(context.TODO())
+	assert.Eventually(t, func() bool {
+		return receiver.processedCount() == 12
+	}, 5*time.Second, 50*time.Millisecond)
+	listener.EndListen(context.TODO())
+
+	// Messages with the same key are processed in the order they were sent
+	last := map[int]int{}
+	for _, value := range receiver.processed {
+		index, _ := strconv.Atoi(value)
+		if previous, ok := last[index%3]; ok {
+			assert.Less(t, previous, index)
+		}
+		last[index%3] = index
+	}
+}
+
+func TestConcurrentMessageListenerDrainsOnEndListen(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	sendTestMessages(t, queue, 2, nil)
+
+	receiver := &slowMessageReceiver{delay: 500 * time.Millisecond}
+	listener := queues.NewConcurrentMessageListener(queue, receiver)
+	listener.Workers = 2
+
+	listener.BeginListen(context.TODO())
+	assert.Eventually(t, func() bool {
+		receiver.lock.Lock()
+		defer receiver.lock.Unlock()
+		return receiver.inFlight == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Messages in flight complete before EndListen returns
+	listener.EndListen(context.TODO())
+	assert.Equal(t, 2, receiver.processedCount())
+}
+
+func TestConcurrentMessageListenerAbandonsFailedMessages(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	sendTestMessages(t, queue, 2, nil)
+
+	receiver := &slowMessageReceiver{
+		delay: 10 * time.Millisecond,
+		fail:  map[string]bool{"1": true},
+	}
+	listener := queues.NewConcurrentMessageListener(queue, receiver)
+
+	listener.BeginListen(context.TODO())
+	defer listener.EndListen(context.TODO())
+
+	// The failed message is received again
+	assert.Eventually(t, func() bool {
+		return receiver.processedCount() == 2
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestConcurrentMessageListenerTimesOutMessages(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	var lock sync.Mutex
+	deliveries := 0
+	receiver := hangingMessageReceiver(func(envelope *queues.MessageEnvelope) {
+		lock.Lock()
+		deliveries++
+		lock.Unlock()
+	})
+
+	listener := queues.NewConcurrentMessageListener(queue, receiver)
+	listener.MessageTimeout = 100 * time.Millisecond
+
+	sendTestMessages(t, queue, 1, nil)
+	listener.BeginListen(context.TODO())
+	defer listener.EndListen(context.TODO())
+
+	// Timed out message is abandoned and delivered again
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return deliveries >= 2
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestConcurrentMessageListenerWaitsForTimedOutMessages(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	receiver := &stubbornMessageReceiver{delay: 300 * time.Millisecond}
+	listener := queues.NewConcurrentMessageListener(queue, receiver)
+	listener.MessageTimeout = 100 * time.Millisecond
+
+	sendTestMessages(t, queue, 1, nil)
+	listener.BeginListen(context.TODO())
+	defer listener.EndListen(context.TODO())
+
+	// Timed out message is delivered again only after the receiver returns
+	assert.Eventually(t, func() bool {
+		receiver.lock.Lock()
+		defer receiver.lock.Unlock()
+		return receiver.deliveries >= 2
+	}, 5*time.Second, 50*time.Millisecond)
+
+	receiver.lock.Lock()
+	defer receiver.lock.Unlock()
+	assert.Equal(t, 1, receiver.maxFlight)
+}
+
+func TestConcurrentMessageListenerLeavesCompletionToReceiver(t *testing.T) {
+	queue := &countingMessageQueue{MemoryMessageQueue: queues.NewMemoryMessageQueue("TestQueue")}
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	sendTestMessages(t, queue, 4, nil)
+
+	receiver := &slowMessageReceiver{
+		delay: 10 * time.Millisecond,
+		fail:  map[string]bool{"1": true},
+	}
+	listener := queues.NewConcurrentMessageListener(queue, receiver)
+
+	listener.BeginListen(context.TODO())
+	assert.Eventually(t, func() bool {
+		return receiver.processedCount() == 4
+	}, 5*time.Second, 50*time.Millisecond)
+	listener.EndListen(context.TODO())
+
+	// Messages are completed only by the receiver and abandoned only on failure
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+	assert.Equal(t, 4, queue.completed)
+	assert.Equal(t, 1, queue.abandoned)
+}
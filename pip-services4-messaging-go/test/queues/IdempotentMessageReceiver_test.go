@@ -0,0 +1,167 @@
+package test_queues
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingReceiver struct {
+	received []*queues.MessageEnvelope
+	err      error
+}
+
+func (c *countingReceiver) ReceiveMessage(ctx context.Context, envelope *queues.MessageEnvelope, queue queues.IMessageQueue) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.received = append(c.received, envelope)
+	return queue.Complete(ctx, envelope)
+}
+
+func TestIdempotentMessageReceiver(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.Background())
+	defer queue.Close(context.Background())
+
+	store := queues.NewMemoryProcessedMessageStore()
+	receiver := &countingReceiver{}
+	idempotent := queues.NewIdempotentMessageReceiver(receiver, store)
+
+	envelope := queues.NewMessageEnvelope("123", "Test", []byte("Test"))
+
+	// Failed processing is not recorded
+	receiver.err = errors.New("failed")
+	err := idempotent.ReceiveMessage(context.Background(), envelope, queue)
+	assert.NotNil(t, err)
+	processed, err := store.IsProcessed(context.Background(), envelope.MessageId)
+	assert.Nil(t, err)
+	assert.False(t, processed)
+
+	receiver.err = nil
+	err = idempotent.ReceiveMessage(context.Background(), envelope, queue)
+	assert.Nil(t, err)
+	assert.Len(t, receiver.received, 1)
+
+	// Duplicates are skipped
+	err = idempotent.ReceiveMessage(context.Background(), envelope.Clone(), queue)
+	assert.Nil(t, err)
+	assert.Len(t, receiver.received, 1)
+
+	// Other messages are processed
+	err = idempotent.ReceiveMessage(context.Background(), queues.NewMessageEnvelope("123", "Test", []byte("Test")), queue)
+	assert.Nil(t, err)
+	assert.Len(t, receiver.received, 2)
+}
+
+func TestMemoryProcessedMessageStoreRetention(t *testing.T) {
+	store := queues.NewMemoryProcessedMessageStore()
+	store.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"options.retention", 100,
+	))
+
+	err := store.MarkProcessed(context.Background(), "1")
+	assert.Nil(t, err)
+	processed, err := store.IsProcessed(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.True(t, processed)
+
+	time.Sleep(200 * time.Millisecond)
+
+	processed, err = store.IsProcessed(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.False(t, processed)
+	assert.Nil(t, store.Cleanup(context.Background()))
+}
+
+type blockingReceiver struct {
+	count   int32
+	started chan bool
+	release chan bool
+}
+
+func (c *blockingReceiver) ReceiveMessage(ctx context.Context, envelope *queues.MessageEnvelope, queue queues.IMessageQueue) error {
+	atomic.AddInt32(&c.count, 1)
+	c.started <- true
+	<-c.release
+	return nil
+}
+
+func TestIdempotentMessageReceiverConcurrentDuplicates(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.Background())
+	defer queue.Close(context.Background())
+
+	store := queues.NewMemoryProcessedMessageStore()
+	receiver := &blockingReceiver{started: make(chan bool, 2), release: make(chan bool)}
+	envelope := queues.NewMessageEnvelope("123", "Test", []byte("Test"))
+
+	// Receivers with a shared store process the message only once
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := queues.NewIdempotentMessageReceiver(receiver, store).ReceiveMessage(context.Background(), envelope, queue)
+		assert.Nil(t, err)
+	}()
+	<-receiver.started
+
+	err := queues.NewIdempotentMessageReceiver(receiver, store).ReceiveMessage(context.Background(), envelope.Clone(), queue)
+	assert.Nil(t, err)
+
+	close(receiver.release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&receiver.count))
+
+	processed, err := store.IsProcessed(context.Background(), envelope.MessageId)
+	assert.Nil(t, err)
+	assert.True(t, processed)
+}
+
+func TestMemoryProcessedMessageStoreClaims(t *testing.T) {
+	store := queues.NewMemoryProcessedMessageStore()
+	store.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"options.claim_timeout", 100,
+	))
+
+	claimed, err := store.TryClaim(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+
+	// Claimed messages are not processed and cannot be claimed again
+	processed, err := store.IsProcessed(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.False(t, processed)
+	claimed, err = store.TryClaim(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.False(t, claimed)
+
+	// Released claims can be claimed again
+	assert.Nil(t, store.ReleaseClaim(context.Background(), "1"))
+	claimed, err = store.TryClaim(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+
+	// Expired claims can be claimed again
+	time.Sleep(200 * time.Millisecond)
+	claimed, err = store.TryClaim(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+
+	// Processed messages cannot be claimed and released
+	assert.Nil(t, store.MarkProcessed(context.Background(), "1"))
+	claimed, err = store.TryClaim(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.False(t, claimed)
+	assert.Nil(t, store.ReleaseClaim(context.Background(), "1"))
+	processed, err = store.IsProcessed(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.True(t, processed)
+}
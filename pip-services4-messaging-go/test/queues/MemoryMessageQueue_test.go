@@ -3,8 +3,11 @@ package test_queues
 import (
 	"context"
 	"testing"
+	"time"
 
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
 	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMemoryMessageQueue(t *testing.T) {
@@ -23,4 +26,47 @@ func TestMemoryMessageQueue(t *testing.T) {
 	t.Run("MemoryMessageQueue:Peek No Message", fixture.TestPeekNoMessage)
 	t.Run("MemoryMessageQueue:Move To Dead Message", fixture.TestMoveToDeadMessage)
 	t.Run("MemoryMessageQueue:On Message", fixture.TestOnMessage)
+	t.Run("MemoryMessageQueue:Trace Context Propagation", fixture.TestTraceContextPropagation)
+	t.Run("MemoryMessageQueue:Send Delayed Message", fixture.TestSendDelayedMessage)
+	t.Run("MemoryMessageQueue:Send Receive Batch", fixture.TestSendReceiveBatch)
+}
+
+func TestMemoryMessageQueueDeadLetter(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Configure(context.TODO(), cconf.NewConfigParamsFromTuples(
+		"options.max_delivery_count", 2,
+	))
+	deadLetterQueue := queues.NewMemoryMessageQueue("TestDeadLetterQueue")
+	queue.DeadLetterQueue = deadLetterQueue
+
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+	deadLetterQueue.Open(context.TODO())
+	defer deadLetterQueue.Close(context.TODO())
+
+	envelope1 := queues.NewMessageEnvelope("123", "Test", []byte("Test message"))
+	err := queue.Send(context.TODO(), envelope1)
+	assert.Nil(t, err)
+
+	for i := 1; i <= 2; i++ {
+		envelope2, err := queue.Receive(context.TODO(), 1000*time.Millisecond)
+		assert.Nil(t, err)
+		assert.NotNil(t, envelope2)
+		assert.Equal(t, i, envelope2.DeliveryCount)
+
+		err = queue.Abandon(context.TODO(), envelope2)
+		assert.Nil(t, err)
+	}
+
+	// The third delivery exceeds the limit
+	envelope2, err := queue.Receive(context.TODO(), 500*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Nil(t, envelope2)
+
+	envelope3, err := deadLetterQueue.Peek(context.TODO())
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope3)
+	assert.Equal(t, envelope1.MessageId, envelope3.MessageId)
+	assert.Equal(t, envelope1.Message, envelope3.Message)
+	assert.Equal(t, 3, envelope3.DeliveryCount)
 }
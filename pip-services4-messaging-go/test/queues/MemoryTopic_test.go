@@ -0,0 +1,104 @@
+package test_queues
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryTopicFanOut(t *testing.T) {
+	ctx := context.Background()
+
+	topic := queues.NewMemoryTopic("orders")
+	topic.Open(ctx)
+	defer topic.Close(ctx)
+
+	billing, err := topic.Subscribe(ctx, "billing")
+	assert.Nil(t, err)
+	shipping, err := topic.Subscribe(ctx, "shipping")
+	assert.Nil(t, err)
+	assert.Equal(t, "billing", billing.Name())
+	assert.Equal(t, "orders", billing.Topic())
+
+	// Receivers with the same subscription name compete for messages
+	billing2, err := topic.Subscribe(ctx, "billing")
+	assert.Nil(t, err)
+
+	err = topic.Publish(ctx, queues.NewMessageEnvelope("123", "created", []byte("ABC")))
+	assert.Nil(t, err)
+
+	message1, err := billing.Receive(ctx, 1000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, message1)
+	assert.Equal(t, "ABC", message1.GetMessageAsString())
+
+	message2, err := shipping.Receive(ctx, 1000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, message2)
+	assert.Equal(t, message1.MessageId, message2.MessageId)
+
+	count, err := billing2.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	// Unsubscribed subscriptions do not receive messages
+	err = shipping.Unsubscribe(ctx)
+	assert.Nil(t, err)
+	err = topic.Publish(ctx, queues.NewMessageEnvelope("123", "created", []byte("DEF")))
+	assert.Nil(t, err)
+
+	message1, err = billing.Receive(ctx, 1000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, message1)
+	assert.Equal(t, "DEF", message1.GetMessageAsString())
+}
+
+func TestMemoryTopicWildcards(t *testing.T) {
+	ctx := context.Background()
+
+	broker := queues.NewMemoryTopicBroker()
+	created := broker.Topic("orders.created")
+	deleted := broker.Topic("orders.items.deleted")
+
+	oneLevel, err := broker.Topic("orders.*").Subscribe(ctx, "audit")
+	assert.Nil(t, err)
+	allLevels, err := broker.Topic("orders.#").Subscribe(ctx, "audit")
+	assert.Nil(t, err)
+
+	err = created.Publish(ctx, queues.NewMessageEnvelope("123", "created", []byte("ABC")))
+	assert.Nil(t, err)
+	err = deleted.Publish(ctx, queues.NewMessageEnvelope("123", "deleted", []byte("DEF")))
+	assert.Nil(t, err)
+
+	count, err := oneLevel.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = allLevels.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// Messages can not be published to patterns
+	err = broker.Topic("orders.*").Publish(ctx, queues.NewMessageEnvelope("123", "created", []byte("ABC")))
+	assert.NotNil(t, err)
+	err = broker.Topic("orders.>").Publish(ctx, queues.NewMessageEnvelope("123", "created", []byte("ABC")))
+	assert.NotNil(t, err)
+}
+
+func TestMatchTopic(t *testing.T) {
+	assert.True(t, queues.MatchTopic("orders", "orders"))
+	assert.False(t, queues.MatchTopic("orders", "orders.created"))
+	assert.True(t, queues.MatchTopic("orders.*", "orders.created"))
+	assert.False(t, queues.MatchTopic("orders.*", "orders"))
+	assert.True(t, queues.MatchTopic("orders.#", "orders"))
+	assert.True(t, queues.MatchTopic("orders.#", "orders.items.created"))
+	assert.True(t, queues.MatchTopic("*.items.#", "orders.items.created"))
+	assert.False(t, queues.MatchTopic("*.items.#", "orders.created"))
+	assert.True(t, queues.MatchTopic("orders.>", "orders.items.created"))
+	assert.False(t, queues.MatchTopic("orders.>", "orders"))
+	assert.True(t, queues.IsTopicPattern("orders.#"))
+	assert.True(t, queues.IsTopicPattern("orders.>"))
+	assert.False(t, queues.IsTopicPattern("orders"))
+}
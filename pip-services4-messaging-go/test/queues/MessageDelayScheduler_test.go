@@ -0,0 +1,64 @@
+package test_queues
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageDelayScheduler(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	scheduler := queues.NewMessageDelayScheduler(queue)
+
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	envelope1 := queues.NewMessageEnvelope("123", "Test", []byte("Second"))
+	envelope1.VisibleTime = time.Now().Add(400 * time.Millisecond)
+	envelope2 := queues.NewMessageEnvelope("123", "Test", []byte("First"))
+	envelope2.VisibleTime = time.Now().Add(200 * time.Millisecond)
+
+	err := scheduler.Schedule(context.TODO(), envelope1)
+	assert.Nil(t, err)
+	err = scheduler.Schedule(context.TODO(), envelope2)
+	assert.Nil(t, err)
+
+	// Nothing is due yet
+	err = scheduler.ReleaseDue(context.TODO())
+	assert.Nil(t, err)
+	count, err := queue.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	scheduler.Start(context.TODO())
+	defer scheduler.Stop(context.TODO())
+
+	envelope, err := queue.Receive(context.TODO(), 5000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope)
+	assert.Equal(t, "First", envelope.GetMessageAsString())
+
+	envelope, err = queue.Receive(context.TODO(), 5000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope)
+	assert.Equal(t, "Second", envelope.GetMessageAsString())
+}
+
+func TestSendWithOptionsNotSupported(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Capabilities().WithDelay(false)
+
+	queue.Open(context.TODO())
+	defer queue.Close(context.TODO())
+
+	envelope := queues.NewMessageEnvelope("123", "Test", []byte("Test message"))
+	err := queue.SendWithOptions(context.TODO(), envelope, queues.NewDelaySendOptions(time.Second))
+	assert.NotNil(t, err)
+
+	// Messages without delay are sent as usual
+	err = queue.SendWithOptions(context.TODO(), envelope, nil)
+	assert.Nil(t, err)
+}
@@ -1,9 +1,13 @@
 package test_queues
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/codecs"
 	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
 	"github.com/stretchr/testify/assert"
 )
@@ -25,6 +29,9 @@ func (c *messageEnvelopeTest) TestSerializeMessage(t *testing.T) {
 	assert.Equal(t, "TestMessage", message.MessageType)
 	assert.Equal(t, []byte("This is a test message"), message.Message)
 	assert.NotEqual(t, "", message.MessageId)
+	message.VisibleTime = time.Now().Add(time.Minute)
+	message.DeliveryCount = 2
+	message.SetHeader("tenant_id", "1")
 
 	buffer, err := json.Marshal(message)
 	assert.Nil(t, err)
@@ -37,6 +44,10 @@ func (c *messageEnvelopeTest) TestSerializeMessage(t *testing.T) {
 	assert.Equal(t, message.TraceId, message2.TraceId)
 	assert.Equal(t, message.MessageType, message2.MessageType)
 	assert.Equal(t, message.Message, message2.Message)
+	assert.WithinDuration(t, message.VisibleTime, message2.VisibleTime, time.Second)
+	assert.Equal(t, 2, message2.DeliveryCount)
+	assert.Equal(t, "1", message2.GetHeader("tenant_id"))
+	assert.Equal(t, "", message2.GetHeader("schema_version"))
 }
 
 func (c *messageEnvelopeTest) TestMessageEnvelopMethods(t *testing.T) {
@@ -56,9 +67,61 @@ func (c *messageEnvelopeTest) TestMessageEnvelopMethods(t *testing.T) {
 	assert.Equal(t, testObj, resultObj)
 }
 
+func (c *messageEnvelopeTest) TestEncodedMessage(t *testing.T) {
+	testObj := testType{Value: "This is a test message"}
+
+	message, err := queues.NewMessageEnvelopeFromEncodedObject("123", "TestMessage", testObj, codecs.MessagePackContentType)
+	assert.Nil(t, err)
+	assert.Equal(t, codecs.MessagePackContentType, message.GetContentType())
+
+	// The content type is kept when the envelope is passed as JSON
+	buffer, err := json.Marshal(message)
+	assert.Nil(t, err)
+	message2 := queues.NewEmptyMessageEnvelope()
+	err = json.Unmarshal(buffer, message2)
+	assert.Nil(t, err)
+
+	resultObj, err := queues.GetMessageAs[testType](message2)
+	assert.Nil(t, err)
+	assert.Equal(t, testObj, resultObj)
+
+	_, err = queues.NewMessageEnvelopeFromEncodedObject("123", "TestMessage", testObj, "application/unknown")
+	assert.NotNil(t, err)
+
+	message.SetHeader(queues.ContentTypeHeader, "application/unknown")
+	_, err = queues.GetMessageAs[testType](message)
+	assert.NotNil(t, err)
+}
+
+func (c *messageEnvelopeTest) TestSendEncodedObject(t *testing.T) {
+	ctx := context.Background()
+
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Configure(ctx, cconf.NewConfigParamsFromTuples(
+		"options.content_type", codecs.MessagePackContentType,
+	))
+	assert.Nil(t, queue.Open(ctx))
+	defer queue.Close(ctx)
+
+	testObj := testType{Value: "This is a test message"}
+	err := queue.SendAsObject(ctx, "TestMessage", testObj)
+	assert.Nil(t, err)
+
+	message, err := queue.Receive(ctx, time.Second)
+	assert.Nil(t, err)
+	assert.NotNil(t, message)
+	assert.Equal(t, codecs.MessagePackContentType, message.GetContentType())
+
+	resultObj, err := queues.GetMessageAs[testType](message)
+	assert.Nil(t, err)
+	assert.Equal(t, testObj, resultObj)
+}
+
 func TestMessageEnvelop(t *testing.T) {
 	test := NewMessageEnvelopTest()
 
 	t.Run("MessageEnvelop:Serialize Message", test.TestSerializeMessage)
 	t.Run("MessageEnvelop:Methods", test.TestMessageEnvelopMethods)
+	t.Run("MessageEnvelop:Encoded Message", test.TestEncodedMessage)
+	t.Run("MessageEnvelop:Send Encoded Object", test.TestSendEncodedObject)
 }
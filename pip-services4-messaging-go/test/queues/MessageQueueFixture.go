@@ -7,6 +7,7 @@ import (
 
 	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
 	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/test"
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -152,3 +153,87 @@ func (c *MessageQueueFixture) TestOnMessage(t *testing.T) {
 
 	c.queue.EndListen(context.TODO())
 }
+
+func (c *MessageQueueFixture) TestTraceContextPropagation(t *testing.T) {
+	ctx := ctrace.NewContextWithSpanContext(context.Background(), ctrace.SpanContext{
+		TraceId:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanId:     "00f067aa0ba902b7",
+		TraceFlags: ctrace.TraceFlagsSampled,
+	})
+	ctx = ctrace.NewContextWithBaggageValue(ctx, "userId", "alice")
+
+	envelope1 := queues.NewMessageEnvelope("123", "Test", []byte("Test message"))
+	receiver := test.NewTestMessageReceiver()
+	c.queue.BeginListen(context.TODO(), receiver)
+
+	time.Sleep(500 * time.Millisecond)
+
+	sndErr := c.queue.Send(ctx, envelope1)
+	assert.Nil(t, sndErr)
+
+	time.Sleep(500 * time.Millisecond)
+
+	contexts := receiver.GetContexts()
+	assert.Len(t, contexts, 1)
+	envelope2 := receiver.GetMessages()[0]
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", envelope2.GetHeader(ctrace.TraceParentHeader))
+
+	spanContext, ok := ctrace.GetSpanContext(contexts[0])
+	assert.True(t, ok)
+	assert.True(t, spanContext.Remote)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceId)
+	assert.Equal(t, "00f067aa0ba902b7", spanContext.SpanId)
+	assert.Equal(t, "alice", ctrace.GetBaggageValue(contexts[0], "userId"))
+
+	c.queue.EndListen(context.TODO())
+}
+
+func (c *MessageQueueFixture) TestSendDelayedMessage(t *testing.T) {
+	envelope1 := queues.NewMessageEnvelope("123", "Test", []byte("Test message"))
+	start := time.Now()
+	sndErr := c.queue.SendWithOptions(context.TODO(), envelope1, queues.NewDelaySendOptions(500*time.Millisecond))
+	assert.Nil(t, sndErr)
+
+	if c.queue.Capabilities().CanPeek() {
+		envelope2, pkErr := c.queue.Peek(context.TODO())
+		assert.Nil(t, pkErr)
+		assert.Nil(t, envelope2)
+	}
+
+	envelope2, rcvErr := c.queue.Receive(context.TODO(), 10000*time.Millisecond)
+	assert.Nil(t, rcvErr)
+	assert.NotNil(t, envelope2)
+	assert.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, envelope1.MessageType, envelope2.MessageType)
+	assert.Equal(t, envelope1.Message, envelope2.Message)
+	assert.Equal(t, envelope1.TraceId, envelope2.TraceId)
+}
+
+func (c *MessageQueueFixture) TestSendReceiveBatch(t *testing.T) {
+	envelopes := []*queues.MessageEnvelope{
+		queues.NewMessageEnvelope("123", "Test", []byte("Test message 1")),
+		queues.NewMessageEnvelope("123", "Test", []byte("Test message 2")),
+		queues.NewMessageEnvelope("123", "Test", []byte("Test message 3")),
+	}
+	sndErr := c.queue.SendBatch(context.Background(), envelopes)
+	assert.Nil(t, sndErr)
+
+	received := make([]*queues.MessageEnvelope, 0)
+	for len(received) < len(envelopes) {
+		batch, rcvErr := c.queue.ReceiveBatch(context.Background(), int64(len(envelopes)-len(received)), 10000*time.Millisecond)
+		assert.Nil(t, rcvErr)
+		if len(batch) == 0 {
+			break
+		}
+		received = append(received, batch...)
+	}
+
+	assert.Len(t, received, len(envelopes))
+	for index, envelope := range received {
+		assert.Equal(t, envelopes[index].MessageType, envelope.MessageType)
+		assert.Equal(t, envelopes[index].Message, envelope.Message)
+	}
+
+	cmplErr := c.queue.CompleteBatch(context.Background(), received)
+	assert.Nil(t, cmplErr)
+}
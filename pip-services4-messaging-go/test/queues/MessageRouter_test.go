@@ -0,0 +1,235 @@
+package test_queues
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cvalid "github.com/pip-services4/pip-services4-go/pip-services4-data-go/validate"
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/codecs"
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+type orderCreated struct {
+	Id     string  `json:"id"`
+	Amount float64 `json:"amount"`
+}
+
+func newRouterTestQueues(t *testing.T) (*queues.MemoryMessageQueue, *queues.MemoryMessageQueue) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	deadLetterQueue := queues.NewMemoryMessageQueue("TestDeadLetterQueue")
+	queue.DeadLetterQueue = deadLetterQueue
+
+	assert.Nil(t, queue.Open(context.TODO()))
+	assert.Nil(t, deadLetterQueue.Open(context.TODO()))
+	return queue, deadLetterQueue
+}
+
+func routeNextMessage(t *testing.T, queue queues.IMessageQueue, router *queues.MessageRouter) error {
+	envelope, err := queue.Receive(context.TODO(), 1000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope)
+	return router.ReceiveMessage(context.TODO(), envelope, queue)
+}
+
+func TestMessageRouterTypedHandlers(t *testing.T) {
+	queue, deadLetterQueue := newRouterTestQueues(t)
+	defer queue.Close(context.TODO())
+	defer deadLetterQueue.Close(context.TODO())
+
+	var order orderCreated
+	var deletedId string
+
+	router := queues.NewMessageRouter()
+	queues.RegisterMessageHandler(router, "order.created", nil,
+		func(ctx context.Context, message orderCreated, envelope *queues.MessageEnvelope, queue queues.IMessageQueue) error {
+			order = message
+			return queue.Complete(ctx, envelope)
+		})
+	queues.RegisterMessageHandler(router, "order.deleted", nil,
+		func(ctx context.Context, message string, envelope *queues.MessageEnvelope, queue queues.IMessageQueue) error {
+			deletedId = message
+			return queue.Complete(ctx, envelope)
+		})
+
+	assert.True(t, router.IsRegistered("order.created"))
+	assert.False(t, router.IsRegistered("order.updated"))
+
+	err := queue.SendAsObject(context.TODO(), "order.created", orderCreated{Id: "1", Amount: 10.5})
+	assert.Nil(t, err)
+	err = queue.SendAsObject(context.TODO(), "order.deleted", "2")
+	assert.Nil(t, err)
+
+	assert.Nil(t, routeNextMessage(t, queue, router))
+	assert.Nil(t, routeNextMessage(t, queue, router))
+
+	assert.Equal(t, orderCreated{Id: "1", Amount: 10.5}, order)
+	assert.Equal(t, "2", deletedId)
+}
+
+func TestMessageRouterValidation(t *testing.T) {
+	queue, deadLetterQueue := newRouterTestQueues(t)
+	defer queue.Close(context.TODO())
+	defer deadLetterQueue.Close(context.TODO())
+
+	handled := 0
+	router := queues.NewMessageRouter()
+	schema := cvalid.NewObjectSchema().
+		WithRequiredProperty("id", cconv.String).
+		WithOptionalProperty("amount", cconv.Float)
+	queues.RegisterMessageHandler(router, "order.created", schema,
+		func(ctx context.Context, message orderCreated, envelope *queues.MessageEnvelope, queue queues.IMessageQueue) error {
+			handled++
+			return queue.Complete(ctx, envelope)
+		})
+
+	err := queue.SendAsObject(context.TODO(), "order.created", map[string]any{"amount": 10})
+	assert.Nil(t, err)
+	envelope := queues.NewMessageEnvelope("123", "order.created", []byte("not json"))
+	err = queue.Send(context.TODO(), envelope)
+	assert.Nil(t, err)
+
+	// Invalid messages are moved to dead letter queue
+	assert.Nil(t, routeNextMessage(t, queue, router))
+	assert.Nil(t, routeNextMessage(t, queue, router))
+	assert.Equal(t, 0, handled)
+
+	count, err := deadLetterQueue.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestMessageRouterValidatesEncodedMessages(t *testing.T) {
+	queue, deadLetterQueue := newRouterTestQueues(t)
+	defer queue.Close(context.TODO())
+	defer deadLetterQueue.Close(context.TODO())
+
+	var order orderCreated
+	router := queues.NewMessageRouter()
+	schema := cvalid.NewObjectSchema().
+		WithRequiredProperty("id", cconv.String).
+		WithOptionalProperty("amount", cconv.Float)
+	queues.RegisterMessageHandler(router, "order.created", schema,
+		func(ctx context.Context, message orderCreated, envelope *queues.MessageEnvelope, queue queues.IMessageQueue) error {
+			order = message
+			return queue.Complete(ctx, envelope)
+		})
+
+	envelope, err := queues.NewMessageEnvelopeFromEncodedObject("123", "order.created",
+		orderCreated{Id: "1", Amount: 10.5}, codecs.MessagePackContentType)
+	assert.Nil(t, err)
+	assert.Nil(t, queue.Send(context.TODO(), envelope))
+	envelope, err = queues.NewMessageEnvelopeFromEncodedObject("123", "order.created",
+		map[string]any{"amount": 10.5}, codecs.MessagePackContentType)
+	assert.Nil(t, err)
+	assert.Nil(t, queue.Send(context.TODO(), envelope))
+
+	// The valid message is decoded by its codec and passed to the handler
+	assert.Nil(t, routeNextMessage(t, queue, router))
+	assert.Equal(t, orderCreated{Id: "1", Amount: 10.5}, order)
+
+	// The message without required property is moved to dead letter queue
+	assert.Nil(t, routeNextMessage(t, queue, router))
+	count, err := deadLetterQueue.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestMessageRouterValidatesProtobufMessages(t *testing.T) {
+	queue, deadLetterQueue := newRouterTestQueues(t)
+	defer queue.Close(context.TODO())
+	defer deadLetterQueue.Close(context.TODO())
+
+	var order map[string]any
+	var field *typepb.Field
+	router := queues.NewMessageRouter()
+	queues.RegisterMessageHandler(router, "order.created",
+		cvalid.NewObjectSchema().
+			WithRequiredProperty("id", cconv.String).
+			WithOptionalProperty("amount", cconv.Float),
+		func(ctx context.Context, message *structpb.Struct, envelope *queues.MessageEnvelope, queue queues.IMessageQueue) error {
+			order = message.AsMap()
+			return queue.Complete(ctx, envelope)
+		})
+	queues.RegisterMessageHandler(router, "field.added",
+		cvalid.NewObjectSchema().
+			WithRequiredProperty("name", cconv.String).
+			WithRequiredProperty("number", cconv.Integer).
+			WithRequiredProperty("kind", cconv.String).
+			WithRequiredProperty("options", cconv.Array),
+		func(ctx context.Context, message *typepb.Field, envelope *queues.MessageEnvelope, queue queues.IMessageQueue) error {
+			field = message
+			return queue.Complete(ctx, envelope)
+		})
+
+	message, err := structpb.NewStruct(map[string]any{"id": "1", "amount": 10.5})
+	assert.Nil(t, err)
+	envelope, err := queues.NewMessageEnvelopeFromEncodedObject("123", "order.created", message, codecs.ProtobufContentType)
+	assert.Nil(t, err)
+	assert.Nil(t, queue.Send(context.TODO(), envelope))
+
+	message, err = structpb.NewStruct(map[string]any{"amount": 10.5})
+	assert.Nil(t, err)
+	envelope, err = queues.NewMessageEnvelopeFromEncodedObject("123", "order.created", message, codecs.ProtobufContentType)
+	assert.Nil(t, err)
+	assert.Nil(t, queue.Send(context.TODO(), envelope))
+
+	envelope, err = queues.NewMessageEnvelopeFromEncodedObject("123", "field.added",
+		&typepb.Field{Name: "id", Number: 1, Kind: typepb.Field_TYPE_STRING}, codecs.ProtobufContentType)
+	assert.Nil(t, err)
+	assert.Nil(t, queue.Send(context.TODO(), envelope))
+
+	// Valid messages are decoded into the handler types
+	assert.Nil(t, routeNextMessage(t, queue, router))
+	assert.Equal(t, map[string]any{"id": "1", "amount": 10.5}, order)
+
+	// The message without required property is moved to dead letter queue
+	assert.Nil(t, routeNextMessage(t, queue, router))
+	count, err := deadLetterQueue.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// Fields are validated by their names in .proto files
+	assert.Nil(t, routeNextMessage(t, queue, router))
+	assert.NotNil(t, field)
+	assert.Equal(t, "id", field.Name)
+	count, err = deadLetterQueue.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestMessageRouterUnknownTypes(t *testing.T) {
+	queue, deadLetterQueue := newRouterTestQueues(t)
+	defer queue.Close(context.TODO())
+	defer deadLetterQueue.Close(context.TODO())
+
+	router := queues.NewMessageRouter()
+
+	// Ignored messages are removed from the queue
+	assert.Nil(t, queue.SendAsObject(context.TODO(), "unknown", "value"))
+	assert.Nil(t, routeNextMessage(t, queue, router))
+	count, _ := queue.ReadMessageCount()
+	assert.Equal(t, int64(0), count)
+
+	// Abandoned messages are returned into the queue
+	router.Configure(context.TODO(), cconf.NewConfigParamsFromTuples(
+		"options.unknown_type", queues.UnknownMessageAbandon,
+	))
+	assert.Nil(t, queue.SendAsObject(context.TODO(), "unknown", "value"))
+	assert.Nil(t, routeNextMessage(t, queue, router))
+	count, _ = queue.ReadMessageCount()
+	assert.Equal(t, int64(1), count)
+
+	// Dead letter messages are moved to dead letter queue
+	router.UnknownType = queues.UnknownMessageDeadLetter
+	assert.Nil(t, routeNextMessage(t, queue, router))
+	count, _ = queue.ReadMessageCount()
+	assert.Equal(t, int64(0), count)
+	count, _ = deadLetterQueue.ReadMessageCount()
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,59 @@
+package test_queues
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxPublisher(t *testing.T) {
+	queue := queues.NewMemoryMessageQueue("TestQueue")
+	queue.Open(context.Background())
+	defer queue.Close(context.Background())
+
+	outbox := cpersist.NewMemoryChangeOutbox()
+	persistence := cpersist.NewIdentifiableMemoryPersistence[changeDummy, string]()
+	persistence.AddChangeListener(outbox)
+
+	publisher := queues.NewOutboxPublisher(outbox, queue)
+	publisher.BatchSize = 2
+
+	for _, id := range []string{"1", "2", "3"} {
+		_, err := persistence.Create(context.Background(), changeDummy{Id: id, Key: "Key " + id})
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 3, outbox.GetCount())
+
+	count, err := publisher.Publish(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 0, outbox.GetCount())
+
+	messageCount, err := queue.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), messageCount)
+
+	envelope, err := queue.Receive(context.Background(), 1000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope)
+	assert.Equal(t, cpersist.ChangeTypeCreated, envelope.MessageType)
+	assert.NotEmpty(t, envelope.MessageId)
+
+	event, err := queues.GetMessageAs[cpersist.ChangeEvent](envelope)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", event.Id)
+
+	// Background publishing
+	publisher.Interval = 50 * time.Millisecond
+	publisher.Start(context.Background())
+	defer publisher.Stop(context.Background())
+
+	_, err = persistence.Create(context.Background(), changeDummy{Id: "4", Key: "Key 4"})
+	assert.Nil(t, err)
+
+	assert.Eventually(t, func() bool { return outbox.GetCount() == 0 }, 2*time.Second, 50*time.Millisecond)
+}
@@ -6,7 +6,10 @@ import (
 
 	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
 	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
 	"github.com/pip-services4/pip-services4-go/pip-services4-data-go/keys"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	mngoptions "go.mongodb.org/mongo-driver/mongo/options"
@@ -43,6 +46,8 @@ import (
 //			- auto_reconnect:            (optional) enable auto reconnection (default: true) (not used)
 //			- reconnect_interval:        (optional) reconnection interval in milliseconds (default: 1000) (not used)
 //			- max_page_size:             (optional) maximum page size (default: 100)
+//			- versioned:                 (optional) turns on optimistic concurrency for items that implement IVersioned (default: false)
+//			- soft_delete:               (optional) mark items that implement ITrackable as deleted instead of removing them (default: false)
 //			- replica_set:               (optional) name of replica set
 //			- ssl:                       (optional) enable SSL connection (default: false) (not implements in this release)
 //			- auth_source:               (optional) authentication source
@@ -98,6 +103,10 @@ type IdentifiableMongoDbPersistence[T any, K any] struct {
 
 	// Flag to turn on automated string ID generation
 	_autoGenerateId bool
+
+	// Versioned turns on optimistic concurrency. When items implement IVersioned
+	// they are updated only when their version matches the stored one.
+	Versioned bool
 }
 
 // InheritIdentifiableMongoDbPersistence is creates a new instance of the persistence component.
@@ -124,6 +133,30 @@ func InheritIdentifiableMongoDbPersistence[T any, K any](overrides IMongoDbPersi
 func (c *IdentifiableMongoDbPersistence[T, K]) Configure(ctx context.Context, config *cconf.ConfigParams) {
 	c.MongoDbPersistence.Configure(ctx, config)
 	c.maxPageSize = (int32)(config.GetAsIntegerWithDefault("options.max_page_size", (int)(c.maxPageSize)))
+	c.Versioned = config.GetAsBooleanWithDefault("options.versioned", c.Versioned)
+}
+
+// GetPageByFilterWithToken is gets a page of data items retrieved by a given filter using keyset paging.
+// The "_id" field is added to sort parameters when it is missing to make the order unique.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to Trace execution through call chain.
+//		- filter any (optional) a filter JSON object
+//		- paging cquery.TokenizedPagingParams paging parameters with a token from the previous page
+//		- sort cquery.SortParams sort parameters
+//		- select  any (optional) projection BSON object, it must include the sort fields
+//	Returns: page cquery.TokenizedDataPage[T], err error a data page or error, if they are occurred
+func (c *IdentifiableMongoDbPersistence[T, K]) GetPageByFilterWithToken(ctx context.Context,
+	filter any, paging cquery.TokenizedPagingParams, sort cquery.SortParams, sel any) (page cquery.TokenizedDataPage[T], err error) {
+
+	hasId := false
+	for _, field := range sort {
+		hasId = hasId || field.Name == "_id"
+	}
+	if !hasId {
+		sort = append(append(cquery.SortParams{}, sort...), cquery.NewSortField("_id", true))
+	}
+	return c.MongoDbPersistence.GetPageByFilterWithToken(ctx, filter, paging, sort, sel)
 }
 
 // GetListByIds is gets a list of data items retrieved by given unique ids.
@@ -142,6 +175,7 @@ func (c *IdentifiableMongoDbPersistence[T, K]) GetListByIds(ctx context.Context,
 }
 
 // GetOneById is gets a data item by its unique id.
+// In soft-delete mode deleted items are not returned unless the context is created by cpersist.WithDeleted.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to Trace execution through call chain.
@@ -150,7 +184,7 @@ func (c *IdentifiableMongoDbPersistence[T, K]) GetListByIds(ctx context.Context,
 func (c *IdentifiableMongoDbPersistence[T, K]) GetOneById(ctx context.Context,
 	id K) (item T, err error) {
 
-	filter := bson.M{"_id": id}
+	filter := c.filterDeleted(ctx, bson.M{"_id": id})
 	var docPointer map[string]any
 
 	res := c.Collection.FindOne(ctx, filter)
@@ -181,7 +215,7 @@ func (c *IdentifiableMongoDbPersistence[T, K]) Create(ctx context.Context,
 	item T) (result T, err error) {
 	var defaultValue T
 
-	newItem, err := c.Overrides.ConvertFromPublic(item)
+	newItem, err := c.Overrides.ConvertFromPublic(c.trackItem(item, true))
 	if err != nil {
 		return defaultValue, err
 	}
@@ -218,7 +252,7 @@ func (c *IdentifiableMongoDbPersistence[T, K]) Set(ctx context.Context,
 	item T) (result T, err error) {
 	var defaultValue T
 
-	newItem, err := c.Overrides.ConvertFromPublic(item)
+	newItem, err := c.Overrides.ConvertFromPublic(c.trackItem(item, true))
 	if err != nil {
 		return defaultValue, err
 	}
@@ -258,6 +292,9 @@ func (c *IdentifiableMongoDbPersistence[T, K]) Set(ctx context.Context,
 }
 
 // Update is updates a data item.
+// When Versioned option is on and the item implements IVersioned the item is updated
+// only when its version matches the stored one, and the version is incremented.
+// ConflictError is returned when versions do not match.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to Trace execution through call chain.
@@ -266,6 +303,7 @@ func (c *IdentifiableMongoDbPersistence[T, K]) Set(ctx context.Context,
 func (c *IdentifiableMongoDbPersistence[T, K]) Update(ctx context.Context,
 	item T) (result T, err error) {
 
+	item = c.trackItem(item, false)
 	newItem, err := c.Overrides.ConvertFromPublic(item)
 	if err != nil {
 		return result, err
@@ -273,6 +311,13 @@ func (c *IdentifiableMongoDbPersistence[T, K]) Update(ctx context.Context,
 	id := newItem["_id"]
 
 	filter := bson.M{"_id": id}
+	version, versioned := "", false
+	if c.Versioned {
+		if version, versioned = cpersist.GetObjectVersion(item); versioned {
+			filter = composeVersionFilter(id, version)
+			newItem["version"] = cpersist.NextVersion(version)
+		}
+	}
 	update := bson.D{{"$set", newItem}}
 
 	var options mngoptions.FindOneAndUpdateOptions
@@ -282,6 +327,9 @@ func (c *IdentifiableMongoDbPersistence[T, K]) Update(ctx context.Context,
 	res := c.Collection.FindOneAndUpdate(ctx, filter, update, &options)
 	if err := res.Err(); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
+			if versioned {
+				return result, c.checkVersionConflict(ctx, id)
+			}
 			return result, nil
 		}
 		return result, err
@@ -301,6 +349,8 @@ func (c *IdentifiableMongoDbPersistence[T, K]) Update(ctx context.Context,
 }
 
 // UpdatePartially is updates only few selected fields in a data item.
+// When Versioned option is on the version is taken from "version" field in the data
+// or from the stored item, and ConflictError is returned when it does not match.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to Trace execution through call chain.
@@ -310,11 +360,25 @@ func (c *IdentifiableMongoDbPersistence[T, K]) Update(ctx context.Context,
 func (c *IdentifiableMongoDbPersistence[T, K]) UpdatePartially(ctx context.Context,
 	id K, data cdata.AnyValueMap) (item T, err error) {
 
+	data = c.trackPartialData(data)
 	newItem := bson.M{}
 	for k, v := range data.Value() {
 		newItem[k] = v
 	}
-	filter := bson.M{"_id": id}
+	var filter any = bson.M{"_id": id}
+	versioned := c.Versioned && cpersist.IsVersionedType[T]()
+	if versioned {
+		version, ok := data.GetAsNullableString("version")
+		if !ok {
+			current, err := c.GetOneById(ctx, id)
+			if err != nil {
+				return item, err
+			}
+			version, _ = cpersist.GetObjectVersion(current)
+		}
+		filter = composeVersionFilter(id, version)
+		newItem["version"] = cpersist.NextVersion(version)
+	}
 	update := bson.D{{"$set", newItem}}
 
 	var options mngoptions.FindOneAndUpdateOptions
@@ -324,6 +388,9 @@ func (c *IdentifiableMongoDbPersistence[T, K]) UpdatePartially(ctx context.Conte
 	res := c.Collection.FindOneAndUpdate(ctx, filter, update, &options)
 	if err := res.Err(); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
+			if versioned {
+				return item, c.checkVersionConflict(ctx, id)
+			}
 			return item, nil
 		}
 		return item, err
@@ -342,6 +409,7 @@ func (c *IdentifiableMongoDbPersistence[T, K]) UpdatePartially(ctx context.Conte
 }
 
 // DeleteById is deleted a data item by it's unique id.
+// In soft-delete mode the item is marked as deleted instead.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to Trace execution through call chain.
@@ -350,6 +418,21 @@ func (c *IdentifiableMongoDbPersistence[T, K]) UpdatePartially(ctx context.Conte
 func (c *IdentifiableMongoDbPersistence[T, K]) DeleteById(ctx context.Context,
 	id K) (item T, err error) {
 
+	if c.isSoftDelete() {
+		return c.markDeletedById(ctx, id, true)
+	}
+	return c.PurgeById(ctx, id)
+}
+
+// PurgeById is physically deletes a data item by it's unique id regardless of soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to Trace execution through call chain.
+//		- id K id of the item to be deleted
+//	Returns: item T, err error deleted item and error, if they are occurred
+func (c *IdentifiableMongoDbPersistence[T, K]) PurgeById(ctx context.Context,
+	id K) (item T, err error) {
+
 	filter := bson.M{"_id": id}
 
 	res := c.Collection.FindOneAndDelete(ctx, filter)
@@ -373,7 +456,49 @@ func (c *IdentifiableMongoDbPersistence[T, K]) DeleteById(ctx context.Context,
 	return c.Overrides.ConvertToPublic(docPointer)
 }
 
+// RestoreById is restores a data item marked as deleted in soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to Trace execution through call chain.
+//		- id K id of the item to be restored
+//	Returns: item T, err error restored item and error, if they are occurred
+func (c *IdentifiableMongoDbPersistence[T, K]) RestoreById(ctx context.Context,
+	id K) (item T, err error) {
+
+	return c.markDeletedById(ctx, id, false)
+}
+
+func (c *IdentifiableMongoDbPersistence[T, K]) markDeletedById(ctx context.Context,
+	id K, deleted bool) (item T, err error) {
+
+	var options mngoptions.FindOneAndUpdateOptions
+	retDoc := mngoptions.After
+	options.ReturnDocument = &retDoc
+
+	res := c.Collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, c.composeDeletedUpdate(deleted), &options)
+	if err := res.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return item, nil
+		}
+		return item, err
+	}
+
+	if deleted {
+		c.Logger.Trace(ctx, "Marked as deleted in %s with id = %s", c.CollectionName, id)
+	} else {
+		c.Logger.Trace(ctx, "Restored in %s with id = %s", c.CollectionName, id)
+	}
+
+	var docPointer map[string]any
+	if err := res.Decode(&docPointer); err != nil {
+		return item, err
+	}
+
+	return c.Overrides.ConvertToPublic(docPointer)
+}
+
 // DeleteByIds is deletes multiple data items by their unique ids.
+// In soft-delete mode the items are marked as deleted instead.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to Trace execution through call chain.
@@ -387,3 +512,14 @@ func (c *IdentifiableMongoDbPersistence[T, K]) DeleteByIds(ctx context.Context,
 	}
 	return c.DeleteByFilter(ctx, filter)
 }
+
+func (c *IdentifiableMongoDbPersistence[T, K]) checkVersionConflict(ctx context.Context, id any) error {
+	count, err := c.Collection.CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return cpersist.NewVersionConflictError(cctx.GetTraceId(ctx), id)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package persistence
+
+import (
+	"encoding/base64"
+	"strings"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type keysetToken struct {
+	Values bson.A `bson:"v"`
+}
+
+// composeKeysetSort converts sort parameters into a sorting BSON object.
+func composeKeysetSort(sort cquery.SortParams) bson.D {
+	result := bson.D{}
+	for _, field := range sort {
+		direction := 1
+		if !field.Ascending {
+			direction = -1
+		}
+		result = append(result, bson.E{Key: field.Name, Value: direction})
+	}
+	return result
+}
+
+// composeKeysetToken encodes values of sort fields taken from a raw document into a token.
+// Values are stored in extended JSON to keep their BSON types, e.g. ObjectID or DateTime.
+func composeKeysetToken(doc bson.Raw, sort cquery.SortParams) (string, error) {
+	token := keysetToken{Values: make(bson.A, len(sort))}
+	for i, field := range sort {
+		value, err := doc.LookupErr(strings.Split(field.Name, ".")...)
+		if err == nil {
+			token.Values[i] = value
+		}
+	}
+
+	buf, err := bson.MarshalExtJSON(token, true, false)
+	if err != nil {
+		return cquery.EmptyTokenValue, err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// composeKeysetFilter creates a filter that selects documents located after the document
+// encoded in the token according to the sort order. It returns nil for the first page.
+func composeKeysetFilter(token string, sort cquery.SortParams) (bson.M, error) {
+	if token == cquery.EmptyTokenValue {
+		return nil, nil
+	}
+
+	var decoded keysetToken
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = bson.UnmarshalExtJSON(buf, true, &decoded)
+	}
+	if err != nil {
+		return nil, cerr.NewBadRequestError("", "INVALID_TOKEN", "Paging token is invalid").WithCause(err)
+	}
+	if len(decoded.Values) != len(sort) {
+		return nil, cerr.NewBadRequestError("", "INVALID_TOKEN", "Paging token does not match sort parameters")
+	}
+
+	// For fields f1, f2 the filter is (f1 > v1) OR (f1 = v1 AND f2 > v2)
+	groups := bson.A{}
+	for i, field := range sort {
+		group := bson.M{}
+		for j := 0; j < i; j++ {
+			group[sort[j].Name] = decoded.Values[j]
+		}
+
+		value := decoded.Values[i]
+		if value == nil {
+			// Nulls go first in ascending order, so all non-null values follow them
+			if !field.Ascending {
+				continue
+			}
+			group[field.Name] = bson.M{"$ne": nil}
+		} else if field.Ascending {
+			group[field.Name] = bson.M{"$gt": value}
+		} else {
+			group[field.Name] = bson.M{"$lt": value}
+		}
+		groups = append(groups, group)
+	}
+
+	if len(groups) == 0 {
+		// Nothing can follow the last document
+		return bson.M{"_id": bson.M{"$in": bson.A{}}}, nil
+	}
+	return bson.M{"$or": groups}, nil
+}
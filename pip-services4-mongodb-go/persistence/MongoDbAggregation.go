@@ -0,0 +1,173 @@
+package persistence
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+
+	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var aggregateNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ComposeAggregatePipeline converts aggregate parameters into an aggregation pipeline
+// with $match, $group and $sort stages. Group fields are returned in "_id" document
+// as "g0", "g1", ... and aggregates as "a0", "a1", ... in the order they are defined.
+//
+//	Parameters:
+//		- filter any (optional) a filter BSON object
+//		- params cquery.AggregateParams group fields and aggregate functions
+//	Returns: bson.A aggregation pipeline or BadRequestError if the parameters are invalid.
+func (c *MongoDbPersistence[T]) ComposeAggregatePipeline(filter any, params cquery.AggregateParams) (bson.A, error) {
+	if len(params.GroupBy) == 0 && len(params.Fields) == 0 {
+		return nil, cerr.NewBadRequestError("", "INVALID_AGGREGATE",
+			"Aggregation requires group fields or aggregate functions")
+	}
+
+	toFieldPath := func(field string) (string, error) {
+		if !fieldPathRegex.MatchString(field) {
+			return "", cerr.NewBadRequestError("", "INVALID_AGGREGATE",
+				"Field "+field+" is not a valid name").
+				WithDetails("field", field)
+		}
+		if field == "id" {
+			field = "_id"
+		}
+		return "$" + field, nil
+	}
+
+	var groupId any
+	sort := bson.D{}
+	if len(params.GroupBy) > 0 {
+		keys := bson.M{}
+		for i, field := range params.GroupBy {
+			path, err := toFieldPath(field)
+			if err != nil {
+				return nil, err
+			}
+			key := "g" + strconv.Itoa(i)
+			keys[key] = path
+			sort = append(sort, bson.E{Key: "_id." + key, Value: 1})
+		}
+		groupId = keys
+	}
+
+	group := bson.M{"_id": groupId}
+	names := make(map[string]bool, len(params.Fields))
+	for i, field := range params.Fields {
+		if !aggregateNameRegex.MatchString(field.Name) || names[field.Name] {
+			return nil, cerr.NewBadRequestError("", "INVALID_AGGREGATE",
+				"Aggregate name "+field.Name+" is not valid or not unique").
+				WithDetails("name", field.Name)
+		}
+		names[field.Name] = true
+
+		var aggregate bson.M
+		if field.Function == cquery.AggregateCount && field.Field == "" {
+			aggregate = bson.M{"$sum": 1}
+		} else {
+			path, err := toFieldPath(field.Field)
+			if err != nil {
+				return nil, err
+			}
+			switch field.Function {
+			case cquery.AggregateCount:
+				aggregate = bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{path, nil}}, 1, 0}}}
+			case cquery.AggregateSum, cquery.AggregateAvg, cquery.AggregateMin, cquery.AggregateMax:
+				aggregate = bson.M{"$" + field.Function: path}
+			default:
+				return nil, cerr.NewBadRequestError("", "INVALID_AGGREGATE",
+					"Aggregate function "+field.Function+" is not supported").
+					WithDetails("function", field.Function)
+			}
+		}
+		group["a"+strconv.Itoa(i)] = aggregate
+	}
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+	pipeline := bson.A{bson.M{"$match": filter}, bson.M{"$group": group}}
+	if len(sort) > 0 {
+		pipeline = append(pipeline, bson.M{"$sort": sort})
+	}
+	return pipeline, nil
+}
+
+// GetAggregateByFilter calculates aggregates over data items retrieved by a given filter
+// and grouped by the group fields using an aggregation pipeline. The result rows are sorted by the group fields.
+// This method shall be called by a func (c *IdentifiableMongoDbPersistence) GetAggregateByFilter method from child type that
+// receives FilterParams and converts them into a filter function.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to Trace execution through call chain.
+//		- filter any (optional) a filter BSON object
+//		- params cquery.AggregateParams group fields and aggregate functions
+//	Returns: rows []cquery.AggregateRow, err error result rows and error, if they are occurred
+func (c *MongoDbPersistence[T]) GetAggregateByFilter(ctx context.Context,
+	filter any, params cquery.AggregateParams) (rows []cquery.AggregateRow, err error) {
+
+	pipeline, err := c.ComposeAggregatePipeline(c.filterDeleted(ctx, filter), params)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := c.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	rows = make([]cquery.AggregateRow, 0)
+	for cursor.Next(ctx) {
+		if c.IsTerminated() {
+			return nil, cerr.
+				NewError("query terminated").
+				WithTraceId(cctx.GetTraceId(ctx))
+		}
+
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		row := cquery.NewAggregateRow(make(map[string]any, len(params.GroupBy)), make(map[string]any, len(params.Fields)))
+		keys, _ := doc["_id"].(bson.M)
+		for i, field := range params.GroupBy {
+			row.Group[field] = keys["g"+strconv.Itoa(i)]
+		}
+		for i, field := range params.Fields {
+			value := doc["a"+strconv.Itoa(i)]
+			if value != nil {
+				switch field.Function {
+				case cquery.AggregateCount:
+					value = cconv.LongConverter.ToLong(value)
+				case cquery.AggregateSum, cquery.AggregateAvg:
+					value = cconv.DoubleConverter.ToDouble(value)
+				}
+			}
+			row.Values[field.Name] = value
+		}
+		rows = append(rows, row)
+	}
+
+	// Like in SQL, aggregates without groups always return one row
+	if len(rows) == 0 && len(params.GroupBy) == 0 {
+		row := cquery.NewAggregateRow(nil, make(map[string]any, len(params.Fields)))
+		for _, field := range params.Fields {
+			if field.Function == cquery.AggregateCount {
+				row.Values[field.Name] = int64(0)
+			} else {
+				row.Values[field.Name] = nil
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	c.Logger.Trace(ctx, "Retrieved %d aggregate rows from %s", len(rows), c.CollectionName)
+	return rows, cursor.Err()
+}
@@ -0,0 +1,206 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	"github.com/pip-services4/pip-services4-go/pip-services4-data-go/keys"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mngoptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateMany creates multiple data items with a single unordered insert.
+// Items that failed to be inserted do not prevent others from being inserted.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to Trace execution through call chain.
+//		- items []T items to be created.
+//	Returns: created items and errors of every item in the order of the given items, or error.
+func (c *IdentifiableMongoDbPersistence[T, K]) CreateMany(ctx context.Context,
+	items []T) ([]T, []error, error) {
+
+	results := make([]T, len(items))
+	errs := make([]error, len(items))
+
+	docs := make([]any, 0, len(items))
+	positions := make([]int, 0, len(items))
+	for i, item := range items {
+		newItem, err := c.prepareBatchItem(item)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		docs = append(docs, newItem)
+		positions = append(positions, i)
+	}
+	if len(docs) == 0 {
+		return results, errs, nil
+	}
+
+	options := mngoptions.InsertMany().SetOrdered(false)
+	_, err := c.Collection.InsertMany(ctx, docs, options)
+	if err = c.collectBatchErrors(err, positions, errs); err != nil {
+		return results, errs, err
+	}
+
+	for j, doc := range docs {
+		if i := positions[j]; errs[i] == nil {
+			if results[i], err = c.Overrides.ConvertToPublic(doc); err != nil {
+				errs[i] = err
+			}
+		}
+	}
+
+	c.Logger.Trace(ctx, "Created %d items in %s", len(docs), c.CollectionName)
+	return results, errs, nil
+}
+
+// UpsertMany sets multiple data items with a single unordered bulk write.
+// The data items that exist are replaced, others are created.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to Trace execution through call chain.
+//		- items []T items to be set.
+//	Returns: set items and errors of every item in the order of the given items, or error.
+func (c *IdentifiableMongoDbPersistence[T, K]) UpsertMany(ctx context.Context,
+	items []T) ([]T, []error, error) {
+
+	results := make([]T, len(items))
+	errs := make([]error, len(items))
+
+	docs := make([]map[string]any, 0, len(items))
+	models := make([]mongo.WriteModel, 0, len(items))
+	positions := make([]int, 0, len(items))
+	for i, item := range items {
+		newItem, err := c.prepareBatchItem(item)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		docs = append(docs, newItem)
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": newItem["_id"]}).
+			SetReplacement(newItem).
+			SetUpsert(true))
+		positions = append(positions, i)
+	}
+	if len(models) == 0 {
+		return results, errs, nil
+	}
+
+	options := mngoptions.BulkWrite().SetOrdered(false)
+	_, err := c.Collection.BulkWrite(ctx, models, options)
+	if err = c.collectBatchErrors(err, positions, errs); err != nil {
+		return results, errs, err
+	}
+
+	for j, doc := range docs {
+		if i := positions[j]; errs[i] == nil {
+			if results[i], err = c.Overrides.ConvertToPublic(doc); err != nil {
+				errs[i] = err
+			}
+		}
+	}
+
+	c.Logger.Trace(ctx, "Set %d items in %s", len(models), c.CollectionName)
+	return results, errs, nil
+}
+
+// UpdateManyPartially updates the same few selected fields in multiple data items with a single update.
+// When Versioned option is on the items are updated one by one to check their versions.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to Trace execution through call chain.
+//		- ids []K ids of data items to be updated.
+//		- data cdata.AnyValueMap a map with fields to be updated.
+//	Returns: updated items and errors of every item in the order of the given ids, or error.
+//	Items that were not found are returned as empty values.
+func (c *IdentifiableMongoDbPersistence[T, K]) UpdateManyPartially(ctx context.Context,
+	ids []K, data cdata.AnyValueMap) ([]T, []error, error) {
+
+	results := make([]T, len(ids))
+	errs := make([]error, len(ids))
+	if len(ids) == 0 {
+		return results, errs, nil
+	}
+
+	if c.Versioned && cpersist.IsVersionedType[T]() {
+		for i, id := range ids {
+			results[i], errs[i] = c.UpdatePartially(ctx, id, data)
+		}
+		return results, errs, nil
+	}
+
+	data = c.trackPartialData(data)
+	newItem := bson.M{}
+	for k, v := range data.Value() {
+		newItem[k] = v
+	}
+	filter := bson.M{"_id": bson.M{"$in": ids}}
+	if _, err := c.Collection.UpdateMany(ctx, filter, bson.M{"$set": newItem}); err != nil {
+		return results, errs, err
+	}
+
+	positions := make(map[string]int, len(ids))
+	for i, id := range ids {
+		positions[cconv.StringConverter.ToString(id)] = i
+	}
+
+	cursor, err := c.Collection.Find(ctx, filter)
+	if err != nil {
+		return results, errs, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var docPointer map[string]any
+		if err := cursor.Decode(&docPointer); err != nil {
+			return results, errs, err
+		}
+		if i, ok := positions[cconv.StringConverter.ToString(docPointer["_id"])]; ok {
+			if results[i], err = c.Overrides.ConvertToPublic(docPointer); err != nil {
+				errs[i] = err
+			}
+		}
+	}
+
+	c.Logger.Trace(ctx, "Updated partially %d items in %s", len(ids), c.CollectionName)
+	return results, errs, cursor.Err()
+}
+
+// prepareBatchItem converts the item and generates its id if needed.
+func (c *IdentifiableMongoDbPersistence[T, K]) prepareBatchItem(item T) (map[string]any, error) {
+	newItem, err := c.Overrides.ConvertFromPublic(c.trackItem(item, true))
+	if err != nil {
+		return nil, err
+	}
+
+	val, ok := newItem["_id"]
+	if (!ok || val == nil || val == "") && c._autoGenerateId {
+		newItem["_id"] = keys.IdGenerator.NextLong()
+	}
+	return newItem, nil
+}
+
+// collectBatchErrors assigns write errors of an unordered bulk operation to the items.
+// It returns other errors as errors of the entire operation.
+func (c *IdentifiableMongoDbPersistence[T, K]) collectBatchErrors(err error, positions []int, errs []error) error {
+	if err == nil {
+		return nil
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return err
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Index >= 0 && writeErr.Index < len(positions) {
+			errs[positions[writeErr.Index]] = writeErr
+		}
+	}
+	return nil
+}
@@ -53,6 +53,7 @@ type IMongoDbPersistenceOverrides[T any] interface {
 //			- auto_reconnect:            (optional) enable auto reconnection (default: true) (not used)
 //			- reconnect_interval:        (optional) reconnection interval in milliseconds (default: 1000) (not used)
 //			- max_page_size:             (optional) maximum page size (default: 100)
+//			- soft_delete:               (optional) mark items that implement ITrackable as deleted instead of removing them (default: false)
 //			- replica_set:               (optional) name of replica set
 //			- ssl:                       (optional) enable SSL connection (default: false) (not implements in this release)
 //			- auth_source:               (optional) authentication source
@@ -154,6 +155,8 @@ type MongoDbPersistence[T any] struct {
 	Db *mongodrv.Database
 	// The MongoDb collection object.
 	Collection *mongodrv.Collection
+	// Defines if deleted items of ITrackable types are only marked as deleted.
+	SoftDelete bool
 
 	// Defines channel which closed before closing persistence and signals about terminating
 	// all going processes
@@ -209,6 +212,7 @@ func (c *MongoDbPersistence[T]) Configure(ctx context.Context, config *cconf.Con
 	c.config = config
 	c.DependencyResolver.Configure(ctx, config)
 	c.CollectionName = config.GetAsStringWithDefault("collection", c.CollectionName)
+	c.SoftDelete = config.GetAsBooleanWithDefault("options.soft_delete", c.SoftDelete)
 }
 
 // SetReferences method are sets references to dependent components.
@@ -474,6 +478,8 @@ func (c *MongoDbPersistence[T]) Clear(ctx context.Context) error {
 //	Returns: page cdata.DataPage[T], err error a data page or error, if they are occurred
 func (c *MongoDbPersistence[T]) GetPageByFilter(ctx context.Context,
 	filter any, paging cquery.PagingParams, sort any, sel any) (page cquery.DataPage[T], err error) {
+	filter = c.filterDeleted(ctx, filter)
+
 	// Adjust max item count based on configuration
 
 	skip := paging.GetSkip(-1)
@@ -536,6 +542,94 @@ func (c *MongoDbPersistence[T]) GetPageByFilter(ctx context.Context,
 	return *cquery.NewDataPage(items, cquery.EmptyTotalValue), nil
 }
 
+// GetPageByFilterWithToken is gets a page of data items retrieved by a given filter and sorted according
+// to sort parameters using keyset paging. Instead of skipping documents the query seeks right after
+// the document encoded in the paging token, so it takes the same time for any page.
+// The returned page contains a token to get the next page, it is empty when there are no more items.
+// This method shall be called by a func (c *IdentifiableMongoDbPersistence) GetPageByFilterWithToken method
+// from child type that receives FilterParams and converts them into a filter function.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to Trace execution through call chain.
+//		- filter any (optional) a filter JSON object
+//		- paging cquery.TokenizedPagingParams paging parameters with a token from the previous page
+//		- sort cquery.SortParams sort parameters, the last field must be unique
+//		- select  any (optional) projection BSON object, it must include the sort fields
+//	Returns: page cquery.TokenizedDataPage[T], err error a data page or error, if they are occurred
+func (c *MongoDbPersistence[T]) GetPageByFilterWithToken(ctx context.Context,
+	filter any, paging cquery.TokenizedPagingParams, sort cquery.SortParams, sel any) (page cquery.TokenizedDataPage[T], err error) {
+
+	if len(sort) == 0 {
+		return *cquery.NewEmptyTokenizedDataPage[T](), cerr.
+			NewBadRequestError(cctx.GetTraceId(ctx), "INVALID_SORT", "Keyset paging requires sort fields")
+	}
+
+	seek, err := composeKeysetFilter(paging.Token, sort)
+	if err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+	filter = c.filterDeleted(ctx, filter)
+	if seek != nil && filter != nil {
+		filter = bson.M{"$and": bson.A{filter, seek}}
+	} else if seek != nil {
+		filter = seek
+	} else if filter == nil {
+		filter = bson.M{}
+	}
+
+	// Read one extra document to find out if there is a next page
+	take := paging.GetTake((int64)(c.maxPageSize))
+	limit := take + 1
+
+	var options mongoopt.FindOptions
+	options.Limit = &limit
+	options.Sort = composeKeysetSort(sort)
+	if sel != nil {
+		options.Projection = sel
+	}
+
+	cursor, err := c.Collection.Find(ctx, filter, &options)
+	if err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+	defer cursor.Close(ctx)
+
+	items := make([]T, 0, 1)
+	var last bson.Raw
+	hasMore := false
+	for cursor.Next(ctx) {
+		if c.IsTerminated() {
+			return *cquery.NewEmptyTokenizedDataPage[T](), cerr.
+				NewError("query terminated").
+				WithTraceId(cctx.GetTraceId(ctx))
+		}
+		if (int64)(len(items)) == take {
+			// The extra document only indicates that the next page exists
+			hasMore = true
+			break
+		}
+
+		var item T
+		if curErr := cursor.Decode(&item); curErr != nil {
+			continue
+		}
+		items = append(items, item)
+		last = append(bson.Raw(nil), cursor.Current...)
+	}
+
+	c.Logger.Trace(ctx, "Retrieved %d from %s", len(items), c.CollectionName)
+
+	token := cquery.EmptyTokenValue
+	if hasMore && last != nil {
+		token, err = composeKeysetToken(last, sort)
+		if err != nil {
+			return *cquery.NewEmptyTokenizedDataPage[T](), err
+		}
+	}
+
+	return *cquery.NewTokenizedDataPage(token, items), nil
+}
+
 // GetListByFilter is gets a list of data items retrieved by a given filter and sorted according to sort parameters.
 // This method shall be called by a func (c *IdentifiableMongoDbPersistence) GetListByFilter method from child type that
 // receives FilterParams and converts them into a filter function.
@@ -549,6 +643,8 @@ func (c *MongoDbPersistence[T]) GetPageByFilter(ctx context.Context,
 func (c *MongoDbPersistence[T]) GetListByFilter(ctx context.Context,
 	filter any, sort any, sel any) (items []T, err error) {
 
+	filter = c.filterDeleted(ctx, filter)
+
 	// Configure options
 	var options mongoopt.FindOptions
 
@@ -608,6 +704,7 @@ func (c *MongoDbPersistence[T]) GetListByFilter(ctx context.Context,
 func (c *MongoDbPersistence[T]) GetOneRandom(ctx context.Context,
 	filter any) (item T, err error) {
 
+	filter = c.filterDeleted(ctx, filter)
 	docCount, err := c.Collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return item, err
@@ -647,6 +744,7 @@ func (c *MongoDbPersistence[T]) GetOneRandom(ctx context.Context,
 //		- item any an item to be created.
 //	Returns: result any, err error created item and error, if they are occurred
 func (c *MongoDbPersistence[T]) Create(ctx context.Context, item T) (result T, err error) {
+	item = c.trackItem(item, true)
 	newItem, err := c.Overrides.ConvertFromPublic(item)
 	if err != nil {
 		return result, err
@@ -665,6 +763,7 @@ func (c *MongoDbPersistence[T]) Create(ctx context.Context, item T) (result T, e
 }
 
 // DeleteByFilter is deletes data items that match to a given filter.
+// In soft-delete mode the items are marked as deleted instead.
 // This method shall be called by a func (c *IdentifiableMongoDbPersistence) deleteByFilter method from child class that
 // receives FilterParams and converts them into a filter function.
 //
@@ -673,6 +772,15 @@ func (c *MongoDbPersistence[T]) Create(ctx context.Context, item T) (result T, e
 //		- filter any (optional) a filter BSON object.
 //	Returns: error or nil for success.
 func (c *MongoDbPersistence[T]) DeleteByFilter(ctx context.Context, filter any) error {
+	if c.isSoftDelete() {
+		res, err := c.Collection.UpdateMany(ctx, c.filterDeleted(ctx, filter), c.composeDeletedUpdate(true))
+		if err != nil {
+			return err
+		}
+		c.Logger.Trace(ctx, "Marked %d items as deleted in %s", res.ModifiedCount, c.CollectionName)
+		return nil
+	}
+
 	res, err := c.Collection.DeleteMany(ctx, filter)
 	if err != nil {
 		return err
@@ -691,6 +799,8 @@ func (c *MongoDbPersistence[T]) DeleteByFilter(ctx context.Context, filter any)
 //	Returns: count int, err error a data count or error, if they are occurred
 func (c *MongoDbPersistence[T]) GetCountByFilter(ctx context.Context, filter any) (count int64, err error) {
 
+	filter = c.filterDeleted(ctx, filter)
+
 	// Configure options
 	var options mongoopt.CountOptions
 	count, err = c.Collection.CountDocuments(ctx, filter, &options)
@@ -0,0 +1,104 @@
+package persistence
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var fieldPathRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// ComposeProjection converts projection parameters into a projection BSON object
+// that can be passed to GetPageByFilter or GetListByFilter as selection.
+// Nested fields are converted into dot paths and "id" field is mapped to "_id".
+// When "id" is not listed the "_id" field is excluded from the results.
+//
+//	Parameters:
+//		- projection *cquery.ProjectionParams projection parameters, nil or empty to select all fields.
+//	Returns: bson.M projection object (nil to select all fields) or BadRequestError if a field name is invalid.
+func (c *MongoDbPersistence[T]) ComposeProjection(projection *cquery.ProjectionParams) (bson.M, error) {
+	if projection == nil || projection.Len() == 0 {
+		return nil, nil
+	}
+
+	paths := make([]string, 0, projection.Len())
+	for _, path := range cquery.ParseProjectionParams(projection.Value()...).Value() {
+		path = strings.ReplaceAll(path, " ", "")
+		if !fieldPathRegex.MatchString(path) {
+			return nil, cerr.NewBadRequestError("", "INVALID_PROJECTION",
+				"Projection field "+path+" is not a valid name").
+				WithDetails("field", path)
+		}
+		if path == "id" || strings.HasPrefix(path, "id.") {
+			path = "_" + path
+		}
+		paths = append(paths, path)
+	}
+
+	// MongoDB rejects a field together with its subfields, so the subfields are dropped
+	sort.Strings(paths)
+	result := bson.M{"_id": 0}
+	parent := ""
+	for _, path := range paths {
+		if parent != "" && (path == parent || strings.HasPrefix(path, parent+".")) {
+			continue
+		}
+		parent = path
+		result[path] = 1
+	}
+	return result, nil
+}
+
+// GetPageByFilterWithProjection is gets a page of data items retrieved by a given filter and sorted according
+// to sort parameters. Only the fields listed in the projection are read, other fields are left empty.
+// This method shall be called by a func (c *IdentifiableMongoDbPersistence) GetPageByFilter method from child type that
+// receives FilterParams and converts them into a filter function.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to Trace execution through call chain.
+//		- filter any (optional) a filter JSON object
+//		- paging cdata.PagingParams (optional) paging parameters
+//		- sort any (optional) sorting BSON object
+//		- projection *cquery.ProjectionParams (optional) projection parameters, e.g. "id,key,content(field1,field2)"
+//	Returns: page cdata.DataPage[T], err error a data page or error, if they are occurred
+func (c *MongoDbPersistence[T]) GetPageByFilterWithProjection(ctx context.Context,
+	filter any, paging cquery.PagingParams, sort any, projection *cquery.ProjectionParams) (page cquery.DataPage[T], err error) {
+
+	sel, err := c.ComposeProjection(projection)
+	if err != nil {
+		return *cquery.NewEmptyDataPage[T](), err
+	}
+	if sel == nil {
+		return c.GetPageByFilter(ctx, filter, paging, sort, nil)
+	}
+	return c.GetPageByFilter(ctx, filter, paging, sort, sel)
+}
+
+// GetListByFilterWithProjection is gets a list of data items retrieved by a given filter and sorted according
+// to sort parameters. Only the fields listed in the projection are read, other fields are left empty.
+// This method shall be called by a func (c *IdentifiableMongoDbPersistence) GetListByFilter method from child type that
+// receives FilterParams and converts them into a filter function.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to Trace execution through call chain.
+//		- filter any (optional) a filter BSON object
+//		- sort any (optional) sorting BSON object
+//		- projection *cquery.ProjectionParams (optional) projection parameters, e.g. "id,key,content(field1,field2)"
+//	Returns: items []any, err error data list and error, if they are occurred
+func (c *MongoDbPersistence[T]) GetListByFilterWithProjection(ctx context.Context,
+	filter any, sort any, projection *cquery.ProjectionParams) (items []T, err error) {
+
+	sel, err := c.ComposeProjection(projection)
+	if err != nil {
+		return nil, err
+	}
+	if sel == nil {
+		return c.GetListByFilter(ctx, filter, sort, nil)
+	}
+	return c.GetListByFilter(ctx, filter, sort, sel)
+}
@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// PurgeDeleted is physically deletes data items marked as deleted in soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to Trace execution through call chain.
+//	Returns: error or nil for success.
+func (c *MongoDbPersistence[T]) PurgeDeleted(ctx context.Context) error {
+	res, err := c.Collection.DeleteMany(ctx, bson.M{"deleted": true})
+	if err != nil {
+		return err
+	}
+	c.Logger.Trace(ctx, "Purged %d deleted items from %s", res.DeletedCount, c.CollectionName)
+	return nil
+}
+
+func (c *MongoDbPersistence[T]) isSoftDelete() bool {
+	return c.SoftDelete && cpersist.IsTrackableType[T]()
+}
+
+// filterDeleted adds exclusion of logically deleted documents to the filter.
+func (c *MongoDbPersistence[T]) filterDeleted(ctx context.Context, filter any) any {
+	if !c.isSoftDelete() || cpersist.IsDeletedIncluded(ctx) {
+		return filter
+	}
+	// Documents without the flag are not deleted as well
+	notDeleted := bson.M{"deleted": bson.M{"$ne": true}}
+	if filter == nil {
+		return notDeleted
+	}
+	return bson.M{"$and": bson.A{filter, notDeleted}}
+}
+
+// composeDeletedUpdate creates an update that marks documents as deleted or restores them.
+func (c *MongoDbPersistence[T]) composeDeletedUpdate(deleted bool) bson.M {
+	return bson.M{"$set": bson.M{"deleted": deleted, "change_time": time.Now().UTC()}}
+}
+
+// trackItem stamps create and change times in soft-delete mode.
+// The properties are set on a copy, so the given item is not changed.
+func (c *MongoDbPersistence[T]) trackItem(item T, created bool) T {
+	if !c.SoftDelete {
+		return item
+	}
+	var obj any = item
+	cpersist.TrackObjectChange(&obj, created, time.Now().UTC())
+	if result, ok := obj.(T); ok {
+		return result
+	}
+	return item
+}
+
+// trackPartialData adds change time to partially updated fields in soft-delete mode.
+func (c *MongoDbPersistence[T]) trackPartialData(data cdata.AnyValueMap) cdata.AnyValueMap {
+	if !c.SoftDelete || !cpersist.IsChangeableType[T]() {
+		return data
+	}
+	return *cdata.NewAnyValueMapFromMaps(data.Value(), map[string]any{"change_time": time.Now().UTC()})
+}
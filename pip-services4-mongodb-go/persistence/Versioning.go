@@ -0,0 +1,14 @@
+package persistence
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// composeVersionFilter creates a filter that selects an item by id and expected version.
+// It is a MongoDB counterpart of cpersist.ComposeVersionFilter.
+func composeVersionFilter(id any, version string) bson.M {
+	if version == "" {
+		return bson.M{"_id": id, "version": bson.M{"$in": bson.A{nil, ""}}}
+	}
+	return bson.M{"_id": id, "version": version}
+}
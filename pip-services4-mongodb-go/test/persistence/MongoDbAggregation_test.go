@@ -0,0 +1,34 @@
+package test_persistence
+
+import (
+	"testing"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMongoDbComposeAggregatePipeline(t *testing.T) {
+	persistence := NewDummyMongoDbPersistence()
+
+	pipeline, err := persistence.ComposeAggregatePipeline(bson.M{"key": "Key 1"}, *cquery.NewAggregateParams(
+		[]string{"key", "id"},
+		cquery.NewCountAggregateField("count"),
+		cquery.NewAggregateField("total", cquery.AggregateSum, "content.size"),
+	))
+	assert.Nil(t, err)
+	assert.Equal(t, bson.A{
+		bson.M{"$match": bson.M{"key": "Key 1"}},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{"g0": "$key", "g1": "$_id"},
+			"a0":  bson.M{"$sum": 1},
+			"a1":  bson.M{"$sum": "$content.size"},
+		}},
+		bson.M{"$sort": bson.D{{Key: "_id.g0", Value: 1}, {Key: "_id.g1", Value: 1}}},
+	}, pipeline)
+
+	_, err = persistence.ComposeAggregatePipeline(nil, *cquery.NewAggregateParams(nil,
+		cquery.NewAggregateField("total", cquery.AggregateSum, "$where"),
+	))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,88 @@
+package test_persistence
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMongoDbGetPageByFilterWithToken(t *testing.T) {
+	mongoUri := os.Getenv("MONGO_URI")
+	mongoHost := os.Getenv("MONGO_HOST")
+	if mongoHost == "" {
+		mongoHost = "localhost"
+	}
+	mongoPort := os.Getenv("MONGO_PORT")
+	if mongoPort == "" {
+		mongoPort = "27017"
+	}
+	mongoDatabase := os.Getenv("MONGO_DB")
+	if mongoDatabase == "" {
+		mongoDatabase = "test"
+	}
+	if mongoUri == "" && mongoHost == "" {
+		return
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.uri", mongoUri,
+		"connection.host", mongoHost,
+		"connection.port", mongoPort,
+		"connection.database", mongoDatabase,
+	)
+
+	persistence := NewDummyMongoDbPersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	if err != nil {
+		t.Error("Error opened persistence", err)
+		return
+	}
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	for i := 1; i <= 25; i++ {
+		_, err = persistence.Create(context.Background(), Dummy{
+			Id:      strconv.Itoa(100 + i),
+			Key:     "Key " + strconv.Itoa(i),
+			Content: "Content " + strconv.Itoa(i%3),
+		})
+		assert.Nil(t, err)
+	}
+
+	sort := *cquery.NewSortParams([]cquery.SortField{cquery.NewSortField("content", false)})
+	filter := bson.M{"key": bson.M{"$ne": "Key 7"}}
+
+	ids := make(map[string]bool)
+	token := ""
+	pages := 0
+	for {
+		page, err := persistence.IdentifiableMongoDbPersistence.GetPageByFilterWithToken(context.Background(),
+			filter, *cquery.NewTokenizedPagingParams(token, 10, false), sort, nil)
+		assert.Nil(t, err)
+		pages++
+
+		for _, item := range page.Data {
+			assert.False(t, ids[item.Id])
+			ids[item.Id] = true
+		}
+
+		if page.Token == "" {
+			break
+		}
+		token = page.Token
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Len(t, ids, 24)
+	assert.False(t, ids["107"])
+}
@@ -0,0 +1,28 @@
+package test_persistence
+
+import (
+	"testing"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMongoDbComposeProjection(t *testing.T) {
+	persistence := NewDummyMongoDbPersistence()
+
+	projection, err := persistence.ComposeProjection(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, projection)
+
+	projection, err = persistence.ComposeProjection(cquery.NewProjectionParamsFromValue("id,key,content(field1,field2),content.field1"))
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 1, "key": 1, "content.field1": 1, "content.field2": 1}, projection)
+
+	projection, err = persistence.ComposeProjection(cquery.ParseProjectionParams("content(field1)", "content"))
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 0, "content": 1}, projection)
+
+	_, err = persistence.ComposeProjection(cquery.ParseProjectionParams("$where"))
+	assert.NotNil(t, err)
+}
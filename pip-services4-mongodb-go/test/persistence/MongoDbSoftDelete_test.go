@@ -0,0 +1,154 @@
+package test_persistence
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-mongodb-go/persistence"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type TrackedDummy struct {
+	Id         string    `bson:"_id" json:"id"`
+	Key        string    `bson:"key" json:"key"`
+	CreateTime time.Time `bson:"create_time" json:"create_time"`
+	ChangeTime time.Time `bson:"change_time" json:"change_time"`
+	Deleted    bool      `bson:"deleted" json:"deleted"`
+}
+
+func (c TrackedDummy) GetCreateTime() time.Time {
+	return c.CreateTime
+}
+
+func (c TrackedDummy) GetChangeTime() time.Time {
+	return c.ChangeTime
+}
+
+func (c TrackedDummy) GetDeleted() bool {
+	return c.Deleted
+}
+
+type TrackedMongoDbPersistence struct {
+	*persist.IdentifiableMongoDbPersistence[TrackedDummy, string]
+}
+
+func NewTrackedMongoDbPersistence() *TrackedMongoDbPersistence {
+	c := &TrackedMongoDbPersistence{}
+	c.IdentifiableMongoDbPersistence = persist.InheritIdentifiableMongoDbPersistence[TrackedDummy, string](c, "tracked_dummies")
+	return c
+}
+
+func TestSoftDeleteMongoDbPersistence(t *testing.T) {
+	mongoUri := os.Getenv("MONGO_URI")
+	mongoHost := os.Getenv("MONGO_HOST")
+	if mongoHost == "" {
+		mongoHost = "localhost"
+	}
+	mongoPort := os.Getenv("MONGO_PORT")
+	if mongoPort == "" {
+		mongoPort = "27017"
+	}
+	mongoDatabase := os.Getenv("MONGO_DB")
+	if mongoDatabase == "" {
+		mongoDatabase = "test"
+	}
+	if mongoUri == "" && mongoHost == "" {
+		return
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.uri", mongoUri,
+		"connection.host", mongoHost,
+		"connection.port", mongoPort,
+		"connection.database", mongoDatabase,
+		"options.soft_delete", true,
+	)
+
+	persistence := NewTrackedMongoDbPersistence()
+	persistence.Configure(context.Background(), dbConfig)
+	assert.True(t, persistence.SoftDelete)
+
+	err := persistence.Open(context.Background())
+	if err != nil {
+		t.Error("Error opened persistence", err)
+		return
+	}
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	item1, err := persistence.Create(context.Background(), TrackedDummy{Id: "1", Key: "Key 1"})
+	assert.Nil(t, err)
+	assert.False(t, item1.CreateTime.IsZero())
+	assert.False(t, item1.ChangeTime.IsZero())
+
+	for _, id := range []string{"2", "3", "4"} {
+		_, err = persistence.Create(context.Background(), TrackedDummy{Id: id, Key: "Key " + id})
+		assert.Nil(t, err)
+	}
+
+	item, err := persistence.UpdatePartially(context.Background(), "1", *cdata.NewAnyValueMapFromTuples("key", "Key 5"))
+	assert.Nil(t, err)
+	assert.Equal(t, "Key 5", item.Key)
+	assert.True(t, item1.CreateTime.Equal(item.CreateTime))
+	assert.False(t, item.ChangeTime.Before(item1.ChangeTime))
+
+	// Deleted items are marked and excluded from reads
+	item, err = persistence.DeleteById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.True(t, item.Deleted)
+
+	err = persistence.DeleteByIds(context.Background(), []string{"2"})
+	assert.Nil(t, err)
+	err = persistence.DeleteByFilter(context.Background(), bson.M{"key": "Key 3"})
+	assert.Nil(t, err)
+
+	item, err = persistence.GetOneById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "", item.Id)
+
+	page, err := persistence.GetPageByFilter(context.Background(), nil, *cquery.NewPagingParams(0, 10, true), nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, 1, page.Total)
+
+	items, err := persistence.GetListByIds(context.Background(), []string{"1", "2", "3", "4"})
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+
+	// Deleted items can be read explicitly
+	item, err = persistence.GetOneById(cpersist.WithDeleted(context.Background()), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", item.Id)
+	assert.True(t, item.Deleted)
+
+	count, err := persistence.GetCountByFilter(cpersist.WithDeleted(context.Background()), bson.M{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), count)
+
+	// Restore and purge
+	item, err = persistence.RestoreById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.False(t, item.Deleted)
+	assert.Equal(t, "Key 5", item.Key)
+
+	err = persistence.PurgeDeleted(context.Background())
+	assert.Nil(t, err)
+	items, err = persistence.GetListByIds(cpersist.WithDeleted(context.Background()), []string{"1", "2", "3", "4"})
+	assert.Nil(t, err)
+	assert.Len(t, items, 2)
+
+	_, err = persistence.PurgeById(context.Background(), "4")
+	assert.Nil(t, err)
+	items, err = persistence.GetListByIds(cpersist.WithDeleted(context.Background()), []string{"1", "2", "3", "4"})
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+}
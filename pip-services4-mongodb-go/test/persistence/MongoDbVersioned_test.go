@@ -0,0 +1,94 @@
+package test_persistence
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-mongodb-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+type VersionedDummy struct {
+	Id      string `bson:"_id" json:"id"`
+	Key     string `bson:"key" json:"key"`
+	Version string `bson:"version" json:"version"`
+}
+
+func (c VersionedDummy) GetVersion() string {
+	return c.Version
+}
+
+type VersionedMongoDbPersistence struct {
+	*persist.IdentifiableMongoDbPersistence[VersionedDummy, string]
+}
+
+func NewVersionedMongoDbPersistence() *VersionedMongoDbPersistence {
+	c := &VersionedMongoDbPersistence{}
+	c.IdentifiableMongoDbPersistence = persist.InheritIdentifiableMongoDbPersistence[VersionedDummy, string](c, "versioned_dummies")
+	return c
+}
+
+func TestVersionedMongoDbPersistence(t *testing.T) {
+	mongoUri := os.Getenv("MONGO_URI")
+	mongoHost := os.Getenv("MONGO_HOST")
+	if mongoHost == "" {
+		mongoHost = "localhost"
+	}
+	mongoPort := os.Getenv("MONGO_PORT")
+	if mongoPort == "" {
+		mongoPort = "27017"
+	}
+	mongoDatabase := os.Getenv("MONGO_DB")
+	if mongoDatabase == "" {
+		mongoDatabase = "test"
+	}
+	if mongoUri == "" && mongoHost == "" {
+		return
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.uri", mongoUri,
+		"connection.host", mongoHost,
+		"connection.port", mongoPort,
+		"connection.database", mongoDatabase,
+		"options.versioned", true,
+	)
+
+	persistence := NewVersionedMongoDbPersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	if err != nil {
+		t.Error("Error opened persistence", err)
+		return
+	}
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	item, err := persistence.Create(context.Background(), VersionedDummy{Id: "1", Key: "Key 1"})
+	assert.Nil(t, err)
+
+	item.Key = "Key 2"
+	item, err = persistence.Update(context.Background(), item)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", item.Version)
+
+	_, err = persistence.Update(context.Background(), VersionedDummy{Id: "1", Key: "Key 3", Version: "0"})
+	assert.NotNil(t, err)
+	assert.Equal(t, cerr.Conflict, err.(*cerr.ApplicationError).Category)
+
+	item, err = persistence.UpdatePartially(context.Background(), "1",
+		*cdata.NewAnyValueMapFromTuples("key", "Key 4"))
+	assert.Nil(t, err)
+	assert.Equal(t, "2", item.Version)
+
+	_, err = persistence.UpdatePartially(context.Background(), "1",
+		*cdata.NewAnyValueMapFromTuples("key", "Key 5", "version", "1"))
+	assert.NotNil(t, err)
+}
@@ -9,6 +9,7 @@ import (
 
 // Creates MqttMessageQueue components by their descriptors.
 // See MqttMessageQueue
+// See MqttTopic
 type DefaultMqttFactory struct {
 	*cbuild.Factory
 }
@@ -21,6 +22,7 @@ func NewDefaultMqttFactory() *DefaultMqttFactory {
 	mqttQueueFactoryDescriptor := cref.NewDescriptor("pip-services", "queue-factory", "mqtt", "*", "1.0")
 	mqttConnectionDescriptor := cref.NewDescriptor("pip-services", "connection", "mqtt", "*", "1.0")
 	mqttQueueDescriptor := cref.NewDescriptor("pip-services", "message-queue", "mqtt", "*", "1.0")
+	mqttTopicDescriptor := cref.NewDescriptor("pip-services", "topic", "mqtt", "*", "1.0")
 
 	c.RegisterType(mqttQueueFactoryDescriptor, NewMqttMessageQueueFactory)
 
@@ -36,5 +38,15 @@ func NewDefaultMqttFactory() *DefaultMqttFactory {
 		return queues.NewMqttMessageQueue(name)
 	})
 
+	c.Register(mqttTopicDescriptor, func(locator interface{}) interface{} {
+		name := ""
+		descriptor, ok := locator.(*cref.Descriptor)
+		if ok {
+			name = descriptor.Name()
+		}
+
+		return queues.NewMqttTopic(name)
+	})
+
 	return &c
 }
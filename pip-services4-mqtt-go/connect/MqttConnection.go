@@ -2,14 +2,18 @@ package connect
 
 import (
 	"context"
+	"net/url"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
 	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
 	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
 	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
 )
@@ -18,6 +22,10 @@ import (
 // By defining a connection and sharing it through multiple message queues
 // you can reduce number of used connections.
 //
+// By default the connection uses MQTT 3.1.1 protocol that has no message properties.
+// MQTT 5 protocol is turned on by protocol_version option, then messages carry user properties.
+// MQTT 5 connection always reconnects after the connection is lost and restores subscriptions.
+//
 // Configuration parameters
 //   - client_id:               (optional) name of the client id
 //   - connection(s):
@@ -34,6 +42,7 @@ import (
 //   - connect_timeout:      (optional) number of milliseconds to wait for connection (default: 30000)
 //   - reconnect_timeout:    (optional) number of milliseconds to wait on each reconnection attempt (default: 1000)
 //   - keepalive_timeout:    (optional) number of milliseconds to ping broker while inactive (default: 3000)
+//   - protocol_version:     (optional) MQTT protocol version: 3 for MQTT 3.1.1 or 5 for MQTT 5 (default: 3)
 //
 // References
 //   - \*:logger:\*:\*:1.0           (optional) ILogger components to pass log messages
@@ -50,6 +59,8 @@ type MqttConnection struct {
 
 	// The MQTT connection object.
 	Connection mqtt.Client
+	// The MQTT 5 connection manager used when protocol_version is 5.
+	ConnectionManager *autopaho.ConnectionManager
 
 	// Topic subscriptions
 	subscriptions []*MqttSubscription
@@ -60,6 +71,7 @@ type MqttConnection struct {
 	connectTimeout   int
 	reconnectTimeout int
 	keepAliveTimeout int
+	protocolVersion  int
 }
 
 // NewMqttConnection creates a new instance of the connection component.
@@ -70,6 +82,7 @@ func NewMqttConnection() *MqttConnection {
 			"options.connect_timeout", 30000,
 			"options.reconnect_timeout", 1000,
 			"options.keepalive_timeout", 60000,
+			"options.protocol_version", 3,
 		),
 
 		Logger:             clog.NewCompositeLogger(),
@@ -82,6 +95,7 @@ func NewMqttConnection() *MqttConnection {
 		connectTimeout:   30000,
 		reconnectTimeout: 60000,
 		keepAliveTimeout: 1000, //!!
+		protocolVersion:  3,
 	}
 	return c
 }
@@ -101,6 +115,7 @@ func (c *MqttConnection) Configure(ctx context.Context, config *cconf.ConfigPara
 	c.connectTimeout = config.GetAsIntegerWithDefault("options.connect_timeout", c.connectTimeout)
 	c.reconnectTimeout = config.GetAsIntegerWithDefault("options.reconnect_timeout", c.reconnectTimeout)
 	c.keepAliveTimeout = config.GetAsIntegerWithDefault("options.keepalive_timeout", c.keepAliveTimeout)
+	c.protocolVersion = config.GetAsIntegerWithDefault("options.protocol_version", c.protocolVersion)
 }
 
 // Sets references to dependent components.
@@ -115,7 +130,13 @@ func (c *MqttConnection) SetReferences(ctx context.Context, references cref.IRef
 // Checks if the component is opened.
 // Returns true if the component has been opened and false otherwise.
 func (c *MqttConnection) IsOpen() bool {
-	return c.Connection != nil
+	return c.Connection != nil || c.ConnectionManager != nil
+}
+
+// ProtocolVersion gets the configured MQTT protocol version.
+// Returns 5 for MQTT 5 or 3 for MQTT 3.1.1 protocol.
+func (c *MqttConnection) ProtocolVersion() int {
+	return c.protocolVersion
 }
 
 // Opens the component.
@@ -131,9 +152,13 @@ func (c *MqttConnection) Open(ctx context.Context) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	uri := options.GetAsString("uri")
+	if c.protocolVersion == 5 {
+		return c.openV5(ctx, uri, options.GetAsString("username"), options.GetAsString("password"))
+	}
+
 	opts := mqtt.NewClientOptions()
 
-	uri := options.GetAsString("uri")
 	uris := strings.Split(uri, ",")
 	for _, uri = range uris {
 		opts.AddBroker(uri)
@@ -170,22 +195,127 @@ func (c *MqttConnection) Open(ctx context.Context) error {
 	return nil
 }
 
+func (c *MqttConnection) openV5(ctx context.Context, uri string, user string, password string) error {
+	serverUrls := make([]*url.URL, 0)
+	for _, item := range strings.Split(uri, ",") {
+		serverUrl, err := url.Parse(item)
+		if err != nil {
+			return cerr.NewConfigError(cctx.GetTraceId(ctx), "BAD_URI", "MQTT broker uri is invalid").
+				WithDetails("uri", item).WithCause(err)
+		}
+		serverUrls = append(serverUrls, serverUrl)
+	}
+
+	config := autopaho.ClientConfig{
+		ServerUrls:                    serverUrls,
+		KeepAlive:                     uint16(c.keepAliveTimeout / 1000),
+		CleanStartOnInitialConnection: true,
+		ConnectRetryDelay:             time.Millisecond * time.Duration(c.reconnectTimeout),
+		ConnectTimeout:                time.Millisecond * time.Duration(c.connectTimeout),
+		OnConnectionUp:                c.onConnectionUp,
+		ClientConfig: paho.ClientConfig{
+			ClientID:          c.clientId,
+			OnPublishReceived: []func(paho.PublishReceived) (bool, error){c.onPublishReceived},
+		},
+	}
+	if user != "" {
+		config.ConnectUsername = user
+	}
+	if password != "" {
+		config.ConnectPassword = []byte(password)
+	}
+
+	// The manager keeps reconnecting until it is disconnected
+	manager, err := autopaho.NewConnection(context.Background(), config)
+	if err == nil {
+		connectCtx, cancel := context.WithTimeout(ctx, time.Millisecond*time.Duration(c.connectTimeout))
+		defer cancel()
+		if err = manager.AwaitConnection(connectCtx); err != nil {
+			_ = manager.Disconnect(context.Background())
+		}
+	}
+	if err != nil {
+		err = cerr.NewConnectionError(cctx.GetTraceId(ctx), "CONNECT_FAILED", "Connection to MQTT broker failed").
+			WithCause(err)
+		c.Logger.Error(ctx, err, "Failed to connect to MQTT broker at "+uri)
+		return err
+	}
+
+	c.ConnectionManager = manager
+
+	c.Logger.Debug(ctx, "Connected to MQTT broker at "+uri)
+
+	return nil
+}
+
+// onConnectionUp restores subscriptions of MQTT 5 connection after reconnects
+// because sessions are not kept by the broker.
+func (c *MqttConnection) onConnectionUp(manager *autopaho.ConnectionManager, connack *paho.Connack) {
+	c.lock.Lock()
+	options := make([]paho.SubscribeOptions, 0, len(c.subscriptions))
+	for _, subscription := range c.subscriptions {
+		options = append(options, paho.SubscribeOptions{Topic: subscription.Topic, QoS: subscription.Qos})
+	}
+	c.lock.Unlock()
+
+	if len(options) == 0 {
+		return
+	}
+	if _, err := manager.Subscribe(context.Background(), &paho.Subscribe{Subscriptions: options}); err != nil {
+		c.Logger.Error(context.Background(), err, "Failed to restore subscriptions to MQTT broker")
+	}
+}
+
+// onPublishReceived passes a message received via MQTT 5 connection to listeners
+// of subscriptions with matching topic filters.
+func (c *MqttConnection) onPublishReceived(received paho.PublishReceived) (bool, error) {
+	message := NewMqttMessage(received.Packet)
+
+	c.lock.Lock()
+	subscriptions := make([]*MqttSubscription, 0, len(c.subscriptions))
+	for _, subscription := range c.subscriptions {
+		if MatchTopicFilter(subscription.Topic, message.Topic()) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	c.lock.Unlock()
+
+	for _, subscription := range subscriptions {
+		// The listener can be removed to keep other subscriptions to the same topic alive
+		if atomic.LoadInt32(&subscription.Skip) == 0 {
+			subscription.Listener.OnMessage(message)
+		}
+	}
+	return true, nil
+}
+
 // Closes component and frees used resources.
 // Parameters:
 //   - ctx context.Context	transaction id to trace execution through call chain.
 //
 // Return			 error or nil no errors occured
 func (c *MqttConnection) Close(ctx context.Context) error {
-	if c.Connection == nil {
+	if !c.IsOpen() {
 		return nil
 	}
 
 	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	c.Connection.Disconnect(250)
+	manager := c.ConnectionManager
+	if c.Connection != nil {
+		c.Connection.Disconnect(250)
+	}
 	c.Connection = nil
+	c.ConnectionManager = nil
 	c.subscriptions = []*MqttSubscription{}
+	c.lock.Unlock()
+
+	if manager != nil {
+		disconnectCtx, cancel := context.WithTimeout(ctx, 250*time.Millisecond)
+		defer cancel()
+		if err := manager.Disconnect(disconnectCtx); err != nil {
+			c.Logger.Warn(ctx, "Failed to disconnect from MQTT broker: %s", err)
+		}
+	}
 
 	c.Logger.Debug(ctx, "Disconnected from MQTT broker")
 
@@ -209,7 +339,7 @@ func (c *MqttConnection) DeleteQueue() error {
 }
 
 func (c *MqttConnection) checkOpen() error {
-	if c.Connection != nil {
+	if c.IsOpen() {
 		return nil
 	}
 
@@ -231,12 +361,46 @@ func (c *MqttConnection) checkOpen() error {
 //
 // Returns: error or nil for success
 func (c *MqttConnection) Publish(ctx context.Context, topic string, qos byte, retained bool, data []byte) error {
+	return c.PublishWithProperties(ctx, topic, qos, retained, data, nil)
+}
+
+// PublishWithProperties publishes a message with user properties to a specified topic.
+// User properties are sent only via MQTT 5 protocol and they are skipped by MQTT 3.1.1.
+//
+// Parameters:
+//   - ctx context.Context	operation context.
+//   - topic a topic name
+//   - qos quality of service (QOS) for the message
+//   - retained retained flag for the message
+//   - data a message to be published
+//   - properties user properties of the message
+//
+// Returns: error or nil for success
+func (c *MqttConnection) PublishWithProperties(ctx context.Context, topic string, qos byte, retained bool,
+	data []byte, properties map[string]string) error {
 	// Check for open connection
 	err := c.checkOpen()
 	if err != nil {
 		return err
 	}
 
+	if manager := c.ConnectionManager; manager != nil {
+		publish := &paho.Publish{
+			Topic:   topic,
+			QoS:     qos,
+			Retain:  retained,
+			Payload: data,
+		}
+		if len(properties) > 0 {
+			publish.Properties = &paho.PublishProperties{}
+			for key, value := range properties {
+				publish.Properties.User.Add(key, value)
+			}
+		}
+		_, err = manager.Publish(ctx, publish)
+		return err
+	}
+
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
@@ -262,9 +426,6 @@ func (c *MqttConnection) Subscribe(ctx context.Context, topic string, qos byte,
 		return err
 	}
 
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	// Create the subscription
 	subscription := &MqttSubscription{
 		Topic:    topic,
@@ -272,6 +433,24 @@ func (c *MqttConnection) Subscribe(ctx context.Context, topic string, qos byte,
 		Listener: listener,
 	}
 
+	if manager := c.ConnectionManager; manager != nil {
+		// Messages are routed to listeners of registered subscriptions
+		c.lock.Lock()
+		c.subscriptions = append(c.subscriptions, subscription)
+		c.lock.Unlock()
+
+		_, err = manager.Subscribe(ctx, &paho.Subscribe{
+			Subscriptions: []paho.SubscribeOptions{{Topic: topic, QoS: qos}},
+		})
+		if err != nil {
+			c.removeSubscription(topic, listener)
+		}
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	// Subscribe to topic
 	token := c.Connection.Subscribe(topic, qos, func(client mqtt.Client, msg mqtt.Message) {
 		// The listener can be removed to keep other subscriptions to the same topic alive
@@ -300,6 +479,32 @@ func (c *MqttConnection) Subscribe(ctx context.Context, topic string, qos byte,
 //
 // Returns: err or nil for success
 func (c *MqttConnection) Unsubscribe(ctx context.Context, topic string, listener IMqttMessageListener) error {
+	removed, hasMoreSubscriptions := c.removeSubscription(topic, listener)
+
+	// Unsubscribe from the topic if nobody else listens
+	if !removed || hasMoreSubscriptions {
+		return nil
+	}
+
+	if manager := c.ConnectionManager; manager != nil {
+		_, err := manager.Unsubscribe(ctx, &paho.Unsubscribe{Topics: []string{topic}})
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.Connection == nil {
+		return nil
+	}
+	token := c.Connection.Unsubscribe(topic)
+	token.Wait()
+	return token.Error()
+}
+
+// removeSubscription removes the subscription of the listener to the topic.
+// Returns true if the subscription was removed and true if there are more subscriptions to the same topic.
+func (c *MqttConnection) removeSubscription(topic string, listener IMqttMessageListener) (bool, bool) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
@@ -315,27 +520,17 @@ func (c *MqttConnection) Unsubscribe(ctx context.Context, topic string, listener
 
 	// If nothing to remove then skip
 	if removedSubscription == nil {
-		return nil
+		return false, false
 	}
 
 	// Unset listener to avoid receiving subscriptions
 	atomic.StoreInt32(&removedSubscription.Skip, 1)
 
 	// Check if there are more subscriptions to the same topic
-	hasMoreSubscriptions := false
 	for _, subscription := range c.subscriptions {
 		if subscription.Topic == topic {
-			hasMoreSubscriptions = true
-			break
+			return true, true
 		}
 	}
-
-	// Unsubscribe from the topic if nobody else listens
-	if !hasMoreSubscriptions {
-		token := c.Connection.Unsubscribe(topic)
-		token.Wait()
-		return token.Error()
-	}
-
-	return nil
+	return true, false
 }
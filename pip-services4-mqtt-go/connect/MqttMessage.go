@@ -0,0 +1,87 @@
+package connect
+
+import (
+	"strings"
+
+	"github.com/eclipse/paho.golang/paho"
+)
+
+// MqttMessage is a message received via MQTT 5 protocol.
+// It implements mqtt.Message interface and keeps user properties of the message.
+type MqttMessage struct {
+	packet *paho.Publish
+}
+
+// NewMqttMessage creates a message from the received MQTT 5 publish packet.
+// Parameters:
+//   - packet	the received publish packet.
+func NewMqttMessage(packet *paho.Publish) *MqttMessage {
+	return &MqttMessage{packet: packet}
+}
+
+func (c *MqttMessage) Duplicate() bool {
+	return c.packet.Duplicate()
+}
+
+func (c *MqttMessage) Qos() byte {
+	return c.packet.QoS
+}
+
+func (c *MqttMessage) Retained() bool {
+	return c.packet.Retain
+}
+
+func (c *MqttMessage) Topic() string {
+	return c.packet.Topic
+}
+
+func (c *MqttMessage) MessageID() uint16 {
+	return c.packet.PacketID
+}
+
+func (c *MqttMessage) Payload() []byte {
+	return c.packet.Payload
+}
+
+// Ack does nothing, received messages are acknowledged by the connection.
+func (c *MqttMessage) Ack() {}
+
+// UserProperties gets user properties of the message.
+// Returns a map of user properties or nil when the message has no properties.
+func (c *MqttMessage) UserProperties() map[string]string {
+	if c.packet.Properties == nil || len(c.packet.Properties.User) == 0 {
+		return nil
+	}
+	properties := make(map[string]string, len(c.packet.Properties.User))
+	for _, property := range c.packet.Properties.User {
+		properties[property.Key] = property.Value
+	}
+	return properties
+}
+
+// MatchTopicFilter checks if the topic matches the subscription filter with "+" and "#" wildcards.
+// Prefixes of shared subscriptions "$share/<group>/" are not a part of the filter.
+// Parameters:
+//   - filter	a subscription topic filter.
+//   - topic	a topic name of the message.
+//
+// Returns true if the topic matches the filter.
+func MatchTopicFilter(filter string, topic string) bool {
+	if strings.HasPrefix(filter, "$share/") {
+		if parts := strings.SplitN(filter, "/", 3); len(parts) == 3 {
+			filter = parts[2]
+		}
+	}
+
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	for index, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if index >= len(topicLevels) || (level != "+" && level != topicLevels[index]) {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
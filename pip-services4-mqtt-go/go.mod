@@ -3,12 +3,14 @@ module github.com/pip-services4/pip-services4-go/pip-services4-mqtt-go
 go 1.20
 
 require (
+	github.com/eclipse/paho.golang v0.20.0
 	github.com/eclipse/paho.mqtt.golang v1.4.3
-	github.com/pip-services4/pip-services4-go/pip-services4-commons-go v0.0.0-20230714192537-504cee138e02
-	github.com/pip-services4/pip-services4-go/pip-services4-components-go v0.0.0-20230714192537-504cee138e02
-	github.com/pip-services4/pip-services4-go/pip-services4-config-go v0.0.0-20230714192537-504cee138e02
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go v0.0.1-2
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go v0.0.1-2
+	github.com/pip-services4/pip-services4-go/pip-services4-config-go v0.0.1-3
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go v0.0.1-2
 	github.com/pip-services4/pip-services4-go/pip-services4-messaging-go v0.0.0-20230714192537-504cee138e02
-	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.0-20230714192537-504cee138e02
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.1-3
 	github.com/stretchr/testify v1.8.4
 )
 
@@ -16,9 +18,30 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/google/uuid v1.3.0 // indirect
 	github.com/gorilla/websocket v1.5.0 // indirect
-	github.com/pip-services4/pip-services4-go/pip-services4-data-go v0.0.0-20230714192537-504cee138e02 // indirect
+	github.com/hamba/avro/v2 v2.20.0 // indirect
+	github.com/jinzhu/copier v0.3.5 // indirect
+	github.com/json-iterator/go v1.1.12 // indirect
+	github.com/mitchellh/mapstructure v1.5.0 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+	github.com/pip-services4/pip-services4-go/pip-services4-persistence-go v0.0.0-20230719170734-6e7b58414323 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/net v0.8.0 // indirect
-	golang.org/x/sync v0.1.0 // indirect
+	github.com/vmihailenco/msgpack/v5 v5.4.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/net v0.17.0 // indirect
+	golang.org/x/sync v0.4.0 // indirect
+	google.golang.org/protobuf v1.31.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go => ../pip-services4-commons-go
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go => ../pip-services4-components-go
+	github.com/pip-services4/pip-services4-go/pip-services4-config-go => ../pip-services4-config-go
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go => ../pip-services4-data-go
+	github.com/pip-services4/pip-services4-go/pip-services4-expressions-go => ../pip-services4-expressions-go
+	github.com/pip-services4/pip-services4-go/pip-services4-messaging-go => ../pip-services4-messaging-go
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go => ../pip-services4-observability-go
+	github.com/pip-services4/pip-services4-go/pip-services4-persistence-go => ../pip-services4-persistence-go
+	github.com/pip-services4/pip-services4-go/pip-services4-rpc-go => ../pip-services4-rpc-go
+)
@@ -20,6 +20,11 @@ import (
 
 // MqttMessageQueue are message queue that sends and receives messages via MQTT message broker.
 //
+// Message headers are passed as MQTT 5 user properties when protocol_version option is 5.
+// When serialize_envelope option is not set, message id, trace id, type and delivery count
+// are also passed as user properties. MQTT 3.1.1 protocol has no message properties,
+// so it passes message headers and delivery count only when serialize_envelope option is set.
+//
 // Configuration parameters:
 //
 //   - topic:                         name of MQTT topic to subscribe
@@ -41,6 +46,10 @@ import (
 //   - connect_timeout:      (optional) number of milliseconds to wait for connection (default: 30000)
 //   - reconnect_timeout:    (optional) number of milliseconds to wait on each reconnection attempt (default: 1000)
 //   - keepalive_timeout:    (optional) number of milliseconds to ping broker while inactive (default: 3000)
+//   - protocol_version:     (optional) MQTT protocol version: 3 for MQTT 3.1.1 or 5 for MQTT 5 (default: 3)
+//   - dead_letter_queue:    (optional) name of MQTT topic to move poison messages to (default: none, messages are dropped)
+//   - max_delivery_count:   (optional) maximum number of deliveries before a message is moved to dead letter queue (default: 0 unlimited).
+//     It requires serialize_envelope option or MQTT 5 protocol to pass the delivery count, otherwise Open fails.
 //
 // References:
 //   - *:logger:*:*:1.0             (optional)  ILogger components to pass log messages
@@ -89,6 +98,7 @@ type MqttMessageQueue struct {
 
 	serializeEnvelope bool
 	topic             string
+	deadLetterTopic   string
 	qos               byte
 	retain            bool
 	autoSubscribe     bool
@@ -115,7 +125,7 @@ func NewMqttMessageQueue(name string) *MqttMessageQueue {
 		Logger: clog.NewCompositeLogger(),
 	}
 	c.MessageQueue = cqueues.InheritMessageQueue(&c, name,
-		cqueues.NewMessagingCapabilities(false, true, true, true, true, false, false, false, true))
+		cqueues.NewMessagingCapabilities(false, true, true, true, true, false, false, true, true))
 	c.DependencyResolver = cref.NewDependencyResolver()
 	c.DependencyResolver.Put(context.Background(), "connection", cref.NewDescriptor("pip-services", "connection", "mqtt", "*", "1.0"))
 	c.DependencyResolver.Configure(context.Background(), c.defaultConfig)
@@ -140,6 +150,8 @@ func (c *MqttMessageQueue) Configure(ctx context.Context, config *cconf.ConfigPa
 	c.autoSubscribe = config.GetAsBooleanWithDefault("options.autosubscribe", c.autoSubscribe)
 	c.qos = byte(config.GetAsIntegerWithDefault("options.qos", int(c.qos)))
 	c.retain = config.GetAsBooleanWithDefault("options.retain", c.retain)
+	c.deadLetterTopic = config.GetAsStringWithDefault("options.dead_letter_queue", c.deadLetterTopic)
+	c.MaxDeliveryCount = config.GetAsIntegerWithDefault("options.max_delivery_count", c.MaxDeliveryCount)
 }
 
 // Sets references to dependent components.
@@ -201,6 +213,12 @@ func (c *MqttMessageQueue) Open(ctx context.Context) (err error) {
 		c.localConnection = true
 	}
 
+	// Abandoned messages would be published again forever without delivery count
+	if c.MaxDeliveryCount > 0 && !c.serializeEnvelope && c.Connection.ProtocolVersion() != 5 {
+		return cerr.NewConfigError(cctx.GetTraceId(ctx), "DELIVERY_COUNT_NOT_SUPPORTED",
+			"max_delivery_count requires serialize_envelope option or MQTT 5 protocol")
+	}
+
 	if c.localConnection {
 		err = c.Connection.Open(ctx)
 	}
@@ -312,6 +330,21 @@ func (c *MqttMessageQueue) fromMessage(message *cqueues.MessageEnvelope) ([]byte
 	return data, nil
 }
 
+// fromProperties composes MQTT 5 user properties of the message.
+func (c *MqttMessageQueue) fromProperties(message *cqueues.MessageEnvelope) map[string]string {
+	properties := make(map[string]string, len(message.Headers)+4)
+	for key, value := range message.Headers {
+		properties[key] = value
+	}
+	if !c.serializeEnvelope {
+		properties["message_id"] = message.MessageId
+		properties["trace_id"] = message.TraceId
+		properties["message_type"] = message.MessageType
+		properties["delivery_count"] = strconv.Itoa(message.DeliveryCount)
+	}
+	return properties
+}
+
 func (c *MqttMessageQueue) toMessage(msg mqtt.Message) (*cqueues.MessageEnvelope, error) {
 	message := cqueues.NewEmptyMessageEnvelope()
 
@@ -324,7 +357,31 @@ func (c *MqttMessageQueue) toMessage(msg mqtt.Message) (*cqueues.MessageEnvelope
 		message.MessageId = strconv.FormatUint(uint64(msg.MessageID()), 10)
 		message.MessageType = msg.Topic()
 		message.Message = msg.Payload()
+
+		// Restore the message from MQTT 5 user properties
+		if properties, ok := msg.(*connect.MqttMessage); ok {
+			for key, value := range properties.UserProperties() {
+				switch key {
+				case "message_id":
+					if value != "" {
+						message.MessageId = value
+					}
+				case "trace_id":
+					message.TraceId = value
+				case "message_type":
+					if value != "" {
+						message.MessageType = value
+					}
+				case "delivery_count":
+					message.DeliveryCount, _ = strconv.Atoi(value)
+				default:
+					message.SetHeader(key, value)
+				}
+			}
+		}
 	}
+	// Count this delivery on top of the previous ones
+	message.DeliveryCount++
 	message.SetReference(msg)
 
 	return message, nil
@@ -333,7 +390,7 @@ func (c *MqttMessageQueue) toMessage(msg mqtt.Message) (*cqueues.MessageEnvelope
 func (c *MqttMessageQueue) OnMessage(msg mqtt.Message) {
 	// Skip if it came from a wrong topic
 	expectedTopic := c.getTopic()
-	if !strings.Contains(expectedTopic, "*") && expectedTopic != msg.Topic() {
+	if !strings.Contains(expectedTopic, "*") && !connect.MatchTopicFilter(expectedTopic, msg.Topic()) {
 		return
 	}
 
@@ -347,6 +404,11 @@ func (c *MqttMessageQueue) OnMessage(msg mqtt.Message) {
 	c.Counters.IncrementOne(context.Background(), "queue."+c.Name()+".received_messages")
 	c.Logger.Debug(context.Background(), message.TraceId, "Received message %s via %s", message, c.Name())
 
+	// Skip poison messages
+	if c.MoveToDeadLetterIfExceeded(context.Background(), message) {
+		return
+	}
+
 	// Send message to receiver if its set or put it into the queue
 	c.Lock.Lock()
 	defer c.Lock.Unlock()
@@ -482,7 +544,7 @@ func (c *MqttMessageQueue) Receive(ctx context.Context, waitTimeout time.Duratio
 		if len(c.messages) == 0 {
 			c.Lock.Unlock()
 			time.Sleep(time.Duration(100) * time.Millisecond)
-			elapsedTime += time.Duration(100)
+			elapsedTime += time.Duration(100) * time.Millisecond
 			continue
 		}
 
@@ -498,6 +560,53 @@ func (c *MqttMessageQueue) Receive(ctx context.Context, waitTimeout time.Duratio
 	return message, nil
 }
 
+// ReceiveBatch method are receives multiple incoming messages and removes them from the queue.
+// The method waits for the first message to come and then takes all received messages up to the maximum count.
+// Parameters:
+//   - ctx context.Context	transaction id to trace execution through call chain.
+//   - maxCount  int64     a maximum number of messages to receive.
+//   - waitTimeout  time.Duration     a timeout in milliseconds to wait for messages to come.
+//
+// Returns: a list with messages or error.
+func (c *MqttMessageQueue) ReceiveBatch(ctx context.Context, maxCount int64, waitTimeout time.Duration) ([]*cqueues.MessageEnvelope, error) {
+	err := c.CheckOpen(cctx.GetTraceId(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// Subscribe if needed
+	err = c.subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []*cqueues.MessageEnvelope{}
+	elapsedTime := time.Duration(0)
+
+	for elapsedTime < waitTimeout && len(messages) == 0 {
+		c.Lock.Lock()
+		count := int64(len(c.messages))
+		if count == 0 {
+			c.Lock.Unlock()
+			time.Sleep(time.Duration(100) * time.Millisecond)
+			elapsedTime += time.Duration(100) * time.Millisecond
+			continue
+		}
+
+		// Get messages from the queue
+		if count > maxCount {
+			count = maxCount
+		}
+		for index := int64(0); index < count; index++ {
+			messages = append(messages, &c.messages[index])
+		}
+		c.messages = c.messages[count:]
+		c.Lock.Unlock()
+	}
+
+	return messages, nil
+}
+
 // Send method are sends a message into the queue.
 // Parameters:
 //   - ctx context.Context	transaction id to trace execution through call chain.
@@ -510,6 +619,9 @@ func (c *MqttMessageQueue) Send(ctx context.Context, envelop *cqueues.MessageEnv
 		return err
 	}
 
+	// Propagate W3C trace context and baggage to receivers
+	cqueues.InjectTraceContext(ctx, envelop)
+
 	c.Counters.IncrementOne(ctx, "queue."+c.Name()+".sent_messages")
 	c.Logger.Debug(cctx.NewContextWithTraceId(ctx, envelop.TraceId), "Sent message %s via %s", envelop.String(), c.Name())
 
@@ -523,7 +635,7 @@ func (c *MqttMessageQueue) Send(ctx context.Context, envelop *cqueues.MessageEnv
 		topic = c.Name()
 	}
 
-	err = c.Connection.Publish(ctx, topic, c.qos, c.retain, msg)
+	err = c.Connection.PublishWithProperties(ctx, topic, c.qos, c.retain, msg, c.fromProperties(envelop))
 	if err != nil {
 		c.Logger.Error(cctx.NewContextWithTraceId(ctx, envelop.TraceId), err, "Failed to send message via %s", c.Name())
 		return err
@@ -565,7 +677,8 @@ func (c *MqttMessageQueue) Complete(ctx context.Context, message *cqueues.Messag
 // This method is usually used to return a message which could not be processed at the moment
 // to repeat the attempt. Messages that cause unrecoverable errors shall be removed permanently
 // or/and send to dead letter queue.
-// Important: This method is not supported by MQTT.
+// MQTT does not redeliver messages, so the message is published again with incremented
+// delivery count when max_delivery_count is set. Otherwise the method does nothing.
 // Parameters:
 //   - ctx context.Context	operation context.
 //   - message *cqueues.MessageEnvelope  a message to return.
@@ -574,12 +687,16 @@ func (c *MqttMessageQueue) Complete(ctx context.Context, message *cqueues.Messag
 //
 //	error or nil for success.
 func (c *MqttMessageQueue) Abandon(ctx context.Context, message *cqueues.MessageEnvelope) error {
-	// Not supported
-	return nil
+	if c.MaxDeliveryCount <= 0 {
+		return nil
+	}
+
+	return c.Send(ctx, message.Clone())
 }
 
 // Permanently removes a message from the queue and sends it to dead letter queue.
-// Important: This method is not supported by MQTT.
+// The message is sent to DeadLetterQueue when it is set or to the topic
+// configured by options.dead_letter_queue. Otherwise it is dropped.
 // Parameters:
 //   - ctx context.Context	operation context.
 //   - message  *cqueues.MessageEnvelope a message to be removed.
@@ -588,7 +705,32 @@ func (c *MqttMessageQueue) Abandon(ctx context.Context, message *cqueues.Message
 //
 //	error or nil for success.
 func (c *MqttMessageQueue) MoveToDeadLetter(ctx context.Context, message *cqueues.MessageEnvelope) error {
-	// Not supported
+	err := c.CheckOpen(cctx.GetTraceId(ctx))
+	if err != nil {
+		return err
+	}
+
+	sent, err := c.SendToDeadLetterQueue(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	if !sent && c.deadLetterTopic != "" {
+		msg, err := c.fromMessage(message)
+		if err != nil {
+			return err
+		}
+
+		err = c.Connection.PublishWithProperties(ctx, c.deadLetterTopic, c.qos, c.retain, msg, c.fromProperties(message))
+		if err != nil {
+			c.Logger.Error(cctx.NewContextWithTraceId(ctx, message.TraceId), err, "Failed to move message to %s", c.deadLetterTopic)
+			return err
+		}
+	}
+
+	c.Counters.IncrementOne(ctx, "queue."+c.Name()+".dead_messages")
+	c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Moved to dead message %s at %s", message, c.Name())
+
 	return nil
 }
 
@@ -602,7 +744,7 @@ func (c *MqttMessageQueue) sendMessageToReceiver(ctx context.Context, receiver c
 		}
 	}()
 
-	err := receiver.ReceiveMessage(ctx, message, c)
+	err := receiver.ReceiveMessage(cqueues.ExtractTraceContext(ctx, message), message, c)
 	if err != nil {
 		c.Logger.Error(cctx.NewContextWithTraceId(ctx, traceId), err, "Failed to process the message")
 	}
@@ -637,7 +779,7 @@ func (c *MqttMessageQueue) Listen(ctx context.Context, receiver cqueues.IMessage
 
 	// Resend collected messages to receiver
 	for _, message := range batchMessages {
-		receiver.ReceiveMessage(ctx, &message, c)
+		receiver.ReceiveMessage(cqueues.ExtractTraceContext(ctx, &message), &message, c)
 	}
 
 	// Set the receiver
@@ -0,0 +1,218 @@
+package queues
+
+import (
+	"context"
+	"sync"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	"github.com/pip-services4/pip-services4-go/pip-services4-data-go/keys"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+)
+
+// MqttTopic are publish/subscribe topic that delivers messages via MQTT topics.
+// Every subscription is an MQTT shared subscription "$share/<subscription>/<topic>",
+// so receivers that subscribe with the same name share messages while different subscriptions
+// receive every message. The broker shall support shared subscriptions.
+//
+// Topic name is an MQTT topic. Subscriptions support MQTT wildcards:
+// "+" matches a single level and "#" matches any number of levels at the end of the topic.
+// Every subscription opens its own connection.
+//
+// Configuration parameters:
+//
+//   - connection(s):
+//   - discovery_key:               (optional) a key to retrieve the connection from  IDiscovery
+//   - host:                        host name or IP address
+//   - port:                        port number
+//   - uri:                         resource URI or connection string with all parameters in it
+//   - credential(s):
+//   - store_key:                   (optional) a key to retrieve the credentials from  ICredentialStore
+//   - username:                    user name
+//   - password:                    user password
+//   - options:
+//   - serialize_envelope:    (optional) true to serialize entire message as JSON, false to send only message payload (default: true)
+//   - qos:                  (optional) quality of service level aka QOS (default: 0)
+//   - retain:               (optional) retention flag for published messages (default: false)
+//   - retry_connect:        (optional) turns on/off automated reconnect when connection is log (default: true)
+//   - connect_timeout:      (optional) number of milliseconds to wait for connection (default: 30000)
+//   - reconnect_timeout:    (optional) number of milliseconds to wait on each reconnection attempt (default: 1000)
+//   - keepalive_timeout:    (optional) number of milliseconds to ping broker while inactive (default: 3000)
+//
+// References:
+//   - *:logger:*:*:1.0             (optional)  ILogger components to pass log messages
+//   - *:counters:*:*:1.0           (optional)  ICounters components to pass collected measurements
+//   - *:discovery:*:*:1.0          (optional)  IDiscovery services to resolve connections
+//   - *:credential-store:*:*:1.0   (optional) Credential stores to resolve credentials
+//   - *:connection:mqtt:*:1.0      (optional) Shared connection to MQTT service
+//
+// See ITopic
+// See MqttMessageQueue
+//
+// Example:
+//
+//	ctx := context.Background()
+//	topic := NewMqttTopic("devices/+/temperature")
+//	topic.Configure(ctx, cconf.NewConfigParamsFromTuples(
+//		"connection.host", "localhost",
+//		"connection.port", 1883,
+//	))
+//	_ = topic.Open(ctx)
+//
+//	subscription, err := topic.Subscribe(ctx, "monitoring")
+//	message, err := subscription.Receive(ctx, 10000*time.Millisecond)
+type MqttTopic struct {
+	publisher     *MqttMessageQueue
+	config        *cconf.ConfigParams
+	references    cref.IReferences
+	lock          sync.Mutex
+	subscriptions []*MqttMessageQueue
+}
+
+// NewMqttTopic are creates a new instance of the topic.
+//
+//	Parameters:
+//		- name  string an MQTT topic name.
+func NewMqttTopic(name string) *MqttTopic {
+	c := &MqttTopic{
+		publisher:     NewMqttMessageQueue(name),
+		config:        cconf.NewEmptyConfigParams(),
+		subscriptions: make([]*MqttMessageQueue, 0),
+	}
+	return c
+}
+
+// Configure are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context	operation context
+//		- config    configuration parameters to be set.
+func (c *MqttTopic) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.config = config.Override(cconf.NewConfigParamsFromTuples(
+		"topic", c.Name(),
+	))
+	c.publisher.Configure(ctx, c.config)
+}
+
+// SetReferences are sets references to dependent components.
+//
+//	Parameters:
+//		- ctx context.Context	operation context
+//		- references 	references to locate the component dependencies.
+func (c *MqttTopic) SetReferences(ctx context.Context, references cref.IReferences) {
+	c.references = references
+	c.publisher.SetReferences(ctx, references)
+}
+
+// Name are gets the topic name.
+func (c *MqttTopic) Name() string {
+	return c.publisher.Name()
+}
+
+// IsOpen are checks if the component is opened.
+func (c *MqttTopic) IsOpen() bool {
+	return c.publisher.IsOpen()
+}
+
+// Open are opens the component.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//
+// Returns error or nil no errors occured.
+func (c *MqttTopic) Open(ctx context.Context) error {
+	return c.publisher.Open(ctx)
+}
+
+// Close are closes all subscriptions and frees used resources.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//
+// Returns error or nil no errors occured.
+func (c *MqttTopic) Close(ctx context.Context) error {
+	c.lock.Lock()
+	subscriptions := c.subscriptions
+	c.subscriptions = make([]*MqttMessageQueue, 0)
+	c.lock.Unlock()
+
+	for _, subscription := range subscriptions {
+		if err := subscription.Close(ctx); err != nil {
+			return err
+		}
+	}
+	return c.publisher.Close(ctx)
+}
+
+// Publish are sends a message to all subscriptions of the topic.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- envelope  a message envelop to be published.
+//
+// Returns error or nil for success.
+func (c *MqttTopic) Publish(ctx context.Context, envelope *cqueues.MessageEnvelope) error {
+	return c.publisher.Send(ctx, envelope)
+}
+
+// Subscribe are joins an MQTT shared subscription with the subscription name.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- subscription  a name of the subscription.
+//
+// Returns the subscription or error.
+func (c *MqttTopic) Subscribe(ctx context.Context, subscription string) (cqueues.ISubscription, error) {
+	if !c.IsOpen() {
+		return nil, cerr.NewInvalidStateError(cctx.GetTraceId(ctx), "NOT_OPENED", "Topic "+c.Name()+" is not opened")
+	}
+
+	config := c.config.Override(cconf.NewConfigParamsFromTuples(
+		"topic", "$share/"+subscription+"/"+c.Name(),
+		"options.autosubscribe", true,
+	))
+	// Every subscription needs its own client
+	if clientId := config.GetAsString("client_id"); clientId != "" {
+		config.SetAsObject("client_id", clientId+"-"+keys.IdGenerator.NextShort())
+	}
+
+	queue := NewMqttMessageQueue(subscription)
+	queue.Configure(ctx, config)
+	if c.references != nil {
+		queue.SetReferences(ctx, c.references)
+	}
+
+	// The client delivers every message to all matching subscriptions,
+	// so subscriptions do not share the connection of the topic
+	if !queue.localConnection {
+		queue.Connection = queue.createConnection()
+		queue.localConnection = true
+	}
+
+	if err := queue.Open(ctx); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.subscriptions = append(c.subscriptions, queue)
+	c.lock.Unlock()
+
+	return cqueues.NewTopicSubscription(queue, c.Name(), func(ctx context.Context) error {
+		return c.unsubscribe(ctx, queue)
+	}), nil
+}
+
+func (c *MqttTopic) unsubscribe(ctx context.Context, queue *MqttMessageQueue) error {
+	c.lock.Lock()
+	for index, subscription := range c.subscriptions {
+		if subscription == queue {
+			c.subscriptions = append(c.subscriptions[:index:index], c.subscriptions[index+1:]...)
+			break
+		}
+	}
+	c.lock.Unlock()
+
+	return queue.Close(ctx)
+}
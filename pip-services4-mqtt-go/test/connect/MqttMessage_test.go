@@ -0,0 +1,43 @@
+package test_connect
+
+import (
+	"testing"
+
+	"github.com/eclipse/paho.golang/paho"
+	connect "github.com/pip-services4/pip-services4-go/pip-services4-mqtt-go/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMqttMessageUserProperties(t *testing.T) {
+	packet := &paho.Publish{
+		PacketID: 12,
+		QoS:      1,
+		Topic:    "test",
+		Payload:  []byte("Test message"),
+		Properties: &paho.PublishProperties{
+			User: paho.UserProperties{
+				{Key: "trace_id", Value: "123"},
+				{Key: "tenant_id", Value: "tenant1"},
+			},
+		},
+	}
+
+	message := connect.NewMqttMessage(packet)
+	assert.Equal(t, uint16(12), message.MessageID())
+	assert.Equal(t, byte(1), message.Qos())
+	assert.Equal(t, "test", message.Topic())
+	assert.Equal(t, []byte("Test message"), message.Payload())
+	assert.Equal(t, map[string]string{"trace_id": "123", "tenant_id": "tenant1"}, message.UserProperties())
+
+	message = connect.NewMqttMessage(&paho.Publish{Topic: "test"})
+	assert.Nil(t, message.UserProperties())
+}
+
+func TestMqttMatchTopicFilter(t *testing.T) {
+	assert.True(t, connect.MatchTopicFilter("test", "test"))
+	assert.False(t, connect.MatchTopicFilter("test", "test/a"))
+	assert.True(t, connect.MatchTopicFilter("test/+", "test/a"))
+	assert.False(t, connect.MatchTopicFilter("test/+", "test/a/b"))
+	assert.True(t, connect.MatchTopicFilter("test/#", "test/a/b"))
+	assert.True(t, connect.MatchTopicFilter("$share/group/test/+", "test/a"))
+}
@@ -42,6 +42,23 @@ func (c *MessageQueueFixture) TestSendReceiveMessage(t *testing.T) {
 	assert.Equal(t, envelope1.TraceId, envelope2.TraceId)
 }
 
+func (c *MessageQueueFixture) TestSendReceiveHeaders(t *testing.T) {
+	envelope1 := queues.NewMessageEnvelope("123", "Test", []byte("Test message"))
+	envelope1.SetHeader("tenant_id", "tenant1")
+	envelope1.SetHeader("schema_version", "2")
+	sndErr := c.queue.Send(context.Background(), envelope1)
+	assert.Nil(t, sndErr)
+
+	envelope2, rcvErr := c.queue.Receive(context.Background(), 10000*time.Millisecond)
+	assert.Nil(t, rcvErr)
+	assert.NotNil(t, envelope2)
+	assert.Equal(t, envelope1.MessageId, envelope2.MessageId)
+	assert.Equal(t, envelope1.MessageType, envelope2.MessageType)
+	assert.Equal(t, envelope1.TraceId, envelope2.TraceId)
+	assert.Equal(t, "tenant1", envelope2.GetHeader("tenant_id"))
+	assert.Equal(t, "2", envelope2.GetHeader("schema_version"))
+}
+
 func (c *MessageQueueFixture) TestMessageCount(t *testing.T) {
 	envelope1 := queues.NewMessageEnvelope("123", "Test", []byte("Test message"))
 	c.queue.Send(context.Background(), envelope1)
@@ -193,3 +210,28 @@ func (c *TestMsgReceiver) ReceiveMessage(ctx context.Context, envelope *queues.M
 	c._lock.Unlock()
 	return nil
 }
+
+func (c *MessageQueueFixture) TestSendReceiveBatch(t *testing.T) {
+	envelopes := []*queues.MessageEnvelope{
+		queues.NewMessageEnvelope("123", "Test", []byte("Test message 1")),
+		queues.NewMessageEnvelope("123", "Test", []byte("Test message 2")),
+		queues.NewMessageEnvelope("123", "Test", []byte("Test message 3")),
+	}
+	sndErr := c.queue.SendBatch(context.Background(), envelopes)
+	assert.Nil(t, sndErr)
+
+	// Wait until all messages are delivered
+	time.Sleep(500 * time.Millisecond)
+
+	received, rcvErr := c.queue.ReceiveBatch(context.Background(), int64(len(envelopes)), 10000*time.Millisecond)
+	assert.Nil(t, rcvErr)
+
+	assert.Len(t, received, len(envelopes))
+	for index, envelope := range received {
+		assert.Equal(t, envelopes[index].MessageType, envelope.MessageType)
+		assert.Equal(t, envelopes[index].Message, envelope.Message)
+	}
+
+	cmplErr := c.queue.CompleteBatch(context.Background(), received)
+	assert.Nil(t, cmplErr)
+}
@@ -7,6 +7,7 @@ import (
 
 	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
 	queues "github.com/pip-services4/pip-services4-go/pip-services4-mqtt-go/queues"
+	"github.com/stretchr/testify/assert"
 )
 
 type mqttMessageQueueTest struct {
@@ -14,7 +15,7 @@ type mqttMessageQueueTest struct {
 	fixture *MessageQueueFixture
 }
 
-func newMqttMessageQueueTest() *mqttMessageQueueTest {
+func newMqttMessageQueueTest(options ...any) *mqttMessageQueueTest {
 	mqttUri := os.Getenv("MQTT_SERVICE_URI")
 	mqttHost := os.Getenv("MQTT_SERVICE_HOST")
 	if mqttHost == "" {
@@ -44,8 +45,7 @@ func newMqttMessageQueueTest() *mqttMessageQueueTest {
 		return nil
 	}
 
-	queue := queues.NewMqttMessageQueue(mqttTopic)
-	queue.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+	config := cconf.NewConfigParamsFromTuples(
 		"connection.uri", mqttUri,
 		"connection.host", mqttHost,
 		"connection.port", mqttPort,
@@ -53,7 +53,11 @@ func newMqttMessageQueueTest() *mqttMessageQueueTest {
 		"credential.password", mqttPassword,
 		"options.autosubscribe", true,
 		"options.serialize_envelope", true,
-	))
+	)
+	config = config.Override(cconf.NewConfigParamsFromTuples(options...))
+
+	queue := queues.NewMqttMessageQueue(mqttTopic)
+	queue.Configure(context.Background(), config)
 
 	fixture := NewMessageQueueFixture(queue)
 
@@ -109,4 +113,45 @@ func TestMqttMessageQueue(t *testing.T) {
 	c.setup(t)
 	t.Run("On Message", c.fixture.TestOnMessage)
 	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Receive Batch", c.fixture.TestSendReceiveBatch)
+	c.teardown(t)
+}
+
+func TestMqtt5MessageQueue(t *testing.T) {
+	c := newMqttMessageQueueTest(
+		"options.protocol_version", 5,
+		"options.serialize_envelope", false,
+	)
+	if c == nil {
+		return
+	}
+
+	c.setup(t)
+	t.Run("Send Receive Message", c.fixture.TestSendReceiveMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Receive Headers", c.fixture.TestSendReceiveHeaders)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("On Message", c.fixture.TestOnMessage)
+	c.teardown(t)
+}
+
+func TestMqttMessageQueueMaxDeliveryCount(t *testing.T) {
+	c := newMqttMessageQueueTest(
+		"options.serialize_envelope", false,
+		"options.max_delivery_count", 3,
+	)
+	if c == nil {
+		return
+	}
+
+	// MQTT 3.1.1 cannot pass delivery count without serialized envelope
+	err := c.queue.Open(context.Background())
+	assert.NotNil(t, err)
+	assert.False(t, c.queue.IsOpen())
 }
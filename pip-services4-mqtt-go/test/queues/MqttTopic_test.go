@@ -0,0 +1,54 @@
+package test_queues
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	queues "github.com/pip-services4/pip-services4-go/pip-services4-mqtt-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMqttTopic(t *testing.T) {
+	mqttUri := os.Getenv("MQTT_SERVICE_URI")
+	mqttHost := os.Getenv("MQTT_SERVICE_HOST")
+	if mqttHost == "" {
+		mqttHost = "localhost"
+	}
+
+	mqttPort := os.Getenv("MQTT_SERVICE_PORT")
+	if mqttPort == "" {
+		mqttPort = "1883"
+	}
+
+	mqttUser := os.Getenv("MQTT_USER")
+	if mqttUser == "" {
+		mqttUser = "mqtt"
+	}
+	mqttPassword := os.Getenv("MQTT_PASS")
+	if mqttPassword == "" {
+		mqttPassword = "mqtt"
+	}
+
+	if mqttUri == "" && mqttHost == "" {
+		return
+	}
+
+	topic := queues.NewMqttTopic("test/topic")
+	topic.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"connection.uri", mqttUri,
+		"connection.host", mqttHost,
+		"connection.port", mqttPort,
+		"credential.username", mqttUser,
+		"credential.password", mqttPassword,
+		"options.serialize_envelope", true,
+	))
+
+	err := topic.Open(context.Background())
+	assert.Nil(t, err)
+	defer topic.Close(context.Background())
+
+	fixture := NewTopicFixture(topic)
+	t.Run("Publish Subscribe", fixture.TestPublishSubscribe)
+}
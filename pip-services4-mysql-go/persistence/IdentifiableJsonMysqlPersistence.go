@@ -6,6 +6,7 @@ import (
 
 	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
 	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 )
 
 // IdentifiableJsonMySqlPersistence is an abstract persistence component that stores data in MySQL in JSON or JSONB fields
@@ -104,6 +105,7 @@ type IdentifiableJsonMySqlPersistence[T any, K any] struct {
 func InheritIdentifiableJsonMySqlPersistence[T any, K any](overrides IMySqlPersistenceOverrides[T], tableName string) *IdentifiableJsonMySqlPersistence[T, K] {
 	c := &IdentifiableJsonMySqlPersistence[T, K]{}
 	c.IdentifiableMySqlPersistence = InheritIdentifiableMySqlPersistence[T, K](overrides, tableName)
+	c.FilterCompiler = NewJsonMySqlFilterCompiler("data")
 	return c
 }
 
@@ -221,6 +223,15 @@ func (c *IdentifiableJsonMySqlPersistence[T, K]) ConvertFromPublicPartial(value
 // Returns: receives updated item or error.
 func (c *IdentifiableJsonMySqlPersistence[T, K]) UpdatePartially(ctx context.Context,
 	id K, data cdata.AnyValueMap) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updatePartially(ctx, id, data)
+		})
+}
+
+func (c *IdentifiableJsonMySqlPersistence[T, K]) updatePartially(ctx context.Context,
+	id K, data cdata.AnyValueMap) (result T, err error) {
+	data = c.trackPartialData(data)
 	buf, toJsonErr := cconv.JsonConverter.ToJson(data.Value())
 	if toJsonErr != nil {
 		return result, toJsonErr
@@ -229,14 +240,14 @@ func (c *IdentifiableJsonMySqlPersistence[T, K]) UpdatePartially(ctx context.Con
 	query := "UPDATE " + c.QuotedTableName() + " SET `data`=JSON_MERGE_PATCH(data,?) WHERE id=?"
 	values := []any{buf, id}
 
-	_, err = c.IdentifiableMySqlPersistence.Client.ExecContext(ctx, query, values...)
+	_, err = c.IdentifiableMySqlPersistence.GetClient(ctx).ExecContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
 
 	// Getting result
 	query = "SELECT * FROM " + c.QuotedTableName() + " WHERE id=?"
-	rows, err := c.Client.QueryContext(ctx, query, []any{id}...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, []any{id}...)
 	if err != nil {
 		return result, err
 	}
@@ -257,3 +268,22 @@ func (c *IdentifiableJsonMySqlPersistence[T, K]) UpdatePartially(ctx context.Con
 	}
 	return result, rows.Err()
 }
+
+// UpdateManyPartially updates the same few selected fields in multiple data items in a single statement.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- ids               ids of data items to be updated.
+//		- data              a map with fields to be updated.
+//
+// Returns: updated items and errors of every item in the order of the given ids, or error.
+func (c *IdentifiableJsonMySqlPersistence[T, K]) UpdateManyPartially(ctx context.Context,
+	ids []K, data cdata.AnyValueMap) ([]T, []error, error) {
+
+	data = c.trackPartialData(data)
+	buf, toJsonErr := cconv.JsonConverter.ToJson(data.Value())
+	if toJsonErr != nil {
+		return nil, nil, toJsonErr
+	}
+	return c.trackUpdateManyPartially(ctx, ids, "`data`=JSON_MERGE_PATCH(data,?)", []any{buf})
+}
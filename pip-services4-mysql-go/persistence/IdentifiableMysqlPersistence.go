@@ -4,6 +4,7 @@ import (
 	"context"
 
 	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
 	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 
 	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
@@ -21,6 +22,10 @@ import (
 // In complex scenarios child classes can implement additional operations by
 // accessing c._collection and c._model properties.
 //
+// Write operations emit created, updated and deleted change events to listeners
+// added by AddChangeListener. In outbox mode the events are also written to the outbox table
+// in the same transaction as the data.
+//
 //	Configuration parameters
 //		- collection:               (optional) MySQL collection name
 //		- connection(s):
@@ -36,6 +41,7 @@ import (
 //			- connect_timeout:      (optional) number of milliseconds to wait before timing out when connecting a new client (default: 0)
 //			- idle_timeout:         (optional) number of milliseconds a client must sit idle in the pool and not be checked out (default: 10000)
 //			- max_pool_size:        (optional) maximum number of clients the pool should contain (default: 10)
+//			- outbox:               (optional) write change events to the outbox table in the same transaction as the data (default: false)
 //
 //	References
 //		- *:logger:*:*:1.0           (optional) ILogger components to pass log messages components to pass log messages
@@ -115,6 +121,25 @@ func InheritIdentifiableMySqlPersistence[T any, K any](overrides IMySqlPersisten
 	return c
 }
 
+// GetPageByFilterWithToken gets a page of data items retrieved by a given filter using keyset paging.
+// The "id" field is added to sort parameters when it is missing to make the order unique.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- filter            (optional) a filter JSON object
+//		- paging            paging parameters with a token from the previous page
+//		- sort              sort parameters
+//		- selection         (optional) projection JSON object, it must include the sort fields
+//		- args              (optional) query parameters referenced by the filter
+//	Returns: data page or error.
+func (c *IdentifiableMySqlPersistence[T, K]) GetPageByFilterWithToken(ctx context.Context,
+	filter string, paging cquery.TokenizedPagingParams, sort cquery.SortParams, selection string,
+	args ...any) (page cquery.TokenizedDataPage[T], err error) {
+
+	return c.MySqlPersistence.GetPageByFilterWithToken(ctx, filter, paging,
+		cpersist.AddKeysetSortField(sort, "id"), selection, args...)
+}
+
 // GetListByIds gets a list of data items retrieved by given unique ids.
 //
 //	Parameters:
@@ -126,9 +151,9 @@ func (c *IdentifiableMySqlPersistence[T, K]) GetListByIds(ctx context.Context,
 
 	ln := len(ids)
 	params := c.GenerateParameters(ln)
-	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE id IN(" + params + ")"
+	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE " + c.filterDeleted(ctx, "id IN("+params+")")
 
-	rows, err := c.Client.QueryContext(ctx, query, ItemsToAnySlice(ids)...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, ItemsToAnySlice(ids)...)
 	if err != nil {
 		return nil, err
 	}
@@ -157,6 +182,7 @@ func (c *IdentifiableMySqlPersistence[T, K]) GetListByIds(ctx context.Context,
 }
 
 // GetOneById gets a data item by its unique id.
+// In soft-delete mode deleted items are not returned unless the context is created by cpersist.WithDeleted.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -165,9 +191,9 @@ func (c *IdentifiableMySqlPersistence[T, K]) GetListByIds(ctx context.Context,
 // Returns: data item or error.
 func (c *IdentifiableMySqlPersistence[T, K]) GetOneById(ctx context.Context, id K) (item T, err error) {
 
-	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE id=?"
+	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE " + c.filterDeleted(ctx, "id=?")
 
-	rows, err := c.Client.QueryContext(ctx, query, id)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, id)
 	if err != nil {
 		return item, err
 	}
@@ -195,7 +221,10 @@ func (c *IdentifiableMySqlPersistence[T, K]) Create(ctx context.Context, item T)
 	newItem := c.cloneItem(item)
 	newItem = GenerateObjectIdIfNotExists[T](newItem)
 
-	return c.MySqlPersistence.Create(ctx, newItem)
+	return c.trackChange(ctx, cpersist.ChangeTypeCreated, GetObjectId[K](newItem), false,
+		func(ctx context.Context) (T, error) {
+			return c.MySqlPersistence.Create(ctx, newItem)
+		})
 }
 
 // Set a data item. If the data item exists it updates it,
@@ -206,6 +235,14 @@ func (c *IdentifiableMySqlPersistence[T, K]) Create(ctx context.Context, item T)
 //		- item              an item to be set.
 //	Returns: (optional)  updated item or error.
 func (c *IdentifiableMySqlPersistence[T, K]) Set(ctx context.Context, item T) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, GetObjectId[K](item), true,
+		func(ctx context.Context) (T, error) {
+			return c.set(ctx, item)
+		})
+}
+
+func (c *IdentifiableMySqlPersistence[T, K]) set(ctx context.Context, item T) (result T, err error) {
+	item = c.trackItem(item, true)
 	objMap, convErr := c.Overrides.ConvertFromPublic(item)
 	if convErr != nil {
 		return result, convErr
@@ -225,14 +262,14 @@ func (c *IdentifiableMySqlPersistence[T, K]) Set(ctx context.Context, item T) (r
 	query := "INSERT INTO " + c.QuotedTableName() + " (" + columnsStr + ") VALUES (" + paramsStr + ")"
 	query += " ON DUPLICATE KEY UPDATE " + setParams
 
-	_, err = c.Client.ExecContext(ctx, query, values...)
+	_, err = c.GetClient(ctx).ExecContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
 
 	// Getting result
 	query = "SELECT * FROM " + c.QuotedTableName() + " WHERE id=?"
-	rows, err := c.Client.QueryContext(ctx, query, []any{id}...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, []any{id}...)
 	if err != nil {
 		return result, err
 	}
@@ -261,6 +298,14 @@ func (c *IdentifiableMySqlPersistence[T, K]) Set(ctx context.Context, item T) (r
 //		- item              an item to be updated.
 //	Returns          (optional)  updated item or error.
 func (c *IdentifiableMySqlPersistence[T, K]) Update(ctx context.Context, item T) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, GetObjectId[K](item), true,
+		func(ctx context.Context) (T, error) {
+			return c.update(ctx, item)
+		})
+}
+
+func (c *IdentifiableMySqlPersistence[T, K]) update(ctx context.Context, item T) (result T, err error) {
+	item = c.trackItem(item, false)
 	objMap, convErr := c.Overrides.ConvertFromPublic(item)
 	if convErr != nil {
 		return result, convErr
@@ -272,14 +317,14 @@ func (c *IdentifiableMySqlPersistence[T, K]) Update(ctx context.Context, item T)
 
 	query := "UPDATE " + c.QuotedTableName() + " SET " + paramsStr + " WHERE id=?"
 
-	_, err = c.Client.ExecContext(ctx, query, values...)
+	_, err = c.GetClient(ctx).ExecContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
 
 	// Getting result
 	query = "SELECT * FROM " + c.QuotedTableName() + " WHERE id=?"
-	rows, err := c.Client.QueryContext(ctx, query, []any{id}...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, []any{id}...)
 	if err != nil {
 		return result, err
 	}
@@ -308,6 +353,14 @@ func (c *IdentifiableMySqlPersistence[T, K]) Update(ctx context.Context, item T)
 //		- data              a map with fields to be updated.
 //	Returns: updated item or error.
 func (c *IdentifiableMySqlPersistence[T, K]) UpdatePartially(ctx context.Context, id K, data cdata.AnyValueMap) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updatePartially(ctx, id, data)
+		})
+}
+
+func (c *IdentifiableMySqlPersistence[T, K]) updatePartially(ctx context.Context, id K, data cdata.AnyValueMap) (result T, err error) {
+	data = c.trackPartialData(data)
 	objMap, convErr := c.Overrides.ConvertFromPublicPartial(data.Value())
 	if convErr != nil {
 		return result, convErr
@@ -318,13 +371,13 @@ func (c *IdentifiableMySqlPersistence[T, K]) UpdatePartially(ctx context.Context
 
 	query := "UPDATE " + c.QuotedTableName() + " SET " + paramsStr + " WHERE id=?"
 
-	_, err = c.Client.ExecContext(ctx, query, values...)
+	_, err = c.GetClient(ctx).ExecContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
 
 	query = "SELECT * FROM " + c.QuotedTableName() + " WHERE id=?"
-	rows, err := c.Client.QueryContext(ctx, query, []any{id}...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, []any{id}...)
 	if err != nil {
 		return result, err
 	}
@@ -346,21 +399,42 @@ func (c *IdentifiableMySqlPersistence[T, K]) UpdatePartially(ctx context.Context
 }
 
 // DeleteById deletes a data item by its unique id.
+// In soft-delete mode the item is marked as deleted instead.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- id                an id of the item to be deleted
 //	Returns: (optional)  deleted item or error.
 func (c *IdentifiableMySqlPersistence[T, K]) DeleteById(ctx context.Context, id K) (result T, err error) {
+	if c.isSoftDelete() {
+		return c.markDeletedById(ctx, id, true)
+	}
+	return c.PurgeById(ctx, id)
+}
+
+// PurgeById physically deletes a data item by its unique id regardless of soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- id                an id of the item to be deleted
+//	Returns: (optional)  deleted item or error.
+func (c *IdentifiableMySqlPersistence[T, K]) PurgeById(ctx context.Context, id K) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeDeleted, id, false,
+		func(ctx context.Context) (T, error) {
+			return c.purgeById(ctx, id)
+		})
+}
+
+func (c *IdentifiableMySqlPersistence[T, K]) purgeById(ctx context.Context, id K) (result T, err error) {
 	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE id=?"
 
-	rows, err := c.Client.QueryContext(ctx, query, []any{id}...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, []any{id}...)
 	if err != nil {
 		return result, err
 	}
 
 	query = "DELETE FROM " + c.QuotedTableName() + " WHERE id=?"
-	_, err = c.Client.ExecContext(ctx, query, []any{id}...)
+	_, err = c.GetClient(ctx).ExecContext(ctx, query, []any{id}...)
 	if err != nil {
 		return result, err
 	}
@@ -381,7 +455,61 @@ func (c *IdentifiableMySqlPersistence[T, K]) DeleteById(ctx context.Context, id
 	return result, rows.Err()
 }
 
+// RestoreById restores a data item marked as deleted in soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- id                an id of the item to be restored
+//	Returns: (optional)  restored item or error.
+func (c *IdentifiableMySqlPersistence[T, K]) RestoreById(ctx context.Context, id K) (result T, err error) {
+	return c.markDeletedById(ctx, id, false)
+}
+
+func (c *IdentifiableMySqlPersistence[T, K]) markDeletedById(ctx context.Context, id K, deleted bool) (result T, err error) {
+	changeType := cpersist.ChangeTypeUpdated
+	if deleted {
+		changeType = cpersist.ChangeTypeDeleted
+	}
+	return c.trackChange(ctx, changeType, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updateDeletedById(ctx, id, deleted)
+		})
+}
+
+// updateDeletedById updates the deletion flag and reads the item back, since MySQL does not return updated rows.
+func (c *IdentifiableMySqlPersistence[T, K]) updateDeletedById(ctx context.Context, id K, deleted bool) (result T, err error) {
+	query := "UPDATE " + c.QuotedTableName() + " SET " + c.composeDeletedSet(deleted) + " WHERE id=?"
+
+	_, err = c.GetClient(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return result, err
+	}
+
+	query = "SELECT * FROM " + c.QuotedTableName() + " WHERE id=?"
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, id)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return result, rows.Err()
+	}
+
+	result, convErr := c.Overrides.ConvertToPublic(rows)
+	if convErr != nil {
+		return result, convErr
+	}
+	if deleted {
+		c.Logger.Trace(ctx, "Marked as deleted in %s with id = %s", c.TableName, id)
+	} else {
+		c.Logger.Trace(ctx, "Restored in %s with id = %s", c.TableName, id)
+	}
+	return result, nil
+}
+
 // DeleteByIds deletes multiple data items by their unique ids.
+// In soft-delete mode the items are marked as deleted instead.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -392,9 +520,21 @@ func (c *IdentifiableMySqlPersistence[T, K]) DeleteByIds(ctx context.Context, id
 	ln := len(ids)
 	paramsStr := c.GenerateParameters(ln)
 
-	query := "DELETE FROM " + c.QuotedTableName() + " WHERE id IN(" + paramsStr + ")"
+	condition := "id IN(" + paramsStr + ")"
+	query := "DELETE FROM " + c.QuotedTableName() + " WHERE " + condition
+	if c.isSoftDelete() {
+		condition = c.filterDeleted(ctx, condition)
+		query = "UPDATE " + c.QuotedTableName() + " SET " + c.composeDeletedSet(true) + " WHERE " + condition
+	}
+
+	if c.isTrackingChanges() {
+		return c.trackChanges(ctx, cpersist.ChangeTypeDeleted, ids, c.isSoftDelete(),
+			func(ctx context.Context) ([]T, error) {
+				return c.deleteItems(ctx, condition, query, ItemsToAnySlice(ids)...)
+			})
+	}
 
-	result, err := c.Client.ExecContext(ctx, query, ItemsToAnySlice(ids)...)
+	result, err := c.GetClient(ctx).ExecContext(ctx, query, ItemsToAnySlice(ids)...)
 	if err != nil {
 		return err
 	}
@@ -409,3 +549,51 @@ func (c *IdentifiableMySqlPersistence[T, K]) DeleteByIds(ctx context.Context, id
 	}
 	return nil
 }
+
+// deleteItems executes a delete query and returns the deleted items. MySQL does not return deleted rows,
+// so the items matching the condition are read before the delete, and in soft-delete mode they are read back.
+func (c *IdentifiableMySqlPersistence[T, K]) deleteItems(ctx context.Context, condition string, query string,
+	values ...any) ([]T, error) {
+
+	items, err := c.readItems(ctx, "SELECT * FROM "+c.QuotedTableName()+" WHERE "+condition, values...)
+	if err != nil || len(items) == 0 {
+		return items, err
+	}
+
+	if _, err = c.GetClient(ctx).ExecContext(ctx, query, values...); err != nil {
+		return nil, err
+	}
+
+	if c.isSoftDelete() {
+		ids := make([]any, len(items))
+		for i, item := range items {
+			ids[i] = GetObjectId[K](item)
+		}
+		query = "SELECT * FROM " + c.QuotedTableName() + " WHERE id IN(" + c.GenerateParameters(len(ids)) + ")"
+		if items, err = c.readItems(ctx, query, ids...); err != nil {
+			return nil, err
+		}
+	}
+
+	c.Logger.Trace(ctx, "Deleted %d items from %s", len(items), c.TableName)
+	return items, nil
+}
+
+// readItems executes a select query and reads the items.
+func (c *IdentifiableMySqlPersistence[T, K]) readItems(ctx context.Context, query string, values ...any) ([]T, error) {
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]T, 0)
+	for rows.Next() {
+		item, convErr := c.Overrides.ConvertToPublic(rows)
+		if convErr != nil {
+			return items, convErr
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
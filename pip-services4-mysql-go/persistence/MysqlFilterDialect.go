@@ -0,0 +1,85 @@
+package persistence
+
+import (
+	"strings"
+
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// MySqlFilterDialect defines MySQL syntax to compile FilterExpression into parameterized queries.
+//
+// When JsonColumn is set, fields other than "id" are extracted as unquoted values from the JSON
+// document stored in that column using ->> operator. Extracted fields are cast to decimal
+// when they are compared with numbers, so ranges of numeric fields are not compared as strings.
+type MySqlFilterDialect struct {
+	JsonColumn string
+	// Compares fields with numbers only when values have numeric types, like keyset values
+	typedValues bool
+}
+
+// NewMySqlFilterCompiler creates a filter compiler for plain MySQL tables.
+//
+//	Returns: a new filter compiler.
+func NewMySqlFilterCompiler() *cpersist.SqlFilterCompiler {
+	return cpersist.NewSqlFilterCompiler(&MySqlFilterDialect{})
+}
+
+// NewJsonMySqlFilterCompiler creates a filter compiler for MySQL tables that keep data in JSON column.
+//
+//	Parameters:
+//		- jsonColumn a name of the JSON column.
+//	Returns: a new filter compiler.
+func NewJsonMySqlFilterCompiler(jsonColumn string) *cpersist.SqlFilterCompiler {
+	return cpersist.NewSqlFilterCompiler(&MySqlFilterDialect{JsonColumn: jsonColumn})
+}
+
+// FormatField converts a field name into a quoted column or a JSON path extraction.
+func (c *MySqlFilterDialect) FormatField(field string) string {
+	if c.JsonColumn != "" && field != "id" {
+		return c.JsonColumn + "->>'$." + field + "'"
+	}
+	return "`" + strings.ReplaceAll(field, ".", "`.`") + "`"
+}
+
+// FormatComparison casts a field extracted from JSON document to decimal when it is compared with numbers.
+// Values of FilterParams are strings, so numeric strings are treated as numbers by range operators.
+// Equality and "in" operators compare with numbers only when the values have numeric types.
+func (c *MySqlFilterDialect) FormatComparison(field string, operator cpersist.FilterOperator, values []any) string {
+	column := c.FormatField(field)
+	if c.JsonColumn == "" || field == "id" ||
+		!cpersist.IsSqlNumericComparison(operator, values, !c.typedValues) {
+		return column
+	}
+	return "CAST(" + column + " AS DECIMAL(65,30))"
+}
+
+// FormatParameter returns a positional parameter placeholder "?".
+func (c *MySqlFilterDialect) FormatParameter(index int) string {
+	return "?"
+}
+
+// FormatProjection converts included fields into a SELECT column list.
+// For JSON tables the fields are extracted from the JSON document
+// and composed into a new document with JSON_OBJECT function.
+func (c *MySqlFilterDialect) FormatProjection(fields []*cpersist.ProjectionField) string {
+	if c.JsonColumn == "" {
+		columns := make([]string, len(fields))
+		for i, field := range fields {
+			columns[i] = c.FormatField(field.Name)
+		}
+		return strings.Join(columns, ",")
+	}
+	return c.formatJsonObject(fields) + " AS " + c.JsonColumn
+}
+
+func (c *MySqlFilterDialect) formatJsonObject(fields []*cpersist.ProjectionField) string {
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		value := "JSON_EXTRACT(" + c.JsonColumn + ",'$." + field.Path + "')"
+		if len(field.Children) > 0 {
+			value = c.formatJsonObject(field.Children)
+		}
+		values[i] = "'" + field.Name + "'," + value
+	}
+	return "JSON_OBJECT(" + strings.Join(values, ",") + ")"
+}
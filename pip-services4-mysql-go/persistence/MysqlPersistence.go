@@ -20,6 +20,7 @@ import (
 	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
 	conn "github.com/pip-services4/pip-services4-go/pip-services4-mysql-go/connect"
 	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 )
 
 type IMySqlPersistenceOverrides[T any] interface {
@@ -52,6 +53,12 @@ type IMySqlPersistenceOverrides[T any] interface {
 //			- connect_timeout:      (optional) number of milliseconds to wait before timing out when connecting a new client (default: 0)
 //			- idle_timeout:         (optional) number of milliseconds a client must sit idle in the pool and not be checked out (default: 10000)
 //			- max_pool_size:        (optional) maximum number of clients the pool should contain (default: 10)
+//			- migrations_dir:       (optional) a directory to load migrations from, see cpersist.LoadMigrationsFromDirectory
+//			- migrations_table:     (optional) a table that tracks applied migrations (default: <table>_migrations)
+//			- migrations_dry_run:   (optional) log pending migrations instead of applying them (default: false)
+//			- soft_delete:          (optional) mark items that implement ITrackable as deleted instead of removing them (default: false)
+//			- outbox:               (optional) write change events to the outbox table in the same transaction as the data (default: false)
+//			- outbox_table:         (optional) a table that keeps change events (default: <table>_outbox)
 //
 //	References:
 //		- *:logger:*:*:1.0           (optional) ILogger components to pass log messages
@@ -84,7 +91,7 @@ type IMySqlPersistenceOverrides[T any] interface {
 //
 //		query := "SELECT * FROM " + c.QuotedTableName() + " WHERE name=?"
 //
-//		rows, err := c.Client.QueryContext(ctx, query, name)
+//		rows, err := c.GetClient(ctx).QueryContext(ctx, query, name)
 //		if err != nil {
 //			return item, err
 //		}
@@ -118,14 +125,14 @@ type IMySqlPersistenceOverrides[T any] interface {
 //		query := "INSERT INTO " + c.QuotedTableName() + " (" + columnsStr + ") VALUES (" + paramsStr + ")"
 //		query += " ON DUPLICATE KEY UPDATE " + setParams
 //
-//		_, err = c.Client.ExecContext(ctx, query, values...)
+//		_, err = c.GetClient(ctx).ExecContext(ctx, query, values...)
 //		if err != nil {
 //			return result, err
 //		}
 //
 //		// Getting result
 //		query = "SELECT * FROM " + c.QuotedTableName() + " WHERE id=?"
-//		rows, err := c.Client.QueryContext(ctx, query, []any{id}...)
+//		rows, err := c.GetClient(ctx).QueryContext(ctx, query, []any{id}...)
 //		if err != nil {
 //			return result, err
 //		}
@@ -188,6 +195,23 @@ type MySqlPersistence[T any] struct {
 	//The MySql table object.
 	TableName   string
 	MaxPageSize int
+	// The compiler of FilterExpression into parameterized SQL conditions.
+	FilterCompiler *cpersist.SqlFilterCompiler
+	// The directory to load migrations from on opening.
+	MigrationsDir string
+	// The name of the table that tracks applied migrations. If not set "<table>_migrations" is used.
+	MigrationsTableName string
+	// Defines if pending migrations are only logged and not applied.
+	MigrationsDryRun bool
+	// Defines if deleted items of ITrackable types are only marked as deleted.
+	SoftDelete bool
+	// Defines if change events are written to the outbox table in the same transaction as the data.
+	Outbox bool
+	// The name of the table that keeps change events. If not set "<table>_outbox" is used.
+	OutboxTableName string
+
+	migrations []*cpersist.Migration
+	changes    *cpersist.ChangeNotifier
 
 	// Defines channel which closed before closing persistence and signals about terminating
 	// all going processes
@@ -217,6 +241,8 @@ func InheritMySqlPersistence[T any](overrides IMySqlPersistenceOverrides[T], tab
 		schemaStatements: make([]string, 0),
 		Logger:           clog.NewCompositeLogger(),
 		MaxPageSize:      100,
+		FilterCompiler:   NewMySqlFilterCompiler(),
+		changes:          cpersist.NewChangeNotifier(),
 		TableName:        tableName,
 		JsonConvertor:    cconv.NewDefaultCustomTypeJsonConvertor[T](),
 		JsonMapConvertor: cconv.NewDefaultCustomTypeJsonConvertor[map[string]any](),
@@ -243,6 +269,12 @@ func (c *MySqlPersistence[T]) Configure(ctx context.Context, config *cconf.Confi
 	c.TableName = config.GetAsStringWithDefault("collection", c.TableName)
 	c.TableName = config.GetAsStringWithDefault("table", c.TableName)
 	c.MaxPageSize = config.GetAsIntegerWithDefault("options.max_page_size", c.MaxPageSize)
+	c.MigrationsDir = config.GetAsStringWithDefault("options.migrations_dir", c.MigrationsDir)
+	c.MigrationsTableName = config.GetAsStringWithDefault("options.migrations_table", c.MigrationsTableName)
+	c.MigrationsDryRun = config.GetAsBooleanWithDefault("options.migrations_dry_run", c.MigrationsDryRun)
+	c.SoftDelete = config.GetAsBooleanWithDefault("options.soft_delete", c.SoftDelete)
+	c.Outbox = config.GetAsBooleanWithDefault("options.outbox", c.Outbox)
+	c.OutboxTableName = config.GetAsStringWithDefault("options.outbox_table", c.OutboxTableName)
 	c.SchemaName = config.GetAsStringWithDefault("schema", c.SchemaName)
 }
 
@@ -451,6 +483,20 @@ func (c *MySqlPersistence[T]) QuotedTableName() string {
 	return c.QuoteIdentifier(c.TableName)
 }
 
+// GetClient gets a client to execute queries. When the context carries a transaction
+// started on the same connection the transaction is returned, otherwise the connection pool is used.
+// Child components shall use it instead of Client to take part in transactions.
+//
+//	Parameters:
+//		- ctx context.Context a context that may contain a transaction.
+//	Returns: a client to execute queries.
+func (c *MySqlPersistence[T]) GetClient(ctx context.Context) conn.IMySqlClient {
+	if tx, ok := conn.GetMySqlTransaction(ctx, c.Client); ok {
+		return tx
+	}
+	return c.Client
+}
+
 // IsOpen checks if the component is opened.
 //
 //	Returns: true if the component has been opened and false otherwise.
@@ -513,8 +559,14 @@ func (c *MySqlPersistence[T]) Open(ctx context.Context) (err error) {
 	// Define database schema
 	c.Overrides.DefineSchema()
 
-	// Recreate objects
+	// Recreate objects and apply pending migrations
 	err = c.CreateSchema(ctx)
+	if err == nil {
+		err = c.Migrate(ctx)
+	}
+	if err == nil {
+		err = c.createOutbox(ctx)
+	}
 	if err != nil {
 		c.Client = nil
 		err = cerr.NewConnectionError(cctx.GetTraceId(ctx), "CONNECT_FAILED", "Connection to mysql failed").WithCause(err)
@@ -565,7 +617,7 @@ func (c *MySqlPersistence[T]) Clear(ctx context.Context) error {
 		return errors.New("Table name is not defined")
 	}
 
-	rows, err := c.Client.QueryContext(ctx, "DELETE FROM "+c.QuotedTableName())
+	rows, err := c.GetClient(ctx).QueryContext(ctx, "DELETE FROM "+c.QuotedTableName())
 	if err != nil {
 		return cerr.
 			NewConnectionError(cctx.GetTraceId(ctx), "CONNECT_FAILED", "Connection to mysql failed").
@@ -591,7 +643,7 @@ func (c *MySqlPersistence[T]) CreateSchema(ctx context.Context) (err error) {
 	c.Logger.Debug(ctx, "Table "+c.QuotedTableName()+" does not exist. Creating database objects...")
 
 	for _, dml := range c.schemaStatements {
-		result, err := c.Client.QueryContext(ctx, dml)
+		result, err := c.GetClient(ctx).QueryContext(ctx, dml)
 		if err != nil {
 			c.Logger.Error(ctx, err, "Failed to autocreate database object")
 			return err
@@ -604,7 +656,7 @@ func (c *MySqlPersistence[T]) CreateSchema(ctx context.Context) (err error) {
 func (c *MySqlPersistence[T]) checkTableExists(ctx context.Context) (bool, error) {
 	// Check if table exist to determine either to auto create objects
 	query := "SHOW TABLES LIKE '" + c.TableName + "'"
-	result, err := c.Client.QueryContext(ctx, query)
+	result, err := c.GetClient(ctx).QueryContext(ctx, query)
 	if err != nil {
 		return false, err
 	}
@@ -737,6 +789,17 @@ func (c *MySqlPersistence[T]) GenerateColumnsAndValues(objMap map[string]any) ([
 	return columns, values
 }
 
+// CompileFilter converts a filter expression into a parameterized condition
+// that can be passed to GetPageByFilter, GetListByFilter, GetCountByFilter and other methods
+// together with the returned query parameters.
+//
+//	Parameters:
+//		- filter a filter expression, usually created by cpersist.NewFilterExpressionFromParams
+//	Returns: condition like "`key`=?", query parameters or error if filter is invalid.
+func (c *MySqlPersistence[T]) CompileFilter(filter *cpersist.FilterExpression) (string, []any, error) {
+	return c.FilterCompiler.Compile(filter, 1)
+}
+
 // GetPageByFilter gets a page of data items retrieved by a given filter and sorted according to sort parameters.
 // This method shall be called by a func (c * MySqlPersistence) getPageByFilter method from child class that
 // receives FilterParams and converts them into a filter function.
@@ -747,9 +810,10 @@ func (c *MySqlPersistence[T]) GenerateColumnsAndValues(objMap map[string]any) ([
 //		- paging            (optional) paging parameters
 //		- sort              (optional) sorting JSON object
 //		- select            (optional) projection JSON object
+//		- args              (optional) query parameters referenced by the filter
 //	Returns: receives a data page or error.
 func (c *MySqlPersistence[T]) GetPageByFilter(ctx context.Context,
-	filter string, paging cquery.PagingParams, sort string, selection string) (page cquery.DataPage[T], err error) {
+	filter string, paging cquery.PagingParams, sort string, selection string, args ...any) (page cquery.DataPage[T], err error) {
 
 	query := "SELECT * FROM " + c.QuotedTableName()
 	if len(selection) > 0 {
@@ -761,7 +825,7 @@ func (c *MySqlPersistence[T]) GetPageByFilter(ctx context.Context,
 	take := paging.GetTake((int64)(c.MaxPageSize))
 	pagingEnabled := paging.Total
 
-	if len(filter) > 0 {
+	if filter := c.filterDeleted(ctx, filter); len(filter) > 0 {
 		query += " WHERE " + filter
 	}
 	if len(sort) > 0 {
@@ -774,7 +838,7 @@ func (c *MySqlPersistence[T]) GetPageByFilter(ctx context.Context,
 		query += " OFFSET " + strconv.FormatInt(skip, 10)
 	}
 
-	rows, err := c.Client.QueryContext(ctx, query)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return *cquery.NewEmptyDataPage[T](), err
 	}
@@ -800,7 +864,7 @@ func (c *MySqlPersistence[T]) GetPageByFilter(ctx context.Context,
 	}
 
 	if pagingEnabled {
-		count, err := c.GetCountByFilter(ctx, filter)
+		count, err := c.GetCountByFilter(ctx, filter, args...)
 		if err != nil {
 			return *cquery.NewEmptyDataPage[T](), err
 		}
@@ -811,6 +875,116 @@ func (c *MySqlPersistence[T]) GetPageByFilter(ctx context.Context,
 	return *cquery.NewDataPage[T](items, cquery.EmptyTotalValue), rows.Err()
 }
 
+// GetPageByFilterWithToken gets a page of data items retrieved by a given filter and sorted
+// according to sort parameters using keyset paging. Instead of skipping rows the query
+// seeks right after the row encoded in the paging token, so it takes the same time for any page.
+// The returned page contains a token to get the next page, it is empty when there are no more items.
+// This method shall be called by a func (c * MySqlPersistence) GetPageByFilterWithToken method from child class that
+// receives FilterParams and converts them into a filter function.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- filter            (optional) a filter JSON object
+//		- paging            paging parameters with a token from the previous page
+//		- sort              sort parameters, the last field must be unique
+//		- selection         (optional) projection JSON object, it must include the sort fields
+//		- args              (optional) query parameters referenced by the filter
+//	Returns: data page or error.
+func (c *MySqlPersistence[T]) GetPageByFilterWithToken(ctx context.Context,
+	filter string, paging cquery.TokenizedPagingParams, sort cquery.SortParams, selection string,
+	args ...any) (page cquery.TokenizedDataPage[T], err error) {
+
+	keyset, err := cpersist.ComposeKeysetFilter(paging.Token, sort)
+	if err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+	seek, seekArgs, err := c.compileKeysetFilter(keyset, len(args)+1)
+	if err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+	orderBy, err := c.FilterCompiler.CompileSort(sort)
+	if err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+
+	query := "SELECT * FROM " + c.QuotedTableName()
+	if len(selection) > 0 {
+		query = "SELECT " + selection + " FROM " + c.QuotedTableName()
+	}
+
+	take := paging.GetTake((int64)(c.MaxPageSize))
+
+	filter = c.filterDeleted(ctx, filter)
+	if len(filter) > 0 && len(seek) > 0 {
+		query += " WHERE (" + filter + ") AND " + seek
+	} else if len(filter) > 0 {
+		query += " WHERE " + filter
+	} else if len(seek) > 0 {
+		query += " WHERE " + seek
+	}
+	query += " ORDER BY " + orderBy
+
+	// Read one extra row to find out if there is a next page
+	query += " LIMIT " + strconv.FormatInt(take+1, 10)
+
+	queryArgs := append(append(make([]any, 0, len(args)+len(seekArgs)), args...), seekArgs...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+	defer rows.Close()
+
+	items := make([]T, 0)
+	for rows.Next() {
+		if c.IsTerminated() {
+			rows.Close()
+			return *cquery.NewEmptyTokenizedDataPage[T](), cerr.
+				NewError("query terminated").
+				WithTraceId(cctx.GetTraceId(ctx))
+		}
+		item, convErr := c.Overrides.ConvertToPublic(rows)
+		if convErr != nil {
+			return page, convErr
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+
+	c.Logger.Trace(ctx, "Retrieved %d from %s", len(items), c.TableName)
+
+	token := cquery.EmptyTokenValue
+	if (int64)(len(items)) > take {
+		items = items[:take]
+		token, err = c.composeKeysetToken(items[len(items)-1], sort)
+		if err != nil {
+			return *cquery.NewEmptyTokenizedDataPage[T](), err
+		}
+	}
+
+	return *cquery.NewTokenizedDataPage[T](token, items), nil
+}
+
+// Keyset values have the types of sorted fields, so strings are never compared as numbers
+func (c *MySqlPersistence[T]) compileKeysetFilter(filter *cpersist.FilterExpression, startIndex int) (string, []any, error) {
+	dialect, ok := c.FilterCompiler.Dialect.(*MySqlFilterDialect)
+	if !ok || dialect.JsonColumn == "" {
+		return c.FilterCompiler.Compile(filter, startIndex)
+	}
+
+	typedDialect := &MySqlFilterDialect{JsonColumn: dialect.JsonColumn, typedValues: true}
+	return cpersist.NewSqlFilterCompiler(typedDialect).Compile(filter, startIndex)
+}
+
+func (c *MySqlPersistence[T]) composeKeysetToken(item T, sort cquery.SortParams) (string, error) {
+	buf, err := c.JsonConvertor.ToJson(item)
+	if err != nil {
+		return cquery.EmptyTokenValue, err
+	}
+	return cpersist.ComposeKeysetTokenFromJson(buf, sort)
+}
+
 // GetCountByFilter gets a number of data items retrieved by a given filter.
 // This method shall be called by a func (c * MySqlPersistence) getCountByFilter method from child class that
 // receives FilterParams and converts them into a filter function.
@@ -818,16 +992,18 @@ func (c *MySqlPersistence[T]) GetPageByFilter(ctx context.Context,
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- filter            (optional) a filter JSON object
+//		- args              (optional) query parameters referenced by the filter
 //	Returns: data page or error.
 func (c *MySqlPersistence[T]) GetCountByFilter(ctx context.Context,
-	filter string) (int64, error) {
+	filter string, args ...any) (int64, error) {
 
 	query := "SELECT COUNT(*) AS count FROM " + c.QuotedTableName()
+	filter = c.filterDeleted(ctx, filter)
 	if len(filter) > 0 {
 		query += " WHERE " + filter
 	}
 
-	rows, err := c.Client.QueryContext(ctx, query)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -866,9 +1042,10 @@ func (c *MySqlPersistence[T]) GetCountByFilter(ctx context.Context,
 //		- paging           (optional) paging parameters
 //		- sort             (optional) sorting JSON object
 //		- select           (optional) projection JSON object
+//		- args             (optional) query parameters referenced by the filter
 //	Returns: data list or error.
 func (c *MySqlPersistence[T]) GetListByFilter(ctx context.Context,
-	filter string, sort string, selection string) (items []T, err error) {
+	filter string, sort string, selection string, args ...any) (items []T, err error) {
 
 	query := "SELECT * FROM " + c.QuotedTableName()
 
@@ -876,6 +1053,7 @@ func (c *MySqlPersistence[T]) GetListByFilter(ctx context.Context,
 		query = "SELECT " + selection + " FROM " + c.QuotedTableName()
 	}
 
+	filter = c.filterDeleted(ctx, filter)
 	if len(filter) > 0 {
 		query += " WHERE " + filter
 	}
@@ -884,7 +1062,7 @@ func (c *MySqlPersistence[T]) GetListByFilter(ctx context.Context,
 		query += " ORDER BY " + sort
 	}
 
-	rows, err := c.Client.QueryContext(ctx, query)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -919,9 +1097,10 @@ func (c *MySqlPersistence[T]) GetListByFilter(ctx context.Context,
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- filter            (optional) a filter JSON object
+//		- args              (optional) query parameters referenced by the filter
 //	Returns: random item or error.
-func (c *MySqlPersistence[T]) GetOneRandom(ctx context.Context, filter string) (item T, err error) {
-	count, err := c.GetCountByFilter(ctx, filter)
+func (c *MySqlPersistence[T]) GetOneRandom(ctx context.Context, filter string, args ...any) (item T, err error) {
+	count, err := c.GetCountByFilter(ctx, filter, args...)
 	if err != nil {
 		return item, err
 	}
@@ -940,12 +1119,12 @@ func (c *MySqlPersistence[T]) GetOneRandom(ctx context.Context, filter string) (
 
 	// build query
 	query := "SELECT * FROM " + c.QuotedTableName()
-	if len(filter) > 0 {
+	if filter := c.filterDeleted(ctx, filter); len(filter) > 0 {
 		query += " WHERE " + filter
 	}
 	query += " LIMIT 1" + " OFFSET " + strconv.FormatInt(pos, 10)
 
-	rows, err := c.Client.QueryContext(ctx, query)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return item, err
 	}
@@ -972,6 +1151,7 @@ func (c *MySqlPersistence[T]) GetOneRandom(ctx context.Context, filter string) (
 //		- item              an item to be created.
 //	Returns: (optional) callback function that receives created item or error.
 func (c *MySqlPersistence[T]) Create(ctx context.Context, item T) (result T, err error) {
+	item = c.trackItem(item, true)
 	objMap, convErr := c.Overrides.ConvertFromPublic(item)
 	if convErr != nil {
 		return result, convErr
@@ -984,7 +1164,7 @@ func (c *MySqlPersistence[T]) Create(ctx context.Context, item T) (result T, err
 
 	query := "INSERT INTO " + c.QuotedTableName() + " (" + columnsStr + ") VALUES (" + paramsStr + ")"
 
-	rows, err := c.Client.QueryContext(ctx, query, values...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
@@ -996,20 +1176,26 @@ func (c *MySqlPersistence[T]) Create(ctx context.Context, item T) (result T, err
 }
 
 // DeleteByFilter deletes data items that match to a given filter.
+// In soft-delete mode the items are marked as deleted instead.
 // This method shall be called by a func (c * MySqlPersistence) deleteByFilter method from child class that
 // receives FilterParams and converts them into a filter function.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- filter            (optional) a filter JSON object.
+//		- args              (optional) query parameters referenced by the filter
 //	Returns: error or nil for success.
-func (c *MySqlPersistence[T]) DeleteByFilter(ctx context.Context, filter string) error {
+func (c *MySqlPersistence[T]) DeleteByFilter(ctx context.Context, filter string, args ...any) error {
 	query := "DELETE FROM " + c.QuotedTableName()
+	if c.isSoftDelete() {
+		query = "UPDATE " + c.QuotedTableName() + " SET " + c.composeDeletedSet(true)
+		filter = c.filterDeleted(ctx, filter)
+	}
 	if len(filter) > 0 {
 		query += " WHERE " + filter
 	}
 
-	result, err := c.Client.ExecContext(ctx, query)
+	result, err := c.GetClient(ctx).ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// PurgeDeleted physically deletes data items marked as deleted in soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: error or nil for success.
+func (c *MySqlPersistence[T]) PurgeDeleted(ctx context.Context) error {
+	query := "DELETE FROM " + c.QuotedTableName() + " WHERE NOT " + c.composeNotDeletedCondition()
+
+	result, err := c.GetClient(ctx).ExecContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Trace(ctx, "Purged %d deleted items from %s", count, c.TableName)
+	return nil
+}
+
+func (c *MySqlPersistence[T]) isSoftDelete() bool {
+	return c.SoftDelete && cpersist.IsTrackableType[T]()
+}
+
+// filterDeleted adds exclusion of logically deleted rows to the filter.
+func (c *MySqlPersistence[T]) filterDeleted(ctx context.Context, filter string) string {
+	if !c.isSoftDelete() || cpersist.IsDeletedIncluded(ctx) {
+		return filter
+	}
+	if len(filter) == 0 {
+		return c.composeNotDeletedCondition()
+	}
+	return "(" + filter + ") AND " + c.composeNotDeletedCondition()
+}
+
+func (c *MySqlPersistence[T]) composeNotDeletedCondition() string {
+	// Booleans are kept as integers in columns, while ->> operator extracts them from JSON as text
+	if dialect, ok := c.FilterCompiler.Dialect.(*MySqlFilterDialect); ok && dialect.JsonColumn != "" {
+		return "(IFNULL(" + dialect.FormatField("deleted") + ", 'false')<>'true')"
+	}
+	return "(IFNULL(" + c.FilterCompiler.Dialect.FormatField("deleted") + ", 0)=0)"
+}
+
+// composeDeletedSet generates SET clause that marks rows as deleted or restores them.
+// The values are generated here, so they are safe to be inlined without parameters.
+func (c *MySqlPersistence[T]) composeDeletedSet(deleted bool) string {
+	if dialect, ok := c.FilterCompiler.Dialect.(*MySqlFilterDialect); ok && dialect.JsonColumn != "" {
+		flag := "CAST('false' AS JSON)"
+		if deleted {
+			flag = "CAST('true' AS JSON)"
+		}
+		changeTime := "'" + time.Now().UTC().Format(time.RFC3339Nano) + "'"
+		return "`" + dialect.JsonColumn + "`=JSON_SET(`" + dialect.JsonColumn +
+			"`, '$.deleted', " + flag + ", '$.change_time', " + changeTime + ")"
+	}
+
+	flag := "0"
+	if deleted {
+		flag = "1"
+	}
+	// DATETIME columns do not accept RFC3339 time zone suffix, so the time is taken from the server
+	return "`deleted`=" + flag + ", `change_time`=UTC_TIMESTAMP(6)"
+}
+
+// trackItem stamps create and change times in soft-delete mode.
+func (c *MySqlPersistence[T]) trackItem(item T, created bool) T {
+	if !c.SoftDelete {
+		return item
+	}
+	var obj any = c.cloneItem(item)
+	cpersist.TrackObjectChange(&obj, created, time.Now().UTC())
+	if result, ok := obj.(T); ok {
+		return result
+	}
+	return item
+}
+
+// trackPartialData adds change time to partially updated fields in soft-delete mode.
+func (c *MySqlPersistence[T]) trackPartialData(data cdata.AnyValueMap) cdata.AnyValueMap {
+	if !c.SoftDelete || !cpersist.IsChangeableType[T]() {
+		return data
+	}
+	return *cdata.NewAnyValueMapFromMaps(data.Value(), map[string]any{"change_time": time.Now().UTC()})
+}
@@ -3,10 +3,14 @@ package test
 import (
 	"context"
 	"os"
+	"strconv"
 	"testing"
 
 	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
 	tf "github.com/pip-services4/pip-services4-go/pip-services4-mysql-go/test/fixtures"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDummyJsonMySqlPersistence(t *testing.T) {
@@ -85,4 +89,53 @@ func TestDummyJsonMySqlPersistence(t *testing.T) {
 
 	t.Run("DummyMySqlConnection:Batch", fixture.TestBatchOperations)
 
+	opnErr = persistence.Clear(context.Background())
+	if opnErr != nil {
+		t.Error("Error cleaned persistence", opnErr)
+		return
+	}
+
+	t.Run("DummyMySqlConnection:NumericRange", func(t *testing.T) {
+		ctx := context.Background()
+		for i, count := range []int{9, 10, 100} {
+			_, err := persistence.GetClient(ctx).ExecContext(ctx,
+				"INSERT INTO "+persistence.QuotedTableName()+" (id, data) VALUES (?, ?)",
+				strconv.Itoa(i), `{"id":"`+strconv.Itoa(i)+`","key":"`+strconv.Itoa(count)+`","count":`+strconv.Itoa(count)+`}`)
+			assert.Nil(t, err)
+		}
+
+		getCount := func(filter cquery.FilterParams) int64 {
+			expr, err := cpersist.NewFilterExpressionFromParams(filter)
+			assert.Nil(t, err)
+			where, args, err := persistence.CompileFilter(expr)
+			assert.Nil(t, err)
+			count, err := persistence.IdentifiableJsonMySqlPersistence.GetCountByFilter(ctx, where, args...)
+			assert.Nil(t, err)
+			return count
+		}
+
+		assert.Equal(t, int64(2), getCount(*cquery.NewFilterParamsFromTuples("count__gt", 9)))
+		assert.Equal(t, int64(2), getCount(*cquery.NewFilterParamsFromTuples("count__between", "9.5,100")))
+		assert.Equal(t, int64(2), getCount(*cquery.NewFilterParamsFromTuples("count__in", "9,100")))
+		assert.Equal(t, int64(1), getCount(*cquery.NewFilterParamsFromTuples("count", "10")))
+
+		// Keyset filters compare strings as text as they are sorted
+		sort := *cquery.NewSortParams([]cquery.SortField{cquery.NewSortField("key", true)})
+		ids := make([]string, 0)
+		token := ""
+		for len(ids) < 5 {
+			page, err := persistence.IdentifiableJsonMySqlPersistence.GetPageByFilterWithToken(ctx,
+				"", *cquery.NewTokenizedPagingParams(token, 1, false), sort, "")
+			assert.Nil(t, err)
+			for _, item := range page.Data {
+				ids = append(ids, item.Id)
+			}
+			if page.Token == "" {
+				break
+			}
+			token = page.Token
+		}
+		assert.Equal(t, []string{"1", "2", "0"}, ids)
+	})
+
 }
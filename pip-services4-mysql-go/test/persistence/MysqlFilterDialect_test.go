@@ -0,0 +1,38 @@
+package test
+
+import (
+	"testing"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-mysql-go/persistence"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonMySqlFilterNumericRange(t *testing.T) {
+	compiler := persist.NewJsonMySqlFilterCompiler("data")
+
+	filter, err := cpersist.NewFilterExpressionFromParams(*cquery.NewFilterParamsFromTuples(
+		"count__gt", 9,
+		"price__between", "0.5,10",
+		"key", "10",
+		"code__ge", "A10",
+	))
+	assert.Nil(t, err)
+
+	where, args, err := compiler.Compile(filter, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "(data->>'$.code'>=? AND CAST(data->>'$.count' AS DECIMAL(65,30))>?"+
+		" AND data->>'$.key'=? AND CAST(data->>'$.price' AS DECIMAL(65,30)) BETWEEN ? AND ?)", where)
+	assert.Equal(t, []any{"A10", "9", "10", "0.5", "10"}, args)
+
+	filter = cpersist.NewFilterCondition("count", cpersist.FilterIn, 1, 2)
+	where, _, err = compiler.Compile(filter, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "CAST(data->>'$.count' AS DECIMAL(65,30)) IN (?,?)", where)
+
+	where, _, err = persist.NewMySqlFilterCompiler().Compile(
+		cpersist.NewFilterCondition("count", cpersist.FilterMore, "9"), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "`count`>?", where)
+}
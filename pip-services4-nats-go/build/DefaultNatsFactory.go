@@ -9,6 +9,7 @@ import (
 
 // Creates NatsMessageQueue components by their descriptors.
 // See NatsMessageQueue
+// See NatsTopic
 type DefaultNatsFactory struct {
 	*cbuild.Factory
 }
@@ -22,6 +23,7 @@ func NewDefaultNatsFactory() *DefaultNatsFactory {
 	natsConnectionDescriptor := cref.NewDescriptor("pip-services", "connection", "nats", "*", "1.0")
 	bareNatsQueueDescriptor := cref.NewDescriptor("pip-services", "message-queue", "bare-nats", "*", "1.0")
 	natsQueueDescriptor := cref.NewDescriptor("pip-services", "message-queue", "nats", "*", "1.0")
+	natsTopicDescriptor := cref.NewDescriptor("pip-services", "topic", "nats", "*", "1.0")
 
 	c.RegisterType(natsQueueFactoryDescriptor, NewNatsMessageQueueFactory)
 
@@ -47,5 +49,15 @@ func NewDefaultNatsFactory() *DefaultNatsFactory {
 		return queues.NewNatsMessageQueue(name)
 	})
 
+	c.Register(natsTopicDescriptor, func(locator interface{}) interface{} {
+		name := ""
+		descriptor, ok := locator.(*cref.Descriptor)
+		if ok {
+			name = descriptor.Name()
+		}
+
+		return queues.NewNatsTopic(name)
+	})
+
 	return &c
 }
@@ -214,6 +214,34 @@ func (c *NatsConnection) Publish(ctx context.Context, subject string, message *n
 	return c.Connection.PublishMsg(message)
 }
 
+// PublishBatch publishes multiple messages to a specified topic
+// and flushes them to the server at once
+//
+// Parameters:
+//   - subject a subject (topic) name
+//   - messages messages to be published
+//
+// Returns: error or nil for success
+func (c *NatsConnection) PublishBatch(ctx context.Context, subject string, messages []*nats.Msg) error {
+	// Check for open connection
+	err := c.checkOpen()
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		if subject != "" {
+			message.Subject = subject
+		}
+		err = c.Connection.PublishMsg(message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.Connection.FlushTimeout(time.Duration(c.flushTimeout) * time.Millisecond)
+}
+
 // Subscribe to a topic
 //
 // Parameters:
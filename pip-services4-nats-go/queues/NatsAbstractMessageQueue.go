@@ -36,10 +36,14 @@ type NatsAbstractMessageQueue struct {
 	Connection *connect.NatsConnection
 	//The NATS connection object.
 	Client *nats.Conn
+	//The scheduler that holds delayed messages since NATS does not support them.
+	Scheduler *cqueues.MessageDelayScheduler
 
 	// SerializeEnvelop bool
 	Subject    string
 	QueueGroup string
+	//The NATS subject to move poison messages to.
+	DeadLetterSubject string
 }
 
 // Creates a new instance of the queue component.
@@ -60,6 +64,8 @@ func InheritNatsAbstractMessageQueue(overrides cqueues.IMessageQueueOverrides, n
 		Logger: clog.NewCompositeLogger(),
 	}
 	c.MessageQueue = cqueues.InheritMessageQueue(overrides, name, capabilities)
+	c.Scheduler = cqueues.NewMessageDelayScheduler(overrides)
+	c.Scheduler.Logger = c.Logger
 	c.DependencyResolver = cref.NewDependencyResolver()
 	c.DependencyResolver.Configure(context.Background(), c.defaultConfig)
 	return c
@@ -81,6 +87,8 @@ func (c *NatsAbstractMessageQueue) Configure(ctx context.Context, config *cconf.
 	c.Subject = config.GetAsStringWithDefault("subject", c.Subject)
 	c.QueueGroup = config.GetAsStringWithDefault("group", c.QueueGroup)
 	c.QueueGroup = config.GetAsStringWithDefault("queue_group", c.QueueGroup)
+	c.DeadLetterSubject = config.GetAsStringWithDefault("options.dead_letter_queue", c.DeadLetterSubject)
+	c.MaxDeliveryCount = config.GetAsIntegerWithDefault("options.max_delivery_count", c.MaxDeliveryCount)
 }
 
 // Sets references to dependent components.
@@ -162,6 +170,7 @@ func (c *NatsAbstractMessageQueue) Open(ctx context.Context) (err error) {
 		return err
 	}
 	c.Client = c.Connection.GetConnection()
+	c.Scheduler.Start(ctx)
 
 	return err
 }
@@ -201,6 +210,7 @@ func (c *NatsAbstractMessageQueue) Close(ctx context.Context) (err error) {
 	}
 
 	// Todo: Flush messages?
+	c.Scheduler.Stop(ctx)
 	c.opened = false
 	c.Client = nil
 
@@ -258,6 +268,10 @@ func (c *NatsAbstractMessageQueue) FromMessage(message *cqueues.MessageEnvelope)
 	msg.Header.Add("trace_id", message.TraceId)
 	msg.Header.Add("message_type", message.MessageType)
 	msg.Header.Add("sent_time", cconv.StringConverter.ToString(message.SentTime))
+	msg.Header.Add("delivery_count", cconv.StringConverter.ToString(message.DeliveryCount))
+	for key, value := range message.Headers {
+		msg.Header.Set(key, value)
+	}
 	return msg, nil
 }
 
@@ -287,6 +301,18 @@ func (c *NatsAbstractMessageQueue) ToMessage(msg *nats.Msg) (*cqueues.MessageEnv
 	message.MessageType = msg.Header.Get("message_type")
 	message.SentTime = cconv.DateTimeConverter.ToDateTime(msg.Header.Get("sent_time"))
 	message.Message = msg.Data
+	// Count this delivery on top of the previous ones
+	message.DeliveryCount = cconv.IntegerConverter.ToInteger(msg.Header.Get("delivery_count")) + 1
+
+	// Collect custom headers
+	for key := range msg.Header {
+		switch key {
+		case "message_id", "trace_id", "message_type", "sent_time", "delivery_count":
+			continue
+		}
+		message.SetHeader(key, msg.Header.Get(key))
+	}
+
 	message.SetReference(msg)
 
 	return message, nil
@@ -299,8 +325,8 @@ func (c *NatsAbstractMessageQueue) ToMessage(msg *nats.Msg) (*cqueues.MessageEnv
 //
 // Returns error or nil no errors occured.
 func (c *NatsAbstractMessageQueue) Clear(ctx context.Context) error {
-	// Not supported
-	return nil
+	// Only delayed messages can be cleared
+	return c.Scheduler.Clear(ctx)
 }
 
 // ReadMessageCount method are reads the current number of messages in the queue to be delivered.
@@ -311,6 +337,8 @@ func (c *NatsAbstractMessageQueue) ReadMessageCount() (int64, error) {
 }
 
 // Send method are sends a message into the queue.
+// NATS does not support delayed delivery, so delayed messages are held
+// by the Scheduler and published when their visible time comes.
 //
 //	Parameters:
 //		- ctx context.Context	transaction id to trace execution through call chain.
@@ -323,6 +351,13 @@ func (c *NatsAbstractMessageQueue) Send(ctx context.Context, envelop *cqueues.Me
 		return err
 	}
 
+	// Propagate W3C trace context and baggage to receivers
+	cqueues.InjectTraceContext(ctx, envelop)
+
+	if !envelop.IsVisible(time.Now()) {
+		return c.Scheduler.Schedule(ctx, envelop)
+	}
+
 	msg, err := c.FromMessage(envelop)
 	if err != nil {
 		return err
@@ -345,6 +380,62 @@ func (c *NatsAbstractMessageQueue) Send(ctx context.Context, envelop *cqueues.Me
 	return nil
 }
 
+// SendBatch method are sends multiple messages into the queue and flushes them at once.
+// Delayed messages are held by the Scheduler and published when their visible time comes.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- envelopes []*cqueues.MessageEnvelope  a list of message envelops to be sent.
+//
+// Returns: error or nil for success.
+func (c *NatsAbstractMessageQueue) SendBatch(ctx context.Context, envelopes []*cqueues.MessageEnvelope) error {
+	err := c.CheckOpen(cctx.GetTraceId(ctx))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	msgs := make([]*nats.Msg, 0, len(envelopes))
+
+	for _, envelop := range envelopes {
+		cqueues.InjectTraceContext(ctx, envelop)
+
+		if !envelop.IsVisible(now) {
+			err = c.Scheduler.Schedule(ctx, envelop)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		msg, err := c.FromMessage(envelop)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	subject := c.Name()
+	if subject == "" {
+		subject = c.Subject
+	}
+
+	err = c.Connection.PublishBatch(ctx, subject, msgs)
+	if err != nil {
+		c.Logger.Error(ctx, err, "Failed to send %d messages via %s", len(msgs), c.Name())
+		return err
+	}
+
+	c.Counters.Increment(ctx, "queue."+c.Name()+".sent_messages", int64(len(msgs)))
+	c.Logger.Debug(ctx, "Sent %d messages via %s", len(msgs), c.Name())
+
+	return nil
+}
+
 // RenewLock method are renews a lock on a message that makes it invisible from other receivers in the queue.
 // This method is usually used to extend the message processing time.
 // Important: This method is not supported by NATS.
@@ -380,7 +471,8 @@ func (c *NatsAbstractMessageQueue) Complete(ctx context.Context, message *cqueue
 // This method is usually used to return a message which could not be processed at the moment
 // to repeat the attempt. Messages that cause unrecoverable errors shall be removed permanently
 // or/and send to dead letter queue.
-// Important: This method is not supported by NATS.
+// NATS does not redeliver messages, so the message is published again with incremented
+// delivery count when max_delivery_count is set. Otherwise the method does nothing.
 //
 //	Parameters:
 //		- ctx context.Context	operation context
@@ -390,12 +482,18 @@ func (c *NatsAbstractMessageQueue) Complete(ctx context.Context, message *cqueue
 //
 //	error or nil for success.
 func (c *NatsAbstractMessageQueue) Abandon(ctx context.Context, message *cqueues.MessageEnvelope) (err error) {
-	// Not supported
-	return nil
+	if c.MaxDeliveryCount <= 0 {
+		return nil
+	}
+
+	message = message.Clone()
+	message.VisibleTime = time.Time{}
+	return c.Send(ctx, message)
 }
 
 // Permanently removes a message from the queue and sends it to dead letter queue.
-// Important: This method is not supported by NATS.
+// The message is sent to DeadLetterQueue when it is set or to the subject
+// configured by options.dead_letter_queue. Otherwise it is dropped.
 //
 //	Parameters:
 //		- ctx context.Context	operation context
@@ -405,6 +503,32 @@ func (c *NatsAbstractMessageQueue) Abandon(ctx context.Context, message *cqueues
 //
 //	error or nil for success.
 func (c *NatsAbstractMessageQueue) MoveToDeadLetter(ctx context.Context, message *cqueues.MessageEnvelope) (err error) {
-	// Not supported
+	err = c.CheckOpen(cctx.GetTraceId(ctx))
+	if err != nil {
+		return err
+	}
+
+	sent, err := c.SendToDeadLetterQueue(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	if !sent && c.DeadLetterSubject != "" {
+		msg, err := c.FromMessage(message)
+		if err != nil {
+			return err
+		}
+		msg.Subject = c.DeadLetterSubject
+
+		err = c.Connection.Publish(ctx, c.DeadLetterSubject, msg)
+		if err != nil {
+			c.Logger.Error(cctx.NewContextWithTraceId(ctx, message.TraceId), err, "Failed to move message to %s", c.DeadLetterSubject)
+			return err
+		}
+	}
+
+	c.Counters.IncrementOne(ctx, "queue."+c.Name()+".dead_messages")
+	c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Moved to dead message %s at %s", message, c.Name())
+
 	return nil
 }
@@ -31,6 +31,8 @@ import (
 //			- max_reconnect:        (optional) maximum reconnection attempts (default: 3)
 //			- reconnect_timeout:    (optional) number of milliseconds to wait on each reconnection attempt (default: 3000)
 //			- flush_timeout:        (optional) number of milliseconds to wait on flushing messages (default: 3000)
+//			- dead_letter_queue:    (optional) name of NATS subject to move poison messages to (default: none, messages are dropped)
+//			- max_delivery_count:   (optional) maximum number of deliveries before a message is moved to dead letter queue (default: 0 unlimited)
 //
 //	References:
 //
@@ -74,7 +76,7 @@ type NatsBareMessageQueue struct {
 func NewNatsBareMessageQueue(name string) *NatsBareMessageQueue {
 	c := NatsBareMessageQueue{}
 	c.NatsAbstractMessageQueue = InheritNatsAbstractMessageQueue(&c, name,
-		cqueues.NewMessagingCapabilities(false, true, true, false, false, false, false, false, false))
+		cqueues.NewMessagingCapabilities(false, true, true, false, false, false, false, true, false).WithDelay(true))
 	return &c
 }
 
@@ -136,13 +138,18 @@ func (c *NatsBareMessageQueue) Receive(ctx context.Context, waitTimeout time.Dur
 
 	defer subscription.Unsubscribe()
 
-	// Wait for a message
-	msg, err := subscription.NextMsg(waitTimeout)
-	if err != nil {
-		return nil, err
-	}
+	// Wait for a message, poison messages are skipped
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		msg, err := subscription.NextMsg(time.Until(deadline))
+		if err != nil {
+			return nil, err
+		}
+
+		if msg == nil {
+			return nil, nil
+		}
 
-	if msg != nil {
 		message, err := c.ToMessage(msg)
 		if err != nil {
 			return nil, err
@@ -151,11 +158,92 @@ func (c *NatsBareMessageQueue) Receive(ctx context.Context, waitTimeout time.Dur
 		c.Counters.IncrementOne(ctx, "queue."+c.Name()+".received_messages")
 		c.Logger.Debug(cctx.NewContextWithTraceId(ctx, message.TraceId), "Received message %s via %s", msg, c.Name())
 
+		if c.MoveToDeadLetterIfExceeded(ctx, message) {
+			continue
+		}
+
 		// Convert the message and return
 		return message, nil
 	}
+}
 
-	return nil, nil
+// ReceiveBatch method are receives multiple incoming messages and removes them from the queue.
+// The method waits for the first message to come and then takes all messages
+// pending in the subscription up to the maximum count.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- maxCount  int64     a maximum number of messages to receive.
+//		- waitTimeout  time.Duration     a timeout in milliseconds to wait for messages to come.
+//
+// Returns: a list with messages or error.
+func (c *NatsBareMessageQueue) ReceiveBatch(ctx context.Context, maxCount int64, waitTimeout time.Duration) ([]*cqueues.MessageEnvelope, error) {
+	err := c.CheckOpen(cctx.GetTraceId(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a temporary subscription
+	var subscription *nats.Subscription
+
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
+	if c.QueueGroup != "" {
+		subscription, err = c.Client.QueueSubscribeSync(c.SubscriptionSubject(), c.QueueGroup)
+	} else {
+		subscription, err = c.Client.SubscribeSync(c.SubscriptionSubject())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	defer subscription.Unsubscribe()
+
+	messages := []*cqueues.MessageEnvelope{}
+	deadline := time.Now().Add(waitTimeout)
+
+	for int64(len(messages)) < maxCount {
+		// Wait only for the first message, then take pending ones
+		timeout := time.Until(deadline)
+		if len(messages) > 0 {
+			pending, _, err := subscription.Pending()
+			if err != nil {
+				return messages, err
+			}
+			if pending == 0 {
+				break
+			}
+			timeout = time.Duration(100) * time.Millisecond
+		} else if timeout <= 0 {
+			break
+		}
+
+		msg, err := subscription.NextMsg(timeout)
+		if err == nats.ErrTimeout {
+			break
+		}
+		if err != nil {
+			return messages, err
+		}
+
+		message, err := c.ToMessage(msg)
+		if err != nil {
+			return messages, err
+		}
+
+		c.Counters.IncrementOne(ctx, "queue."+c.Name()+".received_messages")
+		c.Logger.Debug(cctx.NewContextWithTraceId(ctx, message.TraceId), "Received message %s via %s", msg, c.Name())
+
+		// Skip poison messages
+		if c.MoveToDeadLetterIfExceeded(ctx, message) {
+			continue
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
 }
 
 func (c *NatsBareMessageQueue) receiveMessage(ctx context.Context, receiver cqueues.IMessageReceiver) func(msg *nats.Msg) {
@@ -169,6 +257,11 @@ func (c *NatsBareMessageQueue) receiveMessage(ctx context.Context, receiver cque
 		c.Counters.IncrementOne(ctx, "queue."+c.Name()+".received_messages")
 		c.Logger.Debug(cctx.NewContextWithTraceId(ctx, message.TraceId), "Received message %s via %s", msg, c.Name())
 
+		// Skip poison messages
+		if c.MoveToDeadLetterIfExceeded(ctx, message) {
+			return
+		}
+
 		// Pass the message to receiver and recover after panic
 		func(message *cqueues.MessageEnvelope) {
 			defer func() {
@@ -178,7 +271,7 @@ func (c *NatsBareMessageQueue) receiveMessage(ctx context.Context, receiver cque
 				}
 			}()
 
-			err = receiver.ReceiveMessage(ctx, message, c)
+			err = receiver.ReceiveMessage(cqueues.ExtractTraceContext(ctx, message), message, c)
 			if err != nil {
 				c.Logger.Error(cctx.NewContextWithTraceId(ctx, message.TraceId), err, "Failed to process the message")
 			}
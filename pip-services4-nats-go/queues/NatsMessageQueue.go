@@ -33,6 +33,8 @@ import (
 //			- max_reconnect:        (optional) maximum reconnection attempts (default: 3)
 //			- reconnect_timeout:    (optional) number of milliseconds to wait on each reconnection attempt (default: 3000)
 //			- flush_timeout:        (optional) number of milliseconds to wait on flushing messages (default: 3000)
+//			- dead_letter_queue:    (optional) name of NATS subject to move poison messages to (default: none, messages are dropped)
+//			- max_delivery_count:   (optional) maximum number of deliveries before a message is moved to dead letter queue (default: 0 unlimited)
 //
 //
 // References:
@@ -85,7 +87,7 @@ func NewNatsMessageQueue(name string) *NatsMessageQueue {
 	c := NatsMessageQueue{}
 
 	c.NatsAbstractMessageQueue = InheritNatsAbstractMessageQueue(&c, name,
-		cqueues.NewMessagingCapabilities(false, true, true, true, true, false, false, false, true))
+		cqueues.NewMessagingCapabilities(false, true, true, true, true, false, false, true, true).WithDelay(true))
 
 	c.messages = make([]*cqueues.MessageEnvelope, 0)
 
@@ -193,7 +195,7 @@ func (c *NatsMessageQueue) Clear(ctx context.Context) (err error) {
 	c.messages = make([]*cqueues.MessageEnvelope, 0)
 	c.receiver = nil
 
-	return nil
+	return c.Scheduler.Clear(ctx)
 }
 
 // ReadMessageCount method are reads the current number of messages in the queue to be delivered.
@@ -321,6 +323,52 @@ func (c *NatsMessageQueue) Receive(ctx context.Context, waitTimeout time.Duratio
 	return message, nil
 }
 
+// ReceiveBatch method are receives multiple incoming messages and removes them from the queue.
+// The method waits for the first message to come and then takes all collected messages up to the maximum count.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- maxCount  int64     a maximum number of messages to receive.
+//		- waitTimeout  time.Duration     a timeout in milliseconds to wait for messages to come.
+//
+// Returns: a list with messages or error.
+func (c *NatsMessageQueue) ReceiveBatch(ctx context.Context, maxCount int64, waitTimeout time.Duration) ([]*cqueues.MessageEnvelope, error) {
+	err := c.CheckOpen(cctx.GetTraceId(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// Subscribe if needed
+	err = c.subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []*cqueues.MessageEnvelope{}
+	elapsedTime := time.Duration(0)
+
+	for elapsedTime < waitTimeout && len(messages) == 0 {
+		c.Lock.Lock()
+		count := int64(len(c.messages))
+		if count == 0 {
+			c.Lock.Unlock()
+			time.Sleep(time.Duration(100) * time.Millisecond)
+			elapsedTime += time.Duration(100) * time.Millisecond
+			continue
+		}
+
+		// Get messages from the queue
+		if count > maxCount {
+			count = maxCount
+		}
+		messages = append(messages, c.messages[:count]...)
+		c.messages = c.messages[count:]
+		c.Lock.Unlock()
+	}
+
+	return messages, nil
+}
+
 // Function thath process incoming messages
 //
 //	Parameters:
@@ -335,6 +383,11 @@ func (c *NatsMessageQueue) OnMessage(msg *nats.Msg) {
 	c.Counters.IncrementOne(context.Background(), "queue."+c.Name()+".received_messages")
 	c.Logger.Debug(cctx.NewContextWithTraceId(context.Background(), message.TraceId), "Received message %s via %s", msg, c.Name())
 
+	// Skip poison messages
+	if c.MoveToDeadLetterIfExceeded(context.Background(), message) {
+		return
+	}
+
 	// Send message to receiver if its set or put it into the queue
 	c.Lock.Lock()
 	if c.receiver != nil {
@@ -357,7 +410,7 @@ func (c *NatsMessageQueue) sendMessageToReceiver(receiver cqueues.IMessageReceiv
 		}
 	}()
 
-	err := receiver.ReceiveMessage(context.Background(), message, c)
+	err := receiver.ReceiveMessage(cqueues.ExtractTraceContext(context.Background(), message), message, c)
 	if err != nil {
 		c.Logger.Error(ctx, err, "Failed to process the message")
 	}
@@ -393,7 +446,7 @@ func (c *NatsMessageQueue) Listen(ctx context.Context, receiver cqueues.IMessage
 
 	// Resend collected messages to receiver
 	for _, message := range batchMessages {
-		receiver.ReceiveMessage(ctx, message, c)
+		receiver.ReceiveMessage(cqueues.ExtractTraceContext(ctx, message), message, c)
 	}
 
 	// Set the receiver
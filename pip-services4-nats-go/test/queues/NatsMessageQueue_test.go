@@ -114,4 +114,12 @@ func TestNatsMessageQueue(t *testing.T) {
 	c.setup(t)
 	t.Run("On Message", c.fixture.TestOnMessage)
 	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Delayed Message", c.fixture.TestSendDelayedMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Receive Batch", c.fixture.TestSendReceiveBatch)
+	c.teardown(t)
 }
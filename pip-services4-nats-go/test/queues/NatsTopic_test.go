@@ -0,0 +1,55 @@
+package test_queues
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	queues "github.com/pip-services4/pip-services4-go/pip-services4-nats-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNatsTopic(t *testing.T) {
+	natsHost := os.Getenv("NATS_SERVICE_HOST")
+	if natsHost == "" {
+		natsHost = "localhost"
+	}
+
+	natsPort := os.Getenv("NATS_SERVICE_PORT")
+	if natsPort == "" {
+		natsPort = "4222"
+	}
+
+	natsUser := os.Getenv("NATS_USER")
+	if natsUser == "" {
+		natsUser = "nats"
+	}
+	natsPassword := os.Getenv("NATS_PASS")
+	if natsPassword == "" {
+		natsPassword = "nats"
+	}
+
+	topic := queues.NewNatsTopic("test.topic")
+	topic.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"connection.host", natsHost,
+		"connection.port", natsPort,
+		"credential.username", natsUser,
+		"credential.password", natsPassword,
+	))
+
+	err := topic.Open(context.Background())
+	assert.Nil(t, err)
+	defer topic.Close(context.Background())
+
+	fixture := NewTopicFixture(topic)
+	t.Run("Publish Subscribe", fixture.TestPublishSubscribe)
+}
+
+func TestNatsTopicPublishToWildcards(t *testing.T) {
+	topic := queues.NewNatsTopic("test.>")
+
+	err := topic.Publish(context.Background(), cqueues.NewMessageEnvelope("", "test", []byte("Test message")))
+	assert.NotNil(t, err)
+}
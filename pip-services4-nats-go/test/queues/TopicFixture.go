@@ -0,0 +1,48 @@
+package test_queues
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+type TopicFixture struct {
+	topic cqueues.ITopic
+}
+
+func NewTopicFixture(topic cqueues.ITopic) *TopicFixture {
+	c := TopicFixture{
+		topic: topic,
+	}
+	return &c
+}
+
+func (c *TopicFixture) TestPublishSubscribe(t *testing.T) {
+	subscription1, err := c.topic.Subscribe(context.Background(), "subscription1")
+	assert.Nil(t, err)
+	defer subscription1.Unsubscribe(context.Background())
+
+	subscription2, err := c.topic.Subscribe(context.Background(), "subscription2")
+	assert.Nil(t, err)
+	defer subscription2.Unsubscribe(context.Background())
+
+	// Give the broker time to register subscriptions
+	time.Sleep(500 * time.Millisecond)
+
+	envelope := cqueues.NewMessageEnvelope("123", "Test", []byte("Test message"))
+	err = c.topic.Publish(context.Background(), envelope)
+	assert.Nil(t, err)
+
+	for _, subscription := range []cqueues.ISubscription{subscription1, subscription2} {
+		message, err := subscription.Receive(context.Background(), 10000*time.Millisecond)
+		assert.Nil(t, err)
+		assert.NotNil(t, message)
+		if message != nil {
+			assert.Equal(t, envelope.Message, message.Message)
+			assert.Nil(t, subscription.Complete(context.Background(), message))
+		}
+	}
+}
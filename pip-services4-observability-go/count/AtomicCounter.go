@@ -19,6 +19,7 @@ type AtomicCounter struct {
 	_max     float64
 	_average float64
 	_count   int64
+	_labels  map[string]string
 }
 
 // NewAtomicCounter creates an instance of the data obejct
@@ -37,6 +38,18 @@ func NewAtomicCounter(name string, typ CounterType) *AtomicCounter {
 	}
 }
 
+// NewAtomicCounterWithLabels creates an instance of the data obejct with dimensional labels
+//	Parameters:
+//		- name string a counter name.
+//		- labels map[string]string counter labels. They shall not be modified after the call.
+//		- type CounterType a counter type.
+//	Returns: *Counter
+func NewAtomicCounterWithLabels(name string, labels map[string]string, typ CounterType) *AtomicCounter {
+	counter := NewAtomicCounter(name, typ)
+	counter._labels = labels
+	return counter
+}
+
 // SetLast is a setter for the _last
 //	Parameters: value float64
 func (c *AtomicCounter) SetLast(value float64) {
@@ -90,6 +103,7 @@ func (c *AtomicCounter) GetCounter() Counter {
 		Max:     c._max,
 		Average: c._average,
 		Time:    c._time,
+		Labels:  c._labels,
 	}
 }
 
@@ -101,6 +115,12 @@ func (c *AtomicCounter) Name() string {
 	return c._name
 }
 
+// Labels gets counter _labels
+//	Returns: map[string]string
+func (c *AtomicCounter) Labels() map[string]string {
+	return c._labels
+}
+
 // Type gets counter _type
 //	Returns: int
 func (c *AtomicCounter) Type() CounterType {
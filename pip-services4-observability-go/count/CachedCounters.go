@@ -16,6 +16,11 @@ import (
 //		- options:
 //			- interval: interval in milliseconds to save current counters measurements (default: 5 mins)
 //			- reset_timeout: timeout in milliseconds to reset the counters. 0 disables the reset (default: 0)
+//			- max_labels: maximum number of labels per measurement (default: 10)
+//			- max_label_sets: maximum number of distinct label sets per counter (default: 100)
+//
+// Counters with labels are stored as separate series for every distinct set of labels.
+// Measurements with label sets over the limit are recorded into the counter without labels.
 type CachedCounters struct {
 	cache         map[string]*AtomicCounter
 	updated       bool
@@ -24,6 +29,7 @@ type CachedCounters struct {
 	mux           sync.RWMutex
 	interval      int64
 	resetTimeout  int64
+	limiter       *CounterLabelsLimiter
 	Overrides     ICachedCountersOverrides
 }
 
@@ -51,6 +57,7 @@ func InheritCacheCounters(overrides ICachedCountersOverrides) *CachedCounters {
 		lastResetTime: time.Now(),
 		interval:      DefaultInterval,
 		resetTimeout:  DefaultResetTimeout,
+		limiter:       NewCounterLabelsLimiter(),
 		Overrides:     overrides,
 	}
 }
@@ -63,9 +70,11 @@ func InheritCacheCounters(overrides ICachedCountersOverrides) *CachedCounters {
 func (c *CachedCounters) Configure(ctx context.Context, config *config.ConfigParams) {
 	c.interval = config.GetAsLongWithDefault(ConfigParameterInterval, c.interval)
 	c.resetTimeout = config.GetAsLongWithDefault(ConfigParameterResetTimeout, c.resetTimeout)
+	c.limiter.Configure(ctx, config)
 }
 
-// Clear clears (resets) a counter specified by its name.
+// Clear clears (resets) a counter specified by its name
+// including all its series with labels.
 //
 //	Parameters:
 //		- ctx context.Context
@@ -74,7 +83,12 @@ func (c *CachedCounters) Clear(ctx context.Context, name string) {
 	c.mux.Lock()
 	defer c.mux.Unlock()
 
-	delete(c.cache, name)
+	for key, counter := range c.cache {
+		if counter.Name() == name {
+			delete(c.cache, key)
+		}
+	}
+	c.limiter.Clear(name)
 }
 
 // ClearAll clears (resets) all counters.
@@ -86,6 +100,7 @@ func (c *CachedCounters) ClearAll(ctx context.Context) {
 	defer c.mux.Unlock()
 
 	c.cache = make(map[string]*AtomicCounter)
+	c.limiter.ClearAll()
 }
 
 func (c *CachedCounters) isUpdated() bool {
@@ -139,6 +154,7 @@ func (c *CachedCounters) resetIfNeeded(ctx context.Context) {
 	newResetTime := c.lastResetTime.Add(time.Duration(c.resetTimeout) * time.Millisecond)
 	if time.Now().After(newResetTime) {
 		c.cache = make(map[string]*AtomicCounter)
+		c.limiter.ClearAll()
 		c.updated = false
 		c.lastDumpTime = time.Now()
 	}
@@ -188,19 +204,36 @@ func (c *CachedCounters) getAllCountersStats() []Counter {
 //		- typ int a counter type.
 //	Returns: *Counter an existing or newly created counter of the specified type.
 func (c *CachedCounters) Get(ctx context.Context, name string, typ CounterType) (*AtomicCounter, bool) {
+	return c.GetWithLabels(ctx, name, nil, typ)
+}
+
+// GetWithLabels a counter specified by its name and labels. It counter does not exist or its type doesn't match the
+// specified type it creates a new one. Labels are limited by configured cardinality limits.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name to retrieve.
+//		- labels map[string]string counter labels.
+//		- typ int a counter type.
+//	Returns: *Counter an existing or newly created counter of the specified type.
+func (c *CachedCounters) GetWithLabels(ctx context.Context, name string, labels map[string]string,
+	typ CounterType) (*AtomicCounter, bool) {
 	if name == "" {
 		return nil, false
 	}
 
 	c.resetIfNeeded(ctx)
 
+	labels = c.limiter.Limit(name, labels)
+	key := name + CounterLabelsToString(labels)
+
 	c.mux.Lock()
 	defer c.mux.Unlock()
 
-	counter, ok := c.cache[name]
+	counter, ok := c.cache[key]
 	if !ok || counter.Type() != typ {
-		counter = NewAtomicCounter(name, typ)
-		c.cache[name] = counter
+		counter = NewAtomicCounterWithLabels(name, labels, typ)
+		c.cache[key] = counter
 	}
 
 	return counter, true
@@ -226,10 +259,7 @@ func (c *CachedCounters) BeginTiming(ctx context.Context, name string) *CounterT
 //		- name string a counter name
 //		- elapsed float64 execution elapsed time in milliseconds to update the counter.
 func (c *CachedCounters) EndTiming(ctx context.Context, name string, elapsed float64) {
-	if counter, ok := c.Get(ctx, name, Interval); ok {
-		counter.CalculateStats(elapsed)
-		_ = c.update(ctx)
-	}
+	c.EndTimingWithLabels(ctx, name, nil, elapsed)
 }
 
 // Stats calculates min/average/max statistics based on the current and previous values.
@@ -239,10 +269,7 @@ func (c *CachedCounters) EndTiming(ctx context.Context, name string, elapsed flo
 //		- name string a counter name of Statistics type
 //		- value float32 a value to update statistics
 func (c *CachedCounters) Stats(ctx context.Context, name string, value float64) {
-	if counter, ok := c.Get(ctx, name, Statistics); ok {
-		counter.CalculateStats(value)
-		_ = c.update(ctx)
-	}
+	c.StatsWithLabels(ctx, name, nil, value)
 }
 
 // Last records the last calculated measurement value.
@@ -253,10 +280,7 @@ func (c *CachedCounters) Stats(ctx context.Context, name string, value float64)
 //		- name string a counter name of Last type.
 //		- value number a last value to record.
 func (c *CachedCounters) Last(ctx context.Context, name string, value float64) {
-	if counter, ok := c.Get(ctx, name, LastValue); ok {
-		counter.SetLast(value)
-		_ = c.update(ctx)
-	}
+	c.LastWithLabels(ctx, name, nil, value)
 }
 
 // TimestampNow records the current time as a timestamp.
@@ -275,10 +299,7 @@ func (c *CachedCounters) TimestampNow(ctx context.Context, name string) {
 //		- name string a counter name of Timestamp type.
 //		- value time.Time a timestamp to record.
 func (c *CachedCounters) Timestamp(ctx context.Context, name string, value time.Time) {
-	if counter, ok := c.Get(ctx, name, Timestamp); ok {
-		counter.SetTime(value)
-		_ = c.update(ctx)
-	}
+	c.TimestampWithLabels(ctx, name, nil, value)
 }
 
 // IncrementOne increments counter by 1.
@@ -297,7 +318,108 @@ func (c *CachedCounters) IncrementOne(ctx context.Context, name string) {
 //		- name string a counter name of Increment type.
 //		- value int a value to add to the counter.
 func (c *CachedCounters) Increment(ctx context.Context, name string, value int64) {
-	if counter, ok := c.Get(ctx, name, Increment); ok {
+	c.IncrementWithLabels(ctx, name, nil, value)
+}
+
+// BeginTimingWithLabels begins measurement of execution time interval for a counter with labels.
+// It returns Timing object which has to be called at
+// Timing.EndTiming to end the measurement and update the counter.
+//
+//	Parameters
+//		- ctx context.Context
+//		- name string a counter name of Interval type.
+//		- labels map[string]string counter labels.
+//	Returns: *Timing a Timing callback object to end timing.
+func (c *CachedCounters) BeginTimingWithLabels(ctx context.Context, name string, labels map[string]string) *CounterTiming {
+	return NewCounterTimingWithLabels(name, labels, c)
+}
+
+// EndTimingWithLabels ends measurement of execution elapsed time and updates specified counter with labels.
+//
+//	see Timing.EndTiming
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name
+//		- labels map[string]string counter labels.
+//		- elapsed float64 execution elapsed time in milliseconds to update the counter.
+func (c *CachedCounters) EndTimingWithLabels(ctx context.Context, name string, labels map[string]string, elapsed float64) {
+	if counter, ok := c.GetWithLabels(ctx, name, labels, Interval); ok {
+		counter.CalculateStats(elapsed)
+		_ = c.update(ctx)
+	}
+}
+
+// StatsWithLabels calculates min/average/max statistics for a counter with labels.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Statistics type
+//		- labels map[string]string counter labels.
+//		- value float64 a value to update statistics
+func (c *CachedCounters) StatsWithLabels(ctx context.Context, name string, labels map[string]string, value float64) {
+	if counter, ok := c.GetWithLabels(ctx, name, labels, Statistics); ok {
+		counter.CalculateStats(value)
+		_ = c.update(ctx)
+	}
+}
+
+// LastWithLabels records the last calculated measurement value for a counter with labels.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Last type.
+//		- labels map[string]string counter labels.
+//		- value float64 a last value to record.
+func (c *CachedCounters) LastWithLabels(ctx context.Context, name string, labels map[string]string, value float64) {
+	if counter, ok := c.GetWithLabels(ctx, name, labels, LastValue); ok {
+		counter.SetLast(value)
+		_ = c.update(ctx)
+	}
+}
+
+// TimestampNowWithLabels records the current time as a timestamp for a counter with labels.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Timestamp type.
+//		- labels map[string]string counter labels.
+func (c *CachedCounters) TimestampNowWithLabels(ctx context.Context, name string, labels map[string]string) {
+	c.TimestampWithLabels(ctx, name, labels, time.Now())
+}
+
+// TimestampWithLabels records the given timestamp for a counter with labels.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Timestamp type.
+//		- labels map[string]string counter labels.
+//		- value time.Time a timestamp to record.
+func (c *CachedCounters) TimestampWithLabels(ctx context.Context, name string, labels map[string]string, value time.Time) {
+	if counter, ok := c.GetWithLabels(ctx, name, labels, Timestamp); ok {
+		counter.SetTime(value)
+		_ = c.update(ctx)
+	}
+}
+
+// IncrementOneWithLabels increments counter with labels by 1.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Increment type.
+//		- labels map[string]string counter labels.
+func (c *CachedCounters) IncrementOneWithLabels(ctx context.Context, name string, labels map[string]string) {
+	c.IncrementWithLabels(ctx, name, labels, 1)
+}
+
+// IncrementWithLabels increments counter with labels by given value.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Increment type.
+//		- labels map[string]string counter labels.
+//		- value int64 a value to add to the counter.
+func (c *CachedCounters) IncrementWithLabels(ctx context.Context, name string, labels map[string]string, value int64) {
+	if counter, ok := c.GetWithLabels(ctx, name, labels, Increment); ok {
 		counter.Inc(value)
 		_ = c.update(ctx)
 	}
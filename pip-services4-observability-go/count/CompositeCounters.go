@@ -184,3 +184,117 @@ func (c *CompositeCounters) Increment(ctx context.Context, name string, value in
 		}
 	}
 }
+
+// BeginTimingWithLabels begins measurement of execution time interval for a counter with labels.
+// It returns Timing object which has to be called at
+// Timing.endTiming to end the measurement and update the counter.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Interval type.
+//		- labels map[string]string counter labels.
+//	Returns: *Timing a Timing callback object to end timing.
+func (c *CompositeCounters) BeginTimingWithLabels(ctx context.Context, name string, labels map[string]string) *CounterTiming {
+	return NewCounterTimingWithLabels(name, labels, c)
+}
+
+// EndTimingWithLabels ends measurement of execution elapsed time and updates specified counter with labels.
+// Counters that don't support labels receive the measurement without them.
+//
+//	see Timing.EndTiming
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name
+//		- labels map[string]string counter labels.
+//		- elapsed float64 execution elapsed time in milliseconds to update the counter.
+func (c *CompositeCounters) EndTimingWithLabels(ctx context.Context, name string, labels map[string]string, elapsed float64) {
+	for _, counter := range c.counters {
+		if counter != nil {
+			if callback, ok := counter.(ILabeledCounterTimingCallback); ok {
+				callback.EndTimingWithLabels(ctx, name, labels, elapsed)
+			} else if callback, ok := counter.(ICounterTimingCallback); ok {
+				callback.EndTiming(ctx, name, elapsed)
+			}
+		}
+	}
+}
+
+// StatsWithLabels calculates min/average/max statistics for a counter with labels.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Statistics type
+//		- labels map[string]string counter labels.
+//		- value float64 a value to update statistics
+func (c *CompositeCounters) StatsWithLabels(ctx context.Context, name string, labels map[string]string, value float64) {
+	for _, counter := range c.counters {
+		if counter != nil {
+			counter.StatsWithLabels(ctx, name, labels, value)
+		}
+	}
+}
+
+// LastWithLabels records the last calculated measurement value for a counter with labels.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Last type.
+//		- labels map[string]string counter labels.
+//		- value float64 a last value to record.
+func (c *CompositeCounters) LastWithLabels(ctx context.Context, name string, labels map[string]string, value float64) {
+	for _, counter := range c.counters {
+		if counter != nil {
+			counter.LastWithLabels(ctx, name, labels, value)
+		}
+	}
+}
+
+// TimestampNowWithLabels records the current time as a timestamp for a counter with labels.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Timestamp type.
+//		- labels map[string]string counter labels.
+func (c *CompositeCounters) TimestampNowWithLabels(ctx context.Context, name string, labels map[string]string) {
+	c.TimestampWithLabels(ctx, name, labels, time.Now())
+}
+
+// TimestampWithLabels records the given timestamp for a counter with labels.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Timestamp type.
+//		- labels map[string]string counter labels.
+//		- value time.Time a timestamp to record.
+func (c *CompositeCounters) TimestampWithLabels(ctx context.Context, name string, labels map[string]string, value time.Time) {
+	for _, counter := range c.counters {
+		if counter != nil {
+			counter.TimestampWithLabels(ctx, name, labels, value)
+		}
+	}
+}
+
+// IncrementOneWithLabels increments counter with labels by 1.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Increment type.
+//		- labels map[string]string counter labels.
+func (c *CompositeCounters) IncrementOneWithLabels(ctx context.Context, name string, labels map[string]string) {
+	c.IncrementWithLabels(ctx, name, labels, 1)
+}
+
+// IncrementWithLabels increments counter with labels by given value.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Increment type.
+//		- labels map[string]string counter labels.
+//		- value int64 a value to add to the counter.
+func (c *CompositeCounters) IncrementWithLabels(ctx context.Context, name string, labels map[string]string, value int64) {
+	for _, counter := range c.counters {
+		if counter != nil {
+			counter.IncrementWithLabels(ctx, name, labels, value)
+		}
+	}
+}
@@ -15,4 +15,6 @@ type Counter struct {
 	Max     float64     `json:"max"`
 	Average float64     `json:"average"`
 	Time    time.Time   `json:"time"`
+	// Labels are dimensions of the measurement. They shall not be modified.
+	Labels map[string]string `json:"labels,omitempty"`
 }
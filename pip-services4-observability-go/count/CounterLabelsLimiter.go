@@ -0,0 +1,158 @@
+package count
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+)
+
+const (
+	DefaultMaxLabels            = 10
+	DefaultMaxLabelSets         = 100
+	ConfigParameterMaxLabels    = "options.max_labels"
+	ConfigParameterMaxLabelSets = "options.max_label_sets"
+)
+
+// CounterLabelsLimiter limits cardinality of counter labels to protect monitoring backends
+// from unbounded number of time series.
+// Labels over the maximum number per measurement are dropped in the order of their keys.
+// When a counter reaches the maximum number of distinct label sets,
+// measurements with new label sets are recorded without labels.
+//
+//	Configuration parameters:
+//		- options:
+//			- max_labels: maximum number of labels per measurement (default: 10)
+//			- max_label_sets: maximum number of distinct label sets per counter (default: 100)
+type CounterLabelsLimiter struct {
+	maxLabels    int
+	maxLabelSets int
+	labelSets    map[string]map[string]bool
+	mux          sync.Mutex
+}
+
+// NewCounterLabelsLimiter creates a new instance of the limiter with default limits.
+//
+//	Returns: *CounterLabelsLimiter
+func NewCounterLabelsLimiter() *CounterLabelsLimiter {
+	return &CounterLabelsLimiter{
+		maxLabels:    DefaultMaxLabels,
+		maxLabelSets: DefaultMaxLabelSets,
+		labelSets:    make(map[string]map[string]bool),
+	}
+}
+
+// Configure configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config *config.ConfigParams configuration parameters to be set.
+func (c *CounterLabelsLimiter) Configure(ctx context.Context, config *config.ConfigParams) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.maxLabels = config.GetAsIntegerWithDefault(ConfigParameterMaxLabels, c.maxLabels)
+	if c.maxLabels < 0 {
+		c.maxLabels = 0
+	}
+	c.maxLabelSets = config.GetAsIntegerWithDefault(ConfigParameterMaxLabelSets, c.maxLabelSets)
+}
+
+// Limit applies cardinality limits to labels of a counter measurement.
+//
+//	Parameters:
+//		- name string a counter name.
+//		- labels map[string]string measurement labels.
+//	Returns: map[string]string a copy of labels within the limits or nil when labels shall be dropped.
+func (c *CounterLabelsLimiter) Limit(name string, labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	keys := sortedLabelKeys(labels)
+	if len(keys) > c.maxLabels {
+		keys = keys[:c.maxLabels]
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(keys))
+	for _, key := range keys {
+		result[key] = labels[key]
+	}
+
+	key := CounterLabelsToString(result)
+	sets, ok := c.labelSets[name]
+	if !ok {
+		sets = make(map[string]bool)
+		c.labelSets[name] = sets
+	}
+	if !sets[key] {
+		if len(sets) >= c.maxLabelSets {
+			return nil
+		}
+		sets[key] = true
+	}
+	return result
+}
+
+// Clear forgets label sets of a counter specified by its name.
+//
+//	Parameters:
+//		- name string a counter name to clear.
+func (c *CounterLabelsLimiter) Clear(name string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	delete(c.labelSets, name)
+}
+
+// ClearAll forgets label sets of all counters.
+func (c *CounterLabelsLimiter) ClearAll() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.labelSets = make(map[string]map[string]bool)
+}
+
+// CounterLabelsToString converts labels into a string with pairs sorted by keys.
+//
+//	Parameters:
+//		- labels map[string]string counter labels.
+//	Returns: string labels as {key1="value1",key2="value2"} or empty string if there are no labels.
+func CounterLabelsToString(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString("{")
+	for index, key := range sortedLabelKeys(labels) {
+		if index > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(key)
+		builder.WriteString("=")
+		builder.WriteString(strconv.Quote(labels[key]))
+	}
+	builder.WriteString("}")
+	return builder.String()
+}
+
+func sortedLabelKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -15,6 +15,7 @@ type CounterTiming struct {
 	start    time.Time
 	callback ICounterTimingCallback
 	counter  string
+	labels   map[string]string
 }
 
 // NewEmptyCounterTiming creates a new instance of the timing callback object.
@@ -40,6 +41,23 @@ func NewCounterTiming(counter string, callback ICounterTimingCallback) *CounterT
 	}
 }
 
+// NewCounterTimingWithLabels creates a new instance of the timing callback object
+// for a counter with dimensional labels.
+//
+//	Parameters:
+//		- counter string an associated counter name
+//		- labels map[string]string counter labels
+//		- callback ITimingCallback a callback that shall be called when EndTiming is called.
+//	Returns: *Timing
+func NewCounterTimingWithLabels(counter string, labels map[string]string, callback ICounterTimingCallback) *CounterTiming {
+	return &CounterTiming{
+		start:    time.Now(),
+		callback: callback,
+		counter:  counter,
+		labels:   labels,
+	}
+}
+
 // EndTiming ends timing of an execution block, calculates
 // elapsed time and updates the associated counter.
 func (c *CounterTiming) EndTiming(ctx context.Context) {
@@ -48,5 +66,9 @@ func (c *CounterTiming) EndTiming(ctx context.Context) {
 	}
 
 	elapsed := time.Since(c.start).Seconds() * 1000
+	if callback, ok := c.callback.(ILabeledCounterTimingCallback); ok && len(c.labels) > 0 {
+		callback.EndTimingWithLabels(ctx, c.counter, c.labels, elapsed)
+		return
+	}
 	c.callback.EndTiming(ctx, c.counter, elapsed)
 }
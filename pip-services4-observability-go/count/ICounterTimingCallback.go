@@ -13,3 +13,14 @@ import "context"
 type ICounterTimingCallback interface {
 	EndTiming(ctx context.Context, name string, elapsed float64)
 }
+
+// ILabeledCounterTimingCallback ends measurement of execution elapsed time and updates specified counter with labels.
+//	see Timing.EndTiming
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name
+//		- labels map[string]string counter labels
+//		- elapsed float32 execution elapsed time in milliseconds to update the counter.
+type ILabeledCounterTimingCallback interface {
+	EndTimingWithLabels(ctx context.Context, name string, labels map[string]string, elapsed float64)
+}
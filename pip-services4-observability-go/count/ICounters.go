@@ -9,6 +9,8 @@ import (
 // The performance counters measure how code is performing: how fast or slow,
 // how many transactions performed, how many objects are stored, what was the latest transaction time and so on.
 // They are critical to monitor and improve performance, scalability and reliability of code in production.
+// Variants with labels record measurements split by dimensions, e.g. by method or status.
+// Each distinct set of labels is a separate time series, so label values shall have a limited number of values.
 type ICounters interface {
 	// BeginTiming begins measurement of execution time interval.
 	// It returns Timing object which has to be called at
@@ -33,4 +35,26 @@ type ICounters interface {
 
 	// Increment increments counter by given value.
 	Increment(ctx context.Context, name string, value int64)
+
+	// BeginTimingWithLabels begins measurement of execution time interval
+	// for a counter with dimensional labels.
+	BeginTimingWithLabels(ctx context.Context, name string, labels map[string]string) *CounterTiming
+
+	// StatsWithLabels calculates min/average/max statistics for a counter with dimensional labels.
+	StatsWithLabels(ctx context.Context, name string, labels map[string]string, value float64)
+
+	// LastWithLabels records the last calculated measurement value for a counter with dimensional labels.
+	LastWithLabels(ctx context.Context, name string, labels map[string]string, value float64)
+
+	// TimestampNowWithLabels records the current time as a timestamp for a counter with dimensional labels.
+	TimestampNowWithLabels(ctx context.Context, name string, labels map[string]string)
+
+	// TimestampWithLabels records the given timestamp for a counter with dimensional labels.
+	TimestampWithLabels(ctx context.Context, name string, labels map[string]string, value time.Time)
+
+	// IncrementOneWithLabels increments counter with dimensional labels by 1.
+	IncrementOneWithLabels(ctx context.Context, name string, labels map[string]string)
+
+	// IncrementWithLabels increments counter with dimensional labels by given value.
+	IncrementWithLabels(ctx context.Context, name string, labels map[string]string, value int64)
 }
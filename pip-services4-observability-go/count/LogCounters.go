@@ -60,7 +60,7 @@ func (c *LogCounters) SetReferences(ctx context.Context, references refer.IRefer
 }
 
 func (c *LogCounters) counterToString(counter Counter) string {
-	result := "Counter " + counter.Name + " { "
+	result := "Counter " + counter.Name + CounterLabelsToString(counter.Labels) + " { "
 	result = result + "\"type\": " + counter.Type.ToString()
 
 	switch counter.Type {
@@ -104,7 +104,10 @@ func (c *LogCounters) Save(ctx context.Context, counters []Counter) error {
 	}
 
 	sort.Slice(counters, func(i, j int) bool {
-		return counters[i].Name < counters[j].Name
+		if counters[i].Name != counters[j].Name {
+			return counters[i].Name < counters[j].Name
+		}
+		return CounterLabelsToString(counters[i].Labels) < CounterLabelsToString(counters[j].Labels)
 	})
 
 	for _, counter := range counters {
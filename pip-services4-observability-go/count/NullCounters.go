@@ -66,3 +66,63 @@ func (c *NullCounters) IncrementOne(ctx context.Context, name string) {}
 //		- name string a counter name of Increment type.
 //		- value int64 a value to add to the counter.
 func (c *NullCounters) Increment(ctx context.Context, name string, value int64) {}
+
+// BeginTimingWithLabels begins measurement of execution time interval for a counter with labels.
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Interval type.
+//		- labels map[string]string counter labels.
+//	Returns: *Timing a Timing callback object to end timing.
+func (c *NullCounters) BeginTimingWithLabels(ctx context.Context, name string, labels map[string]string) *CounterTiming {
+	return NewEmptyCounterTiming()
+}
+
+// StatsWithLabels calculates min/average/max statistics for a counter with labels.
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Statistics type
+//		- labels map[string]string counter labels.
+//		- value float64 a value to update statistics
+func (c *NullCounters) StatsWithLabels(ctx context.Context, name string, labels map[string]string, value float64) {
+}
+
+// LastWithLabels records the last calculated measurement value for a counter with labels.
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Last type.
+//		- labels map[string]string counter labels.
+//		- value float64 a last value to record.
+func (c *NullCounters) LastWithLabels(ctx context.Context, name string, labels map[string]string, value float64) {
+}
+
+// TimestampNowWithLabels records the current time as a timestamp for a counter with labels.
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Timestamp type.
+//		- labels map[string]string counter labels.
+func (c *NullCounters) TimestampNowWithLabels(ctx context.Context, name string, labels map[string]string) {}
+
+// TimestampWithLabels records the given timestamp for a counter with labels.
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Timestamp type.
+//		- labels map[string]string counter labels.
+//		- value time.Time a timestamp to record.
+func (c *NullCounters) TimestampWithLabels(ctx context.Context, name string, labels map[string]string, value time.Time) {
+}
+
+// IncrementOneWithLabels increments counter with labels by 1.
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Increment type.
+//		- labels map[string]string counter labels.
+func (c *NullCounters) IncrementOneWithLabels(ctx context.Context, name string, labels map[string]string) {}
+
+// IncrementWithLabels increments counter with labels by given value.
+//	Parameters:
+//		- ctx context.Context
+//		- name string a counter name of Increment type.
+//		- labels map[string]string counter labels.
+//		- value int64 a value to add to the counter.
+func (c *NullCounters) IncrementWithLabels(ctx context.Context, name string, labels map[string]string, value int64) {
+}
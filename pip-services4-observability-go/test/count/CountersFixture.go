@@ -71,3 +71,62 @@ func (c *CountersFixture) TestMeasureElapsedTime(t *testing.T) {
 
 	c.counters.Dump(context.Background())
 }
+
+func (c *CountersFixture) TestCountersWithLabels(t *testing.T) {
+	ctx := context.Background()
+
+	c.counters.IncrementOneWithLabels(ctx, "Test.Labeled", map[string]string{"method": "get"})
+	c.counters.IncrementWithLabels(ctx, "Test.Labeled", map[string]string{"method": "get"}, 2)
+	c.counters.IncrementOneWithLabels(ctx, "Test.Labeled", map[string]string{"method": "post"})
+	c.counters.IncrementOne(ctx, "Test.Labeled")
+
+	counter, ok := c.counters.GetWithLabels(ctx, "Test.Labeled", map[string]string{"method": "get"}, count.Increment)
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), counter.Count())
+	assert.Equal(t, map[string]string{"method": "get"}, counter.Labels())
+
+	counter, ok = c.counters.GetWithLabels(ctx, "Test.Labeled", map[string]string{"method": "post"}, count.Increment)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), counter.Count())
+
+	counter, ok = c.counters.Get(ctx, "Test.Labeled", count.Increment)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), counter.Count())
+	assert.Nil(t, counter.Labels())
+
+	timing := c.counters.BeginTimingWithLabels(ctx, "Test.LabeledElapsed", map[string]string{"method": "get"})
+	timing.EndTiming(ctx)
+
+	counter, ok = c.counters.GetWithLabels(ctx, "Test.LabeledElapsed", map[string]string{"method": "get"}, count.Interval)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), counter.Count())
+
+	c.counters.Clear(ctx, "Test.Labeled")
+	for _, counter := range c.counters.GetAllCountersStats() {
+		assert.NotEqual(t, "Test.Labeled", counter.Name)
+	}
+
+	_ = c.counters.Dump(ctx)
+}
+
+func (c *CountersFixture) TestLabelsLimits(t *testing.T) {
+	ctx := context.Background()
+
+	c.counters.IncrementOneWithLabels(ctx, "Test.Limited", map[string]string{"a": "1", "b": "1", "c": "1"})
+
+	counter, ok := c.counters.GetWithLabels(ctx, "Test.Limited", map[string]string{"a": "1", "b": "1"}, count.Increment)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), counter.Count())
+	assert.Equal(t, map[string]string{"a": "1", "b": "1"}, counter.Labels())
+
+	c.counters.IncrementOneWithLabels(ctx, "Test.Limited", map[string]string{"a": "2"})
+	c.counters.IncrementOneWithLabels(ctx, "Test.Limited", map[string]string{"a": "3"})
+
+	// Label sets over the limit are recorded without labels
+	counter, ok = c.counters.Get(ctx, "Test.Limited", count.Increment)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), counter.Count())
+	assert.Len(t, c.counters.GetAll(), 3)
+
+	_ = c.counters.Dump(ctx)
+}
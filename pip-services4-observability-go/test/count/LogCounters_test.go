@@ -23,6 +23,22 @@ func TestLogCountersMeasureElapsedTime(t *testing.T) {
 	fixture.TestMeasureElapsedTime(t)
 }
 
+func TestLogCountersWithLabels(t *testing.T) {
+	counters := count.NewLogCounters()
+	fixture := NewCountersFixture(counters.CachedCounters)
+	fixture.TestCountersWithLabels(t)
+}
+
+func TestLogCountersLabelsLimits(t *testing.T) {
+	counters := count.NewLogCounters()
+	counters.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"options.max_labels", 2,
+		"options.max_label_sets", 2,
+	))
+	fixture := NewCountersFixture(counters.CachedCounters)
+	fixture.TestLabelsLimits(t)
+}
+
 func TestLogCountersSave(t *testing.T) {
 	counters := count.NewLogCounters()
 	logger := log.NewConsoleLogger()
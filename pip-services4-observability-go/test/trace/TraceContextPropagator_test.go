@@ -0,0 +1,106 @@
+package test_tracer
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testTraceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanId  = "00f067aa0ba902b7"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	spanContext, ok := ctrace.ParseTraceParent("00-" + testTraceId + "-" + testSpanId + "-01")
+	assert.True(t, ok)
+	assert.Equal(t, testTraceId, spanContext.TraceId)
+	assert.Equal(t, testSpanId, spanContext.SpanId)
+	assert.True(t, spanContext.IsSampled())
+	assert.Equal(t, "00-"+testTraceId+"-"+testSpanId+"-01", spanContext.TraceParent())
+
+	// Future versions may have extra fields
+	_, ok = ctrace.ParseTraceParent("01-" + testTraceId + "-" + testSpanId + "-00-extra")
+	assert.True(t, ok)
+
+	invalid := []string{
+		"",
+		"00-" + testTraceId + "-" + testSpanId,
+		"00-" + testTraceId + "-" + testSpanId + "-01-extra",
+		"ff-" + testTraceId + "-" + testSpanId + "-01",
+		"00-00000000000000000000000000000000-" + testSpanId + "-01",
+		"00-" + testTraceId + "-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-" + testSpanId + "-01",
+		"00-" + testTraceId + "-" + testSpanId + "-1",
+	}
+	for _, value := range invalid {
+		_, ok = ctrace.ParseTraceParent(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestBaggage(t *testing.T) {
+	baggage := ctrace.ParseBaggage("userId=alice, serverNode = DF%2028 ;prop=1,invalid,isProduction=false")
+	assert.Len(t, baggage, 3)
+	assert.Equal(t, "alice", baggage["userId"])
+	assert.Equal(t, "DF 28", baggage["serverNode"])
+	assert.Equal(t, "false", baggage["isProduction"])
+
+	assert.Equal(t, "isProduction=false,serverNode=DF%2028,userId=alice", baggage.String())
+
+	ctx := ctrace.NewContextWithBaggage(context.Background(), baggage)
+	ctx = ctrace.NewContextWithBaggageValue(ctx, "tenant", "acme")
+	assert.Equal(t, "acme", ctrace.GetBaggageValue(ctx, "tenant"))
+	assert.Equal(t, "alice", ctrace.GetBaggageValue(ctx, "userId"))
+	assert.Len(t, baggage, 3)
+}
+
+func TestInjectAndExtractTraceContext(t *testing.T) {
+	ctx := ctrace.NewContextWithSpanContext(context.Background(), ctrace.SpanContext{
+		TraceId:    testTraceId,
+		SpanId:     testSpanId,
+		TraceFlags: ctrace.TraceFlagsSampled,
+		TraceState: "vendor=value",
+	})
+	ctx = ctrace.NewContextWithBaggageValue(ctx, "userId", "alice")
+
+	header := http.Header{}
+	ctrace.InjectTraceContext(ctx, ctrace.HeaderTraceContextCarrier(header))
+	assert.Equal(t, "00-"+testTraceId+"-"+testSpanId+"-01", header.Get("Traceparent"))
+	assert.Equal(t, "vendor=value", header.Get("Tracestate"))
+	assert.Equal(t, "userId=alice", header.Get("Baggage"))
+
+	extracted := ctrace.ExtractTraceContext(context.Background(), ctrace.HeaderTraceContextCarrier(header))
+	spanContext, ok := ctrace.GetSpanContext(extracted)
+	assert.True(t, ok)
+	assert.True(t, spanContext.Remote)
+	assert.Equal(t, testTraceId, spanContext.TraceId)
+	assert.Equal(t, testSpanId, spanContext.SpanId)
+	assert.Equal(t, "vendor=value", spanContext.TraceState)
+	assert.Equal(t, "alice", ctrace.GetBaggageValue(extracted, "userId"))
+
+	headers := map[string]string{}
+	ctrace.InjectTraceContext(ctx, ctrace.MapTraceContextCarrier(headers))
+	assert.Equal(t, "00-"+testTraceId+"-"+testSpanId+"-01", headers[ctrace.TraceParentHeader])
+
+	metadata := map[string][]string{}
+	ctrace.InjectTraceContext(ctx, ctrace.MultiValueTraceContextCarrier(metadata))
+	assert.Equal(t, []string{"userId=alice"}, metadata[ctrace.BaggageHeader])
+}
+
+func TestExtractInvalidTraceContext(t *testing.T) {
+	carrier := ctrace.MapTraceContextCarrier{
+		ctrace.TraceParentHeader: "invalid",
+	}
+	ctx := ctrace.ExtractTraceContext(context.Background(), carrier)
+	_, ok := ctrace.GetSpanContext(ctx)
+	assert.False(t, ok)
+	assert.Nil(t, ctrace.GetBaggage(ctx))
+
+	headers := map[string]string{}
+	ctrace.InjectTraceContext(ctx, ctrace.MapTraceContextCarrier(headers))
+	assert.Empty(t, headers)
+}
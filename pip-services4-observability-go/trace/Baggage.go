@@ -0,0 +1,157 @@
+package trace
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Baggage key-value pairs propagated between services together with the trace
+// as defined by W3C Baggage specification (https://www.w3.org/TR/baggage/).
+// Metadata properties of baggage members are not supported and dropped.
+type Baggage map[string]string
+
+const (
+	// MaxBaggageMembers the maximum number of baggage members
+	MaxBaggageMembers = 180
+	// MaxBaggageLength the maximum length of baggage header
+	MaxBaggageLength = 8192
+)
+
+type baggageKey struct{}
+
+// ParseBaggage parses W3C baggage header value.
+// Invalid members are skipped.
+//
+//	Parameters:
+//		- value a baggage header value.
+//	Returns: the parsed baggage.
+func ParseBaggage(value string) Baggage {
+	result := Baggage{}
+	if len(value) > MaxBaggageLength {
+		return result
+	}
+
+	for _, member := range strings.Split(value, ",") {
+		if len(result) >= MaxBaggageMembers {
+			break
+		}
+
+		// Drop member properties
+		if index := strings.IndexByte(member, ';'); index >= 0 {
+			member = member[:index]
+		}
+
+		key, value, ok := strings.Cut(member, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if !isBaggageToken(key) {
+			continue
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		result[key] = value
+	}
+
+	return result
+}
+
+func isBaggageToken(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, char := range value {
+		if char <= ' ' || char >= 0x7f || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", char) {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the baggage as W3C baggage header value.
+// Members are sorted by keys. Members with invalid keys and members
+// over the size limits are skipped.
+//
+//	Returns: the header value.
+func (c Baggage) String() string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		if isBaggageToken(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	builder := strings.Builder{}
+	count := 0
+	for _, key := range keys {
+		member := key + "=" + url.PathEscape(c[key])
+		if count >= MaxBaggageMembers || builder.Len()+len(member)+1 > MaxBaggageLength {
+			break
+		}
+		if count > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(member)
+		count++
+	}
+	return builder.String()
+}
+
+// NewContextWithBaggage creates a new context with the baggage.
+// The baggage replaces the baggage of the parent context.
+//
+//	Parameters:
+//		- ctx a parent context.
+//		- baggage a baggage to keep.
+//	Returns: a new context with the baggage.
+func NewContextWithBaggage(ctx context.Context, baggage Baggage) context.Context {
+	return context.WithValue(ctx, baggageKey{}, baggage)
+}
+
+// NewContextWithBaggageValue creates a new context with the baggage of the parent context
+// extended with the key-value pair.
+//
+//	Parameters:
+//		- ctx a parent context.
+//		- key a baggage key.
+//		- value a baggage value.
+//	Returns: a new context with the baggage.
+func NewContextWithBaggageValue(ctx context.Context, key string, value string) context.Context {
+	parent := GetBaggage(ctx)
+	baggage := make(Baggage, len(parent)+1)
+	for k, v := range parent {
+		baggage[k] = v
+	}
+	baggage[key] = value
+	return NewContextWithBaggage(ctx, baggage)
+}
+
+// GetBaggage gets the baggage from the context.
+// The returned baggage shall not be modified.
+//
+//	Parameters:
+//		- ctx a context to read.
+//	Returns: the baggage or nil if it is not set.
+func GetBaggage(ctx context.Context) Baggage {
+	if ctx == nil {
+		return nil
+	}
+	baggage, _ := ctx.Value(baggageKey{}).(Baggage)
+	return baggage
+}
+
+// GetBaggageValue gets the baggage value from the context.
+//
+//	Parameters:
+//		- ctx a context to read.
+//		- key a baggage key.
+//	Returns: the baggage value or empty string if it is not set.
+func GetBaggageValue(ctx context.Context, key string) string {
+	return GetBaggage(ctx)[key]
+}
@@ -0,0 +1,137 @@
+package trace
+
+import (
+	"context"
+	"strings"
+)
+
+// SpanContext identifies a span in a distributed trace as defined by
+// W3C Trace Context specification (https://www.w3.org/TR/trace-context/).
+// It is carried in context.Context and passed between services in
+// traceparent and tracestate headers.
+type SpanContext struct {
+	// The trace id as 32 lowercase hex characters
+	TraceId string
+	// The span id as 16 lowercase hex characters
+	SpanId string
+	// The trace flags. Bit 0x01 means the trace is sampled
+	TraceFlags byte
+	// The vendor specific trace state
+	TraceState string
+	// True when the span context was received from a remote service
+	Remote bool
+}
+
+const (
+	// TraceFlagsSampled the flag for sampled traces
+	TraceFlagsSampled byte = 0x01
+
+	traceParentVersion = "00"
+	hexDigits          = "0123456789abcdef"
+)
+
+type spanContextKey struct{}
+
+// IsValid checks if the span context has non-zero trace and span ids.
+//
+//	Returns: true if the span context is valid and false otherwise.
+func (c SpanContext) IsValid() bool {
+	return isValidTraceId(c.TraceId, 32) && isValidTraceId(c.SpanId, 16)
+}
+
+// IsSampled checks if the trace is sampled.
+//
+//	Returns: true if the sampled flag is set.
+func (c SpanContext) IsSampled() bool {
+	return c.TraceFlags&TraceFlagsSampled != 0
+}
+
+// TraceParent formats the span context as W3C traceparent header value.
+//
+//	Returns: the header value or empty string if the span context is invalid.
+func (c SpanContext) TraceParent() string {
+	if !c.IsValid() {
+		return ""
+	}
+	return traceParentVersion + "-" + c.TraceId + "-" + c.SpanId + "-" +
+		string([]byte{hexDigits[c.TraceFlags>>4], hexDigits[c.TraceFlags&0x0f]})
+}
+
+// ParseTraceParent parses W3C traceparent header value.
+// Values of unknown future versions are accepted as long as they start with known fields.
+//
+//	Parameters:
+//		- value a traceparent header value.
+//	Returns: the parsed span context and true, or empty span context and false if the value is invalid.
+func ParseTraceParent(value string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, false
+	}
+
+	version := parts[0]
+	if len(version) != 2 || !isHex(version) || version == "ff" {
+		return SpanContext{}, false
+	}
+	if version == traceParentVersion && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+
+	flags := parts[3]
+	if len(flags) != 2 || !isHex(flags) {
+		return SpanContext{}, false
+	}
+
+	result := SpanContext{
+		TraceId:    parts[1],
+		SpanId:     parts[2],
+		TraceFlags: fromHex(flags[0])<<4 | fromHex(flags[1]),
+	}
+	if !result.IsValid() {
+		return SpanContext{}, false
+	}
+	return result, true
+}
+
+func isHex(value string) bool {
+	for index := 0; index < len(value); index++ {
+		if strings.IndexByte(hexDigits, value[index]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func fromHex(value byte) byte {
+	return byte(strings.IndexByte(hexDigits, value))
+}
+
+func isValidTraceId(value string, length int) bool {
+	return len(value) == length && isHex(value) && strings.Trim(value, "0") != ""
+}
+
+// NewContextWithSpanContext creates a new context with the span context.
+//
+//	Parameters:
+//		- ctx a parent context.
+//		- spanContext a span context to keep.
+//	Returns: a new context with the span context.
+func NewContextWithSpanContext(ctx context.Context, spanContext SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, spanContext)
+}
+
+// GetSpanContext gets the span context from the context.
+//
+//	Parameters:
+//		- ctx a context to read.
+//	Returns: the span context and true, or empty span context and false if it is not set.
+func GetSpanContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+	spanContext, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	if !ok || !spanContext.IsValid() {
+		return SpanContext{}, false
+	}
+	return spanContext, true
+}
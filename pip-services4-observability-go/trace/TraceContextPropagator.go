@@ -0,0 +1,115 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const (
+	// TraceParentHeader the W3C header with trace and parent span ids
+	TraceParentHeader = "traceparent"
+	// TraceStateHeader the W3C header with vendor specific trace state
+	TraceStateHeader = "tracestate"
+	// BaggageHeader the W3C header with baggage
+	BaggageHeader = "baggage"
+)
+
+// ITraceContextCarrier interface for headers or metadata that carry
+// trace context between services.
+type ITraceContextCarrier interface {
+	// Get gets the value of the key or empty string if it is not set.
+	Get(key string) string
+
+	// Set sets the value of the key.
+	Set(key string, value string)
+}
+
+// MapTraceContextCarrier carrier over a map of strings, e.g. message headers.
+type MapTraceContextCarrier map[string]string
+
+// Get gets the value of the key.
+func (c MapTraceContextCarrier) Get(key string) string {
+	return c[key]
+}
+
+// Set sets the value of the key.
+func (c MapTraceContextCarrier) Set(key string, value string) {
+	c[key] = value
+}
+
+// MultiValueTraceContextCarrier carrier over a map of string slices, e.g. gRPC metadata.
+// Keys are used as is, so they shall be in lower case.
+type MultiValueTraceContextCarrier map[string][]string
+
+// Get gets the first value of the key.
+func (c MultiValueTraceContextCarrier) Get(key string) string {
+	values := c[key]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set replaces values of the key.
+func (c MultiValueTraceContextCarrier) Set(key string, value string) {
+	c[key] = []string{value}
+}
+
+// HeaderTraceContextCarrier carrier over HTTP headers.
+type HeaderTraceContextCarrier http.Header
+
+// Get gets the first value of the header.
+func (c HeaderTraceContextCarrier) Get(key string) string {
+	return http.Header(c).Get(key)
+}
+
+// Set replaces values of the header.
+func (c HeaderTraceContextCarrier) Set(key string, value string) {
+	http.Header(c).Set(key, value)
+}
+
+// InjectTraceContext writes the span context and baggage kept in the context
+// into the carrier as traceparent, tracestate and baggage headers.
+//
+//	Parameters:
+//		- ctx a context with the span context and baggage.
+//		- carrier a carrier to write headers to.
+func InjectTraceContext(ctx context.Context, carrier ITraceContextCarrier) {
+	if spanContext, ok := GetSpanContext(ctx); ok {
+		carrier.Set(TraceParentHeader, spanContext.TraceParent())
+		if spanContext.TraceState != "" {
+			carrier.Set(TraceStateHeader, spanContext.TraceState)
+		}
+	}
+
+	if baggage := GetBaggage(ctx); len(baggage) > 0 {
+		if value := baggage.String(); value != "" {
+			carrier.Set(BaggageHeader, value)
+		}
+	}
+}
+
+// ExtractTraceContext reads traceparent, tracestate and baggage headers from the carrier
+// and returns a context with the remote span context and baggage.
+// Invalid headers are ignored.
+//
+//	Parameters:
+//		- ctx a parent context.
+//		- carrier a carrier to read headers from.
+//	Returns: a context with the extracted span context and baggage.
+func ExtractTraceContext(ctx context.Context, carrier ITraceContextCarrier) context.Context {
+	if spanContext, ok := ParseTraceParent(carrier.Get(TraceParentHeader)); ok {
+		spanContext.TraceState = strings.TrimSpace(carrier.Get(TraceStateHeader))
+		spanContext.Remote = true
+		ctx = NewContextWithSpanContext(ctx, spanContext)
+	}
+
+	if value := carrier.Get(BaggageHeader); value != "" {
+		if baggage := ParseBaggage(value); len(baggage) > 0 {
+			ctx = NewContextWithBaggage(ctx, baggage)
+		}
+	}
+
+	return ctx
+}
@@ -0,0 +1,35 @@
+package build
+
+import (
+	cbuild "github.com/pip-services4/pip-services4-go/pip-services4-components-go/build"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	count "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/count"
+	log "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/log"
+	trace "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/trace"
+)
+
+// DefaultOpenTelemetryFactory are creates OpenTelemetry components by their descriptors.
+//
+//	See OtelTracer
+//	See OtelCounters
+//	See OtelLogger
+type DefaultOpenTelemetryFactory struct {
+	*cbuild.Factory
+}
+
+// NewDefaultOpenTelemetryFactory create a new instance of the factory.
+//
+//	Returns: *DefaultOpenTelemetryFactory
+func NewDefaultOpenTelemetryFactory() *DefaultOpenTelemetryFactory {
+	c := DefaultOpenTelemetryFactory{}
+	c.Factory = cbuild.NewFactory()
+	otelTracerDescriptor := cref.NewDescriptor("pip-services", "tracer", "otel", "*", "1.0")
+	otelCountersDescriptor := cref.NewDescriptor("pip-services", "counters", "otel", "*", "1.0")
+	otelLoggerDescriptor := cref.NewDescriptor("pip-services", "logger", "otel", "*", "1.0")
+
+	c.RegisterType(otelTracerDescriptor, trace.NewOtelTracer)
+	c.RegisterType(otelCountersDescriptor, count.NewOtelCounters)
+	c.RegisterType(otelLoggerDescriptor, log.NewOtelLogger)
+
+	return &c
+}
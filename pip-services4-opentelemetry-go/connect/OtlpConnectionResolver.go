@@ -0,0 +1,124 @@
+package connect
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	cconn "github.com/pip-services4/pip-services4-go/pip-services4-config-go/connect"
+)
+
+const (
+	DefaultOtlpProtocol = "http"
+	DefaultOtlpHost     = "localhost"
+	DefaultOtlpPort     = 4318
+)
+
+// OtlpConnectionResolver helper class to retrieve connections to OpenTelemetry collectors
+// that accept OTLP over HTTP.
+//
+//	Configuration parameters:
+//		- connection:
+//			- discovery_key:         (optional) a key to retrieve the connection from IDiscovery
+//			- protocol:              (optional) connection protocol: http or https (default: http)
+//			- host:                  (optional) host name or IP address (default: localhost)
+//			- port:                  (optional) port number (default: 4318)
+//			- uri:                   (optional) resource URI or connection string with all parameters in it
+//
+//	References:
+//		- *:discovery:*:*:1.0        (optional) IDiscovery services to resolve connection
+//
+//	Example:
+//		resolver := NewOtlpConnectionResolver()
+//		resolver.Configure(ctx, cconf.NewConfigParamsFromTuples(
+//			"connection.uri", "http://collector:4318",
+//		))
+//
+//		endpoint, err := resolver.Resolve(ctx)
+//		fmt.Println(endpoint.Url("traces")) // Result: http://collector:4318/v1/traces
+type OtlpConnectionResolver struct {
+	// The base connection resolver.
+	ConnectionResolver *cconn.ConnectionResolver
+}
+
+// NewOtlpConnectionResolver method are creates a new instance of the resolver.
+//
+//	Returns: *OtlpConnectionResolver
+func NewOtlpConnectionResolver() *OtlpConnectionResolver {
+	return &OtlpConnectionResolver{
+		ConnectionResolver: cconn.NewEmptyConnectionResolver(),
+	}
+}
+
+// Configure method are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config    configuration parameters to be set.
+func (c *OtlpConnectionResolver) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.ConnectionResolver.Configure(ctx, config)
+}
+
+// SetReferences method are sets references to dependent components.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- references 	references to locate the component dependencies.
+func (c *OtlpConnectionResolver) SetReferences(ctx context.Context, references cref.IReferences) {
+	c.ConnectionResolver.SetReferences(ctx, references)
+}
+
+// Resolve method are resolves the collector endpoint. When connection is not configured
+// the default local collector is used.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: the resolved endpoint or error.
+func (c *OtlpConnectionResolver) Resolve(ctx context.Context) (*OtlpEndpoint, error) {
+	traceId := cctx.GetTraceId(ctx)
+
+	connection, err := c.ConnectionResolver.Resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if connection == nil {
+		connection = cconn.NewEmptyConnectionParams()
+	}
+
+	endpoint := &OtlpEndpoint{
+		Protocol: connection.ProtocolWithDefault(DefaultOtlpProtocol),
+		Host:     connection.Host(),
+		Port:     connection.PortWithDefault(DefaultOtlpPort),
+	}
+
+	if uri := connection.Uri(); uri != "" {
+		address, err := url.Parse(uri)
+		if err != nil || address.Hostname() == "" {
+			return nil, cerr.NewConfigError(traceId, "WRONG_URI", "OTLP connection uri is invalid").
+				WithDetails("uri", uri)
+		}
+		endpoint.Protocol = address.Scheme
+		endpoint.Host = address.Hostname()
+		endpoint.Path = address.Path
+		endpoint.Port = DefaultOtlpPort
+		if address.Port() != "" {
+			endpoint.Port, _ = strconv.Atoi(address.Port())
+		} else if address.Scheme == "https" {
+			endpoint.Port = 443
+		}
+	}
+
+	if endpoint.Host == "" {
+		endpoint.Host = DefaultOtlpHost
+	}
+	if endpoint.Protocol != "http" && endpoint.Protocol != "https" {
+		return nil, cerr.NewConfigError(traceId, "WRONG_PROTOCOL", "Protocol is not supported by OTLP connection").
+			WithDetails("protocol", endpoint.Protocol)
+	}
+
+	return endpoint, nil
+}
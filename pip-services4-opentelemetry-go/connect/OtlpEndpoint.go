@@ -0,0 +1,51 @@
+package connect
+
+import (
+	"strconv"
+	"strings"
+)
+
+// OtlpEndpoint contains resolved address of OTLP/HTTP collector.
+// Signals are sent to the path prefix followed by "/v1/traces", "/v1/metrics" or "/v1/logs".
+type OtlpEndpoint struct {
+	// The connection protocol: http or https
+	Protocol string
+	// The collector host name or IP address
+	Host string
+	// The collector port
+	Port int
+	// The path prefix before signal paths
+	Path string
+}
+
+// Address method are gets the collector address as host:port.
+//
+//	Returns: the collector address.
+func (c *OtlpEndpoint) Address() string {
+	return c.Host + ":" + strconv.Itoa(c.Port)
+}
+
+// Insecure method are checks if the collector shall be called without TLS.
+//
+//	Returns: true for http protocol and false otherwise.
+func (c *OtlpEndpoint) Insecure() bool {
+	return c.Protocol != "https"
+}
+
+// SignalPath method are gets the URL path for the signal.
+//
+//	Parameters:
+//		- signal    a signal name: traces, metrics or logs
+//	Returns: the URL path.
+func (c *OtlpEndpoint) SignalPath(signal string) string {
+	return strings.TrimSuffix(c.Path, "/") + "/v1/" + signal
+}
+
+// Url method are gets the full URL for the signal.
+//
+//	Parameters:
+//		- signal    a signal name: traces, metrics or logs
+//	Returns: the signal URL.
+func (c *OtlpEndpoint) Url(signal string) string {
+	return c.Protocol + "://" + c.Address() + c.SignalPath(signal)
+}
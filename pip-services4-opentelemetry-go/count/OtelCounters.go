@@ -0,0 +1,471 @@
+package count
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	ccount "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/count"
+	"github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/connect"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// The instrumentation scope name used by the counters
+const MeterScopeName = "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go"
+
+// OtelCounters performance counters that record measurements as OpenTelemetry metrics
+// and export them to a collector via OTLP over HTTP.
+//
+// Counters are mapped to instruments as follows:
+//   - Interval (BeginTiming) - histogram in milliseconds
+//   - Statistics (Stats) - histogram
+//   - Increment - counter
+//   - LastValue (Last) - gauge
+//   - Timestamp - gauge with time in milliseconds since Unix epoch
+//
+// Labels of measurements are recorded as metric attributes.
+// Measurements taken before the counters are opened are ignored.
+//
+//	Configuration parameters:
+//		- source:                  (optional) service name reported to the collector
+//		- instance:                (optional) service instance id (default: host name)
+//		- connection:
+//			- discovery_key:         (optional) a key to retrieve the connection from IDiscovery
+//			- protocol:              (optional) connection protocol: http or https (default: http)
+//			- host:                  (optional) host name or IP address (default: localhost)
+//			- port:                  (optional) port number (default: 4318)
+//			- uri:                   (optional) resource URI or connection string with all parameters in it
+//		- options:
+//			- interval:              interval in milliseconds to export metrics (default: 10 seconds)
+//			- timeout:               export timeout in milliseconds (default: 10 seconds)
+//			- max_labels:            maximum number of labels per measurement (default: 10)
+//			- max_label_sets:        maximum number of distinct label sets per counter (default: 100)
+//
+//	References:
+//		- *:context-info:*:*:1.0     (optional) ContextInfo to detect the context id and specify service name
+//		- *:discovery:*:*:1.0        (optional) IDiscovery services to resolve connection
+//
+//	Example:
+//		counters := NewOtelCounters()
+//		counters.Configure(ctx, cconf.NewConfigParamsFromTuples(
+//			"source", "my-service",
+//			"connection.uri", "http://collector:4318",
+//		))
+//		err := counters.Open(ctx)
+//
+//		counters.IncrementOne(ctx, "mycomponent.mymethod.calls")
+//		timing := counters.BeginTiming(ctx, "mycomponent.mymethod.exec_time")
+//		...
+//		timing.EndTiming(ctx)
+//
+//		counters.Dump(ctx)
+type OtelCounters struct {
+	connectionResolver *connect.OtlpConnectionResolver
+	source             string
+	instance           string
+	interval           time.Duration
+	timeout            time.Duration
+
+	lock       sync.Mutex
+	provider   *sdkmetric.MeterProvider
+	meter      metric.Meter
+	limiter    *ccount.CounterLabelsLimiter
+	histograms map[string]metric.Float64Histogram
+	counters   map[string]metric.Int64Counter
+	gauges     map[string]map[string]*otelGaugeValue
+}
+
+type otelGaugeValue struct {
+	attributes attribute.Set
+	value      float64
+}
+
+// NewOtelCounters method are creates a new instance of the performance counters.
+//
+//	Returns: *OtelCounters
+func NewOtelCounters() *OtelCounters {
+	c := &OtelCounters{
+		connectionResolver: connect.NewOtlpConnectionResolver(),
+		interval:           10000 * time.Millisecond,
+		timeout:            10000 * time.Millisecond,
+		limiter:            ccount.NewCounterLabelsLimiter(),
+	}
+	c.instance, _ = os.Hostname()
+	c.reset()
+	return c
+}
+
+func (c *OtelCounters) reset() {
+	c.histograms = make(map[string]metric.Float64Histogram)
+	c.counters = make(map[string]metric.Int64Counter)
+	c.gauges = make(map[string]map[string]*otelGaugeValue)
+	c.limiter.ClearAll()
+}
+
+// Configure method are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config    configuration parameters to be set.
+func (c *OtelCounters) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.connectionResolver.Configure(ctx, config)
+
+	c.source = config.GetAsStringWithDefault("source", c.source)
+	c.instance = config.GetAsStringWithDefault("instance", c.instance)
+	c.interval = time.Duration(config.GetAsLongWithDefault("options.interval", c.interval.Milliseconds())) * time.Millisecond
+	c.timeout = time.Duration(config.GetAsLongWithDefault("options.timeout", c.timeout.Milliseconds())) * time.Millisecond
+	c.limiter.Configure(ctx, config)
+}
+
+// SetReferences method are sets references to dependent components.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- references 	references to locate the component dependencies.
+func (c *OtelCounters) SetReferences(ctx context.Context, references cref.IReferences) {
+	c.connectionResolver.SetReferences(ctx, references)
+
+	ref := references.GetOneOptional(cref.NewDescriptor("pip-services", "context-info", "default", "*", "1.0"))
+	if contextInfo, ok := ref.(*cctx.ContextInfo); ok && contextInfo != nil {
+		if c.source == "" {
+			c.source = contextInfo.Name
+		}
+		if c.instance == "" {
+			c.instance = contextInfo.ContextId
+		}
+	}
+}
+
+// IsOpen method are checks if the component is opened.
+//
+//	Returns: true if the component has been opened and false otherwise.
+func (c *OtelCounters) IsOpen() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.provider != nil
+}
+
+// Open method are creates the metric exporter and starts periodic export.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *OtelCounters) Open(ctx context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.provider != nil {
+		return nil
+	}
+
+	endpoint, err := c.connectionResolver.Resolve(ctx)
+	if err != nil {
+		return err
+	}
+
+	options := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(endpoint.Address()),
+		otlpmetrichttp.WithURLPath(endpoint.SignalPath("metrics")),
+		otlpmetrichttp.WithTimeout(c.timeout),
+	}
+	if endpoint.Insecure() {
+		options = append(options, otlpmetrichttp.WithInsecure())
+	}
+
+	exporter, err := otlpmetrichttp.New(ctx, options...)
+	if err != nil {
+		return err
+	}
+
+	c.provider = sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(c.interval))),
+		sdkmetric.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", c.source),
+			attribute.String("service.instance.id", c.instance),
+		)),
+	)
+	c.meter = c.provider.Meter(MeterScopeName)
+	c.reset()
+	return nil
+}
+
+// Dump method are exports collected metrics immediately.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *OtelCounters) Dump(ctx context.Context) error {
+	c.lock.Lock()
+	provider := c.provider
+	c.lock.Unlock()
+
+	if provider == nil {
+		return nil
+	}
+	return provider.ForceFlush(ctx)
+}
+
+// Close method are exports remaining metrics and closes the exporter.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *OtelCounters) Close(ctx context.Context) error {
+	c.lock.Lock()
+	provider := c.provider
+	c.provider = nil
+	c.meter = nil
+	c.lock.Unlock()
+
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
+
+func (c *OtelCounters) attributes(name string, labels map[string]string) (string, attribute.Set) {
+	labels = c.limiter.Limit(name, labels)
+	attributes := make([]attribute.KeyValue, 0, len(labels))
+	for key, value := range labels {
+		attributes = append(attributes, attribute.String(key, value))
+	}
+	return ccount.CounterLabelsToString(labels), attribute.NewSet(attributes...)
+}
+
+func (c *OtelCounters) recordHistogram(ctx context.Context, name string, labels map[string]string, unit string, value float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.meter == nil {
+		return
+	}
+
+	histogram, ok := c.histograms[name]
+	if !ok {
+		// The SDK returns usable instruments even for invalid names
+		histogram, _ = c.meter.Float64Histogram(name, metric.WithUnit(unit))
+		c.histograms[name] = histogram
+	}
+	_, attributes := c.attributes(name, labels)
+	histogram.Record(ctx, value, metric.WithAttributeSet(attributes))
+}
+
+func (c *OtelCounters) recordGauge(name string, labels map[string]string, value float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.meter == nil {
+		return
+	}
+
+	values, ok := c.gauges[name]
+	if !ok {
+		values = make(map[string]*otelGaugeValue)
+		c.gauges[name] = values
+
+		// Gauge values are observed by the reader on every export
+		_, _ = c.meter.Float64ObservableGauge(name,
+			metric.WithFloat64Callback(func(_ context.Context, observer metric.Float64Observer) error {
+				c.lock.Lock()
+				defer c.lock.Unlock()
+
+				for _, gauge := range values {
+					observer.Observe(gauge.value, metric.WithAttributeSet(gauge.attributes))
+				}
+				return nil
+			}))
+	}
+
+	key, attributes := c.attributes(name, labels)
+	values[key] = &otelGaugeValue{attributes: attributes, value: value}
+}
+
+// BeginTiming method are begins measurement of execution time interval.
+// It returns CounterTiming object which has to be called at
+// CounterTiming.EndTiming to end the measurement and update the counter.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Interval type.
+//	Returns: a CounterTiming callback object to end timing.
+func (c *OtelCounters) BeginTiming(ctx context.Context, name string) *ccount.CounterTiming {
+	return ccount.NewCounterTiming(name, c)
+}
+
+// EndTiming method are ends measurement of execution elapsed time and records it in a histogram.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name
+//		- elapsed   execution elapsed time in milliseconds to update the counter.
+func (c *OtelCounters) EndTiming(ctx context.Context, name string, elapsed float64) {
+	c.recordHistogram(ctx, name, nil, "ms", elapsed)
+}
+
+// Stats method are calculates min/average/max statistics based on the current and previous values.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Statistics type
+//		- value     a value to update statistics
+func (c *OtelCounters) Stats(ctx context.Context, name string, value float64) {
+	c.recordHistogram(ctx, name, nil, "", value)
+}
+
+// Last method are records the last calculated measurement value.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Last type.
+//		- value     a last value to record.
+func (c *OtelCounters) Last(ctx context.Context, name string, value float64) {
+	c.recordGauge(name, nil, value)
+}
+
+// TimestampNow method are records the current time as a timestamp.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Timestamp type.
+func (c *OtelCounters) TimestampNow(ctx context.Context, name string) {
+	c.Timestamp(ctx, name, time.Now())
+}
+
+// Timestamp method are records the given timestamp.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Timestamp type.
+//		- value     a timestamp to record.
+func (c *OtelCounters) Timestamp(ctx context.Context, name string, value time.Time) {
+	c.recordGauge(name, nil, float64(value.UnixMilli()))
+}
+
+// IncrementOne method are increments counter by 1.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Increment type.
+func (c *OtelCounters) IncrementOne(ctx context.Context, name string) {
+	c.Increment(ctx, name, 1)
+}
+
+// Increment method are increments counter by given value.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Increment type.
+//		- value     a value to add to the counter.
+func (c *OtelCounters) Increment(ctx context.Context, name string, value int64) {
+	c.IncrementWithLabels(ctx, name, nil, value)
+}
+
+// BeginTimingWithLabels method are begins measurement of execution time interval for a counter with labels.
+// It returns CounterTiming object which has to be called at
+// CounterTiming.EndTiming to end the measurement and update the counter.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Interval type.
+//		- labels    counter labels recorded as metric attributes.
+//	Returns: a CounterTiming callback object to end timing.
+func (c *OtelCounters) BeginTimingWithLabels(ctx context.Context, name string, labels map[string]string) *ccount.CounterTiming {
+	return ccount.NewCounterTimingWithLabels(name, labels, c)
+}
+
+// EndTimingWithLabels method are ends measurement of execution elapsed time and records it in a histogram with labels.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name
+//		- labels    counter labels recorded as metric attributes.
+//		- elapsed   execution elapsed time in milliseconds to update the counter.
+func (c *OtelCounters) EndTimingWithLabels(ctx context.Context, name string, labels map[string]string, elapsed float64) {
+	c.recordHistogram(ctx, name, labels, "ms", elapsed)
+}
+
+// StatsWithLabels method are records a value into a histogram with labels.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Statistics type
+//		- labels    counter labels recorded as metric attributes.
+//		- value     a value to update statistics
+func (c *OtelCounters) StatsWithLabels(ctx context.Context, name string, labels map[string]string, value float64) {
+	c.recordHistogram(ctx, name, labels, "", value)
+}
+
+// LastWithLabels method are records the last calculated measurement value with labels.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Last type.
+//		- labels    counter labels recorded as metric attributes.
+//		- value     a last value to record.
+func (c *OtelCounters) LastWithLabels(ctx context.Context, name string, labels map[string]string, value float64) {
+	c.recordGauge(name, labels, value)
+}
+
+// TimestampNowWithLabels method are records the current time as a timestamp with labels.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Timestamp type.
+//		- labels    counter labels recorded as metric attributes.
+func (c *OtelCounters) TimestampNowWithLabels(ctx context.Context, name string, labels map[string]string) {
+	c.TimestampWithLabels(ctx, name, labels, time.Now())
+}
+
+// TimestampWithLabels method are records the given timestamp with labels.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Timestamp type.
+//		- labels    counter labels recorded as metric attributes.
+//		- value     a timestamp to record.
+func (c *OtelCounters) TimestampWithLabels(ctx context.Context, name string, labels map[string]string, value time.Time) {
+	c.recordGauge(name, labels, float64(value.UnixMilli()))
+}
+
+// IncrementOneWithLabels method are increments counter with labels by 1.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Increment type.
+//		- labels    counter labels recorded as metric attributes.
+func (c *OtelCounters) IncrementOneWithLabels(ctx context.Context, name string, labels map[string]string) {
+	c.IncrementWithLabels(ctx, name, labels, 1)
+}
+
+// IncrementWithLabels method are increments counter with labels by given value.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- name      a counter name of Increment type.
+//		- labels    counter labels recorded as metric attributes.
+//		- value     a value to add to the counter.
+func (c *OtelCounters) IncrementWithLabels(ctx context.Context, name string, labels map[string]string, value int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.meter == nil {
+		return
+	}
+
+	counter, ok := c.counters[name]
+	if !ok {
+		counter, _ = c.meter.Int64Counter(name)
+		c.counters[name] = counter
+	}
+	_, attributes := c.attributes(name, labels)
+	counter.Add(ctx, value, metric.WithAttributeSet(attributes))
+}
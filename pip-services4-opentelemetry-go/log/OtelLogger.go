@@ -0,0 +1,378 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+	"github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/connect"
+	otrace "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// The instrumentation scope name used by the logger
+const LoggerScopeName = "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go"
+
+// OtelLogger logger that exports log messages as OpenTelemetry log records
+// to a collector via OTLP over HTTP.
+//
+// Every record gets trace and span ids of the span kept in context.Context,
+// so log messages can be correlated with traces recorded by OtelTracer.
+// Messages are cached and sent in batches by timer, when the cache is full and on close.
+//
+//	Configuration parameters:
+//		- level:                   maximum log level to capture
+//		- source:                  (optional) service name reported to the collector
+//		- instance:                (optional) service instance id (default: host name)
+//		- connection:
+//			- discovery_key:         (optional) a key to retrieve the connection from IDiscovery
+//			- protocol:              (optional) connection protocol: http or https (default: http)
+//			- host:                  (optional) host name or IP address (default: localhost)
+//			- port:                  (optional) port number (default: 4318)
+//			- uri:                   (optional) resource URI or connection string with all parameters in it
+//		- options:
+//			- interval:              interval in milliseconds to send log messages (default: 10 seconds)
+//			- max_cache_size:        maximum number of messages stored in the cache (default: 100)
+//			- timeout:               export timeout in milliseconds (default: 10 seconds)
+//
+//	References:
+//		- *:context-info:*:*:1.0     (optional) ContextInfo to detect the context id and specify service name
+//		- *:discovery:*:*:1.0        (optional) IDiscovery services to resolve connection
+//
+//	Example:
+//		logger := NewOtelLogger()
+//		logger.Configure(ctx, cconf.NewConfigParamsFromTuples(
+//			"source", "my-service",
+//			"connection.uri", "http://collector:4318",
+//		))
+//		err := logger.Open(ctx)
+//
+//		logger.Error(ctx, err, "Error occured: %s", err.Error())
+//		logger.Debug(ctx, "Everything is OK.")
+type OtelLogger struct {
+	*clog.Logger
+	connectionResolver *connect.OtlpConnectionResolver
+	instance           string
+	interval           int
+	maxCacheSize       int
+	timeout            time.Duration
+
+	lock     sync.Mutex
+	cache    []*logspb.LogRecord
+	endpoint *connect.OtlpEndpoint
+	client   *http.Client
+	timer    chan bool
+}
+
+// NewOtelLogger method are creates a new instance of the logger.
+//
+//	Returns: *OtelLogger
+func NewOtelLogger() *OtelLogger {
+	c := &OtelLogger{
+		connectionResolver: connect.NewOtlpConnectionResolver(),
+		interval:           clog.DefaultInterval,
+		maxCacheSize:       clog.DefaultMaxCacheSize,
+		timeout:            10000 * time.Millisecond,
+		cache:              make([]*logspb.LogRecord, 0),
+	}
+	c.Logger = clog.InheritLogger(c)
+	c.instance, _ = os.Hostname()
+	return c
+}
+
+// Configure method are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config    configuration parameters to be set.
+func (c *OtelLogger) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.Logger.Configure(ctx, config)
+	c.connectionResolver.Configure(ctx, config)
+
+	c.instance = config.GetAsStringWithDefault("instance", c.instance)
+	c.interval = config.GetAsIntegerWithDefault(clog.ConfigParameterOptionsInterval, c.interval)
+	c.maxCacheSize = config.GetAsIntegerWithDefault(clog.ConfigParameterOptionsMaxCacheSize, c.maxCacheSize)
+	c.timeout = time.Duration(config.GetAsLongWithDefault("options.timeout", c.timeout.Milliseconds())) * time.Millisecond
+}
+
+// SetReferences method are sets references to dependent components.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- references 	references to locate the component dependencies.
+func (c *OtelLogger) SetReferences(ctx context.Context, references cref.IReferences) {
+	c.Logger.SetReferences(ctx, references)
+	c.connectionResolver.SetReferences(ctx, references)
+
+	ref := references.GetOneOptional(cref.NewDescriptor("pip-services", "context-info", "default", "*", "1.0"))
+	if contextInfo, ok := ref.(*cctx.ContextInfo); ok && contextInfo != nil {
+		if c.Source() == "" {
+			c.SetSource(contextInfo.Name)
+		}
+		if c.instance == "" {
+			c.instance = contextInfo.ContextId
+		}
+	}
+}
+
+// IsOpen method are checks if the component is opened.
+//
+//	Returns: true if the component has been opened and false otherwise.
+func (c *OtelLogger) IsOpen() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.endpoint != nil
+}
+
+// Open method are resolves the collector endpoint and starts sending log messages.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *OtelLogger) Open(ctx context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.endpoint != nil {
+		return nil
+	}
+
+	endpoint, err := c.connectionResolver.Resolve(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.endpoint = endpoint
+	c.client = &http.Client{Timeout: c.timeout}
+	c.timer = c.setInterval(func() { _ = c.Dump(ctx) }, c.interval)
+	return nil
+}
+
+// Close method are sends remaining log messages and closes the component.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *OtelLogger) Close(ctx context.Context) error {
+	err := c.Dump(ctx)
+
+	c.lock.Lock()
+	timer := c.timer
+	c.timer = nil
+	c.endpoint = nil
+	c.client = nil
+	c.cache = make([]*logspb.LogRecord, 0)
+	c.lock.Unlock()
+
+	// The timer can be dumping messages, so it is stopped without waiting
+	if timer != nil {
+		close(timer)
+	}
+	return err
+}
+
+func (c *OtelLogger) severity(level clog.LevelType) logspb.SeverityNumber {
+	switch level {
+	case clog.LevelFatal:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL
+	case clog.LevelError:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	case clog.LevelWarn:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case clog.LevelInfo:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	case clog.LevelDebug:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG
+	case clog.LevelTrace:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_TRACE
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED
+	}
+}
+
+func (c *OtelLogger) stringAttribute(key string, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func (c *OtelLogger) convertMessage(ctx context.Context, level clog.LevelType, err error, message string) *logspb.LogRecord {
+	now := uint64(time.Now().UnixNano())
+	record := &logspb.LogRecord{
+		TimeUnixNano:         now,
+		ObservedTimeUnixNano: now,
+		SeverityNumber:       c.severity(level),
+		SeverityText:         clog.LevelConverter.ToString(level),
+		Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: message}},
+	}
+
+	if traceId := cctx.GetTraceId(ctx); traceId != "" {
+		record.Attributes = append(record.Attributes, c.stringAttribute("pip.trace_id", traceId))
+	}
+
+	if err != nil {
+		description := cerr.NewErrorDescription(err)
+		record.Attributes = append(record.Attributes,
+			c.stringAttribute("exception.type", description.Type),
+			c.stringAttribute("exception.message", description.Message),
+		)
+		if description.StackTrace != "" {
+			record.Attributes = append(record.Attributes,
+				c.stringAttribute("exception.stacktrace", description.StackTrace))
+		}
+	}
+
+	if spanContext := otrace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		traceId := spanContext.TraceID()
+		spanId := spanContext.SpanID()
+		record.TraceId = traceId[:]
+		record.SpanId = spanId[:]
+		record.Flags = uint32(spanContext.TraceFlags())
+	}
+
+	return record
+}
+
+// Write method are writes a log message to the cache.
+// The cache is sent to the collector when it reaches the maximum size.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- level             a log level.
+//		- err               an error object associated with this message.
+//		- message           a human-readable message to log.
+func (c *OtelLogger) Write(ctx context.Context, level clog.LevelType, err error, message string) {
+	if c.Level() < level {
+		return
+	}
+
+	record := c.convertMessage(ctx, level, err, message)
+
+	c.lock.Lock()
+	c.cache = append(c.cache, record)
+	full := len(c.cache) >= c.maxCacheSize
+	c.lock.Unlock()
+
+	if full {
+		_ = c.Dump(ctx)
+	}
+}
+
+// Dump method are sends cached log messages to the collector.
+// Messages that failed to send are kept in the cache up to its maximum size.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *OtelLogger) Dump(ctx context.Context) error {
+	c.lock.Lock()
+	endpoint := c.endpoint
+	client := c.client
+	records := c.cache
+	if endpoint == nil || len(records) == 0 {
+		c.lock.Unlock()
+		return nil
+	}
+	c.cache = make([]*logspb.LogRecord, 0)
+	c.lock.Unlock()
+
+	err := c.send(ctx, endpoint, client, records)
+	if err != nil {
+		c.lock.Lock()
+		c.cache = append(records, c.cache...)
+		if len(c.cache) > c.maxCacheSize {
+			c.cache = c.cache[len(c.cache)-c.maxCacheSize:]
+		}
+		c.lock.Unlock()
+	}
+	return err
+}
+
+func (c *OtelLogger) send(ctx context.Context, endpoint *connect.OtlpEndpoint,
+	client *http.Client, records []*logspb.LogRecord) error {
+
+	traceId := cctx.GetTraceId(ctx)
+
+	request := &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{
+			{
+				Resource: &resourcepb.Resource{
+					Attributes: []*commonpb.KeyValue{
+						c.stringAttribute("service.name", c.Source()),
+						c.stringAttribute("service.instance.id", c.instance),
+					},
+				},
+				ScopeLogs: []*logspb.ScopeLogs{
+					{
+						Scope:      &commonpb.InstrumentationScope{Name: LoggerScopeName},
+						LogRecords: records,
+					},
+				},
+			},
+		},
+	}
+
+	body, err := proto.Marshal(request)
+	if err != nil {
+		return cerr.NewInternalError(traceId, "SERIALIZE_FAILED", "Failed to serialize log records").
+			WithCause(err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+		endpoint.Url("logs"), bytes.NewReader(body))
+	if err != nil {
+		return cerr.NewConnectionError(traceId, "CANNOT_CONNECT", "Failed to create request to OTLP collector").
+			WithCause(err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/x-protobuf")
+
+	response, err := client.Do(httpRequest)
+	if err != nil {
+		return cerr.NewConnectionError(traceId, "CANNOT_CONNECT", "Failed to send log records to OTLP collector").
+			WithCause(err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return cerr.NewInvocationError(traceId, "EXPORT_FAILED",
+			"OTLP collector rejected log records with status "+strconv.Itoa(response.StatusCode)).
+			WithDetails("status", response.StatusCode)
+	}
+	return nil
+}
+
+func (c *OtelLogger) setInterval(someFunc func(), milliseconds int) chan bool {
+	interval := time.Duration(milliseconds) * time.Millisecond
+	ticker := time.NewTicker(interval)
+	clear := make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				someFunc()
+			case <-clear:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return clear
+}
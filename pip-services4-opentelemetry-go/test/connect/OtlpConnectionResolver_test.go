@@ -0,0 +1,56 @@
+package connect_test
+
+import (
+	"context"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	"github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOtlpConnectionResolverDefaults(t *testing.T) {
+	resolver := connect.NewOtlpConnectionResolver()
+	resolver.Configure(context.Background(), cconf.NewEmptyConfigParams())
+
+	endpoint, err := resolver.Resolve(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost:4318", endpoint.Address())
+	assert.True(t, endpoint.Insecure())
+	assert.Equal(t, "http://localhost:4318/v1/traces", endpoint.Url("traces"))
+}
+
+func TestOtlpConnectionResolverParams(t *testing.T) {
+	resolver := connect.NewOtlpConnectionResolver()
+	resolver.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"connection.protocol", "https",
+		"connection.host", "collector",
+		"connection.port", 4000,
+	))
+
+	endpoint, err := resolver.Resolve(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "collector:4000", endpoint.Address())
+	assert.False(t, endpoint.Insecure())
+	assert.Equal(t, "/v1/metrics", endpoint.SignalPath("metrics"))
+}
+
+func TestOtlpConnectionResolverUri(t *testing.T) {
+	resolver := connect.NewOtlpConnectionResolver()
+	resolver.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"connection.uri", "https://otlp.example.com/otlp/",
+	))
+
+	endpoint, err := resolver.Resolve(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "otlp.example.com:443", endpoint.Address())
+	assert.Equal(t, "https://otlp.example.com:443/otlp/v1/logs", endpoint.Url("logs"))
+
+	resolver = connect.NewOtlpConnectionResolver()
+	resolver.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"connection.protocol", "tcp",
+	))
+
+	_, err = resolver.Resolve(context.Background())
+	assert.NotNil(t, err)
+}
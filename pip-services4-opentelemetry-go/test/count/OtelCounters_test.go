@@ -0,0 +1,104 @@
+package count_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	otelcount "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/count"
+	otelfixture "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/test/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOtelCounters(t *testing.T) {
+	ctx := context.Background()
+
+	collector := otelfixture.NewOtlpCollectorFixture()
+	defer collector.Close()
+
+	counters := otelcount.NewOtelCounters()
+	counters.Configure(ctx, cconf.NewConfigParamsFromTuples(
+		"source", "test",
+		"connection.uri", collector.Uri(),
+	))
+
+	// Measurements are ignored until the counters are opened
+	counters.IncrementOne(ctx, "test.ignored")
+
+	err := counters.Open(ctx)
+	assert.Nil(t, err)
+	defer counters.Close(ctx)
+
+	t.Run("Simple Counters", func(t *testing.T) {
+		counters.IncrementOne(ctx, "test.increment")
+		counters.Increment(ctx, "test.increment", 2)
+		counters.Last(ctx, "test.last", 123)
+		counters.Last(ctx, "test.last", 123456)
+		counters.Stats(ctx, "test.stats", 10)
+		counters.Stats(ctx, "test.stats", 20)
+
+		assert.Nil(t, counters.Dump(ctx))
+
+		assert.Nil(t, collector.FindMetric("test.ignored"))
+
+		metric := collector.FindMetric("test.increment")
+		assert.NotNil(t, metric)
+		assert.Equal(t, int64(3), metric.GetSum().DataPoints[0].GetAsInt())
+		assert.True(t, metric.GetSum().IsMonotonic)
+
+		metric = collector.FindMetric("test.last")
+		assert.NotNil(t, metric)
+		assert.Equal(t, float64(123456), metric.GetGauge().DataPoints[0].GetAsDouble())
+
+		metric = collector.FindMetric("test.stats")
+		assert.NotNil(t, metric)
+		assert.Equal(t, uint64(2), metric.GetHistogram().DataPoints[0].Count)
+		assert.Equal(t, float64(30), metric.GetHistogram().DataPoints[0].GetSum())
+		assert.Equal(t, float64(10), metric.GetHistogram().DataPoints[0].GetMin())
+		assert.Equal(t, float64(20), metric.GetHistogram().DataPoints[0].GetMax())
+	})
+
+	t.Run("Measure Elapsed Time", func(t *testing.T) {
+		timing := counters.BeginTiming(ctx, "test.elapsed")
+		time.Sleep(50 * time.Millisecond)
+		timing.EndTiming(ctx)
+
+		now := time.Now()
+		counters.Timestamp(ctx, "test.timestamp", now)
+
+		assert.Nil(t, counters.Dump(ctx))
+
+		metric := collector.FindMetric("test.elapsed")
+		assert.NotNil(t, metric)
+		assert.Equal(t, "ms", metric.Unit)
+		assert.Equal(t, uint64(1), metric.GetHistogram().DataPoints[0].Count)
+		assert.True(t, metric.GetHistogram().DataPoints[0].GetSum() >= 50)
+
+		metric = collector.FindMetric("test.timestamp")
+		assert.NotNil(t, metric)
+		assert.Equal(t, float64(now.UnixMilli()), metric.GetGauge().DataPoints[0].GetAsDouble())
+	})
+
+	t.Run("Counters With Labels", func(t *testing.T) {
+		counters.IncrementOneWithLabels(ctx, "test.labeled", map[string]string{"method": "get"})
+		counters.IncrementWithLabels(ctx, "test.labeled", map[string]string{"method": "post"}, 2)
+		counters.LastWithLabels(ctx, "test.labeled_last", map[string]string{"queue": "a"}, 1)
+		counters.LastWithLabels(ctx, "test.labeled_last", map[string]string{"queue": "b"}, 2)
+
+		assert.Nil(t, counters.Dump(ctx))
+
+		metric := collector.FindMetric("test.labeled")
+		assert.NotNil(t, metric)
+		values := map[string]int64{}
+		for _, point := range metric.GetSum().DataPoints {
+			assert.Len(t, point.Attributes, 1)
+			values[point.Attributes[0].Value.GetStringValue()] = point.GetAsInt()
+		}
+		assert.Equal(t, map[string]int64{"get": 1, "post": 2}, values)
+
+		metric = collector.FindMetric("test.labeled_last")
+		assert.NotNil(t, metric)
+		assert.Len(t, metric.GetGauge().DataPoints, 2)
+	})
+}
@@ -0,0 +1,157 @@
+package fixtures
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// OtlpCollectorFixture in-process stand-in for OpenTelemetry collector
+// that accepts OTLP/HTTP requests in protobuf and keeps received data.
+type OtlpCollectorFixture struct {
+	server  *httptest.Server
+	lock    sync.Mutex
+	spans   []*tracepb.Span
+	metrics []*metricspb.Metric
+	records []*logspb.LogRecord
+}
+
+func NewOtlpCollectorFixture() *OtlpCollectorFixture {
+	c := &OtlpCollectorFixture{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/traces", func(w http.ResponseWriter, r *http.Request) {
+		request := &coltracepb.ExportTraceServiceRequest{}
+		if !c.readRequest(w, r, request) {
+			return
+		}
+		c.lock.Lock()
+		for _, resourceSpans := range request.ResourceSpans {
+			for _, scopeSpans := range resourceSpans.ScopeSpans {
+				c.spans = append(c.spans, scopeSpans.Spans...)
+			}
+		}
+		c.lock.Unlock()
+		c.writeResponse(w, &coltracepb.ExportTraceServiceResponse{})
+	})
+	mux.HandleFunc("/v1/metrics", func(w http.ResponseWriter, r *http.Request) {
+		request := &colmetricspb.ExportMetricsServiceRequest{}
+		if !c.readRequest(w, r, request) {
+			return
+		}
+		c.lock.Lock()
+		for _, resourceMetrics := range request.ResourceMetrics {
+			for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+				c.metrics = append(c.metrics, scopeMetrics.Metrics...)
+			}
+		}
+		c.lock.Unlock()
+		c.writeResponse(w, &colmetricspb.ExportMetricsServiceResponse{})
+	})
+	mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		request := &collogspb.ExportLogsServiceRequest{}
+		if !c.readRequest(w, r, request) {
+			return
+		}
+		c.lock.Lock()
+		for _, resourceLogs := range request.ResourceLogs {
+			for _, scopeLogs := range resourceLogs.ScopeLogs {
+				c.records = append(c.records, scopeLogs.LogRecords...)
+			}
+		}
+		c.lock.Unlock()
+		c.writeResponse(w, &collogspb.ExportLogsServiceResponse{})
+	})
+
+	c.server = httptest.NewServer(mux)
+	return c
+}
+
+func (c *OtlpCollectorFixture) readRequest(w http.ResponseWriter, r *http.Request, request proto.Message) bool {
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return false
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	body, err := io.ReadAll(reader)
+	if err == nil {
+		err = proto.Unmarshal(body, request)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func (c *OtlpCollectorFixture) writeResponse(w http.ResponseWriter, response proto.Message) {
+	body, _ := proto.Marshal(response)
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+func (c *OtlpCollectorFixture) Uri() string {
+	return c.server.URL
+}
+
+func (c *OtlpCollectorFixture) Close() {
+	c.server.Close()
+}
+
+func (c *OtlpCollectorFixture) Spans() []*tracepb.Span {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]*tracepb.Span{}, c.spans...)
+}
+
+func (c *OtlpCollectorFixture) FindSpan(name string) *tracepb.Span {
+	for _, span := range c.Spans() {
+		if span.Name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func (c *OtlpCollectorFixture) Metrics() []*metricspb.Metric {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]*metricspb.Metric{}, c.metrics...)
+}
+
+// FindMetric gets the last received metric with the name
+func (c *OtlpCollectorFixture) FindMetric(name string) *metricspb.Metric {
+	var result *metricspb.Metric
+	for _, metric := range c.Metrics() {
+		if metric.Name == name {
+			result = metric
+		}
+	}
+	return result
+}
+
+func (c *OtlpCollectorFixture) LogRecords() []*logspb.LogRecord {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]*logspb.LogRecord{}, c.records...)
+}
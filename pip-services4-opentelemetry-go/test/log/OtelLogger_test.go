@@ -0,0 +1,81 @@
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	otellog "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/log"
+	otelfixture "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/test/fixtures"
+	oteltrace "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/trace"
+	"github.com/stretchr/testify/assert"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+func TestOtelLogger(t *testing.T) {
+	ctx := cctx.NewContextWithTraceId(context.Background(), "123")
+
+	collector := otelfixture.NewOtlpCollectorFixture()
+	defer collector.Close()
+
+	config := cconf.NewConfigParamsFromTuples(
+		"level", "debug",
+		"source", "test",
+		"connection.uri", collector.Uri(),
+		"options.max_cache_size", 3,
+	)
+
+	logger := otellog.NewOtelLogger()
+	logger.Configure(ctx, config)
+	assert.Nil(t, logger.Open(ctx))
+
+	tracer := oteltrace.NewOtelTracer()
+	tracer.Configure(ctx, config)
+	assert.Nil(t, tracer.Open(ctx))
+	defer tracer.Close(ctx)
+
+	spanCtx, timing := tracer.StartTrace(ctx, "mycomponent", "mymethod")
+	logger.Info(spanCtx, "Message with %s", "span")
+	logger.Trace(spanCtx, "Skipped by level")
+	logger.Debug(ctx, "Message without span")
+	timing.EndTrace()
+
+	// Cache is not full yet
+	assert.Len(t, collector.LogRecords(), 0)
+
+	logger.Error(ctx, cerr.NewUnknownError("123", "TEST", "Test error"), "Failed")
+	assert.Len(t, collector.LogRecords(), 3)
+
+	logger.Warn(ctx, "Sent on close")
+	assert.Nil(t, logger.Close(ctx))
+
+	records := collector.LogRecords()
+	assert.Len(t, records, 4)
+
+	assert.Equal(t, "Message with span", records[0].Body.GetStringValue())
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_INFO, records[0].SeverityNumber)
+	assert.Len(t, records[0].TraceId, 16)
+	assert.Len(t, records[0].SpanId, 8)
+
+	assert.Nil(t, tracer.Dump(ctx))
+	span := collector.FindSpan("mycomponent.mymethod")
+	assert.NotNil(t, span)
+	assert.Equal(t, span.TraceId, records[0].TraceId)
+	assert.Equal(t, span.SpanId, records[0].SpanId)
+
+	assert.Equal(t, "Message without span", records[1].Body.GetStringValue())
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG, records[1].SeverityNumber)
+	assert.Empty(t, records[1].TraceId)
+
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_ERROR, records[2].SeverityNumber)
+	attributes := map[string]string{}
+	for _, attribute := range records[2].Attributes {
+		attributes[attribute.Key] = attribute.Value.GetStringValue()
+	}
+	assert.Equal(t, "123", attributes["pip.trace_id"])
+	assert.Equal(t, "Test error", attributes["exception.message"])
+
+	assert.Equal(t, "Sent on close", records[3].Body.GetStringValue())
+}
@@ -0,0 +1,131 @@
+package trace_test
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
+	otelfixture "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/test/fixtures"
+	oteltrace "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/trace"
+	"github.com/stretchr/testify/assert"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func spanAttribute(span *tracepb.Span, key string) string {
+	for _, attribute := range span.Attributes {
+		if attribute.Key == key {
+			return attribute.Value.GetStringValue()
+		}
+	}
+	return ""
+}
+
+func TestOtelTracer(t *testing.T) {
+	ctx := cctx.NewContextWithTraceId(context.Background(), "123")
+
+	collector := otelfixture.NewOtlpCollectorFixture()
+	defer collector.Close()
+
+	tracer := oteltrace.NewOtelTracer()
+	tracer.Configure(ctx, cconf.NewConfigParamsFromTuples(
+		"source", "test",
+		"connection.uri", collector.Uri(),
+	))
+
+	err := tracer.Open(ctx)
+	assert.Nil(t, err)
+	defer tracer.Close(ctx)
+
+	t.Run("Parent Propagation", func(t *testing.T) {
+		parentCtx, parentTiming := tracer.StartTrace(ctx, "mycomponent", "parent")
+		childTiming := tracer.BeginTrace(parentCtx, "mycomponent", "child")
+		tracer.Trace(parentCtx, "mycomponent", "retro", 100)
+		childTiming.EndTrace()
+		parentTiming.EndTrace()
+
+		assert.Nil(t, tracer.Dump(ctx))
+
+		parent := collector.FindSpan("mycomponent.parent")
+		child := collector.FindSpan("mycomponent.child")
+		retro := collector.FindSpan("mycomponent.retro")
+		assert.NotNil(t, parent)
+		assert.NotNil(t, child)
+		assert.NotNil(t, retro)
+
+		assert.Equal(t, parent.TraceId, child.TraceId)
+		assert.Equal(t, parent.SpanId, child.ParentSpanId)
+		assert.Equal(t, parent.SpanId, retro.ParentSpanId)
+		assert.Empty(t, parent.ParentSpanId)
+
+		assert.Equal(t, "mycomponent", spanAttribute(parent, oteltrace.ComponentAttribute))
+		assert.Equal(t, "parent", spanAttribute(parent, oteltrace.OperationAttribute))
+		assert.Equal(t, "123", spanAttribute(parent, oteltrace.TraceIdAttribute))
+
+		// Recorded traces start the given duration ago
+		duration := retro.EndTimeUnixNano - retro.StartTimeUnixNano
+		assert.True(t, duration >= uint64(100*1000000))
+	})
+
+	t.Run("Remote Parent Propagation", func(t *testing.T) {
+		remoteCtx := ctrace.NewContextWithSpanContext(ctx, ctrace.SpanContext{
+			TraceId:    "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanId:     "00f067aa0ba902b7",
+			TraceFlags: ctrace.TraceFlagsSampled,
+			Remote:     true,
+		})
+
+		spanCtx, timing := tracer.StartTrace(remoteCtx, "mycomponent", "remote")
+		// The new span is propagated to other services
+		spanContext, ok := ctrace.GetSpanContext(spanCtx)
+		assert.True(t, ok)
+		assert.False(t, spanContext.Remote)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceId)
+		assert.NotEqual(t, "00f067aa0ba902b7", spanContext.SpanId)
+		timing.EndTrace()
+
+		assert.Nil(t, tracer.Dump(ctx))
+
+		span := collector.FindSpan("mycomponent.remote")
+		assert.NotNil(t, span)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", hex.EncodeToString(span.TraceId))
+		assert.Equal(t, "00f067aa0ba902b7", hex.EncodeToString(span.ParentSpanId))
+		assert.Equal(t, spanContext.SpanId, hex.EncodeToString(span.SpanId))
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		timing := tracer.BeginTrace(ctx, "mycomponent", "failure")
+		timing.EndFailure(cerr.NewUnknownError("123", "TEST", "Test error"))
+
+		tracer.Failure(ctx, "mycomponent", "", cerr.NewUnknownError("123", "TEST", "Test error"), 10)
+
+		assert.Nil(t, tracer.Dump(ctx))
+
+		span := collector.FindSpan("mycomponent.failure")
+		assert.NotNil(t, span)
+		assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, span.Status.Code)
+		assert.Len(t, span.Events, 1)
+		assert.Equal(t, "exception", span.Events[0].Name)
+
+		span = collector.FindSpan("mycomponent")
+		assert.NotNil(t, span)
+		assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, span.Status.Code)
+	})
+}
+
+func TestOtelTracerNotOpened(t *testing.T) {
+	ctx := context.Background()
+
+	tracer := oteltrace.NewOtelTracer()
+
+	spanCtx, timing := tracer.StartTrace(ctx, "mycomponent", "mymethod")
+	assert.Equal(t, ctx, spanCtx)
+	timing.EndTrace()
+	tracer.Trace(ctx, "mycomponent", "mymethod", 10)
+
+	assert.Nil(t, tracer.Dump(ctx))
+	assert.Nil(t, tracer.Close(ctx))
+}
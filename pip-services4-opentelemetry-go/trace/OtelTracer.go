@@ -0,0 +1,351 @@
+package trace
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
+	"github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go/connect"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// The instrumentation scope name used by the tracer
+	TracerScopeName = "github.com/pip-services4/pip-services4-go/pip-services4-opentelemetry-go"
+
+	ComponentAttribute = "component"
+	OperationAttribute = "operation"
+	TraceIdAttribute   = "pip.trace_id"
+)
+
+// OtelTracer tracer that records operations as OpenTelemetry spans
+// and exports them to a collector via OTLP over HTTP.
+//
+// Unlike other tracers spans created by OtelTracer have parent/child relationships:
+// the parent is taken from the span kept in context.Context. Use StartTrace to get
+// a context with the new span and pass it to nested calls.
+// Traces recorded by Trace and Failure methods without starting them are converted
+// into spans that started the given duration ago.
+//
+//	Configuration parameters:
+//		- source:                  (optional) service name reported to the collector
+//		- instance:                (optional) service instance id (default: host name)
+//		- connection:
+//			- discovery_key:         (optional) a key to retrieve the connection from IDiscovery
+//			- protocol:              (optional) connection protocol: http or https (default: http)
+//			- host:                  (optional) host name or IP address (default: localhost)
+//			- port:                  (optional) port number (default: 4318)
+//			- uri:                   (optional) resource URI or connection string with all parameters in it
+//		- options:
+//			- interval:              interval in milliseconds to export batches of spans (default: 5 seconds)
+//			- timeout:               export timeout in milliseconds (default: 10 seconds)
+//
+//	References:
+//		- *:context-info:*:*:1.0     (optional) ContextInfo to detect the context id and specify service name
+//		- *:discovery:*:*:1.0        (optional) IDiscovery services to resolve connection
+//
+//	Example:
+//		tracer := NewOtelTracer()
+//		tracer.Configure(ctx, cconf.NewConfigParamsFromTuples(
+//			"source", "my-service",
+//			"connection.uri", "http://collector:4318",
+//		))
+//		err := tracer.Open(ctx)
+//
+//		ctx, timing := tracer.StartTrace(ctx, "mycomponent", "mymethod")
+//		err = doSomething(ctx)
+//		if err != nil {
+//			timing.EndFailure(err)
+//		} else {
+//			timing.EndTrace()
+//		}
+type OtelTracer struct {
+	connectionResolver *connect.OtlpConnectionResolver
+	source             string
+	instance           string
+	interval           time.Duration
+	timeout            time.Duration
+
+	lock     sync.Mutex
+	provider *sdktrace.TracerProvider
+	tracer   oteltrace.Tracer
+	started  map[startedSpanKey]oteltrace.Span
+}
+
+// startedSpanKey identifies spans started by StartTrace
+// by the returned context and the span name.
+type startedSpanKey struct {
+	ctx  context.Context
+	name string
+}
+
+// NewOtelTracer method are creates a new instance of the tracer.
+//
+//	Returns: *OtelTracer
+func NewOtelTracer() *OtelTracer {
+	c := &OtelTracer{
+		connectionResolver: connect.NewOtlpConnectionResolver(),
+		interval:           5000 * time.Millisecond,
+		timeout:            10000 * time.Millisecond,
+		started:            make(map[startedSpanKey]oteltrace.Span),
+	}
+	c.instance, _ = os.Hostname()
+	return c
+}
+
+// Configure method are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config    configuration parameters to be set.
+func (c *OtelTracer) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.connectionResolver.Configure(ctx, config)
+
+	c.source = config.GetAsStringWithDefault("source", c.source)
+	c.instance = config.GetAsStringWithDefault("instance", c.instance)
+	c.interval = time.Duration(config.GetAsLongWithDefault("options.interval", c.interval.Milliseconds())) * time.Millisecond
+	c.timeout = time.Duration(config.GetAsLongWithDefault("options.timeout", c.timeout.Milliseconds())) * time.Millisecond
+}
+
+// SetReferences method are sets references to dependent components.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- references 	references to locate the component dependencies.
+func (c *OtelTracer) SetReferences(ctx context.Context, references cref.IReferences) {
+	c.connectionResolver.SetReferences(ctx, references)
+
+	ref := references.GetOneOptional(cref.NewDescriptor("pip-services", "context-info", "default", "*", "1.0"))
+	if contextInfo, ok := ref.(*cctx.ContextInfo); ok && contextInfo != nil {
+		if c.source == "" {
+			c.source = contextInfo.Name
+		}
+		if c.instance == "" {
+			c.instance = contextInfo.ContextId
+		}
+	}
+}
+
+// IsOpen method are checks if the component is opened.
+//
+//	Returns: true if the component has been opened and false otherwise.
+func (c *OtelTracer) IsOpen() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.provider != nil
+}
+
+// Open method are creates the span exporter and starts exporting recorded spans.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *OtelTracer) Open(ctx context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.provider != nil {
+		return nil
+	}
+
+	endpoint, err := c.connectionResolver.Resolve(ctx)
+	if err != nil {
+		return err
+	}
+
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpoint.Address()),
+		otlptracehttp.WithURLPath(endpoint.SignalPath("traces")),
+		otlptracehttp.WithTimeout(c.timeout),
+	}
+	if endpoint.Insecure() {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return err
+	}
+
+	c.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(c.interval)),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", c.source),
+			attribute.String("service.instance.id", c.instance),
+		)),
+	)
+	c.tracer = c.provider.Tracer(TracerScopeName)
+	return nil
+}
+
+// Dump method are exports all recorded spans immediately.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *OtelTracer) Dump(ctx context.Context) error {
+	c.lock.Lock()
+	provider := c.provider
+	c.lock.Unlock()
+
+	if provider == nil {
+		return nil
+	}
+	return provider.ForceFlush(ctx)
+}
+
+// Close method are exports remaining spans and closes the exporter.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occured.
+func (c *OtelTracer) Close(ctx context.Context) error {
+	c.lock.Lock()
+	provider := c.provider
+	c.provider = nil
+	c.tracer = nil
+	c.started = make(map[startedSpanKey]oteltrace.Span)
+	c.lock.Unlock()
+
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
+
+func (c *OtelTracer) spanName(component string, operation string) string {
+	if operation == "" {
+		return component
+	}
+	return component + "." + operation
+}
+
+func (c *OtelTracer) spanAttributes(ctx context.Context, component string, operation string) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		attribute.String(ComponentAttribute, component),
+		attribute.String(OperationAttribute, operation),
+	}
+	if traceId := cctx.GetTraceId(ctx); traceId != "" {
+		attributes = append(attributes, attribute.String(TraceIdAttribute, traceId))
+	}
+	return attributes
+}
+
+// StartTrace method are starts a span for the operation and returns a context with it.
+// Spans started by nested calls with the returned context become children of this span.
+// The span ends when the returned TraceTiming is ended.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- component         a name of called component
+//		- operation         a name of the executed operation.
+//	Returns: a context with the started span and a trace timing object.
+func (c *OtelTracer) StartTrace(ctx context.Context, component string,
+	operation string) (context.Context, *ctrace.TraceTiming) {
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.tracer == nil {
+		return ctx, ctrace.NewTraceTiming(ctx, component, operation, c)
+	}
+
+	name := c.spanName(component, operation)
+	spanCtx, span := c.tracer.Start(c.parentContext(ctx), name,
+		oteltrace.WithAttributes(c.spanAttributes(ctx, component, operation)...))
+	// Let clients and message queues propagate the new span to other services
+	spanCtx = ctrace.NewContextWithSpanContext(spanCtx, FromOtelSpanContext(span.SpanContext()))
+	c.started[startedSpanKey{ctx: spanCtx, name: name}] = span
+
+	return spanCtx, ctrace.NewTraceTiming(spanCtx, component, operation, c)
+}
+
+// parentContext links spans without a local parent to the span received from a remote service
+func (c *OtelTracer) parentContext(ctx context.Context) context.Context {
+	if oteltrace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	if remote, ok := ctrace.GetSpanContext(ctx); ok {
+		if spanContext := ToOtelSpanContext(remote); spanContext.IsValid() {
+			return oteltrace.ContextWithRemoteSpanContext(ctx, spanContext)
+		}
+	}
+	return ctx
+}
+
+func (c *OtelTracer) endSpan(ctx context.Context, component string, operation string, err error, duration int64) {
+	name := c.spanName(component, operation)
+	key := startedSpanKey{ctx: ctx, name: name}
+
+	c.lock.Lock()
+	span, ok := c.started[key]
+	if ok {
+		delete(c.started, key)
+	} else if c.tracer != nil {
+		start := time.Now().Add(-time.Duration(duration) * time.Millisecond)
+		_, span = c.tracer.Start(c.parentContext(ctx), name,
+			oteltrace.WithTimestamp(start),
+			oteltrace.WithAttributes(c.spanAttributes(ctx, component, operation)...))
+	}
+	c.lock.Unlock()
+
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Trace method are records an operation trace with its name and duration.
+// If the operation was started by StartTrace its span is ended,
+// otherwise a new span is recorded.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- component         a name of called component
+//		- operation         a name of the executed operation.
+//		- duration          execution duration in milliseconds.
+func (c *OtelTracer) Trace(ctx context.Context, component string, operation string, duration int64) {
+	c.endSpan(ctx, component, operation, nil, duration)
+}
+
+// Failure method are records an operation failure with its name, duration and error.
+// The error is recorded as a span event and the span status is set to error.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- component         a name of called component
+//		- operation         a name of the executed operation.
+//		- err               an error object associated with this trace.
+//		- duration          execution duration in milliseconds.
+func (c *OtelTracer) Failure(ctx context.Context, component string, operation string, err error, duration int64) {
+	c.endSpan(ctx, component, operation, err, duration)
+}
+
+// BeginTrace method are begins recording an operation trace.
+// The span is started immediately, but the context with it is not returned.
+// Use StartTrace to propagate the span to nested calls.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- component         a name of called component
+//		- operation         a name of the executed operation.
+//	Returns: a trace timing object.
+func (c *OtelTracer) BeginTrace(ctx context.Context, component string, operation string) *ctrace.TraceTiming {
+	_, timing := c.StartTrace(ctx, component, operation)
+	return timing
+}
@@ -0,0 +1,69 @@
+package trace
+
+import (
+	"context"
+
+	ctrace "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// ToOtelSpanContext converts W3C span context propagated by Pip.Services
+// into OpenTelemetry span context.
+//
+//	Parameters:
+//		- spanContext a span context to convert.
+//	Returns: the OpenTelemetry span context. It is invalid if the ids can not be parsed.
+func ToOtelSpanContext(spanContext ctrace.SpanContext) oteltrace.SpanContext {
+	traceId, err := oteltrace.TraceIDFromHex(spanContext.TraceId)
+	if err != nil {
+		return oteltrace.SpanContext{}
+	}
+	spanId, err := oteltrace.SpanIDFromHex(spanContext.SpanId)
+	if err != nil {
+		return oteltrace.SpanContext{}
+	}
+	// Invalid trace state is dropped as the specification requires
+	traceState, _ := oteltrace.ParseTraceState(spanContext.TraceState)
+
+	return oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    traceId,
+		SpanID:     spanId,
+		TraceFlags: oteltrace.TraceFlags(spanContext.TraceFlags),
+		TraceState: traceState,
+		Remote:     spanContext.Remote,
+	})
+}
+
+// FromOtelSpanContext converts OpenTelemetry span context into W3C span context
+// propagated by Pip.Services clients and message queues.
+//
+//	Parameters:
+//		- spanContext a span context to convert.
+//	Returns: the W3C span context.
+func FromOtelSpanContext(spanContext oteltrace.SpanContext) ctrace.SpanContext {
+	return ctrace.SpanContext{
+		TraceId:    spanContext.TraceID().String(),
+		SpanId:     spanContext.SpanID().String(),
+		TraceFlags: byte(spanContext.TraceFlags()),
+		TraceState: spanContext.TraceState().String(),
+		Remote:     spanContext.IsRemote(),
+	}
+}
+
+// SpanContextFromContext gets OpenTelemetry span context from the context.
+// When the context has no OpenTelemetry span, the span context received
+// from a remote service is used.
+//
+//	Parameters:
+//		- ctx a context to read.
+//	Returns: the span context. It is invalid if the context is not traced.
+func SpanContextFromContext(ctx context.Context) oteltrace.SpanContext {
+	spanContext := oteltrace.SpanContextFromContext(ctx)
+	if spanContext.IsValid() {
+		return spanContext
+	}
+	if remote, ok := ctrace.GetSpanContext(ctx); ok {
+		return ToOtelSpanContext(remote)
+	}
+	return spanContext
+}
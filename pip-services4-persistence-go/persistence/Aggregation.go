@@ -0,0 +1,196 @@
+package persistence
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"sort"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+)
+
+var aggregateNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateAggregateParams checks that aggregate parameters are safe to use in queries:
+// field names are valid, functions are supported and aggregate names are unique.
+//
+//	Parameters:
+//		- params aggregate parameters to validate.
+//	Returns: BadRequestError if the parameters are invalid or nil otherwise.
+func ValidateAggregateParams(params cquery.AggregateParams) error {
+	if len(params.GroupBy) == 0 && len(params.Fields) == 0 {
+		return cerr.NewBadRequestError("", "INVALID_AGGREGATE",
+			"Aggregation requires group fields or aggregate functions")
+	}
+
+	for _, field := range params.GroupBy {
+		if !filterFieldRegex.MatchString(field) {
+			return cerr.NewBadRequestError("", "INVALID_AGGREGATE",
+				"Group field "+field+" is not a valid name").
+				WithDetails("field", field)
+		}
+	}
+
+	names := make(map[string]bool, len(params.Fields))
+	for _, field := range params.Fields {
+		if !aggregateNameRegex.MatchString(field.Name) || names[field.Name] {
+			return cerr.NewBadRequestError("", "INVALID_AGGREGATE",
+				"Aggregate name "+field.Name+" is not valid or not unique").
+				WithDetails("name", field.Name)
+		}
+		names[field.Name] = true
+
+		switch field.Function {
+		case cquery.AggregateCount:
+			if field.Field == "" {
+				continue
+			}
+		case cquery.AggregateSum, cquery.AggregateAvg, cquery.AggregateMin, cquery.AggregateMax:
+		default:
+			return cerr.NewBadRequestError("", "INVALID_AGGREGATE",
+				"Aggregate function "+field.Function+" is not supported").
+				WithDetails("function", field.Function)
+		}
+		if !filterFieldRegex.MatchString(field.Field) {
+			return cerr.NewBadRequestError("", "INVALID_AGGREGATE",
+				"Aggregate field "+field.Field+" is not a valid name").
+				WithDetails("field", field.Field)
+		}
+	}
+	return nil
+}
+
+// ComposeAggregateRow creates a result row from values read from a database.
+// The values shall follow the order of group fields and then the order of aggregates.
+// Counts are converted into int64, sums and averages into float64.
+//
+//	Parameters:
+//		- params aggregate parameters of the query.
+//		- values values of the group fields and aggregates.
+//	Returns: a result row.
+func ComposeAggregateRow(params cquery.AggregateParams, values []any) cquery.AggregateRow {
+	row := cquery.NewAggregateRow(make(map[string]any, len(params.GroupBy)), make(map[string]any, len(params.Fields)))
+	for i, field := range params.GroupBy {
+		row.Group[field] = toAggregateValue(values[i])
+	}
+	for i, field := range params.Fields {
+		value := toAggregateValue(values[len(params.GroupBy)+i])
+		if value != nil {
+			switch field.Function {
+			case cquery.AggregateCount:
+				value = convert.LongConverter.ToLong(value)
+			case cquery.AggregateSum, cquery.AggregateAvg:
+				value = convert.DoubleConverter.ToDouble(value)
+			}
+		}
+		row.Values[field.Name] = value
+	}
+	return row
+}
+
+func toAggregateValue(value any) any {
+	if valuer, ok := value.(driver.Valuer); ok {
+		value, _ = valuer.Value()
+	}
+	if buf, ok := value.([]byte); ok {
+		return string(buf)
+	}
+	return value
+}
+
+type aggregateAccumulator struct {
+	count int64
+	sum   float64
+	value any
+}
+
+// aggregateItems calculates aggregates over items converted into maps.
+// The result rows are sorted by group fields.
+func aggregateItems(items []map[string]any, params cquery.AggregateParams) []cquery.AggregateRow {
+	groupSort := make(cquery.SortParams, len(params.GroupBy))
+	for i, field := range params.GroupBy {
+		groupSort[i] = cquery.NewSortField(field, true)
+	}
+
+	type group struct {
+		keys         []any
+		accumulators []*aggregateAccumulator
+	}
+
+	groups := make(map[string]*group)
+	order := make([]*group, 0)
+	getGroup := func(keys []any) *group {
+		key, _ := convert.JsonConverter.ToJson(keys)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{keys: keys, accumulators: make([]*aggregateAccumulator, len(params.Fields))}
+			for i := range g.accumulators {
+				g.accumulators[i] = &aggregateAccumulator{}
+			}
+			groups[key] = g
+			order = append(order, g)
+		}
+		return g
+	}
+
+	// Like in SQL, aggregates without groups always return one row
+	if len(params.GroupBy) == 0 {
+		getGroup([]any{})
+	}
+
+	for _, item := range items {
+		g := getGroup(GetKeysetValues(item, groupSort))
+
+		for i, field := range params.Fields {
+			accumulator := g.accumulators[i]
+			var value any
+			if field.Field != "" {
+				value = GetKeysetValues(item, cquery.SortParams{cquery.NewSortField(field.Field, true)})[0]
+				if value == nil {
+					continue
+				}
+			}
+			accumulator.count++
+			switch field.Function {
+			case cquery.AggregateSum, cquery.AggregateAvg:
+				accumulator.sum += convert.DoubleConverter.ToDouble(value)
+			case cquery.AggregateMin:
+				if accumulator.value == nil || CompareKeysetValues(value, accumulator.value) < 0 {
+					accumulator.value = value
+				}
+			case cquery.AggregateMax:
+				if accumulator.value == nil || CompareKeysetValues(value, accumulator.value) > 0 {
+					accumulator.value = value
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return compareKeysetKeys(order[i].keys, order[j].keys, groupSort) < 0
+	})
+
+	rows := make([]cquery.AggregateRow, len(order))
+	for i, g := range order {
+		values := make([]any, 0, len(g.keys)+len(g.accumulators))
+		values = append(values, g.keys...)
+		for j, field := range params.Fields {
+			accumulator := g.accumulators[j]
+			switch {
+			case field.Function == cquery.AggregateCount:
+				values = append(values, accumulator.count)
+			case accumulator.count == 0:
+				values = append(values, nil)
+			case field.Function == cquery.AggregateSum:
+				values = append(values, accumulator.sum)
+			case field.Function == cquery.AggregateAvg:
+				values = append(values, accumulator.sum/float64(accumulator.count))
+			default:
+				values = append(values, accumulator.value)
+			}
+		}
+		rows[i] = ComposeAggregateRow(params, values)
+	}
+	return rows
+}
@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+)
+
+// BatchRowGroup is a group of rows in a batch that have the same set of columns.
+// SQL persistences write every group with its own multi-row statements,
+// so columns missing in a row get their default values instead of NULL.
+type BatchRowGroup struct {
+	// Column names sorted in alphabetical order
+	Columns []string
+	// Rows converted into maps of column values
+	Rows []map[string]any
+}
+
+// GroupBatchRows groups rows of a batch by their sets of columns.
+// The groups follow the order of their first rows and nil rows are skipped.
+//
+//	Parameters:
+//		- rows []map[string]any rows converted into maps of column values.
+//	Returns: []*BatchRowGroup groups of rows with the same columns.
+func GroupBatchRows(rows []map[string]any) []*BatchRowGroup {
+	groups := make([]*BatchRowGroup, 0, 1)
+	index := make(map[string]*BatchRowGroup)
+	for _, row := range rows {
+		if row == nil {
+			continue
+		}
+		columns := make([]string, 0, len(row))
+		for column := range row {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+
+		key := strings.Join(columns, ",")
+		group, ok := index[key]
+		if !ok {
+			group = &BatchRowGroup{Columns: columns}
+			index[key] = group
+			groups = append(groups, group)
+		}
+		group.Rows = append(group.Rows, row)
+	}
+	return groups
+}
+
+// NewDuplicateIdError creates an error returned for an item whose id
+// was already used by another item in the same batch.
+//
+//	Parameters:
+//		- traceId string transaction id to trace execution through call chain.
+//		- id any an id of the duplicated item
+//	Returns: *cerr.ApplicationError a conflict error
+func NewDuplicateIdError(traceId string, id any) *cerr.ApplicationError {
+	return cerr.NewConflictError(traceId, "DUPLICATE_ID",
+		"Item "+convert.StringConverter.ToString(id)+" appears more than once in the batch").
+		WithDetails("id", id)
+}
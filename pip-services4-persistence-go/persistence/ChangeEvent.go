@@ -0,0 +1,138 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// ChangeTypeCreated is a type of change events emitted when data items are created.
+	ChangeTypeCreated = "created"
+	// ChangeTypeUpdated is a type of change events emitted when data items are updated.
+	ChangeTypeUpdated = "updated"
+	// ChangeTypeDeleted is a type of change events emitted when data items are deleted.
+	ChangeTypeDeleted = "deleted"
+)
+
+// ChangeEvent describes a change of a single data item made by a persistence component.
+type ChangeEvent struct {
+	// Type of the change: created, updated or deleted.
+	Type string `json:"type"`
+	// Name of the collection or table where the item is stored.
+	Source string `json:"source"`
+	// Unique id of the changed item.
+	Id any `json:"id"`
+	// Item before the change or nil when it was created.
+	Before any `json:"before"`
+	// Item after the change or nil when it was physically deleted.
+	After any `json:"after"`
+	// Time when the change was made.
+	Time time.Time `json:"time"`
+}
+
+// NewChangeEvent creates a new change event stamped with the current time.
+//
+//	Parameters:
+//		- changeType a type of the change: created, updated or deleted.
+//		- source a name of the collection or table.
+//		- id an id of the changed item.
+//		- before an item before the change or nil.
+//		- after an item after the change or nil.
+//	Returns: *ChangeEvent a new change event.
+func NewChangeEvent(changeType string, source string, id any, before any, after any) *ChangeEvent {
+	return &ChangeEvent{
+		Type:   changeType,
+		Source: source,
+		Id:     id,
+		Before: before,
+		After:  after,
+		Time:   time.Now().UTC(),
+	}
+}
+
+// IChangeListener is an interface for components that receive change events from persistences.
+type IChangeListener interface {
+	// OnChange is called after a data item was changed.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- event *ChangeEvent the change event.
+	//	Returns: error or nil if the event was handled.
+	OnChange(ctx context.Context, event *ChangeEvent) error
+}
+
+// ChangeNotifier keeps change listeners registered in a persistence component
+// and delivers change events to them. It is safe for concurrent use.
+type ChangeNotifier struct {
+	mtx       sync.RWMutex
+	listeners []IChangeListener
+}
+
+// NewChangeNotifier creates a new notifier without listeners.
+//
+//	Returns: *ChangeNotifier
+func NewChangeNotifier() *ChangeNotifier {
+	return &ChangeNotifier{
+		listeners: make([]IChangeListener, 0),
+	}
+}
+
+// AddListener registers a listener to receive change events.
+//
+//	Parameters:
+//		- listener IChangeListener a listener to be added.
+func (c *ChangeNotifier) AddListener(listener IChangeListener) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.listeners = append(c.listeners, listener)
+}
+
+// RemoveListener removes a previously registered listener.
+//
+//	Parameters:
+//		- listener IChangeListener a listener to be removed.
+func (c *ChangeNotifier) RemoveListener(listener IChangeListener) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for i, l := range c.listeners {
+		if l == listener {
+			c.listeners = append(c.listeners[:i:i], c.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// HasListeners checks if there are registered listeners.
+//
+//	Returns: bool true if at least one listener is registered.
+func (c *ChangeNotifier) HasListeners() bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return len(c.listeners) > 0
+}
+
+// Notify delivers change events to all registered listeners in the order of registration.
+// Failure of one listener does not prevent delivery to others.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- events ...*ChangeEvent change events to be delivered.
+//	Returns: the first error returned by listeners or nil.
+func (c *ChangeNotifier) Notify(ctx context.Context, events ...*ChangeEvent) error {
+	c.mtx.RLock()
+	listeners := c.listeners
+	c.mtx.RUnlock()
+
+	var result error
+	for _, event := range events {
+		for _, listener := range listeners {
+			if err := listener.OnChange(ctx, event); err != nil && result == nil {
+				result = err
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package persistence
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// OutboxEntry is a change event kept in a transactional outbox until it is published.
+type OutboxEntry struct {
+	// Sequential id of the entry unique within the outbox.
+	Id string `json:"id"`
+	// The change event.
+	Event *ChangeEvent `json:"event"`
+}
+
+// IChangeOutbox is an interface for persistence components that keep change events
+// in a transactional outbox to be published to message brokers.
+type IChangeOutbox interface {
+	// DrainOutbox reads up to maxCount oldest entries from the outbox and passes them to the handler.
+	// The entries are removed only when the handler succeeds, otherwise they are kept to be retried.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- maxCount int a maximum number of entries to read.
+	//		- handler a function to publish the entries in the order they were written.
+	//	Returns: a number of drained entries or error.
+	DrainOutbox(ctx context.Context, maxCount int,
+		handler func(ctx context.Context, entries []*OutboxEntry) error) (int, error)
+}
+
+// MemoryChangeOutbox is a change listener that keeps change events in memory
+// until they are drained. It allows to use the outbox with memory persistences and in tests.
+// The drained entries are removed only after the handler succeeds.
+//
+//	Example:
+//		outbox := NewMemoryChangeOutbox()
+//		persistence.AddChangeListener(outbox)
+//		...
+//		count, err := outbox.DrainOutbox(ctx, 100, func(ctx context.Context, entries []*OutboxEntry) error {
+//			...
+//		})
+//
+//	Implements: IChangeListener, IChangeOutbox
+type MemoryChangeOutbox struct {
+	mtx     sync.Mutex
+	drain   sync.Mutex
+	entries []*OutboxEntry
+	lastId  int64
+}
+
+// NewMemoryChangeOutbox creates a new empty outbox.
+//
+//	Returns: *MemoryChangeOutbox
+func NewMemoryChangeOutbox() *MemoryChangeOutbox {
+	return &MemoryChangeOutbox{
+		entries: make([]*OutboxEntry, 0),
+	}
+}
+
+// OnChange adds the change event to the outbox.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- event *ChangeEvent the change event.
+//	Returns: error or nil if the event was added.
+func (c *MemoryChangeOutbox) OnChange(ctx context.Context, event *ChangeEvent) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.lastId++
+	c.entries = append(c.entries, &OutboxEntry{
+		Id:    strconv.FormatInt(c.lastId, 10),
+		Event: event,
+	})
+	return nil
+}
+
+// GetCount gets a number of entries kept in the outbox.
+//
+//	Returns: int
+func (c *MemoryChangeOutbox) GetCount() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return len(c.entries)
+}
+
+// DrainOutbox reads up to maxCount oldest entries from the outbox and passes them to the handler.
+// The entries are removed only when the handler succeeds.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- maxCount int a maximum number of entries to read.
+//		- handler a function to publish the entries.
+//	Returns: a number of drained entries or error.
+func (c *MemoryChangeOutbox) DrainOutbox(ctx context.Context, maxCount int,
+	handler func(ctx context.Context, entries []*OutboxEntry) error) (int, error) {
+
+	// Only one drain at a time to keep the order of entries
+	c.drain.Lock()
+	defer c.drain.Unlock()
+
+	c.mtx.Lock()
+	count := len(c.entries)
+	if maxCount > 0 && count > maxCount {
+		count = maxCount
+	}
+	entries := make([]*OutboxEntry, count)
+	copy(entries, c.entries[:count])
+	c.mtx.Unlock()
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	if err := handler(ctx, entries); err != nil {
+		return 0, err
+	}
+
+	c.mtx.Lock()
+	c.entries = c.entries[count:]
+	c.mtx.Unlock()
+
+	return count, nil
+}
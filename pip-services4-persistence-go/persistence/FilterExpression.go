@@ -0,0 +1,294 @@
+package persistence
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+)
+
+// FilterOperator defines an operator used in FilterExpression nodes.
+type FilterOperator string
+
+const (
+	FilterAnd        FilterOperator = "and"
+	FilterOr         FilterOperator = "or"
+	FilterEqual      FilterOperator = "eq"
+	FilterNotEqual   FilterOperator = "ne"
+	FilterLess       FilterOperator = "lt"
+	FilterLessEqual  FilterOperator = "le"
+	FilterMore       FilterOperator = "gt"
+	FilterMoreEqual  FilterOperator = "ge"
+	FilterIn         FilterOperator = "in"
+	FilterLike       FilterOperator = "like"
+	FilterBetween    FilterOperator = "between"
+	FilterIsNull     FilterOperator = "is_null"
+	FilterIsNotNull  FilterOperator = "is_not_null"
+	filterOpSplitter                = "__"
+	filterOrPrefix                  = "or"
+)
+
+var filterFieldRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// FilterExpression is a declarative, database independent filter definition.
+// A node is either a logical group (and/or) that contains child expressions,
+// or a condition that compares a field with one or more values.
+//
+// Expressions can be created in code or parsed from FilterParams using
+// NewFilterExpressionFromParams. Persistence components compile them into
+// parameterized queries, so filter values never get concatenated into SQL.
+//
+//	Example:
+//		filter := NewFilterAnd(
+//			NewFilterCondition("key", FilterEqual, "Key 1"),
+//			NewFilterOr(
+//				NewFilterCondition("content", FilterLike, "%abc%"),
+//				NewFilterCondition("content", FilterIsNull),
+//			),
+//		)
+type FilterExpression struct {
+	// Field name for conditions
+	Field string
+	// Operator of the condition or logical group
+	Operator FilterOperator
+	// Values to compare with
+	Values []any
+	// Child expressions for logical groups
+	Children []*FilterExpression
+}
+
+// NewFilterCondition creates a new filter condition.
+//
+//	Parameters:
+//		- field a name of the field to compare.
+//		- operator a comparison operator.
+//		- values values to compare with. "in" accepts any number of values,
+//			"between" requires exactly two, "is_null" and "is_not_null" require none.
+//	Returns: a new condition.
+func NewFilterCondition(field string, operator FilterOperator, values ...any) *FilterExpression {
+	return &FilterExpression{
+		Field:    field,
+		Operator: operator,
+		Values:   values,
+	}
+}
+
+// NewFilterAnd creates a logical group where all child expressions must match.
+//
+//	Parameters:
+//		- children child expressions.
+//	Returns: a new logical group.
+func NewFilterAnd(children ...*FilterExpression) *FilterExpression {
+	return &FilterExpression{
+		Operator: FilterAnd,
+		Children: children,
+	}
+}
+
+// NewFilterOr creates a logical group where at least one child expression must match.
+//
+//	Parameters:
+//		- children child expressions.
+//	Returns: a new logical group.
+func NewFilterOr(children ...*FilterExpression) *FilterExpression {
+	return &FilterExpression{
+		Operator: FilterOr,
+		Children: children,
+	}
+}
+
+// NewFilterExpressionFromParams parses FilterParams into a FilterExpression.
+//
+// Each key is "<field>" for equality or "<field>__<operator>" for other operators,
+// for example "name", "age__gt", "type__in" or "deleted__is_null".
+// Values of "in" are comma-separated, values of "between" are two comma-separated bounds,
+// values of "is_null" are booleans. All conditions are combined with AND, except keys
+// prefixed with "or." (or "or<N>." for several groups) that form OR groups,
+// e.g. "or.key=A;or.key=B" means (key = A OR key = B). Since values of FilterParams are
+// unique per key, equal fields inside one OR group can be distinguished with the "in" operator.
+//
+//	Parameters:
+//		- filter filter parameters to parse.
+//		- fields (optional) allowed field names. When set, other fields cause an error.
+//	Returns: parsed expression or BadRequestError if the filter is invalid.
+func NewFilterExpressionFromParams(filter cquery.FilterParams, fields ...string) (*FilterExpression, error) {
+	result := NewFilterAnd()
+	if filter.StringValueMap == nil {
+		return result, nil
+	}
+
+	allowed := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		allowed[field] = true
+	}
+
+	values := filter.Value()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	groups := make(map[string]*FilterExpression)
+	for _, key := range keys {
+		target := result
+		name := key
+
+		if pos := strings.Index(key, "."); pos > 0 {
+			prefix := key[:pos]
+			if isFilterOrPrefix(prefix) {
+				group, ok := groups[prefix]
+				if !ok {
+					group = NewFilterOr()
+					groups[prefix] = group
+					result.Children = append(result.Children, group)
+				}
+				target = group
+				name = key[pos+1:]
+			}
+		}
+
+		condition, err := parseFilterCondition(name, values[key])
+		if err != nil {
+			return nil, err
+		}
+		if len(allowed) > 0 && !allowed[condition.Field] {
+			return nil, cerr.NewBadRequestError("", "INVALID_FILTER",
+				"Filtering by field "+condition.Field+" is not allowed").
+				WithDetails("field", condition.Field)
+		}
+		target.Children = append(target.Children, condition)
+	}
+
+	return result, nil
+}
+
+func isFilterOrPrefix(prefix string) bool {
+	if !strings.HasPrefix(prefix, filterOrPrefix) {
+		return false
+	}
+	for _, ch := range prefix[len(filterOrPrefix):] {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func parseFilterCondition(key string, value string) (*FilterExpression, error) {
+	field := key
+	operator := FilterEqual
+	if pos := strings.LastIndex(key, filterOpSplitter); pos > 0 {
+		field = key[:pos]
+		operator = FilterOperator(strings.ToLower(key[pos+len(filterOpSplitter):]))
+	}
+
+	switch operator {
+	case FilterEqual, FilterNotEqual, FilterLess, FilterLessEqual,
+		FilterMore, FilterMoreEqual, FilterLike:
+		return NewFilterCondition(field, operator, value), nil
+	case FilterIn:
+		return NewFilterCondition(field, operator, splitFilterValues(value)...), nil
+	case FilterBetween:
+		values := splitFilterValues(value)
+		if len(values) != 2 {
+			return nil, cerr.NewBadRequestError("", "INVALID_FILTER",
+				"Filter "+key+" requires two comma-separated values").
+				WithDetails("field", field)
+		}
+		return NewFilterCondition(field, operator, values...), nil
+	case FilterIsNull:
+		if strings.ToLower(strings.TrimSpace(value)) == "false" {
+			return NewFilterCondition(field, FilterIsNotNull), nil
+		}
+		return NewFilterCondition(field, FilterIsNull), nil
+	case FilterIsNotNull:
+		return NewFilterCondition(field, FilterIsNotNull), nil
+	default:
+		return nil, cerr.NewBadRequestError("", "INVALID_FILTER",
+			"Filter operator "+string(operator)+" is not supported").
+			WithDetails("field", field)
+	}
+}
+
+func splitFilterValues(value string) []any {
+	if value == "" {
+		return []any{}
+	}
+	items := strings.Split(value, ",")
+	result := make([]any, len(items))
+	for i, item := range items {
+		result[i] = strings.TrimSpace(item)
+	}
+	return result
+}
+
+// IsEmpty checks if the expression contains no conditions.
+//
+//	Returns: true if the expression matches all items.
+func (c *FilterExpression) IsEmpty() bool {
+	if c == nil {
+		return true
+	}
+	if c.Operator != FilterAnd && c.Operator != FilterOr {
+		return false
+	}
+	for _, child := range c.Children {
+		if !child.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks if the expression is well formed: operators are known,
+// field names are valid identifiers and value counts match operators.
+//
+//	Returns: BadRequestError if the expression is invalid or nil otherwise.
+func (c *FilterExpression) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	switch c.Operator {
+	case FilterAnd, FilterOr:
+		for _, child := range c.Children {
+			if err := child.Validate(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !filterFieldRegex.MatchString(c.Field) {
+		return cerr.NewBadRequestError("", "INVALID_FILTER",
+			"Filter field "+c.Field+" is not a valid name").
+			WithDetails("field", c.Field)
+	}
+
+	expected := -1
+	switch c.Operator {
+	case FilterEqual, FilterNotEqual, FilterLess, FilterLessEqual,
+		FilterMore, FilterMoreEqual, FilterLike:
+		expected = 1
+	case FilterBetween:
+		expected = 2
+	case FilterIsNull, FilterIsNotNull:
+		expected = 0
+	case FilterIn:
+		expected = -1
+	default:
+		return cerr.NewBadRequestError("", "INVALID_FILTER",
+			"Filter operator "+string(c.Operator)+" is not supported").
+			WithDetails("field", c.Field)
+	}
+
+	if expected >= 0 && len(c.Values) != expected {
+		return cerr.NewBadRequestError("", "INVALID_FILTER",
+			"Filter operator "+string(c.Operator)+" has wrong number of values").
+			WithDetails("field", c.Field)
+	}
+	return nil
+}
@@ -8,8 +8,10 @@ import (
 	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
 	refl "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/reflect"
 	"github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
 	"github.com/pip-services4/pip-services4-go/pip-services4-data-go/data"
 	"github.com/pip-services4/pip-services4-go/pip-services4-data-go/keys"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
 
 	"github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
 )
@@ -25,6 +27,9 @@ import (
 // accessing cached items via c.Items property and calling Save method
 // on updates.
 //
+// Listeners added by AddChangeListener receive created, updated and deleted change events
+// after the changes are saved. Errors returned by listeners are logged and do not fail the operations.
+//
 //	Important:
 //		- this component is a thread save!
 //		- the data items must implement IDataObject interface
@@ -34,6 +39,8 @@ import (
 //	Configuration parameters:
 //		- options
 //		- max_page_size maximum number of items returned in a single page (default: 100)
+//		- versioned turns on optimistic concurrency for items that implement IVersioned (default: false)
+//		- soft_delete turns on logical deletion for items that implement ITrackable (default: false)
 //	References:
 //		- *:logger:*:*:1.0 (optional) ILogger components to pass log messages
 //	Typed params:
@@ -88,13 +95,20 @@ import (
 //		}
 //
 //	Extends: MemoryPersistence
-//	Implements: IConfigurable, IWriter, IGetter, ISetter
+//	Implements: IConfigurable, IWriter, IGetter, ISetter, IBatchWriter, IBatchSetter, IBatchPartialUpdater
 type IdentifiableMemoryPersistence[T any, K any] struct {
 	*MemoryPersistence[T]
 	Mtx sync.RWMutex
+	// Versioned turns on optimistic concurrency. When items implement IVersioned
+	// they are updated only when their version matches the stored one.
+	Versioned bool
+
+	changes *ChangeNotifier
 }
 
 const IdentifiableMemoryPersistenceConfigParamOptionsMaxPageSize = "options.max_page_size"
+const IdentifiableMemoryPersistenceConfigParamOptionsVersioned = "options.versioned"
+const IdentifiableMemoryPersistenceConfigParamOptionsSoftDelete = "options.soft_delete"
 
 // NewIdentifiableMemoryPersistence creates a new empty instance of the persistence.
 //
@@ -107,6 +121,7 @@ const IdentifiableMemoryPersistenceConfigParamOptionsMaxPageSize = "options.max_
 func NewIdentifiableMemoryPersistence[T any, K any]() (c *IdentifiableMemoryPersistence[T, K]) {
 	c = &IdentifiableMemoryPersistence[T, K]{
 		MemoryPersistence: NewMemoryPersistence[T](),
+		changes:           NewChangeNotifier(),
 	}
 	c.Logger = log.NewCompositeLogger()
 	c.MaxPageSize = 100
@@ -120,6 +135,44 @@ func NewIdentifiableMemoryPersistence[T any, K any]() (c *IdentifiableMemoryPers
 //		- config *config.ConfigParams configuration parameters to be set.
 func (c *IdentifiableMemoryPersistence[T, K]) Configure(ctx context.Context, config *config.ConfigParams) {
 	c.MaxPageSize = config.GetAsIntegerWithDefault(IdentifiableMemoryPersistenceConfigParamOptionsMaxPageSize, c.MaxPageSize)
+	c.Versioned = config.GetAsBooleanWithDefault(IdentifiableMemoryPersistenceConfigParamOptionsVersioned, c.Versioned)
+	c.SoftDelete = config.GetAsBooleanWithDefault(IdentifiableMemoryPersistenceConfigParamOptionsSoftDelete, c.SoftDelete)
+}
+
+// AddChangeListener registers a listener to receive change events of the data items.
+//
+//	Parameters:
+//		- listener IChangeListener a listener to be added.
+func (c *IdentifiableMemoryPersistence[T, K]) AddChangeListener(listener IChangeListener) {
+	c.changes.AddListener(listener)
+}
+
+// RemoveChangeListener removes a previously registered change listener.
+//
+//	Parameters:
+//		- listener IChangeListener a listener to be removed.
+func (c *IdentifiableMemoryPersistence[T, K]) RemoveChangeListener(listener IChangeListener) {
+	c.changes.RemoveListener(listener)
+}
+
+// GetPageByFilterWithToken gets a page of data items retrieved by a given filter using keyset paging.
+// The "id" field is added to sort parameters when it is missing to make the order unique.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- filter func(T) bool (optional) a filter function to filter items
+//		- paging cquery.TokenizedPagingParams paging parameters with a token from the previous page
+//		- sortParams cquery.SortParams sort parameters
+//		- selectFunc func(in T}) (out interface{}) (optional) projection parameters
+//	Return cquery.TokenizedDataPage[T], error data page or error.
+func (c *IdentifiableMemoryPersistence[T, K]) GetPageByFilterWithToken(ctx context.Context,
+	filterFunc func(T) bool,
+	paging cquery.TokenizedPagingParams,
+	sortParams cquery.SortParams,
+	selectFunc func(T) T) (cquery.TokenizedDataPage[T], error) {
+
+	return c.MemoryPersistence.GetPageByFilterWithToken(ctx, filterFunc, paging,
+		AddKeysetSortField(sortParams, "id"), selectFunc)
 }
 
 // GetListByIds gets a list of data items retrieved by given unique ids.
@@ -144,6 +197,7 @@ func (c *IdentifiableMemoryPersistence[T, K]) GetListByIds(ctx context.Context,
 }
 
 // GetOneById gets a data item by its unique id.
+// In soft-delete mode deleted items are not returned unless the context is created by WithDeleted.
 //
 //	Parameters:
 //		- ctx context.Context execution context to trace execution through call chain.
@@ -158,6 +212,9 @@ func (c *IdentifiableMemoryPersistence[T, K]) GetOneById(ctx context.Context, id
 	for _, item := range c.Items {
 		itemId := c.getItemId(item)
 		if c.isEqualIds(itemId, id) {
+			if c.isSoftDelete() && !IsDeletedIncluded(ctx) && GetObjectDeleted(item) {
+				break
+			}
 			c.Logger.Trace(ctx, "Retrieved item %s", id)
 			return c.cloneItem(item), nil
 		}
@@ -193,23 +250,54 @@ func (c *IdentifiableMemoryPersistence[T, K]) GetIndexById(id K) int {
 //		- item T an item to be created.
 //	Returns: T, error created item or error.
 func (c *IdentifiableMemoryPersistence[T, K]) Create(ctx context.Context, item T) (T, error) {
+	newItem, event := c.createItem(ctx, item)
+
+	if err := c.Save(ctx); err != nil {
+		return c.cloneItem(newItem), err
+	}
+	c.notifyChanges(ctx, event)
+
+	return c.cloneItem(newItem), nil
+}
+
+// CreateMany creates multiple data items and saves them at once.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- items []T items to be created.
+//	Returns: []T, []error, error created items and errors of every item or error.
+func (c *IdentifiableMemoryPersistence[T, K]) CreateMany(ctx context.Context, items []T) ([]T, []error, error) {
+	results := make([]T, len(items))
+	events := make([]*ChangeEvent, len(items))
+	for i, item := range items {
+		newItem, event := c.createItem(ctx, item)
+		results[i], events[i] = c.cloneItem(newItem), event
+	}
+
+	if err := c.Save(ctx); err != nil {
+		return results, make([]error, len(items)), err
+	}
+	c.notifyChanges(ctx, events...)
+
+	return results, make([]error, len(items)), nil
+}
+
+func (c *IdentifiableMemoryPersistence[T, K]) createItem(ctx context.Context, item T) (T, *ChangeEvent) {
 	c.Mtx.Lock()
 
 	newItem := c.cloneItem(item)
 	if _item, ok := c.setItemId(newItem, c.getItemId(newItem)).(T); ok {
 		newItem = _item
 	}
+	newItem = c.trackItem(newItem, true)
 
 	c.Items = append(c.Items, newItem)
+	event := c.composeChange(ChangeTypeCreated, nil, newItem)
 
 	c.Mtx.Unlock()
 	c.Logger.Trace(ctx, "Created item %s", c.getItemId(newItem))
 
-	if err := c.Save(ctx); err != nil {
-		return c.cloneItem(newItem), err
-	}
-
-	return c.cloneItem(newItem), nil
+	return newItem, event
 }
 
 // Set a data item. If the data item exists it updates it,
@@ -221,37 +309,73 @@ func (c *IdentifiableMemoryPersistence[T, K]) Create(ctx context.Context, item T
 //
 // Returns: T, error updated item or error.
 func (c *IdentifiableMemoryPersistence[T, K]) Set(ctx context.Context, item T) (T, error) {
+	newItem, event := c.setItem(ctx, item)
+
+	if err := c.Save(ctx); err != nil {
+		return c.cloneItem(newItem), err
+	}
+	c.notifyChanges(ctx, event)
+
+	return c.cloneItem(newItem), nil
+}
+
+// UpsertMany sets multiple data items and saves them at once.
+// The data items that exist are updated, others are created.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- items []T items to be set.
+//	Returns: []T, []error, error set items and errors of every item or error.
+func (c *IdentifiableMemoryPersistence[T, K]) UpsertMany(ctx context.Context, items []T) ([]T, []error, error) {
+	results := make([]T, len(items))
+	events := make([]*ChangeEvent, len(items))
+	for i, item := range items {
+		newItem, event := c.setItem(ctx, item)
+		results[i], events[i] = c.cloneItem(newItem), event
+	}
+
+	if err := c.Save(ctx); err != nil {
+		return results, make([]error, len(items)), err
+	}
+	c.notifyChanges(ctx, events...)
+
+	return results, make([]error, len(items)), nil
+}
+
+func (c *IdentifiableMemoryPersistence[T, K]) setItem(ctx context.Context, item T) (T, *ChangeEvent) {
 	newItem := c.cloneItem(item)
 	if _item, ok := c.setItemId(newItem, c.getItemId(newItem)).(T); ok {
 		newItem = _item
 	}
 
 	index := c.GetIndexById(c.getItemId(item))
+	newItem = c.trackItem(newItem, index < 0)
 
+	var event *ChangeEvent
 	c.Mtx.Lock()
 	if index < 0 {
 		c.Items = append(c.Items, newItem)
+		event = c.composeChange(ChangeTypeCreated, nil, newItem)
 	} else {
+		event = c.composeChange(ChangeTypeUpdated, c.Items[index], newItem)
 		c.Items[index] = newItem
 	}
 
 	c.Mtx.Unlock()
 	c.Logger.Trace(ctx, "Set item %s", c.getItemId(newItem))
 
-	if err := c.Save(ctx); err != nil {
-		return c.cloneItem(newItem), err
-	}
-
-	return c.cloneItem(newItem), nil
+	return newItem, event
 }
 
 // Update a data item.
+// When Versioned option is on and the item implements IVersioned the item is updated
+// only when its version matches the stored one, and the version is incremented.
 //
 //	Parameters:
 //		- ctx context.Context execution context to trace execution through call chain.
 //		- item T an item to be updated.
 //
-// Returns: T, error updated item or error.
+// Returns: T, error updated item or error. ConflictError is returned when versions do not match.
 func (c *IdentifiableMemoryPersistence[T, K]) Update(ctx context.Context, item T) (T, error) {
 	var defaultObject T
 
@@ -263,6 +387,17 @@ func (c *IdentifiableMemoryPersistence[T, K]) Update(ctx context.Context, item T
 	newItem := c.cloneItem(item)
 
 	c.Mtx.Lock()
+	if c.Versioned {
+		if version, ok := GetObjectVersion(newItem); ok {
+			if err := c.checkVersion(ctx, c.Items[index], version); err != nil {
+				c.Mtx.Unlock()
+				return defaultObject, err
+			}
+			newItem = c.setItemVersion(newItem, NextVersion(version))
+		}
+	}
+	newItem = c.trackItem(newItem, false)
+	event := c.composeChange(ChangeTypeUpdated, c.Items[index], newItem)
 	c.Items[index] = newItem
 	c.Mtx.Unlock()
 
@@ -271,31 +406,93 @@ func (c *IdentifiableMemoryPersistence[T, K]) Update(ctx context.Context, item T
 	if err := c.Save(ctx); err != nil {
 		return c.cloneItem(newItem), err
 	}
+	c.notifyChanges(ctx, event)
 
 	return c.cloneItem(newItem), nil
 }
 
 // UpdatePartially only few selected fields in a data item.
+// When Versioned option is on and the data contains "version" field the item is updated
+// only when the version matches the stored one. The version is incremented anyway.
 //
 //	Parameters:
 //		- ctx context.Context execution context to trace execution through call chain.
 //		- id K an id of data item to be updated.
 //		- data  cdata.AnyValueMap a map with fields to be updated.
 //
-// Returns: T, error updated item or error.
+// Returns: T, error updated item or error. ConflictError is returned when versions do not match.
 func (c *IdentifiableMemoryPersistence[T, K]) UpdatePartially(ctx context.Context,
 	id K, data cdata.AnyValueMap) (T, error) {
 
+	newItem, event, found, err := c.updateItemPartially(ctx, id, data)
+	if err != nil || !found {
+		return newItem, err
+	}
+
+	if err := c.Save(ctx); err != nil {
+		return c.cloneItem(newItem), err
+	}
+	c.notifyChanges(ctx, event)
+
+	return c.cloneItem(newItem), nil
+}
+
+// UpdateManyPartially updates the same few selected fields in multiple data items
+// and saves them at once. Versions are checked for every item like in UpdatePartially.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- ids []K ids of data items to be updated.
+//		- data  cdata.AnyValueMap a map with fields to be updated.
+//
+// Returns: []T, []error, error updated items and errors of every item or error.
+// Items that were not found are returned as empty values.
+func (c *IdentifiableMemoryPersistence[T, K]) UpdateManyPartially(ctx context.Context,
+	ids []K, data cdata.AnyValueMap) ([]T, []error, error) {
+
+	results := make([]T, len(ids))
+	errs := make([]error, len(ids))
+	events := make([]*ChangeEvent, len(ids))
+	for i, id := range ids {
+		newItem, event, found, err := c.updateItemPartially(ctx, id, data)
+		if found && err == nil {
+			newItem = c.cloneItem(newItem)
+		}
+		results[i], events[i], errs[i] = newItem, event, err
+	}
+
+	if err := c.Save(ctx); err != nil {
+		return results, errs, err
+	}
+	c.notifyChanges(ctx, events...)
+
+	return results, errs, nil
+}
+
+func (c *IdentifiableMemoryPersistence[T, K]) updateItemPartially(ctx context.Context,
+	id K, data cdata.AnyValueMap) (T, *ChangeEvent, bool, error) {
+
 	var defaultObject T
 
 	index := c.GetIndexById(id)
 	if index < 0 {
 		c.Logger.Trace(ctx, "Item %s was not found", id)
-		return defaultObject, nil
+		return defaultObject, nil, false, nil
 	}
 
 	c.Mtx.Lock()
 
+	oldVersion, versioned := GetObjectVersion(c.Items[index])
+	versioned = versioned && c.Versioned
+	if versioned {
+		if version, ok := data.GetAsNullableString("version"); ok {
+			if err := c.checkVersion(ctx, c.Items[index], version); err != nil {
+				c.Mtx.Unlock()
+				return defaultObject, nil, true, err
+			}
+		}
+	}
+
 	newItem := c.cloneItem(c.Items[index])
 
 	if reflect.ValueOf(newItem).Kind() == reflect.Map {
@@ -312,26 +509,42 @@ func (c *IdentifiableMemoryPersistence[T, K]) UpdatePartially(ctx context.Contex
 			newItem = _newItem
 		}
 	}
+	if versioned {
+		newItem = c.setItemVersion(newItem, NextVersion(oldVersion))
+	}
+	newItem = c.trackItem(newItem, false)
 
+	event := c.composeChange(ChangeTypeUpdated, c.Items[index], newItem)
 	c.Items[index] = newItem
 
 	c.Mtx.Unlock()
 	c.Logger.Trace(ctx, "Partially updated item %s", id)
 
-	if err := c.Save(ctx); err != nil {
-		return c.cloneItem(newItem), err
-	}
-
-	return c.cloneItem(newItem), nil
+	return newItem, event, true, nil
 }
 
 // DeleteById a data item by it's unique id.
+// In soft-delete mode the item is marked as deleted instead.
 //
 //	Parameters:
 //		- ctx context.Context execution context to trace execution through call chain.
 //		- id K an id of the item to be deleted
 //	Returns: T, error deleted item or error.
 func (c *IdentifiableMemoryPersistence[T, K]) DeleteById(ctx context.Context, id K) (T, error) {
+	if c.isSoftDelete() {
+		return c.markDeletedById(ctx, id, true)
+	}
+	return c.PurgeById(ctx, id)
+}
+
+// PurgeById physically removes a data item by it's unique id
+// regardless of soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- id K an id of the item to be removed
+//	Returns: T, error removed item or error.
+func (c *IdentifiableMemoryPersistence[T, K]) PurgeById(ctx context.Context, id K) (T, error) {
 
 	var defaultObject T
 
@@ -349,6 +562,7 @@ func (c *IdentifiableMemoryPersistence[T, K]) DeleteById(ctx context.Context, id
 	} else {
 		c.Items = append(c.Items[:index], c.Items[index+1:]...)
 	}
+	event := c.composeChange(ChangeTypeDeleted, oldItem, nil)
 
 	c.Mtx.Unlock()
 
@@ -357,9 +571,53 @@ func (c *IdentifiableMemoryPersistence[T, K]) DeleteById(ctx context.Context, id
 	if err := c.Save(ctx); err != nil {
 		return oldItem, err
 	}
+	c.notifyChanges(ctx, event)
 	return oldItem, nil
 }
 
+// RestoreById restores a data item marked as deleted in soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- id K an id of the item to be restored
+//	Returns: T, error restored item or error.
+func (c *IdentifiableMemoryPersistence[T, K]) RestoreById(ctx context.Context, id K) (T, error) {
+	return c.markDeletedById(ctx, id, false)
+}
+
+func (c *IdentifiableMemoryPersistence[T, K]) markDeletedById(ctx context.Context, id K, deleted bool) (T, error) {
+	var defaultObject T
+
+	index := c.GetIndexById(id)
+	if index < 0 {
+		c.Logger.Trace(ctx, "Item %s was not found", id)
+		return defaultObject, nil
+	}
+
+	changeType := ChangeTypeUpdated
+	if deleted {
+		changeType = ChangeTypeDeleted
+	}
+
+	c.Mtx.Lock()
+	newItem := c.markItemDeleted(c.Items[index], deleted)
+	event := c.composeChange(changeType, c.Items[index], newItem)
+	c.Items[index] = newItem
+	c.Mtx.Unlock()
+
+	if deleted {
+		c.Logger.Trace(ctx, "Marked item %s as deleted", id)
+	} else {
+		c.Logger.Trace(ctx, "Restored item %s", id)
+	}
+
+	if err := c.Save(ctx); err != nil {
+		return c.cloneItem(newItem), err
+	}
+	c.notifyChanges(ctx, event)
+	return c.cloneItem(newItem), nil
+}
+
 // DeleteByIds multiple data items by their unique ids.
 //
 //	Parameters:
@@ -367,17 +625,74 @@ func (c *IdentifiableMemoryPersistence[T, K]) DeleteById(ctx context.Context, id
 //		- ids []K ids of data items to be deleted.
 //	Returns: error or null for success.
 func (c *IdentifiableMemoryPersistence[T, K]) DeleteByIds(ctx context.Context, ids []K) error {
+	deletedItems := make([]T, 0)
 	filterFunc := func(item T) bool {
 		itemId := c.getItemId(item)
 		for _, id := range ids {
 			if c.isEqualIds(itemId, id) {
+				deletedItems = append(deletedItems, item)
 				return true
 			}
 		}
 		return false
 	}
 
-	return c.DeleteByFilter(ctx, filterFunc)
+	if err := c.DeleteByFilter(ctx, filterFunc); err != nil {
+		return err
+	}
+	if !c.hasChangeListeners() {
+		return nil
+	}
+
+	events := make([]*ChangeEvent, len(deletedItems))
+	for i, oldItem := range deletedItems {
+		var newItem any
+		if c.isSoftDelete() {
+			newItem, _ = c.GetOneById(WithDeleted(ctx), c.getItemId(oldItem))
+		}
+		events[i] = c.composeChange(ChangeTypeDeleted, oldItem, newItem)
+	}
+	c.notifyChanges(ctx, events...)
+	return nil
+}
+
+func (c *IdentifiableMemoryPersistence[T, K]) hasChangeListeners() bool {
+	return c.changes != nil && c.changes.HasListeners()
+}
+
+// composeChange creates a change event with copies of the items when there are listeners to receive it.
+func (c *IdentifiableMemoryPersistence[T, K]) composeChange(changeType string, oldItem any, newItem any) *ChangeEvent {
+	if !c.hasChangeListeners() {
+		return nil
+	}
+
+	var id K
+	if newItem != nil {
+		id = c.getItemId(newItem)
+		newItem = c.cloneItem(newItem)
+	}
+	if oldItem != nil {
+		id = c.getItemId(oldItem)
+		oldItem = c.cloneItem(oldItem)
+	}
+	return NewChangeEvent(changeType, "", id, oldItem, newItem)
+}
+
+// notifyChanges delivers change events to listeners and logs their errors.
+func (c *IdentifiableMemoryPersistence[T, K]) notifyChanges(ctx context.Context, events ...*ChangeEvent) {
+	if !c.hasChangeListeners() {
+		return
+	}
+
+	pending := make([]*ChangeEvent, 0, len(events))
+	for _, event := range events {
+		if event != nil {
+			pending = append(pending, event)
+		}
+	}
+	if err := c.changes.Notify(ctx, pending...); err != nil {
+		c.Logger.Error(ctx, err, "Failed to notify listeners about changes")
+	}
 }
 
 func (c *IdentifiableMemoryPersistence[T, K]) isEqualIds(idA, idB any) bool {
@@ -406,6 +721,24 @@ func (c *IdentifiableMemoryPersistence[T, K]) setItemId(item any, id any) any {
 	return item
 }
 
+func (c *IdentifiableMemoryPersistence[T, K]) checkVersion(ctx context.Context, oldItem T, version string) error {
+	if oldVersion, _ := GetObjectVersion(oldItem); oldVersion != version {
+		id := c.getItemId(oldItem)
+		c.Logger.Trace(ctx, "Item %s has version %s instead of %s", id, oldVersion, version)
+		return NewVersionConflictError(cctx.GetTraceId(ctx), id)
+	}
+	return nil
+}
+
+func (c *IdentifiableMemoryPersistence[T, K]) setItemVersion(item T, version string) T {
+	var obj any = item
+	SetObjectVersion(&obj, version)
+	if result, ok := obj.(T); ok {
+		return result
+	}
+	return item
+}
+
 func (c *IdentifiableMemoryPersistence[T, K]) isEmptyId(id any) bool {
 	if _id, ok := id.(data.IIdentifier[K]); ok {
 		return _id.Empty()
@@ -0,0 +1,278 @@
+package persistence
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+)
+
+// ComposeKeysetFilter creates a filter expression that selects items located after
+// the item encoded in the paging token according to the sort order.
+// For sort fields f1, f2 and token values v1, v2 the result is
+// (f1 > v1) OR (f1 = v1 AND f2 > v2), where ">" turns into "<" for descending fields.
+// Nulls are treated as the smallest values: they go first in ascending
+// and last in descending order, so queries must sort them the same way (see SqlFilterCompiler.CompileSort).
+//
+//	Parameters:
+//		- token a token received from a previous page or empty string for the first page.
+//		- sort sort parameters. The last field must be unique to get stable pages.
+//	Returns: a filter expression (empty for the first page) or BadRequestError
+//		if sort parameters or the token are invalid.
+func ComposeKeysetFilter(token string, sort cquery.SortParams) (*FilterExpression, error) {
+	values, err := ParseKeysetToken(token, sort)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		return NewFilterAnd(), nil
+	}
+
+	result := NewFilterOr()
+	for i, field := range sort {
+		group := NewFilterAnd()
+		for j := 0; j < i; j++ {
+			group.Children = append(group.Children, composeKeysetEqual(sort[j].Name, values[j]))
+		}
+		operator := FilterMore
+		if !field.Ascending {
+			operator = FilterLess
+		}
+		switch {
+		case values[i] == nil && field.Ascending:
+			// Nulls go first in ascending order, so all non-null values follow them
+			group.Children = append(group.Children, NewFilterCondition(field.Name, FilterIsNotNull))
+		case values[i] == nil:
+			// Nulls go last in descending order, so nothing follows them
+			continue
+		case field.Ascending:
+			group.Children = append(group.Children, NewFilterCondition(field.Name, operator, values[i]))
+		default:
+			// Nulls follow all non-null values in descending order
+			group.Children = append(group.Children, NewFilterOr(
+				NewFilterCondition(field.Name, operator, values[i]),
+				NewFilterCondition(field.Name, FilterIsNull),
+			))
+		}
+		result.Children = append(result.Children, group)
+	}
+
+	if len(result.Children) == 0 {
+		// Nothing can follow the last item
+		return NewFilterCondition(sort[0].Name, FilterIn), nil
+	}
+	return result, nil
+}
+
+// ParseKeysetToken validates sort parameters and decodes sort key values from a paging token.
+//
+//	Parameters:
+//		- token a token received from a previous page or empty string for the first page.
+//		- sort sort parameters used to create the token.
+//	Returns: decoded values, nil for the first page, or BadRequestError
+//		if sort parameters or the token are invalid.
+func ParseKeysetToken(token string, sort cquery.SortParams) ([]any, error) {
+	if len(sort) == 0 {
+		return nil, cerr.NewBadRequestError("", "INVALID_SORT", "Keyset paging requires sort fields")
+	}
+	for _, field := range sort {
+		if !filterFieldRegex.MatchString(field.Name) {
+			return nil, cerr.NewBadRequestError("", "INVALID_SORT",
+				"Sort field "+field.Name+" is not a valid name").
+				WithDetails("field", field.Name)
+		}
+	}
+
+	values, err := cquery.DecodeKeysetToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if values != nil && len(values) != len(sort) {
+		return nil, cerr.NewBadRequestError("", "INVALID_TOKEN",
+			"Paging token does not match sort parameters")
+	}
+	return values, nil
+}
+
+func composeKeysetEqual(field string, value any) *FilterExpression {
+	if value == nil {
+		return NewFilterCondition(field, FilterIsNull)
+	}
+	return NewFilterCondition(field, FilterEqual, value)
+}
+
+// ComposeKeysetToken creates a paging token from an item, usually the last item in a page.
+//
+//	Parameters:
+//		- item the item converted into a map with field names used in sort parameters.
+//		- sort sort parameters.
+//	Returns: an opaque token or error.
+func ComposeKeysetToken(item map[string]any, sort cquery.SortParams) (string, error) {
+	return cquery.EncodeKeysetToken(GetKeysetValues(item, sort))
+}
+
+// ComposeKeysetTokenFromJson creates a paging token from an item serialized into JSON.
+// Unlike conversion into a map with float64 numbers, it keeps exact values of large integers.
+//
+//	Parameters:
+//		- item the item serialized into a JSON object.
+//		- sort sort parameters.
+//	Returns: an opaque token or error.
+func ComposeKeysetTokenFromJson(item string, sort cquery.SortParams) (string, error) {
+	values, err := parseKeysetItem(item)
+	if err != nil {
+		return cquery.EmptyTokenValue, err
+	}
+	return ComposeKeysetToken(values, sort)
+}
+
+// parseKeysetItem converts an item serialized into JSON into a map.
+// Integer numbers are restored as int64 or uint64 the same way as in paging tokens.
+func parseKeysetItem(item string) (map[string]any, error) {
+	var result map[string]any
+	decoder := json.NewDecoder(bytes.NewReader([]byte(item)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	return decodeKeysetNumbers(result).(map[string]any), nil
+}
+
+func decodeKeysetNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		return cquery.DecodeKeysetNumber(v)
+	case map[string]any:
+		for key, item := range v {
+			v[key] = decodeKeysetNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = decodeKeysetNumbers(item)
+		}
+	}
+	return value
+}
+
+// GetKeysetValues extracts values of sort fields from an item.
+// Nested fields can be referenced using dot notation: "field1.field2".
+//
+//	Parameters:
+//		- item the item converted into a map.
+//		- sort sort parameters.
+//	Returns: values of the sort fields in the same order.
+func GetKeysetValues(item map[string]any, sort cquery.SortParams) []any {
+	values := make([]any, len(sort))
+	for i, field := range sort {
+		var value any = item
+		for _, name := range strings.Split(field.Name, ".") {
+			if m, ok := value.(map[string]any); ok {
+				value = m[name]
+			} else {
+				value = nil
+				break
+			}
+		}
+		values[i] = value
+	}
+	return values
+}
+
+// AddKeysetSortField appends a unique field to sort parameters when it is not there yet,
+// so pages created by keyset paging do not skip or repeat items with equal sort values.
+//
+//	Parameters:
+//		- sort sort parameters.
+//		- name a name of a unique field, usually "id".
+//	Returns: new sort parameters.
+func AddKeysetSortField(sort cquery.SortParams, name string) cquery.SortParams {
+	result := make(cquery.SortParams, 0, len(sort)+1)
+	for _, field := range sort {
+		result = append(result, field)
+		if field.Name == name {
+			return sort
+		}
+	}
+	return append(result, cquery.NewSortField(name, true))
+}
+
+// CompareKeysetValues compares two values of sort fields. Nulls are less than any other values,
+// numbers and date-times are compared by their values and other types as strings.
+//
+//	Parameters:
+//		- value1 the first value.
+//		- value2 the second value.
+//	Returns: -1, 0 or 1 when value1 is less, equal or greater than value2.
+func CompareKeysetValues(value1 any, value2 any) int {
+	if value1 == nil || value2 == nil {
+		switch {
+		case value1 == nil && value2 == nil:
+			return 0
+		case value1 == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	if isKeysetNumber(value1) && isKeysetNumber(value2) {
+		// Integers are compared exactly as float64 loses precision above 2^53
+		int1, ok1 := toKeysetInteger(value1)
+		int2, ok2 := toKeysetInteger(value2)
+		if ok1 && ok2 {
+			return compareKeysetOrdered(int1, int2)
+		}
+		return compareKeysetOrdered(convert.DoubleConverter.ToDouble(value1), convert.DoubleConverter.ToDouble(value2))
+	}
+
+	_, isTime1 := value1.(time.Time)
+	_, isTime2 := value2.(time.Time)
+	if isTime1 || isTime2 {
+		time1, ok1 := convert.DateTimeConverter.ToNullableDateTime(value1)
+		time2, ok2 := convert.DateTimeConverter.ToNullableDateTime(value2)
+		if ok1 && ok2 {
+			return time1.Compare(time2)
+		}
+	}
+
+	return compareKeysetOrdered(convert.StringConverter.ToString(value1), convert.StringConverter.ToString(value2))
+}
+
+func isKeysetNumber(value any) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func toKeysetInteger(value any) (int64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() <= math.MaxInt64 {
+			return int64(v.Uint()), true
+		}
+	}
+	return 0, false
+}
+
+func compareKeysetOrdered[V int64 | float64 | string](value1 V, value2 V) int {
+	if value1 < value2 {
+		return -1
+	}
+	if value1 > value2 {
+		return 1
+	}
+	return 0
+}
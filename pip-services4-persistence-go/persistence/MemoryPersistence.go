@@ -61,7 +61,12 @@ type MemoryPersistence[T any] struct {
 	Mtx         sync.RWMutex
 	opened      bool
 	MaxPageSize int
-	convertor   convert.IJSONEngine[T]
+	// SoftDelete turns on logical deletion for items that implement ITrackable.
+	// Deleted items are marked as deleted and excluded from read operations
+	// unless the context is created by WithDeleted. Create and change times
+	// of items that implement IChangeable are stamped automatically.
+	SoftDelete bool
+	convertor  convert.IJSONEngine[T]
 }
 
 // NewMemoryPersistence creates a new instance of the MemoryPersistence
@@ -195,6 +200,8 @@ func (c *MemoryPersistence[T]) GetPageByFilter(ctx context.Context,
 	sortFunc func(T, T) bool,
 	selectFunc func(T) T) (cquery.DataPage[T], error) {
 
+	filterFunc = c.filterDeleted(ctx, filterFunc)
+
 	c.Mtx.RLock()
 	defer c.Mtx.RUnlock()
 
@@ -249,6 +256,105 @@ func (c *MemoryPersistence[T]) GetPageByFilter(ctx context.Context,
 	return *cquery.NewDataPage[T](items, int(total)), nil
 }
 
+// GetPageByFilterWithToken gets a page of data items retrieved by a given filter and sorted
+// according to sort parameters using keyset paging. Instead of skipping items the page starts
+// right after the item encoded in the paging token, and the returned page contains a token
+// to get the next page. The token is empty when there are no more items.
+// This method shall be called by a func (c* IdentifiableMemoryPersistence)
+// GetPageByFilterWithToken method from child struct that
+// receives FilterParams and converts them into a filter function.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- filter func(T) bool (optional) a filter function to filter items
+//		- paging cquery.TokenizedPagingParams paging parameters with a token from the previous page
+//		- sortParams cquery.SortParams sort parameters, the last field must be unique
+//		- selectFunc func(in T}) (out interface{}) (optional) projection parameters
+//	Return cquery.TokenizedDataPage[T], error data page or error.
+func (c *MemoryPersistence[T]) GetPageByFilterWithToken(ctx context.Context,
+	filterFunc func(T) bool,
+	paging cquery.TokenizedPagingParams,
+	sortParams cquery.SortParams,
+	selectFunc func(T) T) (cquery.TokenizedDataPage[T], error) {
+
+	lastKeys, err := ParseKeysetToken(paging.Token, sortParams)
+	if err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+	filterFunc = c.filterDeleted(ctx, filterFunc)
+
+	c.Mtx.RLock()
+	defer c.Mtx.RUnlock()
+
+	type keyedItem struct {
+		item T
+		keys []any
+	}
+
+	items := make([]keyedItem, 0, len(c.Items))
+	for _, v := range c.Items {
+		if filterFunc != nil && !filterFunc(v) {
+			continue
+		}
+		keys := GetKeysetValues(c.toKeysetMap(v), sortParams)
+		if lastKeys != nil && compareKeysetKeys(keys, lastKeys, sortParams) <= 0 {
+			continue
+		}
+		items = append(items, keyedItem{item: v, keys: keys})
+	}
+
+	localSort := sorter[keyedItem]{items: items, compFunc: func(a, b keyedItem) bool {
+		return compareKeysetKeys(a.keys, b.keys, sortParams) < 0
+	}}
+	sort.Sort(localSort)
+
+	take := paging.GetTake((int64)(c.MaxPageSize))
+	token := cquery.EmptyTokenValue
+	if (int64)(len(items)) > take {
+		items = items[:take]
+		token, err = cquery.EncodeKeysetToken(items[len(items)-1].keys)
+		if err != nil {
+			return *cquery.NewEmptyTokenizedDataPage[T](), err
+		}
+	}
+
+	result := make([]T, len(items))
+	for i, v := range items {
+		result[i] = c.cloneItem(v.item)
+		if selectFunc != nil {
+			result[i] = selectFunc(result[i])
+		}
+	}
+
+	c.Logger.Trace(ctx, "Retrieved %d items", len(result))
+
+	return *cquery.NewTokenizedDataPage[T](token, result), nil
+}
+
+func compareKeysetKeys(keys1 []any, keys2 []any, sort cquery.SortParams) int {
+	for i, field := range sort {
+		result := CompareKeysetValues(keys1[i], keys2[i])
+		if !field.Ascending {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+func (c *MemoryPersistence[T]) toKeysetMap(item T) map[string]any {
+	strObject, _ := c.convertor.ToJson(item)
+	result, _ := parseKeysetItem(strObject)
+	return result
+}
+
+func (c *MemoryPersistence[T]) toMap(item T) map[string]any {
+	strObject, _ := c.convertor.ToJson(item)
+	return convert.JsonConverter.ToMap(strObject)
+}
+
 // GetListByFilter gets a list of data items retrieved by a given filter and sorted according to sort parameters.
 // This method shall be called by a func (c * IdentifiableMemoryPersistence)
 // GetListByFilter method from child struct that
@@ -266,6 +372,8 @@ func (c *MemoryPersistence[T]) GetListByFilter(ctx context.Context,
 	sortFunc func(T, T) bool,
 	selectFunc func(T) T) ([]T, error) {
 
+	filterFunc = c.filterDeleted(ctx, filterFunc)
+
 	c.Mtx.RLock()
 	defer c.Mtx.RUnlock()
 
@@ -318,6 +426,8 @@ func (c *MemoryPersistence[T]) GetListByFilter(ctx context.Context,
 func (c *MemoryPersistence[T]) GetOneRandom(ctx context.Context,
 	filterFunc func(T) bool) (T, error) {
 
+	filterFunc = c.filterDeleted(ctx, filterFunc)
+
 	c.Mtx.RLock()
 	defer c.Mtx.RUnlock()
 
@@ -363,6 +473,8 @@ func (c *MemoryPersistence[T]) GetOneRandom(ctx context.Context,
 //	Returns: T, error created item or error.
 func (c *MemoryPersistence[T]) Create(ctx context.Context, item T) (T, error) {
 
+	item = c.trackItem(item, true)
+
 	c.Mtx.Lock()
 
 	c.Items = append(c.Items, c.cloneItem(item))
@@ -379,6 +491,7 @@ func (c *MemoryPersistence[T]) Create(ctx context.Context, item T) (T, error) {
 }
 
 // DeleteByFilter data items that match to a given filter.
+// In soft-delete mode the items are marked as deleted instead.
 // this method shall be called by a func (c* IdentifiableMemoryPersistence)
 // DeleteByFilter method from child struct that
 // receives FilterParams and converts them into a filter function.
@@ -390,6 +503,44 @@ func (c *MemoryPersistence[T]) Create(ctx context.Context, item T) (T, error) {
 func (c *MemoryPersistence[T]) DeleteByFilter(ctx context.Context,
 	filterFunc func(T) bool) error {
 
+	if !c.isSoftDelete() {
+		return c.removeByFilter(ctx, filterFunc)
+	}
+
+	c.Mtx.Lock()
+
+	deleted := 0
+	for i, item := range c.Items {
+		if !GetObjectDeleted(item) && filterFunc(item) {
+			c.Items[i] = c.markItemDeleted(item, true)
+			deleted++
+		}
+	}
+	c.Mtx.Unlock()
+
+	if deleted == 0 {
+		return nil
+	}
+
+	c.Logger.Trace(ctx, "Marked %d items as deleted", deleted)
+
+	return c.Save(ctx)
+}
+
+// PurgeDeleted physically removes items marked as deleted in soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//	Returns: error or nil for success.
+func (c *MemoryPersistence[T]) PurgeDeleted(ctx context.Context) error {
+	return c.removeByFilter(ctx, func(item T) bool {
+		return GetObjectDeleted(item)
+	})
+}
+
+func (c *MemoryPersistence[T]) removeByFilter(ctx context.Context,
+	filterFunc func(T) bool) error {
+
 	c.Mtx.Lock()
 
 	deleted := 0
@@ -428,6 +579,8 @@ func (c *MemoryPersistence[T]) DeleteByFilter(ctx context.Context,
 func (c *MemoryPersistence[T]) GetCountByFilter(ctx context.Context,
 	filterFunc func(T) bool) (int64, error) {
 
+	filterFunc = c.filterDeleted(ctx, filterFunc)
+
 	c.Mtx.RLock()
 	defer c.Mtx.RUnlock()
 
@@ -445,6 +598,40 @@ func (c *MemoryPersistence[T]) GetCountByFilter(ctx context.Context,
 	return count, nil
 }
 
+// GetAggregateByFilter calculates aggregates over data items retrieved by a given filter
+// and grouped by the group fields. The result rows are sorted by the group fields.
+// This method shall be called by a func (c * IdentifiableMemoryPersistence)
+// GetAggregateByFilter method from child struct that
+// receives FilterParams and converts them into a filter function.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- filter func(T) bool (optional) a filter function to filter items
+//		- params cquery.AggregateParams group fields and aggregate functions
+//	Return []cquery.AggregateRow, error result rows or error.
+func (c *MemoryPersistence[T]) GetAggregateByFilter(ctx context.Context,
+	filterFunc func(T) bool, params cquery.AggregateParams) ([]cquery.AggregateRow, error) {
+
+	if err := ValidateAggregateParams(params); err != nil {
+		return nil, err
+	}
+
+	filterFunc = c.filterDeleted(ctx, filterFunc)
+
+	c.Mtx.RLock()
+	items := make([]map[string]any, 0, len(c.Items))
+	for _, v := range c.Items {
+		if filterFunc == nil || filterFunc(v) {
+			items = append(items, c.toMap(v))
+		}
+	}
+	c.Mtx.RUnlock()
+
+	rows := aggregateItems(items, params)
+	c.Logger.Trace(ctx, "Aggregated %d items into %d rows", len(items), len(rows))
+	return rows, nil
+}
+
 func (c *MemoryPersistence[T]) cloneItem(item any) T {
 	if cloneableItem, ok := item.(data.ICloneable[T]); ok {
 		return cloneableItem.Clone()
@@ -454,3 +641,39 @@ func (c *MemoryPersistence[T]) cloneItem(item any) T {
 	newItem, _ := c.convertor.FromJson(strObject)
 	return newItem
 }
+
+func (c *MemoryPersistence[T]) isSoftDelete() bool {
+	return c.SoftDelete && IsTrackableType[T]()
+}
+
+// filterDeleted adds exclusion of logically deleted items to the filter.
+func (c *MemoryPersistence[T]) filterDeleted(ctx context.Context, filterFunc func(T) bool) func(T) bool {
+	if !c.isSoftDelete() || IsDeletedIncluded(ctx) {
+		return filterFunc
+	}
+	return func(item T) bool {
+		return !GetObjectDeleted(item) && (filterFunc == nil || filterFunc(item))
+	}
+}
+
+// trackItem stamps create and change times in soft-delete mode.
+func (c *MemoryPersistence[T]) trackItem(item T, created bool) T {
+	if !c.SoftDelete {
+		return item
+	}
+	var obj any = item
+	TrackObjectChange(&obj, created, time.Now().UTC())
+	if result, ok := obj.(T); ok {
+		return result
+	}
+	return item
+}
+
+func (c *MemoryPersistence[T]) markItemDeleted(item T, deleted bool) T {
+	var obj any = c.cloneItem(item)
+	SetObjectDeleted(&obj, deleted)
+	if result, ok := obj.(T); ok {
+		return c.trackItem(result, false)
+	}
+	return item
+}
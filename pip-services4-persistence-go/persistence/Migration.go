@@ -0,0 +1,205 @@
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+)
+
+// MigrationLatestVersion is a target version that migrates database to the latest registered migration.
+const MigrationLatestVersion int64 = -1
+
+// Migration is a versioned change of database schema.
+// Up script applies the change and optional Down script reverts it.
+type Migration struct {
+	// Unique positive version. Migrations are applied in ascending order of versions.
+	Version int64
+	// Human readable description.
+	Description string
+	// SQL script that applies the change.
+	Up string
+	// SQL script that reverts the change. Empty if the migration can not be reverted.
+	Down string
+}
+
+// NewMigration creates a new migration.
+//
+//	Parameters:
+//		- version a unique positive version.
+//		- description a human readable description.
+//		- up a script that applies the change.
+//		- down (optional) a script that reverts the change.
+//	Returns: a new migration.
+func NewMigration(version int64, description string, up string, down string) *Migration {
+	return &Migration{
+		Version:     version,
+		Description: description,
+		Up:          up,
+		Down:        down,
+	}
+}
+
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_(.*)\.(up|down)\.sql$`)
+
+// LoadMigrationsFromDirectory loads migrations from SQL files in a directory.
+// Files must be named as <version>_<description>.up.sql and <version>_<description>.down.sql.
+// Other files are ignored.
+//
+//	Parameters:
+//		- dir a path to the directory.
+//	Returns: migrations sorted by versions or error if the files can not be read.
+func LoadMigrationsFromDirectory(dir string) ([]*Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, cerr.NewFileError("", "READ_FAILED", "Failed to read migrations from "+dir).
+			WithDetails("path", dir).WithCause(err)
+	}
+
+	migrations := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, cerr.NewConfigError("", "INVALID_MIGRATION", "Invalid migration version in "+entry.Name()).
+				WithDetails("file", entry.Name()).WithCause(err)
+		}
+		buf, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, cerr.NewFileError("", "READ_FAILED", "Failed to read migration "+entry.Name()).
+				WithDetails("file", entry.Name()).WithCause(err)
+		}
+
+		migration, ok := migrations[version]
+		if !ok {
+			migration = NewMigration(version, strings.ReplaceAll(match[2], "_", " "), "", "")
+			migrations[version] = migration
+		}
+		if match[3] == "up" {
+			migration.Up = string(buf)
+		} else {
+			migration.Down = string(buf)
+		}
+	}
+
+	result := make([]*Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		if migration.Up == "" {
+			return nil, cerr.NewConfigError("", "INVALID_MIGRATION",
+				"Migration "+strconv.FormatInt(migration.Version, 10)+" has no up script").
+				WithDetails("version", migration.Version)
+		}
+		result = append(result, migration)
+	}
+	sortMigrations(result)
+	return result, nil
+}
+
+// ValidateMigrations checks that migrations have unique positive versions and up scripts.
+//
+//	Parameters:
+//		- migrations migrations to validate.
+//	Returns: ConfigError if migrations are invalid or nil otherwise.
+func ValidateMigrations(migrations []*Migration) error {
+	versions := make(map[int64]bool, len(migrations))
+	for _, migration := range migrations {
+		version := strconv.FormatInt(migration.Version, 10)
+		if migration.Version <= 0 {
+			return cerr.NewConfigError("", "INVALID_MIGRATION", "Migration version "+version+" must be positive").
+				WithDetails("version", migration.Version)
+		}
+		if versions[migration.Version] {
+			return cerr.NewConfigError("", "DUPLICATE_MIGRATION", "Migration "+version+" is registered twice").
+				WithDetails("version", migration.Version)
+		}
+		if strings.TrimSpace(migration.Up) == "" {
+			return cerr.NewConfigError("", "INVALID_MIGRATION", "Migration "+version+" has no up script").
+				WithDetails("version", migration.Version)
+		}
+		versions[migration.Version] = true
+	}
+	return nil
+}
+
+// PlanMigrations calculates migrations to move database from applied versions to the target version.
+// Up migrations are returned in ascending order and down migrations in descending order.
+//
+//	Parameters:
+//		- migrations registered migrations.
+//		- applied versions of migrations already applied to the database.
+//		- target a target version or MigrationLatestVersion to apply all registered migrations.
+//	Returns: migrations to apply, migrations to revert or error if migrations are invalid
+//		or some of the applied migrations can not be reverted.
+func PlanMigrations(migrations []*Migration, applied []int64, target int64) (up []*Migration, down []*Migration, err error) {
+	if err = ValidateMigrations(migrations); err != nil {
+		return nil, nil, err
+	}
+
+	sorted := make([]*Migration, len(migrations))
+	copy(sorted, migrations)
+	sortMigrations(sorted)
+
+	if target == MigrationLatestVersion {
+		target = 0
+		if len(sorted) > 0 {
+			target = sorted[len(sorted)-1].Version
+		}
+	}
+
+	registered := make(map[int64]*Migration, len(sorted))
+	for _, migration := range sorted {
+		registered[migration.Version] = migration
+	}
+	appliedSet := make(map[int64]bool, len(applied))
+	for _, version := range applied {
+		appliedSet[version] = true
+	}
+
+	up = make([]*Migration, 0)
+	for _, migration := range sorted {
+		if migration.Version <= target && !appliedSet[migration.Version] {
+			up = append(up, migration)
+		}
+	}
+
+	reverted := make([]int64, 0)
+	for _, version := range applied {
+		if version > target {
+			reverted = append(reverted, version)
+		}
+	}
+	sort.Slice(reverted, func(i, j int) bool { return reverted[i] > reverted[j] })
+
+	down = make([]*Migration, 0)
+	for _, version := range reverted {
+		migration, ok := registered[version]
+		if !ok {
+			return nil, nil, cerr.NewConfigError("", "UNKNOWN_MIGRATION",
+				"Applied migration "+strconv.FormatInt(version, 10)+" is not registered").
+				WithDetails("version", version)
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			return nil, nil, cerr.NewConfigError("", "IRREVERSIBLE_MIGRATION",
+				"Migration "+strconv.FormatInt(version, 10)+" has no down script").
+				WithDetails("version", version)
+		}
+		down = append(down, migration)
+	}
+
+	return up, down, nil
+}
+
+func sortMigrations(migrations []*Migration) {
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+}
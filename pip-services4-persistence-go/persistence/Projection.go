@@ -0,0 +1,104 @@
+package persistence
+
+import (
+	"strings"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+)
+
+// ProjectionField is a node of a projection tree composed from ProjectionParams.
+// A field without children is included as a whole, a field with children
+// includes only the listed subfields.
+type ProjectionField struct {
+	// Name of the field inside its parent object
+	Name string
+	// Path is a full dot separated path to the field, e.g. "field2.field21"
+	Path string
+	// Children are the included subfields
+	Children []*ProjectionField
+}
+
+// ComposeProjectionFields converts projection parameters into a tree of fields.
+// Both dot and nested formats are supported, e.g. "field1,field2.field21" and "field1,field2(field21)".
+// When a field is included as a whole its subfields are ignored.
+//
+//	Parameters:
+//		- projection projection parameters, nil or empty to include all fields.
+//	Returns: a list of top level fields (empty to include all fields) or BadRequestError
+//		if a field name is invalid.
+func ComposeProjectionFields(projection *cquery.ProjectionParams) ([]*ProjectionField, error) {
+	if projection == nil || projection.Len() == 0 {
+		return []*ProjectionField{}, nil
+	}
+
+	root := &ProjectionField{Children: []*ProjectionField{}}
+	for _, path := range cquery.ParseProjectionParams(projection.Value()...).Value() {
+		path = strings.ReplaceAll(path, " ", "")
+		if !filterFieldRegex.MatchString(path) || strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+			return nil, cerr.NewBadRequestError("", "INVALID_PROJECTION",
+				"Projection field "+path+" is not a valid name").
+				WithDetails("field", path)
+		}
+		root.include(strings.Split(path, "."))
+	}
+	return root.Children, nil
+}
+
+// ComposeProjectionPaths converts projection parameters into a list of full paths
+// to the included fields, e.g. "field1,field2(field21)" into ["field1", "field2.field21"].
+//
+//	Parameters:
+//		- projection projection parameters, nil or empty to include all fields.
+//	Returns: a list of paths (empty to include all fields) or BadRequestError
+//		if a field name is invalid.
+func ComposeProjectionPaths(projection *cquery.ProjectionParams) ([]string, error) {
+	fields, err := ComposeProjectionFields(projection)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(fields))
+	var collect func(fields []*ProjectionField)
+	collect = func(fields []*ProjectionField) {
+		for _, field := range fields {
+			if len(field.Children) == 0 {
+				paths = append(paths, field.Path)
+			} else {
+				collect(field.Children)
+			}
+		}
+	}
+	collect(fields)
+	return paths, nil
+}
+
+func (c *ProjectionField) include(names []string) {
+	var field *ProjectionField
+	for _, child := range c.Children {
+		if child.Name == names[0] {
+			field = child
+			break
+		}
+	}
+
+	if field == nil {
+		path := names[0]
+		if c.Path != "" {
+			path = c.Path + "." + path
+		}
+		field = &ProjectionField{Name: names[0], Path: path}
+		if len(names) > 1 {
+			field.Children = []*ProjectionField{}
+		}
+		c.Children = append(c.Children, field)
+	} else if field.Children == nil {
+		// The field is already included as a whole
+		return
+	}
+
+	if len(names) == 1 {
+		field.Children = nil
+		return
+	}
+	field.include(names[1:])
+}
@@ -0,0 +1,320 @@
+package persistence
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+)
+
+// ISqlFilterDialect defines database specific syntax used by SqlFilterCompiler.
+type ISqlFilterDialect interface {
+	// FormatField converts a field name into a column reference,
+	// e.g. "key" into "\"key\"" or into a JSON path extraction.
+	FormatField(field string) string
+	// FormatParameter returns a placeholder for a query parameter with 1-based index,
+	// e.g. "$1", "?" or "@p1".
+	FormatParameter(index int) string
+}
+
+// ISqlComparisonDialect is an optional interface of ISqlFilterDialect for databases
+// that need to convert fields before they are compared with values,
+// e.g. fields extracted from JSON columns as text that must be compared as numbers.
+type ISqlComparisonDialect interface {
+	// FormatComparison converts a field compared by the operator with the given values into SQL expression.
+	// It is used for all operators except "is_null" and "is_not_null".
+	FormatComparison(field string, operator FilterOperator, values []any) string
+}
+
+// ISqlSortDialect is an optional interface of ISqlFilterDialect for databases
+// that need a special syntax to sort by a field or do not sort nulls as the smallest values.
+type ISqlSortDialect interface {
+	// FormatSort converts a sort field into ORDER BY expression. Nulls must go first
+	// in ascending and last in descending order, as keyset filters expect,
+	// e.g. "\"key\" DESC NULLS LAST".
+	FormatSort(field string, ascending bool) string
+}
+
+// ISqlProjectionDialect is an optional interface of ISqlFilterDialect for databases
+// that need a special syntax to select a subset of fields, e.g. tables that keep data in JSON columns.
+type ISqlProjectionDialect interface {
+	// FormatProjection converts a tree of included fields into a SELECT column list.
+	FormatProjection(fields []*ProjectionField) string
+}
+
+// ISqlAggregateDialect is an optional interface of ISqlFilterDialect for databases
+// that need to convert values before they are aggregated, e.g. fields extracted from JSON columns as text.
+type ISqlAggregateDialect interface {
+	// FormatAggregate converts an aggregate function with an optional field into SQL expression,
+	// e.g. "SUM(\"amount\")".
+	FormatAggregate(function string, field string) string
+}
+
+// SqlFilterCompiler compiles FilterExpression into a parameterized SQL condition.
+// Field names are validated and quoted by the dialect, and all values are passed
+// as query parameters, so the result is safe to use in WHERE clauses.
+//
+//	Example:
+//		compiler := NewSqlFilterCompiler(dialect)
+//		filter, _ := NewFilterExpressionFromParams(*cquery.NewFilterParamsFromTuples("key__in", "A,B"))
+//		where, args, err := compiler.Compile(filter, 1)
+//		// where = "\"key\" IN ($1,$2)", args = ["A", "B"]
+type SqlFilterCompiler struct {
+	Dialect ISqlFilterDialect
+}
+
+// NewSqlFilterCompiler creates a new instance of the compiler.
+//
+//	Parameters:
+//		- dialect a database specific dialect.
+//	Returns: a new compiler.
+func NewSqlFilterCompiler(dialect ISqlFilterDialect) *SqlFilterCompiler {
+	return &SqlFilterCompiler{
+		Dialect: dialect,
+	}
+}
+
+// Compile converts a filter expression into SQL condition and a list of query parameters.
+//
+//	Parameters:
+//		- filter a filter expression to compile.
+//		- startIndex an index of the first generated parameter (usually 1).
+//	Returns: SQL condition (empty when there is nothing to filter), query parameters or error.
+func (c *SqlFilterCompiler) Compile(filter *FilterExpression, startIndex int) (string, []any, error) {
+	if filter.IsEmpty() {
+		return "", []any{}, nil
+	}
+	if err := filter.Validate(); err != nil {
+		return "", nil, err
+	}
+
+	args := make([]any, 0)
+	condition := c.compileExpression(filter, startIndex, &args)
+	return condition, args, nil
+}
+
+// CompileSort converts sort parameters into SQL ORDER BY expression.
+// Nulls are sorted as the smallest values to match filters created by ComposeKeysetFilter.
+// By default it relies on databases that sort nulls this way (MySQL, SQLite, SQL Server),
+// other databases shall implement ISqlSortDialect.
+//
+//	Parameters:
+//		- sort sort parameters.
+//	Returns: a list of sorted fields, e.g. "\"key\" DESC,\"id\" ASC" or error if a field name is invalid.
+func (c *SqlFilterCompiler) CompileSort(sort cquery.SortParams) (string, error) {
+	fields := make([]string, len(sort))
+	for i, field := range sort {
+		if !filterFieldRegex.MatchString(field.Name) {
+			return "", cerr.NewBadRequestError("", "INVALID_SORT",
+				"Sort field "+field.Name+" is not a valid name").
+				WithDetails("field", field.Name)
+		}
+		if dialect, ok := c.Dialect.(ISqlSortDialect); ok {
+			fields[i] = dialect.FormatSort(field.Name, field.Ascending)
+			continue
+		}
+		fields[i] = c.Dialect.FormatField(field.Name)
+		if field.Ascending {
+			fields[i] += " ASC"
+		} else {
+			fields[i] += " DESC"
+		}
+	}
+	return strings.Join(fields, ","), nil
+}
+
+// CompileProjection converts projection parameters into SQL SELECT column list.
+// By default subfields select the entire top level column, dialects that implement
+// ISqlProjectionDialect can extract the subfields.
+//
+//	Parameters:
+//		- projection projection parameters, nil or empty to select all columns.
+//	Returns: a list of columns, e.g. "\"id\",\"key\"" (empty to select all columns)
+//		or error if a field name is invalid.
+func (c *SqlFilterCompiler) CompileProjection(projection *cquery.ProjectionParams) (string, error) {
+	fields, err := ComposeProjectionFields(projection)
+	if err != nil || len(fields) == 0 {
+		return "", err
+	}
+
+	if dialect, ok := c.Dialect.(ISqlProjectionDialect); ok {
+		return dialect.FormatProjection(fields), nil
+	}
+
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		columns[i] = c.Dialect.FormatField(field.Name)
+	}
+	return strings.Join(columns, ","), nil
+}
+
+// CompileAggregate converts aggregate parameters into SQL SELECT column list and GROUP BY expression.
+// The columns contain the group fields followed by the aggregates in the order they are defined,
+// so they can be read by ComposeAggregateRow.
+//
+//	Parameters:
+//		- params aggregate parameters.
+//	Returns: a list of columns, a list of group fields (empty when there are no groups)
+//		or BadRequestError if the parameters are invalid.
+func (c *SqlFilterCompiler) CompileAggregate(params cquery.AggregateParams) (string, string, error) {
+	if err := ValidateAggregateParams(params); err != nil {
+		return "", "", err
+	}
+
+	groups := make([]string, len(params.GroupBy))
+	for i, field := range params.GroupBy {
+		groups[i] = c.Dialect.FormatField(field)
+	}
+
+	columns := append(make([]string, 0, len(groups)+len(params.Fields)), groups...)
+	for _, field := range params.Fields {
+		if dialect, ok := c.Dialect.(ISqlAggregateDialect); ok {
+			columns = append(columns, dialect.FormatAggregate(field.Function, field.Field))
+			continue
+		}
+		column := ""
+		if field.Field != "" {
+			column = c.Dialect.FormatField(field.Field)
+		}
+		columns = append(columns, FormatSqlAggregate(field.Function, column))
+	}
+	return strings.Join(columns, ","), strings.Join(groups, ","), nil
+}
+
+// FormatSqlAggregate composes a standard SQL aggregate function call.
+//
+//	Parameters:
+//		- function an aggregate function, e.g. cquery.AggregateSum.
+//		- column a formatted column or an expression to aggregate, empty to count all rows.
+//	Returns: SQL expression, e.g. "SUM(\"amount\")" or "COUNT(*)".
+func FormatSqlAggregate(function string, column string) string {
+	if column == "" {
+		column = "*"
+	}
+	return strings.ToUpper(function) + "(" + column + ")"
+}
+
+var sqlNumberRegex = regexp.MustCompile(`^[-+]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?$`)
+
+// IsSqlNumericComparison checks if a field compared by the operator with the given values
+// shall be compared as a number. Values of FilterParams are strings, so when parseStrings is set
+// numeric strings are treated as numbers by range operators. Equality and "in" operators
+// compare with numbers only when the values have numeric types.
+//
+//	Parameters:
+//		- operator a comparison operator.
+//		- values values the field is compared with.
+//		- parseStrings true to treat numeric strings as numbers in ranges.
+//	Returns: true if the field shall be converted to a number.
+func IsSqlNumericComparison(operator FilterOperator, values []any, parseStrings bool) bool {
+	if len(values) == 0 || operator == FilterLike {
+		return false
+	}
+
+	ranged := operator == FilterLess || operator == FilterLessEqual ||
+		operator == FilterMore || operator == FilterMoreEqual || operator == FilterBetween
+	for _, value := range values {
+		if !IsSqlNumber(value, parseStrings && ranged) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSqlNumber checks if the value is a number or, when parseStrings is set, a string with a number.
+//
+//	Parameters:
+//		- value a value to check.
+//		- parseStrings true to accept numeric strings.
+//	Returns: true if the value is a number.
+func IsSqlNumber(value any, parseStrings bool) bool {
+	if str, ok := value.(string); ok {
+		return parseStrings && sqlNumberRegex.MatchString(str)
+	}
+	if value == nil {
+		return false
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func (c *SqlFilterCompiler) compileExpression(filter *FilterExpression, startIndex int, args *[]any) string {
+	switch filter.Operator {
+	case FilterAnd, FilterOr:
+		return c.compileGroup(filter, startIndex, args)
+	}
+
+	field := c.Dialect.FormatField(filter.Field)
+	if dialect, ok := c.Dialect.(ISqlComparisonDialect); ok &&
+		filter.Operator != FilterIsNull && filter.Operator != FilterIsNotNull {
+		field = dialect.FormatComparison(filter.Field, filter.Operator, filter.Values)
+	}
+
+	switch filter.Operator {
+	case FilterIsNull:
+		return field + " IS NULL"
+	case FilterIsNotNull:
+		return field + " IS NOT NULL"
+	case FilterBetween:
+		return field + " BETWEEN " + c.addParameter(filter.Values[0], startIndex, args) +
+			" AND " + c.addParameter(filter.Values[1], startIndex, args)
+	case FilterIn:
+		if len(filter.Values) == 0 {
+			return "1=0"
+		}
+		params := make([]string, len(filter.Values))
+		for i, value := range filter.Values {
+			params[i] = c.addParameter(value, startIndex, args)
+		}
+		return field + " IN (" + strings.Join(params, ",") + ")"
+	}
+
+	operator := "="
+	switch filter.Operator {
+	case FilterNotEqual:
+		operator = "<>"
+	case FilterLess:
+		operator = "<"
+	case FilterLessEqual:
+		operator = "<="
+	case FilterMore:
+		operator = ">"
+	case FilterMoreEqual:
+		operator = ">="
+	case FilterLike:
+		operator = " LIKE "
+	}
+	return field + operator + c.addParameter(filter.Values[0], startIndex, args)
+}
+
+func (c *SqlFilterCompiler) compileGroup(filter *FilterExpression, startIndex int, args *[]any) string {
+	separator := " AND "
+	if filter.Operator == FilterOr {
+		separator = " OR "
+	}
+
+	conditions := make([]string, 0, len(filter.Children))
+	for _, child := range filter.Children {
+		if child.IsEmpty() {
+			continue
+		}
+		conditions = append(conditions, c.compileExpression(child, startIndex, args))
+	}
+
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return "(" + strings.Join(conditions, separator) + ")"
+}
+
+func (c *SqlFilterCompiler) addParameter(value any, startIndex int, args *[]any) string {
+	*args = append(*args, value)
+	return c.Dialect.FormatParameter(startIndex + len(*args) - 1)
+}
@@ -0,0 +1,135 @@
+package persistence
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/pip-services4/pip-services4-go/pip-services4-data-go/data"
+)
+
+type deletedIncludedKey struct{}
+
+// WithDeleted creates a context that makes read operations of persistences
+// in soft-delete mode return logically deleted items as well.
+//
+//	Parameters:
+//		- ctx context.Context a parent context.
+//	Returns: context.Context a new context.
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deletedIncludedKey{}, true)
+}
+
+// IsDeletedIncluded checks if logically deleted items shall be returned by read operations.
+//
+//	Parameters:
+//		- ctx context.Context a context created by WithDeleted or any other context.
+//	Returns: bool true if deleted items are included and false otherwise.
+func IsDeletedIncluded(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	included, _ := ctx.Value(deletedIncludedKey{}).(bool)
+	return included
+}
+
+// IsTrackableType checks if items of the type implement ITrackable interface
+// by value or by pointer.
+//
+//	Typed params:
+//		- T any type of data items
+//	Returns: bool true if items are trackable and false otherwise
+func IsTrackableType[T any]() bool {
+	return implementsInterface[T](reflect.TypeOf((*data.ITrackable)(nil)).Elem())
+}
+
+// IsChangeableType checks if items of the type implement IChangeable interface
+// by value or by pointer. ITrackable items are changeable as well.
+//
+//	Typed params:
+//		- T any type of data items
+//	Returns: bool true if items are changeable and false otherwise
+func IsChangeableType[T any]() bool {
+	return implementsInterface[T](reflect.TypeOf((*data.IChangeable)(nil)).Elem())
+}
+
+func implementsInterface[T any](iface reflect.Type) bool {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	return typ.Implements(iface) || reflect.PointerTo(typ).Implements(iface)
+}
+
+// GetObjectDeleted gets the logical deletion flag of object that implements ITrackable interface.
+//
+//	Parameters:
+//		- item any an object to read the flag from.
+//	Returns: bool true if the object is deleted and false otherwise or if the object is not trackable.
+func GetObjectDeleted(item any) bool {
+	if trackable, ok := toInterface[data.ITrackable](item); ok {
+		return trackable.GetDeleted()
+	}
+	return false
+}
+
+// toInterface casts an object to interface implemented by value or by pointer receiver.
+func toInterface[I any](item any) (I, bool) {
+	var empty I
+	val := reflect.ValueOf(item)
+	if !val.IsValid() || val.Kind() == reflect.Ptr && val.IsNil() {
+		return empty, false
+	}
+	if result, ok := item.(I); ok {
+		return result, true
+	}
+	ptr := reflect.New(val.Type())
+	ptr.Elem().Set(val)
+	result, ok := ptr.Interface().(I)
+	return result, ok
+}
+
+// SetObjectCreateTime sets object "CreateTime" property value.
+//
+//	Parameters:
+//		- item *any a pointer on object to set the property
+//		- createTime time.Time a time when the object was created
+//	Results: saved in input object
+func SetObjectCreateTime(item *any, createTime time.Time) {
+	setObjectProperty(item, "CreateTime", createTime)
+}
+
+// SetObjectChangeTime sets object "ChangeTime" property value.
+//
+//	Parameters:
+//		- item *any a pointer on object to set the property
+//		- changeTime time.Time a time when the object was changed
+//	Results: saved in input object
+func SetObjectChangeTime(item *any, changeTime time.Time) {
+	setObjectProperty(item, "ChangeTime", changeTime)
+}
+
+// SetObjectDeleted sets object "Deleted" property value.
+//
+//	Parameters:
+//		- item *any a pointer on object to set the property
+//		- deleted bool the logical deletion flag
+//	Results: saved in input object
+func SetObjectDeleted(item *any, deleted bool) {
+	setObjectProperty(item, "Deleted", deleted)
+}
+
+// TrackObjectChange stamps change time of an object that implements IChangeable interface.
+// When the object is created and implements ITrackable, its create time is stamped as well
+// unless it is already set.
+//
+//	Parameters:
+//		- item *any a pointer on object to stamp
+//		- created bool true if the object is being created
+//		- now time.Time the current time
+//	Results: saved in input object
+func TrackObjectChange(item *any, created bool, now time.Time) {
+	if _, ok := toInterface[data.IChangeable](*item); ok {
+		SetObjectChangeTime(item, now)
+	}
+	if trackable, ok := toInterface[data.ITrackable](*item); ok && created && trackable.GetCreateTime().IsZero() {
+		SetObjectCreateTime(item, now)
+	}
+}
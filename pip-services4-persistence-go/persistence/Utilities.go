@@ -3,13 +3,16 @@ package persistence
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/jinzhu/copier"
 	"github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
 	refl "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/reflect"
+	"github.com/pip-services4/pip-services4-go/pip-services4-data-go/data"
 	"github.com/pip-services4/pip-services4-go/pip-services4-data-go/keys"
 )
 
@@ -163,6 +166,11 @@ func setPropertyRecursive(fieldType reflect.Type, obj any, name string, value an
 				return
 			}
 		case reflect.Struct:
+			// Struct values like time.Time are set as a whole
+			if matchField(field, name) && reflect.TypeOf(value) == field.Type {
+				val.Field(index).Set(reflect.ValueOf(value))
+				return
+			}
 			setPropertyRecursive(field.Type, val.Field(index).Addr().Interface(), name, value)
 		}
 	}
@@ -184,11 +192,14 @@ func GetObjectId(item any) any {
 //		- id any id value for set
 //	Results: saved in input object
 func SetObjectId(item *any, id any) {
+	setObjectProperty(item, "Id", id)
+}
+
+func setObjectProperty(item *any, name string, propValue any) {
 	value := *item
 	var isPointer bool
 	if reflect.ValueOf(value).Kind() == reflect.Map {
-		//refl.ObjectWriter.SetProperty(value, "Id", id)
-		SetProperty(value, "Id", id)
+		SetProperty(value, name, propValue)
 	} else {
 		if reflect.TypeOf(value).Kind() == reflect.Ptr {
 			value = reflect.ValueOf(value).Elem().Interface()
@@ -197,8 +208,7 @@ func SetObjectId(item *any, id any) {
 		typePointer := reflect.New(reflect.TypeOf(value))
 		typePointer.Elem().Set(reflect.ValueOf(value))
 		typeInterface := typePointer.Interface()
-		//refl.ObjectWriter.SetProperty(typeInterface, "Id", id)
-		SetProperty(typeInterface, "Id", id)
+		SetProperty(typeInterface, name, propValue)
 
 		if isPointer {
 			*item = reflect.ValueOf(typeInterface).Interface()
@@ -208,6 +218,94 @@ func SetObjectId(item *any, id any) {
 	}
 }
 
+// IsVersionedType checks if items of the type implement IVersioned interface
+// by value or by pointer.
+//
+//	Typed params:
+//		- T any type of data items
+//	Returns: bool true if items are versioned and false otherwise
+func IsVersionedType[T any]() bool {
+	versioned := reflect.TypeOf((*data.IVersioned)(nil)).Elem()
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	return typ.Implements(versioned) || reflect.PointerTo(typ).Implements(versioned)
+}
+
+// GetObjectVersion gets a version of object that implements IVersioned interface.
+//
+//	Parameters:
+//		- item any an object to read version from.
+//	Returns: string, bool the object version and true if the object is versioned or false otherwise.
+func GetObjectVersion(item any) (string, bool) {
+	val := reflect.ValueOf(item)
+	if !val.IsValid() || val.Kind() == reflect.Ptr && val.IsNil() {
+		return "", false
+	}
+	if versioned, ok := item.(data.IVersioned); ok {
+		return versioned.GetVersion(), true
+	}
+	// Check methods with pointer receiver
+	ptr := reflect.New(val.Type())
+	ptr.Elem().Set(val)
+	if versioned, ok := ptr.Interface().(data.IVersioned); ok {
+		return versioned.GetVersion(), true
+	}
+	return "", false
+}
+
+// SetObjectVersion sets object "Version" property value.
+// The property must have string type like IVersioned.GetVersion result.
+//
+//	Parameters:
+//		- item *any a pointer on object to set version property
+//		- version string version value for set
+//	Results: saved in input object
+func SetObjectVersion(item *any, version string) {
+	setObjectProperty(item, "Version", version)
+}
+
+// NextVersion generates the next version for an object that has been changed.
+// Versions are sequential integers, empty or non-numeric versions are followed by "1".
+//
+//	Parameters:
+//		- version string the current object version
+//	Returns: string the next version
+func NextVersion(version string) string {
+	value, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return "1"
+	}
+	return strconv.FormatInt(value+1, 10)
+}
+
+// ComposeVersionFilter creates a filter expression that selects items with the given version.
+// Items without version are selected for empty version.
+//
+//	Parameters:
+//		- version string an expected version of the item
+//	Returns: *FilterExpression a filter by "version" field
+func ComposeVersionFilter(version string) *FilterExpression {
+	if version == "" {
+		return NewFilterOr(
+			NewFilterCondition("version", FilterIsNull),
+			NewFilterCondition("version", FilterEqual, ""),
+		)
+	}
+	return NewFilterCondition("version", FilterEqual, version)
+}
+
+// NewVersionConflictError creates an error returned when an item was changed
+// by another process after it had been read.
+//
+//	Parameters:
+//		- traceId string transaction id to trace execution through call chain.
+//		- id any an id of the changed item
+//	Returns: *cerr.ApplicationError a conflict error
+func NewVersionConflictError(traceId string, id any) *cerr.ApplicationError {
+	return cerr.NewConflictError(traceId, "VERSION_CONFLICT",
+		"Item "+convert.StringConverter.ToString(id)+" was changed by another process").
+		WithDetails("id", id)
+}
+
 // GenerateObjectId is generates a new id value when it's empty
 //
 //	Parameters:
@@ -0,0 +1,91 @@
+package test_persistence
+
+import (
+	"context"
+	"testing"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+type OrderDummy struct {
+	Id     string  `json:"id"`
+	Type   string  `json:"type"`
+	Amount float64 `json:"amount"`
+	Note   *string `json:"note"`
+}
+
+func TestAggregationMemoryPersistence(t *testing.T) {
+	persistence := cpersist.NewIdentifiableMemoryPersistence[OrderDummy, string]()
+
+	note := "Note"
+	_, _, err := persistence.CreateMany(context.Background(), []OrderDummy{
+		{Id: "1", Type: "B", Amount: 10},
+		{Id: "2", Type: "A", Amount: 5, Note: &note},
+		{Id: "3", Type: "B", Amount: 20},
+		{Id: "4", Type: "A", Amount: 1},
+		{Id: "5", Type: "C", Amount: 100},
+	})
+	assert.Nil(t, err)
+
+	params := cquery.NewAggregateParams([]string{"type"},
+		cquery.NewCountAggregateField("count"),
+		cquery.NewAggregateField("notes", cquery.AggregateCount, "note"),
+		cquery.NewAggregateField("total", cquery.AggregateSum, "amount"),
+		cquery.NewAggregateField("average", cquery.AggregateAvg, "amount"),
+		cquery.NewAggregateField("smallest", cquery.AggregateMin, "amount"),
+		cquery.NewAggregateField("largest", cquery.AggregateMax, "amount"),
+	)
+
+	rows, err := persistence.GetAggregateByFilter(context.Background(), func(item OrderDummy) bool {
+		return item.Type != "C"
+	}, *params)
+	assert.Nil(t, err)
+	assert.Len(t, rows, 2)
+
+	assert.Equal(t, "A", rows[0].GetGroupValue("type"))
+	assert.Equal(t, int64(2), rows[0].GetValueAsLong("count"))
+	assert.Equal(t, int64(1), rows[0].GetValueAsLong("notes"))
+	assert.Equal(t, 6.0, rows[0].GetValueAsDouble("total"))
+	assert.Equal(t, 3.0, rows[0].GetValueAsDouble("average"))
+	assert.Equal(t, 1.0, rows[0].GetValueAsDouble("smallest"))
+	assert.Equal(t, 5.0, rows[0].GetValueAsDouble("largest"))
+
+	assert.Equal(t, "B", rows[1].GetGroupValue("type"))
+	assert.Equal(t, 30.0, rows[1].GetValueAsDouble("total"))
+
+	// Without groups all items are aggregated into one row
+	rows, err = persistence.GetAggregateByFilter(context.Background(), func(item OrderDummy) bool {
+		return item.Type == "D"
+	}, *cquery.NewAggregateParams(nil,
+		cquery.NewCountAggregateField("count"),
+		cquery.NewAggregateField("total", cquery.AggregateSum, "amount"),
+	))
+	assert.Nil(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, int64(0), rows[0].GetValue("count"))
+	assert.Nil(t, rows[0].GetValue("total"))
+
+	_, err = persistence.GetAggregateByFilter(context.Background(), nil, *cquery.NewAggregateParams(nil,
+		cquery.NewAggregateField("total", "median", "amount"),
+	))
+	assert.NotNil(t, err)
+}
+
+func TestSqlFilterCompilerAggregate(t *testing.T) {
+	compiler := cpersist.NewSqlFilterCompiler(&testFilterDialect{})
+
+	columns, groups, err := compiler.CompileAggregate(*cquery.NewAggregateParams([]string{"type", "status"},
+		cquery.NewCountAggregateField("count"),
+		cquery.NewAggregateField("total", cquery.AggregateSum, "amount"),
+	))
+	assert.Nil(t, err)
+	assert.Equal(t, "\"type\",\"status\",COUNT(*),SUM(\"amount\")", columns)
+	assert.Equal(t, "\"type\",\"status\"", groups)
+
+	_, _, err = compiler.CompileAggregate(*cquery.NewAggregateParams([]string{"type;DROP TABLE dummies"},
+		cquery.NewCountAggregateField("count"),
+	))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,72 @@
+package test_persistence
+
+import (
+	"context"
+	"testing"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/write"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchMemoryPersistence(t *testing.T) {
+	persistence := NewDummyMemoryPersistence()
+	var _ write.IBatchWriter[Dummy] = persistence
+	var _ write.IBatchSetter[Dummy] = persistence
+	var _ write.IBatchPartialUpdater[Dummy, string] = persistence
+
+	items, errs, err := persistence.CreateMany(context.Background(), []Dummy{
+		{Id: "1", Key: "Key 1", Content: "Content 1"},
+		{Key: "Key 2", Content: "Content 2"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "1", items[0].Id)
+	assert.NotEqual(t, "", items[1].Id)
+
+	items, errs, err = persistence.UpsertMany(context.Background(), []Dummy{
+		{Id: "1", Key: "Key 3", Content: "Content 3"},
+		{Id: "3", Key: "Key 4", Content: "Content 4"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Equal(t, "Key 3", items[0].Key)
+	assert.Equal(t, "Key 4", items[1].Key)
+
+	items, errs, err = persistence.UpdateManyPartially(context.Background(), []string{"1", "2", "4"},
+		*cdata.NewAnyValueMapFromTuples("content", "Updated"))
+	assert.Nil(t, err)
+	assert.Len(t, errs, 3)
+	assert.Equal(t, "Updated", items[0].Content)
+	assert.Equal(t, "", items[2].Id)
+
+	list, err := persistence.GetListByIds(context.Background(), []string{"1", "3"})
+	assert.Nil(t, err)
+	assert.Len(t, list, 2)
+}
+
+func TestBatchVersionedMemoryPersistence(t *testing.T) {
+	persistence := cpersist.NewIdentifiableMemoryPersistence[VersionedDummy, string]()
+	persistence.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"options.versioned", true,
+	))
+
+	_, _, err := persistence.CreateMany(context.Background(), []VersionedDummy{
+		{Id: "1", Key: "Key 1", Version: "1"},
+		{Id: "2", Key: "Key 2", Version: "2"},
+	})
+	assert.Nil(t, err)
+
+	// Versions are checked for every item
+	items, errs, err := persistence.UpdateManyPartially(context.Background(), []string{"1", "2"},
+		*cdata.NewAnyValueMapFromTuples("key", "Key 3", "version", "1"))
+	assert.Nil(t, err)
+	assert.Nil(t, errs[0])
+	assert.Equal(t, "2", items[0].Version)
+	assert.NotNil(t, errs[1])
+	assert.Equal(t, cerr.Conflict, errs[1].(*cerr.ApplicationError).Category)
+}
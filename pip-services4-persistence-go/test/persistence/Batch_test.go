@@ -0,0 +1,25 @@
+package test_persistence
+
+import (
+	"testing"
+
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupBatchRows(t *testing.T) {
+	groups := cpersist.GroupBatchRows([]map[string]any{
+		{"id": "1", "key": "Key 1"},
+		nil,
+		{"key": "Key 2", "id": "2", "content": "Content 2"},
+		{"key": "Key 3", "id": "3"},
+	})
+
+	// Rows with the same columns go to the same group
+	assert.Len(t, groups, 2)
+	assert.Equal(t, []string{"id", "key"}, groups[0].Columns)
+	assert.Len(t, groups[0].Rows, 2)
+	assert.Equal(t, "3", groups[0].Rows[1]["id"])
+	assert.Equal(t, []string{"content", "id", "key"}, groups[1].Columns)
+	assert.Len(t, groups[1].Rows, 1)
+}
@@ -0,0 +1,82 @@
+package test_persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingChangeListener struct {
+	events []*cpersist.ChangeEvent
+	err    error
+}
+
+func (c *recordingChangeListener) OnChange(ctx context.Context, event *cpersist.ChangeEvent) error {
+	c.events = append(c.events, event)
+	return c.err
+}
+
+func TestChangeEventsMemoryPersistence(t *testing.T) {
+	persistence := NewDummyMemoryPersistence()
+	listener := &recordingChangeListener{}
+	persistence.AddChangeListener(listener)
+
+	// Failed listeners do not fail operations
+	failed := &recordingChangeListener{err: errors.New("failed")}
+	persistence.AddChangeListener(failed)
+
+	_, err := persistence.Create(context.Background(), Dummy{Id: "1", Key: "Key 1", Content: "Content 1"})
+	assert.Nil(t, err)
+	_, err = persistence.Set(context.Background(), Dummy{Id: "1", Key: "Key 1", Content: "Content 2"})
+	assert.Nil(t, err)
+	_, err = persistence.UpdatePartially(context.Background(), "1", *cdata.NewAnyValueMapFromTuples("content", "Content 3"))
+	assert.Nil(t, err)
+	_, err = persistence.DeleteById(context.Background(), "1")
+	assert.Nil(t, err)
+
+	// Missing items do not produce events
+	_, err = persistence.DeleteById(context.Background(), "1")
+	assert.Nil(t, err)
+
+	assert.Len(t, listener.events, 4)
+	assert.Len(t, failed.events, 4)
+
+	event := listener.events[0]
+	assert.Equal(t, cpersist.ChangeTypeCreated, event.Type)
+	assert.Equal(t, "1", event.Id)
+	assert.Nil(t, event.Before)
+	assert.Equal(t, "Content 1", event.After.(Dummy).Content)
+
+	event = listener.events[1]
+	assert.Equal(t, cpersist.ChangeTypeUpdated, event.Type)
+	assert.Equal(t, "Content 1", event.Before.(Dummy).Content)
+	assert.Equal(t, "Content 2", event.After.(Dummy).Content)
+
+	event = listener.events[2]
+	assert.Equal(t, cpersist.ChangeTypeUpdated, event.Type)
+	assert.Equal(t, "Content 2", event.Before.(Dummy).Content)
+	assert.Equal(t, "Content 3", event.After.(Dummy).Content)
+
+	event = listener.events[3]
+	assert.Equal(t, cpersist.ChangeTypeDeleted, event.Type)
+	assert.Equal(t, "Content 3", event.Before.(Dummy).Content)
+	assert.Nil(t, event.After)
+
+	persistence.RemoveChangeListener(failed)
+
+	_, _, err = persistence.CreateMany(context.Background(), []Dummy{{Id: "2", Key: "Key 2"}, {Id: "3", Key: "Key 3"}})
+	assert.Nil(t, err)
+	err = persistence.DeleteByIds(context.Background(), []string{"2", "3"})
+	assert.Nil(t, err)
+
+	assert.Len(t, listener.events, 8)
+	assert.Len(t, failed.events, 4)
+	assert.Equal(t, cpersist.ChangeTypeCreated, listener.events[5].Type)
+	assert.Equal(t, "3", listener.events[5].Id)
+	assert.Equal(t, cpersist.ChangeTypeDeleted, listener.events[6].Type)
+	assert.Equal(t, "2", listener.events[6].Before.(Dummy).Id)
+}
@@ -0,0 +1,117 @@
+package test_persistence
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposeKeysetFilter(t *testing.T) {
+	sort := *cquery.NewSortParams([]cquery.SortField{cquery.NewSortField("key", false), cquery.NewSortField("id", true)})
+
+	filter, err := cpersist.ComposeKeysetFilter("", sort)
+	assert.Nil(t, err)
+	assert.True(t, filter.IsEmpty())
+
+	token, err := cpersist.ComposeKeysetToken(map[string]any{"id": "5", "key": "Key 1"}, sort)
+	assert.Nil(t, err)
+
+	filter, err = cpersist.ComposeKeysetFilter(token, sort)
+	assert.Nil(t, err)
+	where, args, err := cpersist.NewSqlFilterCompiler(&testFilterDialect{}).Compile(filter, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "((\"key\"<$1 OR \"key\" IS NULL) OR (\"key\"=$2 AND \"id\">$3))", where)
+	assert.Equal(t, []any{"Key 1", "Key 1", "5"}, args)
+
+	// Nulls are the smallest values
+	token, err = cpersist.ComposeKeysetToken(map[string]any{"id": "5", "key": nil}, sort)
+	assert.Nil(t, err)
+	filter, err = cpersist.ComposeKeysetFilter(token, sort)
+	assert.Nil(t, err)
+	where, _, err = cpersist.NewSqlFilterCompiler(&testFilterDialect{}).Compile(filter, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "(\"key\" IS NULL AND \"id\">$1)", where)
+
+	ascending := *cquery.NewSortParams([]cquery.SortField{cquery.NewSortField("key", true), cquery.NewSortField("id", true)})
+	token, err = cpersist.ComposeKeysetToken(map[string]any{"id": "5", "key": nil}, ascending)
+	assert.Nil(t, err)
+	filter, err = cpersist.ComposeKeysetFilter(token, ascending)
+	assert.Nil(t, err)
+	where, _, err = cpersist.NewSqlFilterCompiler(&testFilterDialect{}).Compile(filter, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "(\"key\" IS NOT NULL OR (\"key\" IS NULL AND \"id\">$1))", where)
+
+	_, err = cpersist.ComposeKeysetFilter(token, *cquery.NewSortParams([]cquery.SortField{cquery.NewSortField("id", true)}))
+	assert.NotNil(t, err)
+
+	_, err = cpersist.ComposeKeysetFilter("not a token", sort)
+	assert.NotNil(t, err)
+}
+
+func TestKeysetTokenKeepsLargeIntegers(t *testing.T) {
+	sort := *cquery.NewSortParams([]cquery.SortField{cquery.NewSortField("count", true), cquery.NewSortField("id", true)})
+
+	token, err := cpersist.ComposeKeysetTokenFromJson(`{"id":"5","count":9007199254740993}`, sort)
+	assert.Nil(t, err)
+
+	filter, err := cpersist.ComposeKeysetFilter(token, sort)
+	assert.Nil(t, err)
+	_, args, err := cpersist.NewSqlFilterCompiler(&testFilterDialect{}).Compile(filter, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{int64(9007199254740993), int64(9007199254740993), "5"}, args)
+
+	assert.Equal(t, 1, cpersist.CompareKeysetValues(int64(9007199254740993), int64(9007199254740992)))
+	assert.Equal(t, -1, cpersist.CompareKeysetValues(int64(2), 2.5))
+}
+
+func TestMemoryPersistenceGetPageByFilterWithToken(t *testing.T) {
+	persistence := NewDummyMemoryPersistence()
+	persistence.Open(context.Background())
+	defer persistence.Close(context.Background())
+
+	for i := 1; i <= 25; i++ {
+		_, err := persistence.Create(context.Background(), Dummy{
+			Id:      strconv.Itoa(100 + i),
+			Key:     "Key " + strconv.Itoa(i%3),
+			Content: "Content " + strconv.Itoa(i),
+		})
+		assert.Nil(t, err)
+	}
+
+	sort := *cquery.NewSortParams([]cquery.SortField{cquery.NewSortField("key", false)})
+	filter := func(item Dummy) bool { return item.Content != "Content 7" }
+
+	ids := make(map[string]bool)
+	var last *Dummy
+	token := ""
+	pages := 0
+	for {
+		page, err := persistence.GetPageByFilterWithToken(context.Background(),
+			filter, *cquery.NewTokenizedPagingParams(token, 10, false), sort, nil)
+		assert.Nil(t, err)
+		pages++
+
+		for _, item := range page.Data {
+			assert.False(t, ids[item.Id])
+			ids[item.Id] = true
+			if last != nil {
+				assert.True(t, last.Key > item.Key || last.Key == item.Key && last.Id < item.Id)
+			}
+			item := item
+			last = &item
+		}
+
+		if page.Token == "" {
+			break
+		}
+		token = page.Token
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Len(t, ids, 24)
+	assert.False(t, ids["107"])
+}
@@ -0,0 +1,87 @@
+package test_persistence
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func getMigrationVersions(migrations []*cpersist.Migration) []int64 {
+	versions := make([]int64, len(migrations))
+	for i, migration := range migrations {
+		versions[i] = migration.Version
+	}
+	return versions
+}
+
+func TestPlanMigrations(t *testing.T) {
+	migrations := []*cpersist.Migration{
+		cpersist.NewMigration(3, "third", "UP 3", "DOWN 3"),
+		cpersist.NewMigration(1, "first", "UP 1", "DOWN 1"),
+		cpersist.NewMigration(2, "second", "UP 2", ""),
+	}
+
+	up, down, err := cpersist.PlanMigrations(migrations, nil, cpersist.MigrationLatestVersion)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, getMigrationVersions(up))
+	assert.Len(t, down, 0)
+
+	up, down, err = cpersist.PlanMigrations(migrations, []int64{1}, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2}, getMigrationVersions(up))
+	assert.Len(t, down, 0)
+
+	up, down, err = cpersist.PlanMigrations(migrations, []int64{1, 2, 3}, cpersist.MigrationLatestVersion)
+	assert.Nil(t, err)
+	assert.Len(t, up, 0)
+	assert.Len(t, down, 0)
+
+	up, down, err = cpersist.PlanMigrations(migrations, []int64{1, 3}, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2}, getMigrationVersions(up))
+	assert.Equal(t, []int64{3}, getMigrationVersions(down))
+
+	// Migration 2 has no down script
+	_, _, err = cpersist.PlanMigrations(migrations, []int64{1, 2, 3}, 0)
+	assert.NotNil(t, err)
+
+	// Applied migration is unknown
+	_, _, err = cpersist.PlanMigrations(migrations, []int64{1, 2, 3, 4}, cpersist.MigrationLatestVersion)
+	assert.NotNil(t, err)
+
+	_, _, err = cpersist.PlanMigrations(append(migrations, cpersist.NewMigration(1, "duplicate", "UP", "")),
+		nil, cpersist.MigrationLatestVersion)
+	assert.NotNil(t, err)
+}
+
+func TestLoadMigrationsFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"002_add_content.up.sql":      "ALTER TABLE dummies ADD content TEXT",
+		"002_add_content.down.sql":    "ALTER TABLE dummies DROP content",
+		"001_create_dummies.up.sql":   "CREATE TABLE dummies (id TEXT)",
+		"001_create_dummies.down.sql": "DROP TABLE dummies",
+		"README.md":                   "Migrations",
+	}
+	for name, content := range files {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	migrations, err := cpersist.LoadMigrationsFromDirectory(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2}, getMigrationVersions(migrations))
+	assert.Equal(t, "create dummies", migrations[0].Description)
+	assert.Equal(t, "CREATE TABLE dummies (id TEXT)", migrations[0].Up)
+	assert.Equal(t, "ALTER TABLE dummies DROP content", migrations[1].Down)
+
+	// Down script without up script
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "003_broken.down.sql"), []byte("SELECT 1"), 0644))
+	_, err = cpersist.LoadMigrationsFromDirectory(dir)
+	assert.NotNil(t, err)
+
+	_, err = cpersist.LoadMigrationsFromDirectory(filepath.Join(dir, "missing"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,52 @@
+package test_persistence
+
+import (
+	"testing"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposeProjectionFields(t *testing.T) {
+	fields, err := cpersist.ComposeProjectionFields(nil)
+	assert.Nil(t, err)
+	assert.Len(t, fields, 0)
+
+	projection := cquery.NewProjectionParamsFromValue("id, object1(field1,object2(field2)), object1.field3, field4")
+	fields, err = cpersist.ComposeProjectionFields(projection)
+	assert.Nil(t, err)
+	assert.Len(t, fields, 3)
+	assert.Equal(t, "id", fields[0].Name)
+	assert.Nil(t, fields[0].Children)
+	assert.Equal(t, "object1", fields[1].Name)
+	assert.Len(t, fields[1].Children, 3)
+	assert.Equal(t, "object1.object2.field2", fields[1].Children[1].Children[0].Path)
+	assert.Equal(t, "field4", fields[2].Path)
+
+	paths, err := cpersist.ComposeProjectionPaths(projection)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"id", "object1.field1", "object1.object2.field2", "object1.field3", "field4"}, paths)
+
+	// Whole fields override their subfields
+	paths, err = cpersist.ComposeProjectionPaths(cquery.ParseProjectionParams("object1(field1)", "object1", "object1.field2"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"object1"}, paths)
+
+	_, err = cpersist.ComposeProjectionFields(cquery.ParseProjectionParams("id;DROP TABLE"))
+	assert.NotNil(t, err)
+	_, err = cpersist.ComposeProjectionFields(cquery.ParseProjectionParams("object1..field1"))
+	assert.NotNil(t, err)
+}
+
+func TestSqlFilterCompilerProjection(t *testing.T) {
+	compiler := cpersist.NewSqlFilterCompiler(&testFilterDialect{})
+
+	selection, err := compiler.CompileProjection(cquery.NewEmptyProjectionParams())
+	assert.Nil(t, err)
+	assert.Equal(t, "", selection)
+
+	selection, err = compiler.CompileProjection(cquery.ParseProjectionParams("id,key,object1(field1,field2)"))
+	assert.Nil(t, err)
+	assert.Equal(t, "\"id\",\"key\",\"object1\"", selection)
+}
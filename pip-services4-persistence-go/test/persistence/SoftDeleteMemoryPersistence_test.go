@@ -0,0 +1,108 @@
+package test_persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+type TrackedDummy struct {
+	Id         string    `json:"id"`
+	Key        string    `json:"key"`
+	CreateTime time.Time `json:"create_time"`
+	ChangeTime time.Time `json:"change_time"`
+	Deleted    bool      `json:"deleted"`
+}
+
+func (c TrackedDummy) GetCreateTime() time.Time {
+	return c.CreateTime
+}
+
+func (c TrackedDummy) GetChangeTime() time.Time {
+	return c.ChangeTime
+}
+
+func (c TrackedDummy) GetDeleted() bool {
+	return c.Deleted
+}
+
+func TestSoftDeleteMemoryPersistence(t *testing.T) {
+	persistence := cpersist.NewIdentifiableMemoryPersistence[TrackedDummy, string]()
+	persistence.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"options.soft_delete", true,
+	))
+	assert.True(t, persistence.SoftDelete)
+
+	item1, err := persistence.Create(context.Background(), TrackedDummy{Id: "1", Key: "Key 1"})
+	assert.Nil(t, err)
+	assert.False(t, item1.CreateTime.IsZero())
+	assert.Equal(t, item1.CreateTime, item1.ChangeTime)
+
+	_, err = persistence.Create(context.Background(), TrackedDummy{Id: "2", Key: "Key 2"})
+	assert.Nil(t, err)
+	_, err = persistence.Create(context.Background(), TrackedDummy{Id: "3", Key: "Key 3"})
+	assert.Nil(t, err)
+
+	item1, err = persistence.UpdatePartially(context.Background(), "1", *cdata.NewAnyValueMapFromTuples("key", "Key 4"))
+	assert.Nil(t, err)
+	assert.True(t, item1.ChangeTime.After(item1.CreateTime) || item1.ChangeTime.Equal(item1.CreateTime))
+
+	// Deleted items are marked and excluded from reads
+	item1, err = persistence.DeleteById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.True(t, item1.Deleted)
+
+	err = persistence.DeleteByIds(context.Background(), []string{"2"})
+	assert.Nil(t, err)
+
+	item, err := persistence.GetOneById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "", item.Id)
+
+	page, err := persistence.GetPageByFilter(context.Background(), nil, *cquery.NewPagingParams(0, 10, true), nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, 1, page.Total)
+
+	items, err := persistence.GetListByIds(context.Background(), []string{"1", "2", "3"})
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+
+	// Deleted items can be read explicitly
+	all := func(TrackedDummy) bool { return true }
+	item, err = persistence.GetOneById(cpersist.WithDeleted(context.Background()), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", item.Id)
+	assert.True(t, item.Deleted)
+
+	count, err := persistence.GetCountByFilter(cpersist.WithDeleted(context.Background()), all)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// Restore and purge
+	item, err = persistence.RestoreById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.False(t, item.Deleted)
+
+	item, err = persistence.GetOneById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "Key 4", item.Key)
+
+	err = persistence.PurgeDeleted(context.Background())
+	assert.Nil(t, err)
+	count, err = persistence.GetCountByFilter(cpersist.WithDeleted(context.Background()), all)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+
+	_, err = persistence.PurgeById(context.Background(), "3")
+	assert.Nil(t, err)
+	items, err = persistence.GetListByIds(cpersist.WithDeleted(context.Background()), []string{"1", "2", "3"})
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+}
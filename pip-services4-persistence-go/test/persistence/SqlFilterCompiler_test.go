@@ -0,0 +1,101 @@
+package test_persistence
+
+import (
+	"strconv"
+	"testing"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+type testFilterDialect struct{}
+
+func (c *testFilterDialect) FormatField(field string) string {
+	return "\"" + field + "\""
+}
+
+func (c *testFilterDialect) FormatParameter(index int) string {
+	return "$" + strconv.Itoa(index)
+}
+
+func TestFilterExpressionFromParams(t *testing.T) {
+	filter, err := cpersist.NewFilterExpressionFromParams(*cquery.NewFilterParamsFromTuples(
+		"key", "A",
+		"count__gt", 5,
+		"type__in", "x, y",
+		"time__between", "1,2",
+		"content__is_null", false,
+		"or.key", "B",
+		"or.content__like", "%C%",
+	))
+	assert.Nil(t, err)
+	assert.Equal(t, cpersist.FilterAnd, filter.Operator)
+	assert.Len(t, filter.Children, 6)
+
+	compiler := cpersist.NewSqlFilterCompiler(&testFilterDialect{})
+	where, args, err := compiler.Compile(filter, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "(\"content\" IS NOT NULL AND \"count\">$1 AND \"key\"=$2"+
+		" AND (\"content\" LIKE $3 OR \"key\"=$4)"+
+		" AND \"time\" BETWEEN $5 AND $6 AND \"type\" IN ($7,$8))", where)
+	assert.Equal(t, []any{"5", "A", "%C%", "B", "1", "2", "x", "y"}, args)
+}
+
+func TestFilterExpressionAllowedFields(t *testing.T) {
+	_, err := cpersist.NewFilterExpressionFromParams(
+		*cquery.NewFilterParamsFromTuples("key", "A", "secret", "B"), "key")
+	assert.NotNil(t, err)
+
+	_, err = cpersist.NewFilterExpressionFromParams(
+		*cquery.NewFilterParamsFromTuples("key__unknown", "A"))
+	assert.NotNil(t, err)
+
+	_, err = cpersist.NewFilterExpressionFromParams(
+		*cquery.NewFilterParamsFromTuples("key__between", "A"))
+	assert.NotNil(t, err)
+}
+
+func TestSqlFilterCompilerRejectsInjection(t *testing.T) {
+	compiler := cpersist.NewSqlFilterCompiler(&testFilterDialect{})
+
+	filter := cpersist.NewFilterCondition("key\"; DROP TABLE dummies; --", cpersist.FilterEqual, "A")
+	_, _, err := compiler.Compile(filter, 1)
+	assert.NotNil(t, err)
+
+	filter = cpersist.NewFilterCondition("key", cpersist.FilterEqual, "'; DROP TABLE dummies; --")
+	where, args, err := compiler.Compile(filter, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "\"key\"=$3", where)
+	assert.Equal(t, []any{"'; DROP TABLE dummies; --"}, args)
+}
+
+func TestSqlFilterCompilerEmptyFilter(t *testing.T) {
+	compiler := cpersist.NewSqlFilterCompiler(&testFilterDialect{})
+
+	where, args, err := compiler.Compile(nil, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "", where)
+	assert.Len(t, args, 0)
+
+	where, _, err = compiler.Compile(cpersist.NewFilterAnd(cpersist.NewFilterOr()), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "", where)
+
+	where, args, err = compiler.Compile(cpersist.NewFilterCondition("key", cpersist.FilterIn), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "1=0", where)
+	assert.Len(t, args, 0)
+}
+
+func TestIsSqlNumericComparison(t *testing.T) {
+	assert.True(t, cpersist.IsSqlNumericComparison(cpersist.FilterMore, []any{"9"}, true))
+	assert.True(t, cpersist.IsSqlNumericComparison(cpersist.FilterBetween, []any{"-0.5", "1e3"}, true))
+	assert.False(t, cpersist.IsSqlNumericComparison(cpersist.FilterMore, []any{"9"}, false))
+	assert.False(t, cpersist.IsSqlNumericComparison(cpersist.FilterMoreEqual, []any{"A10"}, true))
+
+	assert.False(t, cpersist.IsSqlNumericComparison(cpersist.FilterIn, []any{"1", "2"}, true))
+	assert.True(t, cpersist.IsSqlNumericComparison(cpersist.FilterIn, []any{1, int64(2), 3.5}, true))
+	assert.False(t, cpersist.IsSqlNumericComparison(cpersist.FilterLike, []any{1}, true))
+	assert.False(t, cpersist.IsSqlNumericComparison(cpersist.FilterEqual, []any{nil}, true))
+}
@@ -0,0 +1,72 @@
+package test_persistence
+
+import (
+	"context"
+	"testing"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+type VersionedDummy struct {
+	Id      string `json:"id"`
+	Key     string `json:"key"`
+	Version string `json:"version"`
+}
+
+func (c VersionedDummy) GetVersion() string {
+	return c.Version
+}
+
+func TestVersionedMemoryPersistence(t *testing.T) {
+	persistence := cpersist.NewIdentifiableMemoryPersistence[VersionedDummy, string]()
+	persistence.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"options.versioned", true,
+	))
+	assert.True(t, persistence.Versioned)
+
+	item, err := persistence.Create(context.Background(), VersionedDummy{Id: "1", Key: "Key 1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "", item.Version)
+
+	item.Key = "Key 2"
+	item, err = persistence.Update(context.Background(), item)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", item.Version)
+
+	// Stale version is rejected
+	_, err = persistence.Update(context.Background(), VersionedDummy{Id: "1", Key: "Key 3", Version: ""})
+	assert.NotNil(t, err)
+	assert.Equal(t, "VERSION_CONFLICT", err.(*cerr.ApplicationError).Code)
+	assert.Equal(t, cerr.Conflict, err.(*cerr.ApplicationError).Category)
+
+	item, err = persistence.UpdatePartially(context.Background(), "1",
+		*cdata.NewAnyValueMapFromTuples("key", "Key 4", "version", "1"))
+	assert.Nil(t, err)
+	assert.Equal(t, "Key 4", item.Key)
+	assert.Equal(t, "2", item.Version)
+
+	_, err = persistence.UpdatePartially(context.Background(), "1",
+		*cdata.NewAnyValueMapFromTuples("key", "Key 5", "version", "1"))
+	assert.NotNil(t, err)
+
+	// Version is incremented even when it is not set
+	item, err = persistence.UpdatePartially(context.Background(), "1",
+		*cdata.NewAnyValueMapFromTuples("key", "Key 6"))
+	assert.Nil(t, err)
+	assert.Equal(t, "3", item.Version)
+
+	item, err = persistence.GetOneById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "Key 6", item.Key)
+	assert.Equal(t, "3", item.Version)
+
+	// Versions are ignored unless the option is on
+	persistence.Versioned = false
+	item, err = persistence.Update(context.Background(), VersionedDummy{Id: "1", Key: "Key 7", Version: "0"})
+	assert.Nil(t, err)
+	assert.Equal(t, "0", item.Version)
+}
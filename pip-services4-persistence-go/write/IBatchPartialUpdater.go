@@ -0,0 +1,26 @@
+package write
+
+import (
+	"context"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+)
+
+// IBatchPartialUpdater interface for data processing components to update
+// multiple data items partially in a single operation.
+//
+//	Typed params:
+//		- T any type
+//		- K type of id (key)
+type IBatchPartialUpdater[T any, K any] interface {
+
+	// UpdateManyPartially updates the same few selected fields in multiple data items.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- ids []K ids of data items to be updated.
+	//		- data data.AnyValueMap a map with fields to be updated.
+	//	Returns: []T, []error, error updated items and errors of every item
+	//	in the order of the given ids, or error of the entire operation.
+	//	Items that were not found are returned as empty values.
+	UpdateManyPartially(ctx context.Context, ids []K, data cdata.AnyValueMap) (values []T, errs []error, err error)
+}
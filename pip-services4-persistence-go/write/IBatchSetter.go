@@ -0,0 +1,22 @@
+package write
+
+import (
+	"context"
+)
+
+// IBatchSetter interface for data processing components that can set (create or update)
+// multiple data items in a single operation.
+//
+//	Typed params:
+//		- T any type
+type IBatchSetter[T any] interface {
+
+	// UpsertMany sets multiple data items. The data items that exist are updated,
+	// others are created.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- items []T items to be set.
+	//	Returns: []T, []error, error set items and errors of every item
+	//	in the order of the given items, or error of the entire operation.
+	UpsertMany(ctx context.Context, items []T) (values []T, errs []error, err error)
+}
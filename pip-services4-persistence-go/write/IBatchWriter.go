@@ -0,0 +1,21 @@
+package write
+
+import (
+	"context"
+)
+
+// IBatchWriter interface for data processing components
+// that can create multiple data items in a single operation.
+//
+//	Typed params:
+//		- T any type
+type IBatchWriter[T any] interface {
+
+	// CreateMany creates multiple data items.
+	//	Parameters:
+	//		- ctx context.Context execution context to trace execution through call chain.
+	//		- items []T items to be created.
+	//	Returns: []T, []error, error created items and errors of every item
+	//	in the order of the given items, or error of the entire operation.
+	CreateMany(ctx context.Context, items []T) (values []T, errs []error, err error)
+}
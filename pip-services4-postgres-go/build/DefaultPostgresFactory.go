@@ -4,12 +4,14 @@ import (
 	cbuild "github.com/pip-services4/pip-services4-go/pip-services4-components-go/build"
 	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
 	conn "github.com/pip-services4/pip-services4-go/pip-services4-postgres-go/connect"
+	postgresqueues "github.com/pip-services4/pip-services4-go/pip-services4-postgres-go/queues"
 )
 
 // DefaultPostgresFactory creates Postgres components by their descriptors.
 //
 //	see Factory
 //	see PostgresConnection
+//	see PostgresMessageQueue
 type DefaultPostgresFactory struct {
 	*cbuild.Factory
 }
@@ -23,5 +25,17 @@ func NewDefaultPostgresFactory() *DefaultPostgresFactory {
 	postgresConnectionDescriptor := cref.NewDescriptor("pip-services", "connection", "postgres", "*", "1.0")
 	c.RegisterType(postgresConnectionDescriptor, conn.NewPostgresConnection)
 
+	postgresQueueFactoryDescriptor := cref.NewDescriptor("pip-services", "queue-factory", "postgres", "*", "1.0")
+	postgresQueueDescriptor := cref.NewDescriptor("pip-services", "message-queue", "postgres", "*", "1.0")
+	c.RegisterType(postgresQueueFactoryDescriptor, NewPostgresMessageQueueFactory)
+	c.Register(postgresQueueDescriptor, func(locator any) any {
+		name := ""
+		descriptor, ok := locator.(*cref.Descriptor)
+		if ok {
+			name = descriptor.Name()
+		}
+		return postgresqueues.NewPostgresMessageQueue(name)
+	})
+
 	return c
 }
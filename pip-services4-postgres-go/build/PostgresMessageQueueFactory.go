@@ -0,0 +1,56 @@
+package build
+
+import (
+	"context"
+
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/build"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	postgresqueues "github.com/pip-services4/pip-services4-go/pip-services4-postgres-go/queues"
+)
+
+// PostgresMessageQueueFactory are creates PostgresMessageQueue components by their descriptors.
+// Name of created message queue is taken from its descriptor.
+//
+// See Factory
+// See PostgresMessageQueue
+type PostgresMessageQueueFactory struct {
+	*build.MessageQueueFactory
+}
+
+// NewPostgresMessageQueueFactory method are create a new instance of the factory.
+func NewPostgresMessageQueueFactory() *PostgresMessageQueueFactory {
+	c := PostgresMessageQueueFactory{
+		MessageQueueFactory: build.InheritMessageQueueFactory(),
+	}
+
+	postgresQueueDescriptor := cref.NewDescriptor("pip-services", "message-queue", "postgres", "*", "1.0")
+
+	c.Register(postgresQueueDescriptor, func(locator interface{}) interface{} {
+		name := ""
+		descriptor, ok := locator.(*cref.Descriptor)
+		if ok {
+			name = descriptor.Name()
+		}
+		return c.CreateQueue(name)
+	})
+
+	return &c
+}
+
+// Creates a message queue component and assigns its name.
+//
+// Parameters:
+//   - name: a name of the created message queue.
+func (c *PostgresMessageQueueFactory) CreateQueue(name string) cqueues.IMessageQueue {
+	queue := postgresqueues.NewPostgresMessageQueue(name)
+
+	if c.Config != nil {
+		queue.Configure(context.Background(), c.Config)
+	}
+	if c.References != nil {
+		queue.SetReferences(context.Background(), c.References)
+	}
+
+	return queue
+}
@@ -0,0 +1,174 @@
+package connect
+
+import (
+	"context"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+)
+
+// IPostgresClient is a common interface of *pgxpool.Pool and pgx.Tx
+// used by persistence components to execute queries.
+type IPostgresClient interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type postgresTransactionKey struct {
+	pool *pgxpool.Pool
+}
+
+// PostgresTransaction is a unit of work started on PostgreSQL connection and carried in context.Context.
+// Persistence components that share the same connection pick up the transaction from the context
+// and execute their queries within it. Transactions started on a context that already contains
+// a transaction for the same connection are nested and implemented by savepoints.
+//
+// Transactions are not safe for concurrent use, the context shall not be shared between goroutines.
+//
+//	Example:
+//		ctx, tx, err := connection.BeginTransaction(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		defer tx.Rollback(ctx)
+//
+//		order, err = ordersPersistence.Create(ctx, order)
+//		...
+//		lines, err = linesPersistence.Create(ctx, line)
+//		...
+//		return tx.Commit(ctx)
+type PostgresTransaction struct {
+	tx        pgx.Tx
+	nested    bool
+	completed bool
+}
+
+// BeginTransaction starts a new transaction and returns a context that carries it.
+// If the context already has a transaction started on this connection
+// a nested transaction is created using a savepoint.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: context.Context with the transaction, the transaction and error or nil when no errors occurred.
+func (c *PostgresConnection) BeginTransaction(ctx context.Context) (context.Context, *PostgresTransaction, error) {
+	if c.Connection == nil {
+		return ctx, nil, cerr.NewInvalidStateError(cctx.GetTraceId(ctx), "NOT_OPENED", "Connection is not opened")
+	}
+	key := postgresTransactionKey{pool: c.Connection}
+
+	if parent, ok := ctx.Value(key).(*PostgresTransaction); ok && !parent.completed {
+		// pgx implements nested transactions by savepoints
+		tx, err := parent.tx.Begin(ctx)
+		if err != nil {
+			return ctx, nil, cerr.NewConnectionError(cctx.GetTraceId(ctx), "TRANSACTION_FAILED",
+				"Failed to create savepoint").WithCause(err)
+		}
+		transaction := &PostgresTransaction{tx: tx, nested: true}
+		return context.WithValue(ctx, key, transaction), transaction, nil
+	}
+
+	tx, err := c.Connection.Begin(ctx)
+	if err != nil {
+		return ctx, nil, cerr.NewConnectionError(cctx.GetTraceId(ctx), "TRANSACTION_FAILED",
+			"Failed to begin transaction").WithCause(err)
+	}
+	transaction := &PostgresTransaction{tx: tx}
+	return context.WithValue(ctx, key, transaction), transaction, nil
+}
+
+// ExecuteInTransaction runs a function within a transaction. The transaction is committed
+// when the function succeeds and rolled back when it returns an error or panics.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- action func(ctx context.Context) error a function that receives context with the transaction.
+//	Returns: error returned by the function or by the transaction, or nil when no errors occurred.
+func (c *PostgresConnection) ExecuteInTransaction(ctx context.Context, action func(ctx context.Context) error) (err error) {
+	txCtx, transaction, err := c.BeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			_ = transaction.Rollback(txCtx)
+			panic(r)
+		}
+	}()
+
+	if err = action(txCtx); err != nil {
+		_ = transaction.Rollback(txCtx)
+		return err
+	}
+	return transaction.Commit(txCtx)
+}
+
+// GetPostgresTransaction gets a transaction started on the connection from the context.
+//
+//	Parameters:
+//		- ctx context.Context a context that may contain a transaction.
+//		- pool *pgxpool.Pool a connection the transaction was started on.
+//	Returns: pgx.Tx the transaction and true, or nil and false when there is no transaction.
+func GetPostgresTransaction(ctx context.Context, pool *pgxpool.Pool) (pgx.Tx, bool) {
+	if ctx == nil || pool == nil {
+		return nil, false
+	}
+	if transaction, ok := ctx.Value(postgresTransactionKey{pool: pool}).(*PostgresTransaction); ok && !transaction.completed {
+		return transaction.tx, true
+	}
+	return nil, false
+}
+
+// Tx gets the underlying database transaction.
+//
+//	Returns: pgx.Tx
+func (c *PostgresTransaction) Tx() pgx.Tx {
+	return c.tx
+}
+
+// IsNested checks if the transaction is nested into another one.
+//
+//	Returns: true if the transaction is implemented by a savepoint and false otherwise.
+func (c *PostgresTransaction) IsNested() bool {
+	return c.nested
+}
+
+// Commit commits the transaction or releases the savepoint of a nested transaction.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: error or nil when no errors occurred.
+func (c *PostgresTransaction) Commit(ctx context.Context) error {
+	if c.completed {
+		return cerr.NewInvalidStateError(cctx.GetTraceId(ctx), "TRANSACTION_COMPLETED", "Transaction is already completed")
+	}
+	c.completed = true
+
+	if err := c.tx.Commit(ctx); err != nil {
+		return cerr.NewConnectionError(cctx.GetTraceId(ctx), "TRANSACTION_FAILED",
+			"Failed to commit transaction").WithCause(err)
+	}
+	return nil
+}
+
+// Rollback rolls back the transaction or rolls back to the savepoint of a nested transaction.
+// It does nothing when the transaction was already committed or rolled back, so it can be deferred.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: error or nil when no errors occurred.
+func (c *PostgresTransaction) Rollback(ctx context.Context) error {
+	if c.completed {
+		return nil
+	}
+	c.completed = true
+
+	if err := c.tx.Rollback(ctx); err != nil {
+		return cerr.NewConnectionError(cctx.GetTraceId(ctx), "TRANSACTION_FAILED",
+			"Failed to rollback transaction").WithCause(err)
+	}
+	return nil
+}
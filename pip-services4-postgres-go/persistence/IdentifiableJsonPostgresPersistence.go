@@ -6,6 +6,7 @@ import (
 	"github.com/jackc/pgx/v4"
 	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
 	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 )
 
 // IdentifiableJsonPostgresPersistence is an abstract persistence component that stores data in PostgreSQL in JSON or JSONB fields
@@ -102,6 +103,7 @@ type IdentifiableJsonPostgresPersistence[T any, K any] struct {
 func InheritIdentifiableJsonPostgresPersistence[T any, K any](overrides IPostgresPersistenceOverrides[T], tableName string) *IdentifiableJsonPostgresPersistence[T, K] {
 	c := &IdentifiableJsonPostgresPersistence[T, K]{}
 	c.IdentifiablePostgresPersistence = InheritIdentifiablePostgresPersistence[T, K](overrides, tableName)
+	c.FilterCompiler = NewJsonPostgresFilterCompiler("data")
 	return c
 }
 
@@ -191,6 +193,8 @@ func (c *IdentifiableJsonPostgresPersistence[T, K]) ConvertFromPublicPartial(val
 }
 
 // UpdatePartially updates only few selected fields in a data item.
+// When Versioned option is on the version is taken from "version" field in the data
+// or from the stored item, and ConflictError is returned when it does not match.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -200,17 +204,48 @@ func (c *IdentifiableJsonPostgresPersistence[T, K]) ConvertFromPublicPartial(val
 // Returns: receives updated item or error.
 func (c *IdentifiableJsonPostgresPersistence[T, K]) UpdatePartially(ctx context.Context,
 	id K, data cdata.AnyValueMap) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updatePartially(ctx, id, data)
+		})
+}
+
+func (c *IdentifiableJsonPostgresPersistence[T, K]) updatePartially(ctx context.Context,
+	id K, data cdata.AnyValueMap) (result T, err error) {
+
+	version, versioned := "", false
+	if c.Versioned && cpersist.IsVersionedType[T]() {
+		if version, err = c.getPartialVersion(ctx, id, data); err != nil {
+			return result, err
+		}
+		versioned = true
+		data = *cdata.NewAnyValueMapFromMaps(data.Value(), map[string]any{"version": cpersist.NextVersion(version)})
+	}
+	data = c.trackPartialData(data)
 
-	query := "UPDATE " + c.QuotedTableName() + " SET \"data\"=\"data\"||$2 WHERE \"id\"=$1 RETURNING *"
+	query := "UPDATE " + c.QuotedTableName() + " SET \"data\"=\"data\"||$2 WHERE \"id\"=$1"
 	values := []any{id, data.Value()}
 
-	rows, err := c.IdentifiablePostgresPersistence.Client.Query(ctx, query, values...)
+	if versioned {
+		condition, args, err := c.composeVersionCondition(version, len(values)+1)
+		if err != nil {
+			return result, err
+		}
+		query += " AND " + condition
+		values = append(values, args...)
+	}
+	query += " RETURNING *"
+
+	rows, err := c.IdentifiablePostgresPersistence.GetClient(ctx).Query(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
 	defer rows.Close()
 
 	if !rows.Next() {
+		if versioned && rows.Err() == nil {
+			return result, c.checkVersionConflict(ctx, id)
+		}
 		return result, rows.Err()
 	}
 
@@ -225,3 +260,23 @@ func (c *IdentifiableJsonPostgresPersistence[T, K]) UpdatePartially(ctx context.
 	}
 	return result, rows.Err()
 }
+
+// UpdateManyPartially updates the same few selected fields in multiple data items in a single statement.
+// When Versioned option is on the items are updated one by one to check their versions.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- ids               ids of data items to be updated.
+//		- data              a map with fields to be updated.
+//
+// Returns: updated items and errors of every item in the order of the given ids, or error.
+func (c *IdentifiableJsonPostgresPersistence[T, K]) UpdateManyPartially(ctx context.Context,
+	ids []K, data cdata.AnyValueMap) ([]T, []error, error) {
+
+	if c.Versioned && cpersist.IsVersionedType[T]() {
+		return c.updateManyPartiallyByOne(ctx, ids, data, c.UpdatePartially)
+	}
+
+	data = c.trackPartialData(data)
+	return c.trackUpdateManyPartially(ctx, ids, "\"data\"=\"data\"||$1", []any{data.Value()})
+}
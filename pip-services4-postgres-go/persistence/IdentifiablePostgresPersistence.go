@@ -5,10 +5,12 @@ import (
 	"strconv"
 
 	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
 	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 
 	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
 	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
 	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
 )
 
@@ -23,6 +25,10 @@ import (
 // In complex scenarios child classes can implement additional operations by
 // accessing c._collection and c._model properties.
 //
+// Write operations emit created, updated and deleted change events to listeners
+// added by AddChangeListener. In outbox mode the events are also written to the outbox table
+// in the same transaction as the data.
+//
 //	Configuration parameters
 //		- collection:               (optional) PostgreSQL collection name
 //		- connection(s):
@@ -38,6 +44,8 @@ import (
 //			- connect_timeout:      (optional) number of milliseconds to wait before timing out when connecting a new client (default: 0)
 //			- idle_timeout:         (optional) number of milliseconds a client must sit idle in the pool and not be checked out (default: 10000)
 //			- max_pool_size:        (optional) maximum number of clients the pool should contain (default: 10)
+//			- versioned:            (optional) turns on optimistic concurrency for items that implement IVersioned (default: false)
+//			- outbox:               (optional) write change events to the outbox table in the same transaction as the data (default: false)
 //
 //	References
 //		- *:logger:*:*:1.0           (optional) ILogger components to pass log messages components to pass log messages
@@ -95,6 +103,9 @@ import (
 //		}
 type IdentifiablePostgresPersistence[T any, K any] struct {
 	*PostgresPersistence[T]
+	// Versioned turns on optimistic concurrency. When items implement IVersioned
+	// they are updated only when their version matches the stored one.
+	Versioned bool
 }
 
 // InheritIdentifiablePostgresPersistence creates a new instance of the persistence component.
@@ -114,6 +125,35 @@ func InheritIdentifiablePostgresPersistence[T any, K any](overrides IPostgresPer
 	return c
 }
 
+// Configure configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config configuration parameters to be set.
+func (c *IdentifiablePostgresPersistence[T, K]) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.PostgresPersistence.Configure(ctx, config)
+	c.Versioned = config.GetAsBooleanWithDefault("options.versioned", c.Versioned)
+}
+
+// GetPageByFilterWithToken gets a page of data items retrieved by a given filter using keyset paging.
+// The "id" field is added to sort parameters when it is missing to make the order unique.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- filter            (optional) a filter JSON object
+//		- paging            paging parameters with a token from the previous page
+//		- sort              sort parameters
+//		- selection         (optional) projection JSON object, it must include the sort fields
+//		- args              (optional) query parameters referenced by the filter
+//	Returns: data page or error.
+func (c *IdentifiablePostgresPersistence[T, K]) GetPageByFilterWithToken(ctx context.Context,
+	filter string, paging cquery.TokenizedPagingParams, sort cquery.SortParams, selection string,
+	args ...any) (page cquery.TokenizedDataPage[T], err error) {
+
+	return c.PostgresPersistence.GetPageByFilterWithToken(ctx, filter, paging,
+		cpersist.AddKeysetSortField(sort, "id"), selection, args...)
+}
+
 // GetListByIds gets a list of data items retrieved by given unique ids.
 //
 //	Parameters:
@@ -125,9 +165,9 @@ func (c *IdentifiablePostgresPersistence[T, K]) GetListByIds(ctx context.Context
 
 	ln := len(ids)
 	params := c.GenerateParameters(ln)
-	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE \"id\" IN(" + params + ")"
+	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE " + c.filterDeleted(ctx, "\"id\" IN("+params+")")
 
-	rows, err := c.Client.Query(ctx, query, ItemsToAnySlice(ids)...)
+	rows, err := c.GetClient(ctx).Query(ctx, query, ItemsToAnySlice(ids)...)
 	if err != nil {
 		return nil, err
 	}
@@ -156,6 +196,7 @@ func (c *IdentifiablePostgresPersistence[T, K]) GetListByIds(ctx context.Context
 }
 
 // GetOneById gets a data item by its unique id.
+// In soft-delete mode deleted items are not returned unless the context is created by cpersist.WithDeleted.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -164,9 +205,9 @@ func (c *IdentifiablePostgresPersistence[T, K]) GetListByIds(ctx context.Context
 // Returns: data item or error.
 func (c *IdentifiablePostgresPersistence[T, K]) GetOneById(ctx context.Context, id K) (item T, err error) {
 
-	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE \"id\"=$1"
+	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE " + c.filterDeleted(ctx, "\"id\"=$1")
 
-	rows, err := c.Client.Query(ctx, query, id)
+	rows, err := c.GetClient(ctx).Query(ctx, query, id)
 	if err != nil {
 		return item, err
 	}
@@ -195,7 +236,10 @@ func (c *IdentifiablePostgresPersistence[T, K]) Create(ctx context.Context, item
 	newItem := c.cloneItem(item)
 	newItem = GenerateObjectIdIfNotExists[T](newItem)
 
-	return c.PostgresPersistence.Create(ctx, newItem)
+	return c.trackChange(ctx, cpersist.ChangeTypeCreated, GetObjectId[K](newItem), false,
+		func(ctx context.Context) (T, error) {
+			return c.PostgresPersistence.Create(ctx, newItem)
+		})
 }
 
 // Set a data item. If the data item exists it updates it,
@@ -206,6 +250,14 @@ func (c *IdentifiablePostgresPersistence[T, K]) Create(ctx context.Context, item
 //		- item              an item to be set.
 //	Returns: (optional)  updated item or error.
 func (c *IdentifiablePostgresPersistence[T, K]) Set(ctx context.Context, item T) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, GetObjectId[K](item), true,
+		func(ctx context.Context) (T, error) {
+			return c.set(ctx, item)
+		})
+}
+
+func (c *IdentifiablePostgresPersistence[T, K]) set(ctx context.Context, item T) (result T, err error) {
+	item = c.trackItem(item, true)
 	objMap, convErr := c.Overrides.ConvertFromPublic(item)
 	if convErr != nil {
 		return result, convErr
@@ -225,7 +277,7 @@ func (c *IdentifiablePostgresPersistence[T, K]) Set(ctx context.Context, item T)
 		" VALUES (" + paramsStr + ")" +
 		" ON CONFLICT (\"id\") DO UPDATE SET " + setParams + " RETURNING *"
 
-	rows, err := c.Client.Query(ctx, query, values...)
+	rows, err := c.GetClient(ctx).Query(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
@@ -249,12 +301,30 @@ func (c *IdentifiablePostgresPersistence[T, K]) Set(ctx context.Context, item T)
 }
 
 // Update a data item.
+// When Versioned option is on and the item implements IVersioned the item is updated
+// only when its version matches the stored one, and the version is incremented.
+// ConflictError is returned when versions do not match.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- item              an item to be updated.
 //	Returns          (optional)  updated item or error.
 func (c *IdentifiablePostgresPersistence[T, K]) Update(ctx context.Context, item T) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, GetObjectId[K](item), true,
+		func(ctx context.Context) (T, error) {
+			return c.update(ctx, item)
+		})
+}
+
+func (c *IdentifiablePostgresPersistence[T, K]) update(ctx context.Context, item T) (result T, err error) {
+	version, versioned := "", false
+	if c.Versioned {
+		if version, versioned = cpersist.GetObjectVersion(item); versioned {
+			item = c.setItemVersion(item, cpersist.NextVersion(version))
+		}
+	}
+	item = c.trackItem(item, false)
+
 	objMap, convErr := c.Overrides.ConvertFromPublic(item)
 	if convErr != nil {
 		return result, convErr
@@ -265,14 +335,27 @@ func (c *IdentifiablePostgresPersistence[T, K]) Update(ctx context.Context, item
 	values = append(values, id)
 
 	query := "UPDATE " + c.QuotedTableName() +
-		" SET " + paramsStr + " WHERE \"id\"=$" + strconv.FormatInt((int64)(len(values)), 10) + " RETURNING *"
+		" SET " + paramsStr + " WHERE \"id\"=$" + strconv.FormatInt((int64)(len(values)), 10)
+
+	if versioned {
+		condition, args, err := c.composeVersionCondition(version, len(values)+1)
+		if err != nil {
+			return result, err
+		}
+		query += " AND " + condition
+		values = append(values, args...)
+	}
+	query += " RETURNING *"
 
-	rows, err := c.Client.Query(ctx, query, values...)
+	rows, err := c.GetClient(ctx).Query(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
 	defer rows.Close()
 	if !rows.Next() {
+		if versioned && rows.Err() == nil {
+			return result, c.checkVersionConflict(ctx, id)
+		}
 		return result, rows.Err()
 	}
 
@@ -289,6 +372,8 @@ func (c *IdentifiablePostgresPersistence[T, K]) Update(ctx context.Context, item
 }
 
 // UpdatePartially updates only few selected fields in a data item.
+// When Versioned option is on the version is taken from "version" field in the data
+// or from the stored item, and ConflictError is returned when it does not match.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -296,6 +381,23 @@ func (c *IdentifiablePostgresPersistence[T, K]) Update(ctx context.Context, item
 //		- data              a map with fields to be updated.
 //	Returns: updated item or error.
 func (c *IdentifiablePostgresPersistence[T, K]) UpdatePartially(ctx context.Context, id K, data cdata.AnyValueMap) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updatePartially(ctx, id, data)
+		})
+}
+
+func (c *IdentifiablePostgresPersistence[T, K]) updatePartially(ctx context.Context, id K, data cdata.AnyValueMap) (result T, err error) {
+	version, versioned := "", false
+	if c.Versioned && cpersist.IsVersionedType[T]() {
+		if version, err = c.getPartialVersion(ctx, id, data); err != nil {
+			return result, err
+		}
+		versioned = true
+		data = *cdata.NewAnyValueMapFromMaps(data.Value(), map[string]any{"version": cpersist.NextVersion(version)})
+	}
+	data = c.trackPartialData(data)
+
 	objMap, convErr := c.Overrides.ConvertFromPublicPartial(data.Value())
 	if convErr != nil {
 		return result, convErr
@@ -305,15 +407,28 @@ func (c *IdentifiablePostgresPersistence[T, K]) UpdatePartially(ctx context.Cont
 	values = append(values, id)
 
 	query := "UPDATE " + c.QuotedTableName() +
-		" SET " + paramsStr + " WHERE \"id\"=$" + strconv.FormatInt((int64)(len(values)), 10) + " RETURNING *"
+		" SET " + paramsStr + " WHERE \"id\"=$" + strconv.FormatInt((int64)(len(values)), 10)
 
-	rows, err := c.Client.Query(ctx, query, values...)
+	if versioned {
+		condition, args, err := c.composeVersionCondition(version, len(values)+1)
+		if err != nil {
+			return result, err
+		}
+		query += " AND " + condition
+		values = append(values, args...)
+	}
+	query += " RETURNING *"
+
+	rows, err := c.GetClient(ctx).Query(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
 	defer rows.Close()
 
 	if !rows.Next() {
+		if versioned && rows.Err() == nil {
+			return result, c.checkVersionConflict(ctx, id)
+		}
 		return result, rows.Err()
 	}
 
@@ -330,15 +445,36 @@ func (c *IdentifiablePostgresPersistence[T, K]) UpdatePartially(ctx context.Cont
 }
 
 // DeleteById deletes a data item by its unique id.
+// In soft-delete mode the item is marked as deleted instead.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- id                an id of the item to be deleted
 //	Returns: (optional)  deleted item or error.
 func (c *IdentifiablePostgresPersistence[T, K]) DeleteById(ctx context.Context, id K) (result T, err error) {
+	if c.isSoftDelete() {
+		return c.markDeletedById(ctx, id, true)
+	}
+	return c.PurgeById(ctx, id)
+}
+
+// PurgeById physically deletes a data item by its unique id regardless of soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- id                an id of the item to be deleted
+//	Returns: (optional)  deleted item or error.
+func (c *IdentifiablePostgresPersistence[T, K]) PurgeById(ctx context.Context, id K) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeDeleted, id, false,
+		func(ctx context.Context) (T, error) {
+			return c.purgeById(ctx, id)
+		})
+}
+
+func (c *IdentifiablePostgresPersistence[T, K]) purgeById(ctx context.Context, id K) (result T, err error) {
 	query := "DELETE FROM " + c.QuotedTableName() + " WHERE \"id\"=$1 RETURNING *"
 
-	rows, err := c.Client.Query(ctx, query, id)
+	rows, err := c.GetClient(ctx).Query(ctx, query, id)
 	if err != nil {
 		return result, err
 	}
@@ -360,7 +496,55 @@ func (c *IdentifiablePostgresPersistence[T, K]) DeleteById(ctx context.Context,
 	return result, rows.Err()
 }
 
+// RestoreById restores a data item marked as deleted in soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- id                an id of the item to be restored
+//	Returns: (optional)  restored item or error.
+func (c *IdentifiablePostgresPersistence[T, K]) RestoreById(ctx context.Context, id K) (result T, err error) {
+	return c.markDeletedById(ctx, id, false)
+}
+
+func (c *IdentifiablePostgresPersistence[T, K]) markDeletedById(ctx context.Context, id K, deleted bool) (result T, err error) {
+	changeType := cpersist.ChangeTypeUpdated
+	if deleted {
+		changeType = cpersist.ChangeTypeDeleted
+	}
+	return c.trackChange(ctx, changeType, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updateDeletedById(ctx, id, deleted)
+		})
+}
+
+func (c *IdentifiablePostgresPersistence[T, K]) updateDeletedById(ctx context.Context, id K, deleted bool) (result T, err error) {
+	query := "UPDATE " + c.QuotedTableName() + " SET " + c.composeDeletedSet(deleted) +
+		" WHERE \"id\"=$1 RETURNING *"
+
+	rows, err := c.GetClient(ctx).Query(ctx, query, id)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return result, rows.Err()
+	}
+
+	result, convErr := c.Overrides.ConvertToPublic(rows)
+	if convErr != nil {
+		return result, convErr
+	}
+	if deleted {
+		c.Logger.Trace(ctx, "Marked as deleted in %s with id = %s", c.TableName, id)
+	} else {
+		c.Logger.Trace(ctx, "Restored in %s with id = %s", c.TableName, id)
+	}
+	return result, nil
+}
+
 // DeleteByIds deletes multiple data items by their unique ids.
+// In soft-delete mode the items are marked as deleted instead.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -372,8 +556,19 @@ func (c *IdentifiablePostgresPersistence[T, K]) DeleteByIds(ctx context.Context,
 	paramsStr := c.GenerateParameters(ln)
 
 	query := "DELETE FROM " + c.QuotedTableName() + " WHERE \"id\" IN(" + paramsStr + ")"
+	if c.isSoftDelete() {
+		query = "UPDATE " + c.QuotedTableName() + " SET " + c.composeDeletedSet(true) +
+			" WHERE " + c.filterDeleted(ctx, "\"id\" IN("+paramsStr+")")
+	}
 
-	rows, err := c.Client.Query(ctx, query, ItemsToAnySlice[K](ids)...)
+	if c.isTrackingChanges() {
+		return c.trackChanges(ctx, cpersist.ChangeTypeDeleted, ids, c.isSoftDelete(),
+			func(ctx context.Context) ([]T, error) {
+				return c.readDeletedItems(ctx, query+" RETURNING *", ItemsToAnySlice[K](ids)...)
+			})
+	}
+
+	rows, err := c.GetClient(ctx).Query(ctx, query, ItemsToAnySlice[K](ids)...)
 	if err != nil {
 		return err
 	}
@@ -393,3 +588,64 @@ func (c *IdentifiablePostgresPersistence[T, K]) DeleteByIds(ctx context.Context,
 	}
 	return rows.Err()
 }
+
+// readDeletedItems executes a delete query that returns rows and reads the deleted items.
+func (c *IdentifiablePostgresPersistence[T, K]) readDeletedItems(ctx context.Context, query string, values ...any) ([]T, error) {
+	rows, err := c.GetClient(ctx).Query(ctx, query, values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]T, 0)
+	for rows.Next() {
+		item, convErr := c.Overrides.ConvertToPublic(rows)
+		if convErr != nil {
+			return items, convErr
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return items, err
+	}
+
+	c.Logger.Trace(ctx, "Deleted %d items from %s", len(items), c.TableName)
+	return items, nil
+}
+
+func (c *IdentifiablePostgresPersistence[T, K]) setItemVersion(item T, version string) T {
+	var obj any = c.cloneItem(item)
+	cpersist.SetObjectVersion(&obj, version)
+	if result, ok := obj.(T); ok {
+		return result
+	}
+	return item
+}
+
+func (c *IdentifiablePostgresPersistence[T, K]) getPartialVersion(ctx context.Context, id K, data cdata.AnyValueMap) (string, error) {
+	if version, ok := data.GetAsNullableString("version"); ok {
+		return version, nil
+	}
+	item, err := c.GetOneById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	version, _ := cpersist.GetObjectVersion(item)
+	return version, nil
+}
+
+func (c *IdentifiablePostgresPersistence[T, K]) composeVersionCondition(version string, startIndex int) (string, []any, error) {
+	return c.FilterCompiler.Compile(cpersist.ComposeVersionFilter(version), startIndex)
+}
+
+func (c *IdentifiablePostgresPersistence[T, K]) checkVersionConflict(ctx context.Context, id any) error {
+	count, err := c.GetCountByFilter(ctx, "\"id\"=$1", id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return cpersist.NewVersionConflictError(cctx.GetTraceId(ctx), id)
+	}
+	c.Logger.Trace(ctx, "Nothing to update in %s with id = %s", c.TableName, id)
+	return nil
+}
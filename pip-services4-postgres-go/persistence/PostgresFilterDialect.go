@@ -0,0 +1,168 @@
+package persistence
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// PostgresFilterDialect defines PostgreSQL syntax to compile FilterExpression into parameterized queries.
+//
+// When JsonColumn is set, fields other than "id" are extracted as text from the JSON document
+// stored in that column using ->> operator. Extracted fields are cast to numeric when they are
+// compared with numbers, so ranges of numeric fields are not compared as strings.
+//
+// Sort fields of JSON documents are ordered as JSONB values: numbers by their values and strings as text.
+// Nulls are sorted as the smallest values, as keyset filters expect.
+type PostgresFilterDialect struct {
+	JsonColumn string
+	// Compares fields of JSON documents as JSONB values to match the sort order
+	jsonbValues bool
+}
+
+// NewPostgresFilterCompiler creates a filter compiler for plain PostgreSQL tables.
+//
+//	Returns: a new filter compiler.
+func NewPostgresFilterCompiler() *cpersist.SqlFilterCompiler {
+	return cpersist.NewSqlFilterCompiler(&PostgresFilterDialect{})
+}
+
+// NewJsonPostgresFilterCompiler creates a filter compiler for PostgreSQL tables that keep data in JSON column.
+//
+//	Parameters:
+//		- jsonColumn a name of the JSON or JSONB column.
+//	Returns: a new filter compiler.
+func NewJsonPostgresFilterCompiler(jsonColumn string) *cpersist.SqlFilterCompiler {
+	return cpersist.NewSqlFilterCompiler(&PostgresFilterDialect{JsonColumn: jsonColumn})
+}
+
+// FormatField converts a field name into a quoted column or a JSON path extraction.
+func (c *PostgresFilterDialect) FormatField(field string) string {
+	if c.jsonbValues && c.JsonColumn != "" && field != "id" {
+		return c.formatJsonbField(field)
+	}
+	if c.JsonColumn != "" && field != "id" {
+		path := strings.Split(field, ".")
+		if len(path) == 1 {
+			return c.JsonColumn + "->>'" + field + "'"
+		}
+		return c.JsonColumn + "#>>'{" + strings.Join(path, ",") + "}'"
+	}
+	return "\"" + strings.ReplaceAll(field, ".", "\".\"") + "\""
+}
+
+// JSON nulls are converted into SQL nulls, so they are sorted and compared as missing fields
+func (c *PostgresFilterDialect) formatJsonbField(field string) string {
+	path := strings.Split(field, ".")
+	column := c.JsonColumn + "->'" + field + "'"
+	if len(path) > 1 {
+		column = c.JsonColumn + "#>'{" + strings.Join(path, ",") + "}'"
+	}
+	return "NULLIF((" + column + ")::jsonb,'null')"
+}
+
+// FormatSort converts a sort field into ORDER BY expression with explicit order of nulls.
+// Nulls go first in ascending and last in descending order, while PostgreSQL
+// by default sorts them as the largest values.
+func (c *PostgresFilterDialect) FormatSort(field string, ascending bool) string {
+	column := c.FormatField(field)
+	if c.JsonColumn != "" && field != "id" {
+		column = c.formatJsonbField(field)
+	}
+	if ascending {
+		return column + " ASC NULLS FIRST"
+	}
+	return column + " DESC NULLS LAST"
+}
+
+// FormatComparison casts a field extracted from JSON document to numeric when it is compared with numbers.
+// Values of FilterParams are strings, so numeric strings are treated as numbers by range operators.
+// Equality and "in" operators compare with numbers only when the values have numeric types.
+func (c *PostgresFilterDialect) FormatComparison(field string, operator cpersist.FilterOperator, values []any) string {
+	column := c.FormatField(field)
+	if c.jsonbValues || c.JsonColumn == "" || field == "id" ||
+		!cpersist.IsSqlNumericComparison(operator, values, true) {
+		return column
+	}
+	return "(" + column + ")::numeric"
+}
+
+// FormatParameter returns a positional parameter placeholder like "$1".
+func (c *PostgresFilterDialect) FormatParameter(index int) string {
+	return "$" + strconv.Itoa(index)
+}
+
+// FormatProjection converts included fields into a SELECT column list.
+// For JSON tables the fields are extracted from the JSON document by JSONB paths
+// and composed into a new document with jsonb_build_object function.
+func (c *PostgresFilterDialect) FormatProjection(fields []*cpersist.ProjectionField) string {
+	if c.JsonColumn == "" {
+		columns := make([]string, len(fields))
+		for i, field := range fields {
+			columns[i] = c.FormatField(field.Name)
+		}
+		return strings.Join(columns, ",")
+	}
+	return c.formatJsonObject(fields) + " AS " + c.JsonColumn
+}
+
+func (c *PostgresFilterDialect) formatJsonObject(fields []*cpersist.ProjectionField) string {
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		value := c.JsonColumn + "#>'{" + strings.ReplaceAll(field.Path, ".", ",") + "}'"
+		if len(field.Children) > 0 {
+			value = c.formatJsonObject(field.Children)
+		}
+		values[i] = "'" + field.Name + "'," + value
+	}
+	return "jsonb_build_object(" + strings.Join(values, ",") + ")"
+}
+
+// FormatAggregate converts an aggregate function into SQL expression.
+// Sums and averages of JSON fields are calculated over their numeric values.
+// Minimum and maximum of JSON fields are found among their text values.
+func (c *PostgresFilterDialect) FormatAggregate(function string, field string) string {
+	column := ""
+	if field != "" {
+		column = c.FormatField(field)
+		if c.JsonColumn != "" && field != "id" && (function == cquery.AggregateSum || function == cquery.AggregateAvg) {
+			column = "(" + column + ")::double precision"
+		}
+	}
+	return cpersist.FormatSqlAggregate(function, column)
+}
+
+// encodeJsonbFilter copies a filter and encodes values of JSON document fields
+// into JSON, so they can be compared with fields formatted as JSONB values.
+func (c *PostgresFilterDialect) encodeJsonbFilter(filter *cpersist.FilterExpression) (*cpersist.FilterExpression, error) {
+	if filter == nil {
+		return nil, nil
+	}
+
+	result := &cpersist.FilterExpression{
+		Operator: filter.Operator,
+		Field:    filter.Field,
+		Values:   filter.Values,
+	}
+	if filter.Field != "" && filter.Field != "id" {
+		result.Values = make([]any, len(filter.Values))
+		for i, value := range filter.Values {
+			buf, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			result.Values[i] = string(buf)
+		}
+	}
+	for _, child := range filter.Children {
+		child, err := c.encodeJsonbFilter(child)
+		if err != nil {
+			return nil, err
+		}
+		result.Children = append(result.Children, child)
+	}
+	return result, nil
+}
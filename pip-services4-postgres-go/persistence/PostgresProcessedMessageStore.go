@@ -0,0 +1,200 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+)
+
+// PostgresProcessedMessageStore records ids of processed messages in PostgreSQL table.
+// It implements IProcessedMessageStore interface of the messaging module and is used
+// by IdempotentMessageReceiver to skip redelivered messages.
+//
+// When the store shares the connection with other persistence components, the ids are recorded
+// in the transaction carried by the context. So ids recorded within the transaction
+// that handles the message are committed together with the changes made by the handler.
+// Messages are claimed by conditional inserts, so concurrent receivers cannot claim the same message.
+//
+//	Configuration parameters
+//		- table:                       (optional) PostgreSQL table name (default: processed_messages)
+//		- schema:                      (optional) PostgreSQL schema, default "public"
+//		- connection(s):
+//			- discovery_key:             (optional) a key to retrieve the connection from IDiscovery
+//			- host:                      host name or IP address
+//			- port:                      port number (default: 5432)
+//			- uri:                       resource URI or connection string with all parameters in it
+//		- credential(s):
+//			- store_key:                 (optional) a key to retrieve the credentials from ICredentialStore
+//			- username:                  (optional) user name
+//			- password:                  (optional) user password
+//		- options:
+//			- retention:            (optional) number of milliseconds to keep processed message ids (default: 86400000)
+//			- claim_timeout:        (optional) number of milliseconds to keep claims of messages in processing (default: 300000)
+//
+//	References:
+//		- *:logger:*:*:1.0           (optional) ILogger components to pass log messages
+//		- *:discovery:*:*:1.0        (optional) IDiscovery services
+//		- *:credential-store:*:*:1.0 (optional) Credential stores to resolve credentials
+//
+//	Example:
+//		store := NewPostgresProcessedMessageStore()
+//		store.Configure(ctx, cconf.NewConfigParamsFromTuples(
+//			"connection.host", "localhost",
+//			"connection.port", 5432,
+//			"connection.database", "test",
+//		))
+//		err := store.Open(ctx)
+//		...
+//		receiver := cqueues.NewIdempotentMessageReceiver(myReceiver, store)
+type PostgresProcessedMessageStore struct {
+	*PostgresPersistence[map[string]any]
+	// The period of time to keep processed message ids
+	Retention time.Duration
+	// The period of time to keep claims of messages in processing
+	ClaimTimeout time.Duration
+}
+
+// NewPostgresProcessedMessageStore creates a new instance of the store.
+//
+//	Returns: *PostgresProcessedMessageStore
+func NewPostgresProcessedMessageStore() *PostgresProcessedMessageStore {
+	c := &PostgresProcessedMessageStore{
+		Retention:    time.Duration(24) * time.Hour,
+		ClaimTimeout: time.Duration(5) * time.Minute,
+	}
+	c.PostgresPersistence = InheritPostgresPersistence[map[string]any](c, "processed_messages")
+	return c
+}
+
+// Configure configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config configuration parameters to be set.
+func (c *PostgresProcessedMessageStore) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.PostgresPersistence.Configure(ctx, config)
+
+	retention := config.GetAsLongWithDefault("options.retention", c.Retention.Milliseconds())
+	c.Retention = time.Duration(retention) * time.Millisecond
+	claimTimeout := config.GetAsLongWithDefault("options.claim_timeout", c.ClaimTimeout.Milliseconds())
+	c.ClaimTimeout = time.Duration(claimTimeout) * time.Millisecond
+}
+
+// DefineSchema defines the table to keep processed message ids.
+func (c *PostgresProcessedMessageStore) DefineSchema() {
+	c.ClearSchema()
+	c.PostgresPersistence.DefineSchema()
+	c.EnsureSchema("CREATE TABLE IF NOT EXISTS " + c.QuotedTableName() +
+		" (\"id\" TEXT PRIMARY KEY, \"processed_time\" TIMESTAMP WITH TIME ZONE NOT NULL," +
+		" \"claimed\" BOOLEAN NOT NULL DEFAULT FALSE)")
+	c.EnsureIndex(c.TableName+"_processed_time", map[string]string{"\"processed_time\"": "1"}, nil)
+}
+
+// IsProcessed checks if the message was already processed.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- messageId a unique id of the message.
+//	Returns: true if the message was processed within the retention period or error.
+func (c *PostgresProcessedMessageStore) IsProcessed(ctx context.Context, messageId string) (bool, error) {
+	query := "SELECT COUNT(*) FROM " + c.QuotedTableName() +
+		" WHERE \"id\"=$1 AND NOT \"claimed\" AND \"processed_time\">$2"
+
+	var count int64
+	err := c.GetClient(ctx).QueryRow(ctx, query, messageId, time.Now().Add(-c.Retention).UTC()).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// TryClaim atomically claims the message for processing
+// unless it was already processed or claimed by another receiver.
+// Expired records are claimed again.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- messageId a unique id of the message.
+//	Returns: true if the message was claimed or false if it was processed or claimed before, or error.
+func (c *PostgresProcessedMessageStore) TryClaim(ctx context.Context, messageId string) (bool, error) {
+	table := c.QuotedTableName()
+	query := "INSERT INTO " + table + " (\"id\", \"processed_time\", \"claimed\") VALUES ($1, $2, TRUE)" +
+		" ON CONFLICT (\"id\") DO UPDATE SET \"processed_time\"=EXCLUDED.\"processed_time\", \"claimed\"=TRUE" +
+		" WHERE (" + table + ".\"claimed\" AND " + table + ".\"processed_time\"<=$3)" +
+		" OR (NOT " + table + ".\"claimed\" AND " + table + ".\"processed_time\"<=$4)" +
+		" RETURNING \"id\""
+
+	now := time.Now().UTC()
+	var id string
+	err := c.GetClient(ctx).QueryRow(ctx, query, messageId, now,
+		now.Add(-c.ClaimTimeout), now.Add(-c.Retention)).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	c.Logger.Trace(ctx, "Claimed message %s in %s", messageId, c.TableName)
+	return true, nil
+}
+
+// ReleaseClaim removes the claim of the message that failed to be processed,
+// so the message can be processed again when it is redelivered.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- messageId a unique id of the message.
+//	Returns: error or nil for success.
+func (c *PostgresProcessedMessageStore) ReleaseClaim(ctx context.Context, messageId string) error {
+	query := "DELETE FROM " + c.QuotedTableName() + " WHERE \"id\"=$1 AND \"claimed\""
+
+	_, err := c.GetClient(ctx).Exec(ctx, query, messageId)
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Trace(ctx, "Released claim of message %s in %s", messageId, c.TableName)
+	return nil
+}
+
+// MarkProcessed records the message as processed.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- messageId a unique id of the message.
+//	Returns: error or nil for success.
+func (c *PostgresProcessedMessageStore) MarkProcessed(ctx context.Context, messageId string) error {
+	query := "INSERT INTO " + c.QuotedTableName() + " (\"id\", \"processed_time\", \"claimed\") VALUES ($1, $2, FALSE)" +
+		" ON CONFLICT (\"id\") DO UPDATE SET \"processed_time\"=EXCLUDED.\"processed_time\", \"claimed\"=FALSE"
+
+	_, err := c.GetClient(ctx).Exec(ctx, query, messageId, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Trace(ctx, "Marked message %s as processed in %s", messageId, c.TableName)
+	return nil
+}
+
+// Cleanup removes ids of messages processed before the retention period and expired claims.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//	Returns: error or nil for success.
+func (c *PostgresProcessedMessageStore) Cleanup(ctx context.Context) error {
+	query := "DELETE FROM " + c.QuotedTableName() +
+		" WHERE \"processed_time\"<=$1 OR (\"claimed\" AND \"processed_time\"<=$2)"
+
+	now := time.Now().UTC()
+	result, err := c.GetClient(ctx).Exec(ctx, query, now.Add(-c.Retention), now.Add(-c.ClaimTimeout))
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Trace(ctx, "Removed %d expired processed messages from %s", result.RowsAffected(), c.TableName)
+	return nil
+}
@@ -0,0 +1,64 @@
+package persistence
+
+import (
+	"context"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+)
+
+// ComposeProjection converts projection parameters into a SELECT column list
+// that can be passed to GetPageByFilter or GetListByFilter as selection.
+// Fields of JSON tables are extracted from the JSON documents.
+//
+//	Parameters:
+//		- projection projection parameters, nil or empty to select all fields.
+//	Returns: a column list (empty to select all fields) or BadRequestError if a field name is invalid.
+func (c *PostgresPersistence[T]) ComposeProjection(projection *cquery.ProjectionParams) (string, error) {
+	return c.FilterCompiler.CompileProjection(projection)
+}
+
+// GetPageByFilterWithProjection gets a page of data items retrieved by a given filter and sorted
+// according to sort parameters. Only the fields listed in the projection are read, other fields are left empty.
+// This method shall be called by a func (c * PostgresPersistence) GetPageByFilter method from child class that
+// receives FilterParams and converts them into a filter function.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- filter            (optional) a filter JSON object
+//		- paging            (optional) paging parameters
+//		- sort              (optional) sorting JSON object
+//		- projection        (optional) projection parameters, e.g. "id,key,content(field1,field2)"
+//		- args              (optional) query parameters referenced by the filter
+//	Returns: receives a data page or error.
+func (c *PostgresPersistence[T]) GetPageByFilterWithProjection(ctx context.Context,
+	filter string, paging cquery.PagingParams, sort string, projection *cquery.ProjectionParams,
+	args ...any) (page cquery.DataPage[T], err error) {
+
+	selection, err := c.ComposeProjection(projection)
+	if err != nil {
+		return *cquery.NewEmptyDataPage[T](), err
+	}
+	return c.GetPageByFilter(ctx, filter, paging, sort, selection, args...)
+}
+
+// GetListByFilterWithProjection gets a list of data items retrieved by a given filter and sorted
+// according to sort parameters. Only the fields listed in the projection are read, other fields are left empty.
+// This method shall be called by a func (c * PostgresPersistence) GetListByFilter method from child class that
+// receives FilterParams and converts them into a filter function.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- filter           (optional) a filter JSON object
+//		- sort             (optional) sorting JSON object
+//		- projection       (optional) projection parameters, e.g. "id,key,content(field1,field2)"
+//		- args             (optional) query parameters referenced by the filter
+//	Returns: data list or error.
+func (c *PostgresPersistence[T]) GetListByFilterWithProjection(ctx context.Context,
+	filter string, sort string, projection *cquery.ProjectionParams, args ...any) (items []T, err error) {
+
+	selection, err := c.ComposeProjection(projection)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetListByFilter(ctx, filter, sort, selection, args...)
+}
@@ -0,0 +1,689 @@
+package queues
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	keys "github.com/pip-services4/pip-services4-go/pip-services4-data-go/keys"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-postgres-go/persistence"
+)
+
+const messageColumns = "\"id\", \"message_id\", \"message_type\", \"trace_id\", \"message\", \"headers\", \"sent_time\", \"delivery_count\""
+
+// messageLock identifies a message row locked by a receiver.
+type messageLock struct {
+	id    int64
+	token string
+}
+
+// postgresMessageTable keeps messages of one or many queues in PostgreSQL table.
+type postgresMessageTable struct {
+	*persist.PostgresPersistence[map[string]any]
+}
+
+func newPostgresMessageTable() *postgresMessageTable {
+	c := &postgresMessageTable{}
+	c.PostgresPersistence = persist.InheritPostgresPersistence[map[string]any](c, "messages")
+	return c
+}
+
+// DefineSchema defines the table to keep messages.
+func (c *postgresMessageTable) DefineSchema() {
+	c.ClearSchema()
+	c.PostgresPersistence.DefineSchema()
+	c.EnsureSchema("CREATE TABLE IF NOT EXISTS " + c.QuotedTableName() +
+		" (\"id\" BIGSERIAL PRIMARY KEY, \"queue\" TEXT NOT NULL, \"message_id\" TEXT NOT NULL DEFAULT '', " +
+		"\"message_type\" TEXT NOT NULL DEFAULT '', \"trace_id\" TEXT NOT NULL DEFAULT '', \"message\" BYTEA, " +
+		"\"headers\" TEXT NOT NULL DEFAULT '', \"sent_time\" TIMESTAMP WITH TIME ZONE NOT NULL, " +
+		"\"visible_time\" TIMESTAMP WITH TIME ZONE NOT NULL, \"delivery_count\" INTEGER NOT NULL DEFAULT 0, " +
+		"\"lock_token\" TEXT)")
+	c.EnsureSchema("CREATE INDEX IF NOT EXISTS " + c.QuoteIdentifier(c.TableName+"_visible") +
+		" ON " + c.QuotedTableName() + " (\"queue\", \"visible_time\")")
+}
+
+// PostgresMessageQueue are durable message queue that keeps messages in PostgreSQL table.
+// It is used in small deployments that have PostgreSQL but no message broker.
+//
+// Many queues can share the same table, every queue keeps its messages in rows with its name.
+// Receivers lock messages with SELECT ... FOR UPDATE SKIP LOCKED and hide them for the lock timeout.
+// Messages that were not completed within the lock timeout become visible to other receivers.
+// Delayed messages are kept invisible until their visible time.
+//
+// When the queue shares the connection with other persistence components, messages sent within
+// a transaction carried by the context are delivered only after the transaction is committed.
+//
+//	Configuration parameters:
+//		- queue:                       (optional) name of the queue (default: name of the component)
+//		- table:                       (optional) PostgreSQL table name (default: messages)
+//		- schema:                      (optional) PostgreSQL schema, default "public"
+//		- interval:                    (optional) number of milliseconds to check for new messages while waiting (default: 1000)
+//		- connection(s):
+//			- discovery_key:             (optional) a key to retrieve the connection from IDiscovery
+//			- host:                      host name or IP address
+//			- port:                      port number (default: 5432)
+//			- uri:                       resource URI or connection string with all parameters in it
+//		- credential(s):
+//			- store_key:                 (optional) a key to retrieve the credentials from ICredentialStore
+//			- username:                  (optional) user name
+//			- password:                  (optional) user password
+//		- options:
+//			- lock_timeout:         (optional) number of milliseconds to hide received messages from other receivers (default: 30000)
+//			- dead_letter_queue:    (optional) name of the queue in the same table to move poison messages to (default: none, messages are dropped)
+//			- max_delivery_count:   (optional) maximum number of deliveries before a message is moved to dead letter queue (default: 0 unlimited)
+//
+//	References:
+//		- *:logger:*:*:1.0           (optional) ILogger components to pass log messages
+//		- *:counters:*:*:1.0         (optional) ICounters components to pass collected measurements
+//		- *:discovery:*:*:1.0        (optional) IDiscovery services
+//		- *:credential-store:*:*:1.0 (optional) Credential stores to resolve credentials
+//		- *:connection:postgres:*:1.0 (optional) Shared connection to PostgreSQL database
+//
+//	Example:
+//		queue := NewPostgresMessageQueue("myqueue")
+//		queue.Configure(ctx, cconf.NewConfigParamsFromTuples(
+//			"connection.host", "localhost",
+//			"connection.port", 5432,
+//			"connection.database", "test",
+//		))
+//		err := queue.Open(ctx)
+//		...
+//		err = queue.Send(ctx, cqueues.NewMessageEnvelope("123", "mymessage", []byte("ABC")))
+//		message, err := queue.Receive(ctx, 10000*time.Millisecond)
+//		if message != nil {
+//			...
+//			err = queue.Complete(ctx, message)
+//		}
+type PostgresMessageQueue struct {
+	*cqueues.MessageQueue
+	// The interval to check for new messages while waiting
+	Interval time.Duration
+	// The time to hide received messages from other receivers
+	LockTimeout time.Duration
+
+	table           *postgresMessageTable
+	deadLetterQueue string
+	cancel          int32
+}
+
+// NewPostgresMessageQueue creates a new instance of the message queue.
+//
+//	Parameters:
+//		- name (optional) a queue name.
+//	Returns: *PostgresMessageQueue
+func NewPostgresMessageQueue(name string) *PostgresMessageQueue {
+	c := &PostgresMessageQueue{
+		Interval:    time.Duration(1000) * time.Millisecond,
+		LockTimeout: time.Duration(30000) * time.Millisecond,
+		table:       newPostgresMessageTable(),
+	}
+	c.MessageQueue = cqueues.InheritMessageQueue(c, name,
+		cqueues.NewMessagingCapabilities(true, true, true, true, true, true, true, true, true).WithDelay(true))
+	return c
+}
+
+// Configure configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config configuration parameters to be set.
+func (c *PostgresMessageQueue) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.MessageQueue.Configure(ctx, config)
+	c.table.Configure(ctx, config)
+
+	c.Interval = time.Duration(config.GetAsLongWithDefault("interval", c.Interval.Milliseconds())) * time.Millisecond
+	c.LockTimeout = time.Duration(config.GetAsLongWithDefault("options.lock_timeout", c.LockTimeout.Milliseconds())) * time.Millisecond
+	c.deadLetterQueue = config.GetAsStringWithDefault("options.dead_letter_queue", c.deadLetterQueue)
+}
+
+// SetReferences sets references to dependent components.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- references references to locate the component dependencies.
+func (c *PostgresMessageQueue) SetReferences(ctx context.Context, references cref.IReferences) {
+	c.MessageQueue.SetReferences(ctx, references)
+	c.table.SetReferences(ctx, references)
+}
+
+// IsOpen checks if the component is opened.
+//
+//	Returns: true if the component has been opened and false otherwise.
+func (c *PostgresMessageQueue) IsOpen() bool {
+	return c.table.IsOpen()
+}
+
+// Open opens the component and creates the table to keep messages when it does not exist.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occurred.
+func (c *PostgresMessageQueue) Open(ctx context.Context) error {
+	return c.table.Open(ctx)
+}
+
+// Close closes component and frees used resources.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occurred.
+func (c *PostgresMessageQueue) Close(ctx context.Context) error {
+	c.EndListen(ctx)
+	return c.table.Close(ctx)
+}
+
+// Clear removes all messages of the queue.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occurred.
+func (c *PostgresMessageQueue) Clear(ctx context.Context) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	query := "DELETE FROM " + c.table.QuotedTableName() + " WHERE \"queue\"=$1"
+	_, err := c.table.GetClient(ctx).Exec(ctx, query, c.Name())
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Trace(ctx, "Cleared queue %s", c.Name())
+	return nil
+}
+
+// ReadMessageCount reads the current number of messages in the queue to be delivered.
+// Locked and delayed messages are not counted.
+//
+//	Returns: number of messages or error.
+func (c *PostgresMessageQueue) ReadMessageCount() (int64, error) {
+	ctx := context.Background()
+	if err := c.CheckOpen(""); err != nil {
+		return 0, err
+	}
+
+	query := "SELECT COUNT(*) FROM " + c.table.QuotedTableName() + " WHERE \"queue\"=$1 AND \"visible_time\"<=$2"
+
+	var count int64
+	err := c.table.GetClient(ctx).QueryRow(ctx, query, c.Name(), time.Now().UTC()).Scan(&count)
+	return count, err
+}
+
+func (c *PostgresMessageQueue) toMessages(rows pgx.Rows) ([]*cqueues.MessageEnvelope, []int64, error) {
+	defer rows.Close()
+
+	messages := make([]*cqueues.MessageEnvelope, 0)
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var (
+			id       int64
+			headers  string
+			sentTime time.Time
+		)
+		message := cqueues.NewEmptyMessageEnvelope()
+		err := rows.Scan(&id, &message.MessageId, &message.MessageType, &message.TraceId,
+			&message.Message, &headers, &sentTime, &message.DeliveryCount)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		message.SentTime = sentTime.Local()
+		if headers != "" {
+			if err = json.Unmarshal([]byte(headers), &message.Headers); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		messages = append(messages, message)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// Rows returned by UPDATE are not ordered
+	sort.Sort(messagesById{messages: messages, ids: ids})
+	return messages, ids, nil
+}
+
+func (c *PostgresMessageQueue) fromMessage(message *cqueues.MessageEnvelope, now time.Time) ([]any, error) {
+	headers := ""
+	if len(message.Headers) > 0 {
+		buffer, err := json.Marshal(message.Headers)
+		if err != nil {
+			return nil, err
+		}
+		headers = string(buffer)
+	}
+
+	if message.SentTime.IsZero() {
+		message.SentTime = now
+	}
+	visibleTime := now
+	if message.VisibleTime.After(now) {
+		visibleTime = message.VisibleTime
+	}
+
+	return []any{c.Name(), message.MessageId, message.MessageType, message.TraceId, message.Message,
+		headers, message.SentTime.UTC(), visibleTime.UTC(), message.DeliveryCount}, nil
+}
+
+// Send sends a message into the queue.
+// Delayed messages are kept invisible until their visible time.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- message a message envelop to be sent.
+//	Returns: error or nil for success.
+func (c *PostgresMessageQueue) Send(ctx context.Context, message *cqueues.MessageEnvelope) error {
+	return c.SendBatch(ctx, []*cqueues.MessageEnvelope{message})
+}
+
+// SendBatch sends multiple messages into the queue with a single statement.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- messages a list of message envelops to be sent.
+//	Returns: error or nil for success.
+func (c *PostgresMessageQueue) SendBatch(ctx context.Context, messages []*cqueues.MessageEnvelope) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	values := make([]any, 0)
+	rows := ""
+	for index, message := range messages {
+		// Propagate W3C trace context and baggage to receivers
+		cqueues.InjectTraceContext(ctx, message)
+
+		row, err := c.fromMessage(message, now)
+		if err != nil {
+			return err
+		}
+		if index > 0 {
+			rows += ", "
+		}
+		rows += "("
+		for column := range row {
+			if column > 0 {
+				rows += ", "
+			}
+			rows += "$" + strconv.Itoa(len(values)+column+1)
+		}
+		rows += ")"
+		values = append(values, row...)
+	}
+
+	query := "INSERT INTO " + c.table.QuotedTableName() + " (\"queue\", \"message_id\", \"message_type\", \"trace_id\", " +
+		"\"message\", \"headers\", \"sent_time\", \"visible_time\", \"delivery_count\") VALUES " + rows
+
+	_, err := c.table.GetClient(ctx).Exec(ctx, query, values...)
+	if err != nil {
+		return err
+	}
+
+	c.Counters.Increment(ctx, "queue."+c.Name()+".sent_messages", int64(len(messages)))
+	if len(messages) == 1 {
+		c.Logger.Debug(cctx.NewContextWithTraceId(ctx, messages[0].TraceId), "Sent message %s via %s", messages[0], c.Name())
+	} else {
+		c.Logger.Debug(ctx, "Sent %d messages via %s", len(messages), c.Name())
+	}
+	return nil
+}
+
+// Peek peeks a single incoming message from the queue without removing it.
+// If there are no messages available in the queue it returns nil.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: a message or error.
+func (c *PostgresMessageQueue) Peek(ctx context.Context) (*cqueues.MessageEnvelope, error) {
+	messages, err := c.PeekBatch(ctx, 1)
+	if err != nil || len(messages) == 0 {
+		return nil, err
+	}
+	return messages[0], nil
+}
+
+// PeekBatch peeks multiple incoming messages from the queue without removing them.
+// Locked and delayed messages are skipped.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- messageCount a maximum number of messages to peek.
+//	Returns: a list with messages or error.
+func (c *PostgresMessageQueue) PeekBatch(ctx context.Context, messageCount int64) ([]*cqueues.MessageEnvelope, error) {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + messageColumns + " FROM " + c.table.QuotedTableName() +
+		" WHERE \"queue\"=$1 AND \"visible_time\"<=$2 ORDER BY \"id\" LIMIT $3"
+
+	rows, err := c.table.GetClient(ctx).Query(ctx, query, c.Name(), time.Now().UTC(), messageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, _, err := c.toMessages(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Trace(ctx, "Peeked %d messages on %s", len(messages), c.Name())
+	return messages, nil
+}
+
+// Receive receives an incoming message and locks it for the lock timeout.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- waitTimeout a timeout in milliseconds to wait for a message to come.
+//	Returns: a message or error.
+func (c *PostgresMessageQueue) Receive(ctx context.Context, waitTimeout time.Duration) (*cqueues.MessageEnvelope, error) {
+	messages, err := c.ReceiveBatch(ctx, 1, waitTimeout)
+	if err != nil || len(messages) == 0 {
+		return nil, err
+	}
+	return messages[0], nil
+}
+
+// ReceiveBatch receives multiple incoming messages and locks them for the lock timeout.
+// The method waits for the first messages to come and returns all available messages up to the maximum count.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- maxCount a maximum number of messages to receive.
+//		- waitTimeout a timeout in milliseconds to wait for messages to come.
+//	Returns: a list with messages or error.
+func (c *PostgresMessageQueue) ReceiveBatch(ctx context.Context, maxCount int64,
+	waitTimeout time.Duration) ([]*cqueues.MessageEnvelope, error) {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		messages, err := c.lockMessages(ctx, maxCount)
+		if err != nil || len(messages) > 0 {
+			return messages, err
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return messages, nil
+		}
+		if wait > c.Interval {
+			wait = c.Interval
+		}
+
+		select {
+		case <-ctx.Done():
+			return messages, nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *PostgresMessageQueue) lockMessages(ctx context.Context, maxCount int64) ([]*cqueues.MessageEnvelope, error) {
+	now := time.Now()
+	token := keys.IdGenerator.NextLong()
+
+	query := "UPDATE " + c.table.QuotedTableName() +
+		" SET \"visible_time\"=$1, \"lock_token\"=$2, \"delivery_count\"=\"delivery_count\"+1" +
+		" WHERE \"id\" IN (SELECT \"id\" FROM " + c.table.QuotedTableName() +
+		" WHERE \"queue\"=$3 AND \"visible_time\"<=$4 ORDER BY \"id\" LIMIT $5 FOR UPDATE SKIP LOCKED)" +
+		" RETURNING " + messageColumns
+
+	rows, err := c.table.GetClient(ctx).Query(ctx, query, now.Add(c.LockTimeout).UTC(), token, c.Name(), now.UTC(), maxCount)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, ids, err := c.toMessages(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*cqueues.MessageEnvelope, 0, len(messages))
+	for index, message := range messages {
+		message.SetReference(&messageLock{id: ids[index], token: token})
+
+		// Move poison messages to dead letter queue
+		if c.MoveToDeadLetterIfExceeded(ctx, message) {
+			continue
+		}
+
+		c.Counters.IncrementOne(ctx, "queue."+c.Name()+".received_messages")
+		c.Logger.Debug(cctx.NewContextWithTraceId(ctx, message.TraceId), "Received message %s via %s", message, c.Name())
+		result = append(result, message)
+	}
+	return result, nil
+}
+
+// RenewLock renews a lock on a message that makes it invisible from other receivers in the queue.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- message a message to extend its lock.
+//		- lockTimeout a locking timeout in milliseconds.
+//	Returns: error or nil for success.
+func (c *PostgresMessageQueue) RenewLock(ctx context.Context, message *cqueues.MessageEnvelope, lockTimeout time.Duration) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	lock, ok := message.GetReference().(*messageLock)
+	if !ok {
+		return nil
+	}
+
+	query := "UPDATE " + c.table.QuotedTableName() + " SET \"visible_time\"=$1 WHERE \"id\"=$2 AND \"lock_token\"=$3"
+	_, err := c.table.GetClient(ctx).Exec(ctx, query, time.Now().Add(lockTimeout).UTC(), lock.id, lock.token)
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Renewed lock for message %s at %s", message, c.Name())
+	return nil
+}
+
+// Complete permanently removes a message from the queue.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- message a message to remove.
+//	Returns: error or nil for success.
+func (c *PostgresMessageQueue) Complete(ctx context.Context, message *cqueues.MessageEnvelope) error {
+	return c.CompleteBatch(ctx, []*cqueues.MessageEnvelope{message})
+}
+
+// CompleteBatch permanently removes multiple messages from the queue with a single statement.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- messages a list of messages to remove.
+//	Returns: error or nil for success.
+func (c *PostgresMessageQueue) CompleteBatch(ctx context.Context, messages []*cqueues.MessageEnvelope) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	ids := make([]int64, 0, len(messages))
+	tokens := make([]string, 0, len(messages))
+	for _, message := range messages {
+		if lock, ok := message.GetReference().(*messageLock); ok {
+			ids = append(ids, lock.id)
+			tokens = append(tokens, lock.token)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Messages which locks expired may be already received by others
+	query := "DELETE FROM " + c.table.QuotedTableName() + " WHERE \"id\"=ANY($1) AND \"lock_token\"=ANY($2)"
+	_, err := c.table.GetClient(ctx).Exec(ctx, query, ids, tokens)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		message.SetReference(nil)
+		c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Completed message %s at %s", message, c.Name())
+	}
+	return nil
+}
+
+// Abandon returns message into the queue and makes it available for all subscribers to receive it again.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- message a message to return.
+//	Returns: error or nil for success.
+func (c *PostgresMessageQueue) Abandon(ctx context.Context, message *cqueues.MessageEnvelope) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	lock, ok := message.GetReference().(*messageLock)
+	if !ok {
+		return nil
+	}
+
+	query := "UPDATE " + c.table.QuotedTableName() + " SET \"visible_time\"=$1, \"lock_token\"=NULL" +
+		" WHERE \"id\"=$2 AND \"lock_token\"=$3"
+	_, err := c.table.GetClient(ctx).Exec(ctx, query, time.Now().UTC(), lock.id, lock.token)
+	if err != nil {
+		return err
+	}
+
+	message.SetReference(nil)
+	c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Abandoned message %s at %s", message, c.Name())
+	return nil
+}
+
+// MoveToDeadLetter permanently removes a message from the queue and sends it to dead letter queue.
+// The message is sent to DeadLetterQueue when it is set or moved to the queue
+// configured by options.dead_letter_queue in the same table. Otherwise it is dropped.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- message a message to be removed.
+//	Returns: error or nil for success.
+func (c *PostgresMessageQueue) MoveToDeadLetter(ctx context.Context, message *cqueues.MessageEnvelope) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	sent, err := c.SendToDeadLetterQueue(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	lock, ok := message.GetReference().(*messageLock)
+	if !sent && ok && c.deadLetterQueue != "" {
+		query := "UPDATE " + c.table.QuotedTableName() + " SET \"queue\"=$1, \"visible_time\"=$2, \"lock_token\"=NULL" +
+			" WHERE \"id\"=$3 AND \"lock_token\"=$4"
+		_, err = c.table.GetClient(ctx).Exec(ctx, query, c.deadLetterQueue, time.Now().UTC(), lock.id, lock.token)
+		if err != nil {
+			c.Logger.Error(cctx.NewContextWithTraceId(ctx, message.TraceId), err, "Failed to move message to %s", c.deadLetterQueue)
+			return err
+		}
+		message.SetReference(nil)
+	}
+
+	c.Counters.IncrementOne(ctx, "queue."+c.Name()+".dead_messages")
+	c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Moved to dead message %s at %s", message, c.Name())
+
+	return c.Complete(ctx, message)
+}
+
+// Listen listens for incoming messages and blocks the current thread until queue is closed.
+// The receiver is responsible to complete or abandon received messages.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- receiver a receiver to receive incoming messages.
+//	Returns: error or nil for success.
+func (c *PostgresMessageQueue) Listen(ctx context.Context, receiver cqueues.IMessageReceiver) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	c.Logger.Trace(ctx, "Started listening messages at %s", c.Name())
+
+	// Unset cancellation token
+	atomic.StoreInt32(&c.cancel, 0)
+
+	for atomic.LoadInt32(&c.cancel) == 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		message, err := c.Receive(ctx, c.Interval)
+		if err != nil {
+			c.Logger.Error(ctx, err, "Failed to receive the message")
+			if !c.IsOpen() {
+				return nil
+			}
+			time.Sleep(c.Interval)
+			continue
+		}
+
+		if message != nil && atomic.LoadInt32(&c.cancel) == 0 {
+			func(message *cqueues.MessageEnvelope) {
+				defer func() {
+					if r := recover(); r != nil {
+						c.Logger.Error(ctx, nil, "Failed to process the message - "+fmt.Sprintf("%v", r))
+					}
+				}()
+
+				err = receiver.ReceiveMessage(cqueues.ExtractTraceContext(ctx, message), message, c)
+				if err != nil {
+					c.Logger.Error(cctx.NewContextWithTraceId(ctx, message.TraceId), err, "Failed to process the message")
+				}
+			}(message)
+		}
+	}
+
+	c.Logger.Trace(ctx, "Stopped listening messages at %s", c.Name())
+	return nil
+}
+
+// EndListen ends listening for incoming messages.
+// When this method is call listen unblocks the thread and execution continues.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+func (c *PostgresMessageQueue) EndListen(ctx context.Context) {
+	atomic.StoreInt32(&c.cancel, 1)
+}
+
+// messagesById sorts messages together with their row ids.
+type messagesById struct {
+	messages []*cqueues.MessageEnvelope
+	ids      []int64
+}
+
+func (c messagesById) Len() int           { return len(c.ids) }
+func (c messagesById) Less(i, j int) bool { return c.ids[i] < c.ids[j] }
+func (c messagesById) Swap(i, j int) {
+	c.ids[i], c.ids[j] = c.ids[j], c.ids[i]
+	c.messages[i], c.messages[j] = c.messages[j], c.messages[i]
+}
@@ -3,10 +3,14 @@ package test
 import (
 	"context"
 	"os"
+	"strconv"
 	"testing"
 
 	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 	tf "github.com/pip-services4/pip-services4-go/pip-services4-postgres-go/test/fixtures"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDummyJsonPostgresPersistence(t *testing.T) {
@@ -79,4 +83,29 @@ func TestDummyJsonPostgresPersistence(t *testing.T) {
 
 	t.Run("DummyPostgresConnection:Batch", fixture.TestBatchOperations)
 
+	opnErr = persistence.Clear(context.Background())
+	if opnErr != nil {
+		t.Error("Error cleaned persistence", opnErr)
+		return
+	}
+
+	t.Run("DummyPostgresConnection:NumericRange", func(t *testing.T) {
+		ctx := context.Background()
+		for i, count := range []int{9, 10, 100} {
+			_, err := persistence.GetClient(ctx).Exec(ctx,
+				"INSERT INTO "+persistence.QuotedTableName()+" (id, data) VALUES ($1, $2)",
+				strconv.Itoa(i), map[string]any{"id": strconv.Itoa(i), "key": strconv.Itoa(i), "count": count})
+			assert.Nil(t, err)
+		}
+
+		filter, err := cpersist.NewFilterExpressionFromParams(*cquery.NewFilterParamsFromTuples("count__gt", 9))
+		assert.Nil(t, err)
+		where, args, err := persistence.CompileFilter(filter)
+		assert.Nil(t, err)
+
+		count, err := persistence.IdentifiableJsonPostgresPersistence.GetCountByFilter(ctx, where, args...)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
 }
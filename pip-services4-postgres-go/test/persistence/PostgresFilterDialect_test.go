@@ -0,0 +1,53 @@
+package test
+
+import (
+	"testing"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-postgres-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonPostgresFilterNumericRange(t *testing.T) {
+	compiler := persist.NewJsonPostgresFilterCompiler("data")
+
+	filter, err := cpersist.NewFilterExpressionFromParams(*cquery.NewFilterParamsFromTuples(
+		"count__gt", 9,
+		"price__between", "0.5,10",
+		"key", "10",
+		"code__ge", "A10",
+	))
+	assert.Nil(t, err)
+
+	where, args, err := compiler.Compile(filter, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "(data->>'code'>=$1 AND (data->>'count')::numeric>$2"+
+		" AND data->>'key'=$3 AND (data->>'price')::numeric BETWEEN $4 AND $5)", where)
+	assert.Equal(t, []any{"A10", "9", "10", "0.5", "10"}, args)
+
+	filter = cpersist.NewFilterCondition("count", cpersist.FilterIn, 1, 2)
+	where, _, err = compiler.Compile(filter, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "(data->>'count')::numeric IN ($1,$2)", where)
+
+	where, _, err = persist.NewPostgresFilterCompiler().Compile(
+		cpersist.NewFilterCondition("count", cpersist.FilterMore, "9"), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "\"count\">$1", where)
+}
+
+func TestPostgresFilterSortNulls(t *testing.T) {
+	sort := *cquery.NewSortParams([]cquery.SortField{
+		cquery.NewSortField("key", false),
+		cquery.NewSortField("id", true),
+	})
+
+	orderBy, err := persist.NewPostgresFilterCompiler().CompileSort(sort)
+	assert.Nil(t, err)
+	assert.Equal(t, "\"key\" DESC NULLS LAST,\"id\" ASC NULLS FIRST", orderBy)
+
+	orderBy, err = persist.NewJsonPostgresFilterCompiler("data").CompileSort(sort)
+	assert.Nil(t, err)
+	assert.Equal(t, "NULLIF((data->'key')::jsonb,'null') DESC NULLS LAST,\"id\" ASC NULLS FIRST", orderBy)
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-postgres-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresProcessedMessageStore(t *testing.T) {
+	ctx := context.Background()
+
+	postgresUri := os.Getenv("POSTGRES_URI")
+	postgresHost := os.Getenv("POSTGRES_HOST")
+	if postgresHost == "" {
+		postgresHost = "localhost"
+	}
+
+	postgresPort := os.Getenv("POSTGRES_PORT")
+	if postgresPort == "" {
+		postgresPort = "5432"
+	}
+
+	postgresDatabase := os.Getenv("POSTGRES_DB")
+	if postgresDatabase == "" {
+		postgresDatabase = "test"
+	}
+
+	postgresUser := os.Getenv("POSTGRES_USER")
+	if postgresUser == "" {
+		postgresUser = "postgres"
+	}
+	postgresPassword := os.Getenv("POSTGRES_PASSWORD")
+	if postgresPassword == "" {
+		postgresPassword = "postgres#"
+	}
+
+	if postgresUri == "" && postgresHost == "" {
+		panic("Connection params not set")
+	}
+
+	store := persist.NewPostgresProcessedMessageStore()
+	store.Configure(ctx, cconf.NewConfigParamsFromTuples(
+		"connection.uri", postgresUri,
+		"connection.host", postgresHost,
+		"connection.port", postgresPort,
+		"connection.database", postgresDatabase,
+		"credential.username", postgresUser,
+		"credential.password", postgresPassword,
+		"options.claim_timeout", 500,
+	))
+
+	err := store.Open(ctx)
+	assert.Nil(t, err)
+	defer store.Close(ctx)
+
+	err = store.Clear(ctx)
+	assert.Nil(t, err)
+
+	// Only one of concurrent receivers claims the message
+	var claims int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			claimed, err := store.TryClaim(ctx, "test_message")
+			assert.Nil(t, err)
+			if claimed {
+				atomic.AddInt32(&claims, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), claims)
+
+	processed, err := store.IsProcessed(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.False(t, processed)
+
+	// Released claims can be claimed again
+	err = store.ReleaseClaim(ctx, "test_message")
+	assert.Nil(t, err)
+	claimed, err := store.TryClaim(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+
+	// Expired claims can be claimed again
+	time.Sleep(1000 * time.Millisecond)
+	claimed, err = store.TryClaim(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+
+	// Processed messages cannot be claimed
+	err = store.MarkProcessed(ctx, "test_message")
+	assert.Nil(t, err)
+	processed, err = store.IsProcessed(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.True(t, processed)
+	claimed, err = store.TryClaim(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.False(t, claimed)
+
+	assert.Nil(t, store.Cleanup(ctx))
+}
@@ -0,0 +1,187 @@
+package test_queues
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	"github.com/pip-services4/pip-services4-go/pip-services4-postgres-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+type postgresMessageQueueTest struct {
+	queue   *queues.PostgresMessageQueue
+	fixture *MessageQueueFixture
+}
+
+func newPostgresMessageQueueConfig() *cconf.ConfigParams {
+	postgresUri := os.Getenv("POSTGRES_URI")
+	postgresHost := os.Getenv("POSTGRES_HOST")
+	if postgresHost == "" {
+		postgresHost = "localhost"
+	}
+
+	postgresPort := os.Getenv("POSTGRES_PORT")
+	if postgresPort == "" {
+		postgresPort = "5432"
+	}
+
+	postgresDatabase := os.Getenv("POSTGRES_DB")
+	if postgresDatabase == "" {
+		postgresDatabase = "test"
+	}
+
+	postgresUser := os.Getenv("POSTGRES_USER")
+	if postgresUser == "" {
+		postgresUser = "postgres"
+	}
+	postgresPassword := os.Getenv("POSTGRES_PASSWORD")
+	if postgresPassword == "" {
+		postgresPassword = "postgres#"
+	}
+
+	return cconf.NewConfigParamsFromTuples(
+		"connection.uri", postgresUri,
+		"connection.host", postgresHost,
+		"connection.port", postgresPort,
+		"connection.database", postgresDatabase,
+		"credential.username", postgresUser,
+		"credential.password", postgresPassword,
+		"interval", 100,
+	)
+}
+
+func newPostgresMessageQueueTest(name string, options ...any) *postgresMessageQueueTest {
+	queue := queues.NewPostgresMessageQueue(name)
+	queue.Configure(context.Background(),
+		newPostgresMessageQueueConfig().Override(cconf.NewConfigParamsFromTuples(options...)))
+
+	return &postgresMessageQueueTest{
+		queue:   queue,
+		fixture: NewMessageQueueFixture(queue),
+	}
+}
+
+func (c *postgresMessageQueueTest) setup(t *testing.T) {
+	err := c.queue.Open(context.Background())
+	if err != nil {
+		t.Error("Failed to open queue", err)
+		return
+	}
+
+	err = c.queue.Clear(context.Background())
+	if err != nil {
+		t.Error("Failed to clear queue", err)
+	}
+}
+
+func (c *postgresMessageQueueTest) teardown(t *testing.T) {
+	err := c.queue.Close(context.Background())
+	if err != nil {
+		t.Error("Failed to close queue", err)
+	}
+}
+
+func TestPostgresMessageQueue(t *testing.T) {
+	c := newPostgresMessageQueueTest("test")
+
+	c.setup(t)
+	t.Run("Send Receive Message", c.fixture.TestSendReceiveMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Receive Send Message", c.fixture.TestReceiveSendMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Receive Complete Message", c.fixture.TestReceiveCompleteMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Receive Abandon Message", c.fixture.TestReceiveAbandonMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Peek Message", c.fixture.TestSendPeekMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Peek No Message", c.fixture.TestPeekNoMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Move To Dead Message", c.fixture.TestMoveToDeadMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("On Message", c.fixture.TestOnMessage)
+	t.Run("Trace Context Propagation", c.fixture.TestTraceContextPropagation)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Delayed Message", c.fixture.TestSendDelayedMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Receive Batch", c.fixture.TestSendReceiveBatch)
+	c.teardown(t)
+}
+
+func TestPostgresMessageQueueLockAndDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	c := newPostgresMessageQueueTest("test",
+		"options.lock_timeout", 500,
+		"options.max_delivery_count", 2,
+		"options.dead_letter_queue", "test_dead",
+	)
+	c.setup(t)
+	defer c.teardown(t)
+
+	dead := newPostgresMessageQueueTest("test_dead")
+	dead.setup(t)
+	defer dead.teardown(t)
+
+	err := c.queue.Send(ctx, cqueues.NewMessageEnvelope("123", "Test", []byte("Test message")))
+	assert.Nil(t, err)
+
+	envelope, err := c.queue.Receive(ctx, 10000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope)
+	assert.Equal(t, 1, envelope.DeliveryCount)
+
+	// Renewed lock keeps the message invisible
+	time.Sleep(300 * time.Millisecond)
+	err = c.queue.RenewLock(ctx, envelope, 500*time.Millisecond)
+	assert.Nil(t, err)
+	time.Sleep(300 * time.Millisecond)
+
+	locked, err := c.queue.Receive(ctx, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, locked)
+
+	// Expired lock makes the message visible again
+	envelope, err = c.queue.Receive(ctx, 10000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope)
+	assert.Equal(t, 2, envelope.DeliveryCount)
+
+	// The third delivery exceeds the limit
+	err = c.queue.Abandon(ctx, envelope)
+	assert.Nil(t, err)
+
+	envelope, err = c.queue.Receive(ctx, 500*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Nil(t, envelope)
+
+	count, err := c.queue.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	envelope, err = dead.queue.Peek(ctx)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope)
+	assert.Equal(t, "Test message", envelope.GetMessageAsString())
+}
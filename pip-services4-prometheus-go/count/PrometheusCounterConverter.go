@@ -1,6 +1,7 @@
 package count
 
 import (
+	"sort"
 	"strings"
 
 	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
@@ -15,6 +16,8 @@ type TPrometheusCounterConverter struct {
 }
 
 // ToString method converts the given counters to a string that is returned by Prometheus metrics service.
+// Counter labels are converted into Prometheus labels. Lines of the same metric
+// are grouped under a single TYPE line as Prometheus requires.
 //
 //	Parameters:
 //		- counters  a list of counters to convert.
@@ -29,98 +32,76 @@ func (c *TPrometheusCounterConverter) ToString(counters []ccount.Counter, source
 		return ""
 	}
 
-	var builder string = ""
+	metrics := make([]string, 0)
+	lines := make(map[string][]string)
+	addLine := func(metric string, labels string, value string) {
+		if _, ok := lines[metric]; !ok {
+			metrics = append(metrics, metric)
+		}
+		lines[metric] = append(lines[metric], metric+labels+" "+value)
+	}
 
 	for _, counter := range counters {
 		counterName := c.parseCounterName(counter)
+		if counterName == "" {
+			continue
+		}
 		labels := c.generateCounterLabel(counter, source, instance)
 
 		switch counter.Type {
 		case ccount.Increment:
-			builder += "# TYPE " + counterName + " gauge\n"
-			builder += counterName + labels + " " + cconv.StringConverter.ToString(counter.Count) + "\n"
-		case ccount.Interval:
-			builder += "# TYPE " + counterName + "_max gauge\n"
-			builder += counterName + "_max" + labels + " " + cconv.StringConverter.ToString(counter.Max) + "\n"
-			builder += "# TYPE " + counterName + "_min gauge\n"
-			builder += counterName + "_min" + labels + " " + cconv.StringConverter.ToString(counter.Min) + "\n"
-			builder += "# TYPE " + counterName + "_average gauge\n"
-			builder += counterName + "_average" + labels + " " + cconv.StringConverter.ToString(counter.Average) + "\n"
-			builder += "# TYPE " + counterName + "_count gauge\n"
-			builder += counterName + "_count" + labels + " " + cconv.StringConverter.ToString(counter.Count) + "\n"
+			addLine(counterName, labels, cconv.StringConverter.ToString(counter.Count))
+		case ccount.Interval, ccount.Statistics:
+			addLine(counterName+"_max", labels, cconv.StringConverter.ToString(counter.Max))
+			addLine(counterName+"_min", labels, cconv.StringConverter.ToString(counter.Min))
+			addLine(counterName+"_average", labels, cconv.StringConverter.ToString(counter.Average))
+			addLine(counterName+"_count", labels, cconv.StringConverter.ToString(counter.Count))
 		case ccount.LastValue:
-			builder += "# TYPE " + counterName + " gauge\n"
-			builder += counterName + labels + " " + cconv.StringConverter.ToString(counter.Last) + "\n"
-		case ccount.Statistics:
-			builder += "# TYPE " + counterName + "_max gauge\n"
-			builder += counterName + "_max" + labels + " " + cconv.StringConverter.ToString(counter.Max) + "\n"
-			builder += "# TYPE " + counterName + "_min gauge\n"
-			builder += counterName + "_min" + labels + " " + cconv.StringConverter.ToString(counter.Min) + "\n"
-			builder += "# TYPE " + counterName + "_average gauge\n"
-			builder += counterName + "_average" + labels + " " + cconv.StringConverter.ToString(counter.Average) + "\n"
-			builder += "# TYPE " + counterName + "_count gauge\n"
-			builder += counterName + "_count" + labels + " " + cconv.StringConverter.ToString(counter.Count) + "\n"
+			addLine(counterName, labels, cconv.StringConverter.ToString(counter.Last))
 		case ccount.Timestamp: // Prometheus doesn't support non-numeric metrics
-			builder += "# TYPE " + counterName + " gauge\n" //" untyped\n"
-			builder += counterName + labels + " " + cconv.StringConverter.ToString(counter.Time.Unix()) + "\n"
+			addLine(counterName, labels, cconv.StringConverter.ToString(counter.Time.Unix()))
 		}
 	}
 
-	return builder
+	builder := strings.Builder{}
+	for _, metric := range metrics {
+		builder.WriteString("# TYPE " + metric + " gauge\n")
+		for _, line := range lines[metric] {
+			builder.WriteString(line + "\n")
+		}
+	}
+
+	return builder.String()
 }
 
 func (c *TPrometheusCounterConverter) AtomicCountersToCounters(atomicCounters []*ccount.AtomicCounter) []ccount.Counter {
-	counters := make([]ccount.Counter, len(atomicCounters))
+	counters := make([]ccount.Counter, 0, len(atomicCounters))
 
 	for _, atomicCounter := range atomicCounters {
-		counter := ccount.Counter{
-			Name:    atomicCounter.Name(),
-			Type:    atomicCounter.Type(),
-			Last:    atomicCounter.Last(),
-			Count:   atomicCounter.Count(),
-			Min:     atomicCounter.Min(),
-			Max:     atomicCounter.Max(),
-			Average: atomicCounter.Average(),
-			Time:    atomicCounter.Time(),
-		}
-
-		counters = append(counters, counter)
+		counters = append(counters, atomicCounter.GetCounter())
 	}
 
 	return counters
-
 }
 
 func (c *TPrometheusCounterConverter) generateCounterLabel(counter ccount.Counter, source string, instance string) string {
-
-	labels := make(map[string]string, 0)
-
-	if source != "" {
-		labels["source"] = source
-	}
-
-	if instance != "" {
-		labels["instance"] = instance
-	}
-
-	nameParts := strings.Split(counter.Name, ".")
-
-	// If there are other predictable names from which we can parse labels, we can add them below
-	if len(nameParts) >= 3 && nameParts[2] == "exec_time" {
-		labels["service"] = nameParts[0]
-		labels["command"] = nameParts[1]
-	}
-
+	labels := c.parseCounterLabels(counter, source, instance)
 	if len(labels) == 0 {
 		return ""
 	}
 
-	builder := "{"
+	keys := make([]string, 0, len(labels))
 	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	builder := "{"
+	for _, key := range keys {
 		if len(builder) > 1 {
 			builder += ","
 		}
-		builder += key + `="` + labels[key] + `"`
+		builder += key + `="` + c.escapeLabelValue(labels[key]) + `"`
 	}
 	builder += "}"
 
@@ -148,9 +129,24 @@ func (c *TPrometheusCounterConverter) parseCounterName(counter ccount.Counter) s
 	return result
 }
 
-func (c *TPrometheusCounterConverter) parseCounterLabels(counter ccount.Counter, source string, instance string) interface{} {
+func (c *TPrometheusCounterConverter) parseCounterLabels(counter ccount.Counter, source string, instance string) map[string]string {
 	labels := make(map[string]string, 0)
 
+	nameParts := strings.Split(counter.Name, ".")
+
+	// If there are other predictable names from which we can parse labels, we can add them below
+	if len(nameParts) >= 3 && nameParts[2] == "exec_time" {
+		labels["service"] = nameParts[0]
+		labels["command"] = nameParts[1]
+	}
+
+	for key, value := range counter.Labels {
+		if name := c.parseLabelName(key); name != "" {
+			labels[name] = value
+		}
+	}
+
+	// Source and instance identify the series and shall not be overridden by counter labels
 	if source != "" {
 		labels["source"] = source
 	}
@@ -159,13 +155,26 @@ func (c *TPrometheusCounterConverter) parseCounterLabels(counter ccount.Counter,
 		labels["instance"] = instance
 	}
 
-	nameParts := strings.Split(counter.Name, ".")
+	return labels
+}
 
-	// If there are other predictable names from which we can parse labels, we can add them below
-	if len(nameParts) >= 3 && nameParts[2] == "exec_time" {
-		labels["service"] = nameParts[0]
-		labels["command"] = nameParts[1]
+func (c *TPrometheusCounterConverter) parseLabelName(name string) string {
+	result := strings.Builder{}
+	for index, char := range name {
+		valid := char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') ||
+			(index > 0 && char >= '0' && char <= '9')
+		if valid {
+			result.WriteRune(char)
+		} else {
+			result.WriteRune('_')
+		}
 	}
+	return result.String()
+}
 
-	return labels
+func (c *TPrometheusCounterConverter) escapeLabelValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return value
 }
@@ -34,6 +34,10 @@ import (
 //			- retries:               number of retries (default: 3)
 //			- connect_timeout:       connection timeout in milliseconds (default: 10 sec)
 //			- timeout:               invocation timeout in milliseconds (default: 10 sec)
+//			- max_labels:            maximum number of labels per measurement (default: 10)
+//			- max_label_sets:        maximum number of distinct label sets per counter (default: 100)
+//
+// Counter labels are exported as Prometheus labels.
 //
 //	References:
 //
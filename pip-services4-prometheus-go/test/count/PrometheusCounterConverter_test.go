@@ -0,0 +1,28 @@
+package test_count
+
+import (
+	"testing"
+
+	ccount "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/count"
+	pcount "github.com/pip-services4/pip-services4-go/pip-services4-prometheus-go/count"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusCounterConverterLabels(t *testing.T) {
+	counters := []ccount.Counter{
+		{Name: "http.requests", Type: ccount.Increment, Count: 1,
+			Labels: map[string]string{"method": "GET", "status": "200"}},
+		{Name: "http.requests", Type: ccount.Increment, Count: 2,
+			Labels: map[string]string{"method": "POST", "status": "500", "source": "fake"}},
+		{Name: "http.queue", Type: ccount.LastValue, Last: 3,
+			Labels: map[string]string{"queue.name": "a\"b"}},
+	}
+
+	body := pcount.PrometheusCounterConverter.ToString(counters, "test", "host1")
+
+	assert.Equal(t, "# TYPE http_requests gauge\n"+
+		"http_requests{instance=\"host1\",method=\"GET\",source=\"test\",status=\"200\"} 1\n"+
+		"http_requests{instance=\"host1\",method=\"POST\",source=\"test\",status=\"500\"} 2\n"+
+		"# TYPE http_queue gauge\n"+
+		"http_queue{instance=\"host1\",queue_name=\"a\\\"b\",source=\"test\"} 3\n", body)
+}
@@ -8,6 +8,7 @@ import (
 
 // Creates RabbitMQMessageQueue components by their descriptors.
 // See RabbitMQMessageQueue
+// See RabbitMQTopic
 type DefaultRabbitMQFactory struct {
 	*cbuild.Factory
 }
@@ -19,6 +20,7 @@ func NewDefaultRabbitMQFactory() *DefaultRabbitMQFactory {
 
 	rabbitMQMessageQueueFactoryDescriptor := cref.NewDescriptor("pip-services", "queue-factory", "rabbitmq", "*", "1.0")
 	rabbitMQMessageQueueDescriptor := cref.NewDescriptor("pip-services", "message-queue", "rabbitmq", "*", "1.0")
+	rabbitMQTopicDescriptor := cref.NewDescriptor("pip-services", "topic", "rabbitmq", "*", "1.0")
 
 	c.RegisterType(rabbitMQMessageQueueFactoryDescriptor, NewRabbitMQMessageQueueFactory)
 
@@ -32,5 +34,15 @@ func NewDefaultRabbitMQFactory() *DefaultRabbitMQFactory {
 		return queues.NewEmptyRabbitMQMessageQueue(name)
 	})
 
+	c.Register(rabbitMQTopicDescriptor, func(locator interface{}) interface{} {
+		name := ""
+		descriptor, ok := locator.(*cref.Descriptor)
+		if ok {
+			name = descriptor.Name()
+		}
+
+		return queues.NewRabbitMQTopic(name)
+	})
+
 	return &c
 }
@@ -2,8 +2,10 @@ package queues
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
 	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
 	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
 	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
@@ -16,10 +18,22 @@ import (
 //
 // MQTT is a popular light-weight protocol to communicate IoT devices.
 //
+// Delayed messages are delivered natively by RabbitMQ delayed message exchange plugin
+// when options.delayed_exchange is set. Otherwise they are held by the Scheduler
+// until their visible time.
+//
 // Configuration parameters:
 //
 //   - topic:                         name of MQTT topic to subscribe
 //
+//   - options:
+//
+//   - delayed_exchange:            (optional) true to declare the exchange as x-delayed-message and delay messages natively (default: false)
+//
+//   - dead_letter_queue:           (optional) name of RabbitMQ queue to move poison messages to (default: none, messages are dropped)
+//
+//   - max_delivery_count:          (optional) maximum number of deliveries before a message is moved to dead letter queue (default: 0 unlimited)
+//
 //     connection(s):
 //
 //   - discovery_key:               (optional) a key to retrieve the connection from  IDiscovery
@@ -80,7 +94,11 @@ type RabbitMQMessageQueue struct {
 	autoCreate           bool
 	autoDelete           bool
 	noQueue              bool
+	delayedExchange      bool
+	deadLetterQueueName  string
 	Interval             time.Duration
+	// The scheduler that holds delayed messages when delayed exchange is not used
+	Scheduler *cqueues.MessageDelayScheduler
 
 	cancelListen chan bool
 	isListen     bool
@@ -100,12 +118,15 @@ func NewEmptyRabbitMQMessageQueue(name string) *RabbitMQMessageQueue {
 		autoCreate:           false,
 		autoDelete:           false,
 		noQueue:              false,
+		delayedExchange:      false,
 		cancelListen:         make(chan bool),
 	}
 
 	c.MessageQueue = cqueues.InheritMessageQueue(
 		&c, name,
-		cqueues.NewMessagingCapabilities(true, true, true, true, true, false, true, false, true))
+		cqueues.NewMessagingCapabilities(true, true, true, true, true, false, true, true, true).WithDelay(true))
+	c.Scheduler = cqueues.NewMessageDelayScheduler(&c)
+	c.Scheduler.Logger = c.Logger
 	c.Interval = time.Duration(c.defaultCheckInterval) * time.Millisecond
 	c.optionsResolver = mqcon.NewRabbitMQConnectionResolver()
 
@@ -147,6 +168,8 @@ func (c *RabbitMQMessageQueue) Configure(ctx context.Context, config *cconf.Conf
 	c.autoCreate = config.GetAsBooleanWithDefault("options.auto_create", c.autoCreate)
 	c.autoDelete = config.GetAsBooleanWithDefault("options.auto_delete", c.autoDelete)
 	c.noQueue = config.GetAsBooleanWithDefault("options.noqueue", c.noQueue)
+	c.delayedExchange = config.GetAsBooleanWithDefault("options.delayed_exchange", c.delayedExchange)
+	c.deadLetterQueueName = config.GetAsStringWithDefault("options.dead_letter_queue", c.deadLetterQueueName)
 }
 
 func (c *RabbitMQMessageQueue) checkOpened(traceId string) error {
@@ -203,14 +226,20 @@ func (c *RabbitMQMessageQueue) Open(ctx context.Context) error {
 	// Automatically create queue, exchange and binding
 	if c.autoCreate {
 		if c.exchange != "" {
+			exchangeType := c.exchangeType
+			var args rabbitmq.Table
+			if c.delayedExchange {
+				exchangeType = "x-delayed-message"
+				args = rabbitmq.Table{"x-delayed-type": c.exchangeType}
+			}
 			c.mqChanel.ExchangeDeclare(
 				c.exchange,
-				c.exchangeType,
+				exchangeType,
 				c.persistent,
 				c.autoDelete,
 				false,
 				false,
-				nil,
+				args,
 			)
 		}
 
@@ -249,7 +278,20 @@ func (c *RabbitMQMessageQueue) Open(ctx context.Context) error {
 			)
 
 		}
+
+		if c.deadLetterQueueName != "" {
+			c.mqChanel.QueueDeclare(
+				c.deadLetterQueueName,
+				c.persistent,
+				false,
+				false,
+				false,
+				nil,
+			)
+		}
 	}
+
+	c.Scheduler.Start(ctx)
 	return nil
 }
 
@@ -268,6 +310,8 @@ func (c *RabbitMQMessageQueue) Close(ctx context.Context) (err error) {
 		c.Lock.Unlock()
 	}
 
+	c.Scheduler.Stop(ctx)
+
 	if c.mqChanel != nil {
 		err = c.mqChanel.Close()
 		if err != nil {
@@ -316,32 +360,43 @@ func (c *RabbitMQMessageQueue) toMessage(envelope *rabbitmq.Delivery) *cqueues.M
 		return nil
 	}
 
+	// Count this delivery on top of the previous ones.
+	// Quorum queues count redeliveries in x-delivery-count header.
+	deliveryCount := cconv.IntegerConverter.ToInteger(envelope.Headers["delivery_count"])
+	if count := cconv.IntegerConverter.ToInteger(envelope.Headers["x-delivery-count"]); count > deliveryCount {
+		deliveryCount = count
+	}
+
 	message := cqueues.MessageEnvelope{
-		MessageId:   envelope.MessageId,
-		MessageType: envelope.Type,
-		TraceId:     envelope.CorrelationId,
-		Message:     envelope.Body,
-		SentTime:    time.Now(),
+		MessageId:     envelope.MessageId,
+		MessageType:   envelope.Type,
+		TraceId:       envelope.CorrelationId,
+		Message:       envelope.Body,
+		SentTime:      time.Now(),
+		DeliveryCount: deliveryCount + 1,
+	}
+
+	// Collect custom headers, x- headers are reserved by RabbitMQ
+	for key, value := range envelope.Headers {
+		if key != "delivery_count" && !strings.HasPrefix(key, "x-") {
+			message.SetHeader(key, cconv.StringConverter.ToString(value))
+		}
 	}
+
 	message.SetReference(envelope)
 
 	return &message
 }
 
-//	 Send method are sends a message into the queue.
-//		Parameters:
-//			- ctx context.Context transaction id to trace execution through call chain.
-//			- message a message envelop to be sent.
-func (c *RabbitMQMessageQueue) Send(ctx context.Context, message *cqueues.MessageEnvelope) (err error) {
-	err = c.checkOpened(cctx.GetTraceId(ctx))
-	if err != nil {
-		return err
-	}
-
+func (c *RabbitMQMessageQueue) fromMessage(message *cqueues.MessageEnvelope) rabbitmq.Publishing {
 	messageBuffer := rabbitmq.Publishing{
 		ContentType: "text/plain",
 	}
 
+	if contentType := message.GetContentType(); contentType != "" {
+		messageBuffer.ContentType = contentType
+	}
+
 	if message.TraceId != "" {
 		messageBuffer.CorrelationId = message.TraceId
 	}
@@ -353,7 +408,46 @@ func (c *RabbitMQMessageQueue) Send(ctx context.Context, message *cqueues.Messag
 		messageBuffer.Type = message.MessageType
 	}
 
+	if message.DeliveryCount > 0 || len(message.Headers) > 0 {
+		messageBuffer.Headers = rabbitmq.Table{}
+		for key, value := range message.Headers {
+			messageBuffer.Headers[key] = value
+		}
+		if message.DeliveryCount > 0 {
+			messageBuffer.Headers["delivery_count"] = int64(message.DeliveryCount)
+		}
+	}
+
 	messageBuffer.Body = []byte(message.Message)
+	return messageBuffer
+}
+
+//	 Send method are sends a message into the queue.
+//	 Delayed messages are published with x-delay header to the delayed message exchange
+//	 or held by the Scheduler when the exchange is not configured.
+//		Parameters:
+//			- ctx context.Context transaction id to trace execution through call chain.
+//			- message a message envelop to be sent.
+func (c *RabbitMQMessageQueue) Send(ctx context.Context, message *cqueues.MessageEnvelope) (err error) {
+	err = c.checkOpened(cctx.GetTraceId(ctx))
+	if err != nil {
+		return err
+	}
+
+	// Propagate W3C trace context and baggage to receivers
+	cqueues.InjectTraceContext(ctx, message)
+
+	messageBuffer := c.fromMessage(message)
+
+	if delay := time.Until(message.VisibleTime); !message.VisibleTime.IsZero() && delay > 0 {
+		if !c.delayedExchange || c.exchange == "" {
+			return c.Scheduler.Schedule(ctx, message)
+		}
+		if messageBuffer.Headers == nil {
+			messageBuffer.Headers = rabbitmq.Table{}
+		}
+		messageBuffer.Headers["x-delay"] = delay.Milliseconds()
+	}
 
 	err = c.mqChanel.Publish(c.exchange, c.routingKey, false, false, messageBuffer)
 
@@ -362,6 +456,46 @@ func (c *RabbitMQMessageQueue) Send(ctx context.Context, message *cqueues.Messag
 	return err
 }
 
+// SendBatch method are sends multiple messages into the queue.
+// Messages are published one after another on the same channel without waiting for each other.
+// Delayed messages are sent the same way as by Send.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - messages a list of message envelops to be sent.
+//
+// Returns: error or nil for success.
+func (c *RabbitMQMessageQueue) SendBatch(ctx context.Context, messages []*cqueues.MessageEnvelope) (err error) {
+	err = c.checkOpened(cctx.GetTraceId(ctx))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	count := 0
+	for _, message := range messages {
+		cqueues.InjectTraceContext(ctx, message)
+
+		if !message.IsVisible(now) {
+			err = c.Send(ctx, message)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = c.mqChanel.Publish(c.exchange, c.routingKey, false, false, c.fromMessage(message))
+		if err != nil {
+			c.Logger.Error(cctx.NewContextWithTraceId(ctx, message.TraceId), err, "Failed to send message %s via %s", message, c.Name())
+			return err
+		}
+		count++
+	}
+
+	c.Counters.Increment(ctx, "queue."+c.Name()+".sent_messages", int64(count))
+	c.Logger.Debug(ctx, "Sent %d messages via %s", count, c.Name())
+	return nil
+}
+
 // Peeks a single incoming message from the queue without removing it.
 // If there are no messages available in the queue it returns nil.
 // Parameters:
@@ -442,8 +576,14 @@ func (c *RabbitMQMessageQueue) Receive(ctx context.Context, waitTimeout time.Dur
 		env, ok, getErr := c.mqChanel.Get(c.queue, false) // true
 		if ok && getErr == nil {
 			c.Lock.Lock()
-			defer c.Lock.Unlock()
 			message = c.toMessage(&env)
+			c.Lock.Unlock()
+
+			// Move poison messages to dead letter queue and read the next one
+			if c.MoveToDeadLetterIfExceeded(ctx, message) {
+				message = nil
+				continue
+			}
 			break
 		}
 		timeout = timeout - c.Interval
@@ -457,6 +597,54 @@ func (c *RabbitMQMessageQueue) Receive(ctx context.Context, waitTimeout time.Dur
 	return message, nil
 }
 
+// ReceiveBatch method are receives multiple incoming messages and removes them from the queue.
+// The method waits for the first message to come and then gets all available messages up to the maximum count.
+// Parameters:
+//   - ctx context.Context  transaction id to trace execution through call chain.
+//   - maxCount a maximum number of messages to receive.
+//   - waitTimeout a timeout in milliseconds to wait for messages to come.
+//
+// Returns: a list with messages
+func (c *RabbitMQMessageQueue) ReceiveBatch(ctx context.Context, maxCount int64, waitTimeout time.Duration) (result []*cqueues.MessageEnvelope, err error) {
+	err = c.checkOpened(cctx.GetTraceId(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*cqueues.MessageEnvelope, 0)
+	timeout := waitTimeout
+
+	for int64(len(messages)) < maxCount {
+		env, ok, getErr := c.mqChanel.Get(c.queue, false)
+		if getErr != nil {
+			return messages, getErr
+		}
+
+		if !ok {
+			// Wait only for the first message
+			if len(messages) > 0 || timeout <= 0 {
+				break
+			}
+			time.Sleep(c.Interval)
+			timeout = timeout - c.Interval
+			continue
+		}
+
+		message := c.toMessage(&env)
+
+		// Move poison messages to dead letter queue and read the next one
+		if c.MoveToDeadLetterIfExceeded(ctx, message) {
+			continue
+		}
+
+		c.Counters.IncrementOne(ctx, "queue."+c.Name()+".received_messages")
+		c.Logger.Debug(cctx.NewContextWithTraceId(ctx, message.TraceId), "Received message %s via %s", message, c.Name())
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
 // Renews a lock on a message that makes it invisible from other receivers in the queue.
 // This method is usually used to extend the message processing time.
 // Important: This method is not supported by RabbitMQ.
@@ -473,7 +661,8 @@ func (c *RabbitMQMessageQueue) RenewLock(ctx context.Context, message *cqueues.M
 // This method is usually used to return a message which could not be processed at the moment
 // to repeat the attempt.Messages that cause unrecoverable errors shall be removed permanently
 // or/and send to dead letter queue.
-// Important: This method is not supported by RabbitMQ.
+// When max_delivery_count is set the message is sent again with incremented delivery count
+// and the original message is acknowledged.
 // Parameters:
 //   - ctx context.Context
 //   - message a message to return.
@@ -484,6 +673,17 @@ func (c *RabbitMQMessageQueue) Abandon(ctx context.Context, message *cqueues.Mes
 	}
 	err = nil
 
+	// Resend the message to keep track of deliveries
+	if c.MaxDeliveryCount > 0 {
+		resent := message.Clone()
+		resent.VisibleTime = time.Time{}
+		err = c.Send(ctx, resent)
+		if err != nil {
+			return err
+		}
+		return c.Complete(ctx, message)
+	}
+
 	// Make the message immediately visible
 	envelope, ok := message.GetReference().(*rabbitmq.Delivery)
 	if ok {
@@ -519,15 +719,37 @@ func (c *RabbitMQMessageQueue) Complete(ctx context.Context, message *cqueues.Me
 }
 
 // Permanently removes a message from the queue and sends it to dead letter queue.
-// Important: This method is not supported by RabbitMQ.
+// The message is sent to DeadLetterQueue when it is set or to the queue
+// configured by options.dead_letter_queue. Otherwise it is dropped.
 // Parameters:
 //   - ctx context.Context
 //   - message a message to be removed.
 //
 // Returns: error
 func (c *RabbitMQMessageQueue) MoveToDeadLetter(ctx context.Context, message *cqueues.MessageEnvelope) (err error) {
-	// Operation is not supported
-	return nil
+	err = c.checkOpened(cctx.GetTraceId(ctx))
+	if err != nil {
+		return err
+	}
+
+	sent, err := c.SendToDeadLetterQueue(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	if !sent && c.deadLetterQueueName != "" {
+		// Publish directly to the queue via the default exchange
+		err = c.mqChanel.Publish("", c.deadLetterQueueName, false, false, c.fromMessage(message))
+		if err != nil {
+			c.Logger.Error(cctx.NewContextWithTraceId(ctx, message.TraceId), err, "Failed to move message to %s", c.deadLetterQueueName)
+			return err
+		}
+	}
+
+	c.Counters.IncrementOne(ctx, "queue."+c.Name()+".dead_messages")
+	c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Moved to dead message %s at %s", message, c.Name())
+
+	return c.Complete(ctx, message)
 }
 
 //		Listens for incoming messages and blocks the current thread until queue is closed.
@@ -574,11 +796,18 @@ func (c *RabbitMQMessageQueue) Listen(ctx context.Context, receiver cqueues.IMes
 					message := c.toMessage(&msg)
 					c.Counters.IncrementOne(ctx, "queue."+c.Name()+".received_messages")
 					c.Logger.Debug(cctx.NewContextWithTraceId(ctx, message.TraceId), "Received message %s via %s", message, c.Name())
-					recvErr := receiver.ReceiveMessage(ctx, message, c)
+					// Skip poison messages, they are acknowledged when moved
+					if c.MoveToDeadLetterIfExceeded(ctx, message) {
+						continue
+					}
+					recvErr := receiver.ReceiveMessage(cqueues.ExtractTraceContext(ctx, message), message, c)
 					if recvErr != nil {
 						c.Logger.Error(cctx.NewContextWithTraceId(ctx, message.TraceId), recvErr, "Processing received message %s error in queue %s", message, c.Name())
 					}
-					c.mqChanel.Ack(msg.DeliveryTag, false)
+					// Skip if the receiver already completed or abandoned the message
+					if message.GetReference() != nil {
+						c.mqChanel.Ack(msg.DeliveryTag, false)
+					}
 				}
 			case <-c.cancelListen:
 				{
@@ -629,6 +858,11 @@ func (c *RabbitMQMessageQueue) Clear(ctx context.Context) (err error) {
 		return err
 	}
 
+	err = c.Scheduler.Clear(ctx)
+	if err != nil {
+		return err
+	}
+
 	c.Logger.Trace(ctx, "Cleared  %s messages in queue %s", count, c.Name())
 
 	return nil
@@ -0,0 +1,223 @@
+package queues
+
+import (
+	"context"
+	"sync"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+)
+
+// RabbitMQTopic are publish/subscribe topic that delivers messages via RabbitMQ topic exchange.
+// Messages are published to the exchange with the topic name as the routing key.
+// Every subscription is a queue named "<topic>.<subscription>" bound to the exchange,
+// so receivers that subscribe with the same name share messages while different subscriptions
+// receive every message.
+//
+// Topic names consist of words separated by dots. Subscriptions support RabbitMQ wildcards:
+// "*" matches exactly one word and "#" matches zero or more words.
+//
+// Configuration parameters:
+//
+//   - exchange:                      (optional) name of the topic exchange (default: topics)
+//
+//   - options:
+//
+//   - auto_create:                 (optional) true to declare the exchange and subscription queues (default: true)
+//
+//   - persistent:                  (optional) true to declare durable exchange and queues (default: false)
+//
+//   - max_delivery_count:          (optional) maximum number of deliveries before a message is moved to dead letter queue (default: 0 unlimited)
+//
+//     connection(s):
+//
+//   - discovery_key:               (optional) a key to retrieve the connection from  IDiscovery
+//
+//   - host:                        host name or IP address
+//
+//   - port:                        port number
+//
+//   - uri:                         resource URI or connection string with all parameters in it
+//
+//     credential(s):
+//
+//   - store_key:                   (optional) a key to retrieve the credentials from ICredentialStore
+//
+//   - username:                    user name
+//
+//   - password:                    user password
+//
+// References:
+//
+// - *:logger:*:*:1.0             (optional) ILogger components to pass log messages
+// - *:counters:*:*:1.0           (optional) ICounters components to pass collected measurements
+// - *:discovery:*:*:1.0          (optional) IDiscovery services to resolve connections
+// - *:credential-store:*:*:1.0   (optional) Credential stores to resolve credentials
+//
+// See ITopic
+// See RabbitMQMessageQueue
+//
+// Example:
+//
+//	ctx := context.Background()
+//	topic := queues.NewRabbitMQTopic("orders.*")
+//	topic.Configure(ctx, config.NewConfigParamsFromTuples(
+//		"exchange", "events",
+//		"connection.host", "localhost",
+//		"connection.port", "5672",
+//	))
+//	_ = topic.Open(ctx)
+//
+//	subscription, err := topic.Subscribe(ctx, "billing")
+//	message, err := subscription.Receive(ctx, 10000*time.Millisecond)
+type RabbitMQTopic struct {
+	publisher     *RabbitMQMessageQueue
+	config        *cconf.ConfigParams
+	references    cref.IReferences
+	lock          sync.Mutex
+	subscriptions []*RabbitMQMessageQueue
+}
+
+// NewRabbitMQTopic are creates a new instance of the topic.
+//
+//	Parameters:
+//		- name  string a topic name used as the routing key.
+func NewRabbitMQTopic(name string) *RabbitMQTopic {
+	c := &RabbitMQTopic{
+		publisher:     NewEmptyRabbitMQMessageQueue(name),
+		config:        defaultRabbitMQTopicConfig(name),
+		subscriptions: make([]*RabbitMQMessageQueue, 0),
+	}
+	return c
+}
+
+// Configure are configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context	operation context
+//		- config    configuration parameters to be set.
+func (c *RabbitMQTopic) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.config = config.SetDefaults(defaultRabbitMQTopicConfig(c.Name()))
+	c.publisher.Configure(ctx, c.config.Override(cconf.NewConfigParamsFromTuples(
+		"options.noqueue", true,
+	)))
+}
+
+// SetReferences are sets references to dependent components.
+//
+//	Parameters:
+//		- ctx context.Context	operation context
+//		- references 	references to locate the component dependencies.
+func (c *RabbitMQTopic) SetReferences(ctx context.Context, references cref.IReferences) {
+	c.references = references
+	c.publisher.SetReferences(ctx, references)
+}
+
+// Name are gets the topic name.
+func (c *RabbitMQTopic) Name() string {
+	return c.publisher.Name()
+}
+
+// IsOpen are checks if the component is opened.
+func (c *RabbitMQTopic) IsOpen() bool {
+	return c.publisher.IsOpen()
+}
+
+// Open are opens the component.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//
+// Returns error or nil no errors occured.
+func (c *RabbitMQTopic) Open(ctx context.Context) error {
+	return c.publisher.Open(ctx)
+}
+
+// Close are closes all subscriptions and frees used resources.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//
+// Returns error or nil no errors occured.
+func (c *RabbitMQTopic) Close(ctx context.Context) error {
+	c.lock.Lock()
+	subscriptions := c.subscriptions
+	c.subscriptions = make([]*RabbitMQMessageQueue, 0)
+	c.lock.Unlock()
+
+	for _, subscription := range subscriptions {
+		if err := subscription.Close(ctx); err != nil {
+			return err
+		}
+	}
+	return c.publisher.Close(ctx)
+}
+
+// Publish are sends a message to all subscriptions of the topic.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- envelope  a message envelop to be published.
+//
+// Returns error or nil for success.
+func (c *RabbitMQTopic) Publish(ctx context.Context, envelope *cqueues.MessageEnvelope) error {
+	return c.publisher.Send(ctx, envelope)
+}
+
+// Subscribe are joins a subscription queue bound to the exchange with the topic name.
+//
+//	Parameters:
+//		- ctx context.Context	transaction id to trace execution through call chain.
+//		- subscription  a name of the subscription.
+//
+// Returns the subscription or error.
+func (c *RabbitMQTopic) Subscribe(ctx context.Context, subscription string) (cqueues.ISubscription, error) {
+	if !c.IsOpen() {
+		return nil, cerr.NewInvalidStateError(cctx.GetTraceId(ctx), "NOT_OPENED", "Topic "+c.Name()+" is not opened")
+	}
+
+	queue := NewEmptyRabbitMQMessageQueue(subscription)
+	queue.Configure(ctx, c.config.Override(cconf.NewConfigParamsFromTuples(
+		"queue", c.Name()+"."+subscription,
+	)))
+	if c.references != nil {
+		queue.SetReferences(ctx, c.references)
+	}
+
+	if err := queue.Open(ctx); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.subscriptions = append(c.subscriptions, queue)
+	c.lock.Unlock()
+
+	return cqueues.NewTopicSubscription(queue, c.Name(), func(ctx context.Context) error {
+		return c.unsubscribe(ctx, queue)
+	}), nil
+}
+
+func (c *RabbitMQTopic) unsubscribe(ctx context.Context, queue *RabbitMQMessageQueue) error {
+	c.lock.Lock()
+	for index, subscription := range c.subscriptions {
+		if subscription == queue {
+			c.subscriptions = append(c.subscriptions[:index:index], c.subscriptions[index+1:]...)
+			break
+		}
+	}
+	c.lock.Unlock()
+
+	return queue.Close(ctx)
+}
+
+func defaultRabbitMQTopicConfig(name string) *cconf.ConfigParams {
+	return cconf.NewConfigParamsFromTuples(
+		"exchange", "topics",
+		"options.exchange_type", "topic",
+		"options.routing_key", name,
+		"options.auto_create", true,
+	)
+}
@@ -90,5 +90,11 @@ func TestRabbitMQMessageQueue(t *testing.T) {
 	err = queue.Clear(context.Background())
 	assert.Nil(t, err)
 	t.Run("RabbitMQMessageQueue:On Message", fixture.TestOnMessage)
+	err = queue.Clear(context.Background())
+	assert.Nil(t, err)
+	t.Run("RabbitMQMessageQueue:Send Delayed Message", fixture.TestSendDelayedMessage)
+	err = queue.Clear(context.Background())
+	assert.Nil(t, err)
+	t.Run("RabbitMQMessageQueue:Send Receive Batch", fixture.TestSendReceiveBatch)
 
 }
@@ -0,0 +1,47 @@
+package test_queues
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	rabbitqueue "github.com/pip-services4/pip-services4-go/pip-services4-rabbitmq-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRabbitMQTopic(t *testing.T) {
+	rabbitmqHost := os.Getenv("RABBITMQ_SERVICE_HOST")
+	if rabbitmqHost == "" {
+		rabbitmqHost = "localhost"
+	}
+	rabbitmqPort := os.Getenv("RABBITMQ_SERVICE_PORT")
+	if rabbitmqPort == "" {
+		rabbitmqPort = "5672"
+	}
+
+	rabbitmqUser := os.Getenv("RABBITMQ_USER")
+	if rabbitmqUser == "" {
+		rabbitmqUser = "user"
+	}
+
+	rabbitmqPassword := os.Getenv("RABBITMQ_PASS")
+	if rabbitmqPassword == "" {
+		rabbitmqPassword = "password"
+	}
+
+	topic := rabbitqueue.NewRabbitMQTopic("test.topic")
+	topic.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"connection.host", rabbitmqHost,
+		"connection.port", rabbitmqPort,
+		"credential.username", rabbitmqUser,
+		"credential.password", rabbitmqPassword,
+	))
+
+	err := topic.Open(context.Background())
+	assert.Nil(t, err)
+	defer topic.Close(context.Background())
+
+	fixture := NewTopicFixture(topic)
+	t.Run("Publish Subscribe", fixture.TestPublishSubscribe)
+}
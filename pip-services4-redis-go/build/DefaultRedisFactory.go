@@ -5,6 +5,7 @@ import (
 	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
 	rediscache "github.com/pip-services4/pip-services4-go/pip-services4-redis-go/cache"
 	redislock "github.com/pip-services4/pip-services4-go/pip-services4-redis-go/lock"
+	redisqueues "github.com/pip-services4/pip-services4-go/pip-services4-redis-go/queues"
 )
 
 /*
@@ -12,12 +13,19 @@ DefaultRedisFactory are creates Redis components by their descriptors.
 
 See RedisCache
 See RedisLock
+See RedisProcessedMessageStore
+See RedisStreamMessageQueue
 */
 type DefaultRedisFactory struct {
 	*cbuild.Factory
 	Descriptor           *cref.Descriptor
 	RedisCacheDescriptor *cref.Descriptor
 	RedisLockDescriptor  *cref.Descriptor
+
+	RedisProcessedMessageStoreDescriptor *cref.Descriptor
+
+	RedisStreamMessageQueueFactoryDescriptor *cref.Descriptor
+	RedisStreamMessageQueueDescriptor        *cref.Descriptor
 }
 
 // NewDefaultRedisFactory method are create a new instance of the factory.
@@ -29,6 +37,20 @@ func NewDefaultRedisFactory() *DefaultRedisFactory {
 	c.RedisCacheDescriptor = cref.NewDescriptor("pip-services", "cache", "redis", "*", "1.0")
 	c.RedisLockDescriptor = cref.NewDescriptor("pip-services", "lock", "redis", "*", "1.0")
 	c.RegisterType(c.RedisCacheDescriptor, rediscache.NewRedisCache[any])
+	c.RedisProcessedMessageStoreDescriptor = cref.NewDescriptor("pip-services", "processed-message-store", "redis", "*", "1.0")
 	c.RegisterType(c.RedisLockDescriptor, redislock.NewRedisLock)
+	c.RegisterType(c.RedisProcessedMessageStoreDescriptor, redisqueues.NewRedisProcessedMessageStore)
+
+	c.RedisStreamMessageQueueFactoryDescriptor = cref.NewDescriptor("pip-services", "queue-factory", "redis", "*", "1.0")
+	c.RedisStreamMessageQueueDescriptor = cref.NewDescriptor("pip-services", "message-queue", "redis", "*", "1.0")
+	c.RegisterType(c.RedisStreamMessageQueueFactoryDescriptor, NewRedisStreamMessageQueueFactory)
+	c.Register(c.RedisStreamMessageQueueDescriptor, func(locator any) any {
+		name := ""
+		descriptor, ok := locator.(*cref.Descriptor)
+		if ok {
+			name = descriptor.Name()
+		}
+		return redisqueues.NewRedisStreamMessageQueue(name)
+	})
 	return &c
 }
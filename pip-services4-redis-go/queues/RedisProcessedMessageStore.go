@@ -0,0 +1,301 @@
+package queues
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	cauth "github.com/pip-services4/pip-services4-go/pip-services4-config-go/auth"
+	ccon "github.com/pip-services4/pip-services4-go/pip-services4-config-go/connect"
+)
+
+// claimedValue is a value of keys for messages in processing.
+const claimedValue = "claimed"
+
+// releaseClaimScript deletes the key only when it holds a claim,
+// so records of processed messages are never removed.
+var releaseClaimScript = redis.NewScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+/*
+RedisProcessedMessageStore records ids of processed messages in Redis in-memory database.
+It implements IProcessedMessageStore interface of the messaging module and is used
+by IdempotentMessageReceiver to skip redelivered messages.
+The ids are stored as keys that expire after the retention period.
+Messages in processing are claimed with keys that expire after the claim timeout,
+so a message claimed by a crashed receiver can be processed again.
+
+Configuration parameters:
+
+  - connection(s):
+  - discovery_key:         (optional) a key to retrieve the connection from IDiscovery
+  - host:                  host name or IP address
+  - port:                  port number
+  - uri:                   resource URI or connection string with all parameters in it
+  - credential(s):
+  - store_key:             key to retrieve parameters from credential store
+  - username:              user name (currently is not used)
+  - password:              user password
+  - options:
+  - key_prefix:            prefix of the keys for processed message ids (default: processed:)
+  - retention:             number of milliseconds to keep processed message ids (default: 86400000)
+  - claim_timeout:         number of milliseconds to keep claims of messages in processing (default: 300000)
+  - timeout:               connection timeout in milliseconds (default: 30000)
+  - db_num:                database number in Redis  (default 0)
+
+References:
+
+- *:discovery:*:*:1.0        (optional) IDiscovery services to resolve connection
+- *:credential-store:*:*:1.0 (optional) Credential stores to resolve credential
+
+Example:
+
+	ctx := context.Background()
+
+	store := NewRedisProcessedMessageStore()
+	store.Configure(ctx, cconf.NewConfigParamsFromTuples(
+		"host", "localhost",
+		"port", 6379,
+	))
+
+	err := store.Open(ctx)
+	...
+
+	receiver := cqueues.NewIdempotentMessageReceiver(myReceiver, store)
+*/
+type RedisProcessedMessageStore struct {
+	connectionResolver *ccon.ConnectionResolver
+	credentialResolver *cauth.CredentialResolver
+
+	keyPrefix    string
+	retention    int64
+	claimTimeout int64
+	timeout      int
+	dbNum        int
+
+	lock   sync.Mutex
+	client redis.Conn
+}
+
+// NewRedisProcessedMessageStore method are creates a new instance of this store.
+func NewRedisProcessedMessageStore() *RedisProcessedMessageStore {
+	return &RedisProcessedMessageStore{
+		connectionResolver: ccon.NewEmptyConnectionResolver(),
+		credentialResolver: cauth.NewEmptyCredentialResolver(),
+		keyPrefix:          "processed:",
+		retention:          24 * 60 * 60 * 1000,
+		claimTimeout:       5 * 60 * 1000,
+		timeout:            30000,
+		dbNum:              0,
+		client:             nil,
+	}
+}
+
+// Configure method are configures component by passing configuration parameters.
+// Parameters:
+//   - config    configuration parameters to be set.
+func (c *RedisProcessedMessageStore) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.connectionResolver.Configure(ctx, config)
+	c.credentialResolver.Configure(ctx, config)
+
+	c.keyPrefix = config.GetAsStringWithDefault("options.key_prefix", c.keyPrefix)
+	c.retention = config.GetAsLongWithDefault("options.retention", c.retention)
+	c.claimTimeout = config.GetAsLongWithDefault("options.claim_timeout", c.claimTimeout)
+	c.timeout = config.GetAsIntegerWithDefault("options.timeout", c.timeout)
+	c.dbNum = config.GetAsIntegerWithDefault("options.db_num", c.dbNum)
+	if c.dbNum > 15 || c.dbNum < 0 {
+		c.dbNum = 0
+	}
+}
+
+// SetReferences method are sets references to dependent components.
+// Parameters:
+//   - ctx context.Context
+//   - references 	references to locate the component dependencies.
+func (c *RedisProcessedMessageStore) SetReferences(ctx context.Context, references cref.IReferences) {
+	c.connectionResolver.SetReferences(ctx, references)
+	c.credentialResolver.SetReferences(ctx, references)
+}
+
+// IsOpen method are checks if the component is opened.
+// Returns true if the component has been opened and false otherwise.
+func (c *RedisProcessedMessageStore) IsOpen() bool {
+	return c.client != nil
+}
+
+// Open method are opens the component.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//
+// Returns: error or nil no errors occured.
+func (c *RedisProcessedMessageStore) Open(ctx context.Context) error {
+	connection, err := c.connectionResolver.Resolve(ctx)
+	if err != nil {
+		return err
+	}
+	if connection == nil {
+		return cerr.NewConfigError(cctx.GetTraceId(ctx), "NO_CONNECTION", "Connection is not configured")
+	}
+
+	credential, err := c.credentialResolver.Lookup(ctx)
+	if err != nil {
+		return err
+	}
+
+	dialOpts := make([]redis.DialOption, 0)
+	dialOpts = append(dialOpts, redis.DialConnectTimeout(time.Duration(c.timeout)*time.Millisecond))
+	dialOpts = append(dialOpts, redis.DialDatabase(c.dbNum))
+
+	if credential != nil {
+		dialOpts = append(dialOpts, redis.DialPassword(credential.Password()))
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if connection.Uri() != "" {
+		c.client, err = redis.DialURL(connection.Uri(), dialOpts...)
+	} else {
+		host := connection.Host()
+		if host == "" {
+			host = "localhost"
+		}
+		port := strconv.FormatInt(int64(connection.Port()), 10)
+		if port == "0" {
+			port = "6379"
+		}
+		c.client, err = redis.Dial("tcp", host+":"+port, dialOpts...)
+	}
+	return err
+}
+
+// Close method are closes component and frees used resources.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//
+// Retruns: error or nil no errors occured.
+func (c *RedisProcessedMessageStore) Close(ctx context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.client != nil {
+		err := c.client.Close()
+		c.client = nil
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *RedisProcessedMessageStore) checkOpened(traceId string) error {
+	if !c.IsOpen() {
+		return cerr.NewInvalidStateError(traceId, "NOT_OPENED", "Connection is not opened")
+	}
+	return nil
+}
+
+// IsProcessed method are checks if the message was already processed.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - messageId         a unique id of the message.
+//
+// Returns: true if the message was processed within the retention period or error.
+func (c *RedisProcessedMessageStore) IsProcessed(ctx context.Context, messageId string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkOpened(cctx.GetTraceId(ctx)); err != nil {
+		return false, err
+	}
+
+	value, err := redis.String(c.client.Do("GET", c.keyPrefix+messageId))
+	if err == redis.ErrNil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return value != claimedValue, nil
+}
+
+// TryClaim method are atomically claims the message for processing
+// unless it was already processed or claimed by another receiver.
+// The claim expires after the claim timeout.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - messageId         a unique id of the message.
+//
+// Returns: true if the message was claimed or false if it was processed or claimed before, or error.
+func (c *RedisProcessedMessageStore) TryClaim(ctx context.Context, messageId string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkOpened(cctx.GetTraceId(ctx)); err != nil {
+		return false, err
+	}
+
+	reply, err := c.client.Do("SET", c.keyPrefix+messageId, claimedValue, "NX", "PX", c.claimTimeout)
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+// ReleaseClaim method are removes the claim of the message that failed to be processed,
+// so the message can be processed again when it is redelivered.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - messageId         a unique id of the message.
+//
+// Returns: error or nil for success.
+func (c *RedisProcessedMessageStore) ReleaseClaim(ctx context.Context, messageId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkOpened(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	_, err := releaseClaimScript.Do(c.client, c.keyPrefix+messageId, claimedValue)
+	return err
+}
+
+// MarkProcessed method are records the message as processed.
+// The record expires after the retention period.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - messageId         a unique id of the message.
+//
+// Returns: error or nil for success.
+func (c *RedisProcessedMessageStore) MarkProcessed(ctx context.Context, messageId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkOpened(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	_, err := c.client.Do("SET", c.keyPrefix+messageId, time.Now().UTC().Format(time.RFC3339), "PX", c.retention)
+	return err
+}
+
+// Cleanup method are removes ids of messages processed before the retention period.
+// Redis removes expired keys automatically, so the method does nothing.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//
+// Returns: error or nil for success.
+func (c *RedisProcessedMessageStore) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,962 @@
+package queues
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis"
+	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cauth "github.com/pip-services4/pip-services4-go/pip-services4-config-go/auth"
+	ccon "github.com/pip-services4/pip-services4-go/pip-services4-config-go/connect"
+	keys "github.com/pip-services4/pip-services4-go/pip-services4-data-go/keys"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+)
+
+const (
+	streamHeaderPrefix = "header:"
+	claimScanSize      = 100
+	claimInterval      = time.Duration(1000) * time.Millisecond
+)
+
+// streamLockCheck makes scripts return 0 when the message with id ARGV[3] is not pending
+// at the consumer ARGV[2] of the group ARGV[1] or it was delivered again after ARGV[4] deliveries.
+const streamLockCheck = `
+local pending = redis.call("XPENDING", KEYS[1], ARGV[1], ARGV[3], ARGV[3], 1, ARGV[2])
+if not pending or #pending == 0 or tonumber(pending[1][4]) ~= tonumber(ARGV[4]) then
+	return 0
+end
+`
+
+// renewLockScript resets the idle time of a locked message to ARGV[5] milliseconds.
+// The retry count is set explicitly, so the claim does not count a new delivery.
+var renewLockScript = redis.NewScript(streamLockCheck + `
+redis.call("XCLAIM", KEYS[1], ARGV[1], ARGV[2], 0, ARGV[3], "IDLE", ARGV[5], "RETRYCOUNT", ARGV[4], "JUSTID")
+return 1
+`)
+
+// completeScript acknowledges and deletes locked messages given by pairs of ids and delivery counts
+// starting from ARGV[3]. It returns the number of removed messages.
+var completeScript = redis.NewScript(`
+local count = 0
+for i = 3, #ARGV, 2 do
+	local pending = redis.call("XPENDING", KEYS[1], ARGV[1], ARGV[i], ARGV[i], 1, ARGV[2])
+	if pending and #pending > 0 and tonumber(pending[1][4]) == tonumber(ARGV[i + 1]) then
+		redis.call("XACK", KEYS[1], ARGV[1], ARGV[i])
+		redis.call("XDEL", KEYS[1], ARGV[i])
+		count = count + 1
+	end
+end
+return count
+`)
+
+// abandonScript adds the message with fields starting from ARGV[6] to the end of the stream
+// trimmed to ARGV[5] entries and removes the locked original message.
+var abandonScript = redis.NewScript(streamLockCheck + `
+local args = {"XADD", KEYS[1]}
+if tonumber(ARGV[5]) > 0 then
+	table.insert(args, "MAXLEN")
+	table.insert(args, "~")
+	table.insert(args, ARGV[5])
+end
+table.insert(args, "*")
+for i = 6, #ARGV do
+	table.insert(args, ARGV[i])
+end
+redis.call(unpack(args))
+redis.call("XACK", KEYS[1], ARGV[1], ARGV[3])
+redis.call("XDEL", KEYS[1], ARGV[3])
+return 1
+`)
+
+// streamLock is a reference to a received message that identifies its delivery.
+type streamLock struct {
+	id         string
+	deliveries int64
+}
+
+/*
+RedisStreamMessageQueue are message queue that sends and receives messages via Redis Streams.
+
+Receivers of the queue join a consumer group, so every message is delivered to one of them.
+Received messages stay in the pending entries list of the group until they are completed.
+Messages that were not completed within the lock timeout are claimed by other receivers.
+RenewLock resets the lock timeout with XCLAIM command. Its timeout cannot exceed lock_timeout option,
+since other receivers claim messages by their idle time.
+Complete, Abandon and RenewLock check in the pending entries list that the message is still locked
+by the same delivery, so messages claimed by other receivers are left untouched.
+
+Abandoned messages are added again to the end of the stream with incremented delivery count,
+so they are delivered again immediately. Completed messages are acknowledged and deleted from the stream.
+Delayed messages are held by the Scheduler until their visible time.
+
+Configuration parameters:
+
+  - stream:                        (optional) name of Redis stream (default: queue name)
+  - group:                         (optional) name of consumer group (default: default)
+  - consumer:                      (optional) unique name of the consumer in the group (default: generated id)
+  - connection(s):
+  - discovery_key:               (optional) a key to retrieve the connection from IDiscovery
+  - host:                        host name or IP address
+  - port:                        port number
+  - uri:                         resource URI or connection string with all parameters in it
+  - credential(s):
+  - store_key:                   key to retrieve parameters from credential store
+  - username:                    user name (currently is not used)
+  - password:                    user password
+  - options:
+  - lock_timeout:                (optional) number of milliseconds before unfinished messages are claimed by other receivers (default: 30000)
+  - max_length:                  (optional) approximate maximum number of messages kept in the stream (default: 0 unlimited)
+  - dead_letter_queue:           (optional) name of Redis stream to move poison messages to (default: none, messages are dropped)
+  - max_delivery_count:          (optional) maximum number of deliveries before a message is moved to dead letter queue (default: 0 unlimited)
+  - timeout:                     (optional) connection timeout in milliseconds (default: 30000)
+  - retries:                     (optional) number of retries (default: 3)
+  - db_num:                      (optional) database number in Redis  (default 0)
+
+References:
+
+- *:logger:*:*:1.0             (optional) ILogger components to pass log messages
+- *:counters:*:*:1.0           (optional) ICounters components to pass collected measurements
+- *:discovery:*:*:1.0          (optional) IDiscovery services to resolve connection
+- *:credential-store:*:*:1.0   (optional) Credential stores to resolve credential
+
+Example:
+
+	ctx := context.Background()
+	queue := NewRedisStreamMessageQueue("myqueue")
+	queue.Configure(ctx, cconf.NewConfigParamsFromTuples(
+		"group", "mygroup",
+		"connection.host", "localhost",
+		"connection.port", 6379,
+	))
+
+	_ = queue.Open(ctx)
+	_ = queue.Send(ctx, cqueues.NewMessageEnvelope("123", "mymessage", []byte("ABC")))
+
+	message, err := queue.Receive(ctx, 10000*time.Millisecond)
+	if message != nil {
+		...
+		queue.Complete(ctx, message)
+	}
+*/
+type RedisStreamMessageQueue struct {
+	*cqueues.MessageQueue
+
+	// The scheduler that holds delayed messages until their visible time
+	Scheduler *cqueues.MessageDelayScheduler
+
+	client           *redis.Client
+	stream           string
+	group            string
+	consumer         string
+	deadLetterStream string
+	lockTimeout      time.Duration
+	maxLength        int64
+	timeout          int
+	retries          int
+	dbNum            int
+	cancel           int32
+}
+
+// NewRedisStreamMessageQueue method are creates a new instance of the message queue.
+// Parameters:
+//   - name  (optional) a queue name.
+func NewRedisStreamMessageQueue(name string) *RedisStreamMessageQueue {
+	c := RedisStreamMessageQueue{
+		group:       "default",
+		consumer:    keys.IdGenerator.NextShort(),
+		lockTimeout: time.Duration(30000) * time.Millisecond,
+		timeout:     30000,
+		retries:     3,
+		dbNum:       0,
+	}
+
+	c.MessageQueue = cqueues.InheritMessageQueue(&c, name,
+		cqueues.NewMessagingCapabilities(true, true, true, true, true, true, true, true, true).WithDelay(true))
+	c.Scheduler = cqueues.NewMessageDelayScheduler(&c)
+	c.Scheduler.Logger = c.Logger
+
+	return &c
+}
+
+// Configure method are configures component by passing configuration parameters.
+// Parameters:
+//   - ctx context.Context
+//   - config    configuration parameters to be set.
+func (c *RedisStreamMessageQueue) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.MessageQueue.Configure(ctx, config)
+
+	c.stream = config.GetAsStringWithDefault("stream", c.stream)
+	c.group = config.GetAsStringWithDefault("group", c.group)
+	c.consumer = config.GetAsStringWithDefault("consumer", c.consumer)
+
+	c.lockTimeout = time.Duration(config.GetAsLongWithDefault("options.lock_timeout", c.lockTimeout.Milliseconds())) * time.Millisecond
+	c.maxLength = config.GetAsLongWithDefault("options.max_length", c.maxLength)
+	c.deadLetterStream = config.GetAsStringWithDefault("options.dead_letter_queue", c.deadLetterStream)
+	c.timeout = config.GetAsIntegerWithDefault("options.timeout", c.timeout)
+	c.retries = config.GetAsIntegerWithDefault("options.retries", c.retries)
+	c.dbNum = config.GetAsIntegerWithDefault("options.db_num", c.dbNum)
+	if c.dbNum > 15 || c.dbNum < 0 {
+		c.dbNum = 0
+	}
+}
+
+func (c *RedisStreamMessageQueue) getStream() string {
+	if c.stream != "" {
+		return c.stream
+	}
+	return c.Name()
+}
+
+// IsOpen method are checks if the component is opened.
+// Returns true if the component has been opened and false otherwise.
+func (c *RedisStreamMessageQueue) IsOpen() bool {
+	return c.client != nil
+}
+
+// OpenWithParams method are opens the component with given connection and credential parameters.
+// The consumer group is created together with the stream when they do not exist.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - connections       connection parameters
+//   - credential        credential parameters
+//
+// Returns: error or nil no errors occured.
+func (c *RedisStreamMessageQueue) OpenWithParams(ctx context.Context, connections []*ccon.ConnectionParams,
+	credential *cauth.CredentialParams) (err error) {
+	if c.IsOpen() {
+		return nil
+	}
+
+	connection := connections[0]
+	options := &redis.Options{}
+	if connection.Uri() != "" {
+		options, err = redis.ParseURL(connection.Uri())
+		if err != nil {
+			return err
+		}
+	} else {
+		host := connection.Host()
+		if host == "" {
+			host = "localhost"
+		}
+		port := strconv.FormatInt(int64(connection.Port()), 10)
+		if port == "0" {
+			port = "6379"
+		}
+		options.Addr = host + ":" + port
+		options.DB = c.dbNum
+	}
+	options.DialTimeout = time.Duration(c.timeout) * time.Millisecond
+	options.MaxRetries = c.retries
+
+	if credential != nil && credential.Password() != "" {
+		options.Password = credential.Password()
+	}
+
+	client := redis.NewClient(options)
+	if err = client.Ping().Err(); err != nil {
+		_ = client.Close()
+		return err
+	}
+
+	// Read messages sent before the group was created
+	err = client.XGroupCreateMkStream(c.getStream(), c.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		_ = client.Close()
+		return err
+	}
+
+	c.Lock.Lock()
+	c.client = client
+	c.Lock.Unlock()
+
+	c.Scheduler.Start(ctx)
+
+	c.Logger.Debug(ctx, "Connected to Redis stream %s as %s in group %s", c.getStream(), c.consumer, c.group)
+	return nil
+}
+
+// Close method are closes component and frees used resources.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//
+// Returns: error or nil no errors occured.
+func (c *RedisStreamMessageQueue) Close(ctx context.Context) error {
+	c.EndListen(ctx)
+	c.Scheduler.Stop(ctx)
+
+	c.Lock.Lock()
+	client := c.client
+	c.client = nil
+	c.Lock.Unlock()
+
+	if client == nil {
+		return nil
+	}
+
+	c.Logger.Trace(ctx, "Closed queue %s", c.Name())
+	return client.Close()
+}
+
+// Clear method are clears component state.
+// The stream is removed with all its messages and the consumer group is created again.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//
+// Returns: error or nil no errors occured.
+func (c *RedisStreamMessageQueue) Clear(ctx context.Context) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	_, err := c.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Del(c.getStream())
+		pipe.XGroupCreateMkStream(c.getStream(), c.group, "0")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.Scheduler.Clear(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Trace(ctx, "Cleared queue %s", c.Name())
+	return nil
+}
+
+// ReadMessageCount method are reads the current number of messages in the queue to be delivered.
+// Messages locked by receivers are not counted.
+// Returns: number of messages or error.
+func (c *RedisStreamMessageQueue) ReadMessageCount() (int64, error) {
+	if err := c.CheckOpen(""); err != nil {
+		return 0, err
+	}
+
+	length, err := c.client.XLen(c.getStream()).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	pending, err := c.client.XPending(c.getStream(), c.group).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	count := length - pending.Count
+	if count < 0 {
+		count = 0
+	}
+	return count, nil
+}
+
+func (c *RedisStreamMessageQueue) fromMessage(message *cqueues.MessageEnvelope) map[string]any {
+	values := map[string]any{
+		"message": string(message.Message),
+	}
+	if message.MessageId != "" {
+		values["message_id"] = message.MessageId
+	}
+	if message.MessageType != "" {
+		values["message_type"] = message.MessageType
+	}
+	if message.TraceId != "" {
+		values["trace_id"] = message.TraceId
+	}
+	if !message.SentTime.IsZero() {
+		values["sent_time"] = message.SentTime.UTC().Format(time.RFC3339Nano)
+	}
+	if message.DeliveryCount > 0 {
+		values["delivery_count"] = message.DeliveryCount
+	}
+	for key, value := range message.Headers {
+		values[streamHeaderPrefix+key] = value
+	}
+	return values
+}
+
+func (c *RedisStreamMessageQueue) toMessage(entry *redis.XMessage, deliveries int64) *cqueues.MessageEnvelope {
+	message := cqueues.NewEmptyMessageEnvelope()
+
+	for key, value := range entry.Values {
+		text := cconv.StringConverter.ToString(value)
+		switch {
+		case key == "message":
+			message.Message = []byte(text)
+		case key == "message_id":
+			message.MessageId = text
+		case key == "message_type":
+			message.MessageType = text
+		case key == "trace_id":
+			message.TraceId = text
+		case key == "sent_time":
+			message.SentTime = cconv.DateTimeConverter.ToDateTime(text)
+		case key == "delivery_count":
+			message.DeliveryCount = cconv.IntegerConverter.ToInteger(text)
+		case strings.HasPrefix(key, streamHeaderPrefix):
+			message.SetHeader(strings.TrimPrefix(key, streamHeaderPrefix), text)
+		}
+	}
+
+	// Count deliveries in the stream on top of the deliveries before the message was abandoned
+	message.DeliveryCount += int(deliveries)
+	message.SetReference(&streamLock{id: entry.ID, deliveries: deliveries})
+
+	return message
+}
+
+func (c *RedisStreamMessageQueue) addArgs(stream string, message *cqueues.MessageEnvelope) *redis.XAddArgs {
+	return &redis.XAddArgs{
+		Stream:       stream,
+		MaxLenApprox: c.maxLength,
+		Values:       c.fromMessage(message),
+	}
+}
+
+// Send method are sends a message into the queue.
+// Delayed messages are held by the Scheduler and sent when their visible time comes.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - message a message envelop to be sent.
+//
+// Returns: error or nil for success.
+func (c *RedisStreamMessageQueue) Send(ctx context.Context, message *cqueues.MessageEnvelope) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	// Propagate W3C trace context and baggage to receivers
+	cqueues.InjectTraceContext(ctx, message)
+
+	if !message.IsVisible(time.Now()) {
+		return c.Scheduler.Schedule(ctx, message)
+	}
+
+	if message.SentTime.IsZero() {
+		message.SentTime = time.Now()
+	}
+
+	err := c.client.XAdd(c.addArgs(c.getStream(), message)).Err()
+	if err != nil {
+		return err
+	}
+
+	c.Counters.IncrementOne(ctx, "queue."+c.Name()+".sent_messages")
+	c.Logger.Debug(cctx.NewContextWithTraceId(ctx, message.TraceId), "Sent message %s via %s", message, c.Name())
+	return nil
+}
+
+// SendBatch method are sends multiple messages into the queue.
+// Messages are added to the stream in a single pipeline.
+// Delayed messages are held by the Scheduler the same way as by Send.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - messages a list of message envelops to be sent.
+//
+// Returns: error or nil for success.
+func (c *RedisStreamMessageQueue) SendBatch(ctx context.Context, messages []*cqueues.MessageEnvelope) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	visible := make([]*cqueues.MessageEnvelope, 0, len(messages))
+	for _, message := range messages {
+		cqueues.InjectTraceContext(ctx, message)
+
+		if !message.IsVisible(now) {
+			if err := c.Scheduler.Schedule(ctx, message); err != nil {
+				return err
+			}
+			continue
+		}
+		if message.SentTime.IsZero() {
+			message.SentTime = now
+		}
+		visible = append(visible, message)
+	}
+
+	if len(visible) == 0 {
+		return nil
+	}
+
+	_, err := c.client.Pipelined(func(pipe redis.Pipeliner) error {
+		for _, message := range visible {
+			pipe.XAdd(c.addArgs(c.getStream(), message))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Counters.Increment(ctx, "queue."+c.Name()+".sent_messages", int64(len(visible)))
+	c.Logger.Debug(ctx, "Sent %d messages via %s", len(visible), c.Name())
+	return nil
+}
+
+// Peek method are peeks a single incoming message from the queue without removing it.
+// If there are no messages available in the queue it returns nil.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//
+// Returns: a message or error.
+func (c *RedisStreamMessageQueue) Peek(ctx context.Context) (*cqueues.MessageEnvelope, error) {
+	messages, err := c.PeekBatch(ctx, 1)
+	if err != nil || len(messages) == 0 {
+		return nil, err
+	}
+	return messages[0], nil
+}
+
+// PeekBatch method are peeks multiple incoming messages from the queue without removing them.
+// Messages locked by receivers are skipped.
+// If there are no messages available in the queue it returns an empty list.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - messageCount a maximum number of messages to peek.
+//
+// Returns: a list with messages or error.
+func (c *RedisStreamMessageQueue) PeekBatch(ctx context.Context, messageCount int64) ([]*cqueues.MessageEnvelope, error) {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return nil, err
+	}
+
+	stream := c.getStream()
+	pending, err := c.client.XPending(stream, c.group).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	locked := make(map[string]bool)
+	if pending.Count > 0 {
+		entries, err := c.client.XPendingExt(&redis.XPendingExtArgs{
+			Stream: stream,
+			Group:  c.group,
+			Start:  "-",
+			End:    "+",
+			Count:  pending.Count,
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			locked[entry.Id] = true
+		}
+	}
+
+	entries, err := c.client.XRangeN(stream, "-", "+", messageCount+int64(len(locked))).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*cqueues.MessageEnvelope, 0)
+	for index := range entries {
+		if int64(len(messages)) >= messageCount {
+			break
+		}
+		if locked[entries[index].ID] {
+			continue
+		}
+		message := c.toMessage(&entries[index], 0)
+		message.SetReference(nil)
+		messages = append(messages, message)
+	}
+
+	c.Logger.Trace(ctx, "Peeked %d messages on %s", len(messages), c.Name())
+	return messages, nil
+}
+
+// Receive method are receives an incoming message and locks it until it is completed or abandoned.
+// Messages which locks expired at other receivers are received first.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - waitTimeout a timeout in milliseconds to wait for a message to come.
+//
+// Returns: a message or error.
+func (c *RedisStreamMessageQueue) Receive(ctx context.Context, waitTimeout time.Duration) (*cqueues.MessageEnvelope, error) {
+	messages, err := c.ReceiveBatch(ctx, 1, waitTimeout)
+	if err != nil || len(messages) == 0 {
+		return nil, err
+	}
+	return messages[0], nil
+}
+
+// ReceiveBatch method are receives multiple incoming messages and locks them until they are completed or abandoned.
+// The method waits for the first message to come and then reads all available messages up to the maximum count.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - maxCount a maximum number of messages to receive.
+//   - waitTimeout a timeout in milliseconds to wait for messages to come.
+//
+// Returns: a list with messages or error.
+func (c *RedisStreamMessageQueue) ReceiveBatch(ctx context.Context, maxCount int64,
+	waitTimeout time.Duration) ([]*cqueues.MessageEnvelope, error) {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return nil, err
+	}
+
+	messages := make([]*cqueues.MessageEnvelope, 0)
+	deadline := time.Now().Add(waitTimeout)
+
+	for int64(len(messages)) < maxCount {
+		count := maxCount - int64(len(messages))
+
+		entries, deliveries, err := c.claimExpired(count)
+		if err != nil {
+			return messages, err
+		}
+
+		if len(entries) == 0 {
+			// Wait only for the first message. Block 0 means forever in Redis, so it is never used.
+			// Waiting is interrupted periodically to check for expired locks.
+			block := time.Until(deadline)
+			if len(messages) > 0 || block < time.Millisecond {
+				block = -1
+			} else if block > claimInterval {
+				block = claimInterval
+			}
+
+			entries, err = c.readNew(count, block)
+			if err != nil {
+				return messages, err
+			}
+			if len(entries) == 0 {
+				if block < 0 {
+					break
+				}
+				continue
+			}
+			deliveries = make([]int64, len(entries))
+			for index := range deliveries {
+				deliveries[index] = 1
+			}
+		}
+
+		for index := range entries {
+			message := c.toMessage(&entries[index], deliveries[index])
+
+			// Move poison messages to dead letter queue and read the next one
+			if c.MoveToDeadLetterIfExceeded(ctx, message) {
+				continue
+			}
+
+			c.Counters.IncrementOne(ctx, "queue."+c.Name()+".received_messages")
+			c.Logger.Debug(cctx.NewContextWithTraceId(ctx, message.TraceId), "Received message %s via %s", message, c.Name())
+			messages = append(messages, message)
+		}
+	}
+
+	return messages, nil
+}
+
+// claimExpired takes over pending messages which locks expired.
+// It returns claimed messages with numbers of their deliveries in the stream.
+func (c *RedisStreamMessageQueue) claimExpired(count int64) ([]redis.XMessage, []int64, error) {
+	stream := c.getStream()
+
+	// Look behind messages which are still locked by other receivers
+	pending, err := c.client.XPendingExt(&redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  c.group,
+		Start:  "-",
+		End:    "+",
+		Count:  count + claimScanSize,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	retries := make(map[string]int64)
+	ids := make([]string, 0)
+	for _, entry := range pending {
+		if int64(len(ids)) >= count {
+			break
+		}
+		if entry.Idle >= c.lockTimeout {
+			retries[entry.Id] = entry.RetryCount
+			ids = append(ids, entry.Id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil, nil
+	}
+
+	// Minimal idle time makes sure another receiver did not claim the messages first
+	entries, err := c.client.XClaim(&redis.XClaimArgs{
+		Stream:   stream,
+		Group:    c.group,
+		Consumer: c.consumer,
+		MinIdle:  c.lockTimeout,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The claim counts one more delivery
+	deliveries := make([]int64, len(entries))
+	for index, entry := range entries {
+		deliveries[index] = retries[entry.ID] + 1
+	}
+	return entries, deliveries, nil
+}
+
+// readNew reads messages that were not delivered to the consumer group yet.
+func (c *RedisStreamMessageQueue) readNew(count int64, block time.Duration) ([]redis.XMessage, error) {
+	streams, err := c.client.XReadGroup(&redis.XReadGroupArgs{
+		Group:    c.group,
+		Consumer: c.consumer,
+		Streams:  []string{c.getStream(), ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]redis.XMessage, 0)
+	for _, stream := range streams {
+		entries = append(entries, stream.Messages...)
+	}
+	return entries, nil
+}
+
+// RenewLock method are renews a lock on a message that makes it invisible from other receivers in the queue.
+// The message is claimed again by the consumer with XCLAIM command, that sets its idle time,
+// so the message is claimed by other receivers after the given timeout.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - message a message to extend its lock.
+//   - lockTimeout a locking timeout in milliseconds. 0 to use lock_timeout option.
+//
+// Returns: error or nil for success. BadRequestError is returned when the timeout exceeds lock_timeout option.
+func (c *RedisStreamMessageQueue) RenewLock(ctx context.Context, message *cqueues.MessageEnvelope, lockTimeout time.Duration) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	lock, ok := message.GetReference().(*streamLock)
+	if !ok {
+		return nil
+	}
+
+	if lockTimeout <= 0 {
+		lockTimeout = c.lockTimeout
+	}
+	if lockTimeout > c.lockTimeout {
+		return cerr.NewBadRequestError(cctx.GetTraceId(ctx), "INVALID_LOCK_TIMEOUT",
+			"Lock timeout cannot exceed lock_timeout option of the queue").
+			WithDetails("lock_timeout", lockTimeout.Milliseconds())
+	}
+
+	// Messages which locks expired may be already received by others
+	idle := (c.lockTimeout - lockTimeout).Milliseconds()
+	err := renewLockScript.Run(c.client, []string{c.getStream()},
+		c.group, c.consumer, lock.id, lock.deliveries, idle).Err()
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Renewed lock for message %s at %s", message, c.Name())
+	return nil
+}
+
+// Complete method are permanently removes a message from the queue.
+// The message is acknowledged in the consumer group and deleted from the stream
+// unless its lock expired and it was claimed by another receiver.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - message a message to remove.
+//
+// Returns: error or nil for success.
+func (c *RedisStreamMessageQueue) Complete(ctx context.Context, message *cqueues.MessageEnvelope) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	return c.CompleteBatch(ctx, []*cqueues.MessageEnvelope{message})
+}
+
+// CompleteBatch method are permanently removes multiple messages from the queue.
+// All messages are acknowledged and deleted by a single script.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - messages a list of messages to remove.
+//
+// Returns: error or nil for success.
+func (c *RedisStreamMessageQueue) CompleteBatch(ctx context.Context, messages []*cqueues.MessageEnvelope) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	args := []any{c.group, c.consumer}
+	for _, message := range messages {
+		if lock, ok := message.GetReference().(*streamLock); ok {
+			args = append(args, lock.id, lock.deliveries)
+		}
+	}
+	if len(args) == 2 {
+		return nil
+	}
+
+	// Messages which locks expired may be already received by others
+	err := completeScript.Run(c.client, []string{c.getStream()}, args...).Err()
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		message.SetReference(nil)
+		c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Completed message %s at %s", message, c.Name())
+	}
+	return nil
+}
+
+// Abandon method are returns message into the queue and makes it available for all subscribers to receive it again.
+// The message is added to the end of the stream with its delivery count
+// and the original message is removed by the same script.
+// Messages which locks expired and were claimed by other receivers are left untouched.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - message a message to return.
+//
+// Returns: error or nil for success.
+func (c *RedisStreamMessageQueue) Abandon(ctx context.Context, message *cqueues.MessageEnvelope) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	lock, ok := message.GetReference().(*streamLock)
+	if !ok {
+		return nil
+	}
+
+	resent := message.Clone()
+	resent.VisibleTime = time.Time{}
+
+	args := []any{c.group, c.consumer, lock.id, lock.deliveries, c.maxLength}
+	for key, value := range c.fromMessage(resent) {
+		args = append(args, key, cconv.StringConverter.ToString(value))
+	}
+	err := abandonScript.Run(c.client, []string{c.getStream()}, args...).Err()
+	if err != nil {
+		return err
+	}
+
+	message.SetReference(nil)
+	c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Abandoned message %s at %s", message, c.Name())
+	return nil
+}
+
+// MoveToDeadLetter method are permanently removes a message from the queue and sends it to dead letter queue.
+// The message is sent to DeadLetterQueue when it is set or to the stream
+// configured by options.dead_letter_queue. Otherwise it is dropped.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - message a message to be removed.
+//
+// Returns: error or nil for success.
+func (c *RedisStreamMessageQueue) MoveToDeadLetter(ctx context.Context, message *cqueues.MessageEnvelope) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	sent, err := c.SendToDeadLetterQueue(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	if !sent && c.deadLetterStream != "" {
+		dead := message.Clone()
+		dead.VisibleTime = time.Time{}
+
+		err = c.client.XAdd(c.addArgs(c.deadLetterStream, dead)).Err()
+		if err != nil {
+			c.Logger.Error(cctx.NewContextWithTraceId(ctx, message.TraceId), err, "Failed to move message to %s", c.deadLetterStream)
+			return err
+		}
+	}
+
+	c.Counters.IncrementOne(ctx, "queue."+c.Name()+".dead_messages")
+	c.Logger.Trace(cctx.NewContextWithTraceId(ctx, message.TraceId), "Moved to dead message %s at %s", message, c.Name())
+
+	return c.Complete(ctx, message)
+}
+
+// Listen method are listens for incoming messages and blocks the current thread until queue is closed.
+// The receiver is responsible to complete or abandon received messages.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+//   - receiver a receiver to receive incoming messages.
+//
+// Returns: error or nil for success.
+func (c *RedisStreamMessageQueue) Listen(ctx context.Context, receiver cqueues.IMessageReceiver) error {
+	if err := c.CheckOpen(cctx.GetTraceId(ctx)); err != nil {
+		return err
+	}
+
+	c.Logger.Trace(ctx, "Started listening messages at %s", c.Name())
+
+	// Unset cancellation token
+	atomic.StoreInt32(&c.cancel, 0)
+
+	for atomic.LoadInt32(&c.cancel) == 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		message, err := c.Receive(ctx, time.Duration(1000)*time.Millisecond)
+		if err != nil {
+			c.Logger.Error(ctx, err, "Failed to receive the message")
+			if !c.IsOpen() {
+				return nil
+			}
+			time.Sleep(time.Duration(1000) * time.Millisecond)
+			continue
+		}
+
+		if message != nil && atomic.LoadInt32(&c.cancel) == 0 {
+			func(message *cqueues.MessageEnvelope) {
+				defer func() {
+					if r := recover(); r != nil {
+						c.Logger.Error(ctx, nil, "Failed to process the message - "+fmt.Sprintf("%v", r))
+					}
+				}()
+
+				err = receiver.ReceiveMessage(cqueues.ExtractTraceContext(ctx, message), message, c)
+				if err != nil {
+					c.Logger.Error(cctx.NewContextWithTraceId(ctx, message.TraceId), err, "Failed to process the message")
+				}
+			}(message)
+		}
+	}
+
+	c.Logger.Trace(ctx, "Stopped listening messages at %s", c.Name())
+	return nil
+}
+
+// EndListen method are ends listening for incoming messages.
+// When this method is call listen unblocks the thread and execution continues.
+// Parameters:
+//   - ctx context.Context transaction id to trace execution through call chain.
+func (c *RedisStreamMessageQueue) EndListen(ctx context.Context) {
+	atomic.StoreInt32(&c.cancel, 1)
+}
@@ -0,0 +1,124 @@
+package test_queues
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	redisqueues "github.com/pip-services4/pip-services4-go/pip-services4-redis-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisProcessedMessageStore(t *testing.T) {
+	ctx := context.Background()
+
+	host := os.Getenv("REDIS_SERVICE_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+
+	port := os.Getenv("REDIS_SERVICE_PORT")
+	if port == "" {
+		port = "6379"
+	}
+
+	store := redisqueues.NewRedisProcessedMessageStore()
+	store.Configure(ctx, cconf.NewConfigParamsFromTuples(
+		"connection.host", host,
+		"connection.port", port,
+		"options.retention", 500,
+	))
+
+	err := store.Open(ctx)
+	assert.Nil(t, err)
+	defer store.Close(ctx)
+
+	processed, err := store.IsProcessed(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.False(t, processed)
+
+	err = store.MarkProcessed(ctx, "test_message")
+	assert.Nil(t, err)
+
+	processed, err = store.IsProcessed(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.True(t, processed)
+
+	time.Sleep(1000 * time.Millisecond)
+
+	processed, err = store.IsProcessed(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.False(t, processed)
+	assert.Nil(t, store.Cleanup(ctx))
+}
+
+func TestRedisProcessedMessageStoreClaims(t *testing.T) {
+	ctx := context.Background()
+
+	host := os.Getenv("REDIS_SERVICE_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+
+	port := os.Getenv("REDIS_SERVICE_PORT")
+	if port == "" {
+		port = "6379"
+	}
+
+	store := redisqueues.NewRedisProcessedMessageStore()
+	store.Configure(ctx, cconf.NewConfigParamsFromTuples(
+		"connection.host", host,
+		"connection.port", port,
+		"options.key_prefix", "claims:",
+		"options.claim_timeout", 500,
+	))
+
+	err := store.Open(ctx)
+	assert.Nil(t, err)
+	defer store.Close(ctx)
+
+	claimed, err := store.TryClaim(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+
+	// Claimed messages are not processed and cannot be claimed again
+	claimed, err = store.TryClaim(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.False(t, claimed)
+
+	processed, err := store.IsProcessed(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.False(t, processed)
+
+	// Released claims can be claimed again
+	err = store.ReleaseClaim(ctx, "test_message")
+	assert.Nil(t, err)
+
+	claimed, err = store.TryClaim(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+
+	// Expired claims can be claimed again
+	time.Sleep(1000 * time.Millisecond)
+
+	claimed, err = store.TryClaim(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+
+	// Processed messages cannot be claimed and their records are not released
+	err = store.MarkProcessed(ctx, "test_message")
+	assert.Nil(t, err)
+
+	claimed, err = store.TryClaim(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.False(t, claimed)
+
+	err = store.ReleaseClaim(ctx, "test_message")
+	assert.Nil(t, err)
+
+	processed, err = store.IsProcessed(ctx, "test_message")
+	assert.Nil(t, err)
+	assert.True(t, processed)
+}
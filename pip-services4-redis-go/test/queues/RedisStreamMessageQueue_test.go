@@ -0,0 +1,207 @@
+package test_queues
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	redisqueues "github.com/pip-services4/pip-services4-go/pip-services4-redis-go/queues"
+	"github.com/stretchr/testify/assert"
+)
+
+type redisStreamMessageQueueTest struct {
+	queue   *redisqueues.RedisStreamMessageQueue
+	fixture *MessageQueueFixture
+}
+
+func newRedisStreamMessageQueueTest(options ...any) *redisStreamMessageQueueTest {
+	host := os.Getenv("REDIS_SERVICE_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+
+	port := os.Getenv("REDIS_SERVICE_PORT")
+	if port == "" {
+		port = "6379"
+	}
+
+	queue := redisqueues.NewRedisStreamMessageQueue("test")
+	queue.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"stream", "test_stream",
+		"group", "test_group",
+		"connection.host", host,
+		"connection.port", port,
+	).Override(cconf.NewConfigParamsFromTuples(options...)))
+
+	return &redisStreamMessageQueueTest{
+		queue:   queue,
+		fixture: NewMessageQueueFixture(queue),
+	}
+}
+
+func (c *redisStreamMessageQueueTest) setup(t *testing.T) {
+	err := c.queue.Open(context.Background())
+	if err != nil {
+		t.Error("Failed to open queue", err)
+		return
+	}
+
+	err = c.queue.Clear(context.Background())
+	if err != nil {
+		t.Error("Failed to clear queue", err)
+	}
+}
+
+func (c *redisStreamMessageQueueTest) teardown(t *testing.T) {
+	err := c.queue.Close(context.Background())
+	if err != nil {
+		t.Error("Failed to close queue", err)
+	}
+}
+
+func TestRedisStreamMessageQueue(t *testing.T) {
+	c := newRedisStreamMessageQueueTest()
+
+	c.setup(t)
+	t.Run("Send Receive Message", c.fixture.TestSendReceiveMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Receive Send Message", c.fixture.TestReceiveSendMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Receive Complete Message", c.fixture.TestReceiveCompleteMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Receive Abandon Message", c.fixture.TestReceiveAbandonMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Peek Message", c.fixture.TestSendPeekMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Peek No Message", c.fixture.TestPeekNoMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Move To Dead Message", c.fixture.TestMoveToDeadMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("On Message", c.fixture.TestOnMessage)
+	t.Run("Trace Context Propagation", c.fixture.TestTraceContextPropagation)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Delayed Message", c.fixture.TestSendDelayedMessage)
+	c.teardown(t)
+
+	c.setup(t)
+	t.Run("Send Receive Batch", c.fixture.TestSendReceiveBatch)
+	c.teardown(t)
+}
+
+func TestRedisStreamMessageQueueLockAndDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	c := newRedisStreamMessageQueueTest(
+		"options.lock_timeout", 500,
+		"options.max_delivery_count", 2,
+		"options.dead_letter_queue", "test_stream_dead",
+	)
+	c.setup(t)
+	defer c.teardown(t)
+
+	err := c.queue.Send(ctx, cqueues.NewMessageEnvelope("123", "Test", []byte("Test message")))
+	assert.Nil(t, err)
+
+	envelope, err := c.queue.Receive(ctx, 10000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope)
+	assert.Equal(t, 1, envelope.DeliveryCount)
+
+	// Renewed lock keeps the message invisible
+	time.Sleep(300 * time.Millisecond)
+	err = c.queue.RenewLock(ctx, envelope, 500*time.Millisecond)
+	assert.Nil(t, err)
+	time.Sleep(300 * time.Millisecond)
+
+	locked, err := c.queue.Receive(ctx, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, locked)
+
+	// Expired lock makes the message visible again
+	envelope, err = c.queue.Receive(ctx, 10000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope)
+	assert.Equal(t, 2, envelope.DeliveryCount)
+
+	// The third delivery exceeds the limit
+	err = c.queue.Abandon(ctx, envelope)
+	assert.Nil(t, err)
+
+	envelope, err = c.queue.Receive(ctx, 500*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Nil(t, envelope)
+
+	count, err := c.queue.ReadMessageCount()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestRedisStreamMessageQueueLockOwnership(t *testing.T) {
+	ctx := context.Background()
+	c1 := newRedisStreamMessageQueueTest(
+		"consumer", "consumer1",
+		"options.lock_timeout", 500,
+	)
+	c1.setup(t)
+	defer c1.teardown(t)
+
+	c2 := newRedisStreamMessageQueueTest(
+		"consumer", "consumer2",
+		"options.lock_timeout", 500,
+	)
+	err := c2.queue.Open(ctx)
+	assert.Nil(t, err)
+	defer c2.teardown(t)
+
+	err = c1.queue.Send(ctx, cqueues.NewMessageEnvelope("123", "Test", []byte("Test message")))
+	assert.Nil(t, err)
+
+	envelope1, err := c1.queue.Receive(ctx, 10000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope1)
+
+	// Lock cannot be longer than the lock timeout of the queue
+	err = c1.queue.RenewLock(ctx, envelope1, 1000*time.Millisecond)
+	assert.NotNil(t, err)
+
+	// Expired lock is claimed by another receiver
+	envelope2, err := c2.queue.Receive(ctx, 10000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope2)
+	assert.Equal(t, 2, envelope2.DeliveryCount)
+
+	// The first receiver lost the lock and cannot remove the message
+	err = c1.queue.RenewLock(ctx, envelope1, 500*time.Millisecond)
+	assert.Nil(t, err)
+	err = c1.queue.Complete(ctx, envelope1)
+	assert.Nil(t, err)
+
+	err = c2.queue.Abandon(ctx, envelope2)
+	assert.Nil(t, err)
+
+	envelope2, err = c2.queue.Receive(ctx, 10000*time.Millisecond)
+	assert.Nil(t, err)
+	assert.NotNil(t, envelope2)
+	assert.Equal(t, 3, envelope2.DeliveryCount)
+
+	err = c2.queue.Complete(ctx, envelope2)
+	assert.Nil(t, err)
+}
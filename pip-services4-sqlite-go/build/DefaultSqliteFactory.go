@@ -4,12 +4,14 @@ import (
 	cbuild "github.com/pip-services4/pip-services4-go/pip-services4-components-go/build"
 	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
 	conn "github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/connect"
+	sqlitequeues "github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/queues"
 )
 
 // DefaultSqliteFactory helps creates Sqlite components by their descriptors.
 //
 //	see Factory
 //	see SqliteConnection
+//	see SqliteMessageQueue
 type DefaultSqliteFactory struct {
 	cbuild.Factory
 }
@@ -23,5 +25,18 @@ func NewDefaultSqliteFactory() *DefaultSqliteFactory {
 	sqliteConnectionDescriptor := cref.NewDescriptor("pip-services", "connection", "sqlite", "*", "1.0")
 
 	c.RegisterType(sqliteConnectionDescriptor, conn.NewSqliteConnection)
+
+	sqliteQueueFactoryDescriptor := cref.NewDescriptor("pip-services", "queue-factory", "sqlite", "*", "1.0")
+	sqliteQueueDescriptor := cref.NewDescriptor("pip-services", "message-queue", "sqlite", "*", "1.0")
+	c.RegisterType(sqliteQueueFactoryDescriptor, NewSqliteMessageQueueFactory)
+	c.Register(sqliteQueueDescriptor, func(locator any) any {
+		name := ""
+		descriptor, ok := locator.(*cref.Descriptor)
+		if ok {
+			name = descriptor.Name()
+		}
+		return sqlitequeues.NewSqliteMessageQueue(name)
+	})
+
 	return &c
 }
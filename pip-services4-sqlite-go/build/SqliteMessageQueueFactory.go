@@ -0,0 +1,56 @@
+package build
+
+import (
+	"context"
+
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	"github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/build"
+	cqueues "github.com/pip-services4/pip-services4-go/pip-services4-messaging-go/queues"
+	sqlitequeues "github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/queues"
+)
+
+// SqliteMessageQueueFactory are creates SqliteMessageQueue components by their descriptors.
+// Name of created message queue is taken from its descriptor.
+//
+// See Factory
+// See SqliteMessageQueue
+type SqliteMessageQueueFactory struct {
+	*build.MessageQueueFactory
+}
+
+// NewSqliteMessageQueueFactory method are create a new instance of the factory.
+func NewSqliteMessageQueueFactory() *SqliteMessageQueueFactory {
+	c := SqliteMessageQueueFactory{
+		MessageQueueFactory: build.InheritMessageQueueFactory(),
+	}
+
+	sqliteQueueDescriptor := cref.NewDescriptor("pip-services", "message-queue", "sqlite", "*", "1.0")
+
+	c.Register(sqliteQueueDescriptor, func(locator interface{}) interface{} {
+		name := ""
+		descriptor, ok := locator.(*cref.Descriptor)
+		if ok {
+			name = descriptor.Name()
+		}
+		return c.CreateQueue(name)
+	})
+
+	return &c
+}
+
+// Creates a message queue component and assigns its name.
+//
+// Parameters:
+//   - name: a name of the created message queue.
+func (c *SqliteMessageQueueFactory) CreateQueue(name string) cqueues.IMessageQueue {
+	queue := sqlitequeues.NewSqliteMessageQueue(name)
+
+	if c.Config != nil {
+		queue.Configure(context.Background(), c.Config)
+	}
+	if c.References != nil {
+		queue.SetReferences(context.Background(), c.References)
+	}
+
+	return queue
+}
@@ -7,6 +7,7 @@ import (
 
 	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
 	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 )
 
 // IdentifiableJsonSqlitePersistence is an abstract persistence component that stores data in SQLite in JSON or JSONB fields
@@ -103,6 +104,7 @@ type IdentifiableJsonSqlitePersistence[T any, K any] struct {
 func InheritIdentifiableJsonSqlitePersistence[T any, K any](overrides ISqlitePersistenceOverrides[T], tableName string) *IdentifiableJsonSqlitePersistence[T, K] {
 	c := &IdentifiableJsonSqlitePersistence[T, K]{}
 	c.IdentifiableSqlitePersistence = InheritIdentifiableSqlitePersistence[T, K](overrides, tableName)
+	c.FilterCompiler = NewJsonSqliteFilterCompiler("data")
 	return c
 }
 
@@ -203,6 +205,8 @@ func (c *IdentifiableJsonSqlitePersistence[T, K]) ConvertFromPublicPartial(value
 }
 
 // UpdatePartially updates only few selected fields in a data item.
+// When Versioned option is on the version is taken from "version" field in the data
+// or from the stored item, and ConflictError is returned when it does not match.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -212,22 +216,53 @@ func (c *IdentifiableJsonSqlitePersistence[T, K]) ConvertFromPublicPartial(value
 // Returns: receives updated item or error.
 func (c *IdentifiableJsonSqlitePersistence[T, K]) UpdatePartially(ctx context.Context,
 	id K, data cdata.AnyValueMap) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updatePartially(ctx, id, data)
+		})
+}
+
+func (c *IdentifiableJsonSqlitePersistence[T, K]) updatePartially(ctx context.Context,
+	id K, data cdata.AnyValueMap) (result T, err error) {
+	version, versioned := "", false
+	if c.Versioned && cpersist.IsVersionedType[T]() {
+		if version, err = c.getPartialVersion(ctx, id, data); err != nil {
+			return result, err
+		}
+		versioned = true
+		data = *cdata.NewAnyValueMapFromMaps(data.Value(), map[string]any{"version": cpersist.NextVersion(version)})
+	}
+	data = c.trackPartialData(data)
+
 	dataVals, convErr := cconv.JsonConverter.ToJson(data.Value())
 	if convErr != nil {
 		return result, convErr
 	}
 
-	query := "UPDATE " + c.QuotedTableName() + " SET data=JSON_PATCH(data,$1) WHERE id=$2 RETURNING *"
+	query := "UPDATE " + c.QuotedTableName() + " SET data=JSON_PATCH(data,$1) WHERE id=$2"
 
 	values := []any{dataVals, id}
 
-	qResult, err := c.IdentifiableSqlitePersistence.Client.QueryContext(ctx, query, values...)
+	if versioned {
+		condition, args, err := c.composeVersionCondition(version, len(values)+1)
+		if err != nil {
+			return result, err
+		}
+		query += " AND " + condition
+		values = append(values, args...)
+	}
+	query += " RETURNING *"
+
+	qResult, err := c.IdentifiableSqlitePersistence.GetClient(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
 	defer qResult.Close()
 
 	if !qResult.Next() {
+		if versioned && qResult.Err() == nil {
+			return result, c.checkVersionConflict(ctx, id)
+		}
 		return result, qResult.Err()
 	}
 
@@ -239,3 +274,27 @@ func (c *IdentifiableJsonSqlitePersistence[T, K]) UpdatePartially(ctx context.Co
 		return result, nil
 	}
 }
+
+// UpdateManyPartially updates the same few selected fields in multiple data items in a single statement.
+// When Versioned option is on the items are updated one by one to check their versions.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- ids               ids of data items to be updated.
+//		- data              a map with fields to be updated.
+//
+// Returns: updated items and errors of every item in the order of the given ids, or error.
+func (c *IdentifiableJsonSqlitePersistence[T, K]) UpdateManyPartially(ctx context.Context,
+	ids []K, data cdata.AnyValueMap) ([]T, []error, error) {
+
+	if c.Versioned && cpersist.IsVersionedType[T]() {
+		return c.updateManyPartiallyByOne(ctx, ids, data, c.UpdatePartially)
+	}
+
+	data = c.trackPartialData(data)
+	dataVals, convErr := cconv.JsonConverter.ToJson(data.Value())
+	if convErr != nil {
+		return nil, nil, convErr
+	}
+	return c.trackUpdateManyPartially(ctx, ids, "data=JSON_PATCH(data,$1)", []any{dataVals})
+}
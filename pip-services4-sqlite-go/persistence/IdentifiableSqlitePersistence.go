@@ -6,9 +6,11 @@ import (
 
 	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
 	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
 	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 
 	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
 )
 
 // IdentifiableSqlitePersistence Abstract persistence component that stores data in SQLite
@@ -22,6 +24,10 @@ import (
 // In complex scenarios child classes can implement additional operations by
 // accessing c._collection and c._model properties.
 //
+// Write operations emit created, updated and deleted change events to listeners
+// added by AddChangeListener. In outbox mode the events are also written to the outbox table
+// in the same transaction as the data.
+//
 //	Configuration parameters
 //		- collection:               (optional) SQLite collection name
 //		- connection(s):
@@ -37,6 +43,8 @@ import (
 //			- connect_timeout:      (optional) number of milliseconds to wait before timing out when connecting a new client (default: 0)
 //			- idle_timeout:         (optional) number of milliseconds a client must sit idle in the pool and not be checked out (default: 10000)
 //			- max_pool_size:        (optional) maximum number of clients the pool should contain (default: 10)
+//			- versioned:            (optional) turns on optimistic concurrency for items that implement IVersioned (default: false)
+//			- outbox:               (optional) write change events to the outbox table in the same transaction as the data (default: false)
 //
 //	References
 //		- *:logger:*:*:1.0           (optional) ILogger components to pass log messages components to pass log messages
@@ -94,6 +102,9 @@ import (
 //		}
 type IdentifiableSqlitePersistence[T any, K any] struct {
 	*SqlitePersistence[T]
+	// Versioned turns on optimistic concurrency. When items implement IVersioned
+	// they are updated only when their version matches the stored one.
+	Versioned bool
 }
 
 // InheritIdentifiableSqlitePersistence creates a new instance of the persistence component.
@@ -113,6 +124,35 @@ func InheritIdentifiableSqlitePersistence[T any, K any](overrides ISqlitePersist
 	return c
 }
 
+// Configure configures component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config configuration parameters to be set.
+func (c *IdentifiableSqlitePersistence[T, K]) Configure(ctx context.Context, config *cconf.ConfigParams) {
+	c.SqlitePersistence.Configure(ctx, config)
+	c.Versioned = config.GetAsBooleanWithDefault("options.versioned", c.Versioned)
+}
+
+// GetPageByFilterWithToken gets a page of data items retrieved by a given filter using keyset paging.
+// The "id" field is added to sort parameters when it is missing to make the order unique.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- filter            (optional) a filter JSON object
+//		- paging            paging parameters with a token from the previous page
+//		- sort              sort parameters
+//		- selection         (optional) projection JSON object, it must include the sort fields
+//		- args              (optional) query parameters referenced by the filter
+//	Returns: data page or error.
+func (c *IdentifiableSqlitePersistence[T, K]) GetPageByFilterWithToken(ctx context.Context,
+	filter string, paging cquery.TokenizedPagingParams, sort cquery.SortParams, selection string,
+	args ...any) (page cquery.TokenizedDataPage[T], err error) {
+
+	return c.SqlitePersistence.GetPageByFilterWithToken(ctx, filter, paging,
+		cpersist.AddKeysetSortField(sort, "id"), selection, args...)
+}
+
 // GetListByIds gets a list of data items retrieved by given unique ids.
 //
 //	Parameters:
@@ -124,9 +164,9 @@ func (c *IdentifiableSqlitePersistence[T, K]) GetListByIds(ctx context.Context,
 
 	ln := len(ids)
 	params := c.GenerateParameters(ln)
-	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE \"id\" IN(" + params + ")"
+	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE " + c.filterDeleted(ctx, "\"id\" IN("+params+")")
 
-	rows, err := c.Client.QueryContext(ctx, query, ItemsToAnySlice(ids)...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, ItemsToAnySlice(ids)...)
 	if err != nil {
 		return nil, err
 	}
@@ -155,6 +195,7 @@ func (c *IdentifiableSqlitePersistence[T, K]) GetListByIds(ctx context.Context,
 }
 
 // GetOneById gets a data item by its unique id.
+// In soft-delete mode deleted items are not returned unless the context is created by cpersist.WithDeleted.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -162,9 +203,9 @@ func (c *IdentifiableSqlitePersistence[T, K]) GetListByIds(ctx context.Context,
 //
 // Returns: data item or error.
 func (c *IdentifiableSqlitePersistence[T, K]) GetOneById(ctx context.Context, id K) (item T, err error) {
-	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE \"id\"=$1"
+	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE " + c.filterDeleted(ctx, "\"id\"=$1")
 
-	qResult, err := c.Client.QueryContext(ctx, query, id)
+	qResult, err := c.GetClient(ctx).QueryContext(ctx, query, id)
 	if err != nil {
 		return item, err
 	}
@@ -195,7 +236,10 @@ func (c *IdentifiableSqlitePersistence[T, K]) Create(ctx context.Context, item T
 	newItem := c.cloneItem(item)
 	newItem = GenerateObjectIdIfNotExists[T](newItem)
 
-	return c.SqlitePersistence.Create(ctx, newItem)
+	return c.trackChange(ctx, cpersist.ChangeTypeCreated, GetObjectId[K](newItem), false,
+		func(ctx context.Context) (T, error) {
+			return c.SqlitePersistence.Create(ctx, newItem)
+		})
 }
 
 // Set a data item. If the data item exists it updates it,
@@ -206,6 +250,14 @@ func (c *IdentifiableSqlitePersistence[T, K]) Create(ctx context.Context, item T
 //		- item              an item to be set.
 //	Returns: (optional)  updated item or error.
 func (c *IdentifiableSqlitePersistence[T, K]) Set(ctx context.Context, item T) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, GetObjectId[K](item), true,
+		func(ctx context.Context) (T, error) {
+			return c.set(ctx, item)
+		})
+}
+
+func (c *IdentifiableSqlitePersistence[T, K]) set(ctx context.Context, item T) (result T, err error) {
+	item = c.trackItem(item, true)
 	objMap, convErr := c.Overrides.ConvertFromPublic(item)
 	if convErr != nil {
 		return result, convErr
@@ -225,7 +277,7 @@ func (c *IdentifiableSqlitePersistence[T, K]) Set(ctx context.Context, item T) (
 		" VALUES (" + paramsStr + ")" +
 		" ON CONFLICT (\"id\") DO UPDATE SET " + setParams + " RETURNING *"
 
-	qResult, err := c.Client.QueryContext(ctx, query, values...)
+	qResult, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
@@ -245,12 +297,30 @@ func (c *IdentifiableSqlitePersistence[T, K]) Set(ctx context.Context, item T) (
 }
 
 // Update a data item.
+// When Versioned option is on and the item implements IVersioned the item is updated
+// only when its version matches the stored one, and the version is incremented.
+// ConflictError is returned when versions do not match.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- item              an item to be updated.
 //	Returns          (optional)  updated item or error.
 func (c *IdentifiableSqlitePersistence[T, K]) Update(ctx context.Context, item T) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, GetObjectId[K](item), true,
+		func(ctx context.Context) (T, error) {
+			return c.update(ctx, item)
+		})
+}
+
+func (c *IdentifiableSqlitePersistence[T, K]) update(ctx context.Context, item T) (result T, err error) {
+	version, versioned := "", false
+	if c.Versioned {
+		if version, versioned = cpersist.GetObjectVersion(item); versioned {
+			item = c.setItemVersion(item, cpersist.NextVersion(version))
+		}
+	}
+	item = c.trackItem(item, false)
+
 	objMap, convErr := c.Overrides.ConvertFromPublic(item)
 	if convErr != nil {
 		return result, convErr
@@ -261,14 +331,27 @@ func (c *IdentifiableSqlitePersistence[T, K]) Update(ctx context.Context, item T
 	values = append(values, id)
 
 	query := "UPDATE " + c.QuotedTableName() +
-		" SET " + paramsStr + " WHERE \"id\"=$" + strconv.FormatInt((int64)(len(values)), 10) + " RETURNING *"
+		" SET " + paramsStr + " WHERE \"id\"=$" + strconv.FormatInt((int64)(len(values)), 10)
 
-	qResult, err := c.Client.QueryContext(ctx, query, values...)
+	if versioned {
+		condition, args, err := c.composeVersionCondition(version, len(values)+1)
+		if err != nil {
+			return result, err
+		}
+		query += " AND " + condition
+		values = append(values, args...)
+	}
+	query += " RETURNING *"
+
+	qResult, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
 	defer qResult.Close()
 	if !qResult.Next() {
+		if versioned && qResult.Err() == nil {
+			return result, c.checkVersionConflict(ctx, id)
+		}
 		return result, qResult.Err()
 	}
 
@@ -283,6 +366,8 @@ func (c *IdentifiableSqlitePersistence[T, K]) Update(ctx context.Context, item T
 }
 
 // UpdatePartially updates only few selected fields in a data item.
+// When Versioned option is on the version is taken from "version" field in the data
+// or from the stored item, and ConflictError is returned when it does not match.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -290,6 +375,23 @@ func (c *IdentifiableSqlitePersistence[T, K]) Update(ctx context.Context, item T
 //		- data              a map with fields to be updated.
 //	Returns: updated item or error.
 func (c *IdentifiableSqlitePersistence[T, K]) UpdatePartially(ctx context.Context, id K, data cdata.AnyValueMap) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updatePartially(ctx, id, data)
+		})
+}
+
+func (c *IdentifiableSqlitePersistence[T, K]) updatePartially(ctx context.Context, id K, data cdata.AnyValueMap) (result T, err error) {
+	version, versioned := "", false
+	if c.Versioned && cpersist.IsVersionedType[T]() {
+		if version, err = c.getPartialVersion(ctx, id, data); err != nil {
+			return result, err
+		}
+		versioned = true
+		data = *cdata.NewAnyValueMapFromMaps(data.Value(), map[string]any{"version": cpersist.NextVersion(version)})
+	}
+	data = c.trackPartialData(data)
+
 	objMap, convErr := c.Overrides.ConvertFromPublicPartial(data.Value())
 	if convErr != nil {
 		return result, convErr
@@ -299,15 +401,28 @@ func (c *IdentifiableSqlitePersistence[T, K]) UpdatePartially(ctx context.Contex
 	values = append(values, id)
 
 	query := "UPDATE " + c.QuotedTableName() +
-		" SET " + paramsStr + " WHERE \"id\"=$" + strconv.FormatInt((int64)(len(values)), 10) + " RETURNING *"
+		" SET " + paramsStr + " WHERE \"id\"=$" + strconv.FormatInt((int64)(len(values)), 10)
+
+	if versioned {
+		condition, args, err := c.composeVersionCondition(version, len(values)+1)
+		if err != nil {
+			return result, err
+		}
+		query += " AND " + condition
+		values = append(values, args...)
+	}
+	query += " RETURNING *"
 
-	qResult, err := c.Client.QueryContext(ctx, query, values...)
+	qResult, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
 	defer qResult.Close()
 
 	if !qResult.Next() {
+		if versioned && qResult.Err() == nil {
+			return result, c.checkVersionConflict(ctx, id)
+		}
 		return result, qResult.Err()
 	}
 
@@ -322,15 +437,36 @@ func (c *IdentifiableSqlitePersistence[T, K]) UpdatePartially(ctx context.Contex
 }
 
 // DeleteById deletes a data item by its unique id.
+// In soft-delete mode the item is marked as deleted instead.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- id                an id of the item to be deleted
 //	Returns: (optional)  deleted item or error.
 func (c *IdentifiableSqlitePersistence[T, K]) DeleteById(ctx context.Context, id K) (result T, err error) {
+	if c.isSoftDelete() {
+		return c.markDeletedById(ctx, id, true)
+	}
+	return c.PurgeById(ctx, id)
+}
+
+// PurgeById physically deletes a data item by its unique id regardless of soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- id                an id of the item to be deleted
+//	Returns: (optional)  deleted item or error.
+func (c *IdentifiableSqlitePersistence[T, K]) PurgeById(ctx context.Context, id K) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeDeleted, id, false,
+		func(ctx context.Context) (T, error) {
+			return c.purgeById(ctx, id)
+		})
+}
+
+func (c *IdentifiableSqlitePersistence[T, K]) purgeById(ctx context.Context, id K) (result T, err error) {
 	query := "DELETE FROM " + c.QuotedTableName() + " WHERE \"id\"=$1 RETURNING *"
 
-	qResult, err := c.Client.QueryContext(ctx, query, id)
+	qResult, err := c.GetClient(ctx).QueryContext(ctx, query, id)
 	if err != nil {
 		return result, err
 	}
@@ -350,7 +486,55 @@ func (c *IdentifiableSqlitePersistence[T, K]) DeleteById(ctx context.Context, id
 	}
 }
 
+// RestoreById restores a data item marked as deleted in soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- id                an id of the item to be restored
+//	Returns: (optional)  restored item or error.
+func (c *IdentifiableSqlitePersistence[T, K]) RestoreById(ctx context.Context, id K) (result T, err error) {
+	return c.markDeletedById(ctx, id, false)
+}
+
+func (c *IdentifiableSqlitePersistence[T, K]) markDeletedById(ctx context.Context, id K, deleted bool) (result T, err error) {
+	changeType := cpersist.ChangeTypeUpdated
+	if deleted {
+		changeType = cpersist.ChangeTypeDeleted
+	}
+	return c.trackChange(ctx, changeType, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updateDeletedById(ctx, id, deleted)
+		})
+}
+
+func (c *IdentifiableSqlitePersistence[T, K]) updateDeletedById(ctx context.Context, id K, deleted bool) (result T, err error) {
+	query := "UPDATE " + c.QuotedTableName() + " SET " + c.composeDeletedSet(deleted) +
+		" WHERE \"id\"=$1 RETURNING *"
+
+	qResult, err := c.GetClient(ctx).QueryContext(ctx, query, id)
+	if err != nil {
+		return result, err
+	}
+	defer qResult.Close()
+
+	if !qResult.Next() {
+		return result, qResult.Err()
+	}
+
+	result, convErr := c.Overrides.ConvertToPublic(qResult)
+	if convErr != nil {
+		return result, convErr
+	}
+	if deleted {
+		c.Logger.Trace(ctx, "Marked as deleted in %s with id = %s", c.TableName, id)
+	} else {
+		c.Logger.Trace(ctx, "Restored in %s with id = %s", c.TableName, id)
+	}
+	return result, nil
+}
+
 // DeleteByIds deletes multiple data items by their unique ids.
+// In soft-delete mode the items are marked as deleted instead.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -361,8 +545,19 @@ func (c *IdentifiableSqlitePersistence[T, K]) DeleteByIds(ctx context.Context, i
 	paramsStr := c.GenerateParameters(ln)
 
 	query := "DELETE FROM " + c.QuotedTableName() + " WHERE \"id\" IN(" + paramsStr + ")"
+	if c.isSoftDelete() {
+		query = "UPDATE " + c.QuotedTableName() + " SET " + c.composeDeletedSet(true) +
+			" WHERE " + c.filterDeleted(ctx, "\"id\" IN("+paramsStr+")")
+	}
+
+	if c.isTrackingChanges() {
+		return c.trackChanges(ctx, cpersist.ChangeTypeDeleted, ids, c.isSoftDelete(),
+			func(ctx context.Context) ([]T, error) {
+				return c.readDeletedItems(ctx, query+" RETURNING *", ItemsToAnySlice(ids)...)
+			})
+	}
 
-	qResult, qErr := c.Client.ExecContext(ctx, query, ItemsToAnySlice(ids)...)
+	qResult, qErr := c.GetClient(ctx).ExecContext(ctx, query, ItemsToAnySlice(ids)...)
 	if qErr != nil {
 		return qErr
 	}
@@ -373,3 +568,64 @@ func (c *IdentifiableSqlitePersistence[T, K]) DeleteByIds(ctx context.Context, i
 	}
 	return err
 }
+
+// readDeletedItems executes a delete query that returns rows and reads the deleted items.
+func (c *IdentifiableSqlitePersistence[T, K]) readDeletedItems(ctx context.Context, query string, values ...any) ([]T, error) {
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]T, 0)
+	for rows.Next() {
+		item, convErr := c.Overrides.ConvertToPublic(rows)
+		if convErr != nil {
+			return items, convErr
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return items, err
+	}
+
+	c.Logger.Trace(ctx, "Deleted %d items from %s", len(items), c.TableName)
+	return items, nil
+}
+
+func (c *IdentifiableSqlitePersistence[T, K]) setItemVersion(item T, version string) T {
+	var obj any = c.cloneItem(item)
+	cpersist.SetObjectVersion(&obj, version)
+	if result, ok := obj.(T); ok {
+		return result
+	}
+	return item
+}
+
+func (c *IdentifiableSqlitePersistence[T, K]) getPartialVersion(ctx context.Context, id K, data cdata.AnyValueMap) (string, error) {
+	if version, ok := data.GetAsNullableString("version"); ok {
+		return version, nil
+	}
+	item, err := c.GetOneById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	version, _ := cpersist.GetObjectVersion(item)
+	return version, nil
+}
+
+func (c *IdentifiableSqlitePersistence[T, K]) composeVersionCondition(version string, startIndex int) (string, []any, error) {
+	return c.FilterCompiler.Compile(cpersist.ComposeVersionFilter(version), startIndex)
+}
+
+func (c *IdentifiableSqlitePersistence[T, K]) checkVersionConflict(ctx context.Context, id any) error {
+	count, err := c.GetCountByFilter(ctx, "\"id\"=$1", id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return cpersist.NewVersionConflictError(cctx.GetTraceId(ctx), id)
+	}
+	c.Logger.Trace(ctx, "Nothing to update in %s with id = %s", c.TableName, id)
+	return nil
+}
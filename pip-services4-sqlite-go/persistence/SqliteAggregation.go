@@ -0,0 +1,66 @@
+package persistence
+
+import (
+	"context"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// GetAggregateByFilter calculates aggregates over data items retrieved by a given filter
+// and grouped by the group fields. The result rows are sorted by the group fields.
+// This method shall be called by a func (c * SqlitePersistence) GetAggregateByFilter method from child class that
+// receives FilterParams and converts them into a filter function.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- filter            (optional) a filter JSON object
+//		- params            group fields and aggregate functions
+//		- args              (optional) query parameters referenced by the filter
+//	Returns: result rows or error.
+func (c *SqlitePersistence[T]) GetAggregateByFilter(ctx context.Context,
+	filter string, params cquery.AggregateParams, args ...any) ([]cquery.AggregateRow, error) {
+
+	columns, groups, err := c.FilterCompiler.CompileAggregate(params)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + columns + " FROM " + c.QuotedTableName()
+	if filter := c.filterDeleted(ctx, filter); len(filter) > 0 {
+		query += " WHERE " + filter
+	}
+	if len(groups) > 0 {
+		query += " GROUP BY " + groups + " ORDER BY " + groups
+	}
+
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]cquery.AggregateRow, 0)
+	for rows.Next() {
+		if c.IsTerminated() {
+			return nil, cerr.
+				NewError("query terminated").
+				WithTraceId(cctx.GetTraceId(ctx))
+		}
+		values := make([]any, len(params.GroupBy)+len(params.Fields))
+		pointers := make([]any, len(values))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		result = append(result, cpersist.ComposeAggregateRow(params, values))
+	}
+
+	c.Logger.Trace(ctx, "Retrieved %d aggregate rows from %s", len(result), c.TableName)
+
+	return result, rows.Err()
+}
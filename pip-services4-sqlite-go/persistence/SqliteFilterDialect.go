@@ -0,0 +1,98 @@
+package persistence
+
+import (
+	"strconv"
+	"strings"
+
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// SqliteFilterDialect defines SQLite syntax to compile FilterExpression into parameterized queries.
+//
+// When JsonColumn is set, fields other than "id" are extracted from the JSON document
+// stored in that column using JSON_EXTRACT function. Extracted numbers are never equal to strings,
+// so fields are cast to NUMERIC when they are compared with numbers in ranges
+// and to TEXT when they are compared with numeric strings.
+type SqliteFilterDialect struct {
+	JsonColumn string
+	// Compares fields of JSON documents by their JSON types to match the sort order
+	typedValues bool
+}
+
+// NewSqliteFilterCompiler creates a filter compiler for plain SQLite tables.
+//
+//	Returns: a new filter compiler.
+func NewSqliteFilterCompiler() *cpersist.SqlFilterCompiler {
+	return cpersist.NewSqlFilterCompiler(&SqliteFilterDialect{})
+}
+
+// NewJsonSqliteFilterCompiler creates a filter compiler for SQLite tables that keep data in JSON column.
+//
+//	Parameters:
+//		- jsonColumn a name of the JSON column.
+//	Returns: a new filter compiler.
+func NewJsonSqliteFilterCompiler(jsonColumn string) *cpersist.SqlFilterCompiler {
+	return cpersist.NewSqlFilterCompiler(&SqliteFilterDialect{JsonColumn: jsonColumn})
+}
+
+// FormatField converts a field name into a quoted column or a JSON path extraction.
+func (c *SqliteFilterDialect) FormatField(field string) string {
+	if c.JsonColumn != "" && field != "id" {
+		return "JSON_EXTRACT(" + c.JsonColumn + ", '$." + field + "')"
+	}
+	return "\"" + strings.ReplaceAll(field, ".", "\".\"") + "\""
+}
+
+// FormatComparison converts a field extracted from JSON document to compare it with FilterParams values.
+// Values of FilterParams are strings, so numeric strings are treated as numbers by range operators
+// and as text by equality and "in" operators.
+func (c *SqliteFilterDialect) FormatComparison(field string, operator cpersist.FilterOperator, values []any) string {
+	column := c.FormatField(field)
+	if c.typedValues || c.JsonColumn == "" || field == "id" || len(values) == 0 {
+		return column
+	}
+
+	if cpersist.IsSqlNumericComparison(operator, values, true) {
+		return "CAST(" + column + " AS NUMERIC)"
+	}
+	if operator != cpersist.FilterEqual && operator != cpersist.FilterNotEqual && operator != cpersist.FilterIn {
+		return column
+	}
+	for _, value := range values {
+		if _, ok := value.(string); !ok || !cpersist.IsSqlNumber(value, true) {
+			return column
+		}
+	}
+	return "CAST(" + column + " AS TEXT)"
+}
+
+// FormatParameter returns a positional parameter placeholder like "$1".
+func (c *SqliteFilterDialect) FormatParameter(index int) string {
+	return "$" + strconv.Itoa(index)
+}
+
+// FormatProjection converts included fields into a SELECT column list.
+// For JSON tables the fields are extracted from the JSON document
+// and composed into a new document with JSON_OBJECT function.
+func (c *SqliteFilterDialect) FormatProjection(fields []*cpersist.ProjectionField) string {
+	if c.JsonColumn == "" {
+		columns := make([]string, len(fields))
+		for i, field := range fields {
+			columns[i] = c.FormatField(field.Name)
+		}
+		return strings.Join(columns, ",")
+	}
+	return c.formatJsonObject(fields) + " AS " + c.JsonColumn
+}
+
+func (c *SqliteFilterDialect) formatJsonObject(fields []*cpersist.ProjectionField) string {
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		value := "JSON_EXTRACT(" + c.JsonColumn + ", '$." + field.Path + "')"
+		if len(field.Children) > 0 {
+			value = c.formatJsonObject(field.Children)
+		}
+		values[i] = "'" + field.Name + "', " + value
+	}
+	return "JSON_OBJECT(" + strings.Join(values, ", ") + ")"
+}
@@ -17,6 +17,7 @@ import (
 	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
 	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
 	clog "github.com/pip-services4/pip-services4-go/pip-services4-observability-go/log"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 	conn "github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/connect"
 )
 
@@ -50,6 +51,12 @@ type ISqlitePersistenceOverrides[T any] interface {
 //			- connect_timeout:      (optional) number of milliseconds to wait before timing out when connecting a new client (default: 0)
 //			- idle_timeout:         (optional) number of milliseconds a client must sit idle in the pool and not be checked out (default: 10000)
 //			- max_pool_size:        (optional) maximum number of clients the pool should contain (default: 10)
+//			- migrations_dir:       (optional) a directory to load migrations from, see cpersist.LoadMigrationsFromDirectory
+//			- migrations_table:     (optional) a table that tracks applied migrations (default: <table>_migrations)
+//			- migrations_dry_run:   (optional) log pending migrations instead of applying them (default: false)
+//			- soft_delete:          (optional) mark items that implement ITrackable as deleted instead of removing them (default: false)
+//			- outbox:               (optional) write change events to the outbox table in the same transaction as the data (default: false)
+//			- outbox_table:         (optional) a table that keeps change events (default: <table>_outbox)
 //
 //	References:
 //		- *:logger:*:*:1.0           (optional) ILogger components to pass log messages
@@ -79,7 +86,7 @@ type ISqlitePersistenceOverrides[T any] interface {
 //	func (c *MySqlitePersistence) GetOneById(ctx context.Context, name string) (item MyData, err error) {
 //		query := "SELECT * FROM " + c.QuotedTableName() + " WHERE \"name\"=$1"
 //
-//		qResult, err := c.Client.QueryContext(ctx, query, name)
+//		qResult, err := c.GetClient(ctx).QueryContext(ctx, query, name)
 //		if err != nil {
 //			return item, err
 //		}
@@ -118,7 +125,7 @@ type ISqlitePersistenceOverrides[T any] interface {
 //			" VALUES (" + paramsStr + ")" +
 //			" ON CONFLICT (\"id\") DO UPDATE SET " + setParams + " RETURNING *"
 //
-//		qResult, err := c.Client.QueryContext(ctx, query, values...)
+//		qResult, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
 //		if err != nil {
 //			return result, err
 //		}
@@ -165,6 +172,23 @@ type SqlitePersistence[T any] struct {
 	//The SQLite table object.
 	TableName   string
 	MaxPageSize int
+	// The compiler of FilterExpression into parameterized SQL conditions.
+	FilterCompiler *cpersist.SqlFilterCompiler
+	// The directory to load migrations from on opening.
+	MigrationsDir string
+	// The name of the table that tracks applied migrations. If not set "<table>_migrations" is used.
+	MigrationsTableName string
+	// Defines if pending migrations are only logged and not applied.
+	MigrationsDryRun bool
+	// Defines if deleted items of ITrackable types are only marked as deleted.
+	SoftDelete bool
+	// Defines if change events are written to the outbox table in the same transaction as the data.
+	Outbox bool
+	// The name of the table that keeps change events. If not set "<table>_outbox" is used.
+	OutboxTableName string
+
+	migrations []*cpersist.Migration
+	changes    *cpersist.ChangeNotifier
 
 	// Defines channel which closed before closing persistence and signals about terminating
 	// all going processes
@@ -195,6 +219,8 @@ func InheritSqlitePersistence[T any](overrides ISqlitePersistenceOverrides[T], t
 		schemaStatements: make([]string, 0),
 		Logger:           clog.NewCompositeLogger(),
 		MaxPageSize:      100,
+		FilterCompiler:   NewSqliteFilterCompiler(),
+		changes:          cpersist.NewChangeNotifier(),
 		TableName:        tableName,
 		isTerminated:     make(chan struct{}),
 		JsonConvertor:    cconv.NewDefaultCustomTypeJsonConvertor[T](),
@@ -221,6 +247,12 @@ func (c *SqlitePersistence[T]) Configure(ctx context.Context, config *cconf.Conf
 	c.TableName = config.GetAsStringWithDefault("collection", c.TableName)
 	c.TableName = config.GetAsStringWithDefault("table", c.TableName)
 	c.MaxPageSize = config.GetAsIntegerWithDefault("options.max_page_size", c.MaxPageSize)
+	c.MigrationsDir = config.GetAsStringWithDefault("options.migrations_dir", c.MigrationsDir)
+	c.MigrationsTableName = config.GetAsStringWithDefault("options.migrations_table", c.MigrationsTableName)
+	c.MigrationsDryRun = config.GetAsBooleanWithDefault("options.migrations_dry_run", c.MigrationsDryRun)
+	c.SoftDelete = config.GetAsBooleanWithDefault("options.soft_delete", c.SoftDelete)
+	c.Outbox = config.GetAsBooleanWithDefault("options.outbox", c.Outbox)
+	c.OutboxTableName = config.GetAsStringWithDefault("options.outbox_table", c.OutboxTableName)
 }
 
 // SetReferences to dependent components.
@@ -416,6 +448,20 @@ func (c *SqlitePersistence[T]) QuotedTableName() string {
 	return c.QuoteIdentifier(c.TableName)
 }
 
+// GetClient gets a client to execute queries. When the context carries a transaction
+// started on the same connection the transaction is returned, otherwise the connection pool is used.
+// Child components shall use it instead of Client to take part in transactions.
+//
+//	Parameters:
+//		- ctx context.Context a context that may contain a transaction.
+//	Returns: a client to execute queries.
+func (c *SqlitePersistence[T]) GetClient(ctx context.Context) conn.ISqliteClient {
+	if tx, ok := conn.GetSqliteTransaction(ctx, c.Client); ok {
+		return tx
+	}
+	return c.Client
+}
+
 // IsOpen checks if the component is opened.
 //
 //	Returns: true if the component has been opened and false otherwise.
@@ -478,8 +524,14 @@ func (c *SqlitePersistence[T]) Open(ctx context.Context) (err error) {
 	// Define database schema
 	c.Overrides.DefineSchema()
 
-	// Recreate objects
+	// Recreate objects and apply pending migrations
 	err = c.CreateSchema(ctx)
+	if err == nil {
+		err = c.Migrate(ctx)
+	}
+	if err == nil {
+		err = c.createOutbox(ctx)
+	}
 	if err != nil {
 		c.Client = nil
 		err = cerr.NewConnectionError(cctx.GetTraceId(ctx), "CONNECT_FAILED", "Connection to sqlite failed"+err.Error()).WithCause(err)
@@ -530,7 +582,7 @@ func (c *SqlitePersistence[T]) Clear(ctx context.Context) error {
 		return errors.New("TABLE NAME IS NOT DEFINED")
 	}
 
-	_, err := c.Client.ExecContext(ctx, "DELETE FROM "+c.QuotedTableName())
+	_, err := c.GetClient(ctx).ExecContext(ctx, "DELETE FROM "+c.QuotedTableName())
 	if err != nil {
 		return cerr.
 			NewConnectionError(cctx.GetTraceId(ctx), "CONNECT_FAILED", "Connection to sqlite failed: "+err.Error()).
@@ -554,7 +606,7 @@ func (c *SqlitePersistence[T]) CreateSchema(ctx context.Context) (err error) {
 	c.Logger.Debug(ctx, "Table "+c.QuotedTableName()+" does not exist. Creating database objects...")
 
 	for _, dml := range c.schemaStatements {
-		_, err := c.Client.ExecContext(ctx, dml)
+		_, err := c.GetClient(ctx).ExecContext(ctx, dml)
 		if err != nil {
 			c.Logger.Error(ctx, err, "Failed to autocreate database object")
 			return err
@@ -565,7 +617,7 @@ func (c *SqlitePersistence[T]) CreateSchema(ctx context.Context) (err error) {
 	// go func() {
 	// 	defer wg.Done()
 	// 	for _, dml := range c.schemaStatements {
-	// 		_, err := c.Client.ExecContext(ctx, dml)
+	// 		_, err := c.GetClient(ctx).ExecContext(ctx, dml)
 	// 		if err != nil {
 	// 			c.Logger.Error(ctx, err, "Failed to autocreate database object")
 	// 		}
@@ -578,7 +630,7 @@ func (c *SqlitePersistence[T]) CreateSchema(ctx context.Context) (err error) {
 func (c *SqlitePersistence[T]) checkTableExists(ctx context.Context) (bool, error) {
 	// Check if table exist to determine either to auto create objects
 	query := "SELECT * FROM '" + c.TableName + "' LIMIT 1"
-	_, qErr := c.Client.ExecContext(ctx, query)
+	_, qErr := c.GetClient(ctx).ExecContext(ctx, query)
 	if qErr != nil {
 		if !strings.Contains(qErr.Error(), "no such table") {
 			return false, qErr
@@ -673,6 +725,17 @@ func (c *SqlitePersistence[T]) GenerateColumnsAndValues(objMap map[string]any) (
 	return columns, values
 }
 
+// CompileFilter converts a filter expression into a parameterized condition
+// that can be passed to GetPageByFilter, GetListByFilter, GetCountByFilter and other methods
+// together with the returned query parameters.
+//
+//	Parameters:
+//		- filter a filter expression, usually created by cpersist.NewFilterExpressionFromParams
+//	Returns: condition like "\"key\"=$1", query parameters or error if filter is invalid.
+func (c *SqlitePersistence[T]) CompileFilter(filter *cpersist.FilterExpression) (string, []any, error) {
+	return c.FilterCompiler.Compile(filter, 1)
+}
+
 // GetPageByFilter gets a page of data items retrieved by a given filter and sorted according to sort parameters.
 // This method shall be called by a func (c * SqlitePersistence) getPageByFilter method from child class that
 // receives FilterParams and converts them into a filter function.
@@ -683,9 +746,10 @@ func (c *SqlitePersistence[T]) GenerateColumnsAndValues(objMap map[string]any) (
 //		- paging            (optional) paging parameters
 //		- sort              (optional) sorting JSON object
 //		- select            (optional) projection JSON object
+//		- args              (optional) query parameters referenced by the filter
 //	Returns: receives a data page or error.
 func (c *SqlitePersistence[T]) GetPageByFilter(ctx context.Context,
-	filter string, paging cquery.PagingParams, sort string, selection string) (page cquery.DataPage[T], err error) {
+	filter string, paging cquery.PagingParams, sort string, selection string, args ...any) (page cquery.DataPage[T], err error) {
 
 	query := "SELECT * FROM " + c.QuotedTableName()
 	if len(selection) > 0 {
@@ -697,7 +761,7 @@ func (c *SqlitePersistence[T]) GetPageByFilter(ctx context.Context,
 	take := paging.GetTake((int64)(c.MaxPageSize))
 	pagingEnabled := paging.Total
 
-	if len(filter) > 0 {
+	if filter := c.filterDeleted(ctx, filter); len(filter) > 0 {
 		query += " WHERE " + filter
 	}
 	if len(sort) > 0 {
@@ -710,7 +774,7 @@ func (c *SqlitePersistence[T]) GetPageByFilter(ctx context.Context,
 		query += " OFFSET " + strconv.FormatInt(skip, 10)
 	}
 
-	rows, err := c.Client.QueryContext(ctx, query)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return *cquery.NewEmptyDataPage[T](), err
 	}
@@ -736,7 +800,7 @@ func (c *SqlitePersistence[T]) GetPageByFilter(ctx context.Context,
 	}
 
 	if pagingEnabled {
-		count, err := c.GetCountByFilter(ctx, filter)
+		count, err := c.GetCountByFilter(ctx, filter, args...)
 		if err != nil {
 			return *cquery.NewEmptyDataPage[T](), err
 		}
@@ -747,6 +811,116 @@ func (c *SqlitePersistence[T]) GetPageByFilter(ctx context.Context,
 	return *cquery.NewDataPage(items, cquery.EmptyTotalValue), rows.Err()
 }
 
+// GetPageByFilterWithToken gets a page of data items retrieved by a given filter and sorted
+// according to sort parameters using keyset paging. Instead of skipping rows the query
+// seeks right after the row encoded in the paging token, so it takes the same time for any page.
+// The returned page contains a token to get the next page, it is empty when there are no more items.
+// This method shall be called by a func (c * SqlitePersistence) GetPageByFilterWithToken method from child class that
+// receives FilterParams and converts them into a filter function.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- filter            (optional) a filter JSON object
+//		- paging            paging parameters with a token from the previous page
+//		- sort              sort parameters, the last field must be unique
+//		- selection         (optional) projection JSON object, it must include the sort fields
+//		- args              (optional) query parameters referenced by the filter
+//	Returns: data page or error.
+func (c *SqlitePersistence[T]) GetPageByFilterWithToken(ctx context.Context,
+	filter string, paging cquery.TokenizedPagingParams, sort cquery.SortParams, selection string,
+	args ...any) (page cquery.TokenizedDataPage[T], err error) {
+
+	keyset, err := cpersist.ComposeKeysetFilter(paging.Token, sort)
+	if err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+	seek, seekArgs, err := c.compileKeysetFilter(keyset, len(args)+1)
+	if err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+	orderBy, err := c.FilterCompiler.CompileSort(sort)
+	if err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+
+	query := "SELECT * FROM " + c.QuotedTableName()
+	if len(selection) > 0 {
+		query = "SELECT " + selection + " FROM " + c.QuotedTableName()
+	}
+
+	take := paging.GetTake((int64)(c.MaxPageSize))
+
+	filter = c.filterDeleted(ctx, filter)
+	if len(filter) > 0 && len(seek) > 0 {
+		query += " WHERE (" + filter + ") AND " + seek
+	} else if len(filter) > 0 {
+		query += " WHERE " + filter
+	} else if len(seek) > 0 {
+		query += " WHERE " + seek
+	}
+	query += " ORDER BY " + orderBy
+
+	// Read one extra row to find out if there is a next page
+	query += " LIMIT " + strconv.FormatInt(take+1, 10)
+
+	queryArgs := append(append(make([]any, 0, len(args)+len(seekArgs)), args...), seekArgs...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+	defer rows.Close()
+
+	items := make([]T, 0)
+	for rows.Next() {
+		if c.IsTerminated() {
+			rows.Close()
+			return *cquery.NewEmptyTokenizedDataPage[T](), cerr.
+				NewError("query terminated").
+				WithTraceId(cctx.GetTraceId(ctx))
+		}
+		item, convErr := c.Overrides.ConvertToPublic(rows)
+		if convErr != nil {
+			return page, convErr
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return *cquery.NewEmptyTokenizedDataPage[T](), err
+	}
+
+	c.Logger.Trace(ctx, "Retrieved %d from %s", len(items), c.TableName)
+
+	token := cquery.EmptyTokenValue
+	if (int64)(len(items)) > take {
+		items = items[:take]
+		token, err = c.composeKeysetToken(items[len(items)-1], sort)
+		if err != nil {
+			return *cquery.NewEmptyTokenizedDataPage[T](), err
+		}
+	}
+
+	return *cquery.NewTokenizedDataPage[T](token, items), nil
+}
+
+// Fields of JSON documents are compared by their JSON types to match the order created by CompileSort
+func (c *SqlitePersistence[T]) compileKeysetFilter(filter *cpersist.FilterExpression, startIndex int) (string, []any, error) {
+	dialect, ok := c.FilterCompiler.Dialect.(*SqliteFilterDialect)
+	if !ok || dialect.JsonColumn == "" {
+		return c.FilterCompiler.Compile(filter, startIndex)
+	}
+
+	typedDialect := &SqliteFilterDialect{JsonColumn: dialect.JsonColumn, typedValues: true}
+	return cpersist.NewSqlFilterCompiler(typedDialect).Compile(filter, startIndex)
+}
+
+func (c *SqlitePersistence[T]) composeKeysetToken(item T, sort cquery.SortParams) (string, error) {
+	buf, err := c.JsonConvertor.ToJson(item)
+	if err != nil {
+		return cquery.EmptyTokenValue, err
+	}
+	return cpersist.ComposeKeysetTokenFromJson(buf, sort)
+}
+
 // GetCountByFilter gets a number of data items retrieved by a given filter.
 // This method shall be called by a func (c * SqlitePersistence) getCountByFilter method from child class that
 // receives FilterParams and converts them into a filter function.
@@ -754,16 +928,18 @@ func (c *SqlitePersistence[T]) GetPageByFilter(ctx context.Context,
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- filter            (optional) a filter JSON object
+//		- args              (optional) query parameters referenced by the filter
 //	Returns: data page or error.
 func (c *SqlitePersistence[T]) GetCountByFilter(ctx context.Context,
-	filter string) (int64, error) {
+	filter string, args ...any) (int64, error) {
 
 	query := "SELECT COUNT(*) AS count FROM " + c.QuotedTableName()
+	filter = c.filterDeleted(ctx, filter)
 	if len(filter) > 0 {
 		query += " WHERE " + filter
 	}
 
-	queryRes, err := c.Client.QueryContext(ctx, query)
+	queryRes, err := c.GetClient(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -797,9 +973,10 @@ func (c *SqlitePersistence[T]) GetCountByFilter(ctx context.Context,
 //		- paging           (optional) paging parameters
 //		- sort             (optional) sorting JSON object
 //		- select           (optional) projection JSON object
+//		- args             (optional) query parameters referenced by the filter
 //	Returns: data list or error.
 func (c *SqlitePersistence[T]) GetListByFilter(ctx context.Context,
-	filter string, sort string, selection string) (items []T, err error) {
+	filter string, sort string, selection string, args ...any) (items []T, err error) {
 
 	query := "SELECT * FROM " + c.QuotedTableName()
 
@@ -807,6 +984,7 @@ func (c *SqlitePersistence[T]) GetListByFilter(ctx context.Context,
 		query = "SELECT " + selection + " FROM " + c.QuotedTableName()
 	}
 
+	filter = c.filterDeleted(ctx, filter)
 	if len(filter) > 0 {
 		query += " WHERE " + filter
 	}
@@ -815,7 +993,7 @@ func (c *SqlitePersistence[T]) GetListByFilter(ctx context.Context,
 		query += " ORDER BY " + sort
 	}
 
-	rows, err := c.Client.QueryContext(ctx, query)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -850,9 +1028,10 @@ func (c *SqlitePersistence[T]) GetListByFilter(ctx context.Context,
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- filter            (optional) a filter JSON object
+//		- args              (optional) query parameters referenced by the filter
 //	Returns: random item or error.
-func (c *SqlitePersistence[T]) GetOneRandom(ctx context.Context, filter string) (item T, err error) {
-	count, err := c.GetCountByFilter(ctx, filter)
+func (c *SqlitePersistence[T]) GetOneRandom(ctx context.Context, filter string, args ...any) (item T, err error) {
+	count, err := c.GetCountByFilter(ctx, filter, args...)
 	if err != nil {
 		return item, err
 	}
@@ -871,12 +1050,12 @@ func (c *SqlitePersistence[T]) GetOneRandom(ctx context.Context, filter string)
 
 	// build query
 	query := "SELECT * FROM " + c.QuotedTableName()
-	if len(filter) > 0 {
+	if filter := c.filterDeleted(ctx, filter); len(filter) > 0 {
 		query += " WHERE " + filter
 	}
 	query += " LIMIT 1" + " OFFSET " + strconv.FormatInt(pos, 10)
 
-	rows, err := c.Client.QueryContext(ctx, query)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return item, err
 	}
@@ -903,6 +1082,7 @@ func (c *SqlitePersistence[T]) GetOneRandom(ctx context.Context, filter string)
 //		- item              an item to be created.
 //	Returns: (optional) callback function that receives created item or error.
 func (c *SqlitePersistence[T]) Create(ctx context.Context, item T) (result T, err error) {
+	item = c.trackItem(item, true)
 	objMap, convErr := c.Overrides.ConvertFromPublic(item)
 	if convErr != nil {
 		return result, convErr
@@ -915,7 +1095,7 @@ func (c *SqlitePersistence[T]) Create(ctx context.Context, item T) (result T, er
 	query := "INSERT INTO " + c.QuotedTableName() +
 		" (" + columnsStr + ") VALUES (" + paramsStr + ") RETURNING *"
 
-	rows, err := c.Client.QueryContext(ctx, query, values...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
@@ -935,20 +1115,26 @@ func (c *SqlitePersistence[T]) Create(ctx context.Context, item T) (result T, er
 }
 
 // DeleteByFilter deletes data items that match to a given filter.
+// In soft-delete mode the items are marked as deleted instead.
 // This method shall be called by a func (c * SqlitePersistence) deleteByFilter method from child class that
 // receives FilterParams and converts them into a filter function.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- filter            (optional) a filter JSON object.
+//		- args              (optional) query parameters referenced by the filter
 //	Returns: error or nil for success.
-func (c *SqlitePersistence[T]) DeleteByFilter(ctx context.Context, filter string) error {
+func (c *SqlitePersistence[T]) DeleteByFilter(ctx context.Context, filter string, args ...any) error {
 	query := "DELETE FROM " + c.QuotedTableName()
+	if c.isSoftDelete() {
+		query = "UPDATE " + c.QuotedTableName() + " SET " + c.composeDeletedSet(true)
+		filter = c.filterDeleted(ctx, filter)
+	}
 	if len(filter) > 0 {
 		query += " WHERE " + filter
 	}
 
-	qResult, qErr := c.Client.ExecContext(ctx, query)
+	qResult, qErr := c.GetClient(ctx).ExecContext(ctx, query, args...)
 	if qErr != nil {
 		return qErr
 	}
@@ -0,0 +1,93 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// PurgeDeleted physically deletes data items marked as deleted in soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: error or nil for success.
+func (c *SqlitePersistence[T]) PurgeDeleted(ctx context.Context) error {
+	query := "DELETE FROM " + c.QuotedTableName() + " WHERE NOT " + c.composeNotDeletedCondition()
+
+	qResult, err := c.GetClient(ctx).ExecContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	count, err := qResult.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Trace(ctx, "Purged %d deleted items from %s", count, c.TableName)
+	return nil
+}
+
+func (c *SqlitePersistence[T]) isSoftDelete() bool {
+	return c.SoftDelete && cpersist.IsTrackableType[T]()
+}
+
+// filterDeleted adds exclusion of logically deleted rows to the filter.
+func (c *SqlitePersistence[T]) filterDeleted(ctx context.Context, filter string) string {
+	if !c.isSoftDelete() || cpersist.IsDeletedIncluded(ctx) {
+		return filter
+	}
+	if len(filter) == 0 {
+		return c.composeNotDeletedCondition()
+	}
+	return "(" + filter + ") AND " + c.composeNotDeletedCondition()
+}
+
+func (c *SqlitePersistence[T]) composeNotDeletedCondition() string {
+	// SQLite keeps booleans as integers, both in columns and JSON_EXTRACT results
+	return "IFNULL(" + c.FilterCompiler.Dialect.FormatField("deleted") + ", 0)=0"
+}
+
+// composeDeletedSet generates SET clause that marks rows as deleted or restores them.
+// The values are generated here, so they are safe to be inlined without parameters.
+func (c *SqlitePersistence[T]) composeDeletedSet(deleted bool) string {
+	changeTime := "'" + time.Now().UTC().Format(time.RFC3339Nano) + "'"
+
+	if dialect, ok := c.FilterCompiler.Dialect.(*SqliteFilterDialect); ok && dialect.JsonColumn != "" {
+		flag := "JSON('false')"
+		if deleted {
+			flag = "JSON('true')"
+		}
+		return dialect.JsonColumn + "=JSON_SET(" + dialect.JsonColumn +
+			", '$.deleted', " + flag + ", '$.change_time', " + changeTime + ")"
+	}
+
+	flag := "0"
+	if deleted {
+		flag = "1"
+	}
+	return "\"deleted\"=" + flag + ", \"change_time\"=" + changeTime
+}
+
+// trackItem stamps create and change times in soft-delete mode.
+func (c *SqlitePersistence[T]) trackItem(item T, created bool) T {
+	if !c.SoftDelete {
+		return item
+	}
+	var obj any = c.cloneItem(item)
+	cpersist.TrackObjectChange(&obj, created, time.Now().UTC())
+	if result, ok := obj.(T); ok {
+		return result
+	}
+	return item
+}
+
+// trackPartialData adds change time to partially updated fields in soft-delete mode.
+func (c *SqlitePersistence[T]) trackPartialData(data cdata.AnyValueMap) cdata.AnyValueMap {
+	if !c.SoftDelete || !cpersist.IsChangeableType[T]() {
+		return data
+	}
+	return *cdata.NewAnyValueMapFromMaps(data.Value(), map[string]any{"change_time": time.Now().UTC()})
+}
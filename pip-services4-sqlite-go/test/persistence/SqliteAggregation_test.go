@@ -0,0 +1,111 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	"github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/test/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregationSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	)
+
+	persistence := NewDummySqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	_, _, err = persistence.CreateMany(context.Background(), []fixtures.Dummy{
+		{Id: "1", Key: "Key 1", Content: "B"},
+		{Id: "2", Key: "Key 2", Content: "A"},
+		{Id: "3", Key: "Key 3", Content: "B"},
+		{Id: "4", Key: "Key 4", Content: "C"},
+	})
+	assert.Nil(t, err)
+
+	rows, err := persistence.GetAggregateByFilter(context.Background(), "\"content\"<>$1",
+		*cquery.NewAggregateParams([]string{"content"},
+			cquery.NewCountAggregateField("count"),
+			cquery.NewAggregateField("first", cquery.AggregateMin, "key"),
+			cquery.NewAggregateField("last", cquery.AggregateMax, "key"),
+		), "C")
+	assert.Nil(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "A", rows[0].GetGroupValue("content"))
+	assert.Equal(t, int64(1), rows[0].GetValue("count"))
+	assert.Equal(t, "B", rows[1].GetGroupValue("content"))
+	assert.Equal(t, int64(2), rows[1].GetValue("count"))
+	assert.Equal(t, "Key 1", rows[1].GetValue("first"))
+	assert.Equal(t, "Key 3", rows[1].GetValue("last"))
+
+	_, err = persistence.GetAggregateByFilter(context.Background(), "",
+		*cquery.NewAggregateParams([]string{"content;DELETE FROM dummies"}, cquery.NewCountAggregateField("count")))
+	assert.NotNil(t, err)
+}
+
+func TestAggregationJsonSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	)
+
+	persistence := newProjectionJsonSqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	for _, item := range []map[string]any{
+		{"id": "1", "type": "B", "order": map[string]any{"amount": 10}},
+		{"id": "2", "type": "A", "order": map[string]any{"amount": 5}},
+		{"id": "3", "type": "B", "order": map[string]any{"amount": 20}},
+		{"id": "4", "type": "A", "order": map[string]any{"amount": 2.5}},
+	} {
+		_, err = persistence.Create(context.Background(), item)
+		assert.Nil(t, err)
+	}
+
+	params := cquery.NewAggregateParams([]string{"type"},
+		cquery.NewAggregateField("total", cquery.AggregateSum, "order.amount"),
+		cquery.NewAggregateField("average", cquery.AggregateAvg, "order.amount"),
+	)
+	rows, err := persistence.GetAggregateByFilter(context.Background(), "", *params)
+	assert.Nil(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "A", rows[0].GetGroupValue("type"))
+	assert.Equal(t, 7.5, rows[0].GetValue("total"))
+	assert.Equal(t, 3.75, rows[0].GetValue("average"))
+	assert.Equal(t, "B", rows[1].GetGroupValue("type"))
+	assert.Equal(t, 30.0, rows[1].GetValue("total"))
+
+	// Without groups all items are aggregated into one row
+	rows, err = persistence.GetAggregateByFilter(context.Background(), "",
+		*cquery.NewAggregateParams(nil, cquery.NewCountAggregateField("count")))
+	assert.Nil(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, int64(4), rows[0].GetValue("count"))
+}
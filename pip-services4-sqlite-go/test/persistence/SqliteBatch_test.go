@@ -0,0 +1,193 @@
+package test
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	"github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/write"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/persistence"
+	"github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/test/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+type BatchDefaultsSqlitePersistence struct {
+	*persist.IdentifiableSqlitePersistence[map[string]any, string]
+}
+
+func NewBatchDefaultsSqlitePersistence() *BatchDefaultsSqlitePersistence {
+	c := &BatchDefaultsSqlitePersistence{}
+	c.IdentifiableSqlitePersistence = persist.InheritIdentifiableSqlitePersistence[map[string]any, string](c, "batch_defaults")
+	return c
+}
+
+func (c *BatchDefaultsSqlitePersistence) DefineSchema() {
+	c.ClearSchema()
+	c.IdentifiableSqlitePersistence.DefineSchema()
+	c.EnsureSchema("CREATE TABLE " + c.QuotedTableName() +
+		" (\"id\" TEXT PRIMARY KEY, \"key\" TEXT, \"content\" TEXT DEFAULT 'Default')")
+}
+
+type batchPersistence interface {
+	write.IBatchWriter[fixtures.Dummy]
+	write.IBatchSetter[fixtures.Dummy]
+	write.IBatchPartialUpdater[fixtures.Dummy, string]
+	GetListByIds(ctx context.Context, ids []string) ([]fixtures.Dummy, error)
+}
+
+func testBatchWrites(t *testing.T, persistence batchPersistence) {
+	// The number of items exceeds a single statement
+	items := make([]fixtures.Dummy, 400)
+	for i := range items {
+		items[i] = fixtures.Dummy{Id: strconv.Itoa(i), Key: "Key " + strconv.Itoa(i), Content: "Content"}
+	}
+	items[1].Id = ""
+
+	created, errs, err := persistence.CreateMany(context.Background(), items)
+	assert.Nil(t, err)
+	assert.Len(t, errs, 400)
+	assert.Len(t, created, 400)
+	assert.Equal(t, "0", created[0].Id)
+	assert.NotEqual(t, "", created[1].Id)
+	assert.Equal(t, "Key 399", created[399].Key)
+
+	// Duplicates fail the entire statement
+	_, _, err = persistence.CreateMany(context.Background(), []fixtures.Dummy{{Id: "0", Key: "Key 0"}})
+	assert.NotNil(t, err)
+
+	// A failed statement rolls back the statements written before it
+	items = make([]fixtures.Dummy, 400)
+	for i := range items {
+		items[i] = fixtures.Dummy{Id: "batch " + strconv.Itoa(i), Key: "Batch key " + strconv.Itoa(i), Content: "Content"}
+	}
+	items[399].Id = "0"
+	created, _, err = persistence.CreateMany(context.Background(), items)
+	assert.NotNil(t, err)
+	assert.Equal(t, "", created[0].Id)
+
+	list, err := persistence.GetListByIds(context.Background(), []string{"batch 0", "batch 398"})
+	assert.Nil(t, err)
+	assert.Len(t, list, 0)
+
+	set, errs, err := persistence.UpsertMany(context.Background(), []fixtures.Dummy{
+		{Id: "0", Key: "Key A", Content: "Content A"},
+		{Id: "new", Key: "Key B", Content: "Content B"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Equal(t, "Key A", set[0].Key)
+	assert.Equal(t, "new", set[1].Id)
+
+	// Repeated ids are reported for the items that repeat them
+	set, errs, err = persistence.UpsertMany(context.Background(), []fixtures.Dummy{
+		{Id: "dup", Key: "Key D1", Content: "Content D1"},
+		{Id: "dup", Key: "Key D2", Content: "Content D2"},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, errs[0])
+	assert.NotNil(t, errs[1])
+	assert.Equal(t, "DUPLICATE_ID", errs[1].(*cerr.ApplicationError).Code)
+	assert.Equal(t, "Key D1", set[0].Key)
+	assert.Equal(t, "", set[1].Id)
+
+	updated, errs, err := persistence.UpdateManyPartially(context.Background(), []string{"2", "missing", "new"},
+		*cdata.NewAnyValueMapFromTuples("content", "Updated"))
+	assert.Nil(t, err)
+	assert.Len(t, errs, 3)
+	assert.Equal(t, "Updated", updated[0].Content)
+	assert.Equal(t, "Key 2", updated[0].Key)
+	assert.Equal(t, "", updated[1].Id)
+	assert.Equal(t, "Updated", updated[2].Content)
+
+	list, err = persistence.GetListByIds(context.Background(), []string{"0", "2", "new"})
+	assert.Nil(t, err)
+	assert.Len(t, list, 3)
+}
+
+func TestBatchSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	)
+
+	persistence := NewDummySqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	testBatchWrites(t, persistence)
+}
+
+func TestBatchJsonSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	)
+
+	persistence := NewDummyJsonSqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	testBatchWrites(t, persistence)
+}
+
+func TestBatchSqlitePersistenceColumnDefaults(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	persistence := NewBatchDefaultsSqlitePersistence()
+	persistence.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	))
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	// Missing columns get their default values
+	created, errs, err := persistence.CreateMany(context.Background(), []map[string]any{
+		{"id": "1", "key": "Key 1"},
+		{"id": "2", "key": "Key 2", "content": "Content 2"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Equal(t, "Default", created[0]["content"])
+	assert.Equal(t, "Content 2", created[1]["content"])
+
+	// Missing columns keep their values on update
+	set, errs, err := persistence.UpsertMany(context.Background(), []map[string]any{
+		{"id": "2", "key": "Key 3"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []error{nil}, errs)
+	assert.Equal(t, "Key 3", set[0]["key"])
+	assert.Equal(t, "Content 2", set[0]["content"])
+}
@@ -0,0 +1,187 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/write"
+	"github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/test/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+type changeRecorder struct {
+	events []*cpersist.ChangeEvent
+}
+
+func (c *changeRecorder) OnChange(ctx context.Context, event *cpersist.ChangeEvent) error {
+	c.events = append(c.events, event)
+	return nil
+}
+
+type changesPersistence interface {
+	fixtures.IDummyPersistence
+	write.IBatchWriter[fixtures.Dummy]
+	AddChangeListener(listener cpersist.IChangeListener)
+	RemoveChangeListener(listener cpersist.IChangeListener)
+	QuotedOutboxTableName() string
+	DrainOutbox(ctx context.Context, maxCount int,
+		handler func(ctx context.Context, entries []*cpersist.OutboxEntry) error) (int, error)
+}
+
+func testChangeEvents(t *testing.T, persistence changesPersistence, client *sql.DB) {
+	_, err := client.Exec("DELETE FROM " + persistence.QuotedOutboxTableName())
+	assert.Nil(t, err)
+
+	countOutbox := func() int {
+		var count int
+		row := client.QueryRow("SELECT COUNT(*) FROM " + persistence.QuotedOutboxTableName())
+		assert.Nil(t, row.Scan(&count))
+		return count
+	}
+
+	listener := &changeRecorder{}
+	persistence.AddChangeListener(listener)
+	defer persistence.RemoveChangeListener(listener)
+
+	_, err = persistence.Create(context.Background(), fixtures.Dummy{Id: "1", Key: "Key 1", Content: "Content 1"})
+	assert.Nil(t, err)
+	_, err = persistence.Update(context.Background(), fixtures.Dummy{Id: "1", Key: "Key 1", Content: "Content 2"})
+	assert.Nil(t, err)
+	_, err = persistence.UpdatePartially(context.Background(), "1", *cdata.NewAnyValueMapFromTuples("content", "Content 3"))
+	assert.Nil(t, err)
+	_, err = persistence.DeleteById(context.Background(), "1")
+	assert.Nil(t, err)
+
+	// Missing items do not produce events
+	_, err = persistence.DeleteById(context.Background(), "1")
+	assert.Nil(t, err)
+
+	assert.Len(t, listener.events, 4)
+	assert.Equal(t, 4, countOutbox())
+
+	event := listener.events[0]
+	assert.Equal(t, cpersist.ChangeTypeCreated, event.Type)
+	assert.Equal(t, "1", event.Id)
+	assert.Nil(t, event.Before)
+	assert.Equal(t, "Content 1", event.After.(fixtures.Dummy).Content)
+
+	event = listener.events[2]
+	assert.Equal(t, cpersist.ChangeTypeUpdated, event.Type)
+	assert.Equal(t, "Content 2", event.Before.(fixtures.Dummy).Content)
+	assert.Equal(t, "Content 3", event.After.(fixtures.Dummy).Content)
+
+	event = listener.events[3]
+	assert.Equal(t, cpersist.ChangeTypeDeleted, event.Type)
+	assert.Equal(t, "Content 3", event.Before.(fixtures.Dummy).Content)
+	assert.Nil(t, event.After)
+
+	var changeType, itemId, after string
+	row := client.QueryRow("SELECT \"type\", \"item_id\", \"after\" FROM " +
+		persistence.QuotedOutboxTableName() + " ORDER BY \"id\" LIMIT 1")
+	assert.Nil(t, row.Scan(&changeType, &itemId, &after))
+	assert.Equal(t, cpersist.ChangeTypeCreated, changeType)
+	assert.Equal(t, "1", itemId)
+	assert.Contains(t, after, "Content 1")
+
+	// Failed writes do not leave events in the outbox
+	_, _, err = persistence.CreateMany(context.Background(), []fixtures.Dummy{
+		{Id: "2", Key: "Key 2", Content: "Content 2"},
+		{Id: "3", Key: "Key 3", Content: "Content 3"},
+	})
+	assert.Nil(t, err)
+	_, err = persistence.Create(context.Background(), fixtures.Dummy{Id: "2", Key: "Key 4"})
+	assert.NotNil(t, err)
+	assert.Len(t, listener.events, 6)
+	assert.Equal(t, 6, countOutbox())
+
+	err = persistence.DeleteByIds(context.Background(), []string{"2", "3"})
+	assert.Nil(t, err)
+	assert.Len(t, listener.events, 8)
+	assert.Equal(t, 8, countOutbox())
+	assert.Equal(t, cpersist.ChangeTypeDeleted, listener.events[7].Type)
+	assert.NotNil(t, listener.events[7].Before)
+
+	// Failed handlers keep events in the outbox
+	_, err = persistence.DrainOutbox(context.Background(), 5, func(ctx context.Context, entries []*cpersist.OutboxEntry) error {
+		return errors.New("failed")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 8, countOutbox())
+
+	drained := make([]*cpersist.OutboxEntry, 0)
+	drain := func(ctx context.Context, entries []*cpersist.OutboxEntry) error {
+		drained = append(drained, entries...)
+		return nil
+	}
+	count, err := persistence.DrainOutbox(context.Background(), 5, drain)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, count)
+	count, err = persistence.DrainOutbox(context.Background(), 5, drain)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 0, countOutbox())
+
+	assert.Len(t, drained, 8)
+	event = drained[0].Event
+	assert.Equal(t, cpersist.ChangeTypeCreated, event.Type)
+	assert.Equal(t, "1", event.Id)
+	assert.Nil(t, event.Before)
+	assert.Equal(t, "Content 1", event.After.(fixtures.Dummy).Content)
+	assert.False(t, event.Time.IsZero())
+	assert.Nil(t, drained[3].Event.After)
+	assert.NotEqual(t, drained[0].Id, drained[1].Id)
+}
+
+func TestChangesSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+		"options.outbox", true,
+	)
+
+	persistence := NewDummySqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	testChangeEvents(t, persistence, persistence.Client)
+}
+
+func TestChangesJsonSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+		"options.outbox", true,
+	)
+
+	persistence := NewDummyJsonSqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	testChangeEvents(t, persistence, persistence.Client)
+}
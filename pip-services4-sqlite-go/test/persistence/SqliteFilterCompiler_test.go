@@ -0,0 +1,141 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	"github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/test/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqliteFilterCompiler(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	)
+
+	persistence := NewDummySqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	for _, item := range []fixtures.Dummy{
+		{Id: "1", Key: "Key 1", Content: "Content A"},
+		{Id: "2", Key: "Key 2", Content: "Content B"},
+		{Id: "3", Key: "Key 3", Content: "Other"},
+	} {
+		_, err = persistence.Create(context.Background(), item)
+		assert.Nil(t, err)
+	}
+
+	query := func(filter cquery.FilterParams) []fixtures.Dummy {
+		expr, err := cpersist.NewFilterExpressionFromParams(filter, "id", "key", "content")
+		assert.Nil(t, err)
+		where, args, err := persistence.CompileFilter(expr)
+		assert.Nil(t, err)
+
+		page, err := persistence.IdentifiableSqlitePersistence.GetPageByFilter(context.Background(),
+			where, *cquery.NewPagingParams(0, 10, true), "\"id\"", "", args...)
+		assert.Nil(t, err)
+		assert.Equal(t, len(page.Data), page.Total)
+		return page.Data
+	}
+
+	items := query(*cquery.NewFilterParamsFromTuples("key", "Key 1"))
+	assert.Len(t, items, 1)
+	assert.Equal(t, "1", items[0].Id)
+
+	items = query(*cquery.NewFilterParamsFromTuples("key__ne", "Key 1"))
+	assert.Len(t, items, 2)
+
+	items = query(*cquery.NewFilterParamsFromTuples("id__in", "1,3"))
+	assert.Len(t, items, 2)
+	assert.Equal(t, "3", items[1].Id)
+
+	items = query(*cquery.NewFilterParamsFromTuples("content__like", "Content%", "id__gt", "1"))
+	assert.Len(t, items, 1)
+	assert.Equal(t, "2", items[0].Id)
+
+	items = query(*cquery.NewFilterParamsFromTuples("id__between", "2,3", "content__is_null", false))
+	assert.Len(t, items, 2)
+
+	items = query(*cquery.NewFilterParamsFromTuples("or.key", "Key 3", "or.content", "Content A"))
+	assert.Len(t, items, 2)
+	assert.Equal(t, "1", items[0].Id)
+	assert.Equal(t, "3", items[1].Id)
+
+	// Values are passed as parameters and never interpreted as SQL
+	items = query(*cquery.NewFilterParamsFromTuples("key", "' OR '1'='1"))
+	assert.Len(t, items, 0)
+
+	_, err = cpersist.NewFilterExpressionFromParams(*cquery.NewFilterParamsFromTuples("secret", "1"), "id", "key")
+	assert.NotNil(t, err)
+
+	expr := cpersist.NewFilterCondition("key", cpersist.FilterEqual, "Key 2")
+	where, args, err := persistence.CompileFilter(expr)
+	assert.Nil(t, err)
+	count, err := persistence.IdentifiableSqlitePersistence.GetCountByFilter(context.Background(), where, args...)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	err = persistence.IdentifiableSqlitePersistence.DeleteByFilter(context.Background(), where, args...)
+	assert.Nil(t, err)
+	count, err = persistence.IdentifiableSqlitePersistence.GetCountByFilter(context.Background(), "")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestJsonSqliteFilterCompiler(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	)
+
+	persistence := NewDummyJsonSqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	for _, item := range []fixtures.Dummy{
+		{Id: "1", Key: "Key 1", Content: "Content A"},
+		{Id: "2", Key: "Key 2", Content: "Content B"},
+	} {
+		_, err = persistence.Create(context.Background(), item)
+		assert.Nil(t, err)
+	}
+
+	expr, err := cpersist.NewFilterExpressionFromParams(*cquery.NewFilterParamsFromTuples(
+		"key__in", "Key 2,Key 3",
+	))
+	assert.Nil(t, err)
+	where, args, err := persistence.CompileFilter(expr)
+	assert.Nil(t, err)
+	assert.Equal(t, "JSON_EXTRACT(data, '$.key') IN ($1,$2)", where)
+
+	items, err := persistence.IdentifiableJsonSqlitePersistence.GetListByFilter(context.Background(), where, "", "", args...)
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "2", items[0].Id)
+}
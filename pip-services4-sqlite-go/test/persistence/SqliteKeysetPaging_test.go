@@ -0,0 +1,145 @@
+package test
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	"github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/test/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqliteGetPageByFilterWithToken(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	)
+
+	persistence := NewDummySqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	for i := 1; i <= 25; i++ {
+		_, err = persistence.Create(context.Background(), fixtures.Dummy{
+			Id:      strconv.Itoa(100 + i),
+			Key:     "Key " + strconv.Itoa(i),
+			Content: "Content " + strconv.Itoa(i%3),
+		})
+		assert.Nil(t, err)
+	}
+
+	sort := *cquery.NewSortParams([]cquery.SortField{cquery.NewSortField("content", false)})
+
+	ids := make(map[string]bool)
+	var last *fixtures.Dummy
+	token := ""
+	pages := 0
+	for {
+		page, err := persistence.IdentifiableSqlitePersistence.GetPageByFilterWithToken(context.Background(),
+			"\"key\"<>$1", *cquery.NewTokenizedPagingParams(token, 10, false), sort, "", "Key 7")
+		assert.Nil(t, err)
+		pages++
+
+		for _, item := range page.Data {
+			assert.False(t, ids[item.Id])
+			ids[item.Id] = true
+			if last != nil {
+				assert.True(t, last.Content > item.Content || last.Content == item.Content && last.Id < item.Id)
+			}
+			item := item
+			last = &item
+		}
+
+		if page.Token == "" {
+			break
+		}
+		token = page.Token
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Len(t, ids, 24)
+	assert.False(t, ids["107"])
+
+	_, err = persistence.IdentifiableSqlitePersistence.GetPageByFilterWithToken(context.Background(),
+		"", *cquery.NewTokenizedPagingParams("invalid token", 10, false), sort, "")
+	assert.NotNil(t, err)
+}
+
+func TestSqliteGetPageByFilterWithTokenNulls(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	persistence := NewDummyMapSqlitePersistence()
+	persistence.Configure(context.Background(), cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	))
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	for i := 1; i <= 10; i++ {
+		var content any
+		if i%2 == 0 {
+			content = "Content " + strconv.Itoa(i%3)
+		}
+		_, err = persistence.Create(context.Background(), map[string]any{
+			"id":      strconv.Itoa(100 + i),
+			"key":     "Key " + strconv.Itoa(i),
+			"content": content,
+		})
+		assert.Nil(t, err)
+	}
+
+	for _, ascending := range []bool{true, false} {
+		sort := *cquery.NewSortParams([]cquery.SortField{cquery.NewSortField("content", ascending)})
+
+		ids := make(map[string]bool)
+		contents := make([]any, 0)
+		token := ""
+		for {
+			page, err := persistence.IdentifiableSqlitePersistence.GetPageByFilterWithToken(context.Background(),
+				"", *cquery.NewTokenizedPagingParams(token, 3, false), sort, "")
+			assert.Nil(t, err)
+
+			for _, item := range page.Data {
+				assert.False(t, ids[item["id"].(string)])
+				ids[item["id"].(string)] = true
+				contents = append(contents, item["content"])
+			}
+
+			if page.Token == "" {
+				break
+			}
+			token = page.Token
+		}
+
+		assert.Len(t, ids, 10)
+		// Nulls are the smallest values
+		if ascending {
+			assert.Nil(t, contents[0])
+			assert.NotNil(t, contents[9])
+		} else {
+			assert.NotNil(t, contents[0])
+			assert.Nil(t, contents[9])
+		}
+	}
+}
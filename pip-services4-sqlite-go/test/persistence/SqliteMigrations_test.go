@@ -0,0 +1,129 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	conn "github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/connect"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/persistence"
+	"github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/test/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+type migratedSqlitePersistence struct {
+	*persist.IdentifiableSqlitePersistence[fixtures.Dummy, string]
+}
+
+func newMigratedSqlitePersistence() *migratedSqlitePersistence {
+	c := &migratedSqlitePersistence{}
+	c.IdentifiableSqlitePersistence = persist.InheritIdentifiableSqlitePersistence[fixtures.Dummy, string](c, "migrated_dummies")
+	c.AddMigration(1, "create table",
+		"CREATE TABLE \"migrated_dummies\" (\"id\" TEXT PRIMARY KEY, \"key\" TEXT)",
+		"DROP TABLE \"migrated_dummies\"")
+	c.AddMigration(2, "add content",
+		"ALTER TABLE \"migrated_dummies\" ADD COLUMN \"content\" TEXT",
+		"ALTER TABLE \"migrated_dummies\" DROP COLUMN \"content\"")
+	return c
+}
+
+func (c *migratedSqlitePersistence) DefineSchema() {
+	// The schema is created by migrations
+}
+
+func TestSqliteMigrations(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	)
+
+	connection := conn.NewSqliteConnection()
+	connection.Configure(context.Background(), dbConfig)
+	err := connection.Open(context.Background())
+	assert.Nil(t, err)
+	defer connection.Close(context.Background())
+
+	for _, table := range []string{"migrated_dummies", "migrated_dummies_migrations"} {
+		_, err = connection.GetConnection().Exec("DROP TABLE IF EXISTS \"" + table + "\"")
+		assert.Nil(t, err)
+	}
+
+	versions := func() []int64 {
+		rows, err := connection.GetConnection().Query("SELECT \"version\" FROM \"migrated_dummies_migrations\" ORDER BY \"version\"")
+		assert.Nil(t, err)
+		defer rows.Close()
+		result := make([]int64, 0)
+		for rows.Next() {
+			var version int64
+			assert.Nil(t, rows.Scan(&version))
+			result = append(result, version)
+		}
+		return result
+	}
+
+	persistence := newMigratedSqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err = persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+	assert.Equal(t, []int64{1, 2}, versions())
+
+	_, err = persistence.Create(context.Background(), fixtures.Dummy{Id: "1", Key: "Key 1", Content: "Content 1"})
+	assert.Nil(t, err)
+
+	// Applied migrations are skipped
+	err = persistence.Migrate(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2}, versions())
+
+	err = persistence.MigrateTo(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1}, versions())
+	_, err = persistence.Create(context.Background(), fixtures.Dummy{Id: "2", Key: "Key 2", Content: "Content 2"})
+	assert.NotNil(t, err)
+
+	// Dry run does not change the database
+	persistence.MigrationsDryRun = true
+	err = persistence.Migrate(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1}, versions())
+
+	persistence.MigrationsDryRun = false
+	err = persistence.Migrate(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2}, versions())
+
+	item, err := persistence.GetOneById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "Key 1", item.Key)
+
+	// Failed migrations are rolled back
+	broken := newMigratedSqlitePersistence()
+	broken.Configure(context.Background(), dbConfig)
+	broken.AddMigration(3, "broken",
+		"CREATE INDEX \"migrated_dummies_key\" ON \"migrated_dummies\" (\"key\"); SELECT FROM", "")
+	err = broken.Open(context.Background())
+	assert.NotNil(t, err)
+	assert.Equal(t, []int64{1, 2}, versions())
+
+	// Migrations without down scripts can not be reverted
+	irreversible := newMigratedSqlitePersistence()
+	irreversible.Configure(context.Background(), dbConfig)
+	irreversible.AddMigrations(cpersist.NewMigration(3, "add index",
+		"CREATE INDEX \"migrated_dummies_key\" ON \"migrated_dummies\" (\"key\")", ""))
+	err = irreversible.Open(context.Background())
+	assert.Nil(t, err)
+	defer irreversible.Close(context.Background())
+	assert.Equal(t, []int64{1, 2, 3}, versions())
+
+	err = irreversible.MigrateTo(context.Background(), 2)
+	assert.NotNil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, versions())
+}
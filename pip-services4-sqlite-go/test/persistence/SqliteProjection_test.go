@@ -0,0 +1,108 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/persistence"
+	"github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/test/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+type projectionJsonSqlitePersistence struct {
+	*persist.IdentifiableJsonSqlitePersistence[map[string]any, string]
+}
+
+func newProjectionJsonSqlitePersistence() *projectionJsonSqlitePersistence {
+	c := &projectionJsonSqlitePersistence{}
+	c.IdentifiableJsonSqlitePersistence = persist.InheritIdentifiableJsonSqlitePersistence[map[string]any, string](c, "projection_json")
+	return c
+}
+
+func (c *projectionJsonSqlitePersistence) DefineSchema() {
+	c.ClearSchema()
+	c.EnsureTable("", "")
+}
+
+func TestProjectionSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	)
+
+	persistence := NewDummySqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	_, err = persistence.Create(context.Background(), fixtures.Dummy{Id: "1", Key: "Key 1", Content: "Content 1"})
+	assert.Nil(t, err)
+
+	page, err := persistence.GetPageByFilterWithProjection(context.Background(), "", *cquery.NewEmptyPagingParams(),
+		"", cquery.NewProjectionParamsFromValue("id,key"))
+	assert.Nil(t, err)
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, fixtures.Dummy{Id: "1", Key: "Key 1"}, page.Data[0])
+
+	_, err = persistence.GetListByFilterWithProjection(context.Background(), "", "",
+		cquery.NewProjectionParamsFromValue("id,key;DELETE FROM dummies"))
+	assert.NotNil(t, err)
+}
+
+func TestProjectionJsonSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	)
+
+	persistence := newProjectionJsonSqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	_, err = persistence.Create(context.Background(), map[string]any{
+		"id":   "1",
+		"key":  "Key 1",
+		"tags": []any{"a", "b"},
+		"content": map[string]any{
+			"text":   "Content 1",
+			"size":   10,
+			"author": map[string]any{"name": "John", "email": "john@example.com"},
+		},
+	})
+	assert.Nil(t, err)
+
+	items, err := persistence.GetListByFilterWithProjection(context.Background(), "", "",
+		cquery.NewProjectionParamsFromValue("id,tags,content(size,author(name))"))
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, map[string]any{
+		"id":   "1",
+		"tags": []any{"a", "b"},
+		"content": map[string]any{
+			"size":   float64(10),
+			"author": map[string]any{"name": "John"},
+		},
+	}, items[0])
+}
@@ -0,0 +1,216 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+type TrackedDummy struct {
+	Id         string    `json:"id"`
+	Key        string    `json:"key"`
+	CreateTime time.Time `json:"create_time"`
+	ChangeTime time.Time `json:"change_time"`
+	Deleted    bool      `json:"deleted"`
+}
+
+func (c TrackedDummy) GetCreateTime() time.Time {
+	return c.CreateTime
+}
+
+func (c TrackedDummy) GetChangeTime() time.Time {
+	return c.ChangeTime
+}
+
+func (c TrackedDummy) GetDeleted() bool {
+	return c.Deleted
+}
+
+type TrackedSqlitePersistence struct {
+	*persist.IdentifiableSqlitePersistence[TrackedDummy, string]
+}
+
+func NewTrackedSqlitePersistence() *TrackedSqlitePersistence {
+	c := &TrackedSqlitePersistence{}
+	c.IdentifiableSqlitePersistence = persist.InheritIdentifiableSqlitePersistence[TrackedDummy, string](c, "tracked_dummies")
+	return c
+}
+
+func (c *TrackedSqlitePersistence) DefineSchema() {
+	c.ClearSchema()
+	c.IdentifiableSqlitePersistence.DefineSchema()
+	c.EnsureSchema("CREATE TABLE " + c.QuotedTableName() +
+		" (\"id\" TEXT PRIMARY KEY, \"key\" TEXT, \"create_time\" TEXT, \"change_time\" TEXT, \"deleted\" BOOLEAN)")
+}
+
+func (c *TrackedSqlitePersistence) GetPageByFilter(ctx context.Context, paging cquery.PagingParams) (cquery.DataPage[TrackedDummy], error) {
+	return c.IdentifiableSqlitePersistence.GetPageByFilter(ctx, "", paging, "", "")
+}
+
+func (c *TrackedSqlitePersistence) DeleteByFilter(ctx context.Context, key string) error {
+	return c.IdentifiableSqlitePersistence.DeleteByFilter(ctx, "\"key\"=$1", key)
+}
+
+type TrackedJsonSqlitePersistence struct {
+	*persist.IdentifiableJsonSqlitePersistence[TrackedDummy, string]
+}
+
+func NewTrackedJsonSqlitePersistence() *TrackedJsonSqlitePersistence {
+	c := &TrackedJsonSqlitePersistence{}
+	c.IdentifiableJsonSqlitePersistence = persist.InheritIdentifiableJsonSqlitePersistence[TrackedDummy, string](c, "tracked_dummies_json")
+	return c
+}
+
+func (c *TrackedJsonSqlitePersistence) DefineSchema() {
+	c.ClearSchema()
+	c.IdentifiableJsonSqlitePersistence.DefineSchema()
+	c.EnsureTable("", "")
+}
+
+func (c *TrackedJsonSqlitePersistence) GetPageByFilter(ctx context.Context, paging cquery.PagingParams) (cquery.DataPage[TrackedDummy], error) {
+	return c.IdentifiableJsonSqlitePersistence.GetPageByFilter(ctx, "", paging, "", "")
+}
+
+func (c *TrackedJsonSqlitePersistence) DeleteByFilter(ctx context.Context, key string) error {
+	return c.IdentifiableJsonSqlitePersistence.DeleteByFilter(ctx, "JSON_EXTRACT(data, '$.key')=$1", key)
+}
+
+type trackedPersistence interface {
+	GetPageByFilter(ctx context.Context, paging cquery.PagingParams) (cquery.DataPage[TrackedDummy], error)
+	GetListByIds(ctx context.Context, ids []string) ([]TrackedDummy, error)
+	GetOneById(ctx context.Context, id string) (TrackedDummy, error)
+	Create(ctx context.Context, item TrackedDummy) (TrackedDummy, error)
+	UpdatePartially(ctx context.Context, id string, data cdata.AnyValueMap) (TrackedDummy, error)
+	DeleteById(ctx context.Context, id string) (TrackedDummy, error)
+	DeleteByIds(ctx context.Context, ids []string) error
+	DeleteByFilter(ctx context.Context, key string) error
+	RestoreById(ctx context.Context, id string) (TrackedDummy, error)
+	PurgeById(ctx context.Context, id string) (TrackedDummy, error)
+	PurgeDeleted(ctx context.Context) error
+}
+
+func testSoftDelete(t *testing.T, persistence trackedPersistence) {
+	item1, err := persistence.Create(context.Background(), TrackedDummy{Id: "1", Key: "Key 1"})
+	assert.Nil(t, err)
+	assert.False(t, item1.CreateTime.IsZero())
+	assert.False(t, item1.ChangeTime.IsZero())
+
+	for _, id := range []string{"2", "3", "4"} {
+		_, err = persistence.Create(context.Background(), TrackedDummy{Id: id, Key: "Key " + id})
+		assert.Nil(t, err)
+	}
+
+	item, err := persistence.UpdatePartially(context.Background(), "1", *cdata.NewAnyValueMapFromTuples("key", "Key 5"))
+	assert.Nil(t, err)
+	assert.Equal(t, "Key 5", item.Key)
+	assert.True(t, item1.CreateTime.Equal(item.CreateTime))
+	assert.False(t, item.ChangeTime.Before(item1.ChangeTime))
+
+	// Deleted items are marked and excluded from reads
+	item, err = persistence.DeleteById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.True(t, item.Deleted)
+
+	err = persistence.DeleteByIds(context.Background(), []string{"2"})
+	assert.Nil(t, err)
+	err = persistence.DeleteByFilter(context.Background(), "Key 3")
+	assert.Nil(t, err)
+
+	item, err = persistence.GetOneById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "", item.Id)
+
+	page, err := persistence.GetPageByFilter(context.Background(), *cquery.NewPagingParams(0, 10, true))
+	assert.Nil(t, err)
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, 1, page.Total)
+
+	items, err := persistence.GetListByIds(context.Background(), []string{"1", "2", "3", "4"})
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+
+	// Deleted items can be read explicitly
+	item, err = persistence.GetOneById(cpersist.WithDeleted(context.Background()), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", item.Id)
+	assert.True(t, item.Deleted)
+
+	page, err = persistence.GetPageByFilter(cpersist.WithDeleted(context.Background()), *cquery.NewPagingParams(0, 10, true))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, page.Total)
+
+	// Restore and purge
+	item, err = persistence.RestoreById(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.False(t, item.Deleted)
+	assert.Equal(t, "Key 5", item.Key)
+
+	err = persistence.PurgeDeleted(context.Background())
+	assert.Nil(t, err)
+	items, err = persistence.GetListByIds(cpersist.WithDeleted(context.Background()), []string{"1", "2", "3", "4"})
+	assert.Nil(t, err)
+	assert.Len(t, items, 2)
+
+	_, err = persistence.PurgeById(context.Background(), "4")
+	assert.Nil(t, err)
+	items, err = persistence.GetListByIds(cpersist.WithDeleted(context.Background()), []string{"1", "2", "3", "4"})
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+}
+
+func TestSoftDeleteSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+		"options.soft_delete", true,
+	)
+
+	persistence := NewTrackedSqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+	assert.True(t, persistence.SoftDelete)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	testSoftDelete(t, persistence)
+}
+
+func TestSoftDeleteJsonSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+		"options.soft_delete", true,
+	)
+
+	persistence := NewTrackedJsonSqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	testSoftDelete(t, persistence)
+}
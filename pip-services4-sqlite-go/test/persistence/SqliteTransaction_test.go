@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	cref "github.com/pip-services4/pip-services4-go/pip-services4-components-go/refer"
+	conn "github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/connect"
+	"github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/test/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqliteTransaction(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+	)
+
+	connection := conn.NewSqliteConnection()
+	connection.Configure(context.Background(), dbConfig)
+
+	// Both persistences share the same connection component
+	descr := cref.NewDescriptor("pip-services", "connection", "sqlite", "default", "1.0")
+	references := cref.NewReferencesFromTuples(context.Background(), descr, connection)
+
+	persistence := NewDummySqlitePersistence()
+	persistence.SetReferences(context.Background(), references)
+	jsonPersistence := NewDummyJsonSqlitePersistence()
+	jsonPersistence.SetReferences(context.Background(), references)
+
+	err := connection.Open(context.Background())
+	assert.Nil(t, err)
+	defer connection.Close(context.Background())
+
+	err = persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = jsonPersistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer jsonPersistence.Close(context.Background())
+
+	clear := func() {
+		assert.Nil(t, persistence.Clear(context.Background()))
+		assert.Nil(t, jsonPersistence.Clear(context.Background()))
+	}
+
+	t.Run("Commit", func(t *testing.T) {
+		clear()
+
+		err := connection.ExecuteInTransaction(context.Background(), func(ctx context.Context) error {
+			if _, err := persistence.Create(ctx, fixtures.Dummy{Id: "1", Key: "Key 1", Content: "Order"}); err != nil {
+				return err
+			}
+			_, err := jsonPersistence.Create(ctx, fixtures.Dummy{Id: "1", Key: "Key 1", Content: "Line"})
+			return err
+		})
+		assert.Nil(t, err)
+
+		item, err := persistence.GetOneById(context.Background(), "1")
+		assert.Nil(t, err)
+		assert.Equal(t, "Order", item.Content)
+		item, err = jsonPersistence.GetOneById(context.Background(), "1")
+		assert.Nil(t, err)
+		assert.Equal(t, "Line", item.Content)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		clear()
+
+		failure := errors.New("failure")
+		err := connection.ExecuteInTransaction(context.Background(), func(ctx context.Context) error {
+			if _, err := persistence.Create(ctx, fixtures.Dummy{Id: "1", Key: "Key 1", Content: "Order"}); err != nil {
+				return err
+			}
+			if _, err := jsonPersistence.Create(ctx, fixtures.Dummy{Id: "1", Key: "Key 1", Content: "Line"}); err != nil {
+				return err
+			}
+			return failure
+		})
+		assert.Equal(t, failure, err)
+
+		count, err := persistence.IdentifiableSqlitePersistence.GetCountByFilter(context.Background(), "")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), count)
+		count, err = jsonPersistence.IdentifiableJsonSqlitePersistence.GetCountByFilter(context.Background(), "")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		clear()
+
+		ctx, tx, err := connection.BeginTransaction(context.Background())
+		assert.Nil(t, err)
+		assert.False(t, tx.IsNested())
+		defer tx.Rollback(ctx)
+
+		_, err = persistence.Create(ctx, fixtures.Dummy{Id: "1", Key: "Key 1", Content: "Order"})
+		assert.Nil(t, err)
+
+		nestedCtx, nestedTx, err := connection.BeginTransaction(ctx)
+		assert.Nil(t, err)
+		assert.True(t, nestedTx.IsNested())
+
+		_, err = jsonPersistence.Create(nestedCtx, fixtures.Dummy{Id: "1", Key: "Key 1", Content: "Line"})
+		assert.Nil(t, err)
+		count, err := jsonPersistence.IdentifiableJsonSqlitePersistence.GetCountByFilter(nestedCtx, "")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+
+		assert.Nil(t, nestedTx.Rollback(nestedCtx))
+		assert.Nil(t, tx.Commit(ctx))
+		assert.NotNil(t, tx.Commit(ctx))
+
+		count, err = persistence.IdentifiableSqlitePersistence.GetCountByFilter(context.Background(), "")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+		count, err = jsonPersistence.IdentifiableJsonSqlitePersistence.GetCountByFilter(context.Background(), "")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+}
@@ -0,0 +1,146 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cconf "github.com/pip-services4/pip-services4-go/pip-services4-components-go/config"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-sqlite-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+type VersionedDummy struct {
+	Id      string `json:"id"`
+	Key     string `json:"key"`
+	Version string `json:"version"`
+}
+
+func (c VersionedDummy) GetVersion() string {
+	return c.Version
+}
+
+type VersionedSqlitePersistence struct {
+	*persist.IdentifiableSqlitePersistence[VersionedDummy, string]
+}
+
+func NewVersionedSqlitePersistence() *VersionedSqlitePersistence {
+	c := &VersionedSqlitePersistence{}
+	c.IdentifiableSqlitePersistence = persist.InheritIdentifiableSqlitePersistence[VersionedDummy, string](c, "versioned_dummies")
+	return c
+}
+
+func (c *VersionedSqlitePersistence) DefineSchema() {
+	c.ClearSchema()
+	c.IdentifiableSqlitePersistence.DefineSchema()
+	c.EnsureSchema("CREATE TABLE " + c.QuotedTableName() + " (\"id\" TEXT PRIMARY KEY, \"key\" TEXT, \"version\" TEXT)")
+}
+
+type VersionedJsonSqlitePersistence struct {
+	*persist.IdentifiableJsonSqlitePersistence[VersionedDummy, string]
+}
+
+func NewVersionedJsonSqlitePersistence() *VersionedJsonSqlitePersistence {
+	c := &VersionedJsonSqlitePersistence{}
+	c.IdentifiableJsonSqlitePersistence = persist.InheritIdentifiableJsonSqlitePersistence[VersionedDummy, string](c, "versioned_dummies_json")
+	return c
+}
+
+func (c *VersionedJsonSqlitePersistence) DefineSchema() {
+	c.ClearSchema()
+	c.IdentifiableJsonSqlitePersistence.DefineSchema()
+	c.EnsureTable("", "")
+}
+
+type versionedPersistence interface {
+	Create(ctx context.Context, item VersionedDummy) (VersionedDummy, error)
+	Update(ctx context.Context, item VersionedDummy) (VersionedDummy, error)
+	UpdatePartially(ctx context.Context, id string, data cdata.AnyValueMap) (VersionedDummy, error)
+}
+
+func testVersionedUpdates(t *testing.T, persistence versionedPersistence) {
+	item, err := persistence.Create(context.Background(), VersionedDummy{Id: "1", Key: "Key 1"})
+	assert.Nil(t, err)
+
+	item.Key = "Key 2"
+	item, err = persistence.Update(context.Background(), item)
+	assert.Nil(t, err)
+	assert.Equal(t, "Key 2", item.Key)
+	assert.Equal(t, "1", item.Version)
+
+	_, err = persistence.Update(context.Background(), VersionedDummy{Id: "1", Key: "Key 3", Version: "0"})
+	assert.NotNil(t, err)
+	assert.Equal(t, cerr.Conflict, err.(*cerr.ApplicationError).Category)
+
+	item, err = persistence.UpdatePartially(context.Background(), "1",
+		*cdata.NewAnyValueMapFromTuples("key", "Key 4", "version", "1"))
+	assert.Nil(t, err)
+	assert.Equal(t, "Key 4", item.Key)
+	assert.Equal(t, "2", item.Version)
+
+	_, err = persistence.UpdatePartially(context.Background(), "1",
+		*cdata.NewAnyValueMapFromTuples("key", "Key 5", "version", "1"))
+	assert.NotNil(t, err)
+	assert.Equal(t, cerr.Conflict, err.(*cerr.ApplicationError).Category)
+
+	item, err = persistence.UpdatePartially(context.Background(), "1",
+		*cdata.NewAnyValueMapFromTuples("key", "Key 6"))
+	assert.Nil(t, err)
+	assert.Equal(t, "Key 6", item.Key)
+	assert.Equal(t, "3", item.Version)
+
+	// Missing items are not reported as conflicts
+	_, err = persistence.Update(context.Background(), VersionedDummy{Id: "2", Key: "Key 7"})
+	assert.Nil(t, err)
+}
+
+func TestVersionedSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+		"options.versioned", true,
+	)
+
+	persistence := NewVersionedSqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+	assert.True(t, persistence.Versioned)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	testVersionedUpdates(t, persistence)
+}
+
+func TestVersionedJsonSqlitePersistence(t *testing.T) {
+	sqliteDatabase := os.Getenv("SQLITE_DB")
+	if sqliteDatabase == "" {
+		sqliteDatabase = "../../data/test.db"
+	}
+
+	dbConfig := cconf.NewConfigParamsFromTuples(
+		"connection.database", sqliteDatabase,
+		"options.versioned", true,
+	)
+
+	persistence := NewVersionedJsonSqlitePersistence()
+	persistence.Configure(context.Background(), dbConfig)
+
+	err := persistence.Open(context.Background())
+	assert.Nil(t, err)
+	defer persistence.Close(context.Background())
+
+	err = persistence.Clear(context.Background())
+	assert.Nil(t, err)
+
+	testVersionedUpdates(t, persistence)
+}
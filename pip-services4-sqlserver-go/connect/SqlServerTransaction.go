@@ -0,0 +1,186 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	cerror "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+)
+
+// ISqlServerClient is a common interface of *sql.DB and *sql.Tx
+// used by persistence components to execute queries.
+type ISqlServerClient interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type sqlServerTransactionKey struct {
+	db *sql.DB
+}
+
+// SqlServerTransaction is a unit of work started on SQL Server connection and carried in context.Context.
+// Persistence components that share the same connection pick up the transaction from the context
+// and execute their queries within it. Transactions started on a context that already contains
+// a transaction for the same connection are nested and implemented by savepoints.
+//
+// Transactions are not safe for concurrent use, the context shall not be shared between goroutines.
+//
+//	Example:
+//		ctx, tx, err := connection.BeginTransaction(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		defer tx.Rollback(ctx)
+//
+//		order, err = ordersPersistence.Create(ctx, order)
+//		...
+//		lines, err = linesPersistence.Create(ctx, line)
+//		...
+//		return tx.Commit(ctx)
+type SqlServerTransaction struct {
+	tx        *sql.Tx
+	savepoint string
+	level     int
+	completed bool
+}
+
+// BeginTransaction starts a new transaction and returns a context that carries it.
+// If the context already has a transaction started on this connection
+// a nested transaction is created using a savepoint.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: context.Context with the transaction, the transaction and error or nil when no errors occurred.
+func (c *SqlServerConnection) BeginTransaction(ctx context.Context) (context.Context, *SqlServerTransaction, error) {
+	if c.Connection == nil {
+		return ctx, nil, cerror.NewInvalidStateError(cctx.GetTraceId(ctx), "NOT_OPENED", "Connection is not opened")
+	}
+	key := sqlServerTransactionKey{db: c.Connection}
+
+	if parent, ok := ctx.Value(key).(*SqlServerTransaction); ok && !parent.completed {
+		transaction := &SqlServerTransaction{
+			tx:    parent.tx,
+			level: parent.level + 1,
+		}
+		transaction.savepoint = "sp_" + strconv.Itoa(transaction.level)
+		if _, err := parent.tx.ExecContext(ctx, "SAVE TRANSACTION "+transaction.savepoint); err != nil {
+			return ctx, nil, cerror.NewConnectionError(cctx.GetTraceId(ctx), "TRANSACTION_FAILED",
+				"Failed to create savepoint").WithCause(err)
+		}
+		return context.WithValue(ctx, key, transaction), transaction, nil
+	}
+
+	tx, err := c.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return ctx, nil, cerror.NewConnectionError(cctx.GetTraceId(ctx), "TRANSACTION_FAILED",
+			"Failed to begin transaction").WithCause(err)
+	}
+	transaction := &SqlServerTransaction{tx: tx}
+	return context.WithValue(ctx, key, transaction), transaction, nil
+}
+
+// ExecuteInTransaction runs a function within a transaction. The transaction is committed
+// when the function succeeds and rolled back when it returns an error or panics.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- action func(ctx context.Context) error a function that receives context with the transaction.
+//	Returns: error returned by the function or by the transaction, or nil when no errors occurred.
+func (c *SqlServerConnection) ExecuteInTransaction(ctx context.Context, action func(ctx context.Context) error) (err error) {
+	txCtx, transaction, err := c.BeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			_ = transaction.Rollback(txCtx)
+			panic(r)
+		}
+	}()
+
+	if err = action(txCtx); err != nil {
+		_ = transaction.Rollback(txCtx)
+		return err
+	}
+	return transaction.Commit(txCtx)
+}
+
+// GetSqlServerTransaction gets a transaction started on the connection from the context.
+//
+//	Parameters:
+//		- ctx context.Context a context that may contain a transaction.
+//		- db *sql.DB a connection the transaction was started on.
+//	Returns: *sql.Tx the transaction and true, or nil and false when there is no transaction.
+func GetSqlServerTransaction(ctx context.Context, db *sql.DB) (*sql.Tx, bool) {
+	if ctx == nil || db == nil {
+		return nil, false
+	}
+	if transaction, ok := ctx.Value(sqlServerTransactionKey{db: db}).(*SqlServerTransaction); ok && !transaction.completed {
+		return transaction.tx, true
+	}
+	return nil, false
+}
+
+// Tx gets the underlying database transaction.
+//
+//	Returns: *sql.Tx
+func (c *SqlServerTransaction) Tx() *sql.Tx {
+	return c.tx
+}
+
+// IsNested checks if the transaction is nested into another one.
+//
+//	Returns: true if the transaction is implemented by a savepoint and false otherwise.
+func (c *SqlServerTransaction) IsNested() bool {
+	return c.savepoint != ""
+}
+
+// Commit commits the transaction. SQL Server does not release savepoints,
+// so committing a nested transaction only completes it and its changes are committed with the parent one.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: error or nil when no errors occurred.
+func (c *SqlServerTransaction) Commit(ctx context.Context) error {
+	if c.completed {
+		return cerror.NewInvalidStateError(cctx.GetTraceId(ctx), "TRANSACTION_COMPLETED", "Transaction is already completed")
+	}
+	c.completed = true
+
+	if c.IsNested() {
+		return nil
+	}
+	if err := c.tx.Commit(); err != nil {
+		return cerror.NewConnectionError(cctx.GetTraceId(ctx), "TRANSACTION_FAILED",
+			"Failed to commit transaction").WithCause(err)
+	}
+	return nil
+}
+
+// Rollback rolls back the transaction or rolls back to the savepoint of a nested transaction.
+// It does nothing when the transaction was already committed or rolled back, so it can be deferred.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: error or nil when no errors occurred.
+func (c *SqlServerTransaction) Rollback(ctx context.Context) error {
+	if c.completed {
+		return nil
+	}
+	c.completed = true
+
+	var err error
+	if c.IsNested() {
+		_, err = c.tx.ExecContext(ctx, "ROLLBACK TRANSACTION "+c.savepoint)
+	} else {
+		err = c.tx.Rollback()
+	}
+	if err != nil {
+		return cerror.NewConnectionError(cctx.GetTraceId(ctx), "TRANSACTION_FAILED",
+			"Failed to rollback transaction").WithCause(err)
+	}
+	return nil
+}
@@ -4,11 +4,11 @@ go 1.20
 
 require (
 	github.com/microsoft/go-mssqldb v1.3.0
-	github.com/pip-services4/pip-services4-go/pip-services4-commons-go v0.0.0-20230719170734-6e7b58414323
-	github.com/pip-services4/pip-services4-go/pip-services4-components-go v0.0.0-20230719170734-6e7b58414323
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go v0.0.1-2
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go v0.0.1-2
 	github.com/pip-services4/pip-services4-go/pip-services4-config-go v0.0.0-20230719170734-6e7b58414323
-	github.com/pip-services4/pip-services4-go/pip-services4-data-go v0.0.0-20230719170734-6e7b58414323
-	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.0-20230719170734-6e7b58414323
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go v0.0.1-2
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go v0.0.0-20240304160354-17a94135a53a
 	github.com/pip-services4/pip-services4-go/pip-services4-persistence-go v0.0.0-20230719170734-6e7b58414323
 	github.com/stretchr/testify v1.8.4
 )
@@ -23,3 +23,13 @@ require (
 	golang.org/x/crypto v0.9.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/pip-services4/pip-services4-go/pip-services4-commons-go => ../pip-services4-commons-go
+	github.com/pip-services4/pip-services4-go/pip-services4-components-go => ../pip-services4-components-go
+	github.com/pip-services4/pip-services4-go/pip-services4-config-go => ../pip-services4-config-go
+	github.com/pip-services4/pip-services4-go/pip-services4-data-go => ../pip-services4-data-go
+	github.com/pip-services4/pip-services4-go/pip-services4-expressions-go => ../pip-services4-expressions-go
+	github.com/pip-services4/pip-services4-go/pip-services4-observability-go => ../pip-services4-observability-go
+	github.com/pip-services4/pip-services4-go/pip-services4-persistence-go => ../pip-services4-persistence-go
+)
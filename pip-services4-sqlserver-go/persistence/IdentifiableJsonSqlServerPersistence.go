@@ -7,6 +7,7 @@ import (
 
 	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
 	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 )
 
 // IdentifiableJsonSqlServerPersistence is an abstract persistence component that stores data in SqlServer in JSON or JSONB fields
@@ -106,6 +107,7 @@ type IdentifiableJsonSqlServerPersistence[T any, K any] struct {
 func InheritIdentifiableJsonSqlServerPersistence[T any, K any](overrides ISqlServerPersistenceOverrides[T], tableName string) *IdentifiableJsonSqlServerPersistence[T, K] {
 	c := &IdentifiableJsonSqlServerPersistence[T, K]{}
 	c.IdentifiableSqlServerPersistence = InheritIdentifiableSqlServerPersistence[T, K](overrides, tableName)
+	c.FilterCompiler = NewJsonSqlServerFilterCompiler("data")
 	return c
 }
 
@@ -223,6 +225,15 @@ func (c *IdentifiableJsonSqlServerPersistence[T, K]) ConvertFromPublicPartial(va
 // Returns: receives updated item or error.
 func (c *IdentifiableJsonSqlServerPersistence[T, K]) UpdatePartially(ctx context.Context,
 	id K, data cdata.AnyValueMap) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updatePartially(ctx, id, data)
+		})
+}
+
+func (c *IdentifiableJsonSqlServerPersistence[T, K]) updatePartially(ctx context.Context,
+	id K, data cdata.AnyValueMap) (result T, err error) {
+	data = c.trackPartialData(data)
 	columns, values := c.GenerateColumnsAndValues(data.Value())
 	values = append(values, id)
 
@@ -233,7 +244,7 @@ func (c *IdentifiableJsonSqlServerPersistence[T, K]) UpdatePartially(ctx context
 	}
 
 	query := "UPDATE " + c.QuotedTableName() + " SET [data]=" + set + " OUTPUT INSERTED.* WHERE [id]=@p" + strconv.FormatInt(int64(len(values)), 10)
-	rows, err := c.IdentifiableSqlServerPersistence.Client.QueryContext(ctx, query, values...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
@@ -254,3 +265,26 @@ func (c *IdentifiableJsonSqlServerPersistence[T, K]) UpdatePartially(ctx context
 	}
 	return result, rows.Err()
 }
+
+// UpdateManyPartially updates the same few selected fields in multiple data items in a single statement.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- ids               ids of data items to be updated.
+//		- data              a map with fields to be updated.
+//
+// Returns: updated items and errors of every item in the order of the given ids, or error.
+func (c *IdentifiableJsonSqlServerPersistence[T, K]) UpdateManyPartially(ctx context.Context,
+	ids []K, data cdata.AnyValueMap) ([]T, []error, error) {
+
+	data = c.trackPartialData(data)
+	columns, values := c.GenerateColumnsAndValues(data.Value())
+
+	set := "[data]"
+	for i := 1; i <= len(columns); i++ {
+		column := columns[i-1]
+		set = "JSON_MODIFY(" + set + ",'$." + column + "',@p" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+
+	return c.trackUpdateManyPartially(ctx, ids, "[data]="+set, values)
+}
@@ -6,6 +6,7 @@ import (
 
 	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
 	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
 	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
 
 	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
@@ -22,6 +23,10 @@ import (
 // In complex scenarios child classes can implement additional operations by
 // accessing c._collection and c._model properties.
 //
+// Write operations emit created, updated and deleted change events to listeners
+// added by AddChangeListener. In outbox mode the events are also written to the outbox table
+// in the same transaction as the data.
+//
 //	Configuration parameters
 //		- collection:               (optional) SqlServer collection name
 //		- connection(s):
@@ -37,6 +42,7 @@ import (
 //			- connect_timeout:      (optional) number of milliseconds to wait before timing out when connecting a new client (default: 0)
 //			- idle_timeout:         (optional) number of milliseconds a client must sit idle in the pool and not be checked out (default: 10000)
 //			- max_pool_size:        (optional) maximum number of clients the pool should contain (default: 10)
+//			- outbox:               (optional) write change events to the outbox table in the same transaction as the data (default: false)
 //
 //	References
 //		- *:logger:*:*:1.0           (optional) ILogger components to pass log messages components to pass log messages
@@ -114,6 +120,25 @@ func InheritIdentifiableSqlServerPersistence[T any, K any](overrides ISqlServerP
 	return c
 }
 
+// GetPageByFilterWithToken gets a page of data items retrieved by a given filter using keyset paging.
+// The "id" field is added to sort parameters when it is missing to make the order unique.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- filter            (optional) a filter JSON object
+//		- paging            paging parameters with a token from the previous page
+//		- sort              sort parameters
+//		- selection         (optional) projection JSON object, it must include the sort fields
+//		- args              (optional) query parameters referenced by the filter
+//	Returns: data page or error.
+func (c *IdentifiableSqlServerPersistence[T, K]) GetPageByFilterWithToken(ctx context.Context,
+	filter string, paging cquery.TokenizedPagingParams, sort cquery.SortParams, selection string,
+	args ...any) (page cquery.TokenizedDataPage[T], err error) {
+
+	return c.SqlServerPersistence.GetPageByFilterWithToken(ctx, filter, paging,
+		cpersist.AddKeysetSortField(sort, "id"), selection, args...)
+}
+
 // GetListByIds gets a list of data items retrieved by given unique ids.
 //
 //	Parameters:
@@ -125,9 +150,9 @@ func (c *IdentifiableSqlServerPersistence[T, K]) GetListByIds(ctx context.Contex
 
 	ln := len(ids)
 	params := c.GenerateParameters(ln)
-	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE [id] IN(" + params + ")"
+	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE " + c.filterDeleted(ctx, "[id] IN("+params+")")
 
-	rows, err := c.Client.QueryContext(ctx, query, ItemsToAnySlice(ids)...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, ItemsToAnySlice(ids)...)
 	if err != nil {
 		return nil, err
 	}
@@ -156,6 +181,7 @@ func (c *IdentifiableSqlServerPersistence[T, K]) GetListByIds(ctx context.Contex
 }
 
 // GetOneById gets a data item by its unique id.
+// In soft-delete mode deleted items are not returned unless the context is created by cpersist.WithDeleted.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -164,9 +190,9 @@ func (c *IdentifiableSqlServerPersistence[T, K]) GetListByIds(ctx context.Contex
 // Returns: data item or error.
 func (c *IdentifiableSqlServerPersistence[T, K]) GetOneById(ctx context.Context, id K) (item T, err error) {
 
-	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE [id]=@p1"
+	query := "SELECT * FROM " + c.QuotedTableName() + " WHERE " + c.filterDeleted(ctx, "[id]=@p1")
 
-	rows, err := c.Client.QueryContext(ctx, query, id)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, id)
 	if err != nil {
 		return item, err
 	}
@@ -194,7 +220,10 @@ func (c *IdentifiableSqlServerPersistence[T, K]) Create(ctx context.Context, ite
 	newItem := c.cloneItem(item)
 	newItem = GenerateObjectIdIfNotExists[T](newItem)
 
-	return c.SqlServerPersistence.Create(ctx, newItem)
+	return c.trackChange(ctx, cpersist.ChangeTypeCreated, GetObjectId[K](newItem), false,
+		func(ctx context.Context) (T, error) {
+			return c.SqlServerPersistence.Create(ctx, newItem)
+		})
 }
 
 // Set a data item. If the data item exists it updates it,
@@ -205,6 +234,14 @@ func (c *IdentifiableSqlServerPersistence[T, K]) Create(ctx context.Context, ite
 //		- item              an item to be set.
 //	Returns: (optional)  updated item or error.
 func (c *IdentifiableSqlServerPersistence[T, K]) Set(ctx context.Context, item T) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, GetObjectId[K](item), true,
+		func(ctx context.Context) (T, error) {
+			return c.set(ctx, item)
+		})
+}
+
+func (c *IdentifiableSqlServerPersistence[T, K]) set(ctx context.Context, item T) (result T, err error) {
+	item = c.trackItem(item, true)
 	objMap, convErr := c.Overrides.ConvertFromPublic(item)
 	if convErr != nil {
 		return result, convErr
@@ -221,7 +258,7 @@ func (c *IdentifiableSqlServerPersistence[T, K]) Set(ctx context.Context, item T
 
 	query := "INSERT INTO " + c.QuotedTableName() + " (" + columnsStr + ") OUTPUT INSERTED.* VALUES (" + paramsStr + ")"
 
-	rows, err := c.Client.QueryContext(ctx, query, values...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
@@ -238,7 +275,7 @@ func (c *IdentifiableSqlServerPersistence[T, K]) Set(ctx context.Context, item T
 
 	values = append(values, id)
 	query = "UPDATE " + c.QuotedTableName() + " SET " + setParams + " OUTPUT INSERTED.* WHERE [id]=@p" + strconv.FormatInt(int64(len(values)), 10)
-	rows, err = c.Client.QueryContext(ctx, query, values...)
+	rows, err = c.GetClient(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
@@ -265,6 +302,14 @@ func (c *IdentifiableSqlServerPersistence[T, K]) Set(ctx context.Context, item T
 //		- item              an item to be updated.
 //	Returns          (optional)  updated item or error.
 func (c *IdentifiableSqlServerPersistence[T, K]) Update(ctx context.Context, item T) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, GetObjectId[K](item), true,
+		func(ctx context.Context) (T, error) {
+			return c.update(ctx, item)
+		})
+}
+
+func (c *IdentifiableSqlServerPersistence[T, K]) update(ctx context.Context, item T) (result T, err error) {
+	item = c.trackItem(item, false)
 	objMap, convErr := c.Overrides.ConvertFromPublic(item)
 	if convErr != nil {
 		return result, convErr
@@ -276,7 +321,7 @@ func (c *IdentifiableSqlServerPersistence[T, K]) Update(ctx context.Context, ite
 
 	query := "UPDATE " + c.QuotedTableName() + " SET " + paramsStr + " OUTPUT INSERTED.* WHERE [id]=@p" + strconv.FormatInt(int64(len(values)), 10)
 
-	rows, err := c.Client.QueryContext(ctx, query, values...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
@@ -305,6 +350,14 @@ func (c *IdentifiableSqlServerPersistence[T, K]) Update(ctx context.Context, ite
 //		- data              a map with fields to be updated.
 //	Returns: updated item or error.
 func (c *IdentifiableSqlServerPersistence[T, K]) UpdatePartially(ctx context.Context, id K, data cdata.AnyValueMap) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeUpdated, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updatePartially(ctx, id, data)
+		})
+}
+
+func (c *IdentifiableSqlServerPersistence[T, K]) updatePartially(ctx context.Context, id K, data cdata.AnyValueMap) (result T, err error) {
+	data = c.trackPartialData(data)
 	objMap, convErr := c.Overrides.ConvertFromPublicPartial(data.Value())
 	if convErr != nil {
 		return result, convErr
@@ -315,7 +368,7 @@ func (c *IdentifiableSqlServerPersistence[T, K]) UpdatePartially(ctx context.Con
 
 	query := "UPDATE " + c.QuotedTableName() + " SET " + paramsStr + " OUTPUT INSERTED.* WHERE [id]=@p" + strconv.FormatInt(int64(len(values)), 10)
 
-	rows, err := c.Client.QueryContext(ctx, query, values...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return result, err
 	}
@@ -338,15 +391,36 @@ func (c *IdentifiableSqlServerPersistence[T, K]) UpdatePartially(ctx context.Con
 }
 
 // DeleteById deletes a data item by its unique id.
+// In soft-delete mode the item is marked as deleted instead.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
 //		- id                an id of the item to be deleted
 //	Returns: (optional)  deleted item or error.
 func (c *IdentifiableSqlServerPersistence[T, K]) DeleteById(ctx context.Context, id K) (result T, err error) {
+	if c.isSoftDelete() {
+		return c.markDeletedById(ctx, id, true)
+	}
+	return c.PurgeById(ctx, id)
+}
+
+// PurgeById physically deletes a data item by its unique id regardless of soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- id                an id of the item to be deleted
+//	Returns: (optional)  deleted item or error.
+func (c *IdentifiableSqlServerPersistence[T, K]) PurgeById(ctx context.Context, id K) (result T, err error) {
+	return c.trackChange(ctx, cpersist.ChangeTypeDeleted, id, false,
+		func(ctx context.Context) (T, error) {
+			return c.purgeById(ctx, id)
+		})
+}
+
+func (c *IdentifiableSqlServerPersistence[T, K]) purgeById(ctx context.Context, id K) (result T, err error) {
 	query := "DELETE FROM " + c.QuotedTableName() + " OUTPUT DELETED.* WHERE [id]=@p1"
 
-	rows, err := c.Client.QueryContext(ctx, query, []any{id}...)
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, []any{id}...)
 	if err != nil {
 		return result, err
 	}
@@ -368,7 +442,55 @@ func (c *IdentifiableSqlServerPersistence[T, K]) DeleteById(ctx context.Context,
 	return result, rows.Err()
 }
 
+// RestoreById restores a data item marked as deleted in soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- id                an id of the item to be restored
+//	Returns: (optional)  restored item or error.
+func (c *IdentifiableSqlServerPersistence[T, K]) RestoreById(ctx context.Context, id K) (result T, err error) {
+	return c.markDeletedById(ctx, id, false)
+}
+
+func (c *IdentifiableSqlServerPersistence[T, K]) markDeletedById(ctx context.Context, id K, deleted bool) (result T, err error) {
+	changeType := cpersist.ChangeTypeUpdated
+	if deleted {
+		changeType = cpersist.ChangeTypeDeleted
+	}
+	return c.trackChange(ctx, changeType, id, true,
+		func(ctx context.Context) (T, error) {
+			return c.updateDeletedById(ctx, id, deleted)
+		})
+}
+
+func (c *IdentifiableSqlServerPersistence[T, K]) updateDeletedById(ctx context.Context, id K, deleted bool) (result T, err error) {
+	query := "UPDATE " + c.QuotedTableName() + " SET " + c.composeDeletedSet(deleted) +
+		" OUTPUT INSERTED.* WHERE [id]=@p1"
+
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, id)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return result, rows.Err()
+	}
+
+	result, convErr := c.Overrides.ConvertToPublic(rows)
+	if convErr != nil {
+		return result, convErr
+	}
+	if deleted {
+		c.Logger.Trace(ctx, "Marked as deleted in %s with id = %s", c.TableName, id)
+	} else {
+		c.Logger.Trace(ctx, "Restored in %s with id = %s", c.TableName, id)
+	}
+	return result, nil
+}
+
 // DeleteByIds deletes multiple data items by their unique ids.
+// In soft-delete mode the items are marked as deleted instead.
 //
 //	Parameters:
 //		- ctx context.Context transaction id to trace execution through call chain.
@@ -379,9 +501,24 @@ func (c *IdentifiableSqlServerPersistence[T, K]) DeleteByIds(ctx context.Context
 	ln := len(ids)
 	paramsStr := c.GenerateParameters(ln)
 
-	query := "DELETE FROM " + c.QuotedTableName() + " WHERE \"id\" IN(" + paramsStr + ")"
+	query := "DELETE FROM " + c.QuotedTableName()
+	output := " OUTPUT DELETED.*"
+	condition := " WHERE [id] IN(" + paramsStr + ")"
+	if c.isSoftDelete() {
+		query = "UPDATE " + c.QuotedTableName() + " SET " + c.composeDeletedSet(true)
+		output = " OUTPUT INSERTED.*"
+		condition = " WHERE " + c.filterDeleted(ctx, "[id] IN("+paramsStr+")")
+	}
+
+	if c.isTrackingChanges() {
+		return c.trackChanges(ctx, cpersist.ChangeTypeDeleted, ids, c.isSoftDelete(),
+			func(ctx context.Context) ([]T, error) {
+				return c.readDeletedItems(ctx, query+output+condition, ItemsToAnySlice(ids)...)
+			})
+	}
+	query += condition
 
-	result, err := c.Client.ExecContext(ctx, query, ItemsToAnySlice(ids)...)
+	result, err := c.GetClient(ctx).ExecContext(ctx, query, ItemsToAnySlice(ids)...)
 	if err != nil {
 		return err
 	}
@@ -396,3 +533,27 @@ func (c *IdentifiableSqlServerPersistence[T, K]) DeleteByIds(ctx context.Context
 	}
 	return nil
 }
+
+// readDeletedItems executes a delete query that outputs rows and reads the deleted items.
+func (c *IdentifiableSqlServerPersistence[T, K]) readDeletedItems(ctx context.Context, query string, values ...any) ([]T, error) {
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]T, 0)
+	for rows.Next() {
+		item, convErr := c.Overrides.ConvertToPublic(rows)
+		if convErr != nil {
+			return items, convErr
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return items, err
+	}
+
+	c.Logger.Trace(ctx, "Deleted %d items from %s", len(items), c.TableName)
+	return items, nil
+}
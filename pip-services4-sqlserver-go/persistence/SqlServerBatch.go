@@ -0,0 +1,270 @@
+package persistence
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+const (
+	// The maximum number of parameters in a single request is 2100, some are left for the driver.
+	batchMaxParameters = 2000
+	// The maximum number of rows in a single VALUES clause.
+	batchMaxRows = 1000
+)
+
+// CreateMany creates multiple data items using multi-row inserts.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- items             items to be created.
+//	Returns: created items and errors of every item in the order of the given items, or error.
+//	Items that cannot be converted get conversion errors in errs and items that repeat an id
+//	of a previous item get ConflictError. Such items are skipped, while database failures fail the whole batch.
+func (c *IdentifiableSqlServerPersistence[T, K]) CreateMany(ctx context.Context, items []T) (results []T, errs []error, err error) {
+	err = c.trackChanges(ctx, cpersist.ChangeTypeCreated, nil, false, func(ctx context.Context) ([]T, error) {
+		results, errs, err = c.writeMany(ctx, items, func(columns []string, rows string) string {
+			return "INSERT INTO " + c.QuotedTableName() + " (" + c.GenerateColumns(columns) + ")" +
+				" OUTPUT INSERTED.* VALUES " + rows
+		})
+		return results, err
+	})
+	if err == nil {
+		c.Logger.Trace(ctx, "Created %d items in %s", len(items), c.TableName)
+	}
+	return results, errs, err
+}
+
+// UpsertMany sets multiple data items using MERGE statements.
+// The data items that exist are updated, others are created.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- items             items to be set.
+//	Returns: set items and errors of every item in the order of the given items, or error.
+//	Items that cannot be converted get conversion errors in errs and items that repeat an id
+//	of a previous item get ConflictError. Such items are skipped, while database failures fail the whole batch.
+func (c *IdentifiableSqlServerPersistence[T, K]) UpsertMany(ctx context.Context, items []T) (results []T, errs []error, err error) {
+	ids := make([]K, len(items))
+	for i, item := range items {
+		ids[i] = GetObjectId[K](item)
+	}
+
+	err = c.trackChanges(ctx, cpersist.ChangeTypeUpdated, ids, true, func(ctx context.Context) ([]T, error) {
+		results, errs, err = c.writeMany(ctx, items, func(columns []string, rows string) string {
+			sets := make([]string, 0, len(columns))
+			sources := make([]string, len(columns))
+			for i, column := range columns {
+				sources[i] = "source." + c.QuoteIdentifier(column)
+				if column != "id" {
+					sets = append(sets, c.QuoteIdentifier(column)+"="+sources[i])
+				}
+			}
+			query := "MERGE INTO " + c.QuotedTableName() + " AS target" +
+				" USING (VALUES " + rows + ") AS source (" + c.GenerateColumns(columns) + ")" +
+				" ON target.[id]=source.[id]"
+			if len(sets) > 0 {
+				query += " WHEN MATCHED THEN UPDATE SET " + strings.Join(sets, ",")
+			}
+			return query + " WHEN NOT MATCHED THEN INSERT (" + c.GenerateColumns(columns) + ")" +
+				" VALUES (" + strings.Join(sources, ",") + ") OUTPUT INSERTED.*;"
+		})
+		return results, err
+	})
+	if err == nil {
+		c.Logger.Trace(ctx, "Set %d items in %s", len(items), c.TableName)
+	}
+	return results, errs, err
+}
+
+// UpdateManyPartially updates the same few selected fields in multiple data items in a single statement.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- ids               ids of data items to be updated.
+//		- data              a map with fields to be updated.
+//	Returns: updated items and errors of every item in the order of the given ids, or error.
+//	Items that were not found are returned as empty values.
+func (c *IdentifiableSqlServerPersistence[T, K]) UpdateManyPartially(ctx context.Context,
+	ids []K, data cdata.AnyValueMap) ([]T, []error, error) {
+
+	data = c.trackPartialData(data)
+	objMap, err := c.Overrides.ConvertFromPublicPartial(data.Value())
+	if err != nil {
+		return nil, nil, err
+	}
+	columns, values := c.GenerateColumnsAndValues(objMap)
+	return c.trackUpdateManyPartially(ctx, ids, c.GenerateSetParameters(columns), values)
+}
+
+// trackUpdateManyPartially executes the partial update of multiple items and emits their change events.
+func (c *IdentifiableSqlServerPersistence[T, K]) trackUpdateManyPartially(ctx context.Context,
+	ids []K, set string, values []any) (results []T, errs []error, err error) {
+
+	err = c.trackChanges(ctx, cpersist.ChangeTypeUpdated, ids, true, func(ctx context.Context) ([]T, error) {
+		results, errs, err = c.updateManyPartially(ctx, ids, set, values)
+		return results, err
+	})
+	return results, errs, err
+}
+
+// writeMany writes the items with multi-row statements split to fit the parameter limits
+// and maps returned rows back to the items by their ids.
+// Items with different sets of columns are written by separate statements,
+// so columns missing in an item get their default values.
+func (c *IdentifiableSqlServerPersistence[T, K]) writeMany(ctx context.Context, items []T,
+	composeQuery func(columns []string, rows string) string) ([]T, []error, error) {
+
+	results := make([]T, len(items))
+	errs := make([]error, len(items))
+
+	objMaps := make([]map[string]any, len(items))
+	positions := make(map[string]int, len(items))
+	for i, item := range items {
+		newItem := GenerateObjectIdIfNotExists[T](c.cloneItem(item))
+		newItem = c.trackItem(newItem, true)
+		objMap, err := c.Overrides.ConvertFromPublic(newItem)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		// A single statement cannot write the same row twice
+		id := cconv.StringConverter.ToString(cpersist.GetObjectId(objMap))
+		if _, ok := positions[id]; ok {
+			errs[i] = cpersist.NewDuplicateIdError(cctx.GetTraceId(ctx), id)
+			continue
+		}
+		objMaps[i] = objMap
+		positions[id] = i
+	}
+
+	groups := cpersist.GroupBatchRows(objMaps)
+	statements := 0
+	for _, group := range groups {
+		rowsPerStatement := c.rowsPerStatement(len(group.Columns))
+		statements += (len(group.Rows) + rowsPerStatement - 1) / rowsPerStatement
+	}
+	write := func(ctx context.Context) error {
+		for _, group := range groups {
+			if err := c.writeBatchGroup(ctx, group, composeQuery, positions, results); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Items that do not fit a single statement are written in one transaction
+	// to keep the whole batch atomic
+	var err error
+	if statements > 1 {
+		err = c.Connection.ExecuteInTransaction(ctx, write)
+	} else {
+		err = write(ctx)
+	}
+	if err != nil {
+		var empty T
+		for i := range results {
+			results[i] = empty
+		}
+	}
+	return results, errs, err
+}
+
+// rowsPerStatement calculates how many rows with the given number of columns fit a single statement.
+func (c *IdentifiableSqlServerPersistence[T, K]) rowsPerStatement(columns int) int {
+	rowsPerStatement := batchMaxParameters / columns
+	if rowsPerStatement > batchMaxRows {
+		rowsPerStatement = batchMaxRows
+	}
+	return rowsPerStatement
+}
+
+// writeBatchGroup writes rows with the same columns and places returned rows to the positions of their ids.
+func (c *IdentifiableSqlServerPersistence[T, K]) writeBatchGroup(ctx context.Context, group *cpersist.BatchRowGroup,
+	composeQuery func(columns []string, rows string) string, positions map[string]int, results []T) error {
+
+	columns := group.Columns
+	rowsPerStatement := c.rowsPerStatement(len(columns))
+	rows := make([]string, 0, rowsPerStatement)
+	values := make([]any, 0, rowsPerStatement*len(columns))
+	flush := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+		query := composeQuery(columns, strings.Join(rows, ","))
+		err := c.readBatchResults(ctx, query, values, positions, results)
+		rows, values = rows[:0], values[:0]
+		return err
+	}
+
+	for _, objMap := range group.Rows {
+		params := make([]string, len(columns))
+		for j, column := range columns {
+			values = append(values, objMap[column])
+			params[j] = "@p" + strconv.Itoa(len(values))
+		}
+		rows = append(rows, "("+strings.Join(params, ",")+")")
+		if len(rows) >= rowsPerStatement {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// updateManyPartially executes the set clause with the given parameters for all the ids.
+func (c *IdentifiableSqlServerPersistence[T, K]) updateManyPartially(ctx context.Context,
+	ids []K, set string, values []any) ([]T, []error, error) {
+
+	results := make([]T, len(ids))
+	errs := make([]error, len(ids))
+	if len(ids) == 0 {
+		return results, errs, nil
+	}
+
+	positions := make(map[string]int, len(ids))
+	params := make([]string, len(ids))
+	for i, id := range ids {
+		positions[cconv.StringConverter.ToString(id)] = i
+		values = append(values, id)
+		params[i] = "@p" + strconv.Itoa(len(values))
+	}
+
+	query := "UPDATE " + c.QuotedTableName() + " SET " + set +
+		" OUTPUT INSERTED.* WHERE [id] IN(" + strings.Join(params, ",") + ")"
+	if err := c.readBatchResults(ctx, query, values, positions, results); err != nil {
+		return results, errs, err
+	}
+
+	c.Logger.Trace(ctx, "Updated partially %d items in %s", len(ids), c.TableName)
+	return results, errs, nil
+}
+
+// readBatchResults executes the query and places returned rows to the positions of their ids.
+func (c *IdentifiableSqlServerPersistence[T, K]) readBatchResults(ctx context.Context, query string, values []any,
+	positions map[string]int, results []T) error {
+
+	rows, err := c.GetClient(ctx).QueryContext(ctx, query, values...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, convErr := c.Overrides.ConvertToPublic(rows)
+		if convErr != nil {
+			return convErr
+		}
+		id := cconv.StringConverter.ToString(GetObjectId[K](item))
+		if index, ok := positions[id]; ok {
+			results[index] = item
+		}
+	}
+	return rows.Err()
+}
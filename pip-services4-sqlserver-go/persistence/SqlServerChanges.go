@@ -0,0 +1,301 @@
+package persistence
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"time"
+
+	cconv "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/convert"
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// AddChangeListener registers a listener to receive change events of the data items.
+// Listeners are called after the changes are written. Their errors are logged
+// and do not fail the operations.
+//
+//	Parameters:
+//		- listener a listener to be added.
+func (c *SqlServerPersistence[T]) AddChangeListener(listener cpersist.IChangeListener) {
+	c.changes.AddListener(listener)
+}
+
+// RemoveChangeListener removes a previously registered change listener.
+//
+//	Parameters:
+//		- listener a listener to be removed.
+func (c *SqlServerPersistence[T]) RemoveChangeListener(listener cpersist.IChangeListener) {
+	c.changes.RemoveListener(listener)
+}
+
+// QuotedOutboxTableName returns quoted name of the table that keeps change events in outbox mode.
+func (c *SqlServerPersistence[T]) QuotedOutboxTableName() string {
+	name := c.OutboxTableName
+	if name == "" {
+		name = c.TableName + "_outbox"
+	}
+	if len(c.SchemaName) > 0 {
+		return c.QuoteIdentifier(c.SchemaName) + "." + c.QuoteIdentifier(name)
+	}
+	return c.QuoteIdentifier(name)
+}
+
+// createOutbox creates the outbox table when outbox mode is on.
+func (c *SqlServerPersistence[T]) createOutbox(ctx context.Context) error {
+	if !c.Outbox {
+		return nil
+	}
+	tableName := c.QuotedOutboxTableName()
+	_, err := c.GetClient(ctx).ExecContext(ctx, "IF OBJECT_ID(@p1, 'U') IS NULL CREATE TABLE "+tableName+
+		" ([id] BIGINT IDENTITY(1,1) PRIMARY KEY, [type] NVARCHAR(20) NOT NULL, [source] NVARCHAR(255),"+
+		" [item_id] NVARCHAR(255), [before] NVARCHAR(MAX), [after] NVARCHAR(MAX), [time] DATETIMEOFFSET)", tableName)
+	return err
+}
+
+func (c *SqlServerPersistence[T]) isTrackingChanges() bool {
+	return c.Outbox || c.changes.HasListeners()
+}
+
+// writeChanges executes a write that returns change events. In outbox mode the write
+// and the events are committed in the same transaction. The events are delivered
+// to the listeners after the write succeeded.
+func (c *SqlServerPersistence[T]) writeChanges(ctx context.Context,
+	write func(ctx context.Context) ([]*cpersist.ChangeEvent, error)) (err error) {
+
+	var events []*cpersist.ChangeEvent
+	if c.Outbox {
+		err = c.Connection.ExecuteInTransaction(ctx, func(ctx context.Context) (err error) {
+			if events, err = write(ctx); err != nil {
+				return err
+			}
+			return c.writeOutbox(ctx, events)
+		})
+	} else {
+		events, err = write(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(events) > 0 && c.changes.HasListeners() {
+		if err := c.changes.Notify(ctx, events...); err != nil {
+			c.Logger.Error(ctx, err, "Failed to notify listeners about changes in %s", c.TableName)
+		}
+	}
+	return nil
+}
+
+func (c *SqlServerPersistence[T]) writeOutbox(ctx context.Context, events []*cpersist.ChangeEvent) error {
+	query := "INSERT INTO " + c.QuotedOutboxTableName() +
+		" ([type], [source], [item_id], [before], [after], [time]) VALUES (@p1, @p2, @p3, @p4, @p5, @p6)"
+
+	for _, event := range events {
+		before, err := c.toOutboxJson(event.Before)
+		if err != nil {
+			return err
+		}
+		after, err := c.toOutboxJson(event.After)
+		if err != nil {
+			return err
+		}
+		_, err = c.GetClient(ctx).ExecContext(ctx, query, event.Type, event.Source,
+			cconv.StringConverter.ToString(event.Id), before, after, event.Time)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.Logger.Trace(ctx, "Written %d change events to %s", len(events), c.QuotedOutboxTableName())
+	return nil
+}
+
+func (c *SqlServerPersistence[T]) toOutboxJson(item any) (any, error) {
+	if value, ok := item.(T); ok {
+		return c.JsonConvertor.ToJson(value)
+	}
+	return nil, nil
+}
+
+// DrainOutbox reads up to maxCount oldest change events from the outbox table and passes them to the handler.
+// The events are locked while they are handled and removed in the same transaction when the handler succeeds,
+// so concurrent publishers do not read the same events.
+//
+//	Parameters:
+//		- ctx context.Context execution context to trace execution through call chain.
+//		- maxCount int a maximum number of events to read.
+//		- handler a function to publish the events in the order they were written.
+//	Returns: a number of drained events or error.
+func (c *SqlServerPersistence[T]) DrainOutbox(ctx context.Context, maxCount int,
+	handler func(ctx context.Context, entries []*cpersist.OutboxEntry) error) (count int, err error) {
+
+	if !c.Outbox {
+		return 0, cerr.NewConfigError(cctx.GetTraceId(ctx), "NO_OUTBOX",
+			"Outbox is not enabled for "+c.TableName)
+	}
+	if maxCount <= 0 {
+		maxCount = c.MaxPageSize
+	}
+
+	err = c.Connection.ExecuteInTransaction(ctx, func(ctx context.Context) error {
+		query := "SELECT TOP (" + strconv.Itoa(maxCount) + ") [id], [type], [source], [item_id], [before], [after], [time] FROM " +
+			c.QuotedOutboxTableName() + " WITH (UPDLOCK, READPAST, ROWLOCK) ORDER BY [id]"
+
+		rows, err := c.GetClient(ctx).QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]any, 0)
+		entries := make([]*cpersist.OutboxEntry, 0)
+		for rows.Next() {
+			var id int64
+			var changeType, source, itemId string
+			var before, after []byte
+			var changeTime time.Time
+			if err := rows.Scan(&id, &changeType, &source, &itemId, &before, &after, &changeTime); err != nil {
+				rows.Close()
+				return err
+			}
+
+			event := cpersist.NewChangeEvent(changeType, source, itemId, nil, nil)
+			event.Time = changeTime
+			if event.Before, err = c.fromOutboxJson(before); err != nil {
+				rows.Close()
+				return err
+			}
+			if event.After, err = c.fromOutboxJson(after); err != nil {
+				rows.Close()
+				return err
+			}
+
+			ids = append(ids, id)
+			entries = append(entries, &cpersist.OutboxEntry{Id: strconv.FormatInt(id, 10), Event: event})
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		if err := handler(ctx, entries); err != nil {
+			return err
+		}
+
+		_, err = c.GetClient(ctx).ExecContext(ctx, "DELETE FROM "+c.QuotedOutboxTableName()+
+			" WHERE [id] IN ("+c.GenerateParameters(len(ids))+")", ids...)
+		if err != nil {
+			return err
+		}
+		count = len(entries)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		c.Logger.Trace(ctx, "Drained %d change events from %s", count, c.QuotedOutboxTableName())
+	}
+	return count, nil
+}
+
+func (c *SqlServerPersistence[T]) fromOutboxJson(value []byte) (any, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	return c.JsonConvertor.FromJson(string(value))
+}
+
+// trackChange executes a write of a single item and emits its change event.
+// When readBefore is set the stored item is read before the write to fill the before image.
+// Writes that return an empty item do not emit events.
+func (c *IdentifiableSqlServerPersistence[T, K]) trackChange(ctx context.Context, changeType string, id K,
+	readBefore bool, write func(ctx context.Context) (T, error)) (result T, err error) {
+
+	if !c.isTrackingChanges() {
+		return write(ctx)
+	}
+
+	err = c.writeChanges(ctx, func(ctx context.Context) ([]*cpersist.ChangeEvent, error) {
+		var oldItem any
+		if readBefore {
+			item, err := c.GetOneById(cpersist.WithDeleted(ctx), id)
+			if err != nil {
+				return nil, err
+			}
+			if !isEmptyItem(item) {
+				oldItem = item
+			}
+		}
+
+		var err error
+		if result, err = write(ctx); err != nil || isEmptyItem(result) {
+			return nil, err
+		}
+		return []*cpersist.ChangeEvent{c.composeChange(changeType, oldItem, result)}, nil
+	})
+	return result, err
+}
+
+// trackChanges executes a write of multiple items and emits their change events.
+// When readBefore is set the stored items with the given ids are read before the write.
+func (c *IdentifiableSqlServerPersistence[T, K]) trackChanges(ctx context.Context, changeType string, ids []K,
+	readBefore bool, write func(ctx context.Context) ([]T, error)) error {
+
+	if !c.isTrackingChanges() {
+		_, err := write(ctx)
+		return err
+	}
+
+	return c.writeChanges(ctx, func(ctx context.Context) ([]*cpersist.ChangeEvent, error) {
+		oldItems := make(map[string]T)
+		if readBefore && len(ids) > 0 {
+			items, err := c.GetListByIds(cpersist.WithDeleted(ctx), ids)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				oldItems[cconv.StringConverter.ToString(GetObjectId[K](item))] = item
+			}
+		}
+
+		items, err := write(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		events := make([]*cpersist.ChangeEvent, 0, len(items))
+		for _, item := range items {
+			if isEmptyItem(item) {
+				continue
+			}
+			var oldItem any
+			if value, ok := oldItems[cconv.StringConverter.ToString(GetObjectId[K](item))]; ok {
+				oldItem = value
+			}
+			events = append(events, c.composeChange(changeType, oldItem, item))
+		}
+		return events, nil
+	})
+}
+
+// composeChange creates a change event. Updates of missing items are reported as created
+// and items deleted without reading the before image are reported as the before image.
+func (c *IdentifiableSqlServerPersistence[T, K]) composeChange(changeType string, oldItem any, newItem T) *cpersist.ChangeEvent {
+	var after any = newItem
+	switch {
+	case changeType == cpersist.ChangeTypeUpdated && oldItem == nil:
+		changeType = cpersist.ChangeTypeCreated
+	case changeType == cpersist.ChangeTypeDeleted && oldItem == nil:
+		oldItem, after = newItem, nil
+	}
+	return cpersist.NewChangeEvent(changeType, c.TableName, GetObjectId[K](newItem), oldItem, after)
+}
+
+func isEmptyItem[T any](item T) bool {
+	return reflect.ValueOf(&item).Elem().IsZero()
+}
@@ -0,0 +1,122 @@
+package persistence
+
+import (
+	"strconv"
+	"strings"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// SqlServerFilterDialect defines SQL Server syntax to compile FilterExpression into parameterized queries.
+//
+// When JsonColumn is set, fields other than "id" are extracted from the JSON document
+// stored in that column using JSON_VALUE function. Extracted fields are cast to float
+// when they are compared with numbers, so ranges of numeric fields are not compared as strings.
+type SqlServerFilterDialect struct {
+	JsonColumn string
+	// Compares fields with numbers only when values have numeric types, like keyset values
+	typedValues bool
+}
+
+// NewSqlServerFilterCompiler creates a filter compiler for plain SQL Server tables.
+//
+//	Returns: a new filter compiler.
+func NewSqlServerFilterCompiler() *cpersist.SqlFilterCompiler {
+	return cpersist.NewSqlFilterCompiler(&SqlServerFilterDialect{})
+}
+
+// NewJsonSqlServerFilterCompiler creates a filter compiler for SQL Server tables that keep data in JSON column.
+//
+//	Parameters:
+//		- jsonColumn a name of the JSON column.
+//	Returns: a new filter compiler.
+func NewJsonSqlServerFilterCompiler(jsonColumn string) *cpersist.SqlFilterCompiler {
+	return cpersist.NewSqlFilterCompiler(&SqlServerFilterDialect{JsonColumn: jsonColumn})
+}
+
+// FormatField converts a field name into a quoted column or a JSON path extraction.
+func (c *SqlServerFilterDialect) FormatField(field string) string {
+	if c.JsonColumn != "" && field != "id" {
+		return "JSON_VALUE([" + c.JsonColumn + "],'$." + field + "')"
+	}
+	return "[" + strings.ReplaceAll(field, ".", "].[") + "]"
+}
+
+// FormatComparison casts a field extracted from JSON document to float when it is compared with numbers.
+// Values of FilterParams are strings, so numeric strings are treated as numbers by range operators.
+// Equality and "in" operators compare with numbers only when the values have numeric types.
+// Fields that are not numbers are converted into nulls and never match.
+func (c *SqlServerFilterDialect) FormatComparison(field string, operator cpersist.FilterOperator, values []any) string {
+	column := c.FormatField(field)
+	if c.JsonColumn == "" || field == "id" ||
+		!cpersist.IsSqlNumericComparison(operator, values, !c.typedValues) {
+		return column
+	}
+	return "TRY_CAST(" + column + " AS FLOAT)"
+}
+
+// FormatParameter returns a named parameter placeholder like "@p1".
+func (c *SqlServerFilterDialect) FormatParameter(index int) string {
+	return "@p" + strconv.Itoa(index)
+}
+
+// FormatProjection converts included fields into a SELECT column list.
+// For JSON tables the raw values of the fields are read from the JSON document
+// with OPENJSON function and concatenated into a new document.
+func (c *SqlServerFilterDialect) FormatProjection(fields []*cpersist.ProjectionField) string {
+	if c.JsonColumn == "" {
+		columns := make([]string, len(fields))
+		for i, field := range fields {
+			columns[i] = c.FormatField(field.Name)
+		}
+		return strings.Join(columns, ",")
+	}
+	return c.formatJsonObject(fields) + " AS [" + c.JsonColumn + "]"
+}
+
+func (c *SqlServerFilterDialect) formatJsonObject(fields []*cpersist.ProjectionField) string {
+	values := make([]string, 0, 2*len(fields)+2)
+	values = append(values, "'{'")
+	for i, field := range fields {
+		separator := ","
+		if i == 0 {
+			separator = ""
+		}
+		values = append(values, "'"+separator+"\""+field.Name+"\":'")
+		if len(field.Children) > 0 {
+			values = append(values, c.formatJsonObject(field.Children))
+		} else {
+			values = append(values, c.formatJsonValue(field))
+		}
+	}
+	values = append(values, "'}'")
+	return "CONCAT(" + strings.Join(values, ",") + ")"
+}
+
+// formatJsonValue reads a raw JSON value of the field. Strings are escaped
+// and quoted back, missing fields are replaced with null.
+func (c *SqlServerFilterDialect) formatJsonValue(field *cpersist.ProjectionField) string {
+	parent := "$"
+	if index := strings.LastIndex(field.Path, "."); index > 0 {
+		parent += "." + field.Path[:index]
+	}
+	return "COALESCE((SELECT CASE [type] WHEN 0 THEN 'null'" +
+		" WHEN 1 THEN CONCAT('\"',STRING_ESCAPE([value],'json'),'\"') ELSE [value] END" +
+		" FROM OPENJSON([" + c.JsonColumn + "],'" + parent + "') WHERE [key]='" + field.Name + "'),'null')"
+}
+
+// FormatAggregate converts an aggregate function into SQL expression.
+// Sums and averages are calculated over float values to support JSON fields
+// and to avoid integer division.
+// Minimum and maximum of JSON fields are found among their text values.
+func (c *SqlServerFilterDialect) FormatAggregate(function string, field string) string {
+	column := ""
+	if field != "" {
+		column = c.FormatField(field)
+		if function == cquery.AggregateSum || function == cquery.AggregateAvg {
+			column = "CAST(" + column + " AS FLOAT)"
+		}
+	}
+	return cpersist.FormatSqlAggregate(function, column)
+}
@@ -0,0 +1,204 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	cerr "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/errors"
+	cctx "github.com/pip-services4/pip-services4-go/pip-services4-components-go/context"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// AddMigration registers a versioned schema migration that is applied when the component is opened.
+//
+//	Parameters:
+//		- version a unique positive version. Migrations are applied in ascending order of versions.
+//		- description a human readable description.
+//		- up a script that applies the change.
+//		- down (optional) a script that reverts the change.
+func (c *SqlServerPersistence[T]) AddMigration(version int64, description string, up string, down string) {
+	c.migrations = append(c.migrations, cpersist.NewMigration(version, description, up, down))
+}
+
+// AddMigrations registers versioned schema migrations, for instance loaded
+// by cpersist.LoadMigrationsFromDirectory.
+//
+//	Parameters:
+//		- migrations migrations to register.
+func (c *SqlServerPersistence[T]) AddMigrations(migrations ...*cpersist.Migration) {
+	c.migrations = append(c.migrations, migrations...)
+}
+
+// QuotedMigrationsTableName returns quoted name of the table that tracks applied migrations.
+func (c *SqlServerPersistence[T]) QuotedMigrationsTableName() string {
+	name := c.MigrationsTableName
+	if name == "" {
+		name = c.TableName + "_migrations"
+	}
+	if len(c.SchemaName) > 0 {
+		return c.QuoteIdentifier(c.SchemaName) + "." + c.QuoteIdentifier(name)
+	}
+	return c.QuoteIdentifier(name)
+}
+
+// Migrate applies all pending migrations.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: error or nil no errors occurred.
+func (c *SqlServerPersistence[T]) Migrate(ctx context.Context) error {
+	return c.MigrateTo(ctx, cpersist.MigrationLatestVersion)
+}
+
+// MigrateTo moves database schema to the specified version applying pending migrations
+// or reverting applied migrations with greater versions. Migrations run in a single
+// transaction that holds an application lock, so concurrent instances wait for each other.
+// Scripts must not contain "GO" batch separators.
+// In dry-run mode the scripts are logged and the transaction is rolled back.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//		- version a target version, 0 to revert all migrations
+//			or cpersist.MigrationLatestVersion to apply all of them.
+//	Returns: error or nil no errors occurred.
+func (c *SqlServerPersistence[T]) MigrateTo(ctx context.Context, version int64) error {
+	migrations, err := c.getMigrations()
+	if err != nil || len(migrations) == 0 {
+		return err
+	}
+	if c.Client == nil {
+		return cerr.NewInvalidStateError(cctx.GetTraceId(ctx), "NOT_OPENED", "SQL Server connection is not opened")
+	}
+
+	client, err := c.Client.BeginTx(ctx, nil)
+	if err != nil {
+		return c.migrationError(ctx, err)
+	}
+	completed := false
+	defer func() {
+		if !completed {
+			_ = client.Rollback()
+		}
+	}()
+
+	// The application lock is held until the end of the transaction
+	tableName := c.QuotedMigrationsTableName()
+	var locked int
+	err = client.QueryRowContext(ctx, "DECLARE @result int;"+
+		" EXEC @result = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive',"+
+		" @LockOwner = 'Transaction', @LockTimeout = 60000;"+
+		" SELECT @result", tableName).Scan(&locked)
+	if err != nil {
+		return c.migrationError(ctx, err)
+	}
+	if locked < 0 {
+		return cerr.NewConflictError(cctx.GetTraceId(ctx), "MIGRATION_LOCKED",
+			"Failed to acquire migration lock for "+c.QuotedTableName())
+	}
+
+	_, err = client.ExecContext(ctx, "IF OBJECT_ID(@p1, 'U') IS NULL CREATE TABLE "+tableName+
+		" ([version] BIGINT PRIMARY KEY, [description] NVARCHAR(255), [applied_at] DATETIME2)", tableName)
+	if err != nil {
+		return c.migrationError(ctx, err)
+	}
+
+	applied, err := c.readMigrationVersions(ctx, client)
+	if err != nil {
+		return c.migrationError(ctx, err)
+	}
+	up, down, err := cpersist.PlanMigrations(migrations, applied, version)
+	if err != nil {
+		return err
+	}
+	if len(up) == 0 && len(down) == 0 {
+		return nil
+	}
+
+	for _, migration := range down {
+		err = c.executeMigration(ctx, client, migration, false,
+			"DELETE FROM "+tableName+" WHERE [version]=@p1", migration.Version)
+		if err != nil {
+			return err
+		}
+	}
+	for _, migration := range up {
+		err = c.executeMigration(ctx, client, migration, true,
+			"INSERT INTO "+tableName+" ([version], [description], [applied_at]) VALUES (@p1, @p2, @p3)",
+			migration.Version, migration.Description, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.MigrationsDryRun {
+		return nil
+	}
+	completed = true
+	if err = client.Commit(); err != nil {
+		return c.migrationError(ctx, err)
+	}
+	return nil
+}
+
+func (c *SqlServerPersistence[T]) getMigrations() ([]*cpersist.Migration, error) {
+	if c.MigrationsDir == "" {
+		return c.migrations, nil
+	}
+	loaded, err := cpersist.LoadMigrationsFromDirectory(c.MigrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	return append(loaded, c.migrations...), nil
+}
+
+func (c *SqlServerPersistence[T]) readMigrationVersions(ctx context.Context, client *sql.Tx) ([]int64, error) {
+	rows, err := client.QueryContext(ctx, "SELECT [version] FROM "+c.QuotedMigrationsTableName())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]int64, 0)
+	for rows.Next() {
+		var version int64
+		if err = rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+func (c *SqlServerPersistence[T]) executeMigration(ctx context.Context, client *sql.Tx,
+	migration *cpersist.Migration, up bool, record string, args ...any) error {
+
+	script, direction := migration.Up, "up"
+	if !up {
+		script, direction = migration.Down, "down"
+	}
+
+	if c.MigrationsDryRun {
+		c.Logger.Info(ctx, "Dry run of migration %d %s (%s) for %s:\n%s",
+			migration.Version, direction, migration.Description, c.QuotedTableName(), strings.TrimSpace(script))
+		return nil
+	}
+
+	if _, err := client.ExecContext(ctx, script); err != nil {
+		c.Logger.Error(ctx, err, "Failed to run migration %d %s", migration.Version, direction)
+		return c.migrationError(ctx, err).WithDetails("version", migration.Version)
+	}
+	if _, err := client.ExecContext(ctx, record, args...); err != nil {
+		return c.migrationError(ctx, err).WithDetails("version", migration.Version)
+	}
+	c.Logger.Info(ctx, "Applied migration %d %s (%s) for %s",
+		migration.Version, direction, migration.Description, c.QuotedTableName())
+	return nil
+}
+
+func (c *SqlServerPersistence[T]) migrationError(ctx context.Context, err error) *cerr.ApplicationError {
+	return cerr.NewConnectionError(cctx.GetTraceId(ctx), "MIGRATION_FAILED",
+		"Failed to migrate table").
+		WithDetails("table", c.TableName).WithCause(err)
+}
@@ -0,0 +1,104 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	cdata "github.com/pip-services4/pip-services4-go/pip-services4-commons-go/data"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+)
+
+// PurgeDeleted physically deletes data items marked as deleted in soft-delete mode.
+//
+//	Parameters:
+//		- ctx context.Context transaction id to trace execution through call chain.
+//	Returns: error or nil for success.
+func (c *SqlServerPersistence[T]) PurgeDeleted(ctx context.Context) error {
+	query := "DELETE FROM " + c.QuotedTableName() + " WHERE NOT " + c.composeNotDeletedCondition()
+
+	result, err := c.GetClient(ctx).ExecContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Trace(ctx, "Purged %d deleted items from %s", count, c.TableName)
+	return nil
+}
+
+func (c *SqlServerPersistence[T]) isSoftDelete() bool {
+	return c.SoftDelete && cpersist.IsTrackableType[T]()
+}
+
+// filterDeleted adds exclusion of logically deleted rows to the filter.
+func (c *SqlServerPersistence[T]) filterDeleted(ctx context.Context, filter string) string {
+	if !c.isSoftDelete() || cpersist.IsDeletedIncluded(ctx) {
+		return filter
+	}
+	if len(filter) == 0 {
+		return c.composeNotDeletedCondition()
+	}
+	return "(" + filter + ") AND " + c.composeNotDeletedCondition()
+}
+
+func (c *SqlServerPersistence[T]) composeNotDeletedCondition() string {
+	// Booleans are kept as bits in columns, while JSON_VALUE extracts them as text
+	if c.isJsonDialect() {
+		return "(ISNULL(" + c.FilterCompiler.Dialect.FormatField("deleted") + ", 'false')<>'true')"
+	}
+	return "(ISNULL(" + c.FilterCompiler.Dialect.FormatField("deleted") + ", 0)=0)"
+}
+
+// composeDeletedSet generates SET clause that marks rows as deleted or restores them.
+// The values are generated here, so they are safe to be inlined without parameters.
+func (c *SqlServerPersistence[T]) composeDeletedSet(deleted bool) string {
+	flag := "0"
+	if deleted {
+		flag = "1"
+	}
+
+	if dialect, ok := c.FilterCompiler.Dialect.(*SqlServerFilterDialect); ok && dialect.JsonColumn != "" {
+		changeTime := "'" + time.Now().UTC().Format(time.RFC3339Nano) + "'"
+		column := "[" + dialect.JsonColumn + "]"
+		// Bits are written into JSON as booleans
+		return column + "=JSON_MODIFY(JSON_MODIFY(" + column + ",'$.deleted',CAST(" + flag + " AS BIT))" +
+			",'$.change_time'," + changeTime + ")"
+	}
+
+	return "[deleted]=" + flag + ", [change_time]=SYSUTCDATETIME()"
+}
+
+func (c *SqlServerPersistence[T]) isJsonDialect() bool {
+	dialect, ok := c.FilterCompiler.Dialect.(*SqlServerFilterDialect)
+	return ok && dialect.JsonColumn != ""
+}
+
+// trackItem stamps create and change times in soft-delete mode.
+func (c *SqlServerPersistence[T]) trackItem(item T, created bool) T {
+	if !c.SoftDelete {
+		return item
+	}
+	var obj any = c.cloneItem(item)
+	cpersist.TrackObjectChange(&obj, created, time.Now().UTC())
+	if result, ok := obj.(T); ok {
+		return result
+	}
+	return item
+}
+
+// trackPartialData adds change time to partially updated fields in soft-delete mode.
+// JSON_MODIFY does not accept time values, so JSON tables get the time formatted as in JSON documents.
+func (c *SqlServerPersistence[T]) trackPartialData(data cdata.AnyValueMap) cdata.AnyValueMap {
+	if !c.SoftDelete || !cpersist.IsChangeableType[T]() {
+		return data
+	}
+	var changeTime any = time.Now().UTC()
+	if c.isJsonDialect() {
+		changeTime = changeTime.(time.Time).Format(time.RFC3339Nano)
+	}
+	return *cdata.NewAnyValueMapFromMaps(data.Value(), map[string]any{"change_time": changeTime})
+}
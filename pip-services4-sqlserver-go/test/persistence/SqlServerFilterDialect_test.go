@@ -0,0 +1,38 @@
+package test
+
+import (
+	"testing"
+
+	cquery "github.com/pip-services4/pip-services4-go/pip-services4-data-go/query"
+	cpersist "github.com/pip-services4/pip-services4-go/pip-services4-persistence-go/persistence"
+	persist "github.com/pip-services4/pip-services4-go/pip-services4-sqlserver-go/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonSqlServerFilterNumericRange(t *testing.T) {
+	compiler := persist.NewJsonSqlServerFilterCompiler("data")
+
+	filter, err := cpersist.NewFilterExpressionFromParams(*cquery.NewFilterParamsFromTuples(
+		"count__gt", 9,
+		"price__between", "0.5,10",
+		"key", "10",
+		"code__ge", "A10",
+	))
+	assert.Nil(t, err)
+
+	where, args, err := compiler.Compile(filter, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "(JSON_VALUE([data],'$.code')>=@p1 AND TRY_CAST(JSON_VALUE([data],'$.count') AS FLOAT)>@p2"+
+		" AND JSON_VALUE([data],'$.key')=@p3 AND TRY_CAST(JSON_VALUE([data],'$.price') AS FLOAT) BETWEEN @p4 AND @p5)", where)
+	assert.Equal(t, []any{"A10", "9", "10", "0.5", "10"}, args)
+
+	filter = cpersist.NewFilterCondition("count", cpersist.FilterIn, 1, 2)
+	where, _, err = compiler.Compile(filter, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "TRY_CAST(JSON_VALUE([data],'$.count') AS FLOAT) IN (@p1,@p2)", where)
+
+	where, _, err = persist.NewSqlServerFilterCompiler().Compile(
+		cpersist.NewFilterCondition("count", cpersist.FilterMore, "9"), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "[count]>@p1", where)
+}